@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -77,6 +78,10 @@ func (m *mockPubSub) PublishEvent(string, interface{}, map[string]string) error
 	return nil
 }
 
+func (m *mockPubSub) PublishEvents(string, []interface{}, map[string]string) error {
+	return nil
+}
+
 func (m *mockPubSub) Subscribe() (*pubsub.Message, error) {
 	if m.id == "1" {
 		return nil, errors.EntityNotFound{ID: "1"}
@@ -85,6 +90,10 @@ func (m *mockPubSub) Subscribe() (*pubsub.Message, error) {
 	return &pubsub.Message{}, nil
 }
 
+func (m *mockPubSub) SubscribeWithContext(context.Context) (*pubsub.Message, error) {
+	return m.Subscribe()
+}
+
 func (m *mockPubSub) SubscribeWithCommit(pubsub.CommitFunc) (*pubsub.Message, error) {
 	return nil, nil
 }
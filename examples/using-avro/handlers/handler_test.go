@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -34,10 +35,18 @@ func (m *mockPubSub) PublishEvent(string, interface{}, map[string]string) error
 	return nil
 }
 
+func (m *mockPubSub) PublishEvents(string, []interface{}, map[string]string) error {
+	return nil
+}
+
 func (m *mockPubSub) Subscribe() (*pubsub.Message, error) {
 	return &pubsub.Message{}, nil
 }
 
+func (m *mockPubSub) SubscribeWithContext(context.Context) (*pubsub.Message, error) {
+	return m.Subscribe()
+}
+
 func (m *mockPubSub) SubscribeWithCommit(pubsub.CommitFunc) (*pubsub.Message, error) {
 	return &pubsub.Message{}, nil
 }
@@ -11,8 +11,18 @@ const (
 	Framework                = "gofr-" + log.GofrVersion
 	PathHealthCheck          = "/.well-known/health-check"
 	PathHeartBeat            = "/.well-known/heartbeat"
+	PathReady                = "/.well-known/ready"
+	PathRoutes               = "/.well-known/routes"
+	PathDisabledRoutes       = "/.well-known/routes/disabled"
+	PathConfig               = "/.well-known/config"
+	PathRateLimits           = "/.well-known/rate-limits"
+	PathRateLimit            = "/.well-known/rate-limits/{tenant}"
+	PathNotifications        = "/.well-known/notifications"
+	PathNotificationPreview  = "/.well-known/notifications/{name}"
 	PathOpenAPI              = "/.well-known/openapi.json"
 	PathSwagger              = "/.well-known/swagger"
 	PathSwaggerWithPathParam = "/.well-known/swagger/{name}"
+	PathOperation            = "/operations/{id}"
+	PathPrivacySubject       = "/privacy/subjects/{id}"
 	FrameworkMetricsPrefix   = "zs_"
 )
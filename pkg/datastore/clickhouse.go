@@ -26,6 +26,9 @@ type ClickHouseConfig struct {
 	MaxOpenConn       int
 	MaxIdleConn       int
 	MaxConnLife       int
+	// QueryTags comments the queries with their application, route and traceparent, same as DBConfig.QueryTags
+	QueryTags   bool
+	Application string
 }
 
 type ClickHouseDB struct {
@@ -57,16 +60,20 @@ var (
 	_ = prometheus.Register(clickhouseIdle)
 )
 
+// GetNewClickHouseDB connects to ClickHouse, the connections are pooled by the driver, within MaxOpenConn and MaxIdleConn.
+// The client returned on an error is not connected, but it has the config and the logger, so that it reports its health.
 func GetNewClickHouseDB(logger log.Logger, config *ClickHouseConfig) (ClickHouseDB, error) {
+	db := ClickHouseDB{config: config, logger: logger}
+
 	connect, err := clickhouse.Open(&clickhouse.Options{
 		Addr:            []string{fmt.Sprintf("%s:%s", config.Host, config.Port)},
 		Auth:            clickhouse.Auth{Database: config.Database, Username: config.Username, Password: config.Password},
 		MaxOpenConns:    config.MaxOpenConn,
 		MaxIdleConns:    config.MaxIdleConn,
-		ConnMaxLifetime: time.Duration(config.MaxConnLife),
+		ConnMaxLifetime: time.Duration(config.MaxConnLife) * time.Second,
 	})
 	if err != nil {
-		return ClickHouseDB{}, err
+		return db, err
 	}
 
 	if err := connect.Ping(context.Background()); err != nil {
@@ -75,12 +82,12 @@ func GetNewClickHouseDB(logger log.Logger, config *ClickHouseConfig) (ClickHouse
 			logger.Errorf("[%d] %s \n%s\n", exception.Code, exception.Message, exception.StackTrace)
 		}
 
-		return ClickHouseDB{}, err
+		return db, err
 	}
 
 	go pushClickhouseConnMetrics(config.Database, config.Host, connect)
 
-	db := ClickHouseDB{Conn: connect, config: config}
+	db.Conn = connect
 
 	return db, nil
 }
@@ -128,7 +135,7 @@ func (c *ClickHouseDB) Query(ctx context.Context, query string, args ...interfac
 	}
 
 	begin := time.Now()
-	rows, err := c.Conn.Query(ctx, query, args...)
+	rows, err := c.Conn.Query(ctx, c.tag(ctx, query), args...)
 
 	c.monitorQuery(begin, query)
 
@@ -144,7 +151,7 @@ func (c *ClickHouseDB) Exec(ctx context.Context, query string, args ...interface
 
 	begin := time.Now()
 
-	err := c.Conn.Exec(ctx, query, args...)
+	err := c.Conn.Exec(ctx, c.tag(ctx, query), args...)
 
 	c.monitorQuery(begin, query)
 
@@ -154,15 +161,38 @@ func (c *ClickHouseDB) Exec(ctx context.Context, query string, args ...interface
 // QueryRow executes a query that is expected to return at most one row.
 // QueryRow always returns a non-nil value. Errors are deferred until Row's Scan method is called.
 func (c *ClickHouseDB) QueryRow(ctx context.Context, query string, args ...interface{}) driver.Row {
+	if c == nil || c.Conn == nil {
+		return errRow{err: gofrErr.ClickhouseNotInitialized}
+	}
+
 	begin := time.Now()
 
-	row := c.Conn.QueryRow(ctx, query, args...)
+	row := c.Conn.QueryRow(ctx, c.tag(ctx, query), args...)
 
 	c.monitorQuery(begin, query)
 
 	return row
 }
 
+// errRow is the row of a query which could not be executed, it returns the error when it is scanned.
+type errRow struct {
+	err error
+}
+
+func (r errRow) Err() error { return r.err }
+
+func (r errRow) Scan(...interface{}) error { return r.err }
+
+func (r errRow) ScanStruct(interface{}) error { return r.err }
+
+func (c *ClickHouseDB) tag(ctx context.Context, query string) string {
+	if c.config == nil || !c.config.QueryTags {
+		return query
+	}
+
+	return tagQuery(ctx, c.config.Application, query)
+}
+
 func (c *ClickHouseDB) monitorQuery(begin time.Time, query string) {
 	if c == nil || c.Conn == nil {
 		return
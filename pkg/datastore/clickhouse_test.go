@@ -8,6 +8,7 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/stretchr/testify/assert"
 
+	"gofr.dev/pkg"
 	"gofr.dev/pkg/errors"
 	"gofr.dev/pkg/gofr/config"
 	"gofr.dev/pkg/log"
@@ -136,3 +137,20 @@ func TestClickHouse_QueryRow(t *testing.T) {
 		t.Errorf("QueryRow operation failed.")
 	}
 }
+
+func TestClickHouse_NotConnected(t *testing.T) {
+	dc := ClickHouseConfig{Host: "127.0.0.1", Port: "1", Database: "default"}
+
+	db, err := GetNewClickHouseDB(log.NewMockLogger(io.Discard), &dc)
+	assert.Error(t, err)
+
+	health := db.HealthCheck()
+	assert.Equal(t, pkg.StatusDown, health.Status, "the client must report its health without a connection")
+	assert.Equal(t, "127.0.0.1", health.Host)
+
+	var id int
+
+	row := db.QueryRow(context.Background(), "SELECT 1")
+	assert.Equal(t, errors.ClickhouseNotInitialized, row.Err())
+	assert.Equal(t, errors.ClickhouseNotInitialized, row.Scan(&id))
+}
@@ -0,0 +1,67 @@
+package datastore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Close closes the connections of the datastores, so that the servers of the datastores release the resources of the
+// connections at once, instead of on their timeouts. The datastores can not be used once they are closed.
+func (ds *DataStore) Close() error {
+	var errs []error
+
+	closeWith := func(name string, closer io.Closer) {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", name, err))
+		}
+	}
+
+	if db := ds.sqlDB(); db != nil {
+		closeWith("SQL", db)
+	}
+
+	// the clients which could not connect are returned along with the error, and are not set
+	if c, ok := ds.Redis.(io.Closer); ok && ds.Redis.IsSet() {
+		closeWith("Redis", c)
+	}
+
+	if c, ok := ds.PubSub.(io.Closer); ok && ds.PubSub.IsSet() {
+		closeWith("PubSub", c)
+	}
+
+	if ds.ClickHouse.Conn != nil {
+		closeWith("ClickHouse", ds.ClickHouse.Conn)
+	}
+
+	// the sessions of gocql are closed without an error
+	if ds.Cassandra.Session != nil {
+		ds.Cassandra.Session.Close()
+	}
+
+	if ds.YCQL.Session != nil {
+		ds.YCQL.Session.Close()
+	}
+
+	return errors.Join(errs...)
+}
+
+// sqlDB returns the connection pool of the SQL database, which is shared by GORM and sqlx.
+func (ds *DataStore) sqlDB() *sql.DB {
+	switch {
+	case ds.rdb.DB != nil:
+		return ds.rdb.DB
+	case ds.sqlx.DB != nil:
+		return ds.sqlx.DB.DB
+	case ds.gorm.DB != nil:
+		db, err := ds.gorm.DB.DB()
+		if err != nil {
+			return nil
+		}
+
+		return db
+	}
+
+	return nil
+}
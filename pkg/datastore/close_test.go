@@ -0,0 +1,25 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataStore_Close(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectClose()
+
+	ds := &DataStore{rdb: SQLClient{DB: db}}
+
+	assert.NoError(t, ds.Close())
+	assert.NoError(t, mock.ExpectationsWereMet(), "the SQL connections must be closed")
+
+	assert.NoError(t, (&DataStore{}).Close(), "the datastores which are not connected must be skipped")
+	assert.NoError(t, (&DataStore{Redis: &redisClient{}}).Close(), "the clients which could not connect must be skipped")
+}
@@ -15,4 +15,10 @@ const (
 	Avro           = "avro"
 	GooglePubSub   = "google"
 	ClickHouse     = "clickHouse"
+	LDAPStore      = "ldap"
+	InProcess      = "inprocess"
+	NATS           = "nats"
+	RabbitMQ       = "rabbitmq"
+	AWSSQS         = "aws-sqs"
+	ServiceBus     = "servicebus"
 )
@@ -31,6 +31,7 @@ type DataStore struct {
 	Solr          Client
 	Elasticsearch Elasticsearch
 	DynamoDB      DynamoDB
+	LDAP          LDAP
 }
 
 // QueryLogger represents a structure to log database queries.
@@ -212,3 +213,9 @@ func (ds *DataStore) DynamoDBHealthCheck() types.Health {
 func (ds *DataStore) ClickHouseHealthCheck() types.Health {
 	return ds.ClickHouse.HealthCheck()
 }
+
+// LDAPHealthCheck binds a connection with the service account. If the bind does not return an error,
+// the healthCheck status will be set to UP, else the healthCheck status will be DOWN.
+func (ds *DataStore) LDAPHealthCheck() types.Health {
+	return ds.LDAP.HealthCheck()
+}
@@ -64,6 +64,10 @@ type DBConfig struct {
 	MaxIdleConn       int
 	MaxConnLife       int
 	CACertificateFile string
+	// QueryTags appends the application, the route and the traceparent of the context to the queries as a comment, in
+	// the format of sqlcommenter, so that the queries in pg_stat_statements are attributed to the endpoints
+	QueryTags   bool
+	Application string
 }
 
 // GORMClient stores a GORM database client along with logger and configs to connect to GORM DB.
@@ -0,0 +1,404 @@
+package datastore
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+)
+
+const (
+	defaultLDAPPoolSize       = 5
+	defaultLDAPUserFilter     = "(uid=%s)"
+	defaultLDAPGroupAttribute = "memberOf"
+
+	// ErrLDAPInvalidCredentials is returned when the username or password is empty or rejected by the directory.
+	ErrLDAPInvalidCredentials = errors.Error("invalid LDAP credentials")
+	// ErrLDAPUserNotFound is returned when the user filter does not match exactly one entry.
+	ErrLDAPUserNotFound = errors.Error("LDAP user not found")
+)
+
+//nolint:gochecknoglobals // ldapStats has to be a global variable for prometheus
+var (
+	ldapStats = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "zs_ldap_stats",
+		Help:    "Histogram for LDAP",
+		Buckets: []float64{.001, .003, .005, .01, .025, .05, .1, .2, .3, .4, .5, .75, 1, 2, 3, 5, 10, 30},
+	}, []string{"type", "host"})
+
+	_ = prometheus.Register(ldapStats)
+)
+
+// LDAPConfig stores the config variables required to connect to an LDAP or Active Directory server.
+type LDAPConfig struct {
+	// Addr is the address of the directory server, ex: ldap.example.com:636
+	Addr string
+	// UseTLS dials the server over TLS (ldaps), StartTLS upgrades a plain connection instead.
+	UseTLS   bool
+	StartTLS bool
+	// InsecureSkipVerify disables certificate verification, this should be used only for testing.
+	InsecureSkipVerify bool
+	// BindDN and BindPassword are the credentials of the service account used for searches.
+	BindDN       string
+	BindPassword string
+	// BaseDN is the root of all the searches made by the client.
+	BaseDN string
+	// UserFilter is used to find a user entry, %s is replaced by the escaped username.
+	// Default is (uid=%s), use (sAMAccountName=%s) for Active Directory.
+	UserFilter string
+	// GroupAttribute is the user attribute that lists the groups of the user, default is memberOf.
+	GroupAttribute string
+	// PoolSize is the maximum number of idle connections kept open, default is 5.
+	PoolSize int
+	// Timeout is the timeout in seconds for every request made to the server.
+	Timeout int
+	// ConnRetryDuration is the duration in seconds after which the connection is retried.
+	ConnRetryDuration int
+}
+
+// LDAP is an abstraction over an LDAP directory used for authentication and group lookups.
+type LDAP interface {
+	// Authenticate binds with the given credentials and returns the groups the user is a member of.
+	Authenticate(username, password string) ([]string, error)
+	// Groups returns the groups the user is a member of, without validating the credentials.
+	Groups(username string) ([]string, error)
+	// IsMember checks if the user is a member of the given group.
+	IsMember(username, group string) (bool, error)
+	// Search runs the filter under the configured BaseDN and returns the matching entries.
+	Search(filter string, attributes ...string) ([]*ldap.Entry, error)
+
+	HealthCheck() types.Health
+	IsSet() bool
+}
+
+type ldapClient struct {
+	config *LDAPConfig
+	logger log.Logger
+	pool   chan ldap.Client
+	dial   func() (ldap.Client, error)
+}
+
+// NewLDAP creates a pooled LDAP client. A connection is opened and bound with the service account to verify
+// the config, this connection is returned to the pool.
+func NewLDAP(logger log.Logger, config *LDAPConfig) (LDAP, error) {
+	setLDAPDefaults(config)
+
+	l := &ldapClient{
+		config: config,
+		logger: logger,
+		pool:   make(chan ldap.Client, config.PoolSize),
+	}
+
+	l.dial = l.dialServer
+
+	conn, err := l.getConn()
+	if err != nil {
+		return &ldapClient{config: config, logger: logger}, err
+	}
+
+	l.putConn(conn)
+
+	return l, nil
+}
+
+func setLDAPDefaults(config *LDAPConfig) {
+	if config.PoolSize <= 0 {
+		config.PoolSize = defaultLDAPPoolSize
+	}
+
+	if config.UserFilter == "" {
+		config.UserFilter = defaultLDAPUserFilter
+	}
+
+	if config.GroupAttribute == "" {
+		config.GroupAttribute = defaultLDAPGroupAttribute
+	}
+}
+
+func (l *ldapClient) dialServer() (ldap.Client, error) {
+	host, _, _ := net.SplitHostPort(l.config.Addr)
+
+	//nolint:gosec // TLS InsecureSkipVerify value will be provided by the user
+	tlsConfig := &tls.Config{InsecureSkipVerify: l.config.InsecureSkipVerify, ServerName: host}
+
+	scheme := "ldap://"
+	if l.config.UseTLS {
+		scheme = "ldaps://"
+	}
+
+	conn, err := ldap.DialURL(scheme+l.config.Addr, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	if l.config.Timeout > 0 {
+		conn.SetTimeout(time.Duration(l.config.Timeout) * time.Second)
+	}
+
+	if l.config.StartTLS && !l.config.UseTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// getConn returns an idle connection from the pool, or dials a new one bound with the service account.
+func (l *ldapClient) getConn() (ldap.Client, error) {
+	select {
+	case conn := <-l.pool:
+		if !conn.IsClosing() {
+			return conn, nil
+		}
+	default:
+	}
+
+	conn, err := l.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.bindServiceAccount(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// putConn returns the connection to the pool, the connection is closed when the pool is full.
+func (l *ldapClient) putConn(conn ldap.Client) {
+	select {
+	case l.pool <- conn:
+	default:
+		_ = conn.Close()
+	}
+}
+
+func (l *ldapClient) bindServiceAccount(conn ldap.Client) error {
+	if l.config.BindDN == "" {
+		return conn.UnauthenticatedBind("")
+	}
+
+	return conn.Bind(l.config.BindDN, l.config.BindPassword)
+}
+
+// Authenticate finds the user entry with the service account and binds with the user's credentials.
+// The connection is bound back to the service account before it is returned to the pool.
+func (l *ldapClient) Authenticate(username, password string) ([]string, error) {
+	// an empty password results in an unauthenticated bind which most servers accept
+	if username == "" || password == "" {
+		return nil, ErrLDAPInvalidCredentials
+	}
+
+	begin := time.Now()
+	defer l.monitor("authenticate", username, begin)
+
+	conn, err := l.getConn()
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := l.findUser(conn, username)
+	if err != nil {
+		l.release(conn, err)
+		return nil, err
+	}
+
+	if err = conn.Bind(entry.DN, password); err != nil {
+		if !ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			_ = conn.Close()
+			return nil, err
+		}
+
+		l.restore(conn)
+
+		return nil, ErrLDAPInvalidCredentials
+	}
+
+	l.restore(conn)
+
+	return groupNames(entry.GetAttributeValues(l.config.GroupAttribute)), nil
+}
+
+// Groups returns the common names of the groups the user is a member of.
+func (l *ldapClient) Groups(username string) ([]string, error) {
+	begin := time.Now()
+	defer l.monitor("groups", username, begin)
+
+	conn, err := l.getConn()
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := l.findUser(conn, username)
+	l.release(conn, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return groupNames(entry.GetAttributeValues(l.config.GroupAttribute)), nil
+}
+
+// IsMember checks, case-insensitively, if the user is a member of the given group.
+func (l *ldapClient) IsMember(username, group string) (bool, error) {
+	groups, err := l.Groups(username)
+	if err != nil {
+		return false, err
+	}
+
+	for _, g := range groups {
+		if strings.EqualFold(g, group) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Search runs the filter in the subtree of BaseDN and returns the entries found.
+func (l *ldapClient) Search(filter string, attributes ...string) ([]*ldap.Entry, error) {
+	begin := time.Now()
+	defer l.monitor("search", filter, begin)
+
+	conn, err := l.getConn()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := conn.Search(l.searchRequest(filter, attributes))
+	l.release(conn, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Entries, nil
+}
+
+func (l *ldapClient) findUser(conn ldap.Client, username string) (*ldap.Entry, error) {
+	filter := fmt.Sprintf(l.config.UserFilter, ldap.EscapeFilter(username))
+
+	res, err := conn.Search(l.searchRequest(filter, []string{"dn", l.config.GroupAttribute}))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Entries) != 1 {
+		return nil, ErrLDAPUserNotFound
+	}
+
+	return res.Entries[0], nil
+}
+
+func (l *ldapClient) searchRequest(filter string, attributes []string) *ldap.SearchRequest {
+	return ldap.NewSearchRequest(l.config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, l.config.Timeout,
+		false, filter, attributes, nil)
+}
+
+// release returns the connection to the pool, unless a network error occurred on it.
+func (l *ldapClient) release(conn ldap.Client, err error) {
+	if err != nil && ldap.IsErrorWithCode(err, ldap.ErrorNetwork) {
+		_ = conn.Close()
+		return
+	}
+
+	l.putConn(conn)
+}
+
+// restore binds the connection back to the service account before returning it to the pool. The connection is
+// closed if the bind fails, so that a connection bound with a user's identity is never reused.
+func (l *ldapClient) restore(conn ldap.Client) {
+	if err := l.bindServiceAccount(conn); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	l.putConn(conn)
+}
+
+func (l *ldapClient) monitor(operation, query string, begin time.Time) {
+	ldapStats.WithLabelValues(operation, l.config.Addr).Observe(time.Since(begin).Seconds())
+
+	if l.logger != nil {
+		l.logger.Debug(QueryLogger{
+			Hosts:     l.config.Addr,
+			Query:     []string{operation, query},
+			Duration:  time.Since(begin).Microseconds(),
+			DataStore: LDAPStore,
+		})
+	}
+}
+
+// groupNames extracts the common name from group DNs like "CN=admins,OU=Groups,DC=example,DC=com".
+// Values which are not DNs are returned as they are.
+func groupNames(values []string) []string {
+	groups := make([]string, 0, len(values))
+
+	for _, v := range values {
+		dn, err := ldap.ParseDN(v)
+		if err != nil || len(dn.RDNs) == 0 {
+			groups = append(groups, v)
+			continue
+		}
+
+		name := v
+
+		for _, attr := range dn.RDNs[0].Attributes {
+			if strings.EqualFold(attr.Type, "cn") {
+				name = attr.Value
+				break
+			}
+		}
+
+		groups = append(groups, name)
+	}
+
+	return groups
+}
+
+// HealthCheck binds a connection with the service account. If it does not return an error,
+// the healthCheck status will be set to UP, else the healthCheck status will be DOWN.
+func (l *ldapClient) HealthCheck() types.Health {
+	resp := types.Health{
+		Name:     LDAPStore,
+		Status:   pkg.StatusDown,
+		Host:     l.config.Addr,
+		Database: l.config.BaseDN,
+	}
+
+	// The following check is for the condition when the connection to LDAP has not been made during initialization
+	if l.pool == nil {
+		l.logger.Errorf("%v", errors.HealthCheckFailed{Dependency: LDAPStore, Reason: "LDAP not initialized"})
+		return resp
+	}
+
+	conn, err := l.getConn()
+	if err != nil {
+		l.logger.Errorf("%v", errors.HealthCheckFailed{Dependency: LDAPStore, Err: err})
+		return resp
+	}
+
+	l.putConn(conn)
+
+	resp.Status = pkg.StatusUp
+
+	return resp
+}
+
+// IsSet checks whether LDAP is initialized or not
+func (l *ldapClient) IsSet() bool {
+	return l != nil && l.pool != nil
+}
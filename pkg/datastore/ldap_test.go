@@ -0,0 +1,187 @@
+package datastore
+
+import (
+	"io"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/log"
+)
+
+const (
+	testBindDN = "cn=svc,dc=example,dc=com"
+	testUserDN = "uid=john,ou=people,dc=example,dc=com"
+)
+
+// mockLDAPConn implements the methods of ldap.Client used by the client, any other call panics.
+type mockLDAPConn struct {
+	ldap.Client
+
+	boundAs string
+	closed  bool
+	entries []*ldap.Entry
+}
+
+func (m *mockLDAPConn) Bind(username, password string) error {
+	if (username == testBindDN && password == "svc-pass") || (username == testUserDN && password == "john-pass") {
+		m.boundAs = username
+		return nil
+	}
+
+	return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.Error("invalid credentials"))
+}
+
+func (m *mockLDAPConn) UnauthenticatedBind(string) error {
+	m.boundAs = ""
+	return nil
+}
+
+func (m *mockLDAPConn) Search(*ldap.SearchRequest) (*ldap.SearchResult, error) {
+	return &ldap.SearchResult{Entries: m.entries}, nil
+}
+
+func (m *mockLDAPConn) IsClosing() bool {
+	return m.closed
+}
+
+func (m *mockLDAPConn) Close() error {
+	m.closed = true
+	return nil
+}
+
+func newMockLDAPClient(conn *mockLDAPConn, bindPassword string) *ldapClient {
+	cfg := &LDAPConfig{Addr: "localhost:389", BaseDN: "dc=example,dc=com", BindDN: testBindDN, BindPassword: bindPassword}
+	setLDAPDefaults(cfg)
+
+	return &ldapClient{
+		config: cfg,
+		logger: log.NewMockLogger(io.Discard),
+		pool:   make(chan ldap.Client, cfg.PoolSize),
+		dial:   func() (ldap.Client, error) { return conn, nil },
+	}
+}
+
+func TestLDAP_Authenticate(t *testing.T) {
+	user := ldap.NewEntry(testUserDN, map[string][]string{
+		"memberOf": {"CN=admins,OU=Groups,DC=example,DC=com", "developers"},
+	})
+
+	tests := []struct {
+		desc     string
+		username string
+		password string
+		entries  []*ldap.Entry
+		groups   []string
+		err      error
+	}{
+		{"valid credentials", "john", "john-pass", []*ldap.Entry{user}, []string{"admins", "developers"}, nil},
+		{"invalid password", "john", "wrong", []*ldap.Entry{user}, nil, ErrLDAPInvalidCredentials},
+		{"empty password", "john", "", []*ldap.Entry{user}, nil, ErrLDAPInvalidCredentials},
+		{"user not found", "jane", "jane-pass", nil, nil, ErrLDAPUserNotFound},
+	}
+
+	for i, tc := range tests {
+		conn := &mockLDAPConn{entries: tc.entries}
+		l := newMockLDAPClient(conn, "svc-pass")
+
+		groups, err := l.Authenticate(tc.username, tc.password)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.groups, groups, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		// connection must never be left bound as the user
+		assert.NotEqual(t, testUserDN, conn.boundAs, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestLDAP_GroupsAndIsMember(t *testing.T) {
+	user := ldap.NewEntry(testUserDN, map[string][]string{"memberOf": {"cn=Admins,dc=example,dc=com"}})
+	l := newMockLDAPClient(&mockLDAPConn{entries: []*ldap.Entry{user}}, "svc-pass")
+
+	groups, err := l.Groups("john")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Admins"}, groups)
+
+	ok, err := l.IsMember("john", "admins")
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = l.IsMember("john", "developers")
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestLDAP_Search(t *testing.T) {
+	entries := []*ldap.Entry{ldap.NewEntry(testUserDN, nil)}
+	l := newMockLDAPClient(&mockLDAPConn{entries: entries}, "svc-pass")
+
+	got, err := l.Search("(objectClass=person)", "cn")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entries, got)
+}
+
+func TestLDAP_HealthCheck(t *testing.T) {
+	up := newMockLDAPClient(&mockLDAPConn{}, "svc-pass")
+	down := newMockLDAPClient(&mockLDAPConn{}, "wrong-pass")
+	uninitialized := &ldapClient{config: &LDAPConfig{Addr: "localhost:389"}, logger: log.NewMockLogger(io.Discard)}
+
+	tests := []struct {
+		desc   string
+		client *ldapClient
+		status string
+	}{
+		{"service account bind succeeds", up, pkg.StatusUp},
+		{"service account bind fails", down, pkg.StatusDown},
+		{"client not initialized", uninitialized, pkg.StatusDown},
+	}
+
+	for i, tc := range tests {
+		health := tc.client.HealthCheck()
+
+		assert.Equal(t, LDAPStore, health.Name, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.status, health.Status, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.True(t, up.IsSet())
+	assert.False(t, uninitialized.IsSet())
+}
+
+func TestLDAP_PoolReusesConnections(t *testing.T) {
+	dials := 0
+	l := newMockLDAPClient(nil, "svc-pass")
+	l.dial = func() (ldap.Client, error) {
+		dials++
+		return &mockLDAPConn{}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		conn, err := l.getConn()
+
+		assert.NoError(t, err)
+
+		l.putConn(conn)
+	}
+
+	assert.Equal(t, 1, dials)
+}
+
+func TestNewLDAP_Error(t *testing.T) {
+	l, err := NewLDAP(log.NewMockLogger(io.Discard), &LDAPConfig{Addr: "localhost:1", BaseDN: "dc=example,dc=com"})
+
+	assert.Error(t, err)
+	assert.False(t, l.IsSet())
+}
+
+func Test_groupNames(t *testing.T) {
+	got := groupNames([]string{"CN=admins,OU=Groups,DC=example,DC=com", "ou=ops,dc=example,dc=com", "plain"})
+
+	assert.Equal(t, []string{"admins", "ou=ops,dc=example,dc=com", "plain"}, got)
+}
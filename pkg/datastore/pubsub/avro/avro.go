@@ -4,6 +4,7 @@
 package avro
 
 import (
+	"context"
 	"encoding/binary"
 	"strings"
 
@@ -32,8 +33,16 @@ type Config struct {
 	Subject        string
 	SchemaUser     string
 	SchemaPassword string
+	// Format is the serialization of the messages, FormatAvro or FormatProtobuf, default is FormatAvro
+	Format string
 }
 
+// the serializations of the messages, of which the schemas are registered in the schema registry
+const (
+	FormatAvro     = "avro"
+	FormatProtobuf = "protobuf"
+)
+
 // NewWithConfig initializes new avro pubsub along with the configs
 func NewWithConfig(c *Config, ps pubsub.PublisherSubscriber) (pubsub.PublisherSubscriber, error) {
 	if c == nil || c.URL == "" {
@@ -109,6 +118,33 @@ func (a *Avro) PublishEvent(key string, value interface{}, headers map[string]st
 	return a.PublishEventWithOptions(key, value, headers, nil)
 }
 
+// PublishEvents encodes the events using the schema, and publishes them in a batch onto the pubsub configured
+func (a *Avro) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	if a.schema == nil {
+		return &errors.Response{Code: "Missing schema", Reason: "Avro is initialized without schema"}
+	}
+
+	var errs pubsub.PublishErrors
+
+	encoded := make([]interface{}, 0, len(values))
+	indexes := make([]int, 0, len(values))
+
+	for i, value := range values {
+		binaryValue, err := avro.Marshal(a.schema, value)
+		if err != nil {
+			errs = append(errs, pubsub.PublishError{Index: i, Err: err})
+			continue
+		}
+
+		encodedMsg := Encoder{SchemaID: a.schemaID, Content: binaryValue}
+
+		encoded = append(encoded, encodedMsg.Encode())
+		indexes = append(indexes, i)
+	}
+
+	return pubsub.PublishEncoded(a.pubSub, topic, encoded, indexes, headers, errs)
+}
+
 // Subscribe read messages from avro
 func (a *Avro) Subscribe() (*pubsub.Message, error) {
 	msg, err := a.pubSub.Subscribe()
@@ -119,6 +155,16 @@ func (a *Avro) Subscribe() (*pubsub.Message, error) {
 	return a.processMessage(msg)
 }
 
+// SubscribeWithContext reads a message like Subscribe, till the context is done
+func (a *Avro) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
+	msg, err := a.pubSub.SubscribeWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.processMessage(msg)
+}
+
 /*
 SubscribeWithCommit calls the CommitFunc after subscribing message from avro and based on the return values decides
 whether to commit message and consume another message
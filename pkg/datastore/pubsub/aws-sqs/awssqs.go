@@ -0,0 +1,520 @@
+// Package awssqs provides methods to consume the messages of an AWS SQS queue by long polling, and to publish the messages
+// to the queue, or to an AWS SNS topic to which the queue is subscribed. The FIFO queues and topics are grouped by the
+// keys of the messages.
+package awssqs
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+)
+
+const (
+	// maxWaitTime and maxMessages are the limits of SQS for a receive
+	maxWaitTime    = 20
+	maxMessages    = 10
+	requestTimeout = 10 * time.Second
+	fifoSuffix     = ".fifo"
+
+	errNoRegion     = errors.Error("aws region is not provided")
+	errNoTarget     = errors.Error("sqs queue url or sns topic arn is not provided")
+	errNoQueue      = errors.Error("sqs queue url is not provided")
+	errSQSNotSet    = errors.Error("sqs is not initialized")
+	errSQSIsClosing = errors.Error("sqs is closed")
+)
+
+// Config stores the configuration parameters required to connect to AWS SQS and SNS.
+type Config struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Endpoint overrides the endpoint of AWS, ie: the endpoint of localstack
+	Endpoint string
+	// QueueURL is the queue of which the messages are consumed, the messages are published to it when TopicArn is not set
+	QueueURL string
+	// TopicArn is the SNS topic to which the messages are published
+	TopicArn string
+	// WaitTime is the seconds for which a receive waits for the messages, up to 20, default is 20
+	WaitTime int64
+	// VisibilityTimeout is the seconds for which a received message is hidden from the other consumers, until it is
+	// deleted, the visibility timeout of the queue is used when it is 0
+	VisibilityTimeout int64
+	// MaxMessages is the number of messages which are received at once, up to 10, default is 10
+	MaxMessages int64
+}
+
+// Client is a client of an SQS queue, and of the SNS topic to which the messages are published.
+type Client struct {
+	config *Config
+	sqs    sqsiface.SQSAPI
+	sns    snsiface.SNSAPI
+	logger log.Logger
+
+	// mu guards the received messages which are not consumed yet, the consumed messages which are not deleted yet, and
+	// the messages returned by SubscribeWithCommit without being deleted, by the offsets they are returned with
+	mu       sync.Mutex
+	received []*sqs.Message
+	deletes  []*sqs.DeleteMessageBatchRequestEntry
+	pending  map[int64]*sqs.Message
+	offset   int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New returns the client of the queue and the topic, the session is not connected until the first request.
+func New(config *Config, logger log.Logger) (*Client, error) {
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+
+	pubsub.RegisterMetrics()
+
+	awsCfg := aws.NewConfig().WithRegion(config.Region)
+
+	// the default credentials of the environment, ie: the role of the instance, are used when the keys are not set
+	if config.AccessKeyID != "" {
+		awsCfg.Credentials = credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, "")
+	}
+
+	if config.Endpoint != "" {
+		awsCfg.Endpoint = aws.String(config.Endpoint)
+	}
+
+	sess, err := session.NewSession(awsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Client{config: config, sqs: sqs.New(sess), sns: sns.New(sess), logger: logger, ctx: ctx, cancel: cancel}, nil
+}
+
+func validate(config *Config) error {
+	switch {
+	case config.Region == "":
+		return errNoRegion
+	case config.QueueURL == "" && config.TopicArn == "":
+		return errNoTarget
+	}
+
+	if config.WaitTime <= 0 || config.WaitTime > maxWaitTime {
+		config.WaitTime = maxWaitTime
+	}
+
+	if config.MaxMessages <= 0 || config.MaxMessages > maxMessages {
+		config.MaxMessages = maxMessages
+	}
+
+	return nil
+}
+
+/*
+PublishEventWithOptions publishes the message to the topic of the options, which is the SNS topic, or the queue when the
+topic is not configured. The topic of the options is either the ARN of an SNS topic, or the URL of an SQS queue. The key
+is the message group of the messages of the FIFO topics and queues, the FIFO topics and queues have to enable the
+content based deduplication.
+*/
+func (c *Client) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) (err error) {
+	if c == nil {
+		return errSQSNotSet
+	}
+
+	target := c.config.TopicArn
+	if target == "" {
+		target = c.config.QueueURL
+	}
+
+	if options != nil && options.Topic != "" {
+		target = options.Topic
+	}
+
+	pubsub.PublishTotalCount(target, "")
+
+	defer func() {
+		if err != nil {
+			pubsub.PublishFailureCount(target, "")
+			return
+		}
+
+		pubsub.PublishSuccessCount(target, "")
+	}()
+
+	body, ok := value.([]byte)
+	if !ok {
+		if body, err = json.Marshal(value); err != nil {
+			return err
+		}
+	}
+
+	var groupID *string
+
+	if strings.HasSuffix(target, fifoSuffix) && key != "" {
+		groupID = aws.String(key)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	if isTopic(target) {
+		_, err = c.sns.PublishWithContext(ctx, &sns.PublishInput{TopicArn: aws.String(target), Message: aws.String(string(body)),
+			MessageAttributes: snsAttributes(headers), MessageGroupId: groupID})
+
+		return err
+	}
+
+	_, err = c.sqs.SendMessageWithContext(ctx, &sqs.SendMessageInput{QueueUrl: aws.String(target),
+		MessageBody: aws.String(string(body)), MessageAttributes: sqsAttributes(headers), MessageGroupId: groupID})
+
+	return err
+}
+
+// PublishEvent publishes the message to the SNS topic, or to the queue when the topic is not configured
+func (c *Client) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return c.PublishEventWithOptions(key, value, headers, nil)
+}
+
+// PublishEvents publishes the messages one by one
+func (c *Client) PublishEvents(target string, values []interface{}, headers map[string]string) error {
+	return pubsub.PublishEach(c, target, values, headers)
+}
+
+// isTopic checks whether the target is the ARN of an SNS topic, instead of the URL of an SQS queue
+func isTopic(target string) bool {
+	return strings.HasPrefix(target, "arn:") && strings.Contains(target, ":sns:")
+}
+
+func snsAttributes(headers map[string]string) map[string]*sns.MessageAttributeValue {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	attributes := make(map[string]*sns.MessageAttributeValue, len(headers))
+
+	for k, v := range headers {
+		attributes[k] = &sns.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(v)}
+	}
+
+	return attributes
+}
+
+func sqsAttributes(headers map[string]string) map[string]*sqs.MessageAttributeValue {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	attributes := make(map[string]*sqs.MessageAttributeValue, len(headers))
+
+	for k, v := range headers {
+		attributes[k] = &sqs.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(v)}
+	}
+
+	return attributes
+}
+
+// Subscribe returns the next message of the queue, the message is deleted from the queue along with the other consumed
+// messages, before the next messages are received.
+func (c *Client) Subscribe() (*pubsub.Message, error) {
+	return c.SubscribeWithContext(context.Background())
+}
+
+// SubscribeWithContext reads a message like Subscribe, till the context is done, the receive which is in progress is
+// cancelled along with the context, the messages which are received are not deleted till they are returned.
+func (c *Client) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
+	msg, err := c.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.delete(msg)
+
+	return c.toMessage(msg), nil
+}
+
+/*
+SubscribeWithCommit calls the CommitFunc for the messages of the queue, the message is deleted when it is to be
+committed, else it is made visible again, so that it is received again. The messages are consumed until the CommitFunc
+returns false to continue. The message which is returned without being committed is deleted by CommitOffset, with the
+offset it is returned with, and is received again when its visibility timeout is passed before.
+*/
+func (c *Client) SubscribeWithCommit(commitFunc pubsub.CommitFunc) (*pubsub.Message, error) {
+	for {
+		msg, err := c.next(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		message := c.toMessage(msg)
+
+		isCommit, isContinue := commitFunc(message)
+
+		switch {
+		case isCommit:
+			c.delete(msg)
+		case isContinue:
+			c.release(msg)
+		default:
+			message.Offset = c.keep(msg)
+		}
+
+		if !isContinue {
+			return message, nil
+		}
+	}
+}
+
+// next returns the next received message, the messages are received in batches by long polling, which is repeated
+// until a message is received, the context is done, or the client is closed.
+func (c *Client) next(ctx context.Context) (*sqs.Message, error) {
+	if c == nil {
+		return nil, errSQSNotSet
+	}
+
+	if c.config.QueueURL == "" {
+		return nil, errNoQueue
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the receive is cancelled when either the context is done or the client is closed
+	receiveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(c.ctx, cancel)
+	defer stop()
+
+	for len(c.received) == 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// the consumed messages are deleted before the next messages are received, so that they are not received again
+		c.flushDeletes()
+
+		out, err := c.sqs.ReceiveMessageWithContext(receiveCtx, &sqs.ReceiveMessageInput{
+			QueueUrl:              aws.String(c.config.QueueURL),
+			MaxNumberOfMessages:   aws.Int64(c.config.MaxMessages),
+			WaitTimeSeconds:       aws.Int64(c.config.WaitTime),
+			VisibilityTimeout:     visibilityTimeout(c.config.VisibilityTimeout),
+			MessageAttributeNames: []*string{aws.String("All")},
+			AttributeNames:        []*string{aws.String(sqs.MessageSystemAttributeNameMessageGroupId)},
+		})
+
+		if err != nil {
+			if c.ctx.Err() != nil {
+				return nil, errSQSIsClosing
+			}
+
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			pubsub.SubscribeFailureCount(c.config.QueueURL, "")
+
+			return nil, err
+		}
+
+		c.received = out.Messages
+	}
+
+	msg := c.received[0]
+	c.received = c.received[1:]
+
+	pubsub.SubscribeReceiveCount(c.config.QueueURL, "")
+	pubsub.SubscribeSuccessCount(c.config.QueueURL, "")
+
+	return msg, nil
+}
+
+func visibilityTimeout(seconds int64) *int64 {
+	if seconds <= 0 {
+		return nil
+	}
+
+	return aws.Int64(seconds)
+}
+
+// delete adds the message to the batch of the messages to be deleted, the batch is deleted once it is full
+func (c *Client) delete(msg *sqs.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.deletes = append(c.deletes, &sqs.DeleteMessageBatchRequestEntry{Id: msg.MessageId, ReceiptHandle: msg.ReceiptHandle})
+
+	if len(c.deletes) == maxMessages {
+		c.flushDeletes()
+	}
+}
+
+// flushDeletes deletes the consumed messages in a batch, it is called with the lock held
+func (c *Client) flushDeletes() {
+	if len(c.deletes) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	out, err := c.sqs.DeleteMessageBatchWithContext(ctx, &sqs.DeleteMessageBatchInput{QueueUrl: aws.String(c.config.QueueURL),
+		Entries: c.deletes})
+
+	switch {
+	case err != nil:
+		c.logger.Errorf("%v messages of queue %v could not be deleted: %v", len(c.deletes), c.config.QueueURL, err)
+	case len(out.Failed) > 0:
+		c.logger.Errorf("%v messages of queue %v could not be deleted: %v", len(out.Failed), c.config.QueueURL, out.Failed)
+	}
+
+	c.deletes = nil
+}
+
+// release makes the message visible again, so that it is received again instead of after its visibility timeout
+func (c *Client) release(msg *sqs.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	_, err := c.sqs.ChangeMessageVisibilityWithContext(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl: aws.String(c.config.QueueURL), ReceiptHandle: msg.ReceiptHandle, VisibilityTimeout: aws.Int64(0)})
+	if err != nil {
+		c.logger.Errorf("message %v of queue %v could not be released: %v", aws.StringValue(msg.MessageId),
+			c.config.QueueURL, err)
+	}
+}
+
+// toMessage converts the message of SQS, the key of the message is its message group. The messages published to an
+// SNS topic are received as they are published only when the raw message delivery of the subscription is enabled.
+func (c *Client) toMessage(msg *sqs.Message) *pubsub.Message {
+	message := &pubsub.Message{Topic: c.config.QueueURL, Value: aws.StringValue(msg.Body),
+		Key:     aws.StringValue(msg.Attributes[sqs.MessageSystemAttributeNameMessageGroupId]),
+		Headers: make(map[string]string, len(msg.MessageAttributes))}
+
+	for k, v := range msg.MessageAttributes {
+		message.Headers[k] = aws.StringValue(v.StringValue)
+	}
+
+	return message
+}
+
+// Bind parses the JSON message into the target
+func (c *Client) Bind(message []byte, target interface{}) error {
+	return json.Unmarshal(message, target)
+}
+
+// keep keeps the message returned without being deleted, until it is deleted by CommitOffset, it returns the offset
+// the message is kept by, as the messages of SQS do not have offsets.
+func (c *Client) keep(msg *sqs.Message) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pending == nil {
+		c.pending = make(map[int64]*sqs.Message)
+	}
+
+	c.offset++
+	c.pending[c.offset] = msg
+
+	return c.offset
+}
+
+// CommitOffset deletes the message returned by SubscribeWithCommit without being committed, by the offset it is
+// returned with. The messages of SQS are deleted one by one, instead of by their offsets.
+func (c *Client) CommitOffset(offsets pubsub.TopicPartition) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	msg, ok := c.pending[offsets.Offset]
+	delete(c.pending, offsets.Offset)
+	c.mu.Unlock()
+
+	if ok {
+		c.delete(msg)
+	}
+}
+
+// Ping checks that the queue and the topic are accessible
+func (c *Client) Ping() error {
+	if !c.IsSet() {
+		return errSQSNotSet
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	if c.config.QueueURL != "" {
+		_, err := c.sqs.GetQueueAttributesWithContext(ctx, &sqs.GetQueueAttributesInput{QueueUrl: aws.String(c.config.QueueURL),
+			AttributeNames: []*string{aws.String(sqs.QueueAttributeNameQueueArn)}})
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.config.TopicArn != "" {
+		_, err := c.sns.GetTopicAttributesWithContext(ctx, &sns.GetTopicAttributesInput{TopicArn: aws.String(c.config.TopicArn)})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HealthCheck returns the health of the queue and the topic
+func (c *Client) HealthCheck() types.Health {
+	if !c.IsSet() {
+		return types.Health{Name: datastore.AWSSQS, Status: pkg.StatusDown}
+	}
+
+	resp := types.Health{Name: datastore.AWSSQS, Status: pkg.StatusDown, Host: c.config.QueueURL, Database: c.config.TopicArn}
+
+	if err := c.Ping(); err != nil {
+		c.logger.Errorf("%v", errors.HealthCheckFailed{Dependency: datastore.AWSSQS, Err: err})
+
+		return resp
+	}
+
+	resp.Status = pkg.StatusUp
+
+	return resp
+}
+
+// IsSet checks whether the client is initialized or not
+func (c *Client) IsSet() bool {
+	return c != nil && c.sqs != nil && c.sns != nil
+}
+
+// Close stops the receiving of the messages, and deletes the messages which are consumed
+func (c *Client) Close() error {
+	if c == nil {
+		return nil
+	}
+
+	c.cancel()
+
+	c.mu.Lock()
+	c.flushDeletes()
+	c.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,290 @@
+package awssqs
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/log"
+)
+
+const (
+	queueURL = "https://sqs.us-east-1.amazonaws.com/123456789012/orders"
+	topicArn = "arn:aws:sns:us-east-1:123456789012:orders"
+)
+
+// mockSQS returns the batches one by one, and an empty batch when there are no batches
+type mockSQS struct {
+	sqsiface.SQSAPI
+	batches  [][]*sqs.Message
+	received []*sqs.ReceiveMessageInput
+	sent     []*sqs.SendMessageInput
+	deleted  [][]*sqs.DeleteMessageBatchRequestEntry
+	released []string
+	err      error
+}
+
+func (m *mockSQS) ReceiveMessageWithContext(_ aws.Context, in *sqs.ReceiveMessageInput,
+	_ ...request.Option) (*sqs.ReceiveMessageOutput, error) {
+	m.received = append(m.received, in)
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if len(m.batches) == 0 {
+		return &sqs.ReceiveMessageOutput{}, nil
+	}
+
+	batch := m.batches[0]
+	m.batches = m.batches[1:]
+
+	return &sqs.ReceiveMessageOutput{Messages: batch}, nil
+}
+
+func (m *mockSQS) SendMessageWithContext(_ aws.Context, in *sqs.SendMessageInput,
+	_ ...request.Option) (*sqs.SendMessageOutput, error) {
+	m.sent = append(m.sent, in)
+	return &sqs.SendMessageOutput{}, m.err
+}
+
+func (m *mockSQS) DeleteMessageBatchWithContext(_ aws.Context, in *sqs.DeleteMessageBatchInput,
+	_ ...request.Option) (*sqs.DeleteMessageBatchOutput, error) {
+	m.deleted = append(m.deleted, in.Entries)
+	return &sqs.DeleteMessageBatchOutput{}, nil
+}
+
+func (m *mockSQS) ChangeMessageVisibilityWithContext(_ aws.Context, in *sqs.ChangeMessageVisibilityInput,
+	_ ...request.Option) (*sqs.ChangeMessageVisibilityOutput, error) {
+	m.released = append(m.released, aws.StringValue(in.ReceiptHandle))
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (m *mockSQS) GetQueueAttributesWithContext(aws.Context, *sqs.GetQueueAttributesInput,
+	...request.Option) (*sqs.GetQueueAttributesOutput, error) {
+	return &sqs.GetQueueAttributesOutput{}, m.err
+}
+
+type mockSNS struct {
+	snsiface.SNSAPI
+	published []*sns.PublishInput
+}
+
+func (m *mockSNS) PublishWithContext(_ aws.Context, in *sns.PublishInput, _ ...request.Option) (*sns.PublishOutput, error) {
+	m.published = append(m.published, in)
+	return &sns.PublishOutput{}, nil
+}
+
+func (m *mockSNS) GetTopicAttributesWithContext(aws.Context, *sns.GetTopicAttributesInput,
+	...request.Option) (*sns.GetTopicAttributesOutput, error) {
+	return &sns.GetTopicAttributesOutput{}, nil
+}
+
+func newMessage(id, body string) *sqs.Message {
+	return &sqs.Message{MessageId: aws.String(id), ReceiptHandle: aws.String("handle-" + id), Body: aws.String(body)}
+}
+
+func newTestClient(config *Config, sqsClient *mockSQS, snsClient *mockSNS) *Client {
+	_ = validate(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Client{config: config, sqs: sqsClient, sns: snsClient, logger: log.NewMockLogger(io.Discard), ctx: ctx,
+		cancel: cancel}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		desc   string
+		config Config
+		err    error
+	}{
+		{"region is not provided", Config{QueueURL: queueURL}, errNoRegion},
+		{"queue and topic are not provided", Config{Region: "us-east-1"}, errNoTarget},
+	}
+
+	for i, tc := range tests {
+		c, err := New(&tc.config, log.NewMockLogger(io.Discard))
+
+		assert.Nil(t, c, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func Test_validate(t *testing.T) {
+	config := &Config{Region: "us-east-1", QueueURL: queueURL, WaitTime: 60, MaxMessages: 25}
+
+	assert.NoError(t, validate(config))
+	assert.Equal(t, int64(20), config.WaitTime, "wait time is limited to 20 seconds by SQS")
+	assert.Equal(t, int64(10), config.MaxMessages, "messages of a receive are limited to 10 by SQS")
+}
+
+func TestClient_PublishEventWithOptions(t *testing.T) {
+	sqsClient, snsClient := &mockSQS{}, &mockSNS{}
+	c := newTestClient(&Config{Region: "us-east-1", QueueURL: queueURL, TopicArn: topicArn + ".fifo"}, sqsClient, snsClient)
+
+	assert.NoError(t, c.PublishEvent("order-1", map[string]string{"id": "1"}, map[string]string{"source": "test"}))
+	assert.NoError(t, c.PublishEventWithOptions("order-1", []byte("paid"), nil, &pubsub.PublishOptions{Topic: queueURL}))
+	assert.Error(t, c.PublishEvent("", make(chan int), nil), "unmarshalable value is published")
+
+	assert.Len(t, snsClient.published, 1)
+	assert.Equal(t, `{"id":"1"}`, aws.StringValue(snsClient.published[0].Message))
+	assert.Equal(t, "order-1", aws.StringValue(snsClient.published[0].MessageGroupId), "key is the group of a FIFO topic")
+	assert.Equal(t, "test", aws.StringValue(snsClient.published[0].MessageAttributes["source"].StringValue))
+
+	assert.Len(t, sqsClient.sent, 1)
+	assert.Equal(t, queueURL, aws.StringValue(sqsClient.sent[0].QueueUrl))
+	assert.Equal(t, "paid", aws.StringValue(sqsClient.sent[0].MessageBody))
+	assert.Nil(t, sqsClient.sent[0].MessageGroupId, "standard queue must not have message groups")
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	msg := newMessage("1", `{"id":"1"}`)
+	msg.Attributes = map[string]*string{sqs.MessageSystemAttributeNameMessageGroupId: aws.String("order-1")}
+	msg.MessageAttributes = map[string]*sqs.MessageAttributeValue{"source": {StringValue: aws.String("test")}}
+
+	// the receive which has no messages is repeated
+	sqsClient := &mockSQS{batches: [][]*sqs.Message{{}, {msg, newMessage("2", "2")}, {newMessage("3", "3")}}}
+	c := newTestClient(&Config{Region: "us-east-1", QueueURL: queueURL, VisibilityTimeout: 60}, sqsClient, &mockSNS{})
+
+	got, err := c.Subscribe()
+
+	assert.NoError(t, err)
+	assert.Equal(t, &pubsub.Message{Topic: queueURL, Key: "order-1", Value: `{"id":"1"}`,
+		Headers: map[string]string{"source": "test"}}, got)
+	assert.Equal(t, int64(20), aws.Int64Value(sqsClient.received[0].WaitTimeSeconds))
+	assert.Equal(t, int64(60), aws.Int64Value(sqsClient.received[0].VisibilityTimeout))
+
+	got, _ = c.Subscribe()
+	assert.Equal(t, "2", got.Value)
+	assert.Empty(t, sqsClient.deleted, "consumed messages are deleted before the next receive")
+
+	got, _ = c.Subscribe()
+	assert.Equal(t, "3", got.Value)
+
+	assert.Len(t, sqsClient.deleted, 1)
+	assert.Len(t, sqsClient.deleted[0], 2)
+	assert.Equal(t, "handle-1", aws.StringValue(sqsClient.deleted[0][0].ReceiptHandle))
+
+	_ = c.Close()
+
+	assert.Len(t, sqsClient.deleted, 2, "consumed messages are deleted when the client is closed")
+}
+
+func TestClient_Subscribe_Error(t *testing.T) {
+	errReceive := errors.Error("access denied")
+
+	tests := []struct {
+		desc   string
+		queue  string
+		err    error
+		closed bool
+		expErr error
+	}{
+		{"queue is not configured", "", nil, false, errNoQueue},
+		{"messages could not be received", queueURL, errReceive, false, errReceive},
+		{"client is closed", queueURL, errReceive, true, errSQSIsClosing},
+	}
+
+	for i, tc := range tests {
+		c := newTestClient(&Config{Region: "us-east-1", QueueURL: tc.queue, TopicArn: topicArn}, &mockSQS{err: tc.err}, &mockSNS{})
+
+		if tc.closed {
+			_ = c.Close()
+		}
+
+		got, err := c.Subscribe()
+
+		assert.Nil(t, got, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.expErr, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestClient_SubscribeWithContext(t *testing.T) {
+	// the receives do not have messages, so that the receive is repeated till the context is done
+	sqsClient := &mockSQS{}
+	c := newTestClient(&Config{Region: "us-east-1", QueueURL: queueURL}, sqsClient, &mockSNS{})
+
+	deadline, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	got, err := c.SubscribeWithContext(deadline)
+
+	assert.Nil(t, got)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	sqsClient.batches = [][]*sqs.Message{{newMessage("1", "1")}}
+
+	got, err = c.SubscribeWithContext(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", got.Value)
+}
+
+func TestClient_SubscribeWithCommit(t *testing.T) {
+	sqsClient := &mockSQS{batches: [][]*sqs.Message{{newMessage("1", "1"), newMessage("2", "2"), newMessage("3", "3")}}}
+	c := newTestClient(&Config{Region: "us-east-1", QueueURL: queueURL}, sqsClient, &mockSNS{})
+
+	got, err := c.SubscribeWithCommit(func(m *pubsub.Message) (bool, bool) {
+		return m.Value == "1", m.Value != "3"
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3", got.Value)
+	assert.Equal(t, []string{"handle-2"}, sqsClient.released, "message which is not committed must be received again")
+
+	c.CommitOffset(pubsub.TopicPartition{Topic: got.Topic, Offset: got.Offset})
+
+	_ = c.Close()
+
+	assert.Len(t, sqsClient.deleted, 1)
+	assert.Equal(t, []string{"handle-1", "handle-3"}, []string{aws.StringValue(sqsClient.deleted[0][0].ReceiptHandle),
+		aws.StringValue(sqsClient.deleted[0][1].ReceiptHandle)}, "returned message must be deleted by CommitOffset")
+}
+
+func TestClient_HealthCheck(t *testing.T) {
+	errQueue := errors.Error("queue does not exist")
+
+	tests := []struct {
+		desc   string
+		err    error
+		status string
+	}{
+		{"queue and topic are accessible", nil, pkg.StatusUp},
+		{"queue is not accessible", errQueue, pkg.StatusDown},
+	}
+
+	for i, tc := range tests {
+		c := newTestClient(&Config{Region: "us-east-1", QueueURL: queueURL, TopicArn: topicArn}, &mockSQS{err: tc.err}, &mockSNS{})
+
+		health := c.HealthCheck()
+
+		assert.Equal(t, tc.status, health.Status, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, queueURL, health.Host, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestClient_NotSet(t *testing.T) {
+	var c *Client
+
+	_, err := c.Subscribe()
+
+	assert.Equal(t, datastore.AWSSQS, c.HealthCheck().Name)
+	assert.Equal(t, pkg.StatusDown, c.HealthCheck().Status)
+	assert.Equal(t, errSQSNotSet, c.PublishEvent("", "created", nil))
+	assert.Equal(t, errSQSNotSet, err)
+	assert.NoError(t, c.Close())
+}
@@ -0,0 +1,73 @@
+package pubsub
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PublishError is the error of a message of a batch, the index is the index of the message in the batch.
+type PublishError struct {
+	Index int
+	Err   error
+}
+
+// PublishErrors are the errors of the messages of a batch which are not published, the other messages of the batch
+// are published.
+type PublishErrors []PublishError
+
+func (e PublishErrors) Error() string {
+	if len(e) == 0 {
+		return "no message failed"
+	}
+
+	return fmt.Sprintf("%v messages could not be published, message %v: %v", len(e), e[0].Index, e[0].Err)
+}
+
+// PublishEach publishes the messages of a batch one by one, for the pubsubs which can not publish them in batches.
+func PublishEach(p PublisherSubscriber, topic string, values []interface{}, headers map[string]string) error {
+	var errs PublishErrors
+
+	for i, v := range values {
+		if err := p.PublishEventWithOptions("", v, headers, &PublishOptions{Topic: topic, Timestamp: time.Now()}); err != nil {
+			errs = append(errs, PublishError{Index: i, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// PublishEncoded publishes the messages of a batch encoded by a serializer, ex: Avro, using the pubsub of the serializer.
+// The indexes are the indexes in the batch of the messages encoded, and errs are the errors of the messages which could
+// not be encoded, so that the errors returned refer to the messages of the batch.
+func PublishEncoded(p PublisherSubscriber, topic string, encoded []interface{}, indexes []int, headers map[string]string,
+	errs PublishErrors) error {
+	if len(encoded) > 0 {
+		err := p.PublishEvents(topic, encoded, headers)
+
+		publishErrs, ok := err.(PublishErrors) //nolint:errorlint // the errors of the batches are not wrapped
+
+		switch {
+		case ok:
+			for _, e := range publishErrs {
+				errs = append(errs, PublishError{Index: indexes[e.Index], Err: e.Err})
+			}
+		case err != nil:
+			for _, i := range indexes {
+				errs = append(errs, PublishError{Index: i, Err: err})
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+
+	return errs
+}
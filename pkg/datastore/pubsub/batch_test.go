@@ -0,0 +1,92 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+type mockPublisher struct {
+	topics   []string
+	values   []interface{}
+	batchErr error
+}
+
+func (m *mockPublisher) PublishEventWithOptions(_ string, value interface{}, _ map[string]string, options *PublishOptions) error {
+	m.topics = append(m.topics, options.Topic)
+	m.values = append(m.values, value)
+
+	if value == "fail" {
+		return errors.Error("publish failed")
+	}
+
+	return nil
+}
+
+func (m *mockPublisher) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return m.PublishEventWithOptions(key, value, headers, &PublishOptions{})
+}
+
+func (m *mockPublisher) PublishEvents(_ string, values []interface{}, _ map[string]string) error {
+	m.values = values
+
+	return m.batchErr
+}
+
+func (m *mockPublisher) Subscribe() (*Message, error)                           { return nil, nil }
+func (m *mockPublisher) SubscribeWithContext(context.Context) (*Message, error) { return nil, nil }
+func (m *mockPublisher) SubscribeWithCommit(CommitFunc) (*Message, error)       { return nil, nil }
+func (m *mockPublisher) Bind([]byte, interface{}) error                         { return nil }
+func (m *mockPublisher) CommitOffset(TopicPartition)                            {}
+func (m *mockPublisher) Ping() error                                            { return nil }
+func (m *mockPublisher) HealthCheck() types.Health                              { return types.Health{} }
+func (m *mockPublisher) IsSet() bool                                            { return true }
+
+func TestPublishEach(t *testing.T) {
+	p := &mockPublisher{}
+
+	err := PublishEach(p, "orders", []interface{}{"created", "fail", "paid"}, nil)
+
+	assert.Equal(t, PublishErrors{{Index: 1, Err: errors.Error("publish failed")}}, err)
+	assert.Equal(t, []string{"orders", "orders", "orders"}, p.topics)
+	assert.Equal(t, []interface{}{"created", "fail", "paid"}, p.values)
+
+	assert.NoError(t, PublishEach(p, "orders", []interface{}{"created"}, nil))
+}
+
+func TestPublishEncoded(t *testing.T) {
+	errEncode := errors.Error("invalid value")
+	errBroker := errors.Error("broker is down")
+
+	tests := []struct {
+		desc     string
+		batchErr error
+		expErr   error
+	}{
+		{"batch published", nil, PublishErrors{{Index: 1, Err: errEncode}}},
+		{"message of the batch failed", PublishErrors{{Index: 1, Err: errBroker}},
+			PublishErrors{{Index: 1, Err: errEncode}, {Index: 2, Err: errBroker}}},
+		{"batch failed", errBroker, PublishErrors{{Index: 0, Err: errBroker}, {Index: 1, Err: errEncode}, {Index: 2, Err: errBroker}}},
+	}
+
+	for i, tc := range tests {
+		p := &mockPublisher{batchErr: tc.batchErr}
+
+		// the message 1 of the batch could not be encoded
+		err := PublishEncoded(p, "orders", []interface{}{"a", "c"}, []int{0, 2}, nil, PublishErrors{{Index: 1, Err: errEncode}})
+
+		assert.Equal(t, tc.expErr, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.NoError(t, PublishEncoded(&mockPublisher{}, "orders", []interface{}{"a"}, []int{0}, nil, nil))
+}
+
+func TestPublishErrors_Error(t *testing.T) {
+	err := PublishErrors{{Index: 3, Err: errors.Error("broker is down")}, {Index: 5, Err: errors.Error("too large")}}
+
+	assert.Equal(t, "2 messages could not be published, message 3: broker is down", err.Error())
+}
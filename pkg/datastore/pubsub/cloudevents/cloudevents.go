@@ -0,0 +1,420 @@
+// Package cloudevents provides a pubsub which publishes the messages of a pubsub as CloudEvents 1.0, in the structured
+// or the binary content mode, and unwraps the CloudEvents consumed, so that the messages are interoperable with the
+// producers and consumers of CloudEvents, ex: Knative and EventBridge.
+// Ref: https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/spec.md
+package cloudevents
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+const (
+	// Structured publishes the attributes and the data of an event in a JSON envelope, which is the value of the message
+	Structured = "structured"
+	// Binary publishes the data of an event as the value of the message, and its attributes as the headers prefixed by ce_
+	Binary = "binary"
+
+	// HeaderPrefix is the prefix of the headers of the attributes of the events in the binary mode, as per the Kafka
+	// protocol binding. The attributes of an event can be set by the headers of a published message in both the modes.
+	HeaderPrefix = "ce_"
+
+	specVersion       = "1.0"
+	structuredType    = "application/cloudevents+json"
+	defaultSource     = "gofr"
+	defaultType       = "dev.gofr.event"
+	headerContentType = "content-type"
+
+	errInvalidMode = errors.Error("cloudevents mode must be structured or binary")
+)
+
+// Config stores the configuration of the CloudEvents.
+type Config struct {
+	// Mode is the content mode of the events published, Structured or Binary, default is Structured
+	Mode string
+	// Source is the source of the events published, default is gofr
+	Source string
+	// Type is the type of the events published, default is dev.gofr.event. The type of an event is set by its ce_type header
+	Type string
+}
+
+// CloudEvents publishes the messages of the pubsub as CloudEvents, and unwraps the CloudEvents consumed.
+type CloudEvents struct {
+	config *Config
+	pubSub pubsub.PublisherSubscriber
+}
+
+// event is a CloudEvent in the structured mode, Attributes are its optional and extension attributes.
+type event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            string          `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	DataBase64      string          `json:"data_base64,omitempty"`
+
+	Attributes map[string]interface{} `json:"-"`
+}
+
+// New returns the CloudEvents of the pubsub.
+func New(config *Config, ps pubsub.PublisherSubscriber) (*CloudEvents, error) {
+	switch strings.ToLower(config.Mode) {
+	case "", Structured:
+		config.Mode = Structured
+	case Binary:
+		config.Mode = Binary
+	default:
+		return nil, errInvalidMode
+	}
+
+	if config.Source == "" {
+		config.Source = defaultSource
+	}
+
+	if config.Type == "" {
+		config.Type = defaultType
+	}
+
+	return &CloudEvents{config: config, pubSub: ps}, nil
+}
+
+/*
+PublishEventWithOptions publishes the value as the data of a CloudEvent. The id, type and time of the event are set by
+the ce_id, ce_type and ce_time headers, and are generated, or are of the config, when they are not set. The other ce_
+headers are published as the extension attributes of the event.
+*/
+func (c *CloudEvents) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) error {
+	value, headers, err := c.encode(value, headers, options)
+	if err != nil {
+		return err
+	}
+
+	return c.pubSub.PublishEventWithOptions(key, value, headers, options)
+}
+
+// PublishEvent publishes the value as the data of a CloudEvent
+func (c *CloudEvents) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return c.PublishEventWithOptions(key, value, headers, nil)
+}
+
+// PublishEvents publishes the values as the data of the CloudEvents, in a batch in the structured mode, and one by one
+// in the binary mode, as every event has its own headers.
+func (c *CloudEvents) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	if c.config.Mode == Binary {
+		return pubsub.PublishEach(c, topic, values, headers)
+	}
+
+	var (
+		errs        pubsub.PublishErrors
+		encodedHdrs map[string]string
+	)
+
+	encoded := make([]interface{}, 0, len(values))
+	indexes := make([]int, 0, len(values))
+	options := &pubsub.PublishOptions{Topic: topic, Timestamp: time.Now()}
+
+	for i, value := range values {
+		v, h, err := c.encode(value, headers, options)
+		if err != nil {
+			errs = append(errs, pubsub.PublishError{Index: i, Err: err})
+			continue
+		}
+
+		encoded = append(encoded, v)
+		indexes = append(indexes, i)
+		encodedHdrs = h
+	}
+
+	return pubsub.PublishEncoded(c.pubSub, topic, encoded, indexes, encodedHdrs, errs)
+}
+
+// encode returns the value and the headers of the message of the CloudEvent of the value.
+func (c *CloudEvents) encode(value interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) (interface{}, map[string]string, error) {
+	attributes := make(map[string]string)
+	h := make(map[string]string, len(headers))
+
+	for k, v := range headers {
+		if strings.HasPrefix(k, HeaderPrefix) {
+			attributes[strings.TrimPrefix(k, HeaderPrefix)] = v
+		} else {
+			h[k] = v
+		}
+	}
+
+	c.setDefaults(attributes, options)
+
+	if c.config.Mode == Binary {
+		for k, v := range attributes {
+			h[HeaderPrefix+k] = v
+		}
+
+		return value, h, nil
+	}
+
+	e := event{SpecVersion: specVersion, ID: attributes["id"], Source: attributes["source"], Type: attributes["type"],
+		Time: attributes["time"], DataContentType: attributes["datacontenttype"]}
+
+	if err := e.setData(value); err != nil {
+		return nil, nil, err
+	}
+
+	for _, k := range []string{"specversion", "id", "source", "type", "time", "datacontenttype"} {
+		delete(attributes, k)
+	}
+
+	e.Attributes = make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		e.Attributes[k] = v
+	}
+
+	b, err := e.MarshalJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h[headerContentType] = structuredType
+
+	return b, h, nil
+}
+
+// setDefaults sets the required attributes, and the time, of the event which are not set by the headers.
+func (c *CloudEvents) setDefaults(attributes map[string]string, options *pubsub.PublishOptions) {
+	defaults := map[string]string{"specversion": specVersion, "source": c.config.Source, "type": c.config.Type}
+
+	for k, v := range defaults {
+		if attributes[k] == "" {
+			attributes[k] = v
+		}
+	}
+
+	if attributes["id"] == "" {
+		attributes["id"] = uuid.NewString()
+	}
+
+	if attributes["time"] == "" {
+		t := time.Now()
+		if options != nil && !options.Timestamp.IsZero() {
+			t = options.Timestamp
+		}
+
+		attributes["time"] = t.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// setData sets the value as the data of the event, the bytes which are not JSON are encoded in base64.
+func (e *event) setData(value interface{}) error {
+	contentType := "application/json"
+
+	if b, ok := value.([]byte); ok && !json.Valid(b) {
+		e.DataBase64 = base64.StdEncoding.EncodeToString(b)
+		contentType = "application/octet-stream"
+	} else if ok {
+		e.Data = b
+	} else {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		e.Data = b
+	}
+
+	if e.DataContentType == "" {
+		e.DataContentType = contentType
+	}
+
+	return nil
+}
+
+// MarshalJSON marshals the event along with its extension attributes.
+func (e *event) MarshalJSON() ([]byte, error) {
+	type plain event
+
+	b, err := json.Marshal((*plain)(e))
+	if err != nil || len(e.Attributes) == 0 {
+		return b, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	for k, v := range e.Attributes {
+		if fields[k], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// unwrap returns the data and the attributes of the message, when it is a CloudEvent in the structured mode.
+func unwrap(value []byte) (data []byte, attributes map[string]interface{}, ok bool) {
+	if len(value) == 0 || value[0] != '{' {
+		return nil, nil, false
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(value, &fields); err != nil {
+		return nil, nil, false
+	}
+
+	if _, ok = fields["specversion"]; !ok {
+		return nil, nil, false
+	}
+
+	data = fields["data"]
+
+	if encoded, isBase64 := fields["data_base64"]; isBase64 {
+		var s string
+
+		if err := json.Unmarshal(encoded, &s); err != nil {
+			return nil, nil, false
+		}
+
+		var err error
+		if data, err = base64.StdEncoding.DecodeString(s); err != nil {
+			return nil, nil, false
+		}
+	}
+
+	attributes = make(map[string]interface{}, len(fields))
+
+	for k, v := range fields {
+		if k == "data" || k == "data_base64" {
+			continue
+		}
+
+		var attr interface{}
+		if err := json.Unmarshal(v, &attr); err == nil {
+			attributes[k] = attr
+		}
+	}
+
+	return data, attributes, true
+}
+
+/*
+decode returns the message with the data of the CloudEvent in the structured mode as its value, and the attributes of
+the event as its ce_ headers, so that the messages of both the modes are handled alike. The other messages are returned
+as they are.
+*/
+func decode(msg *pubsub.Message) *pubsub.Message {
+	data, attributes, ok := unwrap([]byte(msg.Value))
+	if !ok {
+		return msg
+	}
+
+	headers := make(map[string]string, len(msg.Headers)+len(attributes))
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+
+	for k, v := range attributes {
+		if s, isString := v.(string); isString {
+			headers[HeaderPrefix+k] = s
+		} else {
+			b, _ := json.Marshal(v)
+			headers[HeaderPrefix+k] = string(b)
+		}
+	}
+
+	decoded := *msg
+	decoded.Value = string(data)
+	decoded.Headers = headers
+
+	return &decoded
+}
+
+// Subscribe reads a message, the value of a CloudEvent in the structured mode is its data
+func (c *CloudEvents) Subscribe() (*pubsub.Message, error) {
+	msg, err := c.pubSub.Subscribe()
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(msg), nil
+}
+
+// SubscribeWithContext reads a message like Subscribe, till the context is done
+func (c *CloudEvents) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
+	msg, err := c.pubSub.SubscribeWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(msg), nil
+}
+
+// SubscribeWithCommit calls the CommitFunc with the messages of the CloudEvents, and based on the return values decides
+// whether to commit the message and consume another message
+func (c *CloudEvents) SubscribeWithCommit(f pubsub.CommitFunc) (*pubsub.Message, error) {
+	var decoded *pubsub.Message
+
+	_, err := c.pubSub.SubscribeWithCommit(func(msg *pubsub.Message) (bool, bool) {
+		decoded = decode(msg)
+
+		if f == nil {
+			return false, false
+		}
+
+		return f(decoded)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+// Bind binds the data of the CloudEvent in the structured mode, or the message, to the target using the pubsub
+func (c *CloudEvents) Bind(message []byte, target interface{}) error {
+	if data, _, ok := unwrap(message); ok {
+		message = data
+	}
+
+	return c.pubSub.Bind(message, target)
+}
+
+// CommitOffset commits the offset of the message to the pubsub
+func (c *CloudEvents) CommitOffset(offsets pubsub.TopicPartition) {
+	c.pubSub.CommitOffset(offsets)
+}
+
+// Ping checks the health of the pubsub, returns an error if it is down
+func (c *CloudEvents) Ping() error {
+	return c.pubSub.Ping()
+}
+
+// HealthCheck returns the health of the pubsub
+func (c *CloudEvents) HealthCheck() types.Health {
+	return c.pubSub.HealthCheck()
+}
+
+// IsSet checks whether the pubsub is initialized
+func (c *CloudEvents) IsSet() bool {
+	return c != nil && c.pubSub != nil && c.pubSub.IsSet()
+}
+
+// Close closes the pubsub, when it can be closed
+func (c *CloudEvents) Close() error {
+	if closer, ok := c.pubSub.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
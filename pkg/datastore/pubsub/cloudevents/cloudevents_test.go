@@ -0,0 +1,131 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/datastore/pubsub/inprocess"
+)
+
+func newCloudEvents(t *testing.T, mode string) (*CloudEvents, *inprocess.Bus) {
+	bus, err := inprocess.New(&inprocess.Config{Topics: []string{"orders"}})
+	if err != nil {
+		t.Fatalf("in-process pubsub could not be initialized: %v", err)
+	}
+
+	c, err := New(&Config{Mode: mode, Source: "/orders-api"}, bus)
+	if err != nil {
+		t.Fatalf("cloudevents could not be initialized: %v", err)
+	}
+
+	return c, bus
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&Config{Mode: "batched"}, nil)
+	assert.Equal(t, errInvalidMode, err)
+
+	cfg := &Config{}
+
+	_, err = New(cfg, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{Mode: Structured, Source: defaultSource, Type: defaultType}, cfg)
+}
+
+func TestCloudEvents_Structured(t *testing.T) {
+	c, bus := newCloudEvents(t, "STRUCTURED")
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	err := c.PublishEventWithOptions("o-1", map[string]int{"id": 1},
+		map[string]string{"tenant": "acme", "ce_type": "order.created", "ce_id": "e-1", "ce_region": "eu"},
+		&pubsub.PublishOptions{Timestamp: ts})
+	assert.NoError(t, err)
+
+	raw, _ := bus.Subscribe()
+
+	var envelope map[string]interface{}
+
+	assert.NoError(t, json.Unmarshal([]byte(raw.Value), &envelope))
+	assert.Equal(t, map[string]interface{}{"specversion": "1.0", "id": "e-1", "source": "/orders-api", "type": "order.created",
+		"time": "2026-01-02T03:04:05Z", "datacontenttype": "application/json", "data": map[string]interface{}{"id": float64(1)},
+		"region": "eu"}, envelope)
+	assert.Equal(t, map[string]string{"tenant": "acme", "content-type": structuredType}, raw.Headers)
+
+	assert.NoError(t, c.PublishEvent("o-2", []byte{0xff, 0x01}, nil))
+
+	msg, err := c.Subscribe()
+
+	assert.NoError(t, err)
+	assert.Equal(t, string([]byte{0xff, 0x01}), msg.Value, "the data in base64 is decoded")
+	assert.Equal(t, "application/octet-stream", msg.Headers["ce_datacontenttype"])
+	assert.NotEmpty(t, msg.Headers["ce_id"])
+	assert.Equal(t, "dev.gofr.event", msg.Headers["ce_type"])
+}
+
+func TestCloudEvents_Binary(t *testing.T) {
+	c, bus := newCloudEvents(t, Binary)
+
+	assert.NoError(t, c.PublishEvent("o-1", map[string]int{"id": 1}, map[string]string{"ce_type": "order.created"}))
+
+	msg, err := bus.Subscribe()
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1}`, msg.Value)
+	assert.Equal(t, "1.0", msg.Headers["ce_specversion"])
+	assert.Equal(t, "/orders-api", msg.Headers["ce_source"])
+	assert.Equal(t, "order.created", msg.Headers["ce_type"])
+	assert.NotEmpty(t, msg.Headers["ce_id"])
+	assert.NotEmpty(t, msg.Headers["ce_time"])
+
+	assert.NoError(t, c.PublishEvents("", []interface{}{"a", "b"}, nil))
+
+	first, _ := bus.Subscribe()
+	second, _ := bus.Subscribe()
+
+	assert.NotEqual(t, first.Headers["ce_id"], second.Headers["ce_id"], "every event has its own id")
+}
+
+func TestCloudEvents_Bind(t *testing.T) {
+	c, _ := newCloudEvents(t, Structured)
+
+	var order struct {
+		ID int `json:"id"`
+	}
+
+	assert.NoError(t, c.Bind([]byte(`{"specversion":"1.0","id":"e-1","source":"s","type":"t","data":{"id":7}}`), &order))
+	assert.Equal(t, 7, order.ID, "the data of the event is bound")
+
+	assert.NoError(t, c.Bind([]byte(`{"id":8}`), &order))
+	assert.Equal(t, 8, order.ID, "the messages which are not events are bound as they are")
+}
+
+func TestCloudEvents_PublishEvents(t *testing.T) {
+	c, bus := newCloudEvents(t, Structured)
+
+	err := c.PublishEvents("orders", []interface{}{"a", make(chan int), "b"}, map[string]string{"ce_type": "order.created"})
+
+	assert.Equal(t, 1, err.(pubsub.PublishErrors)[0].Index) //nolint:errorlint // the errors of the batches are not wrapped
+
+	var values []string
+
+	_, err = c.SubscribeWithCommit(func(msg *pubsub.Message) (bool, bool) {
+		values = append(values, msg.Value)
+
+		assert.Equal(t, "order.created", msg.Headers["ce_type"])
+
+		return true, len(values) < 2
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`"a"`, `"b"`}, values)
+	assert.True(t, c.IsSet())
+	assert.NoError(t, c.Close())
+
+	_, err = bus.Subscribe()
+	assert.Error(t, err, "the pubsub is closed")
+}
@@ -0,0 +1,92 @@
+package pubsub
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	// ContentTypeHeader is the header of the content type of a message, ex: application/x-protobuf.
+	ContentTypeHeader = "Content-Type"
+
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeMsgpack  = "application/msgpack"
+
+	// ErrUnsupportedContentType is returned when a message can not be decoded, as its content type is not supported.
+	ErrUnsupportedContentType = errors.Error("content type of the message is not supported")
+	// ErrNotProtoMessage is returned when a Protobuf message is decoded into a target which is not a proto.Message.
+	ErrNotProtoMessage = errors.Error("target of the Protobuf message is not a proto.Message")
+)
+
+// ContentType returns the media type of the Content-Type header of the message, the header is matched without case,
+// as the backends do not all keep the case of the headers. It is empty when the message has no content type.
+func (m *Message) ContentType() string {
+	if m == nil {
+		return ""
+	}
+
+	for key, value := range m.Headers {
+		if strings.EqualFold(key, ContentTypeHeader) {
+			mediaType, _, err := mime.ParseMediaType(value)
+			if err != nil {
+				return strings.ToLower(strings.TrimSpace(value))
+			}
+
+			return mediaType
+		}
+	}
+
+	return ""
+}
+
+/*
+Unmarshal decodes the data of the content type into the target. The content types supported are JSON, including the
+types with the +json suffix, Protobuf, ex: application/x-protobuf or application/protobuf, whose target must be a
+proto.Message, and MessagePack, ex: application/msgpack, whose fields are matched by their json tags, like the JSON
+messages.
+*/
+func Unmarshal(contentType string, data []byte, target interface{}) error {
+	// Context.Subscribe binds to a pointer to the target
+	if t, ok := target.(*interface{}); ok && *t != nil {
+		target = *t
+	}
+
+	switch {
+	case contentType == ContentTypeJSON || strings.HasSuffix(contentType, "+json"):
+		return json.Unmarshal(data, target)
+	case isProtobuf(contentType):
+		msg, ok := target.(proto.Message)
+		if !ok {
+			return ErrNotProtoMessage
+		}
+
+		return proto.Unmarshal(data, msg)
+	case isMsgpack(contentType):
+		dec := msgpack.NewDecoder(bytes.NewReader(data))
+		dec.SetCustomStructTag("json")
+
+		return dec.Decode(target)
+	}
+
+	return ErrUnsupportedContentType
+}
+
+func isProtobuf(contentType string) bool {
+	return contentType == ContentTypeProtobuf || contentType == "application/protobuf" ||
+		contentType == "application/vnd.google.protobuf" || contentType == "application/x-protobuf3"
+}
+
+// isMsgpack checks the content type for the registered MessagePack type, along with the unregistered types used by the
+// older producers.
+func isMsgpack(contentType string) bool {
+	return contentType == ContentTypeMsgpack || contentType == "application/x-msgpack" ||
+		contentType == "application/vnd.msgpack"
+}
@@ -0,0 +1,57 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type order struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+func TestMessage_ContentType(t *testing.T) {
+	tests := []struct {
+		desc        string
+		headers     map[string]string
+		contentType string
+	}{
+		{"no content type", map[string]string{"X-Correlation-ID": "1"}, ""},
+		{"content type with parameters", map[string]string{"Content-Type": "Application/JSON; charset=utf-8"}, "application/json"},
+		{"lower case header", map[string]string{"content-type": "application/x-protobuf"}, "application/x-protobuf"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.contentType, (&Message{Headers: tc.headers}).ContentType(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	protoValue, _ := proto.Marshal(wrapperspb.String("shipped"))
+	msgpackValue, _ := msgpack.Marshal(map[string]interface{}{"id": 1, "status": "shipped"})
+
+	var (
+		fromJSON, fromMsgpack order
+		fromProto                         = &wrapperspb.StringValue{}
+		wrapped               interface{} = &order{}
+	)
+
+	assert.NoError(t, Unmarshal(ContentTypeJSON, []byte(`{"id": 1, "status": "shipped"}`), &fromJSON))
+	assert.Equal(t, order{ID: 1, Status: "shipped"}, fromJSON)
+
+	assert.NoError(t, Unmarshal("application/cloudevents+json", []byte(`{"id": 2}`), &wrapped))
+	assert.Equal(t, &order{ID: 2}, wrapped)
+
+	assert.NoError(t, Unmarshal(ContentTypeMsgpack, msgpackValue, &fromMsgpack))
+	assert.Equal(t, order{ID: 1, Status: "shipped"}, fromMsgpack)
+
+	assert.NoError(t, Unmarshal("application/protobuf", protoValue, fromProto))
+	assert.Equal(t, "shipped", fromProto.GetValue())
+
+	assert.Equal(t, ErrNotProtoMessage, Unmarshal(ContentTypeProtobuf, protoValue, &fromJSON))
+	assert.Equal(t, ErrUnsupportedContentType, Unmarshal("text/csv", []byte("1,shipped"), &fromJSON))
+}
@@ -2,6 +2,7 @@
 package eventbridge
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -102,6 +103,11 @@ func (c *Client) PublishEvent(detailType string, detail interface{}, _ map[strin
 	return nil
 }
 
+// PublishEvents publishes the events one by one, as the events are not published in batches
+func (c *Client) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	return pubsub.PublishEach(c, topic, values, headers)
+}
+
 // PublishEventWithOptions not implemented for Eventbridge
 func (c *Client) PublishEventWithOptions(string, interface{}, map[string]string,
 	*pubsub.PublishOptions) (err error) {
@@ -113,6 +119,11 @@ func (c *Client) Subscribe() (*pubsub.Message, error) {
 	return nil, nil
 }
 
+// SubscribeWithContext not implemented for Eventbridge
+func (c *Client) SubscribeWithContext(context.Context) (*pubsub.Message, error) {
+	return nil, nil
+}
+
 // SubscribeWithCommit not implemented for Eventbridge
 func (c *Client) SubscribeWithCommit(pubsub.CommitFunc) (*pubsub.Message, error) {
 	return nil, nil
@@ -4,6 +4,7 @@ package eventhub
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"strconv"
 	"sync"
 
@@ -104,8 +105,9 @@ func (e *Eventhub) PublishEvent(key string, value interface{}, headers map[strin
 	return e.PublishEventWithOptions(key, value, headers, nil)
 }
 
-// PublishEventWithOptions publishes message to eventhub. Ability to provide additional options described in PublishOptions struct
-func (e *Eventhub) PublishEventWithOptions(key string, value interface{}, _ map[string]string,
+// PublishEventWithOptions publishes message to eventhub, the headers are sent as the properties of the event. Ability to
+// provide additional options described in PublishOptions struct
+func (e *Eventhub) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
 	_ *pubsub.PublishOptions) (err error) {
 	// for every publish
 	pubsub.PublishTotalCount(e.EventhubName, "")
@@ -123,6 +125,10 @@ func (e *Eventhub) PublishEventWithOptions(key string, value interface{}, _ map[
 
 	event := eventhub.NewEvent(data)
 
+	for k, v := range headers {
+		event.Set(k, v)
+	}
+
 	err = e.hub.Send(context.TODO(), event, eventhub.SendWithMessageID(key))
 	if err != nil {
 		// for unsuccessful publish
@@ -137,25 +143,96 @@ func (e *Eventhub) PublishEventWithOptions(key string, value interface{}, _ map[
 	return nil
 }
 
+// PublishEvents publishes the events to the eventhub in batches, the topic is ignored as the eventhub is configured.
+// The events are not published when a batch fails, hence the errors of all the events are returned.
+func (e *Eventhub) PublishEvents(_ string, values []interface{}, headers map[string]string) error {
+	var errs pubsub.PublishErrors
+
+	events := make([]*eventhub.Event, 0, len(values))
+	indexes := make([]int, 0, len(values))
+
+	for i, value := range values {
+		pubsub.PublishTotalCount(e.EventhubName, "")
+
+		data, ok := value.([]byte)
+		if !ok {
+			var err error
+
+			if data, err = json.Marshal(value); err != nil {
+				pubsub.PublishFailureCount(e.EventhubName, "")
+
+				errs = append(errs, pubsub.PublishError{Index: i, Err: err})
+
+				continue
+			}
+		}
+
+		event := eventhub.NewEvent(data)
+
+		for k, v := range headers {
+			event.Set(k, v)
+		}
+
+		events = append(events, event)
+		indexes = append(indexes, i)
+	}
+
+	if len(events) > 0 {
+		if err := e.hub.SendBatch(context.TODO(), eventhub.NewEventBatchIterator(events...)); err != nil {
+			for _, i := range indexes {
+				pubsub.PublishFailureCount(e.EventhubName, "")
+
+				errs = append(errs, pubsub.PublishError{Index: i, Err: err})
+			}
+		} else {
+			for range indexes {
+				pubsub.PublishSuccessCount(e.EventhubName, "")
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+
+		return errs
+	}
+
+	return nil
+}
+
 // Subscribe read messages from eventhub configured
 func (e *Eventhub) Subscribe() (*pubsub.Message, error) {
+	return e.SubscribeWithContext(context.Background())
+}
+
+// SubscribeWithContext reads a message like Subscribe, till the context is done. The receivers of the partitions are
+// closed once a message is read, or the context is done, and the offset is kept only for the message which is returned.
+func (e *Eventhub) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
 	// for every subscribe
 	pubsub.SubscribeReceiveCount(e.EventhubName, "")
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	msg := make(chan *pubsub.Message)
 
-	handler := func(ctx context.Context, event *eventhub.Event) error {
+	handler := func(_ context.Context, event *eventhub.Event) error {
 		var partition int
 
 		if event.SystemProperties.PartitionID != nil {
 			partition = int(*event.SystemProperties.PartitionID)
 		}
 
-		msg <- &pubsub.Message{
+		select {
+		case msg <- &pubsub.Message{
 			Value:     string(event.Data),
 			Partition: partition,
 			Offset:    *event.SystemProperties.Offset,
 			Topic:     e.EventhubName,
+			Headers:   eventHeaders(event),
+		}:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 
 		e.partitionOffsetMap[strconv.Itoa(partition)] = strconv.Itoa(int(*event.SystemProperties.Offset))
@@ -163,8 +240,6 @@ func (e *Eventhub) Subscribe() (*pubsub.Message, error) {
 		return nil
 	}
 
-	ctx := context.TODO()
-
 	runtimeInfo, err := e.hub.GetRuntimeInformation(ctx)
 	if err != nil {
 		// for failed subscribe
@@ -184,17 +259,27 @@ func (e *Eventhub) Subscribe() (*pubsub.Message, error) {
 	for _, partitionID := range runtimeInfo.PartitionIDs {
 		offset := e.partitionOffsetMap[partitionID]
 
-		_, err := e.hub.Receive(ctx, partitionID, handler, eventhub.ReceiveWithStartingOffset(offset))
+		handle, err := e.hub.Receive(ctx, partitionID, handler, eventhub.ReceiveWithStartingOffset(offset))
 		if err != nil {
 			// for failed subscribe
 			pubsub.SubscribeFailureCount(e.EventhubName, "")
 			return nil, err
 		}
+
+		defer handle.Close(context.Background())
 	}
-	// for successful subscribe
-	pubsub.SubscribeSuccessCount(e.EventhubName, "")
 
-	return <-msg, nil
+	select {
+	case m := <-msg:
+		// for successful subscribe
+		pubsub.SubscribeSuccessCount(e.EventhubName, "")
+
+		return m, nil
+	case <-ctx.Done():
+		pubsub.SubscribeFailureCount(e.EventhubName, "")
+
+		return nil, ctx.Err()
+	}
 }
 
 /*
@@ -276,3 +361,20 @@ func NewEventHubWithAvro(config *AvroWithEventhubConfig, logger log.Logger) (pub
 
 	return p, nil
 }
+
+// eventHeaders returns the string properties of the event, which are the headers of its message.
+func eventHeaders(event *eventhub.Event) map[string]string {
+	if len(event.Properties) == 0 {
+		return nil
+	}
+
+	h := make(map[string]string, len(event.Properties))
+
+	for k, v := range event.Properties {
+		if s, ok := v.(string); ok {
+			h[k] = s
+		}
+	}
+
+	return h
+}
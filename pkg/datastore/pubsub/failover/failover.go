@@ -0,0 +1,317 @@
+// Package failover provides a pubsub which publishes to and consumes from a primary pubsub, ie: the brokers of a region,
+// and fails over to a secondary pubsub when the primary fails, so that the messages are published and consumed during
+// the outage of a region.
+package failover
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+)
+
+const (
+	primary   = 0
+	secondary = 1
+
+	defaultFailureThreshold = 5
+	defaultFailbackInterval = 30 * time.Second
+
+	errNoPubSub = errors.Error("primary and secondary pubsub are required for the failover")
+)
+
+//nolint:gochecknoglobals // activeRegion and failovers have to be global variables for prometheus
+var (
+	activeRegion = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: pkg.FrameworkMetricsPrefix + "pubsub_active_region",
+		Help: "Gauge which is 1 for the region of the pubsub which is in use, and 0 for the other region",
+	}, []string{"region"})
+
+	failovers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: pkg.FrameworkMetricsPrefix + "pubsub_failover_count",
+		Help: "Counter for the switches of the pubsub from a region to the other",
+	}, []string{"from", "to"})
+
+	_ = prometheus.Register(activeRegion)
+	_ = prometheus.Register(failovers)
+)
+
+// Config stores the configuration of the failover.
+type Config struct {
+	// PrimaryRegion and SecondaryRegion are the names of the regions of the pubsubs, reported by the metrics and the
+	// health check, default are primary and secondary
+	PrimaryRegion   string
+	SecondaryRegion string
+	// FailureThreshold is the number of consecutive failures of the publishes and the subscribes of the pubsub in use,
+	// after which the other pubsub is used, default is 5
+	FailureThreshold int
+	// FailbackInterval is the interval at which the primary is pinged while the secondary is in use, the primary is used
+	// again once it is healthy, default is 30 seconds
+	FailbackInterval time.Duration
+}
+
+type region struct {
+	name   string
+	pubSub pubsub.PublisherSubscriber
+}
+
+// Failover is a pubsub which uses the primary pubsub, or the secondary pubsub when the primary fails.
+type Failover struct {
+	config  *Config
+	regions [2]region
+	logger  log.Logger
+
+	// mu guards the region in use, its consecutive failures, and the time at which the primary was last pinged
+	mu        sync.Mutex
+	active    int
+	failures  int
+	lastProbe time.Time
+
+	// consumer is the region of the last consumed message, to which its offset is committed
+	consumer int
+}
+
+// New returns the failover of the primary and the secondary pubsub, the primary is used until it fails.
+func New(config *Config, primaryPubSub, secondaryPubSub pubsub.PublisherSubscriber, logger log.Logger) (*Failover, error) {
+	if primaryPubSub == nil || secondaryPubSub == nil {
+		return nil, errNoPubSub
+	}
+
+	if config.PrimaryRegion == "" {
+		config.PrimaryRegion = "primary"
+	}
+
+	if config.SecondaryRegion == "" {
+		config.SecondaryRegion = "secondary"
+	}
+
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaultFailureThreshold
+	}
+
+	if config.FailbackInterval <= 0 {
+		config.FailbackInterval = defaultFailbackInterval
+	}
+
+	f := &Failover{config: config, logger: logger, regions: [2]region{
+		{name: config.PrimaryRegion, pubSub: primaryPubSub},
+		{name: config.SecondaryRegion, pubSub: secondaryPubSub},
+	}}
+
+	activeRegion.WithLabelValues(config.PrimaryRegion).Set(1)
+	activeRegion.WithLabelValues(config.SecondaryRegion).Set(0)
+
+	return f, nil
+}
+
+// current returns the region in use. While the secondary is in use, the primary is pinged once in the failback interval,
+// and it is used again when the ping succeeds.
+func (f *Failover) current() int {
+	f.mu.Lock()
+
+	if f.active == primary || time.Since(f.lastProbe) < f.config.FailbackInterval {
+		active := f.active
+		f.mu.Unlock()
+
+		return active
+	}
+
+	f.lastProbe = time.Now()
+	f.mu.Unlock()
+
+	// the primary is pinged without the lock, so that the other calls are not blocked by the ping
+	if err := f.regions[primary].pubSub.Ping(); err != nil {
+		return secondary
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.active == secondary {
+		f.switchTo(primary)
+	}
+
+	return f.active
+}
+
+// record records the result of a call to the region, and switches to the other region when the failures of the region
+// reach the threshold. It returns true when the region is switched.
+func (f *Failover) record(r int, err error) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// the calls to the region which was in use before a switch are not counted
+	if r != f.active {
+		return false
+	}
+
+	if err == nil {
+		f.failures = 0
+
+		return false
+	}
+
+	f.failures++
+
+	if f.failures < f.config.FailureThreshold {
+		return false
+	}
+
+	f.logger.Errorf("pubsub of region %v failed %v times, the last error: %v", f.regions[r].name, f.failures, err)
+	f.switchTo(1 - r)
+
+	return true
+}
+
+// switchTo switches the region in use, it is called with the lock held.
+func (f *Failover) switchTo(r int) {
+	from, to := f.regions[f.active].name, f.regions[r].name
+
+	f.active, f.failures, f.lastProbe = r, 0, time.Now()
+
+	activeRegion.WithLabelValues(from).Set(0)
+	activeRegion.WithLabelValues(to).Set(1)
+	failovers.WithLabelValues(from, to).Inc()
+
+	f.logger.Warnf("pubsub is switched from region %v to region %v", from, to)
+}
+
+/*
+PublishEventWithOptions publishes the message to the pubsub in use. The message which fails the pubsub over is published
+to the other pubsub, so that it is not lost.
+*/
+func (f *Failover) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) error {
+	r := f.current()
+
+	err := f.regions[r].pubSub.PublishEventWithOptions(key, value, headers, options)
+	if !f.record(r, err) {
+		return err
+	}
+
+	r = 1 - r
+
+	err = f.regions[r].pubSub.PublishEventWithOptions(key, value, headers, options)
+	f.record(r, err)
+
+	return err
+}
+
+// PublishEvent publishes the message to the pubsub in use
+func (f *Failover) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return f.PublishEventWithOptions(key, value, headers, nil)
+}
+
+// PublishEvents publishes the messages one by one, so that the messages after a failover are published to the other pubsub
+func (f *Failover) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	return pubsub.PublishEach(f, topic, values, headers)
+}
+
+// Subscribe returns the next message of the pubsub in use, the consumers of the other pubsub consume from it after a
+// failover, from the offsets committed in its region.
+func (f *Failover) Subscribe() (*pubsub.Message, error) {
+	r := f.current()
+
+	msg, err := f.regions[r].pubSub.Subscribe()
+	f.consumed(r, err)
+
+	return msg, err
+}
+
+// SubscribeWithContext consumes a message of the pubsub in use, till the context is done. The context being done is
+// not a failure of the region.
+func (f *Failover) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
+	r := f.current()
+
+	msg, err := f.regions[r].pubSub.SubscribeWithContext(ctx)
+	if err != nil && ctx.Err() != nil {
+		return nil, err
+	}
+
+	f.consumed(r, err)
+
+	return msg, err
+}
+
+// SubscribeWithCommit consumes the messages of the pubsub in use, using the CommitFunc
+func (f *Failover) SubscribeWithCommit(commitFunc pubsub.CommitFunc) (*pubsub.Message, error) {
+	r := f.current()
+
+	msg, err := f.regions[r].pubSub.SubscribeWithCommit(commitFunc)
+	f.consumed(r, err)
+
+	return msg, err
+}
+
+func (f *Failover) consumed(r int, err error) {
+	f.record(r, err)
+
+	if err == nil {
+		f.mu.Lock()
+		f.consumer = r
+		f.mu.Unlock()
+	}
+}
+
+// Bind parses the message using the pubsub in use
+func (f *Failover) Bind(message []byte, target interface{}) error {
+	return f.regions[f.current()].pubSub.Bind(message, target)
+}
+
+// CommitOffset commits the offset to the pubsub from which the last message is consumed, as the offsets of a region do
+// not apply to the other region
+func (f *Failover) CommitOffset(offsets pubsub.TopicPartition) {
+	f.mu.Lock()
+	r := f.consumer
+	f.mu.Unlock()
+
+	f.regions[r].pubSub.CommitOffset(offsets)
+}
+
+// Ping pings the pubsub in use
+func (f *Failover) Ping() error {
+	return f.regions[f.current()].pubSub.Ping()
+}
+
+// HealthCheck returns the health of the pubsub in use, along with its region
+func (f *Failover) HealthCheck() types.Health {
+	r := f.current()
+
+	health := f.regions[r].pubSub.HealthCheck()
+	details := map[string]interface{}{"region": f.regions[r].name}
+
+	if health.Details != nil {
+		details["details"] = health.Details
+	}
+
+	health.Details = details
+
+	return health
+}
+
+// IsSet checks whether the pubsub in use is initialized
+func (f *Failover) IsSet() bool {
+	return f != nil && f.regions[f.current()].pubSub.IsSet()
+}
+
+// Close closes both the pubsubs, which can be closed
+func (f *Failover) Close() error {
+	var err error
+
+	for _, r := range f.regions {
+		if c, ok := r.pubSub.(interface{ Close() error }); ok {
+			if e := c.Close(); e != nil {
+				err = e
+			}
+		}
+	}
+
+	return err
+}
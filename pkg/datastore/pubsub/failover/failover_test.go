@@ -0,0 +1,178 @@
+package failover
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+)
+
+const errBroker = errors.Error("broker is down")
+
+// mockPubSub is a pubsub which fails while it is down, and records the messages published and the offsets committed
+type mockPubSub struct {
+	down      bool
+	published []string
+	committed []int64
+}
+
+func (m *mockPubSub) err() error {
+	if m.down {
+		return errBroker
+	}
+
+	return nil
+}
+
+func (m *mockPubSub) PublishEventWithOptions(key string, _ interface{}, _ map[string]string, _ *pubsub.PublishOptions) error {
+	if m.down {
+		return errBroker
+	}
+
+	m.published = append(m.published, key)
+
+	return nil
+}
+
+func (m *mockPubSub) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return m.PublishEventWithOptions(key, value, headers, nil)
+}
+
+func (m *mockPubSub) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	return pubsub.PublishEach(m, topic, values, headers)
+}
+
+func (m *mockPubSub) Subscribe() (*pubsub.Message, error) {
+	if m.down {
+		return nil, errBroker
+	}
+
+	return &pubsub.Message{Offset: int64(len(m.committed))}, nil
+}
+
+func (m *mockPubSub) SubscribeWithContext(context.Context) (*pubsub.Message, error) {
+	return m.Subscribe()
+}
+
+func (m *mockPubSub) SubscribeWithCommit(pubsub.CommitFunc) (*pubsub.Message, error) {
+	return m.Subscribe()
+}
+
+func (m *mockPubSub) Bind([]byte, interface{}) error { return nil }
+
+func (m *mockPubSub) CommitOffset(offsets pubsub.TopicPartition) {
+	m.committed = append(m.committed, offsets.Offset)
+}
+
+func (m *mockPubSub) Ping() error { return m.err() }
+
+func (m *mockPubSub) HealthCheck() types.Health {
+	if m.down {
+		return types.Health{Name: "mock", Status: "DOWN"}
+	}
+
+	return types.Health{Name: "mock", Status: "UP"}
+}
+
+func (m *mockPubSub) IsSet() bool { return !m.down }
+
+func newFailover(t *testing.T, threshold int, interval time.Duration) (f *Failover, p, s *mockPubSub) {
+	p, s = &mockPubSub{}, &mockPubSub{}
+
+	f, err := New(&Config{PrimaryRegion: "eu-west", SecondaryRegion: "eu-north", FailureThreshold: threshold,
+		FailbackInterval: interval}, p, s, log.NewMockLogger(io.Discard))
+	if err != nil {
+		t.Fatalf("failover could not be initialized: %v", err)
+	}
+
+	return f, p, s
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&Config{}, &mockPubSub{}, nil, log.NewMockLogger(io.Discard))
+	assert.Equal(t, errNoPubSub, err)
+
+	cfg := &Config{}
+
+	_, err = New(cfg, &mockPubSub{}, &mockPubSub{}, log.NewMockLogger(io.Discard))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{PrimaryRegion: "primary", SecondaryRegion: "secondary", FailureThreshold: defaultFailureThreshold,
+		FailbackInterval: defaultFailbackInterval}, cfg)
+}
+
+func TestFailover_Publish(t *testing.T) {
+	f, p, s := newFailover(t, 2, time.Hour)
+
+	assert.NoError(t, f.PublishEvent("1", "a", nil))
+
+	p.down = true
+
+	assert.Equal(t, errBroker, f.PublishEvent("2", "b", nil), "the failures below the threshold are returned")
+	assert.NoError(t, f.PublishEvent("3", "c", nil), "the message which fails the pubsub over is published to the secondary")
+	assert.NoError(t, f.PublishEvent("4", "d", nil))
+
+	assert.Equal(t, []string{"1"}, p.published)
+	assert.Equal(t, []string{"3", "4"}, s.published)
+	assert.Equal(t, "eu-north", f.HealthCheck().Details.(map[string]interface{})["region"])
+}
+
+func TestFailover_Failback(t *testing.T) {
+	f, p, s := newFailover(t, 1, time.Millisecond)
+
+	p.down = true
+
+	assert.NoError(t, f.PublishEvent("1", "a", nil))
+
+	time.Sleep(2 * time.Millisecond)
+
+	assert.NoError(t, f.PublishEvent("2", "b", nil), "the primary is not used while its ping fails")
+
+	p.down = false
+
+	time.Sleep(2 * time.Millisecond)
+
+	assert.NoError(t, f.PublishEvent("3", "c", nil))
+
+	assert.Equal(t, []string{"3"}, p.published)
+	assert.Equal(t, []string{"1", "2"}, s.published)
+}
+
+func TestFailover_Subscribe(t *testing.T) {
+	f, p, s := newFailover(t, 1, time.Hour)
+
+	_, err := f.Subscribe()
+	assert.NoError(t, err)
+
+	f.CommitOffset(pubsub.TopicPartition{Offset: 1})
+
+	p.down = true
+
+	_, err = f.Subscribe()
+	assert.Equal(t, errBroker, err)
+
+	_, err = f.SubscribeWithCommit(nil)
+	assert.NoError(t, err, "the messages are consumed from the secondary after the failover")
+
+	f.CommitOffset(pubsub.TopicPartition{Offset: 2})
+
+	assert.Equal(t, []int64{1}, p.committed)
+	assert.Equal(t, []int64{2}, s.committed, "the offsets are committed to the region of the consumed message")
+}
+
+func TestFailover_PublishEvents(t *testing.T) {
+	f, p, s := newFailover(t, 1, time.Hour)
+
+	p.down = true
+
+	assert.NoError(t, f.PublishEvents("orders", []interface{}{"a", "b"}, nil))
+	assert.Equal(t, []string{"", ""}, s.published)
+	assert.True(t, f.IsSet())
+}
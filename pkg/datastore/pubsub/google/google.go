@@ -4,11 +4,15 @@ package google
 import (
 	"context"
 	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	gpubsub "cloud.google.com/go/pubsub"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
 	"gofr.dev/pkg"
 	"gofr.dev/pkg/datastore"
@@ -27,6 +31,19 @@ type Config struct {
 	TimeoutDuration     int
 	ConnRetryDuration   int
 	Subscription        *gpubsub.Subscription
+
+	// EmulatorHost is the host of the Pub/Sub emulator, ie: localhost:8085, to which the client connects without
+	// authentication, so that the tests do not need a project
+	EmulatorHost string
+	// EnableOrdering publishes the messages with their keys as the ordering keys, and creates the subscription with
+	// the message ordering, so that the messages of a key are delivered in the order in which they are published
+	EnableOrdering bool
+	// MaxOutstandingMessages and MaxOutstandingBytes are the flow control of the subscription, the messages beyond
+	// them are not received until the received messages are acknowledged. The defaults of the client are used when 0
+	MaxOutstandingMessages int
+	MaxOutstandingBytes    int
+	// NumGoroutines is the number of goroutines which pull the messages of the subscription
+	NumGoroutines int
 }
 
 // Subscription defines the name of the Pub/Sub subscription.
@@ -39,8 +56,27 @@ type GCPubSub struct {
 	config *Config
 	client *gpubsub.Client
 	logger log.Logger
+
+	// mu guards the receive of the subscription, which runs in the background once a message is subscribed, handing
+	// over the received messages one by one, and the messages returned by SubscribeWithCommit without being
+	// acknowledged, by the offsets they are returned with
+	mu        sync.Mutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	receiving bool
+	received  chan received
+	pending   map[int64]*gpubsub.Message
+	offset    int64
+}
+
+// received is a message received in the background, or the error by which the receive is stopped
+type received struct {
+	message *gpubsub.Message
+	err     error
 }
 
+const errClosed = errors.Error("google pubsub is closed")
+
 //nolint:gochecknoglobals // The declared global variable can be accessed across multiple functions
 var (
 	subscribeReceiveCount = prometheus.NewCounterVec(prometheus.CounterOpts{
@@ -87,7 +123,7 @@ func New(config *Config, logger log.Logger) (*GCPubSub, error) {
 
 	defer cancel()
 
-	client, err := gpubsub.NewClient(ctx, config.ProjectID)
+	client, err := gpubsub.NewClient(ctx, config.ProjectID, clientOptions(config)...)
 	if err != nil {
 		logger.Errorf("Google PubSub: Error creating new client - %v", err)
 		return &GCPubSub{client: client, config: config}, err
@@ -96,6 +132,8 @@ func New(config *Config, logger log.Logger) (*GCPubSub, error) {
 	logger.Debug("New client has been created successfully!")
 
 	config.Topic = client.Topic(config.TopicName)
+	config.Topic.EnableMessageOrdering = config.EnableOrdering
+
 	gcpPubsub := &GCPubSub{client: client, config: config, logger: logger}
 
 	err = createTopicsIfNotExist(ctx, gcpPubsub)
@@ -112,6 +150,21 @@ func New(config *Config, logger log.Logger) (*GCPubSub, error) {
 
 	return gcpPubsub, nil
 }
+
+// clientOptions connects the client to the emulator when it is configured, the client connects to the emulator of
+// PUBSUB_EMULATOR_HOST as well.
+func clientOptions(config *Config) []option.ClientOption {
+	if config.EmulatorHost == "" {
+		return nil
+	}
+
+	return []option.ClientOption{
+		option.WithEndpoint(config.EmulatorHost),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	}
+}
+
 func createTopicsIfNotExist(ctx context.Context, g *GCPubSub) error {
 	exist, err := g.config.Topic.Exists(ctx)
 	if err != nil {
@@ -148,25 +201,52 @@ func createSubscription(ctx context.Context, g *GCPubSub) error {
 
 	if !ok {
 		g.config.Subscription, err = g.client.CreateSubscription(ctx, g.config.SubscriptionDetails.Name, gpubsub.SubscriptionConfig{
-			Topic: g.config.Topic,
+			Topic:                 g.config.Topic,
+			EnableMessageOrdering: g.config.EnableOrdering,
 		})
-
-		return err
+		if err != nil {
+			return err
+		}
 	}
 
+	setReceiveSettings(g.config)
+
 	g.logger.Debug("Subscription created successfully")
 
 	return nil
 }
 
-// PublishEventWithOptions publishes message to google Pub/Sub. Ability to provide additional options described in PublishOptions struct
-func (g *GCPubSub) PublishEventWithOptions(_ string, value interface{}, headers map[string]string,
+// setReceiveSettings sets the flow control of the subscription, the settings which are not configured are left to the
+// defaults of the client
+func setReceiveSettings(config *Config) {
+	settings := &config.Subscription.ReceiveSettings
+
+	if config.MaxOutstandingMessages > 0 {
+		settings.MaxOutstandingMessages = config.MaxOutstandingMessages
+	}
+
+	if config.MaxOutstandingBytes > 0 {
+		settings.MaxOutstandingBytes = config.MaxOutstandingBytes
+	}
+
+	if config.NumGoroutines > 0 {
+		settings.NumGoroutines = config.NumGoroutines
+	}
+}
+
+// PublishEventWithOptions publishes message to google Pub/Sub. Ability to provide additional options described in PublishOptions struct.
+// The key is the ordering key of the message when the ordering is enabled.
+func (g *GCPubSub) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
 	options *pubsub.PublishOptions) (err error) {
 	if options == nil {
 		options = &pubsub.PublishOptions{}
 	}
 
-	topic := g.client.Topic(g.config.TopicName)
+	// the topic of the client is reused, as it batches the messages and orders them by their keys
+	topic := g.config.Topic
+	if topic == nil {
+		topic = g.client.Topic(g.config.TopicName)
+	}
 
 	if options.Timestamp.IsZero() {
 		options.Timestamp = time.Now()
@@ -185,6 +265,10 @@ func (g *GCPubSub) PublishEventWithOptions(_ string, value interface{}, headers
 		Attributes: headers,
 	}
 
+	if g.config.EnableOrdering {
+		msg.OrderingKey = key
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g.config.TimeoutDuration)*time.Second)
 
 	defer cancel()
@@ -193,6 +277,11 @@ func (g *GCPubSub) PublishEventWithOptions(_ string, value interface{}, headers
 
 	_, err = result.Get(ctx)
 	if err != nil {
+		// the messages of an ordering key are not published after a failure, until the key is resumed
+		if msg.OrderingKey != "" {
+			topic.ResumePublish(msg.OrderingKey)
+		}
+
 		publishFailureCount.WithLabelValues(options.Topic, "").Inc()
 		g.logger.Debug("Error while publishing the message: ", err)
 
@@ -211,102 +300,202 @@ func (g *GCPubSub) PublishEvent(key string, value interface{}, headers map[strin
 	return g.PublishEventWithOptions(key, value, headers, &pubsub.PublishOptions{Topic: g.config.TopicName, Timestamp: time.Now()})
 }
 
+// PublishEvents publishes the events one by one, as the events are not published in batches
+func (g *GCPubSub) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	return pubsub.PublishEach(g, topic, values, headers)
+}
+
 // Subscribe read messages from google Pub/Sub configured
 func (g *GCPubSub) Subscribe() (*pubsub.Message, error) {
-	subscribeReceiveCount.WithLabelValues(g.config.TopicName, "").Inc()
-
-	var res pubsub.Message
-
-	res.Topic = g.config.TopicName
-
-	ctx, cancel := context.WithCancel(context.Background())
-
-	handler := func(ctx context.Context, m *gpubsub.Message) {
-		defer cancel()
-		g.logger.Debug("Received message: ", string(m.Data))
-		res.Value = string(m.Data)
-		m.Ack() // Acknowledge that the message has been consumed
-	}
+	return g.SubscribeWithContext(context.Background())
+}
 
-	err := g.config.Subscription.Receive(ctx, handler)
+// SubscribeWithContext reads a message like Subscribe, till the context is done, the message is acknowledged only when it
+// is returned.
+func (g *GCPubSub) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
+	m, err := g.next(ctx)
 	if err != nil {
-		subscribeFailureCount.WithLabelValues(g.config.TopicName, "").Inc()
-		g.logger.Debug("Error while receiving message: ", err)
-
 		return nil, err
 	}
 
-	subscribeSuccessCount.WithLabelValues(g.config.TopicName, "").Inc()
+	m.Ack() // Acknowledge that the message has been consumed
 
-	g.logger.Debug("Message received successfully.")
-
-	return &res, nil
+	return g.toMessage(m), nil
 }
 
 /*
-SubscribeWithCommit calls the CommitFunc after subscribing message from googlePubSub and based on the return values decides
-whether to commit message and consume another message
+SubscribeWithCommit calls the CommitFunc for the messages of the subscription, the message is acknowledged when it is to
+be committed, else it is negatively acknowledged, so that it is delivered again. The messages are consumed until the
+CommitFunc returns false to continue. The message which is returned without being committed is acknowledged by
+CommitOffset, with the offset it is returned with.
 */
 func (g *GCPubSub) SubscribeWithCommit(commitFunc pubsub.CommitFunc) (*pubsub.Message, error) {
-	subscribeReceiveCount.WithLabelValues(g.config.TopicName, "").Inc()
+	for {
+		m, err := g.next(context.Background())
+		if err != nil {
+			return nil, err
+		}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+		message := g.toMessage(m)
 
-	var res pubsub.Message
-	res.Topic = g.config.TopicName
+		isCommit, isContinue := commitFunc(message)
 
-	handler := func(_ context.Context, m *gpubsub.Message) {
-		g.processMessage(m, &res, commitFunc, cancel)
+		switch {
+		case isCommit:
+			m.Ack()
+		case isContinue:
+			m.Nack()
+		default:
+			message.Offset = g.keep(m)
+		}
+
+		if !isContinue {
+			return message, nil
+		}
 	}
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return &res, nil // Context canceled or timed out
-		default:
-			if err := g.config.Subscription.Receive(ctx, handler); err != nil {
-				subscribeFailureCount.WithLabelValues(g.config.TopicName, "").Inc()
-				g.logger.Debug("Error while receiving message: ", err)
+// next returns the next message received in the background, it waits until a message is received, the context is done,
+// or the client is closed.
+func (g *GCPubSub) next(ctx context.Context) (*gpubsub.Message, error) {
+	subscribeReceiveCount.WithLabelValues(g.config.TopicName, "").Inc()
 
-				return nil, err
-			}
+	messages, receiveCtx := g.receive()
 
-			subscribeSuccessCount.WithLabelValues(g.config.TopicName, "").Inc()
-			g.logger.Debug("Message received successfully.")
+	select {
+	case r := <-messages:
+		if r.err != nil {
+			subscribeFailureCount.WithLabelValues(g.config.TopicName, "").Inc()
+			g.logger.Debug("Error while receiving message: ", r.err)
+
+			return nil, r.err
 		}
+
+		subscribeSuccessCount.WithLabelValues(g.config.TopicName, "").Inc()
+		g.logger.Debug("Received message: ", string(r.message.Data))
+
+		return r.message, nil
+	case <-ctx.Done():
+		subscribeFailureCount.WithLabelValues(g.config.TopicName, "").Inc()
+
+		return nil, ctx.Err()
+	case <-receiveCtx.Done():
+		return nil, errClosed
+	}
+}
+
+// receive starts the receive of the subscription in the background, unless it is running or the client is closed, and
+// returns the channel of the received messages, along with the context which is done once the client is closed.
+func (g *GCPubSub) receive() (<-chan received, context.Context) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.init()
+
+	if !g.receiving && g.ctx.Err() == nil {
+		g.receiving = true
+
+		go g.run(g.ctx)
 	}
+
+	return g.received, g.ctx
 }
 
-func (g *GCPubSub) processMessage(m *gpubsub.Message, res *pubsub.Message, commitFunc pubsub.CommitFunc,
-	cancelFunc context.CancelFunc) {
-	g.logger.Debug("Received message: ", string(m.Data))
-	res.Value = string(m.Data)
+// init creates the context of the receive, and its channel, once, g.mu is held by the caller.
+func (g *GCPubSub) init() {
+	if g.ctx == nil {
+		g.ctx, g.cancel = context.WithCancel(context.Background())
+		g.received = make(chan received)
+	}
+}
 
-	// Call the commit function
-	isCommit, isContinue := commitFunc(&pubsub.Message{
-		Topic: g.config.TopicName,
-		Value: string(m.Data),
+// run receives the messages of the subscription until the client is closed, or the receive fails. A message is handed
+// over to a subscribe before the next one is received, it is delivered again when the receive is stopped before.
+func (g *GCPubSub) run(ctx context.Context) {
+	err := g.config.Subscription.Receive(ctx, func(handlerCtx context.Context, m *gpubsub.Message) {
+		select {
+		case g.received <- received{message: m}:
+		case <-handlerCtx.Done():
+			m.Nack()
+		}
 	})
 
-	if isCommit {
-		m.Ack() // Acknowledge that the message has been consumed
+	g.mu.Lock()
+	g.receiving = false
+	g.mu.Unlock()
+
+	if err == nil {
+		return
+	}
+
+	// the receive is started again by the next subscribe
+	select {
+	case g.received <- received{err: err}:
+	case <-ctx.Done():
 	}
+}
+
+// keep keeps the message returned without being acknowledged, until it is acknowledged by CommitOffset, it returns the
+// offset the message is kept by, as the messages of Pub/Sub do not have offsets.
+func (g *GCPubSub) keep(m *gpubsub.Message) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	if !isContinue {
-		cancelFunc()
+	if g.pending == nil {
+		g.pending = make(map[int64]*gpubsub.Message)
 	}
+
+	g.offset++
+	g.pending[g.offset] = m
+
+	return g.offset
+}
+
+func (g *GCPubSub) toMessage(m *gpubsub.Message) *pubsub.Message {
+	return &pubsub.Message{Topic: g.config.TopicName, Key: m.OrderingKey, Value: string(m.Data), Headers: m.Attributes}
 }
 
 func (g *GCPubSub) Bind(message []byte, target interface{}) error {
 	return json.Unmarshal(message, &target)
 }
 
-// CommitOffset In Google Cloud Pub/Sub, there is no direct equivalent to committing offsets as in Apache Kafka.
-// Pub/Sub is a fully-managed service where the acknowledgment of messages is handled automatically.
-// In Pub/Sub, once a message is received and processed by a subscriber, it is automatically acknowledged by the service.
-// There is no need to explicitly commit offsets or track offsets manually like in Kafka.
-func (g *GCPubSub) CommitOffset(pubsub.TopicPartition) {}
+// CommitOffset acknowledges the message returned by SubscribeWithCommit without being committed, by the offset it is
+// returned with. In Google Cloud Pub/Sub, there is no direct equivalent to committing offsets as in Apache Kafka, the
+// messages are acknowledged one by one.
+func (g *GCPubSub) CommitOffset(offsets pubsub.TopicPartition) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	m, ok := g.pending[offsets.Offset]
+	delete(g.pending, offsets.Offset)
+	g.mu.Unlock()
+
+	if ok {
+		m.Ack()
+	}
+}
+
+// Close stops the receive of the subscription, the messages which are not acknowledged are delivered again.
+func (g *GCPubSub) Close() error {
+	if g == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.init()
+	g.cancel()
+
+	for offset, m := range g.pending {
+		m.Nack()
+		delete(g.pending, offset)
+	}
+
+	return nil
+}
 
 // Ping checks for the health of google Pub/Sub, returns an error if it is down
 func (g *GCPubSub) Ping() error {
@@ -383,3 +383,32 @@ func initializeTest(t *testing.T) *GCPubSub {
 
 	return &GCPubSub{config: pubsubCfg, client: client, logger: logger}
 }
+
+func Test_clientOptions(t *testing.T) {
+	assert.Empty(t, clientOptions(&Config{}), "client must connect to Pub/Sub when the emulator is not configured")
+	assert.Len(t, clientOptions(&Config{EmulatorHost: "localhost:8085"}), 3)
+}
+
+func Test_setReceiveSettings(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   *Config
+		expected gpubsub.ReceiveSettings
+	}{
+		{"flow control is configured", &Config{MaxOutstandingMessages: 10, MaxOutstandingBytes: 1024, NumGoroutines: 2},
+			gpubsub.ReceiveSettings{MaxOutstandingMessages: 10, MaxOutstandingBytes: 1024, NumGoroutines: 2}},
+		{"flow control is not configured", &Config{}, gpubsub.DefaultReceiveSettings},
+	}
+
+	for i, tc := range testCases {
+		tc.config.Subscription = &gpubsub.Subscription{ReceiveSettings: gpubsub.DefaultReceiveSettings}
+
+		setReceiveSettings(tc.config)
+
+		settings := tc.config.Subscription.ReceiveSettings
+
+		assert.Equal(t, tc.expected.MaxOutstandingMessages, settings.MaxOutstandingMessages, "Test [%d] Failed: %v", i+1, tc.desc)
+		assert.Equal(t, tc.expected.MaxOutstandingBytes, settings.MaxOutstandingBytes, "Test [%d] Failed: %v", i+1, tc.desc)
+		assert.Equal(t, tc.expected.NumGoroutines, settings.NumGoroutines, "Test [%d] Failed: %v", i+1, tc.desc)
+	}
+}
@@ -0,0 +1,259 @@
+// Package inprocess provides a channel based implementation of pubsub.PublisherSubscriber, so that the modules of
+// a single application can exchange events without a broker and move to Kafka later by changing the configuration.
+package inprocess
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+const (
+	defaultBufferSize = 1000
+
+	errBufferFull = errors.Error("in-process pubsub buffer is full")
+	errClosed     = errors.Error("in-process pubsub is closed")
+	errNoTopic    = errors.Error("in-process pubsub requires at least one topic")
+)
+
+// Config stores the topics which are consumed by Subscribe and the number of messages which can be pending.
+// The first topic is used when a message is published without a topic.
+type Config struct {
+	Topics     []string
+	BufferSize int
+}
+
+// Bus is an in-process publisher subscriber, messages are held in memory and are lost when the application stops.
+type Bus struct {
+	config  *Config
+	topics  map[string]bool
+	queue   chan *pubsub.Message
+	done    chan struct{}
+	mu      sync.Mutex
+	offsets map[string]int64
+	closed  bool
+}
+
+// New returns a Bus for the given configuration
+func New(config *Config) (*Bus, error) {
+	topics := make(map[string]bool, len(config.Topics))
+	names := make([]string, 0, len(config.Topics))
+
+	for _, t := range config.Topics {
+		if t = strings.TrimSpace(t); t != "" && !topics[t] {
+			topics[t] = true
+			names = append(names, t)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, errNoTopic
+	}
+
+	config.Topics = names
+
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultBufferSize
+	}
+
+	pubsub.RegisterMetrics()
+
+	return &Bus{
+		config:  config,
+		topics:  topics,
+		queue:   make(chan *pubsub.Message, config.BufferSize),
+		done:    make(chan struct{}),
+		offsets: make(map[string]int64),
+	}, nil
+}
+
+// PublishEventWithOptions publishes the message to the topic in options, messages of topics which are not
+// consumed by the application are discarded. An error is returned when the buffer is full.
+func (b *Bus) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) (err error) {
+	if options == nil {
+		options = &pubsub.PublishOptions{}
+	}
+
+	if options.Topic == "" {
+		options.Topic = b.config.Topics[0]
+	}
+
+	pubsub.PublishTotalCount(options.Topic, "")
+
+	valBytes, ok := value.([]byte)
+	if !ok {
+		valBytes, err = json.Marshal(value)
+		if err != nil {
+			pubsub.PublishFailureCount(options.Topic, "")
+
+			return err
+		}
+	}
+
+	msgHeaders := make(map[string]string, len(headers))
+	for k, v := range headers {
+		msgHeaders[k] = v
+	}
+
+	if err = b.enqueue(&pubsub.Message{
+		Topic:     options.Topic,
+		Partition: options.Partition,
+		Key:       key,
+		Value:     string(valBytes),
+		Headers:   msgHeaders,
+	}); err != nil {
+		pubsub.PublishFailureCount(options.Topic, "")
+
+		return err
+	}
+
+	pubsub.PublishSuccessCount(options.Topic, "")
+
+	return nil
+}
+
+// enqueue assigns the offset and adds the message to the queue, both under the lock so that the offsets
+// of a topic are consumed in order.
+func (b *Bus) enqueue(msg *pubsub.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return errClosed
+	}
+
+	if !b.topics[msg.Topic] {
+		return nil
+	}
+
+	msg.Offset = b.offsets[msg.Topic]
+
+	select {
+	case b.queue <- msg:
+		b.offsets[msg.Topic]++
+
+		return nil
+	default:
+		return errBufferFull
+	}
+}
+
+// PublishEvent publishes the message to the first configured topic
+func (b *Bus) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return b.PublishEventWithOptions(key, value, headers, &pubsub.PublishOptions{
+		Topic:     b.config.Topics[0],
+		Timestamp: time.Now(),
+	})
+}
+
+// PublishEvents publishes the messages one by one
+func (b *Bus) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	return pubsub.PublishEach(b, topic, values, headers)
+}
+
+// Subscribe waits for the next message of the configured topics, it returns an error once the bus is closed
+func (b *Bus) Subscribe() (*pubsub.Message, error) {
+	return b.SubscribeWithContext(context.Background())
+}
+
+// SubscribeWithContext reads a message like Subscribe, till the context is done
+func (b *Bus) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
+	select {
+	case msg := <-b.queue:
+		pubsub.SubscribeReceiveCount(msg.Topic, "")
+		pubsub.SubscribeSuccessCount(msg.Topic, "")
+
+		return msg, nil
+	case <-b.done:
+		pubsub.SubscribeFailureCount(strings.Join(b.config.Topics, ","), "")
+
+		return nil, errClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+/*
+SubscribeWithCommit calls the CommitFunc for every message till it asks not to consume the next message,
+there is nothing to commit as the messages are removed from the buffer once they are read.
+*/
+func (b *Bus) SubscribeWithCommit(f pubsub.CommitFunc) (*pubsub.Message, error) {
+	for {
+		msg, err := b.Subscribe()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, isContinue := f(msg); !isContinue {
+			return msg, nil
+		}
+	}
+}
+
+// Bind parses the message into target
+func (b *Bus) Bind(message []byte, target interface{}) error {
+	return json.Unmarshal(message, target)
+}
+
+// CommitOffset is a no-op, a message is acknowledged when it is read
+func (b *Bus) CommitOffset(pubsub.TopicPartition) {}
+
+// Ping returns an error once the bus is closed
+func (b *Bus) Ping() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return errClosed
+	}
+
+	return nil
+}
+
+// HealthCheck returns the health of the bus
+func (b *Bus) HealthCheck() types.Health {
+	if !b.IsSet() {
+		return types.Health{Name: datastore.InProcess, Status: pkg.StatusDown}
+	}
+
+	resp := types.Health{
+		Name:   datastore.InProcess,
+		Status: pkg.StatusDown,
+		Host:   strings.Join(b.config.Topics, ","),
+	}
+
+	if b.Ping() != nil {
+		return resp
+	}
+
+	resp.Status = pkg.StatusUp
+
+	return resp
+}
+
+// IsSet checks whether the bus is initialized or not
+func (b *Bus) IsSet() bool {
+	return b != nil && b.queue != nil
+}
+
+// Close stops the bus, pending messages are discarded and Subscribe returns an error
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.closed {
+		b.closed = true
+		close(b.done)
+	}
+
+	return nil
+}
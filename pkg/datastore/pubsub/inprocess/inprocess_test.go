@@ -0,0 +1,159 @@
+package inprocess
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/gofr/types"
+)
+
+func TestNew(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		topics []string
+		exp    []string
+		err    error
+	}{
+		{"topics are trimmed", []string{" orders", "payments ", "orders"}, []string{"orders", "payments"}, nil},
+		{"no topic", []string{" ", ""}, nil, errNoTopic},
+	}
+
+	for i, tc := range testcases {
+		bus, err := New(&Config{Topics: tc.topics})
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if err == nil {
+			assert.Equal(t, tc.exp, bus.config.Topics, "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, defaultBufferSize, bus.config.BufferSize, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus, _ := New(&Config{Topics: []string{"orders", "payments"}})
+	headers := map[string]string{"tenant": "acme"}
+
+	assert.NoError(t, bus.PublishEvent("o-1", map[string]int{"id": 1}, headers))
+	assert.NoError(t, bus.PublishEventWithOptions("p-1", []byte("paid"), nil, &pubsub.PublishOptions{Topic: "payments"}))
+	assert.NoError(t, bus.PublishEventWithOptions("", "ignored", nil, &pubsub.PublishOptions{Topic: "shipments"}))
+	assert.NoError(t, bus.PublishEvent("o-2", map[string]int{"id": 2}, nil))
+
+	headers["tenant"] = "changed"
+
+	exp := []pubsub.Message{
+		{Topic: "orders", Key: "o-1", Value: `{"id":1}`, Offset: 0, Headers: map[string]string{"tenant": "acme"}},
+		{Topic: "payments", Key: "p-1", Value: "paid", Offset: 0, Headers: map[string]string{}},
+		{Topic: "orders", Key: "o-2", Value: `{"id":2}`, Offset: 1, Headers: map[string]string{}},
+	}
+
+	for i := range exp {
+		msg, err := bus.Subscribe()
+
+		assert.NoError(t, err, "TEST[%d], failed.\n", i)
+		assert.Equal(t, &exp[i], msg, "TEST[%d], failed.\n", i)
+	}
+}
+
+func TestBus_PublishErrors(t *testing.T) {
+	bus, _ := New(&Config{Topics: []string{"orders"}, BufferSize: 1})
+
+	assert.NoError(t, bus.PublishEvent("", "first", nil))
+	assert.Equal(t, errBufferFull, bus.PublishEvent("", "second", nil))
+	assert.Error(t, bus.PublishEvent("", make(chan int), nil), "unsupported values are not published")
+
+	_ = bus.Close()
+
+	assert.Equal(t, errClosed, bus.PublishEvent("", "third", nil))
+
+	_, err := bus.Subscribe()
+	assert.Equal(t, errClosed, err)
+}
+
+func TestBus_PublishEvents(t *testing.T) {
+	bus, _ := New(&Config{Topics: []string{"orders"}, BufferSize: 2})
+
+	err := bus.PublishEvents("", []interface{}{"a", "b", "c"}, nil)
+
+	errs, ok := err.(pubsub.PublishErrors) //nolint:errorlint // the batch error is returned as is
+	assert.True(t, ok, "expected PublishErrors, got %v", err)
+	assert.Equal(t, pubsub.PublishErrors{{Index: 2, Err: errBufferFull}}, errs)
+}
+
+func TestBus_SubscribeWithCommit(t *testing.T) {
+	bus, _ := New(&Config{Topics: []string{"orders"}})
+
+	for _, v := range []string{"a", "b", "c"} {
+		_ = bus.PublishEvent("", v, nil)
+	}
+
+	var count int
+
+	msg, err := bus.SubscribeWithCommit(func(m *pubsub.Message) (bool, bool) {
+		count++
+		return true, count < 2
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"b"`, msg.Value)
+	assert.Equal(t, 2, count)
+}
+
+func TestBus_Bind(t *testing.T) {
+	bus, _ := New(&Config{Topics: []string{"orders"}})
+
+	var order struct {
+		ID int `json:"id"`
+	}
+
+	assert.NoError(t, bus.Bind([]byte(`{"id":7}`), &order))
+	assert.Equal(t, 7, order.ID)
+}
+
+func TestBus_HealthCheck(t *testing.T) {
+	var nilBus *Bus
+
+	bus, _ := New(&Config{Topics: []string{"orders", "payments"}})
+
+	up := bus.HealthCheck()
+
+	_ = bus.Close()
+
+	testcases := []struct {
+		desc string
+		resp types.Health
+		exp  types.Health
+	}{
+		{"open bus", up, types.Health{Name: datastore.InProcess, Status: pkg.StatusUp, Host: "orders,payments"}},
+		{"closed bus", bus.HealthCheck(), types.Health{Name: datastore.InProcess, Status: pkg.StatusDown, Host: "orders,payments"}},
+		{"nil bus", nilBus.HealthCheck(), types.Health{Name: datastore.InProcess, Status: pkg.StatusDown}},
+	}
+
+	for i, tc := range testcases {
+		assert.Equal(t, tc.exp, tc.resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestBus_SubscribeWithContext(t *testing.T) {
+	bus, _ := New(&Config{Topics: []string{"orders"}})
+
+	deadline, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := bus.SubscribeWithContext(deadline)
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	_ = bus.PublishEvent("1", "placed", nil)
+
+	msg, err := bus.SubscribeWithContext(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"placed"`, msg.Value)
+}
@@ -0,0 +1,197 @@
+package pubsub
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// PublishFunc publishes a message, like PublisherSubscriber.PublishEventWithOptions.
+type PublishFunc func(key string, value interface{}, headers map[string]string, options *PublishOptions) error
+
+// SubscribeFunc consumes a message, like PublisherSubscriber.Subscribe.
+type SubscribeFunc func() (*Message, error)
+
+/*
+Interceptor wraps the publishes and the subscribes of a pubsub, the same way a middleware wraps the HTTP handlers, ex:
+to propagate the tracing headers, to encrypt the payloads, or to log and measure the messages. Either of the functions
+can be nil, when the interceptor wraps only the publishes or the subscribes.
+
+The headers passed to a publish interceptor are a copy of the headers of the message, which can be changed.
+*/
+type Interceptor struct {
+	Publish   func(next PublishFunc) PublishFunc
+	Subscribe func(next SubscribeFunc) SubscribeFunc
+}
+
+// Intercepted is a pubsub whose publishes and subscribes are wrapped by the interceptors, the other methods are of the
+// pubsub it wraps.
+type Intercepted struct {
+	PublisherSubscriber
+
+	interceptors []Interceptor
+	publish      PublishFunc
+	subscribe    SubscribeFunc
+}
+
+/*
+Intercept returns the pubsub with its publishes and subscribes wrapped by the interceptors, the first interceptor is
+the outermost, ie: it is the first to see a published message, and the last to see a consumed message. The interceptors
+are appended to the interceptors of a pubsub which is already intercepted.
+*/
+func Intercept(ps PublisherSubscriber, interceptors ...Interceptor) *Intercepted {
+	if i, ok := ps.(*Intercepted); ok {
+		ps = i.PublisherSubscriber
+		interceptors = append(append([]Interceptor(nil), i.interceptors...), interceptors...)
+	}
+
+	i := &Intercepted{PublisherSubscriber: ps, interceptors: interceptors}
+
+	i.publish = ps.PublishEventWithOptions
+
+	for j := len(interceptors) - 1; j >= 0; j-- {
+		if interceptors[j].Publish != nil {
+			i.publish = interceptors[j].Publish(i.publish)
+		}
+	}
+
+	i.subscribe = i.chain(ps.Subscribe)
+
+	return i
+}
+
+// chain wraps the subscribe in the subscribe interceptors.
+func (i *Intercepted) chain(subscribe SubscribeFunc) SubscribeFunc {
+	for j := len(i.interceptors) - 1; j >= 0; j-- {
+		if i.interceptors[j].Subscribe != nil {
+			subscribe = i.interceptors[j].Subscribe(subscribe)
+		}
+	}
+
+	return subscribe
+}
+
+// PublishEventWithOptions publishes the message through the publish interceptors
+func (i *Intercepted) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
+	options *PublishOptions) error {
+	h := make(map[string]string, len(headers))
+	for k, v := range headers {
+		h[k] = v
+	}
+
+	return i.publish(key, value, h, options)
+}
+
+// PublishEvent publishes the message through the publish interceptors
+func (i *Intercepted) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return i.PublishEventWithOptions(key, value, headers, nil)
+}
+
+// PublishEvents publishes the messages one by one through the publish interceptors, or in a batch when there are no
+// publish interceptors.
+func (i *Intercepted) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	for _, interceptor := range i.interceptors {
+		if interceptor.Publish != nil {
+			return PublishEach(i, topic, values, headers)
+		}
+	}
+
+	return i.PublisherSubscriber.PublishEvents(topic, values, headers)
+}
+
+// Subscribe consumes the next message through the subscribe interceptors
+func (i *Intercepted) Subscribe() (*Message, error) {
+	return i.subscribe()
+}
+
+// SubscribeWithContext consumes the next message through the subscribe interceptors, till the context is done
+func (i *Intercepted) SubscribeWithContext(ctx context.Context) (*Message, error) {
+	return i.chain(func() (*Message, error) { return i.PublisherSubscriber.SubscribeWithContext(ctx) })()
+}
+
+/*
+SubscribeWithCommit consumes the messages through the subscribe interceptors, the CommitFunc is called with the message
+returned by the interceptors. A message which fails an interceptor is neither committed, nor passed to the CommitFunc,
+and its error is returned.
+*/
+func (i *Intercepted) SubscribeWithCommit(f CommitFunc) (*Message, error) {
+	var (
+		msg *Message
+		err error
+	)
+
+	_, subErr := i.PublisherSubscriber.SubscribeWithCommit(func(consumed *Message) (bool, bool) {
+		msg, err = i.chain(func() (*Message, error) { return consumed, nil })()
+		if err != nil || f == nil {
+			return false, false
+		}
+
+		return f(msg)
+	})
+	if subErr != nil {
+		return nil, subErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// Close closes the pubsub it wraps, when it can be closed
+func (i *Intercepted) Close() error {
+	if c, ok := i.PublisherSubscriber.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// PublishHeaders returns the interceptor which sets the headers returned by fn on the published messages, ex: the
+// tracing headers, the headers of the message take precedence.
+func PublishHeaders(fn func() map[string]string) Interceptor {
+	return Interceptor{Publish: func(next PublishFunc) PublishFunc {
+		return func(key string, value interface{}, headers map[string]string, options *PublishOptions) error {
+			for k, v := range fn() {
+				if _, ok := headers[k]; !ok {
+					headers[k] = v
+				}
+			}
+
+			return next(key, value, headers, options)
+		}
+	}}
+}
+
+// Observe returns the interceptor which calls fn after every publish and subscribe, with its duration and error, ex: to
+// log or measure the messages. The message is nil for a failed subscribe.
+func Observe(fn func(op string, msg *Message, duration time.Duration, err error)) Interceptor {
+	return Interceptor{
+		Publish: func(next PublishFunc) PublishFunc {
+			return func(key string, value interface{}, headers map[string]string, options *PublishOptions) error {
+				start := time.Now()
+				err := next(key, value, headers, options)
+
+				msg := &Message{Key: key, Headers: headers}
+				if options != nil {
+					msg.Topic = options.Topic
+				}
+
+				fn("publish", msg, time.Since(start), err)
+
+				return err
+			}
+		},
+		Subscribe: func(next SubscribeFunc) SubscribeFunc {
+			return func() (*Message, error) {
+				start := time.Now()
+				msg, err := next()
+
+				fn("subscribe", msg, time.Since(start), err)
+
+				return msg, err
+			}
+		},
+	}
+}
@@ -0,0 +1,155 @@
+package pubsub
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+)
+
+// mockBroker records the messages published to it, and returns the messages queued on it when they are consumed.
+type mockBroker struct {
+	mockPublisher
+	headers   []map[string]string
+	queue     []*Message
+	committed []int64
+}
+
+func (m *mockBroker) PublishEventWithOptions(key string, value interface{}, headers map[string]string, options *PublishOptions) error {
+	m.headers = append(m.headers, headers)
+
+	if options == nil {
+		options = &PublishOptions{}
+	}
+
+	return m.mockPublisher.PublishEventWithOptions(key, value, headers, options)
+}
+
+func (m *mockBroker) Subscribe() (*Message, error) {
+	msg := m.queue[0]
+	m.queue = m.queue[1:]
+
+	return msg, nil
+}
+
+func (m *mockBroker) SubscribeWithCommit(f CommitFunc) (*Message, error) {
+	for {
+		msg, _ := m.Subscribe()
+
+		isCommit, isContinue := f(msg)
+		if isCommit {
+			m.committed = append(m.committed, msg.Offset)
+		}
+
+		if !isContinue {
+			return msg, nil
+		}
+	}
+}
+
+// tag returns the interceptor which appends its name to the values published and consumed, to record its order.
+func tag(name string) Interceptor {
+	return Interceptor{
+		Publish: func(next PublishFunc) PublishFunc {
+			return func(key string, value interface{}, headers map[string]string, options *PublishOptions) error {
+				return next(key, value.(string)+">"+name, headers, options)
+			}
+		},
+		Subscribe: func(next SubscribeFunc) SubscribeFunc {
+			return func() (*Message, error) {
+				msg, err := next()
+				if err == nil {
+					msg.Value += ">" + name
+				}
+
+				return msg, err
+			}
+		},
+	}
+}
+
+func TestIntercept(t *testing.T) {
+	broker := &mockBroker{queue: []*Message{{Value: "a"}, {Value: "b"}}}
+	headers := map[string]string{"X-Correlation-ID": "abc"}
+
+	ps := Intercept(Intercept(broker, tag("outer")), tag("inner"),
+		PublishHeaders(func() map[string]string { return map[string]string{"traceparent": "00-1", "X-Correlation-ID": "x"} }))
+
+	assert.NoError(t, ps.PublishEventWithOptions("1", "v", headers, &PublishOptions{Topic: "orders"}))
+	assert.NoError(t, ps.PublishEvent("2", "w", nil))
+
+	assert.Equal(t, []interface{}{"v>outer>inner", "w>outer>inner"}, broker.values)
+	assert.Equal(t, map[string]string{"X-Correlation-ID": "abc", "traceparent": "00-1"}, broker.headers[0])
+	assert.Equal(t, map[string]string{"X-Correlation-ID": "abc"}, headers, "the headers of the caller are not changed")
+
+	msg, err := ps.Subscribe()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a>inner>outer", msg.Value)
+}
+
+func TestIntercepted_PublishEvents(t *testing.T) {
+	broker := &mockBroker{}
+
+	assert.NoError(t, Intercept(broker, Interceptor{}).PublishEvents("orders", []interface{}{"a", "b"}, nil))
+	assert.Empty(t, broker.topics, "the batch is published at once, when there are no publish interceptors")
+
+	broker = &mockBroker{}
+
+	assert.NoError(t, Intercept(broker, tag("t")).PublishEvents("orders", []interface{}{"a", "b"}, nil))
+	assert.Equal(t, []string{"orders", "orders"}, broker.topics, "the values of the batch are published one by one")
+	assert.Equal(t, []interface{}{"a>t", "b>t"}, broker.values)
+}
+
+func TestIntercepted_SubscribeWithCommit(t *testing.T) {
+	errInvalid := errors.Error("invalid signature")
+	broker := &mockBroker{queue: []*Message{{Offset: 1, Value: "a"}, {Offset: 2, Value: "b"}, {Offset: 3, Value: "forged"}}}
+
+	ps := Intercept(broker, tag("t"), Interceptor{Subscribe: func(next SubscribeFunc) SubscribeFunc {
+		return func() (*Message, error) {
+			msg, err := next()
+			if err == nil && strings.HasPrefix(msg.Value, "forged") {
+				return nil, errInvalid
+			}
+
+			return msg, err
+		}
+	}})
+
+	var values []string
+
+	msg, err := ps.SubscribeWithCommit(func(msg *Message) (bool, bool) {
+		values = append(values, msg.Value)
+		return true, msg.Offset < 2
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "b>t", msg.Value)
+	assert.Equal(t, []string{"a>t", "b>t"}, values)
+
+	_, err = ps.SubscribeWithCommit(func(*Message) (bool, bool) { return true, true })
+
+	assert.Equal(t, errInvalid, err)
+	assert.Equal(t, []int64{1, 2}, broker.committed, "the message which fails an interceptor is not committed")
+}
+
+func TestObserve(t *testing.T) {
+	broker := &mockBroker{queue: []*Message{{Topic: "orders", Value: "a"}}}
+
+	var ops []string
+
+	ps := Intercept(broker, Observe(func(op string, msg *Message, _ time.Duration, err error) {
+		ops = append(ops, op+":"+msg.Topic)
+
+		assert.NoError(t, err)
+	}))
+
+	_ = ps.PublishEventWithOptions("1", "v", nil, &PublishOptions{Topic: "payments"})
+	_, _ = ps.Subscribe()
+
+	assert.Equal(t, []string{"publish:payments", "subscribe:orders"}, ops)
+	assert.NoError(t, ps.Close())
+}
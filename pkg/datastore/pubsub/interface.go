@@ -6,6 +6,7 @@ azure eventhub etc.
 package pubsub
 
 import (
+	"context"
 	"time"
 
 	eventhub "github.com/Azure/azure-event-hubs-go/v3"
@@ -72,6 +73,13 @@ type PublisherSubscriber interface {
 	*/
 	PublishEvent(string, interface{}, map[string]string) error
 
+	/*
+		PublishEvents publishes the messages to the topic in a batch, the topic is read from config when it is empty.
+
+			returns PublishErrors with the errors of the messages which are not published
+	*/
+	PublishEvents(topic string, values []interface{}, headers map[string]string) error
+
 	/*
 		Subscribe read messages from the pubsub(kafka) configured.
 
@@ -85,6 +93,16 @@ type PublisherSubscriber interface {
 	*/
 	Subscribe() (*Message, error)
 
+	/*
+		SubscribeWithContext reads a message like Subscribe, till the context is done.
+
+			returns the error of the context when it is done before a message is read, ex: context.Canceled when the
+			application is stopped, or context.DeadlineExceeded when the deadline of the context is passed
+			the pending read is stopped when the context is done, and a message is committed only when it is returned,
+			so that a message is not lost for a consume which is cancelled
+	*/
+	SubscribeWithContext(ctx context.Context) (*Message, error)
+
 	/*
 			SubscribeWithCommit read messages from the pubsub(kafka) configured.
 
@@ -124,3 +142,21 @@ type PublisherSubscriberV2 interface {
 	// resume will be used to resume all the consumer groups in kafka/sarama
 	Resume() error
 }
+
+// Publisher publishes the messages, ex: the messages of a transaction
+type Publisher interface {
+	PublishEventWithOptions(key string, value interface{}, headers map[string]string, options *PublishOptions) error
+	PublishEvent(key string, value interface{}, headers map[string]string) error
+}
+
+// Transactional is implemented by the pubsubs which can publish the messages and commit the offset of a consumed
+// message atomically, ex: kafka with a transactional producer
+type Transactional interface {
+	/*
+		Transaction publishes the messages published by fn, and commits the offset of the consumed message, in a transaction.
+
+			the consumed message can be nil, when the messages are only published
+			the transaction is aborted when fn returns an error, and the error is returned
+	*/
+	Transaction(consumed *Message, fn func(p Publisher) error) error
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"net"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -29,8 +30,11 @@ import (
 const (
 	errConsumeMsg       = errors.Error("error while consuming the message")
 	SASLTypeSCRAMSHA512 = "SCRAM-SHA-512"
+	SASLTypeOAuthBearer = "OAUTHBEARER"
 	PLAIN               = "PLAIN"
 	errInvalidMechanism = errors.Error("Invalid SASL Mechanism")
+
+	errMissingTokenProvider = errors.Error("SASL mechanism OAUTHBEARER requires a token provider or a token URL")
 )
 
 // Kafka is a client for interacting with Apache Kafka.
@@ -39,6 +43,14 @@ type Kafka struct {
 	logger   log.Logger
 	Producer sarama.SyncProducer
 	Consumer *Consumer
+
+	// txMu serializes the transactions, as a producer can only have one transaction at a time
+	txMu sync.Mutex
+
+	// lagClient and lagAdmin read the offsets of the partitions and of the consumer group, for the consumer lag
+	lagMu     sync.Mutex
+	lagClient offsetClient
+	lagAdmin  groupOffsets
 }
 
 // AvroWithKafkaConfig represents a configuration for using Avro with Kafka
@@ -105,6 +117,15 @@ type Config struct {
 
 	// This config will allow application to disable kafka consumer auto commit
 	DisableAutoCommit bool
+
+	// Idempotent makes the producer write a message exactly once to a partition, even when the sends are retried
+	Idempotent bool
+
+	// TransactionalID makes the producer transactional, and must be unique for every instance of the application.
+	// The messages are published in transactions, and the offsets of the consumed messages are only committed
+	// by the transactions, ex: Kafka.Transaction, so that the consume-transform-produce pipelines do not duplicate
+	// the messages. The consumer only reads the messages of the committed transactions.
+	TransactionalID string
 }
 
 // SASLConfig holds SASL authentication configurations for Kafka.
@@ -123,6 +144,13 @@ type SASLConfig struct {
 
 	// SSLVerify set it to true if certificate verification is required
 	SSLVerify bool
+
+	// TokenProvider provides the tokens of the mechanism OAUTHBEARER, the tokens are fetched using the client
+	// credentials of OAuth when it is nil.
+	TokenProvider TokenProvider
+
+	// OAuth is the client credentials used to fetch the tokens of the mechanism OAUTHBEARER
+	OAuth OAuthConfig
 }
 
 // NewKafkaFromEnv fetches the config from environment variables and tries to connect to Kafka
@@ -179,6 +207,10 @@ func New(config *Config, logger log.Logger) (*Kafka, error) {
 		return nil, errInvalidMechanism
 	}
 
+	if config.SASL.Mechanism == SASLTypeOAuthBearer && config.SASL.tokenProvider() == nil {
+		return nil, errMissingTokenProvider
+	}
+
 	populateOffsetTopic(config)
 	convertKafkaConfig(config)
 
@@ -287,6 +319,8 @@ func convertKafkaConfig(config *Config) {
 		config.Config.Producer.Retry.Backoff = time.Duration(config.RetryFrequency)
 	}
 
+	processIdempotenceConfigs(config)
+
 	// for logging purpose only
 	if config.Config.ClientID == "" {
 		config.Config.ClientID = "gofr-kafka-log"
@@ -339,6 +373,11 @@ func setDefaultConfig(config *sarama.Config) {
 }
 
 func processSASLConfigs(s SASLConfig, conf *sarama.Config) {
+	if s.Mechanism == SASLTypeOAuthBearer {
+		processOAuthBearerConfigs(s, conf)
+		return
+	}
+
 	if s.User != "" && s.Password != "" {
 		conf.Net.SASL.User = s.User
 		conf.Net.SASL.Password = s.Password
@@ -361,6 +400,24 @@ func processSASLConfigs(s SASLConfig, conf *sarama.Config) {
 	}
 }
 
+// processOAuthBearerConfigs authenticates using the tokens of the provider, the tokens are refreshed by the provider
+// before they expire, so that the connections made after the expiry are authenticated.
+func processOAuthBearerConfigs(s SASLConfig, conf *sarama.Config) {
+	provider := s.tokenProvider()
+	if provider == nil {
+		return
+	}
+
+	conf.Net.SASL.Enable = true
+	conf.Net.SASL.Handshake = true
+	conf.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+	conf.Net.SASL.TokenProvider = &accessTokenProvider{provider: provider}
+	conf.Net.TLS.Enable = true
+	conf.Net.TLS.Config = &tls.Config{
+		InsecureSkipVerify: !s.SSLVerify, //nolint:gosec // the certificates are verified when SSLVerify is set.
+	}
+}
+
 // PublishEvent publishes the event to kafka
 func (k *Kafka) PublishEvent(key string, value interface{}, headers map[string]string) (err error) {
 	return k.PublishEventWithOptions(key, value, headers, &pubsub.PublishOptions{
@@ -370,8 +427,19 @@ func (k *Kafka) PublishEvent(key string, value interface{}, headers map[string]s
 }
 
 // PublishEventWithOptions publishes message to kafka. Ability to provide additional options described in PublishOptions struct
+// The message is published in a transaction of its own, when the producer is transactional.
 func (k *Kafka) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
 	options *pubsub.PublishOptions) (err error) {
+	if k.transactional() {
+		return k.Transaction(nil, func(p pubsub.Publisher) error {
+			return p.PublishEventWithOptions(key, value, headers, options)
+		})
+	}
+
+	return k.publish(key, value, headers, options)
+}
+
+func (k *Kafka) publish(key string, value interface{}, headers map[string]string, options *pubsub.PublishOptions) (err error) {
 	if options == nil {
 		options = &pubsub.PublishOptions{}
 	}
@@ -423,6 +491,89 @@ func (k *Kafka) PublishEventWithOptions(key string, value interface{}, headers m
 	return nil
 }
 
+// PublishEvents publishes the events to the topic in a batch, which is sent to the brokers in the batches of the producer.
+// The batch is published in a transaction when the producer is transactional, none of the events are published then
+// when an event fails.
+func (k *Kafka) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	if k.transactional() {
+		return k.Transaction(nil, func(pubsub.Publisher) error {
+			return k.publishBatch(topic, values, headers)
+		})
+	}
+
+	return k.publishBatch(topic, values, headers)
+}
+
+func (k *Kafka) publishBatch(topic string, values []interface{}, headers map[string]string) error {
+	if topic == "" {
+		topic = k.config.Topics[0]
+	}
+
+	kafkaHeaders := make([]sarama.RecordHeader, 0, len(headers))
+
+	for key, value := range headers {
+		kafkaHeaders = append(kafkaHeaders, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+
+	var errs pubsub.PublishErrors
+
+	messages := make([]*sarama.ProducerMessage, 0, len(values))
+	now := time.Now()
+
+	for i, value := range values {
+		pubsub.PublishTotalCount(topic, k.config.GroupID)
+
+		valBytes, ok := value.([]byte)
+		if !ok {
+			var err error
+
+			if valBytes, err = json.Marshal(value); err != nil {
+				pubsub.PublishFailureCount(topic, k.config.GroupID)
+
+				errs = append(errs, pubsub.PublishError{Index: i, Err: err})
+
+				continue
+			}
+		}
+
+		// the index identifies the message in the errors of the producer
+		messages = append(messages, &sarama.ProducerMessage{Topic: topic, Value: sarama.ByteEncoder(valBytes), Timestamp: now,
+			Headers: kafkaHeaders, Metadata: i})
+	}
+
+	failed := make(map[int]bool)
+
+	if err := k.Producer.SendMessages(messages); err != nil {
+		producerErrs, ok := err.(sarama.ProducerErrors) //nolint:errorlint // the errors of the producer are not wrapped
+		if !ok {
+			return err
+		}
+
+		for _, e := range producerErrs {
+			i, _ := e.Msg.Metadata.(int)
+			failed[i] = true
+
+			errs = append(errs, pubsub.PublishError{Index: i, Err: e.Err})
+		}
+	}
+
+	for _, m := range messages {
+		if failed[m.Metadata.(int)] {
+			pubsub.PublishFailureCount(topic, k.config.GroupID)
+		} else {
+			pubsub.PublishSuccessCount(topic, k.config.GroupID)
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+
+		return errs
+	}
+
+	return nil
+}
+
 // rebalanceSession this method is responsible for rebalancing partitions
 // allocation whenever a new consumer is spawned, or an old one is closed.
 // It runs as an infinite for loop inside a goroutine.
@@ -449,7 +600,9 @@ func (c *Consumer) rebalanceSession(ctx context.Context,
 	return err
 }
 
-func (k *Kafka) subscribeMessage() (*pubsub.Message, error) {
+// subscribeMessage reads the next message, till the context is done. The message is not marked as read, it is committed
+// by CommitOffset, so that the message which is not committed is consumed again.
+func (k *Kafka) subscribeMessage(ctx context.Context) (*pubsub.Message, error) {
 	k.Consumer.initSessionRebalance.Do(
 		func() {
 			k.Consumer.errCh = k.Consumer.rebalanceSession(context.TODO(), k.config)
@@ -458,11 +611,20 @@ func (k *Kafka) subscribeMessage() (*pubsub.Message, error) {
 	select {
 	case e := <-k.Consumer.errCh:
 		return nil, e
-
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	case <-k.Consumer.ConsumerGroupHandler.ready:
 	}
 
-	msg := <-k.Consumer.ConsumerGroupHandler.msg
+	var msg *sarama.ConsumerMessage
+
+	// the message which is not read when the context is done stays with the claim, which is not marked as read
+	select {
+	case msg = <-k.Consumer.ConsumerGroupHandler.msg:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
 	if msg == nil {
 		return nil, errConsumeMsg
 	}
@@ -475,11 +637,6 @@ func (k *Kafka) subscribeMessage() (*pubsub.Message, error) {
 		}
 	}
 
-	// Mark the message as read for autocommit to work
-	k.Consumer.ConsumerGroupHandler.mu.Lock()
-	k.Consumer.ConsumerGroupHandler.consumerGroupSession.MarkMessage(msg, "")
-	k.Consumer.ConsumerGroupHandler.mu.Unlock()
-
 	return &pubsub.Message{
 		Topic:     msg.Topic,
 		Partition: int(msg.Partition),
@@ -495,11 +652,25 @@ func (k *Kafka) subscribeMessage() (*pubsub.Message, error) {
 // consumer group session rebalance, which handles the partition assignment
 // to multiple consumers in the group.
 func (k *Kafka) Subscribe() (*pubsub.Message, error) {
+	return k.subscribe(context.Background())
+}
+
+/*
+SubscribeWithContext reads a message like Subscribe, till the context is done. The read itself waits for the context,
+so that no goroutine is left waiting for a message, and the message is committed only once it is returned, the message
+which is read when the context is done is not committed, hence it is consumed again.
+*/
+func (k *Kafka) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
+	return k.subscribe(ctx)
+}
+
+// subscribe reads the next message, till the context is done, and commits it, unless it is committed by a transaction.
+func (k *Kafka) subscribe(ctx context.Context) (*pubsub.Message, error) {
 	topics := strings.Join(k.config.Topics, ",")
 	// for every subscribe
 	pubsub.SubscribeReceiveCount(topics, k.config.GroupID)
 
-	message, err := k.subscribeMessage()
+	message, err := k.subscribeMessage(ctx)
 	if err != nil {
 		// for unsuccessful subscribe
 		pubsub.SubscribeFailureCount(topics, k.config.GroupID)
@@ -507,11 +678,14 @@ func (k *Kafka) Subscribe() (*pubsub.Message, error) {
 		return nil, err
 	}
 
-	k.CommitOffset(pubsub.TopicPartition{
-		Topic:     message.Topic,
-		Partition: message.Partition,
-		Offset:    message.Offset,
-	})
+	if !k.transactional() {
+		k.CommitOffset(pubsub.TopicPartition{
+			Topic:     message.Topic,
+			Partition: message.Partition,
+			Offset:    message.Offset,
+		})
+	}
+
 	// for successful subscribe
 	pubsub.SubscribeSuccessCount(message.Topic, k.config.GroupID)
 
@@ -528,7 +702,7 @@ func (k *Kafka) SubscribeWithCommit(f pubsub.CommitFunc) (*pubsub.Message, error
 		// for every subscribe
 		pubsub.SubscribeReceiveCount(topics, k.config.GroupID)
 
-		msg, err := k.subscribeMessage()
+		msg, err := k.subscribeMessage(context.Background())
 		if err != nil {
 			// for unsuccessful subscribe
 			pubsub.SubscribeFailureCount(topics, k.config.GroupID)
@@ -719,7 +893,8 @@ func (k *Kafka) CommitOffset(offsets pubsub.TopicPartition) {
 	k.Consumer.ConsumerGroupHandler.mu.Unlock()
 }
 
-// HealthCheck checks if consumer and producer are initialized and the connection is stable
+// HealthCheck checks if consumer and producer are initialized and the connection is stable, the lag of the consumer group
+// on the partitions of the topics is reported in the details
 func (k *Kafka) HealthCheck() types.Health {
 	if k == nil {
 		return types.Health{
@@ -755,6 +930,17 @@ func (k *Kafka) HealthCheck() types.Health {
 
 	resp.Status = pkg.StatusUp
 
+	// the lag is reported when the consumer is in a group, a health check is not failed when it can not be read
+	if k.config.GroupID != "" {
+		lags, err := k.ConsumerLag()
+		if err != nil {
+			k.logger.Warnf("consumer lag of the group %v could not be read: %v", k.config.GroupID, err)
+			return resp
+		}
+
+		resp.Details = map[string]interface{}{"consumerGroup": k.config.GroupID, "lag": lags}
+	}
+
 	return resp
 }
 
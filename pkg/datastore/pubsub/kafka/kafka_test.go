@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha512"
 	"crypto/tls"
 	"encoding/json"
@@ -706,7 +707,7 @@ func TestKafka_SubscribeNilMessage(t *testing.T) {
 	// close the channel to get the msg as nil
 	close(conn.Consumer.ConsumerGroupHandler.msg)
 
-	msg, err := conn.subscribeMessage()
+	msg, err := conn.subscribeMessage(context.Background())
 	if msg != nil {
 		t.Errorf("Failed: Expected Message: %v, Got: %v", nil, msg)
 	}
@@ -714,6 +715,53 @@ func TestKafka_SubscribeNilMessage(t *testing.T) {
 	assert.Equal(t, errConsumeMsg, err)
 }
 
+func TestKafka_SubscribeWithContext(t *testing.T) {
+	session := &MockConsumerGroupSession{}
+	handler := &ConsumerHandler{msg: make(chan *sarama.ConsumerMessage), ready: make(chan bool), consumerGroupSession: session}
+	close(handler.ready)
+
+	k := &Kafka{config: &Config{Topics: []string{"orders"}}, Consumer: &Consumer{ConsumerGroupHandler: handler}}
+	k.Consumer.initSessionRebalance.Do(func() {})
+
+	deadline, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	msg, err := k.SubscribeWithContext(deadline)
+
+	assert.Nil(t, msg)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Empty(t, session.marked, "nothing is committed when the context is done")
+
+	go func() { handler.msg <- &sarama.ConsumerMessage{Topic: "orders", Offset: 4, Value: []byte("a")} }()
+
+	msg, err = k.SubscribeWithContext(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a", msg.Value)
+	assert.Equal(t, []int64{5}, session.marked, "the returned message is committed")
+}
+
+func TestKafka_SubscribeWithCommit_NotCommitted(t *testing.T) {
+	session := &MockConsumerGroupSession{}
+	handler := &ConsumerHandler{msg: make(chan *sarama.ConsumerMessage, 1), ready: make(chan bool), consumerGroupSession: session}
+	close(handler.ready)
+
+	k := &Kafka{config: &Config{Topics: []string{"orders"}}, Consumer: &Consumer{ConsumerGroupHandler: handler}}
+	k.Consumer.initSessionRebalance.Do(func() {})
+
+	handler.msg <- &sarama.ConsumerMessage{Topic: "orders", Offset: 4, Value: []byte("a")}
+
+	msg, err := k.SubscribeWithCommit(func(*pubsub.Message) (bool, bool) { return false, false })
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a", msg.Value)
+	assert.Empty(t, session.marked, "the message is left to CommitOffset")
+
+	k.CommitOffset(pubsub.TopicPartition{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset})
+
+	assert.Equal(t, []int64{5}, session.marked)
+}
+
 func Test_Error(t *testing.T) {
 	brokerErr := brokersErr{}
 	expectedError := connectionFailError
@@ -795,8 +843,58 @@ func TestConsumerHandler_Cleanup(t *testing.T) {
 
 type MockConsumerGroupSession struct {
 	sarama.ConsumerGroupSession
+	marked []int64
 }
 
-func (m *MockConsumerGroupSession) MarkOffset(string, int32, int64, string) {
+func (m *MockConsumerGroupSession) MarkOffset(_ string, _ int32, offset int64, _ string) {
+	m.marked = append(m.marked, offset)
 }
 func (m *MockConsumerGroupSession) MarkMessage(*sarama.ConsumerMessage, string) {}
+
+type mockBatchProducer struct {
+	sarama.SyncProducer
+	sent []*sarama.ProducerMessage
+}
+
+func (m *mockBatchProducer) IsTransactional() bool { return false }
+
+// SendMessages fails the messages of which the value is "fail"
+func (m *mockBatchProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	m.sent = msgs
+
+	var errs sarama.ProducerErrors
+
+	for _, msg := range msgs {
+		if b, _ := msg.Value.Encode(); string(b) == `"fail"` {
+			errs = append(errs, &sarama.ProducerError{Msg: msg, Err: sarama.ErrNotLeaderForPartition})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func TestKafka_PublishEvents(t *testing.T) {
+	producer := &mockBatchProducer{}
+	k := &Kafka{config: &Config{Topics: []string{"orders"}}, Producer: producer}
+
+	err := k.PublishEvents("", []interface{}{"created", "fail", make(chan int), []byte("paid")}, map[string]string{"source": "test"})
+
+	assert.Len(t, producer.sent, 3, "the message which can not be marshaled must not be sent")
+	assert.Equal(t, "orders", producer.sent[0].Topic)
+	assert.Equal(t, []sarama.RecordHeader{{Key: []byte("source"), Value: []byte("test")}}, producer.sent[0].Headers)
+
+	var publishErrs pubsub.PublishErrors
+
+	assert.True(t, errors.As(err, &publishErrs))
+	assert.Len(t, publishErrs, 2)
+	assert.Equal(t, 1, publishErrs[0].Index)
+	assert.Equal(t, sarama.ErrNotLeaderForPartition, publishErrs[0].Err)
+	assert.Equal(t, 2, publishErrs[1].Index)
+
+	assert.NoError(t, k.PublishEvents("payments", []interface{}{"captured"}, nil))
+	assert.Equal(t, "payments", producer.sent[0].Topic)
+}
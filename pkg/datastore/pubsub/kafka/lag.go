@@ -0,0 +1,133 @@
+package kafka
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Shopify/sarama"
+
+	"gofr.dev/pkg/datastore/pubsub"
+)
+
+// PartitionLag is the lag of the consumer group on a partition of a topic, ie: the number of the messages of the
+// partition which are not committed by the group.
+type PartitionLag struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	// Committed is the offset committed by the group, it is -1 when the group has not committed an offset.
+	Committed int64 `json:"committed"`
+	// Latest is the offset of the next message produced to the partition, ie: the high watermark.
+	Latest int64 `json:"latest"`
+	Lag    int64 `json:"lag"`
+}
+
+// offsetClient reads the partitions of the topics and their offsets, it is implemented by sarama.Client.
+type offsetClient interface {
+	Partitions(topic string) ([]int32, error)
+	GetOffset(topic string, partition int32, time int64) (int64, error)
+}
+
+// groupOffsets reads the offsets committed by a consumer group, it is implemented by sarama.ClusterAdmin.
+type groupOffsets interface {
+	ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error)
+}
+
+/*
+ConsumerLag returns the lag of the consumer group on every partition of the topics, and sets it on the consumer lag
+gauge of the pubsub metrics, ex: to alert on the consumers which are falling behind. The lag of a partition on which
+the group has not committed an offset is counted from the oldest offset of the partition.
+*/
+func (k *Kafka) ConsumerLag() ([]PartitionLag, error) {
+	client, admin, err := k.offsetReaders()
+	if err != nil {
+		return nil, err
+	}
+
+	topicPartitions := make(map[string][]int32, len(k.config.Topics))
+
+	for _, topic := range k.config.Topics {
+		partitions, err := client.Partitions(topic)
+		if err != nil {
+			return nil, err
+		}
+
+		topicPartitions[topic] = partitions
+	}
+
+	committed, err := admin.ListConsumerGroupOffsets(k.config.GroupID, topicPartitions)
+	if err != nil {
+		return nil, err
+	}
+
+	lags := make([]PartitionLag, 0, len(topicPartitions))
+
+	for _, topic := range k.config.Topics {
+		for _, partition := range topicPartitions[topic] {
+			lag, err := partitionLag(client, committed, topic, partition)
+			if err != nil {
+				return nil, err
+			}
+
+			pubsub.ConsumerLag(float64(lag.Lag), topic, strconv.Itoa(int(partition)), k.config.GroupID)
+
+			lags = append(lags, lag)
+		}
+	}
+
+	return lags, nil
+}
+
+// partitionLag returns the lag of the group on the partition, from the offset committed by the group.
+func partitionLag(client offsetClient, committed *sarama.OffsetFetchResponse, topic string, partition int32) (PartitionLag, error) {
+	lag := PartitionLag{Topic: topic, Partition: partition, Committed: -1}
+
+	latest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return lag, err
+	}
+
+	lag.Latest = latest
+
+	if block := committed.GetBlock(topic, partition); block != nil && block.Offset >= 0 {
+		lag.Committed = block.Offset
+		lag.Lag = latest - block.Offset
+
+		return lag, nil
+	}
+
+	oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return lag, err
+	}
+
+	lag.Lag = latest - oldest
+
+	return lag, nil
+}
+
+// offsetReaders returns the client and the admin which read the offsets, they are created on the first call, as the
+// consumer group does not expose the client it uses.
+func (k *Kafka) offsetReaders() (offsetClient, groupOffsets, error) {
+	k.lagMu.Lock()
+	defer k.lagMu.Unlock()
+
+	if k.lagClient != nil {
+		return k.lagClient, k.lagAdmin, nil
+	}
+
+	client, err := sarama.NewClient(strings.Split(k.config.Brokers, ","), k.config.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		_ = client.Close()
+
+		return nil, nil, err
+	}
+
+	k.lagClient, k.lagAdmin = client, admin
+
+	return client, admin, nil
+}
@@ -0,0 +1,63 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+)
+
+// mockOffsets returns the offsets of the partitions of the topic orders, and the offsets committed by the group.
+type mockOffsets struct {
+	committed map[int32]int64
+	err       error
+}
+
+func (m mockOffsets) Partitions(string) ([]int32, error) {
+	return []int32{0, 1}, nil
+}
+
+func (m mockOffsets) GetOffset(_ string, _ int32, t int64) (int64, error) {
+	if t == sarama.OffsetOldest {
+		return 20, nil
+	}
+
+	return 100, nil
+}
+
+func (m mockOffsets) ListConsumerGroupOffsets(string, map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	resp := new(sarama.OffsetFetchResponse)
+
+	for partition, offset := range m.committed {
+		resp.AddBlock("orders", partition, &sarama.OffsetFetchResponseBlock{Offset: offset})
+	}
+
+	return resp, nil
+}
+
+func TestKafka_ConsumerLag(t *testing.T) {
+	offsets := mockOffsets{committed: map[int32]int64{0: 90, 1: -1}}
+	k := &Kafka{config: &Config{Topics: []string{"orders"}, GroupID: "billing"}, lagClient: offsets, lagAdmin: offsets}
+
+	lags, err := k.ConsumerLag()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []PartitionLag{{Topic: "orders", Partition: 0, Committed: 90, Latest: 100, Lag: 10},
+		{Topic: "orders", Partition: 1, Committed: -1, Latest: 100, Lag: 80}}, lags)
+}
+
+func TestKafka_ConsumerLagError(t *testing.T) {
+	offsets := mockOffsets{err: errors.Error("coordinator not available")}
+	k := &Kafka{config: &Config{Topics: []string{"orders"}, GroupID: "billing"}, lagClient: offsets, lagAdmin: offsets}
+
+	lags, err := k.ConsumerLag()
+
+	assert.Nil(t, lags)
+	assert.Equal(t, offsets.err, err)
+}
@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// tokenRefreshWindow is the time before the expiry of a token, from which the token is refreshed, so that the brokers
+// are not authenticated with a token which expires during the authentication.
+const tokenRefreshWindow = time.Minute
+
+// TokenProvider returns the token of the SASL/OAUTHBEARER authentication and its expiry, ex: a token of Confluent Cloud
+// or a token of MSK IAM signed by the AWS SDK. It is called again to refresh the token when it is about to expire, the
+// token is fetched on every connection when the expiry is zero.
+type TokenProvider func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// OAuthConfig is the client credentials of the SASL/OAUTHBEARER authentication, the tokens are fetched from the token
+// endpoint of the identity provider.
+type OAuthConfig struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// tokenProvider returns the token provider of the config, or nil when the tokens are not provided.
+func (s *SASLConfig) tokenProvider() TokenProvider {
+	if s.TokenProvider != nil {
+		return s.TokenProvider
+	}
+
+	if s.OAuth.TokenURL == "" {
+		return nil
+	}
+
+	cc := clientcredentials.Config{
+		ClientID:     s.OAuth.ClientID,
+		ClientSecret: s.OAuth.ClientSecret,
+		TokenURL:     s.OAuth.TokenURL,
+		Scopes:       s.OAuth.Scopes,
+	}
+
+	return func(ctx context.Context) (string, time.Time, error) {
+		t, err := cc.Token(ctx)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+
+		return t.AccessToken, t.Expiry, nil
+	}
+}
+
+// accessTokenProvider caches the token of the provider until it is about to expire, as sarama requests the token on
+// every connection to a broker.
+type accessTokenProvider struct {
+	provider TokenProvider
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func (p *accessTokenProvider) Token() (*sarama.AccessToken, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token == "" || time.Until(p.expiry) < tokenRefreshWindow {
+		token, expiry, err := p.provider(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		p.token, p.expiry = token, expiry
+	}
+
+	return &sarama.AccessToken{Token: p.token}, nil
+}
@@ -0,0 +1,110 @@
+package kafka
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+)
+
+func TestAccessTokenProvider_Token(t *testing.T) {
+	tests := []struct {
+		desc   string
+		expiry time.Time
+		calls  int
+	}{
+		{"token is cached until it is about to expire", time.Now().Add(time.Hour), 1},
+		{"token about to expire is refreshed", time.Now().Add(time.Second), 2},
+		{"token without expiry is fetched every time", time.Time{}, 2},
+	}
+
+	for i, tc := range tests {
+		calls := 0
+		p := &accessTokenProvider{provider: func(context.Context) (string, time.Time, error) {
+			calls++
+			return "token", tc.expiry, nil
+		}}
+
+		for j := 0; j < 2; j++ {
+			token, err := p.Token()
+
+			assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, &sarama.AccessToken{Token: "token"}, token, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+
+		assert.Equal(t, tc.calls, calls, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestAccessTokenProvider_TokenError(t *testing.T) {
+	errToken := errors.Error("token endpoint is down")
+
+	p := &accessTokenProvider{provider: func(context.Context) (string, time.Time, error) {
+		return "", time.Time{}, errToken
+	}}
+
+	token, err := p.Token()
+
+	assert.Nil(t, token)
+	assert.Equal(t, errToken, err)
+}
+
+func TestSASLConfig_TokenProviderClientCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "kafka", r.PostForm.Get("scope"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"abc","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	s := SASLConfig{Mechanism: SASLTypeOAuthBearer,
+		OAuth: OAuthConfig{TokenURL: srv.URL, ClientID: "id", ClientSecret: "secret", Scopes: []string{"kafka"}}}
+
+	token, expiry, err := s.tokenProvider()(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", token)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiry, time.Minute)
+}
+
+func Test_processOAuthBearerConfigs(t *testing.T) {
+	tests := []struct {
+		desc    string
+		sasl    SASLConfig
+		enabled bool
+	}{
+		{"token provider", SASLConfig{Mechanism: SASLTypeOAuthBearer,
+			TokenProvider: func(context.Context) (string, time.Time, error) { return "token", time.Time{}, nil }}, true},
+		{"client credentials", SASLConfig{Mechanism: SASLTypeOAuthBearer, OAuth: OAuthConfig{TokenURL: "http://idp/token"}}, true},
+		{"tokens are not provided", SASLConfig{Mechanism: SASLTypeOAuthBearer}, false},
+	}
+
+	for i, tc := range tests {
+		conf := sarama.NewConfig()
+
+		processSASLConfigs(tc.sasl, conf)
+
+		assert.Equal(t, tc.enabled, conf.Net.SASL.Enable, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.enabled {
+			assert.Equal(t, sarama.SASLMechanism(sarama.SASLTypeOAuth), conf.Net.SASL.Mechanism, "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.NotNil(t, conf.Net.SASL.TokenProvider, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestNew_OAuthBearerWithoutTokenProvider(t *testing.T) {
+	_, err := New(&Config{Brokers: "localhost:2008", SASL: SASLConfig{Mechanism: SASLTypeOAuthBearer}}, nil)
+
+	assert.Equal(t, errMissingTokenProvider, err)
+}
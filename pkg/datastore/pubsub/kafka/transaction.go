@@ -0,0 +1,95 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/Shopify/sarama"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+)
+
+const errNotTransactional = errors.Error("kafka producer is not transactional, as the transactional ID is not set")
+
+// processIdempotenceConfigs configures the idempotent producer, which is required by the transactional producer.
+// The producer is idempotent only when the messages are acknowledged by all the replicas, and are sent one request
+// at a time, so that the retries do not reorder the messages.
+func processIdempotenceConfigs(config *Config) {
+	if !config.Idempotent && config.TransactionalID == "" {
+		return
+	}
+
+	conf := config.Config
+
+	conf.Producer.Idempotent = true
+	conf.Producer.RequiredAcks = sarama.WaitForAll
+	conf.Net.MaxOpenRequests = 1
+
+	if conf.Producer.Retry.Max == 0 {
+		conf.Producer.Retry.Max = 1
+	}
+
+	if config.TransactionalID != "" {
+		conf.Producer.Transaction.ID = config.TransactionalID
+		conf.Consumer.IsolationLevel = sarama.ReadCommitted
+	}
+}
+
+// transactional reports whether the messages are published in transactions.
+func (k *Kafka) transactional() bool {
+	return k.Producer != nil && k.Producer.IsTransactional()
+}
+
+// Transaction publishes the messages published by fn, and commits the offset of the consumed message, atomically.
+// The consumed message can be nil, when the messages are only published. The transaction is aborted when fn returns
+// an error, in which case none of the messages are published, and the message is consumed again.
+func (k *Kafka) Transaction(consumed *pubsub.Message, fn func(p pubsub.Publisher) error) error {
+	if !k.transactional() {
+		return errNotTransactional
+	}
+
+	k.txMu.Lock()
+	defer k.txMu.Unlock()
+
+	if err := k.Producer.BeginTxn(); err != nil {
+		return err
+	}
+
+	err := fn(&txPublisher{k: k})
+
+	if err == nil && consumed != nil {
+		offsets := map[string][]*sarama.PartitionOffsetMetadata{
+			consumed.Topic: {{Partition: int32(consumed.Partition), Offset: consumed.Offset + 1}},
+		}
+
+		err = k.Producer.AddOffsetsToTxn(offsets, k.config.GroupID)
+	}
+
+	if err == nil {
+		err = k.Producer.CommitTxn()
+	}
+
+	if err != nil {
+		if abortErr := k.Producer.AbortTxn(); abortErr != nil {
+			return fmt.Errorf("%w, and the transaction could not be aborted: %v", err, abortErr)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// txPublisher publishes the messages in the transaction in progress.
+type txPublisher struct {
+	k *Kafka
+}
+
+func (t *txPublisher) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) error {
+	return t.k.publish(key, value, headers, options)
+}
+
+func (t *txPublisher) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return t.k.publish(key, value, headers, &pubsub.PublishOptions{Topic: t.k.config.Topics[0]})
+}
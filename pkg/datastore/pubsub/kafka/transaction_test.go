@@ -0,0 +1,133 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+)
+
+// mockTxProducer records the calls made to the transactional producer
+type mockTxProducer struct {
+	sarama.SyncProducer
+	calls   []string
+	offsets map[string][]*sarama.PartitionOffsetMetadata
+	sent    []*sarama.ProducerMessage
+}
+
+func (m *mockTxProducer) IsTransactional() bool { return true }
+
+func (m *mockTxProducer) BeginTxn() error {
+	m.calls = append(m.calls, "begin")
+	return nil
+}
+
+func (m *mockTxProducer) CommitTxn() error {
+	m.calls = append(m.calls, "commit")
+	return nil
+}
+
+func (m *mockTxProducer) AbortTxn() error {
+	m.calls = append(m.calls, "abort")
+	return nil
+}
+
+func (m *mockTxProducer) AddOffsetsToTxn(offsets map[string][]*sarama.PartitionOffsetMetadata, _ string) error {
+	m.calls = append(m.calls, "offsets")
+	m.offsets = offsets
+
+	return nil
+}
+
+func (m *mockTxProducer) SendMessage(msg *sarama.ProducerMessage) (partition int32, offset int64, err error) {
+	m.calls = append(m.calls, "send")
+	m.sent = append(m.sent, msg)
+
+	return 0, 0, nil
+}
+
+func TestKafka_Transaction(t *testing.T) {
+	errHandler := errors.Error("stock unavailable")
+	consumed := &pubsub.Message{Topic: "orders", Partition: 2, Offset: 41}
+
+	tests := []struct {
+		desc     string
+		consumed *pubsub.Message
+		fnErr    error
+		calls    []string
+	}{
+		{"publish and commit the offset", consumed, nil, []string{"begin", "send", "send", "offsets", "commit"}},
+		{"publish only", nil, nil, []string{"begin", "send", "send", "commit"}},
+		{"abort on error", consumed, errHandler, []string{"begin", "send", "send", "abort"}},
+	}
+
+	for i, tc := range tests {
+		producer := &mockTxProducer{}
+		k := &Kafka{config: &Config{Topics: []string{"orders"}, GroupID: "billing"}, Producer: producer}
+
+		err := k.Transaction(tc.consumed, func(p pubsub.Publisher) error {
+			_ = p.PublishEvent("1", "invoiced", nil)
+			_ = p.PublishEventWithOptions("1", "paid", nil, &pubsub.PublishOptions{Topic: "payments"})
+
+			return tc.fnErr
+		})
+
+		assert.Equal(t, tc.fnErr, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.calls, producer.calls, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "orders", producer.sent[0].Topic, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "payments", producer.sent[1].Topic, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestKafka_Transaction_Offset(t *testing.T) {
+	producer := &mockTxProducer{}
+	k := &Kafka{config: &Config{Topics: []string{"orders"}, GroupID: "billing"}, Producer: producer}
+
+	err := k.Transaction(&pubsub.Message{Topic: "orders", Partition: 2, Offset: 41}, func(pubsub.Publisher) error { return nil })
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]*sarama.PartitionOffsetMetadata{"orders": {{Partition: 2, Offset: 42}}}, producer.offsets,
+		"the offset of the message next to the consumed one must be committed")
+}
+
+func TestKafka_Transaction_NotTransactional(t *testing.T) {
+	k := &Kafka{config: &Config{Topics: []string{"orders"}}, Producer: &mockBatchProducer{}}
+
+	err := k.Transaction(nil, func(pubsub.Publisher) error { return nil })
+
+	assert.Equal(t, errNotTransactional, err)
+}
+
+func Test_processIdempotenceConfigs(t *testing.T) {
+	tests := []struct {
+		desc       string
+		config     Config
+		idempotent bool
+		txID       string
+		isolation  sarama.IsolationLevel
+	}{
+		{"not idempotent", Config{}, false, "", sarama.ReadUncommitted},
+		{"idempotent", Config{Idempotent: true}, true, "", sarama.ReadUncommitted},
+		{"transactional", Config{TransactionalID: "billing-1"}, true, "billing-1", sarama.ReadCommitted},
+	}
+
+	for i, tc := range tests {
+		tc.config.Config = sarama.NewConfig()
+
+		processIdempotenceConfigs(&tc.config)
+
+		conf := tc.config.Config
+
+		assert.Equal(t, tc.idempotent, conf.Producer.Idempotent, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.txID, conf.Producer.Transaction.ID, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.isolation, conf.Consumer.IsolationLevel, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.idempotent {
+			assert.Equal(t, sarama.WaitForAll, conf.Producer.RequiredAcks, "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, 1, conf.Net.MaxOpenRequests, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
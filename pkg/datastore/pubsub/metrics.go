@@ -37,6 +37,11 @@ var (
 		Name: pkg.FrameworkMetricsPrefix + "pubsub_publish_total_count",
 		Help: "Counter for the total number of publish operations",
 	}, []string{"topic", "consumerGroup"})
+
+	consumerLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: pkg.FrameworkMetricsPrefix + "pubsub_consumer_lag",
+		Help: "Number of the messages of a partition which are not committed by the consumer group",
+	}, []string{"topic", "partition", "consumerGroup"})
 )
 
 func RegisterMetrics() {
@@ -46,6 +51,7 @@ func RegisterMetrics() {
 	_ = prometheus.Register(publishFailureCount)
 	_ = prometheus.Register(publishSuccessCount)
 	_ = prometheus.Register(publishTotalCount)
+	_ = prometheus.Register(consumerLag)
 }
 
 func PublishTotalCount(label ...string) {
@@ -71,3 +77,7 @@ func SubscribeFailureCount(label ...string) {
 func SubscribeSuccessCount(label ...string) {
 	subscribeSuccessCount.WithLabelValues(label...).Inc()
 }
+
+func ConsumerLag(lag float64, label ...string) {
+	consumerLag.WithLabelValues(label...).Set(lag)
+}
@@ -0,0 +1,445 @@
+// Package nats provides methods to publish and consume the messages of a NATS JetStream stream, with a durable consumer
+// of which the messages are acknowledged once processed.
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/datastore/pubsub"
+	gofrErrors "gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+)
+
+const (
+	// KeyHeader is the header in which the key of a message is published, as the messages of NATS do not have keys
+	KeyHeader = "X-Message-Key"
+
+	defaultFetchWait = 5 * time.Second
+	requestTimeout   = 10 * time.Second
+
+	errNoURL         = gofrErrors.Error("nats url is not provided")
+	errNoStream      = gofrErrors.Error("nats stream is not provided")
+	errNoSubject     = gofrErrors.Error("nats subjects are not provided")
+	errNotConnected  = gofrErrors.Error("nats is not connected")
+	errNotConsumer   = gofrErrors.Error("nats consumer is not configured")
+	errNATSNotSet    = gofrErrors.Error("nats is not initialized")
+	errNATSIsClosing = gofrErrors.Error("nats is closed")
+)
+
+// Config stores the configuration parameters required to connect to NATS JetStream.
+type Config struct {
+	// URL of the NATS servers, multiple servers are separated by comma
+	URL string
+	// Stream is the JetStream stream of the subjects, it is created with the subjects when it does not exist
+	Stream string
+	// Subjects of the stream, the first subject is the default subject to which the messages are published
+	Subjects []string
+	// Consumer is the name of the durable consumer of the stream, the messages are consumed only when it is set
+	Consumer string
+	// AckWait is the duration after which a message which is not acknowledged is delivered again
+	AckWait time.Duration
+	// MaxDeliver is the maximum number of times a message is delivered, there is no limit when it is 0
+	MaxDeliver int
+	// FetchWait is the duration for which Subscribe waits for a message before it fetches again, default is 5 seconds
+	FetchWait time.Duration
+
+	User            string
+	Password        string
+	Token           string
+	CredentialsFile string
+
+	ConnRetryDuration int
+}
+
+// NATS is a client of a NATS JetStream stream.
+type NATS struct {
+	config   *Config
+	conn     *natsgo.Conn
+	js       jetstream.JetStream
+	consumer jetstream.Consumer
+	logger   log.Logger
+
+	// mu guards the messages returned by SubscribeWithCommit without being acknowledged, by their sequence, which are
+	// acknowledged by CommitOffset
+	mu      sync.Mutex
+	pending map[int64]jetstream.Msg
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// New connects to NATS, creates the stream when it does not exist, and the durable consumer when it is configured.
+func New(config *Config, logger log.Logger) (*NATS, error) {
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+
+	pubsub.RegisterMetrics()
+
+	conn, err := natsgo.Connect(config.URL, connectOptions(config)...)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+
+		return nil, err
+	}
+
+	n := &NATS{config: config, conn: conn, js: js, logger: logger, done: make(chan struct{})}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	if err := n.createStreamIfNotExist(ctx); err != nil {
+		conn.Close()
+
+		return nil, err
+	}
+
+	if config.Consumer != "" {
+		n.consumer, err = js.CreateOrUpdateConsumer(ctx, config.Stream, jetstream.ConsumerConfig{
+			Durable:    config.Consumer,
+			AckPolicy:  jetstream.AckExplicitPolicy,
+			AckWait:    config.AckWait,
+			MaxDeliver: config.MaxDeliver,
+		})
+		if err != nil {
+			conn.Close()
+
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
+
+func validate(config *Config) error {
+	switch {
+	case config.URL == "":
+		return errNoURL
+	case config.Stream == "":
+		return errNoStream
+	case len(config.Subjects) == 0:
+		return errNoSubject
+	}
+
+	if config.FetchWait <= 0 {
+		config.FetchWait = defaultFetchWait
+	}
+
+	return nil
+}
+
+func connectOptions(config *Config) []natsgo.Option {
+	// the connection is never given up, the messages are buffered while it reconnects
+	options := []natsgo.Option{natsgo.Name(config.Consumer), natsgo.MaxReconnects(-1)}
+
+	switch {
+	case config.CredentialsFile != "":
+		options = append(options, natsgo.UserCredentials(config.CredentialsFile))
+	case config.Token != "":
+		options = append(options, natsgo.Token(config.Token))
+	case config.User != "":
+		options = append(options, natsgo.UserInfo(config.User, config.Password))
+	}
+
+	return options
+}
+
+func (n *NATS) createStreamIfNotExist(ctx context.Context) error {
+	_, err := n.js.Stream(ctx, n.config.Stream)
+	if !errors.Is(err, jetstream.ErrStreamNotFound) {
+		return err
+	}
+
+	_, err = n.js.CreateStream(ctx, jetstream.StreamConfig{Name: n.config.Stream, Subjects: n.config.Subjects})
+
+	return err
+}
+
+// PublishEventWithOptions publishes the message to the subject of the options, which is the first subject of the
+// stream when it is not set. The message is published once it is stored by the stream.
+func (n *NATS) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) (err error) {
+	if n == nil {
+		return errNATSNotSet
+	}
+
+	subject := n.config.Subjects[0]
+	if options != nil && options.Topic != "" {
+		subject = options.Topic
+	}
+
+	pubsub.PublishTotalCount(subject, n.config.Consumer)
+
+	defer func() {
+		if err != nil {
+			pubsub.PublishFailureCount(subject, n.config.Consumer)
+			return
+		}
+
+		pubsub.PublishSuccessCount(subject, n.config.Consumer)
+	}()
+
+	data, ok := value.([]byte)
+	if !ok {
+		if data, err = json.Marshal(value); err != nil {
+			return err
+		}
+	}
+
+	msg := natsgo.NewMsg(subject)
+	msg.Data = data
+
+	for k, v := range headers {
+		msg.Header.Set(k, v)
+	}
+
+	if key != "" {
+		msg.Header.Set(KeyHeader, key)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	_, err = n.js.PublishMsg(ctx, msg)
+
+	return err
+}
+
+// PublishEvent publishes the message to the first subject of the stream
+func (n *NATS) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return n.PublishEventWithOptions(key, value, headers, nil)
+}
+
+// PublishEvents publishes the messages one by one, each of them is acknowledged by the stream
+func (n *NATS) PublishEvents(subject string, values []interface{}, headers map[string]string) error {
+	return pubsub.PublishEach(n, subject, values, headers)
+}
+
+// Subscribe returns the next message of the consumer, and acknowledges it. It waits until a message is available,
+// or NATS is closed.
+func (n *NATS) Subscribe() (*pubsub.Message, error) {
+	return n.SubscribeWithContext(context.Background())
+}
+
+// SubscribeWithContext reads a message like Subscribe, till the context is done. The context is checked between the
+// fetches, which wait for FetchWait at most, the message is acknowledged only when it is returned.
+func (n *NATS) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
+	msg, err := n.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := msg.Ack(); err != nil {
+		return nil, err
+	}
+
+	return toMessage(msg), nil
+}
+
+/*
+SubscribeWithCommit calls the CommitFunc for the messages of the consumer, the message is acknowledged when it is to be
+committed, else it is negatively acknowledged, so that it is delivered again. The messages are consumed until the
+CommitFunc returns false to continue. The message which is returned without being committed is acknowledged by
+CommitOffset, and is delivered again when its ack wait is passed before.
+*/
+func (n *NATS) SubscribeWithCommit(commitFunc pubsub.CommitFunc) (*pubsub.Message, error) {
+	for {
+		msg, err := n.next(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		message := toMessage(msg)
+
+		isCommit, isContinue := commitFunc(message)
+
+		switch {
+		case isCommit:
+			err = msg.Ack()
+		case isContinue:
+			err = msg.Nak()
+		default:
+			n.keep(message.Offset, msg)
+		}
+
+		if err != nil {
+			n.logger.Errorf("message of subject %v at sequence %v could not be acknowledged: %v", message.Topic, message.Offset, err)
+		}
+
+		if !isContinue {
+			return message, nil
+		}
+	}
+}
+
+// next returns the next message of the consumer, the fetches which time out are retried until the context is done, or
+// NATS is closed.
+func (n *NATS) next(ctx context.Context) (jetstream.Msg, error) {
+	if n == nil {
+		return nil, errNATSNotSet
+	}
+
+	if n.consumer == nil {
+		return nil, errNotConsumer
+	}
+
+	for {
+		select {
+		case <-n.done:
+			return nil, errNATSIsClosing
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		msg, err := n.consumer.Next(jetstream.FetchMaxWait(n.config.FetchWait))
+
+		switch {
+		case err == nil:
+			pubsub.SubscribeReceiveCount(msg.Subject(), n.config.Consumer)
+			pubsub.SubscribeSuccessCount(msg.Subject(), n.config.Consumer)
+
+			return msg, nil
+		case errors.Is(err, natsgo.ErrTimeout):
+			continue
+		default:
+			pubsub.SubscribeFailureCount(n.config.Stream, n.config.Consumer)
+
+			return nil, err
+		}
+	}
+}
+
+// toMessage converts the message of JetStream, the offset of the message is its sequence in the stream.
+func toMessage(msg jetstream.Msg) *pubsub.Message {
+	message := &pubsub.Message{Topic: msg.Subject(), Value: string(msg.Data()), Headers: make(map[string]string)}
+
+	for k := range msg.Headers() {
+		message.Headers[k] = msg.Headers().Get(k)
+	}
+
+	message.Key = message.Headers[KeyHeader]
+	delete(message.Headers, KeyHeader)
+
+	if meta, err := msg.Metadata(); err == nil {
+		message.Offset = int64(meta.Sequence.Stream)
+	}
+
+	return message
+}
+
+// Bind parses the JSON message into the target
+func (n *NATS) Bind(message []byte, target interface{}) error {
+	return json.Unmarshal(message, target)
+}
+
+// keep keeps the message returned without being acknowledged, until it is acknowledged by CommitOffset.
+func (n *NATS) keep(sequence int64, msg jetstream.Msg) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.pending == nil {
+		n.pending = make(map[int64]jetstream.Msg)
+	}
+
+	n.pending[sequence] = msg
+}
+
+// CommitOffset acknowledges the message returned by SubscribeWithCommit without being committed, of which the sequence
+// is the offset. The messages of JetStream are acknowledged one by one, instead of by their offsets.
+func (n *NATS) CommitOffset(offsets pubsub.TopicPartition) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	msg, ok := n.pending[offsets.Offset]
+	delete(n.pending, offsets.Offset)
+	n.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if err := msg.Ack(); err != nil {
+		n.logger.Errorf("message of subject %v at sequence %v could not be acknowledged: %v", offsets.Topic, offsets.Offset, err)
+	}
+}
+
+// Ping checks that NATS is connected, and the stream exists
+func (n *NATS) Ping() error {
+	if !n.IsSet() {
+		return errNATSNotSet
+	}
+
+	if !n.conn.IsConnected() {
+		return errNotConnected
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	_, err := n.js.Stream(ctx, n.config.Stream)
+
+	return err
+}
+
+// HealthCheck returns the health of NATS
+func (n *NATS) HealthCheck() types.Health {
+	if n == nil || !n.IsSet() {
+		return types.Health{Name: datastore.NATS, Status: pkg.StatusDown}
+	}
+
+	resp := types.Health{Name: datastore.NATS, Status: pkg.StatusDown, Host: n.conn.ConnectedUrlRedacted(),
+		Database: n.config.Stream}
+
+	if err := n.Ping(); err != nil {
+		n.logger.Errorf("%v", gofrErrors.HealthCheckFailed{Dependency: datastore.NATS, Err: err})
+
+		return resp
+	}
+
+	resp.Status = pkg.StatusUp
+
+	return resp
+}
+
+// IsSet checks whether NATS is initialized or not
+func (n *NATS) IsSet() bool {
+	return n != nil && n.conn != nil && n.js != nil
+}
+
+// Close stops the consumption of the messages, and drains the connection, so that the messages which are being
+// published are sent before it is closed.
+func (n *NATS) Close() error {
+	if n == nil {
+		return nil
+	}
+
+	var err error
+
+	n.closeOnce.Do(func() {
+		close(n.done)
+
+		if n.conn != nil {
+			err = n.conn.Drain()
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,257 @@
+package nats
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/datastore/pubsub"
+	gofrErrors "gofr.dev/pkg/errors"
+	"gofr.dev/pkg/log"
+)
+
+type mockJetStream struct {
+	jetstream.JetStream
+	published []*natsgo.Msg
+	streamErr error
+	created   *jetstream.StreamConfig
+	err       error
+}
+
+func (m *mockJetStream) PublishMsg(_ context.Context, msg *natsgo.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	m.published = append(m.published, msg)
+
+	return &jetstream.PubAck{}, m.err
+}
+
+func (m *mockJetStream) Stream(context.Context, string) (jetstream.Stream, error) {
+	return nil, m.streamErr
+}
+
+func (m *mockJetStream) CreateStream(_ context.Context, cfg jetstream.StreamConfig) (jetstream.Stream, error) {
+	m.created = &cfg
+
+	return nil, m.err
+}
+
+// mockConsumer returns the messages one by one, and times out when there are no messages
+type mockConsumer struct {
+	jetstream.Consumer
+	messages []*mockMsg
+}
+
+func (m *mockConsumer) Next(...jetstream.FetchOpt) (jetstream.Msg, error) {
+	if len(m.messages) == 0 {
+		return nil, natsgo.ErrTimeout
+	}
+
+	msg := m.messages[0]
+	m.messages = m.messages[1:]
+
+	if msg == nil {
+		return nil, natsgo.ErrTimeout
+	}
+
+	return msg, nil
+}
+
+type mockMsg struct {
+	jetstream.Msg
+	subject string
+	data    string
+	headers natsgo.Header
+	acked   bool
+	naked   bool
+}
+
+func (m *mockMsg) Subject() string        { return m.subject }
+func (m *mockMsg) Data() []byte           { return []byte(m.data) }
+func (m *mockMsg) Headers() natsgo.Header { return m.headers }
+
+func (m *mockMsg) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{Sequence: jetstream.SequencePair{Stream: 42}}, nil
+}
+
+func (m *mockMsg) Ack() error {
+	m.acked = true
+	return nil
+}
+
+func (m *mockMsg) Nak() error {
+	m.naked = true
+	return nil
+}
+
+func newTestNATS(js jetstream.JetStream, consumer jetstream.Consumer) *NATS {
+	config := &Config{URL: "nats://localhost:4222", Stream: "ORDERS", Subjects: []string{"orders.created", "orders.paid"},
+		Consumer: "billing", FetchWait: time.Millisecond}
+
+	return &NATS{config: config, js: js, consumer: consumer, logger: log.NewMockLogger(io.Discard), done: make(chan struct{})}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		desc   string
+		config Config
+		err    error
+	}{
+		{"url is not provided", Config{Stream: "ORDERS", Subjects: []string{"orders"}}, errNoURL},
+		{"stream is not provided", Config{URL: "nats://localhost:4222", Subjects: []string{"orders"}}, errNoStream},
+		{"subjects are not provided", Config{URL: "nats://localhost:4222", Stream: "ORDERS"}, errNoSubject},
+	}
+
+	for i, tc := range tests {
+		n, err := New(&tc.config, log.NewMockLogger(io.Discard))
+
+		assert.Nil(t, n, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestNATS_createStreamIfNotExist(t *testing.T) {
+	errStream := gofrErrors.Error("stream info failed")
+
+	tests := []struct {
+		desc      string
+		streamErr error
+		created   bool
+		err       error
+	}{
+		{"stream exists", nil, false, nil},
+		{"stream does not exist", jetstream.ErrStreamNotFound, true, nil},
+		{"stream could not be fetched", errStream, false, errStream},
+	}
+
+	for i, tc := range tests {
+		js := &mockJetStream{streamErr: tc.streamErr}
+
+		err := newTestNATS(js, nil).createStreamIfNotExist(context.Background())
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.created, js.created != nil, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestNATS_PublishEventWithOptions(t *testing.T) {
+	js := &mockJetStream{}
+	n := newTestNATS(js, nil)
+
+	assert.NoError(t, n.PublishEvent("order-1", map[string]string{"id": "1"}, map[string]string{"source": "test"}))
+	assert.NoError(t, n.PublishEventWithOptions("", []byte("paid"), nil, &pubsub.PublishOptions{Topic: "orders.paid"}))
+	assert.Error(t, n.PublishEvent("", make(chan int), nil), "unmarshalable value is published")
+
+	assert.Len(t, js.published, 2)
+	assert.Equal(t, "orders.created", js.published[0].Subject)
+	assert.Equal(t, `{"id":"1"}`, string(js.published[0].Data))
+	assert.Equal(t, "test", js.published[0].Header.Get("source"))
+	assert.Equal(t, "order-1", js.published[0].Header.Get(KeyHeader))
+	assert.Equal(t, "orders.paid", js.published[1].Subject)
+	assert.Equal(t, "paid", string(js.published[1].Data))
+	assert.Empty(t, js.published[1].Header.Get(KeyHeader))
+}
+
+func TestNATS_Subscribe(t *testing.T) {
+	msg := &mockMsg{subject: "orders.created", data: `{"id":"1"}`,
+		headers: natsgo.Header{KeyHeader: {"order-1"}, "Source": {"test"}}}
+
+	// the fetch which times out is retried
+	n := newTestNATS(&mockJetStream{}, &mockConsumer{messages: []*mockMsg{nil, msg}})
+
+	got, err := n.Subscribe()
+
+	assert.NoError(t, err)
+	assert.True(t, msg.acked)
+	assert.Equal(t, &pubsub.Message{Topic: "orders.created", Key: "order-1", Value: `{"id":"1"}`, Offset: 42,
+		Headers: map[string]string{"Source": "test"}}, got)
+}
+
+func TestNATS_Subscribe_Error(t *testing.T) {
+	tests := []struct {
+		desc     string
+		consumer jetstream.Consumer
+		closed   bool
+		err      error
+	}{
+		{"consumer is not configured", nil, false, errNotConsumer},
+		{"nats is closed", &mockConsumer{}, true, errNATSIsClosing},
+	}
+
+	for i, tc := range tests {
+		n := newTestNATS(&mockJetStream{}, tc.consumer)
+
+		if tc.closed {
+			_ = n.Close()
+		}
+
+		got, err := n.Subscribe()
+
+		assert.Nil(t, got, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestNATS_SubscribeWithContext(t *testing.T) {
+	n := newTestNATS(&mockJetStream{}, &mockConsumer{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// the consumer only times out, the fetch is stopped once the deadline is passed
+	got, err := n.SubscribeWithContext(ctx)
+
+	assert.Nil(t, got)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestNATS_SubscribeWithCommit(t *testing.T) {
+	committed := &mockMsg{subject: "orders.created", data: "1"}
+	rejected := &mockMsg{subject: "orders.created", data: "2"}
+	returned := &mockMsg{subject: "orders.created", data: "3"}
+
+	n := newTestNATS(&mockJetStream{}, &mockConsumer{messages: []*mockMsg{committed, rejected, returned}})
+
+	got, err := n.SubscribeWithCommit(func(m *pubsub.Message) (bool, bool) {
+		return m.Value == "1", m.Value != "3"
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3", got.Value)
+	assert.True(t, committed.acked)
+	assert.True(t, rejected.naked, "message which is not committed must be delivered again")
+	assert.False(t, returned.acked || returned.naked, "returned message must be kept till it is committed")
+
+	n.CommitOffset(pubsub.TopicPartition{Topic: got.Topic, Offset: got.Offset})
+
+	assert.True(t, returned.acked, "returned message must be acknowledged by CommitOffset")
+}
+
+func TestNATS_NotSet(t *testing.T) {
+	var n *NATS
+
+	_, err := n.Subscribe()
+
+	assert.Equal(t, datastore.NATS, n.HealthCheck().Name)
+	assert.Equal(t, pkg.StatusDown, n.HealthCheck().Status)
+	assert.Equal(t, errNATSNotSet, n.PublishEvent("", "created", nil))
+	assert.Equal(t, errNATSNotSet, err)
+	assert.NoError(t, n.Close())
+	assert.Equal(t, errNATSNotSet, newTestNATS(nil, nil).Ping())
+}
+
+func TestNATS_Bind(t *testing.T) {
+	var order struct {
+		ID string `json:"id"`
+	}
+
+	n := newTestNATS(nil, nil)
+
+	assert.NoError(t, n.Bind([]byte(`{"id":"1"}`), &order))
+	assert.Equal(t, "1", order.ID)
+}
@@ -0,0 +1,245 @@
+// Package protobuf provides the serialization of the messages of a pubsub to Protobuf, using the wire format of the
+// Confluent Schema Registry, so that the messages are interoperable with the other producers and consumers of the
+// schema registry.
+package protobuf
+
+import (
+	"context"
+	"encoding/binary"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/datastore/pubsub/avro"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+// headerLength is the length of the magic byte and the schema ID, which precede the message indexes and the message.
+const headerLength = 5
+
+// Protobuf publishes the Protobuf messages with the ID of the schema of the subject, and reads the schema ID of the
+// messages consumed.
+type Protobuf struct {
+	subject              string
+	schemaID             int
+	schemaRegistryClient avro.SchemaRegistryClientInterface
+	pubSub               pubsub.PublisherSubscriber
+}
+
+// NewWithConfig initializes the Protobuf pubsub, with the schema registry of the config
+func NewWithConfig(c *avro.Config, ps pubsub.PublisherSubscriber) (pubsub.PublisherSubscriber, error) {
+	if c == nil || c.URL == "" {
+		return nil, nil
+	}
+
+	if c.Version == "" {
+		c.Version = "latest"
+	}
+
+	schemaRegistryClient := avro.NewSchemaRegistryClient(strings.Split(c.URL, ","), c.SchemaUser, c.SchemaPassword)
+
+	return New(ps, schemaRegistryClient, c.Version, c.Subject)
+}
+
+// New initializes the Protobuf pubsub with the schema of the version of the subject, the messages can be consumed
+// without the subject, but can not be published.
+func New(ps pubsub.PublisherSubscriber, src avro.SchemaRegistryClientInterface, version, sub string) (pubsub.PublisherSubscriber, error) {
+	p := &Protobuf{schemaRegistryClient: src, pubSub: ps}
+
+	if sub == "" {
+		return p, nil
+	}
+
+	schemaID, _, err := src.GetSchemaByVersion(sub, version)
+	if err != nil {
+		return nil, err
+	}
+
+	p.schemaID = schemaID
+	p.subject = sub
+
+	return p, nil
+}
+
+// PublishEventWithOptions publishes the Protobuf message, the value must be a proto.Message
+func (p *Protobuf) PublishEventWithOptions(key string, value interface{}, headers map[string]string, options *pubsub.PublishOptions) error {
+	if p.subject == "" {
+		return &errors.Response{Code: "Missing schema", Reason: "Protobuf is initialized without schema"}
+	}
+
+	b, err := p.encode(value)
+	if err != nil {
+		return err
+	}
+
+	return p.pubSub.PublishEventWithOptions(key, b, headers, options)
+}
+
+// PublishEvent publishes the Protobuf message onto the pubsub configured
+func (p *Protobuf) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return p.PublishEventWithOptions(key, value, headers, nil)
+}
+
+// PublishEvents publishes the Protobuf messages in a batch onto the pubsub configured, the values must be proto.Message
+func (p *Protobuf) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	if p.subject == "" {
+		return &errors.Response{Code: "Missing schema", Reason: "Protobuf is initialized without schema"}
+	}
+
+	var errs pubsub.PublishErrors
+
+	encoded := make([]interface{}, 0, len(values))
+	indexes := make([]int, 0, len(values))
+
+	for i, value := range values {
+		b, err := p.encode(value)
+		if err != nil {
+			errs = append(errs, pubsub.PublishError{Index: i, Err: err})
+			continue
+		}
+
+		encoded = append(encoded, b)
+		indexes = append(indexes, i)
+	}
+
+	return pubsub.PublishEncoded(p.pubSub, topic, encoded, indexes, headers, errs)
+}
+
+// encode encodes the Protobuf message in the wire format of the schema registry.
+func (p *Protobuf) encode(value interface{}) ([]byte, error) {
+	msg, ok := value.(proto.Message)
+	if !ok {
+		return nil, &errors.Response{Code: "Invalid message", Reason: "the value is not a Protobuf message"}
+	}
+
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	// the message indexes of the first message of the schema are encoded as a single 0
+	encoded := avro.Encoder{SchemaID: p.schemaID, Content: append([]byte{0}, b...)}
+
+	return encoded.Encode(), nil
+}
+
+// Subscribe reads a message, the value of the message is the Protobuf message without the schema ID
+func (p *Protobuf) Subscribe() (*pubsub.Message, error) {
+	msg, err := p.pubSub.Subscribe()
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(msg)
+}
+
+// SubscribeWithContext reads a message like Subscribe, till the context is done
+func (p *Protobuf) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
+	msg, err := p.pubSub.SubscribeWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(msg)
+}
+
+// SubscribeWithCommit calls the CommitFunc after subscribing a message, and based on the return values decides whether
+// to commit the message and consume another message
+func (p *Protobuf) SubscribeWithCommit(f pubsub.CommitFunc) (*pubsub.Message, error) {
+	for {
+		msg, err := p.pubSub.SubscribeWithCommit(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		msg, err = decode(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		isCommit, isContinue := f(msg)
+		if isCommit {
+			p.CommitOffset(pubsub.TopicPartition{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset})
+		}
+
+		if !isContinue {
+			return msg, nil
+		}
+	}
+}
+
+// decode strips the schema ID and the message indexes of the message.
+// Ref: https://docs.confluent.io/platform/current/schema-registry/fundamentals/serdes-develop/index.html#wire-format
+func decode(msg *pubsub.Message) (*pubsub.Message, error) {
+	value := []byte(msg.Value)
+
+	if len(value) < headerLength+1 || value[0] != 0 {
+		return nil, &errors.Response{Code: "Invalid message", Reason: "the message is not in the wire format of the schema registry"}
+	}
+
+	schemaID := binary.BigEndian.Uint32(value[1:headerLength])
+	rest := value[headerLength:]
+
+	// the message indexes are an array of zigzag encoded varints, preceded by its length
+	count, n := binary.Varint(rest)
+	if n <= 0 || count < 0 {
+		return nil, &errors.Response{Code: "Invalid message", Reason: "the message indexes of the message are invalid"}
+	}
+
+	rest = rest[n:]
+
+	for i := int64(0); i < count; i++ {
+		if _, n = binary.Varint(rest); n <= 0 {
+			return nil, &errors.Response{Code: "Invalid message", Reason: "the message indexes of the message are invalid"}
+		}
+
+		rest = rest[n:]
+	}
+
+	return &pubsub.Message{
+		SchemaID:  int(schemaID),
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Value:     string(rest),
+		Headers:   msg.Headers,
+	}, nil
+}
+
+// Bind parses the Protobuf message and stores the result in the target, which must be a proto.Message
+func (p *Protobuf) Bind(message []byte, target interface{}) error {
+	// Context.Subscribe binds to a pointer to the target
+	if t, ok := target.(*interface{}); ok {
+		target = *t
+	}
+
+	msg, ok := target.(proto.Message)
+	if !ok {
+		return &errors.Response{Code: "Invalid target", Reason: "the target is not a Protobuf message"}
+	}
+
+	return proto.Unmarshal(message, msg)
+}
+
+// Ping checks the health of the pubsub, returns an error if it is down
+func (p *Protobuf) Ping() error {
+	return p.pubSub.Ping()
+}
+
+// HealthCheck returns the health of the pubsub
+func (p *Protobuf) HealthCheck() types.Health {
+	return p.pubSub.HealthCheck()
+}
+
+// IsSet checks whether the Protobuf pubsub is initialized or not
+func (p *Protobuf) IsSet() bool {
+	return p != nil && p.pubSub != nil && p.schemaRegistryClient != nil && p.subject != ""
+}
+
+// CommitOffset marks a particular offset on a specific partition as Read.
+func (p *Protobuf) CommitOffset(offsets pubsub.TopicPartition) {
+	p.pubSub.CommitOffset(offsets)
+}
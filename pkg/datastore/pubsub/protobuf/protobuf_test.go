@@ -0,0 +1,187 @@
+package protobuf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+type mockPubSub struct {
+	published interface{}
+	batch     []interface{}
+	message   *pubsub.Message
+}
+
+func (m *mockPubSub) PublishEventWithOptions(_ string, value interface{}, _ map[string]string, _ *pubsub.PublishOptions) error {
+	m.published = value
+	return nil
+}
+
+func (m *mockPubSub) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return m.PublishEventWithOptions(key, value, headers, nil)
+}
+
+func (m *mockPubSub) PublishEvents(_ string, values []interface{}, _ map[string]string) error {
+	m.batch = values
+
+	// the second message of the batch fails
+	if len(values) > 1 {
+		return pubsub.PublishErrors{{Index: 1, Err: errors.Error("broker is down")}}
+	}
+
+	return nil
+}
+
+func (m *mockPubSub) Subscribe() (*pubsub.Message, error) {
+	return m.message, nil
+}
+
+func (m *mockPubSub) SubscribeWithContext(context.Context) (*pubsub.Message, error) {
+	return m.Subscribe()
+}
+
+func (m *mockPubSub) SubscribeWithCommit(pubsub.CommitFunc) (*pubsub.Message, error) {
+	return m.message, nil
+}
+
+func (m *mockPubSub) Bind([]byte, interface{}) error {
+	return nil
+}
+
+func (m *mockPubSub) CommitOffset(pubsub.TopicPartition) {}
+
+func (m *mockPubSub) Ping() error {
+	return nil
+}
+
+func (m *mockPubSub) HealthCheck() types.Health {
+	return types.Health{}
+}
+
+func (m *mockPubSub) IsSet() bool {
+	return true
+}
+
+type mockRegistry struct{}
+
+func (mockRegistry) GetSchemaByVersion(subject, _ string) (int, string, error) {
+	if subject == "missing" {
+		return 0, "", errors.EntityNotFound{Entity: "subject", ID: subject}
+	}
+
+	return 258, `syntax = "proto3"; message Order {}`, nil
+}
+
+func (mockRegistry) GetSchema(int) (string, error) {
+	return "", nil
+}
+
+func TestProtobuf_PublishEvent(t *testing.T) {
+	ps := &mockPubSub{}
+
+	p, err := New(ps, mockRegistry{}, "latest", "orders-value")
+
+	assert.NoError(t, err)
+	assert.NoError(t, p.PublishEvent("1", wrapperspb.String("created"), nil))
+
+	payload, _ := proto.Marshal(wrapperspb.String("created"))
+
+	assert.Equal(t, append([]byte{0, 0, 0, 1, 2, 0}, payload...), ps.published, "the message must have the schema ID 258")
+}
+
+func TestProtobuf_PublishEventError(t *testing.T) {
+	withoutSubject, _ := New(&mockPubSub{}, mockRegistry{}, "latest", "")
+	withSubject, _ := New(&mockPubSub{}, mockRegistry{}, "latest", "orders-value")
+
+	tests := []struct {
+		desc  string
+		p     pubsub.PublisherSubscriber
+		value interface{}
+		code  string
+	}{
+		{"without schema", withoutSubject, wrapperspb.String("created"), "Missing schema"},
+		{"value is not a Protobuf message", withSubject, "created", "Invalid message"},
+	}
+
+	for i, tc := range tests {
+		err := tc.p.PublishEvent("1", tc.value, nil)
+
+		resp, ok := err.(*errors.Response) //nolint:errorlint // the errors are not wrapped
+
+		assert.True(t, ok, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.code, resp.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestNew_SubjectNotFound(t *testing.T) {
+	_, err := New(&mockPubSub{}, mockRegistry{}, "latest", "missing")
+
+	assert.Equal(t, errors.EntityNotFound{Entity: "subject", ID: "missing"}, err)
+}
+
+func TestProtobuf_Subscribe(t *testing.T) {
+	payload, _ := proto.Marshal(wrapperspb.String("created"))
+
+	tests := []struct {
+		desc    string
+		value   []byte
+		isValid bool
+	}{
+		{"first message of the schema", append([]byte{0, 0, 0, 1, 2, 0}, payload...), true},
+		{"nested message, with the message indexes [1, 2]", append([]byte{0, 0, 0, 1, 2, 4, 2, 4}, payload...), true},
+		{"without magic byte", append([]byte{1, 0, 0, 1, 2, 0}, payload...), false},
+		{"too short", []byte{0, 0, 0}, false},
+	}
+
+	for i, tc := range tests {
+		p, _ := New(&mockPubSub{message: &pubsub.Message{Topic: "orders", Value: string(tc.value)}}, mockRegistry{}, "", "")
+
+		msg, err := p.Subscribe()
+		if !tc.isValid {
+			assert.Error(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+			continue
+		}
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, 258, msg.SchemaID, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		var target interface{} = &wrapperspb.StringValue{}
+
+		// the target is bound as a pointer, as by Context.Subscribe
+		assert.NoError(t, p.Bind([]byte(msg.Value), &target), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "created", target.(*wrapperspb.StringValue).GetValue(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestProtobuf_BindInvalidTarget(t *testing.T) {
+	p, _ := New(&mockPubSub{}, mockRegistry{}, "", "")
+
+	var target struct{ Name string }
+
+	assert.Error(t, p.Bind([]byte{}, &target))
+}
+
+func TestProtobuf_PublishEvents(t *testing.T) {
+	ps := &mockPubSub{}
+
+	p, _ := New(ps, mockRegistry{}, "latest", "orders-value")
+
+	err := p.PublishEvents("orders", []interface{}{wrapperspb.String("created"), "invalid", wrapperspb.String("paid")}, nil)
+
+	assert.Len(t, ps.batch, 2, "the message which is not a Protobuf message must not be published")
+
+	publishErrs, ok := err.(pubsub.PublishErrors) //nolint:errorlint // the errors are not wrapped
+
+	assert.True(t, ok)
+	assert.Len(t, publishErrs, 2)
+	assert.Equal(t, 1, publishErrs[0].Index, "the error of the encoding must refer to the message of the batch")
+	assert.Equal(t, 2, publishErrs[1].Index, "the error of the pubsub must refer to the message of the batch")
+	assert.Equal(t, errors.Error("broker is down"), publishErrs[1].Err)
+}
@@ -0,0 +1,652 @@
+// Package servicebus provides methods to publish the messages to an Azure Service Bus queue or topic, and to consume
+// the messages of a queue or of a subscription of a topic in the peek-lock mode, so that a message is settled once it is
+// processed. The session-enabled entities are consumed one session at a time, to keep the order of their messages.
+package servicebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/datastore/pubsub"
+	gofrErrors "gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+)
+
+const (
+	defaultSessionIdleTimeout = 30 * time.Second
+	requestTimeout            = 10 * time.Second
+
+	errNoNamespace         = gofrErrors.Error("service bus connection string or namespace is not provided")
+	errNoEntity            = gofrErrors.Error("service bus queue or topic is not provided")
+	errNoReceiver          = gofrErrors.Error("service bus queue or subscription is not configured")
+	errServiceBusNotSet    = gofrErrors.Error("service bus is not initialized")
+	errServiceBusIsClosing = gofrErrors.Error("service bus is closed")
+)
+
+// Config stores the configuration parameters required to connect to Azure Service Bus.
+type Config struct {
+	// ConnectionString is the shared access connection string of the namespace, or of the entity
+	ConnectionString string
+	// Namespace is the fully qualified namespace, ie: orders.servicebus.windows.net, which is connected with the client
+	// credentials of Azure AD, when the connection string is not set
+	Namespace    string
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	// Queue is the queue to which the messages are published, and from which they are consumed
+	Queue string
+	// Topic is the topic to which the messages are published, the messages are consumed from its Subscription
+	Topic        string
+	Subscription string
+	// Sessions consumes the messages of a session-enabled entity one session at a time, the keys of the published
+	// messages are their session ids
+	Sessions bool
+	// SessionIdleTimeout is the duration without a message after which the next session is consumed, default is 30 seconds
+	SessionIdleTimeout time.Duration
+	// MaxDeliveryCount dead-letters a message which is not committed after it is delivered as many times, the message is
+	// abandoned to be delivered again until then. Service Bus dead-letters the messages at the max delivery count of the
+	// entity when it is 0.
+	MaxDeliveryCount int
+	// DisableAutoComplete keeps the messages returned by Subscribe locked, until they are completed using CommitOffset
+	DisableAutoComplete bool
+}
+
+// sender is the part of the Service Bus sender used by the client
+type sender interface {
+	SendMessage(ctx context.Context, message *azservicebus.Message, options *azservicebus.SendMessageOptions) error
+	NewMessageBatch(ctx context.Context, options *azservicebus.MessageBatchOptions) (*azservicebus.MessageBatch, error)
+	Close(ctx context.Context) error
+}
+
+// receiver is the part of the Service Bus receiver, and of the session receiver, used by the client
+type receiver interface {
+	ReceiveMessages(ctx context.Context, maxMessages int, options *azservicebus.ReceiveMessagesOptions) (
+		[]*azservicebus.ReceivedMessage, error)
+	PeekMessages(ctx context.Context, maxMessageCount int, options *azservicebus.PeekMessagesOptions) (
+		[]*azservicebus.ReceivedMessage, error)
+	CompleteMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.CompleteMessageOptions) error
+	AbandonMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.AbandonMessageOptions) error
+	DeadLetterMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.DeadLetterOptions) error
+	Close(ctx context.Context) error
+}
+
+// locked is a message which is locked until it is completed using CommitOffset, along with its receiver
+type locked struct {
+	message  *azservicebus.ReceivedMessage
+	receiver receiver
+}
+
+// ServiceBus is a client of an Azure Service Bus queue, or of a topic and its subscription.
+type ServiceBus struct {
+	config *Config
+	client *azservicebus.Client
+	logger log.Logger
+
+	// newSender and acceptSession are replaced in the tests
+	newSender     func(entity string) (sender, error)
+	acceptSession func(ctx context.Context) (receiver, error)
+
+	// mu guards the senders of the entities, and the messages which are not completed yet
+	mu      sync.Mutex
+	senders map[string]sender
+	pending map[int64]locked
+
+	// receiveMu serializes the receives, as a receiver can not receive concurrently. The receiver of a session is
+	// replaced when the session is idle.
+	receiveMu sync.Mutex
+	receiver  receiver
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New returns the client of the entities, the links to the entities are opened with the first request. The receiver
+// of the queue or the subscription is created when it is configured, the sessions are accepted when they are consumed.
+func New(config *Config, logger log.Logger) (*ServiceBus, error) {
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+
+	pubsub.RegisterMetrics()
+
+	client, err := newClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ServiceBus{config: config, client: client, logger: logger}
+	s.init()
+
+	s.newSender = func(entity string) (sender, error) {
+		return client.NewSender(entity, nil)
+	}
+
+	if config.Sessions {
+		s.acceptSession = func(ctx context.Context) (receiver, error) {
+			if config.Queue != "" {
+				return client.AcceptNextSessionForQueue(ctx, config.Queue, nil)
+			}
+
+			return client.AcceptNextSessionForSubscription(ctx, config.Topic, config.Subscription, nil)
+		}
+
+		return s, nil
+	}
+
+	switch {
+	case config.Queue != "":
+		s.receiver, err = client.NewReceiverForQueue(config.Queue, nil)
+	case config.Subscription != "":
+		s.receiver, err = client.NewReceiverForSubscription(config.Topic, config.Subscription, nil)
+	}
+
+	if err != nil {
+		_ = client.Close(context.Background())
+
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func newClient(config *Config) (*azservicebus.Client, error) {
+	if config.ConnectionString != "" {
+		return azservicebus.NewClientFromConnectionString(config.ConnectionString, nil)
+	}
+
+	credential, err := azidentity.NewClientSecretCredential(config.TenantID, config.ClientID, config.ClientSecret, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return azservicebus.NewClient(config.Namespace, credential, nil)
+}
+
+func validate(config *Config) error {
+	switch {
+	case config.ConnectionString == "" && config.Namespace == "":
+		return errNoNamespace
+	case config.Queue == "" && config.Topic == "":
+		return errNoEntity
+	}
+
+	if config.SessionIdleTimeout <= 0 {
+		config.SessionIdleTimeout = defaultSessionIdleTimeout
+	}
+
+	return nil
+}
+
+func (s *ServiceBus) init() {
+	s.senders = make(map[string]sender)
+	s.pending = make(map[int64]locked)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+}
+
+// entity returns the queue or the topic to which the messages are published
+func (s *ServiceBus) entity() string {
+	if s.config.Queue != "" {
+		return s.config.Queue
+	}
+
+	return s.config.Topic
+}
+
+// sender returns the sender of the entity, the senders are created once for an entity
+func (s *ServiceBus) sender(entity string) (sender, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sd, ok := s.senders[entity]; ok {
+		return sd, nil
+	}
+
+	sd, err := s.newSender(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	s.senders[entity] = sd
+
+	return sd, nil
+}
+
+/*
+PublishEventWithOptions publishes the message to the queue or the topic of the options, which is the configured queue or
+topic when it is not set. The key of the message is its session id when the sessions are enabled, else its subject.
+*/
+func (s *ServiceBus) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) (err error) {
+	if s == nil {
+		return errServiceBusNotSet
+	}
+
+	entity := s.entity()
+	if options != nil && options.Topic != "" {
+		entity = options.Topic
+	}
+
+	pubsub.PublishTotalCount(entity, s.config.Subscription)
+
+	defer func() {
+		if err != nil {
+			pubsub.PublishFailureCount(entity, s.config.Subscription)
+			return
+		}
+
+		pubsub.PublishSuccessCount(entity, s.config.Subscription)
+	}()
+
+	msg := &azservicebus.Message{ApplicationProperties: make(map[string]interface{}, len(headers))}
+
+	var ok bool
+
+	if msg.Body, ok = value.([]byte); !ok {
+		if msg.Body, err = json.Marshal(value); err != nil {
+			return err
+		}
+
+		contentType := "application/json"
+		msg.ContentType = &contentType
+	}
+
+	for k, v := range headers {
+		msg.ApplicationProperties[k] = v
+	}
+
+	if key != "" {
+		if s.config.Sessions {
+			msg.SessionID = &key
+		} else {
+			msg.Subject = &key
+		}
+	}
+
+	sd, err := s.sender(entity)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	return sd.SendMessage(ctx, msg, nil)
+}
+
+// PublishEvent publishes the message to the configured queue or topic
+func (s *ServiceBus) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return s.PublishEventWithOptions(key, value, headers, nil)
+}
+
+// PublishEvents publishes the messages to the queue or the topic one by one
+func (s *ServiceBus) PublishEvents(entity string, values []interface{}, headers map[string]string) error {
+	return pubsub.PublishEach(s, entity, values, headers)
+}
+
+/*
+Subscribe returns the next message, and completes it. When the auto complete is disabled, the message stays locked
+until it is completed using CommitOffset with the offset of the message, and it is delivered again when its lock expires.
+*/
+func (s *ServiceBus) Subscribe() (*pubsub.Message, error) {
+	return s.SubscribeWithContext(context.Background())
+}
+
+// SubscribeWithContext reads a message like Subscribe, till the context is done, the receive which is in progress is
+// cancelled along with the context, and the message is completed only when it is returned.
+func (s *ServiceBus) SubscribeWithContext(ctx context.Context) (*pubsub.Message, error) {
+	msg, r, err := s.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.DisableAutoComplete {
+		s.keep(msg, r)
+
+		return s.toMessage(msg), nil
+	}
+
+	if err := s.settle(r, msg, true); err != nil {
+		return nil, err
+	}
+
+	return s.toMessage(msg), nil
+}
+
+/*
+SubscribeWithCommit calls the CommitFunc for the messages, the message is completed when it is to be committed, else it
+is abandoned so that it is delivered again, or dead-lettered once it is delivered MaxDeliveryCount times. The messages
+are consumed until the CommitFunc returns false to continue. The message which is returned without being committed stays
+locked until it is completed using CommitOffset, and it is delivered again when its lock expires.
+*/
+func (s *ServiceBus) SubscribeWithCommit(commitFunc pubsub.CommitFunc) (*pubsub.Message, error) {
+	for {
+		msg, r, err := s.next(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		message := s.toMessage(msg)
+
+		isCommit, isContinue := commitFunc(message)
+
+		if !isCommit && !isContinue {
+			s.keep(msg, r)
+
+			return message, nil
+		}
+
+		if err := s.settle(r, msg, isCommit); err != nil {
+			s.logger.Errorf("message %v of %v could not be settled: %v", msg.MessageID, message.Topic, err)
+		}
+
+		if !isContinue {
+			return message, nil
+		}
+	}
+}
+
+// keep keeps the message locked, until it is completed using CommitOffset with its sequence number.
+func (s *ServiceBus) keep(msg *azservicebus.ReceivedMessage, r receiver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[sequenceNumber(msg)] = locked{message: msg, receiver: r}
+}
+
+// settle completes the message, or abandons it, unless it is delivered MaxDeliveryCount times, then it is dead-lettered
+func (s *ServiceBus) settle(r receiver, msg *azservicebus.ReceivedMessage, complete bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	switch {
+	case complete:
+		return r.CompleteMessage(ctx, msg, nil)
+	case s.config.MaxDeliveryCount > 0 && int(msg.DeliveryCount) >= s.config.MaxDeliveryCount:
+		reason := "MaxDeliveryCountExceeded"
+		description := fmt.Sprintf("message is not processed in %d deliveries", msg.DeliveryCount)
+
+		return r.DeadLetterMessage(ctx, msg, &azservicebus.DeadLetterOptions{Reason: &reason, ErrorDescription: &description})
+	default:
+		return r.AbandonMessage(ctx, msg, nil)
+	}
+}
+
+// next returns the next message along with its receiver, it waits until a message is received, the context is done, or
+// the client is closed.
+func (s *ServiceBus) next(ctx context.Context) (*azservicebus.ReceivedMessage, receiver, error) {
+	if s == nil {
+		return nil, nil, errServiceBusNotSet
+	}
+
+	if s.receiver == nil && s.acceptSession == nil {
+		return nil, nil, errNoReceiver
+	}
+
+	s.receiveMu.Lock()
+	defer s.receiveMu.Unlock()
+
+	// the receive is cancelled when either the context is done or the client is closed
+	receiveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(s.ctx, cancel)
+	defer stop()
+
+	for {
+		if s.ctx.Err() != nil {
+			return nil, nil, errServiceBusIsClosing
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		msg, err := s.receive(receiveCtx)
+		if err != nil {
+			pubsub.SubscribeFailureCount(s.entity(), s.config.Subscription)
+
+			return nil, nil, err
+		}
+
+		if msg != nil {
+			pubsub.SubscribeReceiveCount(s.entity(), s.config.Subscription)
+			pubsub.SubscribeSuccessCount(s.entity(), s.config.Subscription)
+
+			return msg, s.receiver, nil
+		}
+	}
+}
+
+// receive receives a message, it returns nil when no message is received, ie: the session is idle. It is called with
+// receiveMu held.
+func (s *ServiceBus) receive(ctx context.Context) (*azservicebus.ReceivedMessage, error) {
+	if s.acceptSession == nil {
+		messages, err := s.receiver.ReceiveMessages(ctx, 1, nil)
+		if err != nil || len(messages) == 0 {
+			return nil, s.receiveError(ctx, err)
+		}
+
+		return messages[0], nil
+	}
+
+	if s.receiver == nil {
+		r, err := s.acceptSession(ctx)
+		if err != nil {
+			return nil, s.receiveError(ctx, err)
+		}
+
+		s.receiver = r
+	}
+
+	idleCtx, cancel := context.WithTimeout(ctx, s.config.SessionIdleTimeout)
+	defer cancel()
+
+	messages, err := s.receiver.ReceiveMessages(idleCtx, 1, nil)
+	if len(messages) > 0 {
+		return messages[0], nil
+	}
+
+	// the session is closed when it is idle, or fails, so that the next session is accepted
+	_ = s.receiver.Close(context.Background())
+	s.receiver = nil
+
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, nil
+	}
+
+	return nil, s.receiveError(ctx, err)
+}
+
+// receiveError returns the error of a receive, the receive is cancelled when the context is done, or the client is
+// closed
+func (s *ServiceBus) receiveError(ctx context.Context, err error) error {
+	if s.ctx.Err() != nil {
+		return errServiceBusIsClosing
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+// toMessage converts the message of Service Bus, the topic of the message is the queue or the topic from which it is
+// consumed, and its offset is its sequence number.
+func (s *ServiceBus) toMessage(msg *azservicebus.ReceivedMessage) *pubsub.Message {
+	message := &pubsub.Message{Topic: s.entity(), Value: string(msg.Body), Offset: sequenceNumber(msg),
+		Headers: make(map[string]string, len(msg.ApplicationProperties))}
+
+	switch {
+	case msg.SessionID != nil:
+		message.Key = *msg.SessionID
+	case msg.Subject != nil:
+		message.Key = *msg.Subject
+	}
+
+	for k, v := range msg.ApplicationProperties {
+		message.Headers[k] = fmt.Sprint(v)
+	}
+
+	return message
+}
+
+func sequenceNumber(msg *azservicebus.ReceivedMessage) int64 {
+	if msg.SequenceNumber == nil {
+		return 0
+	}
+
+	return *msg.SequenceNumber
+}
+
+// Bind parses the JSON message into the target
+func (s *ServiceBus) Bind(message []byte, target interface{}) error {
+	return json.Unmarshal(message, target)
+}
+
+/*
+CommitOffset completes the message returned by Subscribe, of which the sequence number is the offset, when the auto
+complete is disabled, or returned by SubscribeWithCommit without being committed. The message is dead-lettered instead when the error of the offset is set, with the error as the
+reason.
+*/
+func (s *ServiceBus) CommitOffset(offsets pubsub.TopicPartition) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	l, ok := s.pending[offsets.Offset]
+	delete(s.pending, offsets.Offset)
+	s.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	var err error
+
+	if offsets.Error != nil {
+		reason := offsets.Error.Error()
+		err = l.receiver.DeadLetterMessage(ctx, l.message, &azservicebus.DeadLetterOptions{Reason: &reason})
+	} else {
+		err = l.receiver.CompleteMessage(ctx, l.message, nil)
+	}
+
+	if err != nil {
+		s.logger.Errorf("message %v of %v could not be settled: %v", l.message.MessageID, offsets.Topic, err)
+	}
+}
+
+// Ping checks that the messages of the queue or the subscription can be peeked, or that the link to the entity can be
+// opened when the messages are not consumed, or are consumed from the sessions.
+func (s *ServiceBus) Ping() error {
+	if !s.IsSet() {
+		return errServiceBusNotSet
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	if s.acceptSession == nil && s.receiver != nil {
+		_, err := s.receiver.PeekMessages(ctx, 1, nil)
+
+		return err
+	}
+
+	sd, err := s.sender(s.entity())
+	if err != nil {
+		return err
+	}
+
+	_, err = sd.NewMessageBatch(ctx, nil)
+
+	return err
+}
+
+// HealthCheck returns the health of Service Bus
+func (s *ServiceBus) HealthCheck() types.Health {
+	if !s.IsSet() {
+		return types.Health{Name: datastore.ServiceBus, Status: pkg.StatusDown}
+	}
+
+	resp := types.Health{Name: datastore.ServiceBus, Status: pkg.StatusDown, Host: namespace(s.config), Database: s.entity()}
+
+	if err := s.Ping(); err != nil {
+		s.logger.Errorf("%v", gofrErrors.HealthCheckFailed{Dependency: datastore.ServiceBus, Err: err})
+
+		return resp
+	}
+
+	resp.Status = pkg.StatusUp
+
+	return resp
+}
+
+// namespace returns the namespace of the config, the keys of the connection string are not disclosed
+func namespace(config *Config) string {
+	if config.Namespace != "" {
+		return config.Namespace
+	}
+
+	for _, part := range strings.Split(config.ConnectionString, ";") {
+		if endpoint, ok := strings.CutPrefix(part, "Endpoint="); ok {
+			return strings.TrimSuffix(strings.TrimPrefix(endpoint, "sb://"), "/")
+		}
+	}
+
+	return ""
+}
+
+// IsSet checks whether Service Bus is initialized or not
+func (s *ServiceBus) IsSet() bool {
+	return s != nil && s.newSender != nil
+}
+
+// Close stops the receiving of the messages, and closes the links and the connection. The messages which are not
+// completed are delivered again when their locks expire.
+func (s *ServiceBus) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	s.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	s.receiveMu.Lock()
+
+	if s.receiver != nil {
+		_ = s.receiver.Close(ctx)
+	}
+
+	s.receiveMu.Unlock()
+
+	s.mu.Lock()
+
+	for _, sd := range s.senders {
+		_ = sd.Close(ctx)
+	}
+
+	s.mu.Unlock()
+
+	if s.client == nil {
+		return nil
+	}
+
+	return s.client.Close(ctx)
+}
@@ -0,0 +1,362 @@
+package servicebus
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/datastore/pubsub"
+	gofrErrors "gofr.dev/pkg/errors"
+	"gofr.dev/pkg/log"
+)
+
+const connectionString = "Endpoint=sb://orders.servicebus.windows.net/;SharedAccessKeyName=send;SharedAccessKey=secret"
+
+type mockSender struct {
+	sent []*azservicebus.Message
+	err  error
+}
+
+func (m *mockSender) SendMessage(_ context.Context, msg *azservicebus.Message, _ *azservicebus.SendMessageOptions) error {
+	m.sent = append(m.sent, msg)
+	return m.err
+}
+
+func (m *mockSender) NewMessageBatch(context.Context, *azservicebus.MessageBatchOptions) (*azservicebus.MessageBatch, error) {
+	return nil, m.err
+}
+
+func (m *mockSender) Close(context.Context) error { return nil }
+
+// mockReceiver returns the batches one by one, and waits for the context when there are no batches
+type mockReceiver struct {
+	batches      [][]*azservicebus.ReceivedMessage
+	completed    []string
+	abandoned    []string
+	deadLettered []string
+	reasons      []string
+	closed       bool
+	err          error
+}
+
+func (m *mockReceiver) ReceiveMessages(ctx context.Context, _ int, _ *azservicebus.ReceiveMessagesOptions) (
+	[]*azservicebus.ReceivedMessage, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if len(m.batches) == 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	batch := m.batches[0]
+	m.batches = m.batches[1:]
+
+	return batch, nil
+}
+
+func (m *mockReceiver) PeekMessages(context.Context, int, *azservicebus.PeekMessagesOptions) (
+	[]*azservicebus.ReceivedMessage, error) {
+	return nil, m.err
+}
+
+func (m *mockReceiver) CompleteMessage(_ context.Context, msg *azservicebus.ReceivedMessage,
+	_ *azservicebus.CompleteMessageOptions) error {
+	m.completed = append(m.completed, msg.MessageID)
+	return nil
+}
+
+func (m *mockReceiver) AbandonMessage(_ context.Context, msg *azservicebus.ReceivedMessage,
+	_ *azservicebus.AbandonMessageOptions) error {
+	m.abandoned = append(m.abandoned, msg.MessageID)
+	return nil
+}
+
+func (m *mockReceiver) DeadLetterMessage(_ context.Context, msg *azservicebus.ReceivedMessage,
+	options *azservicebus.DeadLetterOptions) error {
+	m.deadLettered = append(m.deadLettered, msg.MessageID)
+	m.reasons = append(m.reasons, *options.Reason)
+
+	return nil
+}
+
+func (m *mockReceiver) Close(context.Context) error {
+	m.closed = true
+	return nil
+}
+
+func newMessage(id string, seq int64, body string) *azservicebus.ReceivedMessage {
+	return &azservicebus.ReceivedMessage{MessageID: id, SequenceNumber: &seq, Body: []byte(body), DeliveryCount: 1}
+}
+
+func newTestServiceBus(config *Config, sd *mockSender, r *mockReceiver) *ServiceBus {
+	_ = validate(config)
+
+	s := &ServiceBus{config: config, logger: log.NewMockLogger(io.Discard)}
+	s.init()
+
+	s.newSender = func(string) (sender, error) { return sd, nil }
+
+	if r != nil {
+		s.receiver = r
+	}
+
+	return s
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		desc   string
+		config Config
+		err    error
+	}{
+		{"namespace is not provided", Config{Queue: "orders"}, errNoNamespace},
+		{"queue and topic are not provided", Config{ConnectionString: connectionString}, errNoEntity},
+	}
+
+	for i, tc := range tests {
+		s, err := New(&tc.config, log.NewMockLogger(io.Discard))
+
+		assert.Nil(t, s, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func Test_validate(t *testing.T) {
+	config := &Config{Namespace: "orders.servicebus.windows.net", Topic: "orders"}
+
+	assert.NoError(t, validate(config))
+	assert.Equal(t, 30*time.Second, config.SessionIdleTimeout)
+}
+
+func TestServiceBus_PublishEventWithOptions(t *testing.T) {
+	tests := []struct {
+		desc     string
+		sessions bool
+		subject  *string
+		session  *string
+	}{
+		{"key is the subject", false, strPtr("order-1"), nil},
+		{"key is the session id", true, nil, strPtr("order-1")},
+	}
+
+	for i, tc := range tests {
+		sd := &mockSender{}
+		s := newTestServiceBus(&Config{ConnectionString: connectionString, Topic: "orders", Sessions: tc.sessions}, sd, nil)
+
+		assert.NoError(t, s.PublishEvent("order-1", map[string]string{"id": "1"}, map[string]string{"source": "test"}),
+			"TEST[%d], failed.\n%s", i, tc.desc)
+
+		msg := sd.sent[0]
+
+		assert.Equal(t, `{"id":"1"}`, string(msg.Body), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "application/json", *msg.ContentType, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "test", msg.ApplicationProperties["source"], "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.subject, msg.Subject, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.session, msg.SessionID, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestServiceBus_PublishEventWithOptions_Entity(t *testing.T) {
+	entities := make([]string, 0)
+	s := newTestServiceBus(&Config{ConnectionString: connectionString, Queue: "orders"}, nil, nil)
+	s.newSender = func(entity string) (sender, error) {
+		entities = append(entities, entity)
+		return &mockSender{}, nil
+	}
+
+	assert.NoError(t, s.PublishEvent("", []byte("created"), nil))
+	assert.NoError(t, s.PublishEventWithOptions("", []byte("paid"), nil, &pubsub.PublishOptions{Topic: "payments"}))
+	assert.NoError(t, s.PublishEvent("", []byte("shipped"), nil))
+	assert.Error(t, s.PublishEvent("", make(chan int), nil), "unmarshalable value is published")
+
+	assert.Equal(t, []string{"orders", "payments"}, entities, "sender must be created once for an entity")
+}
+
+func TestServiceBus_Subscribe(t *testing.T) {
+	msg := newMessage("1", 7, `{"id":"1"}`)
+	msg.Subject = strPtr("order-1")
+	msg.ApplicationProperties = map[string]interface{}{"source": "test", "attempt": int32(2)}
+
+	r := &mockReceiver{batches: [][]*azservicebus.ReceivedMessage{{}, {msg}}}
+	s := newTestServiceBus(&Config{ConnectionString: connectionString, Queue: "orders"}, &mockSender{}, r)
+
+	got, err := s.Subscribe()
+
+	assert.NoError(t, err)
+	assert.Equal(t, &pubsub.Message{Topic: "orders", Key: "order-1", Value: `{"id":"1"}`, Offset: 7,
+		Headers: map[string]string{"source": "test", "attempt": "2"}}, got)
+	assert.Equal(t, []string{"1"}, r.completed)
+}
+
+func TestServiceBus_Subscribe_DisableAutoComplete(t *testing.T) {
+	r := &mockReceiver{batches: [][]*azservicebus.ReceivedMessage{{newMessage("1", 1, "1")}, {newMessage("2", 2, "2")}}}
+	s := newTestServiceBus(&Config{ConnectionString: connectionString, Queue: "orders", DisableAutoComplete: true},
+		&mockSender{}, r)
+
+	first, _ := s.Subscribe()
+	second, _ := s.Subscribe()
+
+	assert.Empty(t, r.completed, "message must stay locked until it is committed")
+
+	s.CommitOffset(pubsub.TopicPartition{Topic: first.Topic, Offset: first.Offset})
+	s.CommitOffset(pubsub.TopicPartition{Topic: second.Topic, Offset: second.Offset, Error: gofrErrors.Error("invalid order")})
+	s.CommitOffset(pubsub.TopicPartition{Topic: second.Topic, Offset: second.Offset})
+
+	assert.Equal(t, []string{"1"}, r.completed)
+	assert.Equal(t, []string{"2"}, r.deadLettered)
+	assert.Equal(t, []string{"invalid order"}, r.reasons)
+}
+
+func TestServiceBus_SubscribeWithCommit(t *testing.T) {
+	retried := newMessage("3", 3, "3")
+	retried.DeliveryCount = 5
+
+	r := &mockReceiver{batches: [][]*azservicebus.ReceivedMessage{{newMessage("1", 1, "1")}, {newMessage("2", 2, "2")},
+		{retried}, {newMessage("4", 4, "4")}}}
+	s := newTestServiceBus(&Config{ConnectionString: connectionString, Topic: "orders", Subscription: "billing",
+		MaxDeliveryCount: 5}, &mockSender{}, r)
+
+	got, err := s.SubscribeWithCommit(func(m *pubsub.Message) (bool, bool) {
+		return m.Value == "1", m.Value != "4"
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "4", got.Value)
+	assert.Equal(t, "orders", got.Topic)
+	assert.Equal(t, []string{"1"}, r.completed)
+	assert.Equal(t, []string{"2"}, r.abandoned, "message which is not committed must be delivered again")
+	assert.Equal(t, []string{"3"}, r.deadLettered, "message must be dead-lettered at the max delivery count")
+	assert.Equal(t, []string{"MaxDeliveryCountExceeded"}, r.reasons)
+
+	s.CommitOffset(pubsub.TopicPartition{Topic: got.Topic, Offset: got.Offset})
+
+	assert.Equal(t, []string{"1", "4"}, r.completed, "returned message must be completed by CommitOffset")
+}
+
+func TestServiceBus_Subscribe_Sessions(t *testing.T) {
+	first := &mockReceiver{batches: [][]*azservicebus.ReceivedMessage{{newMessage("1", 1, "1")}}}
+	second := &mockReceiver{batches: [][]*azservicebus.ReceivedMessage{{newMessage("2", 2, "2")}}}
+	sessions := []*mockReceiver{first, second}
+
+	s := newTestServiceBus(&Config{ConnectionString: connectionString, Queue: "orders", Sessions: true,
+		SessionIdleTimeout: 10 * time.Millisecond}, &mockSender{}, nil)
+	s.acceptSession = func(context.Context) (receiver, error) {
+		r := sessions[0]
+		sessions = sessions[1:]
+
+		return r, nil
+	}
+
+	got, _ := s.Subscribe()
+	assert.Equal(t, "1", got.Value)
+
+	// the next session is accepted once the session is idle
+	got, _ = s.Subscribe()
+	assert.Equal(t, "2", got.Value)
+
+	assert.True(t, first.closed, "idle session must be closed")
+	assert.Equal(t, []string{"1"}, first.completed)
+	assert.Equal(t, []string{"2"}, second.completed)
+}
+
+func TestServiceBus_Subscribe_Error(t *testing.T) {
+	errReceive := gofrErrors.Error("unauthorized")
+
+	tests := []struct {
+		desc     string
+		receiver *mockReceiver
+		closed   bool
+		err      error
+	}{
+		{"receiver is not configured", nil, false, errNoReceiver},
+		{"messages could not be received", &mockReceiver{err: errReceive}, false, errReceive},
+		{"service bus is closed", &mockReceiver{}, true, errServiceBusIsClosing},
+	}
+
+	for i, tc := range tests {
+		s := newTestServiceBus(&Config{ConnectionString: connectionString, Topic: "orders"}, &mockSender{}, tc.receiver)
+
+		if tc.closed {
+			_ = s.Close()
+		}
+
+		got, err := s.Subscribe()
+
+		assert.Nil(t, got, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestServiceBus_Subscribe_Close(t *testing.T) {
+	s := newTestServiceBus(&Config{ConnectionString: connectionString, Queue: "orders"}, &mockSender{}, &mockReceiver{})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+
+		_ = s.Close()
+	}()
+
+	_, err := s.Subscribe()
+
+	assert.Equal(t, errServiceBusIsClosing, err, "waiting receive must be cancelled when the client is closed")
+}
+
+func TestServiceBus_HealthCheck(t *testing.T) {
+	errPeek := gofrErrors.Error("entity not found")
+
+	tests := []struct {
+		desc     string
+		receiver *mockReceiver
+		sender   *mockSender
+		status   string
+	}{
+		{"messages can be peeked", &mockReceiver{}, &mockSender{err: errPeek}, pkg.StatusUp},
+		{"messages can not be peeked", &mockReceiver{err: errPeek}, &mockSender{}, pkg.StatusDown},
+		{"publisher only", nil, &mockSender{}, pkg.StatusUp},
+		{"entity can not be opened", nil, &mockSender{err: errPeek}, pkg.StatusDown},
+	}
+
+	for i, tc := range tests {
+		s := newTestServiceBus(&Config{ConnectionString: connectionString, Queue: "orders"}, tc.sender, tc.receiver)
+
+		health := s.HealthCheck()
+
+		assert.Equal(t, tc.status, health.Status, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "orders.servicebus.windows.net", health.Host, "keys must not be disclosed. TEST[%d], failed.\n%s",
+			i, tc.desc)
+		assert.Equal(t, "orders", health.Database, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestServiceBus_NotSet(t *testing.T) {
+	var s *ServiceBus
+
+	_, err := s.Subscribe()
+
+	assert.Equal(t, datastore.ServiceBus, s.HealthCheck().Name)
+	assert.Equal(t, pkg.StatusDown, s.HealthCheck().Status)
+	assert.Equal(t, errServiceBusNotSet, s.PublishEvent("", "created", nil))
+	assert.Equal(t, errServiceBusNotSet, err)
+	assert.NoError(t, s.Close())
+}
+
+func TestServiceBus_Bind(t *testing.T) {
+	var order struct {
+		ID string `json:"id"`
+	}
+
+	assert.NoError(t, newTestServiceBus(&Config{}, nil, nil).Bind([]byte(`{"id":"1"}`), &order))
+	assert.Equal(t, "1", order.ID)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,46 @@
+package pubsub
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//nolint:gochecknoglobals // the propagator is stateless
+var traceContext = propagation.TraceContext{}
+
+/*
+InjectTraceContext returns a copy of the headers with the W3C trace context of the span of the context, ie: the
+traceparent and the tracestate headers, so that the processing of the message is traced along with the request which
+published it. The trace context already in the headers takes precedence, and the headers are returned as they are when
+the context has no span.
+*/
+func InjectTraceContext(ctx context.Context, headers map[string]string) map[string]string {
+	if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+		return headers
+	}
+
+	if _, ok := headers["traceparent"]; ok {
+		return headers
+	}
+
+	h := make(map[string]string, len(headers)+len(traceContext.Fields()))
+	for k, v := range headers {
+		h[k] = v
+	}
+
+	traceContext.Inject(ctx, propagation.MapCarrier(h))
+
+	return h
+}
+
+// ExtractTraceContext returns the context with the remote span of the W3C trace context of the headers, the context is
+// returned as it is when the headers have no valid trace context.
+func ExtractTraceContext(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+
+	return traceContext.Extract(ctx, propagation.MapCarrier(headers))
+}
@@ -0,0 +1,37 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContext(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled}))
+
+	headers := map[string]string{"X-Correlation-ID": "123"}
+
+	injected := InjectTraceContext(ctx, headers)
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", injected["traceparent"])
+	assert.Equal(t, "123", injected["X-Correlation-ID"])
+	assert.NotContains(t, headers, "traceparent", "the headers of the caller are not changed")
+
+	sc := trace.SpanContextFromContext(ExtractTraceContext(context.Background(), injected))
+
+	assert.Equal(t, traceID, sc.TraceID())
+	assert.Equal(t, spanID, sc.SpanID())
+	assert.True(t, sc.IsRemote())
+}
+
+func TestInjectTraceContext_NoSpan(t *testing.T) {
+	headers := map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+
+	assert.Equal(t, headers, InjectTraceContext(context.Background(), headers))
+	assert.False(t, trace.SpanContextFromContext(ExtractTraceContext(context.Background(), nil)).IsValid())
+}
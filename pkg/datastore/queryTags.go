@@ -0,0 +1,61 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type queryRouteKey struct{}
+
+// WithQueryRoute returns the context of which the SQL queries are tagged with the route, so that the queries are
+// attributed to the endpoint which issued them.
+func WithQueryRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, queryRouteKey{}, route)
+}
+
+// tagQuery appends a comment in the format of sqlcommenter to the query, with the application, the route and the
+// traceparent of the context, ie: SELECT * FROM orders /*application='orders',route='%2Forders'*/. The comment is kept
+// by the databases in their query statistics, such as pg_stat_statements of PostgreSQL and query_log of ClickHouse.
+// The queries which already have a comment are not tagged, as required by sqlcommenter.
+func tagQuery(ctx context.Context, application, query string) string {
+	if strings.Contains(query, "/*") || strings.Contains(query, "--") {
+		return query
+	}
+
+	// the tags are sorted by their keys
+	tags := make([]string, 0, 3)
+
+	addTag := func(key, value string) {
+		if value != "" {
+			tags = append(tags, key+"='"+url.QueryEscape(value)+"'")
+		}
+	}
+
+	addTag("application", application)
+
+	route, _ := ctx.Value(queryRouteKey{}).(string)
+	addTag("route", route)
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		addTag("traceparent", fmt.Sprintf("00-%s-%s-%s", sc.TraceID(), sc.SpanID(), sc.TraceFlags()))
+	}
+
+	if len(tags) == 0 {
+		return query
+	}
+
+	comment := "/*" + strings.Join(tags, ",") + "*/"
+	query = strings.TrimRightFunc(query, unicode.IsSpace)
+
+	// the comment is a part of the statement, so it is added before the terminating semicolon
+	if strings.HasSuffix(query, ";") {
+		return query[:len(query)-1] + " " + comment + ";"
+	}
+
+	return query + " " + comment
+}
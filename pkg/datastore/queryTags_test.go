@@ -0,0 +1,68 @@
+package datastore
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+
+	"gofr.dev/pkg/log"
+)
+
+func Test_tagQuery(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	traced := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled}))
+
+	tests := []struct {
+		desc        string
+		ctx         context.Context
+		application string
+		query       string
+		exp         string
+	}{
+		{"query of a request", WithQueryRoute(traced, "/orders/{id}"), "orders", "SELECT * FROM orders WHERE id = ?",
+			"SELECT * FROM orders WHERE id = ? /*application='orders',route='%2Forders%2F%7Bid%7D'," +
+				"traceparent='00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01'*/"},
+		{"query terminated by semicolon", context.Background(), "orders", "DELETE FROM orders; \n",
+			"DELETE FROM orders /*application='orders'*/;"},
+		{"query which has a comment", WithQueryRoute(context.Background(), "/orders"), "orders",
+			"SELECT /*+ INDEX(orders) */ * FROM orders", "SELECT /*+ INDEX(orders) */ * FROM orders"},
+		{"query without tags", context.Background(), "", "SELECT 1", "SELECT 1"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.exp, tagQuery(tc.ctx, tc.application, tc.query), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestSQLClient_QueryTags(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock could not be created: %v", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE orders SET status = ? /*application='orders',route='%2Forders'*/").WithArgs("paid").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE orders SET status = ?").WithArgs("paid").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := WithQueryRoute(context.Background(), "/orders")
+	client := &SQLClient{DB: db, logger: log.NewMockLogger(io.Discard), config: &DBConfig{QueryTags: true, Application: "orders"}}
+
+	_, err = client.ExecContext(ctx, "UPDATE orders SET status = ?", "paid")
+	assert.NoError(t, err)
+
+	// the queries are not tagged unless the query tags are enabled
+	client.config.QueryTags = false
+
+	_, err = client.ExecContext(ctx, "UPDATE orders SET status = ?", "paid")
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -242,7 +242,7 @@ func (l *QueryLogger) BeforeProcess(ctx context.Context, _ goRedis.Cmder) (conte
 }
 
 // AfterProcess sets the metrics such as endTime, duration after the completion of the process
-func (l *QueryLogger) AfterProcess(_ context.Context, cmd goRedis.Cmder) error {
+func (l *QueryLogger) AfterProcess(ctx context.Context, cmd goRedis.Cmder) error {
 	endTime := time.Now()
 	query := fmt.Sprintf("%v", cmd.Args())
 	query = strings.TrimPrefix(query, "[")
@@ -254,7 +254,7 @@ func (l *QueryLogger) AfterProcess(_ context.Context, cmd goRedis.Cmder) error {
 	l.DataStore = RedisStore
 	dur := endTime.Sub(l.StartTime).Seconds()
 
-	l.monitorRedis(s, dur)
+	l.monitorRedis(ctx, s, dur)
 
 	return nil
 }
@@ -267,7 +267,7 @@ func (l *QueryLogger) BeforeProcessPipeline(ctx context.Context, _ []goRedis.Cmd
 }
 
 // AfterProcessPipeline sets the metrics such as endTime, duration after the completion of the process
-func (l *QueryLogger) AfterProcessPipeline(_ context.Context, cmds []goRedis.Cmder) error {
+func (l *QueryLogger) AfterProcessPipeline(ctx context.Context, cmds []goRedis.Cmder) error {
 	l.Query = make([]string, len(cmds))
 	endTime := time.Now()
 
@@ -285,13 +285,14 @@ func (l *QueryLogger) AfterProcessPipeline(_ context.Context, cmds []goRedis.Cmd
 
 	dur := endTime.Sub(l.StartTime).Seconds()
 
-	l.monitorRedis(query, dur)
+	l.monitorRedis(ctx, query, dur)
 
 	return nil
 }
 
-func (l *QueryLogger) monitorRedis(query []string, duration float64) {
-	l.Logger.Debug(l)
+func (l *QueryLogger) monitorRedis(ctx context.Context, query []string, duration float64) {
+	log.FromContext(ctx, l.Logger).Debug(l)
 	// push stats to prometheus
 	redisStats.WithLabelValues(query[0], l.Hosts).Observe(duration)
+	middleware.AddServerTiming(ctx, middleware.TimingDatastore, time.Duration(duration*float64(time.Second)))
 }
@@ -0,0 +1,331 @@
+package datastore
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+// formats of the streamed rows
+const (
+	StreamCSV    = "csv"
+	StreamNDJSON = "ndjson"
+)
+
+// ErrInvalidStreamFormat is returned by the streams of which the format is neither StreamCSV nor StreamNDJSON
+const ErrInvalidStreamFormat = errors.Error("invalid stream format, the rows are streamed as csv or ndjson")
+
+// the rows are flushed to the client in batches, as flushing every row sends a packet for each of them
+const streamFlushRows = 100
+
+//nolint:gochecknoglobals // streamedRows has to be a global variable for prometheus
+var (
+	streamedRows = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_streamed_rows",
+		Help: "Counter for the rows streamed from the datastores",
+	}, []string{"datastore", "format"})
+
+	_ = prometheus.Register(streamedRows)
+)
+
+// rowIterator is implemented for the rows of the datastores, so that they are streamed the same way
+type rowIterator interface {
+	columns() ([]string, error)
+	next() bool
+	values() ([]interface{}, error)
+	err() error
+	close() error
+}
+
+// SQLRowsStream streams the rows of a query, in the format StreamCSV or StreamNDJSON, instead of loading them in memory.
+// The rows are closed when the stream is written, ex:
+//
+//	rows, err := c.DB().QueryContext(c, "SELECT id, name FROM customers")
+//	if err != nil {
+//		return nil, err
+//	}
+//
+//	return datastore.SQLRowsStream(rows, datastore.StreamCSV, "customers.csv"), nil
+func SQLRowsStream(rows *sql.Rows, format, fileName string) types.Stream {
+	return rowsStream(&sqlRows{rows: rows}, SQLStore, format, fileName)
+}
+
+// ClickHouseRowsStream streams the rows of a ClickHouse query, in the format StreamCSV or StreamNDJSON.
+// The rows are closed when the stream is written.
+func ClickHouseRowsStream(rows driver.Rows, format, fileName string) types.Stream {
+	return rowsStream(&clickHouseRows{rows: rows}, ClickHouse, format, fileName)
+}
+
+func rowsStream(rows rowIterator, datastore, format, fileName string) types.Stream {
+	s := types.Stream{FileName: fileName}
+
+	switch format {
+	case StreamCSV:
+		s.ContentType = "text/csv"
+	case StreamNDJSON:
+		s.ContentType = "application/x-ndjson"
+	}
+
+	s.Write = func(w io.Writer) error {
+		defer rows.close()
+
+		if s.ContentType == "" {
+			return ErrInvalidStreamFormat
+		}
+
+		return writeRows(w, rows, datastore, format)
+	}
+
+	return s
+}
+
+// writeRows writes the rows in batches of streamFlushRows. As the writes block until the client reads the response,
+// the rows are read from the datastore at the pace of the client.
+func writeRows(w io.Writer, rows rowIterator, datastore, format string) error {
+	columns, err := rows.columns()
+	if err != nil {
+		return err
+	}
+
+	var (
+		bw    = bufio.NewWriter(w)
+		count int
+		enc   rowEncoder
+	)
+
+	if format == StreamCSV {
+		enc, err = newCSVEncoder(bw, columns)
+	} else {
+		enc = &ndjsonEncoder{w: bw, columns: columns}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	flush := func() error {
+		streamedRows.WithLabelValues(datastore, format).Add(float64(count))
+		count = 0
+
+		if err := enc.flush(); err != nil {
+			return err
+		}
+
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+
+		return nil
+	}
+
+	for rows.next() {
+		values, err := rows.values()
+		if err != nil {
+			return err
+		}
+
+		if err = enc.encode(values); err != nil {
+			return err
+		}
+
+		if count++; count == streamFlushRows {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = rows.err(); err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+type rowEncoder interface {
+	encode(values []interface{}) error
+	flush() error
+}
+
+// csvEncoder writes the columns as the header of the CSV
+type csvEncoder struct {
+	w      *bufio.Writer
+	csv    *csv.Writer
+	record []string
+}
+
+func newCSVEncoder(w *bufio.Writer, columns []string) (*csvEncoder, error) {
+	e := &csvEncoder{w: w, csv: csv.NewWriter(w), record: make([]string, len(columns))}
+
+	return e, e.csv.Write(columns)
+}
+
+func (e *csvEncoder) encode(values []interface{}) error {
+	for i, v := range values {
+		switch t := v.(type) {
+		case nil:
+			e.record[i] = ""
+		case time.Time:
+			e.record[i] = t.Format(time.RFC3339Nano)
+		default:
+			e.record[i] = fmt.Sprint(t)
+		}
+	}
+
+	return e.csv.Write(e.record)
+}
+
+func (e *csvEncoder) flush() error {
+	e.csv.Flush()
+
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+
+	return e.w.Flush()
+}
+
+// ndjsonEncoder writes a row as a JSON object in a line, of which the keys are in the order of the columns
+type ndjsonEncoder struct {
+	w       *bufio.Writer
+	columns []string
+}
+
+func (e *ndjsonEncoder) encode(values []interface{}) error {
+	_ = e.w.WriteByte('{')
+
+	for i, v := range values {
+		if i > 0 {
+			_ = e.w.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(e.columns[i])
+
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		_, _ = e.w.Write(key)
+		_ = e.w.WriteByte(':')
+		_, _ = e.w.Write(value)
+	}
+
+	_, err := e.w.WriteString("}\n")
+
+	return err
+}
+
+func (e *ndjsonEncoder) flush() error {
+	return e.w.Flush()
+}
+
+type sqlRows struct {
+	rows *sql.Rows
+	dest []interface{}
+}
+
+func (r *sqlRows) columns() ([]string, error) {
+	columns, err := r.rows.Columns()
+
+	r.dest = make([]interface{}, len(columns))
+
+	return columns, err
+}
+
+func (r *sqlRows) next() bool { return r.rows.Next() }
+
+func (r *sqlRows) values() ([]interface{}, error) {
+	ptrs := make([]interface{}, len(r.dest))
+	for i := range r.dest {
+		ptrs[i] = &r.dest[i]
+	}
+
+	if err := r.rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+
+	// the drivers return the text columns as bytes, which are written as text instead of base64
+	for i, v := range r.dest {
+		if b, ok := v.([]byte); ok {
+			r.dest[i] = string(b)
+		}
+	}
+
+	return r.dest, nil
+}
+
+func (r *sqlRows) err() error { return r.rows.Err() }
+
+func (r *sqlRows) close() error { return r.rows.Close() }
+
+// clickHouseRows scans the columns into the types of the columns, as the driver does not scan into interface{}
+type clickHouseRows struct {
+	rows  driver.Rows
+	types []reflect.Type
+	dest  []interface{}
+}
+
+func (r *clickHouseRows) columns() ([]string, error) {
+	columnTypes := r.rows.ColumnTypes()
+
+	r.types = make([]reflect.Type, len(columnTypes))
+	for i, c := range columnTypes {
+		r.types[i] = c.ScanType()
+	}
+
+	r.dest = make([]interface{}, len(columnTypes))
+
+	return r.rows.Columns(), nil
+}
+
+func (r *clickHouseRows) next() bool { return r.rows.Next() }
+
+func (r *clickHouseRows) values() ([]interface{}, error) {
+	ptrs := make([]interface{}, len(r.types))
+	for i, t := range r.types {
+		ptrs[i] = reflect.New(t).Interface()
+	}
+
+	if err := r.rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+
+	for i, p := range ptrs {
+		v := reflect.ValueOf(p).Elem()
+
+		// the nullable columns are scanned into pointers
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				break
+			}
+
+			v = v.Elem()
+		}
+
+		if v.Kind() == reflect.Ptr {
+			r.dest[i] = nil
+			continue
+		}
+
+		r.dest[i] = v.Interface()
+	}
+
+	return r.dest, nil
+}
+
+func (r *clickHouseRows) err() error { return r.rows.Err() }
+
+func (r *clickHouseRows) close() error { return r.rows.Close() }
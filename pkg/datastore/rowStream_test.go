@@ -0,0 +1,86 @@
+package datastore
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+)
+
+func TestSQLRowsStream(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		desc        string
+		format      string
+		contentType string
+		body        string
+	}{
+		{"csv", StreamCSV, "text/csv", "id,name,created\n1,\"Doe, John\",2024-01-02T03:04:05Z\n2,,2024-01-02T03:04:05Z\n"},
+		{"ndjson", StreamNDJSON, "application/x-ndjson", `{"id":1,"name":"Doe, John","created":"2024-01-02T03:04:05Z"}` + "\n" +
+			`{"id":2,"name":null,"created":"2024-01-02T03:04:05Z"}` + "\n"},
+	}
+
+	for i, tc := range tests {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock could not be created: %v", err)
+		}
+
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created"}).
+			AddRow(1, []byte("Doe, John"), created).AddRow(2, nil, created)).RowsWillBeClosed()
+
+		rows, _ := db.Query("SELECT id, name, created FROM customers")
+		before := testutil.ToFloat64(streamedRows.WithLabelValues(SQLStore, tc.format))
+
+		s := SQLRowsStream(rows, tc.format, "customers")
+		w := httptest.NewRecorder()
+
+		assert.NoError(t, s.Write(w), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.contentType, s.ContentType, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.body, w.Body.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.True(t, w.Flushed, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, before+2, testutil.ToFloat64(streamedRows.WithLabelValues(SQLStore, tc.format)),
+			"TEST[%d], failed.\n%s", i, tc.desc)
+		assert.NoError(t, mock.ExpectationsWereMet(), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		db.Close()
+	}
+}
+
+func TestSQLRowsStream_Error(t *testing.T) {
+	errRow := errors.Error("connection reset")
+
+	tests := []struct {
+		desc   string
+		format string
+		rows   *sqlmock.Rows
+		err    error
+	}{
+		{"invalid format", "xml", sqlmock.NewRows([]string{"id"}).AddRow(1), ErrInvalidStreamFormat},
+		{"rows could not be read", StreamCSV, sqlmock.NewRows([]string{"id"}).AddRow(1).RowError(0, errRow), errRow},
+	}
+
+	for i, tc := range tests {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock could not be created: %v", err)
+		}
+
+		mock.ExpectQuery("SELECT").WillReturnRows(tc.rows).RowsWillBeClosed()
+
+		rows, _ := db.Query("SELECT id FROM customers")
+
+		err = SQLRowsStream(rows, tc.format, "").Write(httptest.NewRecorder())
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.NoError(t, mock.ExpectationsWereMet(), "rows must be closed. TEST[%d], failed.\n%s", i, tc.desc)
+
+		db.Close()
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
 )
 
 // Query executes a query that returns rows, typically a SELECT.
@@ -17,9 +19,9 @@ func (c *SQLClient) Query(query string, args ...interface{}) (*sql.Rows, error)
 	}
 
 	begin := time.Now()
-	rows, err := c.DB.Query(query, args...)
+	rows, err := c.DB.Query(c.tag(context.Background(), query), args...)
 
-	c.monitorQuery(begin, query)
+	c.monitorQuery(context.Background(), begin, query)
 
 	return rows, err
 }
@@ -32,9 +34,9 @@ func (c *SQLClient) Exec(query string, args ...interface{}) (sql.Result, error)
 	}
 
 	begin := time.Now()
-	rows, err := c.DB.Exec(query, args...)
+	rows, err := c.DB.Exec(c.tag(context.Background(), query), args...)
 
-	c.monitorQuery(begin, query)
+	c.monitorQuery(context.Background(), begin, query)
 
 	return rows, err
 }
@@ -45,9 +47,9 @@ func (c *SQLClient) Exec(query string, args ...interface{}) (sql.Result, error)
 func (c *SQLClient) QueryRow(query string, args ...interface{}) *sql.Row {
 	begin := time.Now()
 
-	row := c.DB.QueryRow(query, args...)
+	row := c.DB.QueryRow(c.tag(context.Background(), query), args...)
 
-	c.monitorQuery(begin, query)
+	c.monitorQuery(context.Background(), begin, query)
 
 	return row
 }
@@ -61,9 +63,9 @@ func (c *SQLClient) QueryContext(ctx context.Context, query string, args ...inte
 
 	begin := time.Now()
 
-	rows, err := c.DB.QueryContext(ctx, query, args...)
+	rows, err := c.DB.QueryContext(ctx, c.tag(ctx, query), args...)
 
-	c.monitorQuery(begin, query)
+	c.monitorQuery(ctx, begin, query)
 
 	return rows, err
 }
@@ -77,9 +79,9 @@ func (c *SQLClient) ExecContext(ctx context.Context, query string, args ...inter
 
 	begin := time.Now()
 
-	rows, err := c.DB.ExecContext(ctx, query, args...)
+	rows, err := c.DB.ExecContext(ctx, c.tag(ctx, query), args...)
 
-	c.monitorQuery(begin, query)
+	c.monitorQuery(ctx, begin, query)
 
 	return rows, err
 }
@@ -89,13 +91,22 @@ func (c *SQLClient) ExecContext(ctx context.Context, query string, args ...inter
 // Row's Scan method is called.
 func (c *SQLClient) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	begin := time.Now()
-	row := c.DB.QueryRowContext(ctx, query, args...)
+	row := c.DB.QueryRowContext(ctx, c.tag(ctx, query), args...)
 
-	c.monitorQuery(begin, query)
+	c.monitorQuery(ctx, begin, query)
 
 	return row
 }
 
+// tag appends the tags of the context to the query, when the query tags are enabled
+func (c *SQLClient) tag(ctx context.Context, query string) string {
+	if c.config == nil || !c.config.QueryTags {
+		return query
+	}
+
+	return tagQuery(ctx, c.config.Application, query)
+}
+
 func checkQueryOperation(query string) string {
 	query = strings.ToLower(query)
 	query = strings.TrimSpace(query)
@@ -117,7 +128,7 @@ func checkQueryOperation(query string) string {
 	return "INSERT"
 }
 
-func (c *SQLClient) monitorQuery(begin time.Time, query string) {
+func (c *SQLClient) monitorQuery(ctx context.Context, begin time.Time, query string) {
 	if c == nil || c.DB == nil {
 		return
 	}
@@ -136,6 +147,7 @@ func (c *SQLClient) monitorQuery(begin time.Time, query string) {
 
 	// push stats to prometheus
 	sqlStats.WithLabelValues(checkQueryOperation(query), hostName, dbName).Observe(dur)
+	middleware.AddServerTiming(ctx, middleware.TimingDatastore, time.Since(begin))
 
 	ql := QueryLogger{
 		Query:     []string{query},
@@ -145,7 +157,7 @@ func (c *SQLClient) monitorQuery(begin time.Time, query string) {
 	// log the query
 	if c.logger != nil {
 		ql.Duration = time.Since(begin).Microseconds()
-		c.logger.Debug(ql)
+		log.FromContext(ctx, c.logger).Debug(ql)
 	}
 }
 
@@ -159,7 +171,7 @@ func (c *SQLClient) Begin() (*SQLTx, error) {
 	begin := time.Now()
 
 	tx, err := c.DB.Begin()
-	c.monitorQuery(begin, "BEGIN")
+	c.monitorQuery(context.Background(), begin, "BEGIN")
 
 	return &SQLTx{Tx: tx, logger: c.logger, config: c.config}, err
 }
@@ -173,7 +185,7 @@ func (c *SQLClient) BeginTx(ctx context.Context, opts *sql.TxOptions) (*SQLTx, e
 	begin := time.Now()
 
 	tx, err := c.DB.BeginTx(ctx, opts)
-	c.monitorQuery(begin, "BEGIN TRANSACTION")
+	c.monitorQuery(ctx, begin, "BEGIN TRANSACTION")
 
 	return &SQLTx{Tx: tx, logger: c.logger, config: c.config}, err
 }
@@ -186,8 +198,8 @@ func (c *SQLTx) Exec(query string, args ...interface{}) (sql.Result, error) {
 
 	begin := time.Now()
 
-	result, err := c.Tx.Exec(query, args...)
-	c.monitorQuery(begin, query)
+	result, err := c.Tx.Exec(c.tag(context.Background(), query), args...)
+	c.monitorQuery(context.Background(), begin, query)
 
 	return result, err
 }
@@ -200,8 +212,8 @@ func (c *SQLTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
 
 	begin := time.Now()
 
-	rows, err := c.Tx.Query(query, args...)
-	c.monitorQuery(begin, query)
+	rows, err := c.Tx.Query(c.tag(context.Background(), query), args...)
+	c.monitorQuery(context.Background(), begin, query)
 
 	return rows, err
 }
@@ -212,8 +224,8 @@ func (c *SQLTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
 func (c *SQLTx) QueryRow(query string, args ...interface{}) *sql.Row {
 	begin := time.Now()
 
-	row := c.Tx.QueryRow(query, args...)
-	c.monitorQuery(begin, query)
+	row := c.Tx.QueryRow(c.tag(context.Background(), query), args...)
+	c.monitorQuery(context.Background(), begin, query)
 
 	return row
 }
@@ -226,8 +238,8 @@ func (c *SQLTx) ExecContext(ctx context.Context, query string, args ...interface
 
 	begin := time.Now()
 
-	result, err := c.Tx.ExecContext(ctx, query, args...)
-	c.monitorQuery(begin, query)
+	result, err := c.Tx.ExecContext(ctx, c.tag(ctx, query), args...)
+	c.monitorQuery(ctx, begin, query)
 
 	return result, err
 }
@@ -240,8 +252,8 @@ func (c *SQLTx) QueryContext(ctx context.Context, query string, args ...interfac
 
 	begin := time.Now()
 
-	rows, err := c.Tx.QueryContext(ctx, query, args...)
-	c.monitorQuery(begin, query)
+	rows, err := c.Tx.QueryContext(ctx, c.tag(ctx, query), args...)
+	c.monitorQuery(ctx, begin, query)
 
 	return rows, err
 }
@@ -252,8 +264,8 @@ func (c *SQLTx) QueryContext(ctx context.Context, query string, args ...interfac
 func (c *SQLTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	begin := time.Now()
 
-	row := c.Tx.QueryRowContext(ctx, query, args...)
-	c.monitorQuery(begin, query)
+	row := c.Tx.QueryRowContext(ctx, c.tag(ctx, query), args...)
+	c.monitorQuery(ctx, begin, query)
 
 	return row
 }
@@ -267,12 +279,20 @@ func (c *SQLTx) Commit() error {
 	begin := time.Now()
 
 	err := c.Tx.Commit()
-	c.monitorQuery(begin, "COMMIT")
+	c.monitorQuery(context.Background(), begin, "COMMIT")
 
 	return err
 }
 
-func (c *SQLTx) monitorQuery(begin time.Time, query string) {
+func (c *SQLTx) tag(ctx context.Context, query string) string {
+	if c.config == nil || !c.config.QueryTags {
+		return query
+	}
+
+	return tagQuery(ctx, c.config.Application, query)
+}
+
+func (c *SQLTx) monitorQuery(ctx context.Context, begin time.Time, query string) {
 	var (
 		hostName string
 		dbName   string
@@ -294,9 +314,10 @@ func (c *SQLTx) monitorQuery(begin time.Time, query string) {
 
 	// push stats to prometheus
 	sqlStats.WithLabelValues(checkQueryOperation(query), hostName, dbName).Observe(dur)
+	middleware.AddServerTiming(ctx, middleware.TimingDatastore, time.Since(begin))
 
 	// log the query
 	if c.logger != nil {
-		c.logger.Debug(ql)
+		log.FromContext(ctx, c.logger).Debug(ql)
 	}
 }
@@ -0,0 +1,142 @@
+package errortracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/middleware"
+)
+
+const (
+	bugsnagEndpoint       = "https://notify.bugsnag.com"
+	bugsnagPayloadVersion = "5"
+)
+
+// Bugsnag sends the panic reports to Bugsnag, using its error reporting API.
+type Bugsnag struct {
+	APIKey string
+	// ReleaseStage is the environment of the application, ex: production.
+	ReleaseStage string
+	// AppVersion is the version of the application.
+	AppVersion string
+	// Endpoint is the URL of the error reporting API, it is set for an on-premise Bugsnag.
+	Endpoint string
+	Client   *http.Client
+}
+
+// NewBugsnag returns a Bugsnag exporter for the API key of a Bugsnag project.
+func NewBugsnag(apiKey, releaseStage, appVersion string) *Bugsnag {
+	return &Bugsnag{APIKey: apiKey, ReleaseStage: releaseStage, AppVersion: appVersion, Endpoint: bugsnagEndpoint,
+		Client: http.DefaultClient}
+}
+
+type bugsnagFrame struct {
+	File       string `json:"file"`
+	LineNumber int    `json:"lineNumber"`
+	Method     string `json:"method"`
+	InProject  bool   `json:"inProject"`
+}
+
+type bugsnagException struct {
+	ErrorClass string         `json:"errorClass"`
+	Message    string         `json:"message"`
+	Stacktrace []bugsnagFrame `json:"stacktrace"`
+}
+
+type bugsnagBreadcrumb struct {
+	Timestamp string            `json:"timestamp"`
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	MetaData  map[string]string `json:"metaData"`
+}
+
+type bugsnagEvent struct {
+	Exceptions     []bugsnagException     `json:"exceptions"`
+	Breadcrumbs    []bugsnagBreadcrumb    `json:"breadcrumbs"`
+	Context        string                 `json:"context,omitempty"`
+	Severity       string                 `json:"severity"`
+	Unhandled      bool                   `json:"unhandled"`
+	SeverityReason map[string]string      `json:"severityReason"`
+	App            map[string]string      `json:"app"`
+	Device         map[string]string      `json:"device"`
+	Request        map[string]interface{} `json:"request,omitempty"`
+	MetaData       map[string]interface{} `json:"metaData,omitempty"`
+}
+
+type bugsnagPayload struct {
+	APIKey   string            `json:"apiKey"`
+	Notifier map[string]string `json:"notifier"`
+	Events   []bugsnagEvent    `json:"events"`
+}
+
+// ReportPanic sends the report to Bugsnag as an unhandled event.
+func (b *Bugsnag) ReportPanic(ctx context.Context, report *middleware.PanicReport) error {
+	body, err := json.Marshal(bugsnagPayload{
+		APIKey:   b.APIKey,
+		Notifier: map[string]string{"name": "gofr", "version": pkg.Framework, "url": "https://gofr.dev"},
+		Events:   []bugsnagEvent{b.event(report)},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Bugsnag-Api-Key", b.APIKey)
+	req.Header.Set("Bugsnag-Payload-Version", bugsnagPayloadVersion)
+	req.Header.Set("Bugsnag-Sent-At", time.Now().UTC().Format(time.RFC3339))
+
+	return send(b.Client, req)
+}
+
+func (b *Bugsnag) event(report *middleware.PanicReport) bugsnagEvent {
+	e := bugsnagEvent{
+		Context:        report.Route,
+		Severity:       "error",
+		Unhandled:      true,
+		SeverityReason: map[string]string{"type": "unhandledPanic"},
+		App:            map[string]string{"releaseStage": b.ReleaseStage, "version": b.AppVersion},
+		Device:         map[string]string{"time": report.Time.Format(time.RFC3339)},
+		MetaData:       map[string]interface{}{},
+		Breadcrumbs:    make([]bugsnagBreadcrumb, 0, len(report.Breadcrumbs)),
+	}
+
+	e.Device["hostname"], _ = os.Hostname()
+
+	exception := bugsnagException{ErrorClass: "panic", Message: report.Message}
+
+	for _, f := range report.Stack {
+		exception.Stacktrace = append(exception.Stacktrace,
+			bugsnagFrame{File: f.File, LineNumber: f.Line, Method: f.Function, InProject: !isRuntimeFrame(f)})
+	}
+
+	e.Exceptions = []bugsnagException{exception}
+
+	for _, c := range report.Breadcrumbs {
+		e.Breadcrumbs = append(e.Breadcrumbs, bugsnagBreadcrumb{Timestamp: c.Time.UTC().Format(time.RFC3339Nano),
+			Name: c.Category, Type: "log", MetaData: map[string]string{"message": c.Message}})
+	}
+
+	if report.Method != "" {
+		e.Request = map[string]interface{}{"httpMethod": report.Method, "url": report.URL, "headers": report.Headers}
+	}
+
+	if report.CorrelationID != "" {
+		e.MetaData["request"] = map[string]string{"correlationId": report.CorrelationID}
+	}
+
+	if len(report.AppData) > 0 {
+		e.MetaData["app"] = report.AppData
+	}
+
+	return e
+}
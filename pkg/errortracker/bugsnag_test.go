@@ -0,0 +1,53 @@
+package errortracker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBugsnag_ReportPanic(t *testing.T) {
+	var (
+		header  http.Header
+		payload bugsnagPayload
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer ts.Close()
+
+	b := NewBugsnag("api-key", "production", "1.0.0")
+	b.Endpoint = ts.URL
+
+	assert.NoError(t, b.ReportPanic(context.Background(), testReport()))
+
+	assert.Equal(t, "api-key", header.Get("Bugsnag-Api-Key"))
+	assert.Equal(t, bugsnagPayloadVersion, header.Get("Bugsnag-Payload-Version"))
+	assert.NotEmpty(t, header.Get("Bugsnag-Sent-At"))
+
+	if !assert.Len(t, payload.Events, 1) {
+		return
+	}
+
+	event := payload.Events[0]
+
+	assert.True(t, event.Unhandled)
+	assert.Equal(t, "/orders/{id}", event.Context)
+	assert.Equal(t, "production", event.App["releaseStage"])
+	assert.Equal(t, http.MethodGet, event.Request["httpMethod"])
+	assert.Len(t, event.Breadcrumbs, 1)
+
+	if assert.Len(t, event.Exceptions, 1) {
+		// the frame which panicked is the first frame in Bugsnag
+		assert.Equal(t, []bugsnagFrame{
+			{File: "/app/main.go", LineNumber: 20, Method: "main.handler", InProject: true},
+			{File: "/go/src/net/http/server.go", LineNumber: 2136, Method: "net/http.HandlerFunc.ServeHTTP"},
+		}, event.Exceptions[0].Stacktrace)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Package errortracker provides the exporters of the panic reports to the error trackers, ex: Sentry and Bugsnag.
+The exporters implement middleware.PanicReporter, and are enabled by gofr using SENTRY_DSN and BUGSNAG_API_KEY.
+*/
+package errortracker
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/middleware"
+)
+
+const sentryVersion = "7"
+
+// Sentry sends the panic reports to Sentry, using its store API.
+type Sentry struct {
+	endpoint string
+	key      string
+
+	// Environment is the environment of the application, ex: production.
+	Environment string
+	// Release is the version of the application.
+	Release string
+	Client  *http.Client
+}
+
+// NewSentry returns a Sentry exporter for the DSN of a Sentry project, ex: https://<key>@o1.ingest.sentry.io/<project>
+func NewSentry(dsn, environment, release string) (*Sentry, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	project := strings.Trim(u.Path, "/")
+	if u.User == nil || u.User.Username() == "" || project == "" {
+		return nil, fmt.Errorf("invalid sentry DSN, the key or the project is missing")
+	}
+
+	// the project is the last element of the path, the elements before it are the path of a self-hosted Sentry
+	prefix := ""
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		prefix, project = "/"+project[:i], project[i+1:]
+	}
+
+	return &Sentry{
+		endpoint:    fmt.Sprintf("%v://%v%v/api/%v/store/", u.Scheme, u.Host, prefix, project),
+		key:         u.User.Username(),
+		Environment: environment,
+		Release:     release,
+		Client:      http.DefaultClient,
+	}, nil
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryBreadcrumb struct {
+	Timestamp float64 `json:"timestamp"`
+	Category  string  `json:"category"`
+	Message   string  `json:"message"`
+}
+
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Platform    string                 `json:"platform"`
+	Logger      string                 `json:"logger"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	Transaction string                 `json:"transaction,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Exception   struct {
+		Values []sentryException `json:"values"`
+	} `json:"exception"`
+	Request     *sentryRequest `json:"request,omitempty"`
+	Breadcrumbs struct {
+		Values []sentryBreadcrumb `json:"values"`
+	} `json:"breadcrumbs"`
+}
+
+type sentryRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type sentryException struct {
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	Stacktrace struct {
+		Frames []sentryFrame `json:"frames"`
+	} `json:"stacktrace"`
+	Mechanism struct {
+		Type    string `json:"type"`
+		Handled bool   `json:"handled"`
+	} `json:"mechanism"`
+}
+
+// ReportPanic sends the report to Sentry as an event.
+func (s *Sentry) ReportPanic(ctx context.Context, report *middleware.PanicReport) error {
+	body, err := json.Marshal(s.event(report))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=%v, sentry_client=gofr/%v, sentry_key=%v",
+		sentryVersion, pkg.Framework, s.key))
+
+	return send(s.Client, req)
+}
+
+func (s *Sentry) event(report *middleware.PanicReport) *sentryEvent {
+	e := &sentryEvent{
+		EventID:     eventID(),
+		Timestamp:   report.Time.Format(time.RFC3339),
+		Level:       "fatal",
+		Platform:    "go",
+		Logger:      "gofr",
+		Environment: s.Environment,
+		Release:     s.Release,
+		Transaction: report.Route,
+		Tags:        map[string]string{},
+		Extra:       report.AppData,
+	}
+
+	e.ServerName, _ = os.Hostname()
+
+	if report.CorrelationID != "" {
+		e.Tags["correlation_id"] = report.CorrelationID
+	}
+
+	exception := sentryException{Type: "panic", Value: report.Message}
+	exception.Mechanism.Type = "gofr.recover"
+
+	// the frames are ordered from the outermost to the frame which panicked in Sentry
+	for i := len(report.Stack) - 1; i >= 0; i-- {
+		f := report.Stack[i]
+		exception.Stacktrace.Frames = append(exception.Stacktrace.Frames,
+			sentryFrame{Function: f.Function, Filename: f.File, Lineno: f.Line, InApp: !isRuntimeFrame(f)})
+	}
+
+	e.Exception.Values = []sentryException{exception}
+
+	if report.Method != "" {
+		e.Request = &sentryRequest{Method: report.Method, URL: report.URL, Headers: report.Headers}
+	}
+
+	e.Breadcrumbs.Values = make([]sentryBreadcrumb, 0, len(report.Breadcrumbs))
+
+	for _, b := range report.Breadcrumbs {
+		e.Breadcrumbs.Values = append(e.Breadcrumbs.Values, sentryBreadcrumb{
+			Timestamp: float64(b.Time.UnixNano()) / float64(time.Second),
+			Category:  b.Category,
+			Message:   b.Message,
+		})
+	}
+
+	return e
+}
+
+func eventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// isRuntimeFrame reports whether the frame is of the Go runtime or the standard library, which are not the code
+// of the application.
+func isRuntimeFrame(f middleware.StackFrame) bool {
+	return strings.HasPrefix(f.Function, "runtime.") || strings.HasPrefix(f.Function, "net/http.")
+}
+
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%v responded with status code %v", req.URL.Host, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package errortracker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/middleware"
+)
+
+func testReport() *middleware.PanicReport {
+	return &middleware.PanicReport{
+		Message: "order not found",
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Stack: []middleware.StackFrame{
+			{Function: "main.handler", File: "/app/main.go", Line: 20},
+			{Function: "net/http.HandlerFunc.ServeHTTP", File: "/go/src/net/http/server.go", Line: 2136},
+		},
+		CorrelationID: "b00ff8de800911ec8f6502bfe7568078",
+		Method:        http.MethodGet,
+		URL:           "http://localhost:8000/orders/1",
+		Route:         "/orders/{id}",
+		Breadcrumbs:   []middleware.Breadcrumb{{Time: time.Now(), Category: "sql", Message: "SELECT * FROM orders"}},
+	}
+}
+
+func TestNewSentry(t *testing.T) {
+	tests := []struct {
+		desc     string
+		dsn      string
+		endpoint string
+		key      string
+		err      bool
+	}{
+		{"sentry.io", "https://abc@o1.ingest.sentry.io/42", "https://o1.ingest.sentry.io/api/42/store/", "abc", false},
+		{"self-hosted", "http://abc@localhost:9000/sentry/7", "http://localhost:9000/sentry/api/7/store/", "abc", false},
+		{"key missing", "https://o1.ingest.sentry.io/42", "", "", true},
+		{"project missing", "https://abc@o1.ingest.sentry.io", "", "", true},
+		{"invalid DSN", "://abc", "", "", true},
+	}
+
+	for i, tc := range tests {
+		s, err := NewSentry(tc.dsn, "test", "1.0.0")
+
+		if tc.err {
+			assert.Error(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+			continue
+		}
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.endpoint, s.endpoint, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.key, s.key, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestSentry_ReportPanic(t *testing.T) {
+	var (
+		auth  string
+		event sentryEvent
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("X-Sentry-Auth")
+		_ = json.NewDecoder(r.Body).Decode(&event)
+	}))
+	defer ts.Close()
+
+	s, err := NewSentry(strings.Replace(ts.URL, "://", "://abc@", 1)+"/42", "test", "1.0.0")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, s.ReportPanic(context.Background(), testReport()))
+
+	assert.Contains(t, auth, "sentry_key=abc")
+	assert.Len(t, event.EventID, 32)
+	assert.Equal(t, "test", event.Environment)
+	assert.Equal(t, "1.0.0", event.Release)
+	assert.Equal(t, "/orders/{id}", event.Transaction)
+	assert.Equal(t, "b00ff8de800911ec8f6502bfe7568078", event.Tags["correlation_id"])
+	assert.Equal(t, "http://localhost:8000/orders/1", event.Request.URL)
+	assert.Len(t, event.Breadcrumbs.Values, 1)
+
+	if assert.Len(t, event.Exception.Values, 1) {
+		frames := event.Exception.Values[0].Stacktrace.Frames
+
+		// the frame which panicked is the last frame in Sentry
+		assert.Equal(t, []sentryFrame{
+			{Function: "net/http.HandlerFunc.ServeHTTP", Filename: "/go/src/net/http/server.go", Lineno: 2136},
+			{Function: "main.handler", Filename: "/app/main.go", Lineno: 20, InApp: true},
+		}, frames)
+	}
+}
+
+func TestSentry_ReportPanicError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	s, _ := NewSentry(strings.Replace(ts.URL, "://", "://abc@", 1)+"/42", "", "")
+
+	assert.Error(t, s.ReportPanic(context.Background(), testReport()))
+}
@@ -0,0 +1,51 @@
+package file
+
+import (
+	"context"
+	"path/filepath"
+
+	"gofr.dev/pkg/gofr"
+)
+
+// Archiver writes the archives of middleware.BodyArchive to the configured file store, with the key of an archive
+// as its file name.
+type Archiver struct {
+	Config *Config
+}
+
+// NewArchiver returns an Archiver for the file store configured by FILE_STORE, and its respective configs.
+func NewArchiver(config gofr.Config) *Archiver {
+	return &Archiver{Config: &Config{
+		FileStore: config.Get("FILE_STORE"),
+		Azure:     setAzureConfig(config),
+		AWS:       setAWSConfig(config),
+		GCP:       setGCPConfig(config),
+		SFTP:      setSFTPConfig(config),
+		FTP:       setFTPConfig(config),
+	}}
+}
+
+// Archive writes the data to the file named key.
+func (a *Archiver) Archive(_ context.Context, key string, data []byte) error {
+	if a.Config.FileStore == Local {
+		if err := createNestedDir(filepath.Dir(key)); err != nil {
+			return err
+		}
+	}
+
+	f, err := New(a.Config, key, WRITE)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Open(); err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
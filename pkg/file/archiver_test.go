@@ -0,0 +1,33 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+)
+
+func TestArchiver_Archive(t *testing.T) {
+	key := filepath.Join(t.TempDir(), "archives", "2023", "05", "01", "100000-id")
+	a := NewArchiver(&config.MockConfig{Data: map[string]string{"FILE_STORE": Local}})
+
+	err := a.Archive(context.Background(), key, []byte("archive"))
+
+	assert.NoError(t, err)
+
+	data, _ := os.ReadFile(key)
+
+	assert.Equal(t, "archive", string(data))
+}
+
+func TestArchiver_InvalidStore(t *testing.T) {
+	a := NewArchiver(&config.MockConfig{Data: map[string]string{"FILE_STORE": "invalid"}})
+
+	err := a.Archive(context.Background(), "archive", []byte("archive"))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package file
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Checkpoint stores the number of the last line processed of a file, so that the processing of the file
+// can be resumed after a failure or a restart.
+type Checkpoint interface {
+	// Load returns the number of the last line processed, it returns 0 when the file has no checkpoint.
+	Load(name string) (int64, error)
+	// Save stores the number of the last line processed.
+	Save(name string, line int64) error
+	// Clear removes the checkpoint, once the file has been processed completely.
+	Clear(name string) error
+}
+
+// FileCheckpoint stores the checkpoints as files in a local directory.
+type FileCheckpoint struct {
+	Dir string
+}
+
+func (f FileCheckpoint) path(name string) string {
+	// the name can be a URL or a path, hence it is hashed to get a valid file name
+	sum := sha256.Sum256([]byte(name))
+
+	return filepath.Join(f.Dir, hex.EncodeToString(sum[:])+".checkpoint")
+}
+
+func (f FileCheckpoint) Load(name string) (int64, error) {
+	data, err := os.ReadFile(f.path(name))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// Save writes the checkpoint to a temporary file and renames it, so that a crash while saving
+// does not leave a partially written checkpoint.
+func (f FileCheckpoint) Save(name string, line int64) error {
+	if err := os.MkdirAll(f.Dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	path := f.path(name)
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(line, 10)), 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func (f FileCheckpoint) Clear(name string) error {
+	err := os.Remove(f.path(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,224 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/log"
+)
+
+const (
+	defaultCheckpointInterval = 1000
+	defaultRetryBackoff       = time.Second
+)
+
+//nolint:gochecknoglobals // the metrics have to be global variables for prometheus
+var (
+	fileLines = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_file_lines_total",
+		Help: "Counter for the lines processed from files",
+	}, []string{"source", "status"})
+
+	fileProcessing = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "zs_file_processing_duration_seconds",
+		Help:    "Histogram for the duration of processing a file",
+		Buckets: []float64{1, 5, 10, 30, 60, 300, 600, 1800, 3600},
+	}, []string{"source", "status"})
+
+	_ = prometheus.Register(fileLines)
+	_ = prometheus.Register(fileProcessing)
+)
+
+// LineHandler processes a line of a file, the line is retried when an error is returned.
+type LineHandler func(ctx context.Context, line []byte) error
+
+// LineError is returned when a line could not be processed even after retrying.
+type LineError struct {
+	Source string
+	Line   int64
+	Err    error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("processing line %v of %v failed: %v", e.Line, e.Source, e.Err)
+}
+
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+// Result is the summary of processing a file.
+type Result struct {
+	// Processed is the number of lines processed in this run.
+	Processed int64
+	// Resumed is the number of lines skipped, as they were processed in an earlier run.
+	Resumed int64
+}
+
+// Processor streams the lines of a file to a LineHandler. The progress is saved in the Checkpoint, so that a
+// file whose processing failed is resumed from the line which failed, instead of being processed again.
+type Processor struct {
+	Logger log.Logger
+	// Checkpoint stores the progress, the progress is not stored when it is nil.
+	Checkpoint Checkpoint
+	// CheckpointInterval is the number of lines after which the progress is saved. Default is 1000.
+	CheckpointInterval int64
+	// Retries is the number of times opening the file and processing a line is retried.
+	Retries int
+	// RetryBackoff is the delay before the first retry, it is doubled for every retry. Default is 1 second.
+	RetryBackoff time.Duration
+}
+
+// Process processes the lines of the source in order. It stops at the first line which fails after retrying, and
+// returns a LineError, the progress until the previous line is saved. The checkpoint is cleared once all the lines
+// are processed.
+func (p *Processor) Process(ctx context.Context, src Source, handler LineHandler) (Result, error) {
+	start := time.Now()
+
+	result, err := p.process(ctx, src, handler)
+
+	status := "SUCCESS"
+	if err != nil {
+		status = "FAILED"
+	}
+
+	fileProcessing.WithLabelValues(src.Name(), status).Observe(time.Since(start).Seconds())
+
+	return result, err
+}
+
+func (p *Processor) process(ctx context.Context, src Source, handler LineHandler) (Result, error) {
+	var result Result
+
+	name := src.Name()
+
+	resumeFrom, err := p.load(name)
+	if err != nil {
+		return result, err
+	}
+
+	var rc io.ReadCloser
+
+	err = p.retry(ctx, func() error {
+		var openErr error
+
+		rc, openErr = src.Open(ctx)
+
+		return openErr
+	})
+	if err != nil {
+		return result, err
+	}
+
+	defer rc.Close()
+
+	lines, err := NewLineReader(rc)
+	if err != nil {
+		return result, err
+	}
+
+	defer lines.Close()
+
+	if resumeFrom > 0 && p.Logger != nil {
+		p.Logger.Infof("resuming %v after line %v", name, resumeFrom)
+	}
+
+	for lines.Next() {
+		n := lines.LineNumber()
+		if n <= resumeFrom {
+			result.Resumed++
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return result, p.stop(name, n-1, err)
+		}
+
+		if err := p.retry(ctx, func() error { return handler(ctx, lines.Bytes()) }); err != nil {
+			fileLines.WithLabelValues(name, "FAILED").Inc()
+
+			return result, p.stop(name, n-1, LineError{Source: name, Line: n, Err: err})
+		}
+
+		fileLines.WithLabelValues(name, "SUCCESS").Inc()
+
+		result.Processed++
+
+		if n%p.checkpointInterval() == 0 {
+			if err := p.save(name, n); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if err := lines.Err(); err != nil {
+		return result, p.stop(name, lines.LineNumber(), err)
+	}
+
+	if p.Checkpoint != nil {
+		return result, p.Checkpoint.Clear(name)
+	}
+
+	return result, nil
+}
+
+// stop saves the progress until the line, and returns the error which stopped the processing.
+func (p *Processor) stop(name string, line int64, err error) error {
+	if saveErr := p.save(name, line); saveErr != nil && p.Logger != nil {
+		p.Logger.Errorf("checkpoint of %v could not be saved at line %v: %v", name, line, saveErr)
+	}
+
+	return err
+}
+
+func (p *Processor) load(name string) (int64, error) {
+	if p.Checkpoint == nil {
+		return 0, nil
+	}
+
+	return p.Checkpoint.Load(name)
+}
+
+func (p *Processor) save(name string, line int64) error {
+	if p.Checkpoint == nil || line <= 0 {
+		return nil
+	}
+
+	return p.Checkpoint.Save(name, line)
+}
+
+func (p *Processor) checkpointInterval() int64 {
+	if p.CheckpointInterval <= 0 {
+		return defaultCheckpointInterval
+	}
+
+	return p.CheckpointInterval
+}
+
+// retry calls f until it succeeds, the retries are exhausted or the context is done.
+func (p *Processor) retry(ctx context.Context, f func() error) error {
+	backoff := p.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	err := f()
+
+	for i := 0; err != nil && i < p.Retries; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+
+		err = f()
+	}
+
+	return err
+}
@@ -0,0 +1,136 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+func TestFileCheckpoint(t *testing.T) {
+	c := FileCheckpoint{Dir: filepath.Join(t.TempDir(), "checkpoints")}
+
+	line, err := c.Load("s3://bucket/orders.csv")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), line)
+
+	assert.NoError(t, c.Save("s3://bucket/orders.csv", 42))
+
+	line, err = c.Load("s3://bucket/orders.csv")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), line)
+
+	assert.NoError(t, c.Clear("s3://bucket/orders.csv"))
+	assert.NoError(t, c.Clear("s3://bucket/orders.csv"))
+
+	line, _ = c.Load("s3://bucket/orders.csv")
+
+	assert.Equal(t, int64(0), line)
+}
+
+func TestProcessor_Resume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.csv")
+	_ = os.WriteFile(path, []byte("1\n2\n3\n4\n5\n"), 0o600)
+
+	checkpoint := FileCheckpoint{Dir: t.TempDir()}
+	p := Processor{Logger: log.NewMockLogger(io.Discard), Checkpoint: checkpoint, CheckpointInterval: 2}
+
+	var processed []string
+
+	failAt := "4"
+	handler := func(_ context.Context, line []byte) error {
+		if string(line) == failAt {
+			return errors.New("unavailable")
+		}
+
+		processed = append(processed, string(line))
+
+		return nil
+	}
+
+	result, err := p.Process(context.Background(), LocalSource{Path: path}, handler)
+
+	var lineErr LineError
+
+	assert.ErrorAs(t, err, &lineErr)
+	assert.Equal(t, int64(4), lineErr.Line)
+	assert.Equal(t, Result{Processed: 3}, result)
+
+	line, _ := checkpoint.Load(path)
+	assert.Equal(t, int64(3), line, "progress until the failed line should be saved")
+
+	failAt = ""
+
+	result, err = p.Process(context.Background(), LocalSource{Path: path}, handler)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Processed: 2, Resumed: 3}, result)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, processed)
+
+	line, _ = checkpoint.Load(path)
+	assert.Equal(t, int64(0), line, "checkpoint should be cleared after the file is processed")
+}
+
+func TestProcessor_Retry(t *testing.T) {
+	attempts := 0
+	p := Processor{Retries: 2, RetryBackoff: time.Millisecond}
+
+	src := &S3Source{Client: &mockClient{}, Bucket: "test-bucket-zs", Key: "fetch.txt"}
+
+	result, err := p.Process(context.Background(), src, func(_ context.Context, line []byte) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("unavailable")
+		}
+
+		assert.Equal(t, "Successful fetch", string(line))
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, Result{Processed: 1}, result)
+}
+
+func TestProcessor_OpenError(t *testing.T) {
+	p := Processor{Retries: 1, RetryBackoff: time.Millisecond}
+
+	_, err := p.Process(context.Background(), &S3Source{Client: &mockClient{}, Bucket: "unknown", Key: "a.txt"},
+		func(context.Context, []byte) error { return nil })
+
+	assert.Error(t, err)
+}
+
+func TestProcessor_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := Processor{}
+
+	_, err := p.Process(ctx, &memorySource{data: "a\nb\n"}, func(context.Context, []byte) error { return nil })
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+type memorySource struct {
+	data string
+}
+
+func (g *memorySource) Name() string {
+	return "memory"
+}
+
+func (g *memorySource) Open(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(g.data)), nil
+}
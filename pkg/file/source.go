@@ -0,0 +1,89 @@
+package file
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"google.golang.org/api/option"
+)
+
+// Source is a file which can be streamed by the Processor.
+type Source interface {
+	// Name identifies the file in the checkpoints, metrics and logs.
+	Name() string
+	// Open returns a reader for the contents of the file, it is closed by the caller.
+	Open(ctx context.Context) (io.ReadCloser, error)
+}
+
+// LocalSource streams a file of the local file system.
+type LocalSource struct {
+	Path string
+}
+
+func (l LocalSource) Name() string {
+	return l.Path
+}
+
+func (l LocalSource) Open(context.Context) (io.ReadCloser, error) {
+	return os.Open(filepath.Clean(l.Path))
+}
+
+// S3Source streams an object of an AWS S3 bucket, without downloading it to a temporary file.
+type S3Source struct {
+	Client S3Client
+	Bucket string
+	Key    string
+}
+
+// NewS3Source returns a source for the object with the key in the bucket of the config.
+func NewS3Source(c *AWSConfig, key string) *S3Source {
+	client := s3.New(s3.Options{
+		Credentials: credentials.NewStaticCredentialsProvider(c.AccessKey, c.SecretKey, c.Token),
+		Region:      c.Region,
+	})
+
+	return &S3Source{Client: client, Bucket: c.Bucket, Key: key}
+}
+
+func (s *S3Source) Name() string {
+	return "s3://" + s.Bucket + "/" + s.Key
+}
+
+func (s *S3Source) Open(ctx context.Context) (io.ReadCloser, error) {
+	resp, err := s.Client.GetObject(ctx, &s3.GetObjectInput{Bucket: &s.Bucket, Key: &s.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// GCSSource streams an object of a Google Cloud Storage bucket, without downloading it to a temporary file.
+type GCSSource struct {
+	Client *storage.Client
+	Bucket string
+	Object string
+}
+
+// NewGCSSource returns a source for the object in the bucket of the config.
+func NewGCSSource(ctx context.Context, c *GCPConfig, object string) (*GCSSource, error) {
+	client, err := storage.NewClient(ctx, option.WithCredentialsFile(c.GCPKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GCSSource{Client: client, Bucket: c.BucketName, Object: object}, nil
+}
+
+func (g *GCSSource) Name() string {
+	return "gs://" + g.Bucket + "/" + g.Object
+}
+
+func (g *GCSSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	return g.Client.Bucket(g.Bucket).Object(g.Object).NewReader(ctx)
+}
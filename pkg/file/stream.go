@@ -0,0 +1,137 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+//nolint:gochecknoglobals // gzipMagic is used as a constant
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// LineReader reads a stream line by line, without loading the whole stream in memory. Gzip compressed streams
+// are decompressed transparently, so that the same job can process both compressed and plain files.
+type LineReader struct {
+	r      *bufio.Reader
+	closer io.Closer
+	line   []byte
+	number int64
+	err    error
+}
+
+// NewLineReader returns a LineReader for r. The stream is decompressed when it starts with the gzip header.
+func NewLineReader(r io.Reader) (*LineReader, error) {
+	br := bufio.NewReader(r)
+
+	header, err := br.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if !bytes.Equal(header, gzipMagic) {
+		return &LineReader{r: br}, nil
+	}
+
+	gz, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LineReader{r: bufio.NewReader(gz), closer: gz}, nil
+}
+
+// Next advances the reader to the next line, it returns false when the stream ends or an error occurs.
+func (l *LineReader) Next() bool {
+	if l.err != nil {
+		return false
+	}
+
+	line, err := l.r.ReadBytes('\n')
+
+	switch {
+	case err == io.EOF && len(line) == 0:
+		l.err = io.EOF
+		return false
+	case err != nil && err != io.EOF:
+		l.err = err
+		return false
+	}
+
+	l.line = bytes.TrimRight(line, "\r\n")
+	l.number++
+
+	return true
+}
+
+// Bytes returns the current line without the line terminator. The slice is valid until the next call to Next.
+func (l *LineReader) Bytes() []byte {
+	return l.line
+}
+
+// Text returns the current line without the line terminator.
+func (l *LineReader) Text() string {
+	return string(l.line)
+}
+
+// LineNumber returns the number of the current line, starting from 1.
+func (l *LineReader) LineNumber() int64 {
+	return l.number
+}
+
+// Err returns the error encountered while reading, it returns nil when the stream ended.
+func (l *LineReader) Err() error {
+	if l.err == io.EOF {
+		return nil
+	}
+
+	return l.err
+}
+
+// Close closes the decompressor of the stream, the underlying reader is not closed.
+func (l *LineReader) Close() error {
+	if l.closer != nil {
+		return l.closer.Close()
+	}
+
+	return nil
+}
+
+// LineWriter writes lines to a stream through a buffer, optionally compressing them with gzip.
+type LineWriter struct {
+	w  *bufio.Writer
+	gz *gzip.Writer
+}
+
+// NewLineWriter returns a LineWriter for w, the lines are gzip compressed when compress is true.
+func NewLineWriter(w io.Writer, compress bool) *LineWriter {
+	if !compress {
+		return &LineWriter{w: bufio.NewWriter(w)}
+	}
+
+	gz := gzip.NewWriter(w)
+
+	return &LineWriter{w: bufio.NewWriter(gz), gz: gz}
+}
+
+// WriteLine writes the line followed by a line terminator.
+func (l *LineWriter) WriteLine(line []byte) error {
+	if _, err := l.w.Write(line); err != nil {
+		return err
+	}
+
+	return l.w.WriteByte('\n')
+}
+
+// Close flushes the buffered lines and completes the gzip stream, the underlying writer is not closed.
+func (l *LineWriter) Close() error {
+	if err := l.w.Flush(); err != nil {
+		return err
+	}
+
+	if l.gz != nil {
+		return l.gz.Close()
+	}
+
+	return nil
+}
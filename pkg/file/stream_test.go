@@ -0,0 +1,64 @@
+package file
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineReader(t *testing.T) {
+	var gz bytes.Buffer
+
+	w := NewLineWriter(&gz, true)
+	_ = w.WriteLine([]byte("first"))
+	_ = w.WriteLine([]byte("second"))
+	_ = w.Close()
+
+	tests := []struct {
+		desc  string
+		input []byte
+		lines []string
+	}{
+		{"plain text", []byte("first\nsecond\n"), []string{"first", "second"}},
+		{"windows line endings without trailing newline", []byte("first\r\nsecond"), []string{"first", "second"}},
+		{"gzip compressed", gz.Bytes(), []string{"first", "second"}},
+		{"empty", []byte{}, nil},
+	}
+
+	for i, tc := range tests {
+		r, err := NewLineReader(bytes.NewReader(tc.input))
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		var lines []string
+
+		for r.Next() {
+			lines = append(lines, r.Text())
+
+			assert.Equal(t, int64(len(lines)), r.LineNumber(), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+
+		assert.NoError(t, r.Err(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.lines, lines, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.NoError(t, r.Close(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestLineReader_InvalidGzip(t *testing.T) {
+	_, err := NewLineReader(strings.NewReader("\x1f\x8b"))
+
+	assert.Error(t, err)
+}
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := NewLineWriter(&buf, false)
+
+	assert.NoError(t, w.WriteLine([]byte("a,b")))
+	assert.NoError(t, w.WriteLine([]byte("c,d")))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "a,b\nc,d\n", buf.String())
+}
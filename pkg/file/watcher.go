@@ -0,0 +1,256 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"gofr.dev/pkg/log"
+)
+
+const defaultWatchInterval = time.Minute
+
+// Lister lists the files of a directory, it is implemented by the Storage of all the file stores.
+type Lister interface {
+	List(directory string) ([]string, error)
+}
+
+// EventPublisher publishes the events of the watcher, it is implemented by pubsub.PublisherSubscriber.
+type EventPublisher interface {
+	PublishEvent(key string, value interface{}, headers map[string]string) error
+}
+
+// Event denotes a new file which appeared in the watched directory.
+type Event struct {
+	Directory  string    `json:"directory"`
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// Tracker tracks the files for which an event has been emitted, so that an event is emitted only once for a file,
+// even across restarts when the tracker is persistent.
+type Tracker interface {
+	IsProcessed(path string) (bool, error)
+	MarkProcessed(path string) error
+}
+
+// Watcher polls a directory of a file store, ex: FTP or SFTP, and emits an event for every new file whose name
+// matches the pattern. It is meant for ingesting the files dropped by partners, who cannot notify the application.
+type Watcher struct {
+	Lister    Lister
+	Directory string
+	// Pattern is the shell pattern matched against the file names, ex: orders-*.csv. All the files match an empty pattern.
+	Pattern string
+	// Interval is the time between two polls. Default is 1 minute.
+	Interval time.Duration
+	// Tracker tracks the processed files, the files are tracked in memory when it is nil.
+	Tracker Tracker
+	Logger  log.Logger
+
+	once sync.Once
+}
+
+func (w *Watcher) init() {
+	w.once.Do(func() {
+		if w.Tracker == nil {
+			w.Tracker = NewMemoryTracker()
+		}
+
+		if w.Interval <= 0 {
+			w.Interval = defaultWatchInterval
+		}
+	})
+}
+
+// Poll lists the directory once, and returns the events of the new files, sorted by name. The files are not marked
+// as processed, Watch and Publish mark them once their events are delivered.
+func (w *Watcher) Poll() ([]Event, error) {
+	w.init()
+
+	names, err := w.Lister.List(w.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0)
+
+	for _, name := range names {
+		name = path.Base(name)
+
+		if w.Pattern != "" {
+			if ok, err := filepath.Match(w.Pattern, name); err != nil || !ok {
+				continue
+			}
+		}
+
+		p := path.Join(w.Directory, name)
+
+		processed, err := w.Tracker.IsProcessed(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if !processed {
+			events = append(events, Event{Directory: w.Directory, Name: name, Path: p, DetectedAt: time.Now()})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Name < events[j].Name })
+
+	return events, nil
+}
+
+// Watch polls the directory until the context is done, and sends the events of the new files on the returned
+// channel. The channel is closed once the watcher stops.
+func (w *Watcher) Watch(ctx context.Context) <-chan Event {
+	events := make(chan Event)
+
+	go func() {
+		defer close(events)
+
+		w.run(ctx, func(e Event) error {
+			select {
+			case events <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	return events
+}
+
+// Publish polls the directory until the context is done, and publishes the events of the new files using the
+// publisher, with the path of the file as the key. A file whose event could not be published is retried in the
+// next poll.
+func (w *Watcher) Publish(ctx context.Context, publisher EventPublisher) {
+	w.run(ctx, func(e Event) error {
+		return publisher.PublishEvent(e.Path, e, map[string]string{"directory": e.Directory})
+	})
+}
+
+func (w *Watcher) run(ctx context.Context, emit func(Event) error) {
+	w.init()
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.emit(emit)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) emit(emit func(Event) error) {
+	events, err := w.Poll()
+	if err != nil {
+		w.logf("listing %v failed: %v", w.Directory, err)
+		return
+	}
+
+	for _, e := range events {
+		if err := emit(e); err != nil {
+			w.logf("event of %v could not be emitted: %v", e.Path, err)
+			return
+		}
+
+		if err := w.Tracker.MarkProcessed(e.Path); err != nil {
+			w.logf("%v could not be marked as processed: %v", e.Path, err)
+		}
+	}
+}
+
+func (w *Watcher) logf(format string, args ...interface{}) {
+	if w.Logger != nil {
+		w.Logger.Errorf(format, args...)
+	}
+}
+
+// MemoryTracker tracks the processed files in memory, hence the files are emitted again after a restart.
+type MemoryTracker struct {
+	mu    sync.RWMutex
+	paths map[string]struct{}
+}
+
+func NewMemoryTracker() *MemoryTracker {
+	return &MemoryTracker{paths: make(map[string]struct{})}
+}
+
+func (m *MemoryTracker) IsProcessed(path string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.paths[path]
+
+	return ok, nil
+}
+
+func (m *MemoryTracker) MarkProcessed(path string) error {
+	m.mu.Lock()
+	m.paths[path] = struct{}{}
+	m.mu.Unlock()
+
+	return nil
+}
+
+// FileTracker tracks the processed files in a local file, with a path on every line.
+type FileTracker struct {
+	memory *MemoryTracker
+	file   *os.File
+	mu     sync.Mutex
+}
+
+// NewFileTracker returns a tracker which appends the processed files to the file at name, the files which are
+// already present in it are considered processed.
+func NewFileTracker(name string) (*FileTracker, error) {
+	f, err := os.OpenFile(filepath.Clean(name), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &FileTracker{memory: NewMemoryTracker(), file: f}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		_ = t.memory.MarkProcessed(scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (f *FileTracker) IsProcessed(path string) (bool, error) {
+	return f.memory.IsProcessed(path)
+}
+
+func (f *FileTracker) MarkProcessed(path string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.file.WriteString(path + "\n"); err != nil {
+		return err
+	}
+
+	return f.memory.MarkProcessed(path)
+}
+
+// Close closes the file of the tracker.
+func (f *FileTracker) Close() error {
+	return f.file.Close()
+}
@@ -0,0 +1,161 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockLister struct {
+	mu    sync.Mutex
+	files []string
+	err   error
+}
+
+func (m *mockLister) List(string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]string(nil), m.files...), m.err
+}
+
+func (m *mockLister) add(name string) {
+	m.mu.Lock()
+	m.files = append(m.files, name)
+	m.mu.Unlock()
+}
+
+type mockPublisher struct {
+	mu     sync.Mutex
+	keys   []string
+	failed bool
+}
+
+func (m *mockPublisher) PublishEvent(key string, _ interface{}, _ map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failed {
+		m.failed = false
+		return errors.New("broker unavailable")
+	}
+
+	m.keys = append(m.keys, key)
+
+	return nil
+}
+
+func (m *mockPublisher) published() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]string(nil), m.keys...)
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	tracker := NewMemoryTracker()
+	_ = tracker.MarkProcessed("/inbound/orders-1.csv")
+
+	tests := []struct {
+		desc    string
+		pattern string
+		files   []string
+		err     error
+		paths   []string
+	}{
+		{"all files", "", []string{"b.txt", "orders-2.csv"}, nil, []string{"/inbound/b.txt", "/inbound/orders-2.csv"}},
+		{"pattern", "orders-*.csv", []string{"orders-3.csv", "b.txt", "orders-2.csv"}, nil,
+			[]string{"/inbound/orders-2.csv", "/inbound/orders-3.csv"}},
+		{"processed files", "", []string{"orders-1.csv"}, nil, []string{}},
+		{"listing error", "", nil, errors.New("connection reset"), nil},
+	}
+
+	for i, tc := range tests {
+		w := Watcher{Lister: &mockLister{files: tc.files, err: tc.err}, Directory: "/inbound", Pattern: tc.pattern, Tracker: tracker}
+
+		events, err := w.Poll()
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		var paths []string
+
+		if events != nil {
+			paths = make([]string, 0)
+		}
+
+		for _, e := range events {
+			paths = append(paths, e.Path)
+		}
+
+		assert.Equal(t, tc.paths, paths, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestWatcher_Watch(t *testing.T) {
+	lister := &mockLister{files: []string{"orders-1.csv"}}
+	w := Watcher{Lister: lister, Directory: "/inbound", Interval: 10 * time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := w.Watch(ctx)
+
+	assert.Equal(t, "/inbound/orders-1.csv", (<-events).Path)
+
+	lister.add("orders-2.csv")
+
+	select {
+	case e := <-events:
+		assert.Equal(t, "/inbound/orders-2.csv", e.Path, "a processed file is emitted again")
+	case <-time.After(time.Second):
+		t.Fatal("new file is not emitted")
+	}
+
+	cancel()
+
+	_, open := <-events
+	for open {
+		_, open = <-events
+	}
+}
+
+func TestWatcher_Publish(t *testing.T) {
+	publisher := &mockPublisher{failed: true}
+	w := Watcher{Lister: &mockLister{files: []string{"orders-1.csv"}}, Directory: "/inbound", Interval: 10 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	w.Publish(ctx, publisher)
+
+	assert.Equal(t, []string{"/inbound/orders-1.csv"}, publisher.published(), "failed event is not retried exactly once")
+}
+
+func TestFileTracker(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "processed")
+
+	tracker, err := NewFileTracker(name)
+	assert.NoError(t, err)
+
+	assert.NoError(t, tracker.MarkProcessed("/inbound/orders-1.csv"))
+	assert.NoError(t, tracker.Close())
+
+	tracker, err = NewFileTracker(name)
+	assert.NoError(t, err)
+
+	defer tracker.Close()
+
+	processed, err := tracker.IsProcessed("/inbound/orders-1.csv")
+
+	assert.NoError(t, err)
+	assert.True(t, processed, "processed files are not restored")
+
+	processed, _ = tracker.IsProcessed("/inbound/orders-2.csv")
+
+	assert.False(t, processed)
+}
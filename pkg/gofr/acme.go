@@ -0,0 +1,92 @@
+package gofr
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const defaultACMECacheDir = "certs"
+
+// ACME provisions and renews the certificates of the HTTPS server from an ACME certificate authority, ex: Let's
+// Encrypt, so that the application serves HTTPS without a load balancer in front of it. The domains are validated
+// using the TLS-ALPN-01 challenge on the HTTPS port, and the HTTP-01 challenge on the HTTP port, which must be 80
+// for the HTTP-01 challenge.
+type ACME struct {
+	// Domains are the domains for which the certificates are requested, the certificates of the other domains are
+	// not requested.
+	Domains []string
+	// Email is the contact of the account, to which the CA sends the notices about the certificates.
+	Email string
+	// DirectoryURL is the directory of the CA, ex: the staging directory of Let's Encrypt. The production directory of
+	// Let's Encrypt is used when it is empty.
+	DirectoryURL string
+	// Cache stores the certificates and the account key, so that they are not requested again when the application is
+	// restarted, ex: in an object store shared by the instances. The directory CacheDir is used when it is nil.
+	Cache    autocert.Cache
+	CacheDir string
+
+	once    sync.Once
+	manager *autocert.Manager
+}
+
+// Manager returns the certificate manager, which is created on the first call, hence the config must not be changed
+// after the server is started.
+func (a *ACME) Manager() *autocert.Manager {
+	a.once.Do(func() {
+		cache := a.Cache
+		if cache == nil {
+			dir := a.CacheDir
+			if dir == "" {
+				dir = defaultACMECacheDir
+			}
+
+			cache = autocert.DirCache(dir)
+		}
+
+		a.manager = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      cache,
+			HostPolicy: autocert.HostWhitelist(a.Domains...),
+			Email:      a.Email,
+		}
+
+		if a.DirectoryURL != "" {
+			a.manager.Client = &acme.Client{DirectoryURL: a.DirectoryURL}
+		}
+	})
+
+	return a.manager
+}
+
+// tlsConfig sets the certificates of the manager on the config, and enables the TLS-ALPN-01 challenge.
+func (a *ACME) tlsConfig(cfg *tls.Config) *tls.Config {
+	cfg = cfg.Clone()
+	cfg.GetCertificate = a.Manager().GetCertificate
+	cfg.NextProtos = append([]string{"h2", "http/1.1"}, acme.ALPNProto)
+
+	return cfg
+}
+
+// httpHandler serves the HTTP-01 challenges, the other requests are served by the handler.
+func (a *ACME) httpHandler(handler http.Handler) http.Handler {
+	return a.Manager().HTTPHandler(handler)
+}
+
+// getACME returns the ACME config of the domains ACME_DOMAINS, or nil when the certificates are not managed.
+func getACME(c Config) *ACME {
+	domains := splitList(c.Get("ACME_DOMAINS"))
+	if len(domains) == 0 {
+		return nil
+	}
+
+	return &ACME{
+		Domains:      domains,
+		Email:        c.Get("ACME_EMAIL"),
+		DirectoryURL: c.Get("ACME_DIRECTORY_URL"),
+		CacheDir:     c.GetOrDefault("ACME_CACHE_DIR", defaultACMECacheDir),
+	}
+}
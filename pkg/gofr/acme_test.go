@@ -0,0 +1,78 @@
+package gofr
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"gofr.dev/pkg/gofr/config"
+)
+
+func TestGetACME(t *testing.T) {
+	tests := []struct {
+		desc     string
+		data     map[string]string
+		expected *ACME
+	}{
+		{"certificates are not managed", map[string]string{"ACME_EMAIL": "ops@example.com"}, nil},
+		{"default cache", map[string]string{"ACME_DOMAINS": "example.com, www.example.com", "ACME_EMAIL": "ops@example.com"},
+			&ACME{Domains: []string{"example.com", "www.example.com"}, Email: "ops@example.com", CacheDir: defaultACMECacheDir}},
+		{"staging directory", map[string]string{"ACME_DOMAINS": "example.com", "ACME_CACHE_DIR": "/var/certs",
+			"ACME_DIRECTORY_URL": "https://acme-staging-v02.api.letsencrypt.org/directory"},
+			&ACME{Domains: []string{"example.com"}, CacheDir: "/var/certs",
+				DirectoryURL: "https://acme-staging-v02.api.letsencrypt.org/directory"}},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.expected, getACME(&config.MockConfig{Data: tc.data}), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestACME_Manager(t *testing.T) {
+	a := &ACME{Domains: []string{"example.com"}, CacheDir: t.TempDir(), DirectoryURL: "https://ca.example.com/directory"}
+	m := a.Manager()
+
+	assert.Same(t, m, a.Manager(), "the manager must be created once")
+	assert.Equal(t, autocert.DirCache(a.CacheDir), m.Cache)
+	assert.Equal(t, "https://ca.example.com/directory", m.Client.DirectoryURL)
+	assert.NoError(t, m.HostPolicy(nil, "example.com"))
+	assert.Error(t, m.HostPolicy(nil, "other.com"), "the certificates of the other domains must not be requested")
+}
+
+func TestACME_TLSConfig(t *testing.T) {
+	a := &ACME{Domains: []string{"example.com"}, CacheDir: t.TempDir()}
+	base := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	cfg := a.tlsConfig(base)
+
+	assert.NotNil(t, cfg.GetCertificate)
+	assert.Contains(t, cfg.NextProtos, acme.ALPNProto, "TLS-ALPN-01 challenge must be enabled")
+	assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+	assert.Nil(t, base.GetCertificate, "the config of the server must not be changed")
+}
+
+func TestACME_HTTPHandler(t *testing.T) {
+	a := &ACME{Domains: []string{"example.com"}, CacheDir: t.TempDir()}
+	h := a.httpHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusTeapot) }))
+
+	tests := []struct {
+		desc   string
+		target string
+		status int
+	}{
+		{"request served by the handler", "http://example.com/hello", http.StatusTeapot},
+		{"unknown challenge", "http://example.com/.well-known/acme-challenge/token", http.StatusNotFound},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, http.NoBody))
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
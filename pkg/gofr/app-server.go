@@ -3,6 +3,7 @@ package gofr
 import (
 	ctx "context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"regexp"
@@ -11,19 +12,23 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/context"
 
 	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
 	"gofr.dev/pkg/errors"
-	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/errortracker"
+	"gofr.dev/pkg/gofr/metrics"
 	"gofr.dev/pkg/gofr/responder"
 	"gofr.dev/pkg/log"
 	"gofr.dev/pkg/middleware"
 	"gofr.dev/pkg/middleware/oauth"
+	"gofr.dev/pkg/middleware/openapi"
+	"gofr.dev/pkg/middleware/policy"
 )
 
 type server struct {
@@ -43,19 +48,51 @@ type server struct {
 	MetricsRoute  string
 	metricsServer *http.Server
 
+	// MetricsCertificateFile and MetricsKeyFile serve the metrics port over TLS when both are set, using
+	// METRICS_CERTIFICATE_FILE and METRICS_KEY_FILE, so that the client certificates of ProbeAuth can be verified.
+	MetricsCertificateFile string
+	MetricsKeyFile         string
+
+	// ProbeAuth requires a bearer token, a client certificate or an allowed network for the metrics, the pprof and the
+	// health endpoints, it is set using the PROBE_* configs. The endpoints are open when it is nil.
+	ProbeAuth *ProbeAuth
+
 	// ValidateHeaders is used to decide if we need to enforce v3 headers and headers configured using VALIDATE_HEADERS
 	// Making this false will disable this check. By default, it is set to false.
 	ValidateHeaders bool
+
+	// OpenAPIValidation validates the requests and responses against ./api/openapi.json, when set to log or reject
+	// using OPENAPI_VALIDATION. It should be enabled only in development and staging environments.
+	OpenAPIValidation openapi.Mode
+
+	// PanicReporters receive the reports of the panics recovered in the handlers. Sentry and Bugsnag are added
+	// when SENTRY_DSN and BUGSNAG_API_KEY are set, GOFR_ENV is reported as the environment.
+	PanicReporters []middleware.PanicReporter
+
+	// HeaderPolicies set and remove the response headers of the route groups, they are added using AddHeaderPolicy
+	// or the headers of the routing manifest.
+	HeaderPolicies []HeaderPolicy
+
+	// debugTraceToken authenticates the X-Debug-Trace header, the requests with the token are logged at DEBUG level
+	// and their logs are sent in the X-Debug-Logs trailer. It is set using DEBUG_TRACE_TOKEN.
+	debugTraceToken string
+
+	// devErrorPages renders the server errors of the requests from a browser as HTML pages with the details of the
+	// error, it is set when APP_ENV is dev.
+	devErrorPages bool
 }
 
 type HTTP struct {
 	Port            int
 	RedirectToHTTPS bool
+	// Config tunes the timeouts and the connections of the server, it is set using the HTTP_* configs.
+	Config ServerConfig
 }
 
 const (
 	defaultMetricsPort  = 2121
 	defaultMetricsRoute = "/metrics"
+	openAPIFile         = "./api/openapi.json"
 )
 
 // NewServer creates a new server instance with initialized properties, middleware, and configuration based on the provided parameters.
@@ -75,6 +112,10 @@ func NewServer(c Config, gofr *Gofr) *server {
 		ValidateHeaders: false,
 		MetricsPort:     defaultMetricsPort,
 		MetricsRoute:    defaultMetricsRoute,
+
+		OpenAPIValidation: openapi.Mode(strings.ToLower(c.Get("OPENAPI_VALIDATION"))),
+		debugTraceToken:   c.Get("DEBUG_TRACE_TOKEN"),
+		devErrorPages:     strings.EqualFold(c.Get("APP_ENV"), "dev"),
 	}
 
 	s.contextPool.New = func() interface{} {
@@ -106,13 +147,75 @@ func NewServer(c Config, gofr *Gofr) *server {
 	s.Router.Use(middleware.Trace(appName, appVersion, tracerExporter))
 	s.Router.Use(middleware.CORS(s.mwVars))
 	s.Router.Use(middleware.Logging(gofr.Logger, s.mwVars["LOG_OMIT_HEADERS"]))
+	// the handlers set the values of the request labels with c.Metrics().Label, up to the maximum number of values of
+	// a label, beyond which the values are recorded as "other"
+	if names := splitList(c.Get("METRICS_REQUEST_LABELS")); len(names) > 0 {
+		if err := middleware.SetRequestLabels(names, positiveInt(c, "METRICS_REQUEST_LABEL_MAX_VALUES")); err != nil {
+			gofr.Logger.Errorf("request labels %v could not be added to the metrics: %v", names, err)
+		}
+	}
+
 	s.Router.Use(middleware.PrometheusMiddleware)
 
+	// the CPU time and the allocations are attributed to the routes only when enabled, as it adds the pprof labels
+	// to every request
+	if strings.EqualFold(c.Get("ROUTE_RESOURCE_METRICS"), "true") {
+		s.Router.Use(middleware.ResourceAttribution(0))
+	}
+
+	responder.SetSerializationLimits(serializationLimits(c))
+
+	s.setupPolicies(c, gofr.Logger)
 	s.setupAuth(c, gofr)
+	s.setupPanicReporters(c, gofr.Logger)
 
 	return s
 }
 
+func (s *server) setupPanicReporters(c Config, logger log.Logger) {
+	environment, release := c.Get("GOFR_ENV"), c.Get("APP_VERSION")
+
+	if dsn := c.Get("SENTRY_DSN"); dsn != "" {
+		sentry, err := errortracker.NewSentry(dsn, c.GetOrDefault("SENTRY_ENVIRONMENT", environment), release)
+		if err != nil {
+			logger.Errorf("panics will not be reported to Sentry: %v", err)
+		} else {
+			s.PanicReporters = append(s.PanicReporters, sentry)
+		}
+	}
+
+	if apiKey := c.Get("BUGSNAG_API_KEY"); apiKey != "" {
+		s.PanicReporters = append(s.PanicReporters, errortracker.NewBugsnag(apiKey, environment, release))
+	}
+}
+
+// setupPolicies runs the Lua policies in POLICY_DIR on the requests and the responses. The policies are reloaded as they
+// change, at the interval of POLICY_RELOAD_INTERVAL seconds, and each hook runs for up to POLICY_TIMEOUT milliseconds.
+func (s *server) setupPolicies(c Config, logger log.Logger) {
+	dir := c.Get("POLICY_DIR")
+	if dir == "" {
+		return
+	}
+
+	cfg := policy.Config{Dir: dir, FailOpen: strings.EqualFold(c.Get("POLICY_FAIL_OPEN"), "true"), Logger: logger}
+
+	if interval, err := strconv.Atoi(c.Get("POLICY_RELOAD_INTERVAL")); err == nil {
+		cfg.ReloadInterval = time.Duration(interval) * time.Second
+	}
+
+	if timeout, err := strconv.Atoi(c.Get("POLICY_TIMEOUT")); err == nil {
+		cfg.Timeout = time.Duration(timeout) * time.Millisecond
+	}
+
+	mw, err := policy.New(cfg)
+	if err != nil {
+		logger.Errorf("policies are not enabled: %v", err)
+		return
+	}
+
+	s.Router.Use(mw)
+}
+
 func (s *server) setupAuth(c Config, gofr *Gofr) {
 	// OAuth
 	if oAuthOptions, oAuthOk := getOAuthOptions(c); oAuthOk {
@@ -125,15 +228,30 @@ func (s *server) setupAuth(c Config, gofr *Gofr) {
 	}
 }
 
+func (s *server) setupOpenAPIValidation(logger log.Logger) {
+	if s.OpenAPIValidation != openapi.ModeLog && s.OpenAPIValidation != openapi.ModeReject {
+		return
+	}
+
+	spec, err := openapi.LoadSpec(openAPIFile)
+	if err != nil {
+		logger.Warnf("OpenAPI validation not enabled, unable to load %v: %v", openAPIFile, err)
+		return
+	}
+
+	s.Router.Use(openapi.Validator(logger, spec, s.OpenAPIValidation))
+}
+
 func (s *server) handleMetrics(l log.Logger) {
 	if s.HTTP.Port == s.MetricsPort {
 		if r, ok := s.Router.(*router); ok {
 			l.Infof("Metrics server will run at :%v", s.HTTP.Port)
-			r.Router.Handle(s.MetricsRoute, promhttp.Handler())
+			r.Router.Handle(s.MetricsRoute, metrics.Handler())
 		}
 	} else {
 		// Start metrics server
-		s.metricsServer = metricsServer(l, s.MetricsPort, s.MetricsRoute)
+		s.metricsServer = metricsServer(l, s.MetricsPort, s.MetricsRoute, s.ProbeAuth, s.MetricsCertificateFile,
+			s.MetricsKeyFile)
 	}
 }
 
@@ -148,9 +266,13 @@ func (s *server) handleMetrics(l log.Logger) {
 func (s *server) Start(logger log.Logger) {
 	s.Router.Route(http.MethodGet, pkg.PathHealthCheck, HealthHandler)
 	s.Router.Route(http.MethodGet, pkg.PathHeartBeat, HeartBeatHandler)
+	s.Router.Route(http.MethodGet, pkg.PathReady, ReadinessHandler)
+	s.Router.Route(http.MethodGet, pkg.PathDisabledRoutes, authorizeRouteAdmin(DisabledRoutesHandler))
+	s.Router.Route(http.MethodPost, pkg.PathDisabledRoutes, authorizeRouteAdmin(DisableRouteHandler))
+	s.Router.Route(http.MethodDelete, pkg.PathDisabledRoutes, authorizeRouteAdmin(EnableRouteHandler))
 
 	// check if openapi file is present
-	if _, err := os.Stat("./api/openapi.json"); err == nil {
+	if _, err := os.Stat(openAPIFile); err == nil {
 		s.Router.Route(http.MethodGet, pkg.PathOpenAPI, OpenAPIHandler)
 
 		// routes for swagger-endpoints.
@@ -160,12 +282,31 @@ func (s *server) Start(logger log.Logger) {
 
 	s.handleMetrics(logger)
 
+	if s.ProbeAuth != nil {
+		// the routes and the config schema expose the internal details of the application, hence they are served only
+		// to the probes
+		s.Router.Route(http.MethodGet, pkg.PathRoutes, RoutesHandler)
+		s.Router.Route(http.MethodGet, pkg.PathConfig, ConfigSchemaHandler)
+		s.Router.Use(s.ProbeAuth.middleware(s.MetricsRoute))
+	}
+
 	if s.ValidateHeaders {
 		s.Router.Use(middleware.ValidateHeaders(s.mwVars["VALIDATE_HEADERS"], logger))
 	}
 
+	s.setupOpenAPIValidation(logger)
+
+	// the error page wraps the recovery middleware, so that it shows the panics as well
+	if s.devErrorPages {
+		s.Router.Use(middleware.DevErrorPage)
+	}
+
 	// call the recovery middleware
-	s.Router.Use(middleware.Recover(logger))
+	s.Router.Use(middleware.Recover(logger, s.PanicReporters...))
+
+	if len(s.HeaderPolicies) > 0 {
+		s.Router.Use(headerPolicies(s.HeaderPolicies))
+	}
 
 	// Use all user defined Middleware
 	if len(s.mws) > 0 {
@@ -192,8 +333,16 @@ func (s *server) Start(logger log.Logger) {
 	// logs all the routes of the server along with methods
 	logger.Log(fmt.Sprint(s.Router))
 
-	// Start HTTPS Server if key is present
-	if s.HTTPS.KeyFile != "" && s.HTTPS.CertificateFile != "" {
+	// Start HTTPS Server if key is present, or the certificates are managed
+	if (s.HTTPS.KeyFile != "" && s.HTTPS.CertificateFile != "") || s.HTTPS.ACME != nil {
+		if s.ProbeAuth != nil && s.ProbeAuth.ClientCAs != nil {
+			if s.HTTPS.TLSConfig == nil {
+				s.HTTPS.TLSConfig = s.HTTPS.perfectSSLScoreConfig()
+			}
+
+			s.HTTPS.TLSConfig = s.ProbeAuth.tlsConfig(s.HTTPS.TLSConfig)
+		}
+
 		go s.HTTPS.StartServer(logger, s.Router)
 	}
 
@@ -211,21 +360,22 @@ func (s *server) Start(logger log.Logger) {
 		if s.HTTP.RedirectToHTTPS {
 			logger.Logf("starting http redirect server at :%v", s.HTTP.Port)
 
-			//nolint:gosec // not setting readheader timeout.
-			srv = &http.Server{
-				Addr:    ":" + strconv.Itoa(s.HTTP.Port),
-				Handler: http.HandlerFunc(s.redirectHandler),
-			}
-			err = srv.ListenAndServe()
+			srv = s.HTTP.Config.server(":"+strconv.Itoa(s.HTTP.Port), http.HandlerFunc(s.redirectHandler))
 		} else {
-			addr := ":" + strconv.Itoa(s.HTTP.Port)
-			logger.Logf("starting http server at %s", addr)
-			//nolint:gosec // noreadtimeoout will be set as of now.
-			srv = &http.Server{
-				Addr:    addr,
-				Handler: s.Router,
-			}
-			err = srv.ListenAndServe()
+			logger.Logf("starting http server at :%v", s.HTTP.Port)
+
+			srv = s.HTTP.Config.server(":"+strconv.Itoa(s.HTTP.Port), s.Router)
+		}
+
+		// the HTTP-01 challenges of the managed certificates are served on the HTTP server
+		if s.HTTPS.ACME != nil {
+			srv.Handler = s.HTTPS.ACME.httpHandler(srv.Handler)
+		}
+
+		var listener net.Listener
+
+		if listener, err = s.HTTP.Config.listen(s.HTTP.Port); err == nil {
+			err = srv.Serve(listener)
 		}
 
 		if err != nil {
@@ -270,10 +420,9 @@ const appData middleware.LogDataKey = "appLogData"
 func (s *server) contextInjector(inner http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		c := s.contextPool.Get().(*Context)
-		c.reset(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r))
-		*r = *r.Clone(ctx.WithValue(r.Context(), appData, &sync.Map{}))
-		c.Context = r.Context()
-		*r = *r.Clone(ctx.WithValue(c.Context, gofrContextkey, c))
+		c.httpResp.Reset(w, r)
+		c.httpReq.Reset(r)
+		c.reset(&c.httpResp, &c.httpReq)
 
 		correlationID := r.Header.Get("X-B3-TraceID")
 		if correlationID == "" {
@@ -284,14 +433,79 @@ func (s *server) contextInjector(inner http.Handler) http.Handler {
 			correlationID = trace.SpanFromContext(r.Context()).SpanContext().TraceID().String()
 		}
 
-		c.Logger = log.NewCorrelationLogger(correlationID)
+		var capture *debugCapture
+
+		// the traced requests are logged at DEBUG level, and their logs are sent back in the trailer
+		switch devLogs := middleware.DevErrorLogs(r.Context()); {
+		case s.isDebugTraced(r):
+			capture = &debugCapture{}
+			c.Logger = log.NewDebugLogger(correlationID, capture)
+
+			announceDebugLogs(w)
+		case devLogs != nil:
+			// the logs are shown in the error page of the request
+			c.Logger = log.NewCaptureLogger(correlationID, devLogs)
+		default:
+			c.Logger = log.NewCorrelationLogger(correlationID)
+		}
+
+		log.SetSpan(c.Logger, trace.SpanFromContext(r.Context()).SpanContext())
+		addRequestLogData(c)
+
+		// the logger is carried by the context, so that the logs of the datastores called with the context carry the
+		// data of the request as well
+		c.Context = log.NewContext(ctx.WithValue(r.Context(), appData, &sync.Map{}), c.Logger)
+
+		// the values of the request listed in CONTEXT_EXPORT are forwarded to the downstream services
+		if c.Gofr != nil {
+			c.Context = exportContext(c, c.Gofr.contextExports)
+		}
+
+		// the SQL queries of the request are tagged with its route, when the query tags are enabled
+		if route := mux.CurrentRoute(r); route != nil {
+			if path, err := route.GetPathTemplate(); err == nil {
+				c.Context = datastore.WithQueryRoute(c.Context, path)
+			}
+		}
+
+		cancel := c.withDeadline()
+		// WithContext makes a shallow copy of the request, unlike Clone which copies the headers as well, the request
+		// of the caller is not modified, as the outer middlewares can still be reading it
+		r = r.WithContext(ctx.WithValue(c.Context, gofrContextkey, c))
+		c.httpReq.Reset(r)
 
 		inner.ServeHTTP(w, r)
 
+		cancel()
+
+		if capture != nil {
+			writeDebugLogs(w, capture)
+		}
+
 		s.contextPool.Put(c)
 	})
 }
 
+// addRequestLogData adds the route, the tenant and the authenticated subject of the request to the logger of the
+// context, so that they are logged without being added in the handlers.
+func addRequestLogData(c *Context) {
+	l, r := c.Logger, c.Request()
+
+	if route := mux.CurrentRoute(r); route != nil {
+		if path, err := route.GetPathTemplate(); err == nil {
+			l.AddData("route", path)
+		}
+	}
+
+	if tenant, ok := r.Context().Value(middleware.TenantIDKey).(string); ok {
+		l.AddData("tenantID", tenant)
+	}
+
+	if subject := authenticatedSubject(c); subject != "" {
+		l.AddData("subject", subject)
+	}
+}
+
 func (s *server) wsConnCreate(inner http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		c := s.contextPool.Get().(*Context)
@@ -375,7 +589,7 @@ func (s *server) removePathParamValueFromTraces() func(inner http.Handler) http.
 
 			if route, ok := isEndpointWithPathParam(routeMap, r.URL.Path); ok {
 				//nolint // cannot create custom type as it will result in import cycle
-				*r = *r.Clone(ctx.WithValue(r.Context(), "path", route))
+				r = r.WithContext(ctx.WithValue(r.Context(), "path", route))
 			}
 
 			inner.ServeHTTP(w, r)
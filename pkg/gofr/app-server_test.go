@@ -10,10 +10,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 
 	"gofr.dev/pkg/gofr/config"
 	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
 	"gofr.dev/pkg/middleware/oauth"
 )
 
@@ -36,6 +38,58 @@ func TestContextInjector(t *testing.T) {
 	assert.NotNil(t, s.contextPool.Get())
 }
 
+func TestContextInjector_ReusesContext(t *testing.T) {
+	s := &server{}
+	s.contextPool.New = func() interface{} {
+		return NewContext(nil, nil, &Gofr{})
+	}
+
+	var headers []http.Header
+
+	handler := s.contextInjector(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, _ := r.Context().Value(gofrContextkey).(*Context)
+
+		assert.Equal(t, r.URL.Path, c.Request().URL.Path)
+
+		headers = append(headers, r.Header)
+
+		c.resp.Respond(c.PathParam("id"), nil)
+	}))
+
+	for _, path := range []string{"/first", "/second"} {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		req.Header.Set("X-Correlation-ID", "abc")
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "abc", req.Header.Get("X-Correlation-ID"))
+	}
+
+	// the headers are not copied when the context of the request is replaced
+	assert.Len(t, headers, 2)
+}
+
+func BenchmarkContextInjector(b *testing.B) {
+	s := &server{}
+	s.contextPool.New = func() interface{} {
+		return NewContext(nil, nil, &Gofr{})
+	}
+
+	handler := s.contextInjector(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, _ := r.Context().Value(gofrContextkey).(*Context)
+		c.resp.Respond(map[string]string{"status": "ok"}, nil)
+	}))
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	}
+}
+
 func Test_getMWVars(t *testing.T) {
 	conf := &config.MockConfig{Data: map[string]string{
 		"VALIDATE_HEADERS":       "TRUE",
@@ -380,3 +434,158 @@ type MockHandler struct{}
 func (r *MockHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
 	_, _ = w.Write([]byte("test"))
 }
+
+func Test_setupOpenAPIValidation(t *testing.T) {
+	b := new(bytes.Buffer)
+	logger := log.NewMockLogger(b)
+
+	tests := []struct {
+		desc       string
+		mode       string
+		logMessage string
+	}{
+		{"validation disabled", "", ""},
+		{"unknown mode", "strict", ""},
+		{"openapi file not present", "LOG", "OpenAPI validation not enabled"},
+	}
+
+	for i, tc := range tests {
+		b.Reset()
+
+		s := NewServer(&config.MockConfig{Data: map[string]string{"OPENAPI_VALIDATION": tc.mode}}, &Gofr{Logger: logger})
+		s.setupOpenAPIValidation(logger)
+
+		if tc.logMessage == "" {
+			assert.Empty(t, b.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+			continue
+		}
+
+		assert.Contains(t, b.String(), tc.logMessage, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestServer_PanicReporters(t *testing.T) {
+	b := new(bytes.Buffer)
+	logger := log.NewMockLogger(b)
+
+	tests := []struct {
+		desc      string
+		config    map[string]string
+		reporters int
+	}{
+		{"no error tracker", map[string]string{}, 0},
+		{"sentry and bugsnag", map[string]string{"SENTRY_DSN": "https://abc@o1.ingest.sentry.io/42", "BUGSNAG_API_KEY": "key"}, 2},
+		{"invalid sentry DSN", map[string]string{"SENTRY_DSN": "https://o1.ingest.sentry.io/42"}, 0},
+	}
+
+	for i, tc := range tests {
+		s := NewServer(&config.MockConfig{Data: tc.config}, &Gofr{Logger: logger})
+
+		assert.Len(t, s.PanicReporters, tc.reporters, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.Contains(t, b.String(), "panics will not be reported to Sentry")
+}
+
+func TestServer_Policies(t *testing.T) {
+	b := new(bytes.Buffer)
+	logger := log.NewMockLogger(b)
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(dir+"/deny.lua", []byte(`function on_request(req) return {status = 403} end`), 0600))
+
+	tests := []struct {
+		desc   string
+		config map[string]string
+		status int
+	}{
+		{"no policies", map[string]string{}, http.StatusOK},
+		{"policies", map[string]string{"POLICY_DIR": dir}, http.StatusForbidden},
+		{"policy directory not found", map[string]string{"POLICY_DIR": dir + "/["}, http.StatusOK},
+	}
+
+	for i, tc := range tests {
+		s := NewServer(&config.MockConfig{Data: tc.config}, &Gofr{Logger: logger})
+		s.Router.(*router).handle(http.MethodGet, "/hello", http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+		w := httptest.NewRecorder()
+		s.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hello", http.NoBody))
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.Contains(t, b.String(), "policies are not enabled")
+}
+
+func TestContextInjector_LogData(t *testing.T) {
+	stdout := os.Stdout
+	tmpFile, _ := os.CreateTemp("", "fake-stdout.*")
+
+	defer func() {
+		os.Stdout = stdout
+		os.Remove(tmpFile.Name())
+	}()
+
+	os.Stdout = tmpFile
+
+	g := &Gofr{Config: &config.MockConfig{Data: map[string]string{"TRUSTED_GATEWAY": "true"}}}
+
+	muxRouter := logDataRouter(t, g)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/1", http.NoBody)
+	req.Header.Set("X-Correlation-ID", "b00ff8de800911ec8f6502bfe7568078")
+	req.Header.Set("X-Tenant-ID", "acme")
+	req.Header.Set("X-Authenticated-UserId", "42")
+
+	muxRouter.ServeHTTP(httptest.NewRecorder(), req)
+
+	output, _ := os.ReadFile(tmpFile.Name())
+
+	assert.Contains(t, string(output), `"route":"/orders/{id}"`)
+	assert.Contains(t, string(output), `"tenantID":"acme"`)
+	assert.Contains(t, string(output), `"subject":"42"`)
+	assert.Contains(t, string(output), `"correlationId":"b00ff8de800911ec8f6502bfe7568078"`)
+}
+
+func TestContextInjector_LogData_UntrustedSubject(t *testing.T) {
+	stdout := os.Stdout
+	tmpFile, _ := os.CreateTemp("", "fake-stdout.*")
+
+	defer func() {
+		os.Stdout = stdout
+		os.Remove(tmpFile.Name())
+	}()
+
+	os.Stdout = tmpFile
+
+	muxRouter := logDataRouter(t, &Gofr{Config: &config.MockConfig{Data: map[string]string{}}})
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/1", http.NoBody)
+	req.Header.Set("X-Authenticated-UserId", "42")
+
+	muxRouter.ServeHTTP(httptest.NewRecorder(), req)
+
+	output, _ := os.ReadFile(tmpFile.Name())
+
+	assert.Contains(t, string(output), "order fetched")
+	assert.NotContains(t, string(output), `"subject"`, "user id sent by the client is logged as the subject")
+}
+
+func logDataRouter(t *testing.T, g *Gofr) *mux.Router {
+	s := &server{}
+	s.contextPool.New = func() interface{} {
+		return NewContext(nil, nil, g)
+	}
+
+	muxRouter := mux.NewRouter()
+	muxRouter.Use(middleware.PropagateHeaders, s.contextInjector)
+	muxRouter.HandleFunc("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		c, _ := r.Context().Value(gofrContextkey).(*Context)
+
+		assert.Equal(t, c.Logger, log.FromContext(c, nil), "logger of the request is not carried by the context")
+
+		c.Logger.Info("order fetched")
+	})
+
+	return muxRouter
+}
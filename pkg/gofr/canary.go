@@ -0,0 +1,269 @@
+package gofr
+
+import (
+	"bytes"
+	ctx "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultCanaryConcurrency = 10
+	// canaryMaxBodySize is the size of the bodies of the requests for which the candidate is called, as the body is
+	// buffered for the candidate.
+	canaryMaxBodySize = 1 << 20
+)
+
+//nolint:gochecknoglobals // the metric is shared by all the canary handlers, and is labelled by their name
+var canaryComparisons = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "zs_canary_comparisons_total",
+	Help: "Counter of the comparisons of the results of the current and the candidate implementations of the handlers",
+}, []string{"name", "result"})
+
+// CanaryDiff is a difference between the results of the current and the candidate implementation of a handler.
+type CanaryDiff struct {
+	// Path is the JSON path of the differing value, ex: $.items[0].price, the errors are compared at the path $error.
+	Path      string      `json:"path"`
+	Current   interface{} `json:"current"`
+	Candidate interface{} `json:"candidate"`
+}
+
+// CanaryOption configures the comparison of the implementations of a handler.
+type CanaryOption struct {
+	// Name identifies the handler in the logs and the metrics.
+	Name string
+	// Percentage of the requests for which the candidate is called, from 0 to 100. Default is 100.
+	Percentage float64
+	// Ignore are the JSON paths which are not compared, ex: $.generatedAt, the values of which are expected to differ.
+	Ignore []string
+	// Recorder records the differences, they are logged when it is nil. It is called with the context of the candidate.
+	Recorder func(c *Context, diffs []CanaryDiff)
+	// MaxConcurrency is the number of the candidates which run at a time, the candidate is not called while the limit
+	// is reached, so that a slow candidate does not pile up goroutines. Default is 10.
+	MaxConcurrency int
+}
+
+// Canary returns a handler which calls both the current and the candidate implementation of a handler, and responds
+// with the result of the current one, while the structural differences between the results are logged and counted
+// in the metric zs_canary_comparisons_total. It allows a critical endpoint to be refactored safely, by comparing the
+// implementations on the production traffic. The candidate is called in the background after the current one returns,
+// so that it does not delay the response, with a copy of the request and its body, hence it must not have side
+// effects, ex: writes to a database, which must not happen twice. The candidate is not called for the requests whose
+// body is larger than 1 MB, or while MaxConcurrency candidates are running.
+func Canary(current, candidate Handler, opt *CanaryOption) Handler {
+	_ = prometheus.Register(canaryComparisons)
+
+	o := CanaryOption{Name: "canary", Percentage: 100, MaxConcurrency: defaultCanaryConcurrency}
+
+	if opt != nil {
+		o.Recorder, o.Ignore = opt.Recorder, opt.Ignore
+
+		if opt.Name != "" {
+			o.Name = opt.Name
+		}
+
+		if opt.Percentage > 0 {
+			o.Percentage = opt.Percentage
+		}
+
+		if opt.MaxConcurrency > 0 {
+			o.MaxConcurrency = opt.MaxConcurrency
+		}
+	}
+
+	inFlight := make(chan struct{}, o.MaxConcurrency)
+
+	return func(c *Context) (interface{}, error) {
+		//nolint:gosec // the sampling of the requests does not need a secure random number
+		if rand.Float64()*100 >= o.Percentage {
+			return current(c)
+		}
+
+		body, ok := bufferBody(c)
+
+		data, err := current(c)
+		if !ok {
+			return data, err
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			canaryComparisons.WithLabelValues(o.Name, "dropped").Inc()
+			return data, err
+		}
+
+		parent := ctx.Background()
+		if c.Context != nil {
+			parent = c.Context
+		}
+
+		// the candidate runs with a copy of the request, as the context of the request is reused once it returns
+		cc := &Context{Context: ctx.WithoutCancel(parent), Gofr: c.Gofr, Logger: c.Logger, req: detachedRequest(c, body)}
+
+		go func() {
+			defer func() { <-inFlight }()
+
+			o.compare(cc, candidate, data, err)
+		}()
+
+		return data, err
+	}
+}
+
+// compare calls the candidate, and records the differences between its result and the result of the current one.
+func (o *CanaryOption) compare(c *Context, candidate Handler, data interface{}, err error) {
+	candidateData, candidateErr := callCandidate(c, candidate)
+
+	diffs := compareResults(data, err, candidateData, candidateErr, o.Ignore)
+	if len(diffs) == 0 {
+		canaryComparisons.WithLabelValues(o.Name, "match").Inc()
+		return
+	}
+
+	canaryComparisons.WithLabelValues(o.Name, "diff").Inc()
+
+	switch {
+	case o.Recorder != nil:
+		o.Recorder(c, diffs)
+	case c.Logger != nil:
+		c.Logger.Warnf("canary %v: the candidate differs at %v paths: %+v", o.Name, len(diffs), diffs)
+	}
+}
+
+// bufferBody reads the body of the request, so that it is read again by both the current and the candidate
+// implementation, it returns false when the body is larger than canaryMaxBodySize, which is then not buffered.
+func bufferBody(c *Context) ([]byte, bool) {
+	if c.req == nil || c.req.Request() == nil {
+		return nil, true
+	}
+
+	r := c.req.Request()
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, canaryMaxBodySize+1))
+
+	// the part of the body which is read is put back, so that the current implementation reads the whole body
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+
+	return body, err == nil && len(body) <= canaryMaxBodySize
+}
+
+// callCandidate calls the candidate, a panic of which is returned as its error, so that it does not fail the request.
+func callCandidate(c *Context, candidate Handler) (data interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			data, err = nil, fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return candidate(c)
+}
+
+func compareResults(current interface{}, currentErr error, candidate interface{}, candidateErr error, ignore []string) []CanaryDiff {
+	if currentErr != nil || candidateErr != nil {
+		if errorString(currentErr) == errorString(candidateErr) {
+			return nil
+		}
+
+		return []CanaryDiff{{Path: "$error", Current: errorString(currentErr), Candidate: errorString(candidateErr)}}
+	}
+
+	ignored := make(map[string]bool, len(ignore))
+	for _, p := range ignore {
+		ignored[p] = true
+	}
+
+	var diffs []CanaryDiff
+
+	compareValues("$", normalize(current), normalize(candidate), ignored, &diffs)
+
+	return diffs
+}
+
+func errorString(err error) interface{} {
+	if err == nil {
+		return nil
+	}
+
+	return err.Error()
+}
+
+// normalize converts the value to its JSON representation, so that the results are compared as they are responded.
+func normalize(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	var n interface{}
+
+	_ = json.Unmarshal(b, &n)
+
+	return n
+}
+
+func compareValues(path string, a, b interface{}, ignored map[string]bool, diffs *[]CanaryDiff) {
+	if ignored[path] {
+		return
+	}
+
+	switch x := a.(type) {
+	case map[string]interface{}:
+		if y, ok := b.(map[string]interface{}); ok {
+			compareObjects(path, x, y, ignored, diffs)
+			return
+		}
+	case []interface{}:
+		if y, ok := b.([]interface{}); ok && len(x) == len(y) {
+			for i := range x {
+				compareValues(fmt.Sprintf("%v[%d]", path, i), x[i], y[i], ignored, diffs)
+			}
+
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*diffs = append(*diffs, CanaryDiff{Path: path, Current: a, Candidate: b})
+	}
+}
+
+func compareObjects(path string, a, b map[string]interface{}, ignored map[string]bool, diffs *[]CanaryDiff) {
+	keys := make([]string, 0, len(a)+len(b))
+
+	for k := range a {
+		keys = append(keys, k)
+	}
+
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	// the keys are sorted, so that the differences are reported in the same order for every request
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := path + "." + k
+		if strings.ContainsAny(k, ".[] ") {
+			p = fmt.Sprintf("%v[%q]", path, k)
+		}
+
+		compareValues(p, a[k], b[k], ignored, diffs)
+	}
+}
@@ -0,0 +1,162 @@
+package gofr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/gofr/types"
+)
+
+type canaryOrder struct {
+	ID          int      `json:"id"`
+	Items       []string `json:"items"`
+	GeneratedAt string   `json:"generatedAt"`
+}
+
+func canaryHandler(data interface{}, err error) Handler {
+	return func(*Context) (interface{}, error) {
+		return data, err
+	}
+}
+
+// canaryRecorder returns the option whose recorder sends the differences to the channel.
+func canaryRecorder(opt *CanaryOption) (*CanaryOption, chan []CanaryDiff) {
+	diffs := make(chan []CanaryDiff, 1)
+	opt.Recorder = func(_ *Context, d []CanaryDiff) { diffs <- d }
+
+	return opt, diffs
+}
+
+// recordedDiffs waits for the differences of the candidate, which is called in the background, the differences are
+// nil when the results match.
+func recordedDiffs(diffs chan []CanaryDiff) []CanaryDiff {
+	select {
+	case d := <-diffs:
+		return d
+	case <-time.After(100 * time.Millisecond):
+		return nil
+	}
+}
+
+func TestCanary(t *testing.T) {
+	current := types.Response{Data: canaryOrder{ID: 1, Items: []string{"book", "pen"}, GeneratedAt: "10:00"}}
+
+	tests := []struct {
+		desc      string
+		candidate Handler
+		diffs     []CanaryDiff
+	}{
+		{"same result", canaryHandler(types.Response{Data: map[string]interface{}{"id": 1, "items": []string{"book", "pen"},
+			"generatedAt": "10:01"}}, nil), nil},
+		{"different values", canaryHandler(types.Response{Data: canaryOrder{ID: 2, Items: []string{"book", "ink"}}}, nil),
+			[]CanaryDiff{{Path: "$.data.id", Current: float64(1), Candidate: float64(2)},
+				{Path: "$.data.items[1]", Current: "pen", Candidate: "ink"}}},
+		{"different structure", canaryHandler(types.Response{Data: map[string]interface{}{"id": 1, "items": []string{"book"},
+			"total": 2}}, nil), []CanaryDiff{{Path: "$.data.items", Current: []interface{}{"book", "pen"}, Candidate: []interface{}{"book"}},
+			{Path: "$.data.total", Candidate: float64(2)}}},
+		{"candidate fails", canaryHandler(nil, errors.EntityNotFound{Entity: "order", ID: "1"}),
+			[]CanaryDiff{{Path: "$error", Candidate: "No 'order' found for Id: '1'"}}},
+		{"candidate panics", func(*Context) (interface{}, error) { panic("nil map") },
+			[]CanaryDiff{{Path: "$error", Candidate: "panic: nil map"}}},
+	}
+
+	for i, tc := range tests {
+		opt, diffs := canaryRecorder(&CanaryOption{Name: "orders", Ignore: []string{"$.data.generatedAt"}})
+
+		data, err := Canary(canaryHandler(current, nil), tc.candidate, opt)(NewContext(nil, nil, &Gofr{Config: &config.MockConfig{}}))
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, current, data, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.diffs, recordedDiffs(diffs), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestCanary_Errors(t *testing.T) {
+	notFound := errors.EntityNotFound{Entity: "order", ID: "1"}
+	recorder, diffs := canaryRecorder(&CanaryOption{})
+
+	_, err := Canary(canaryHandler(nil, notFound), canaryHandler(nil, notFound), recorder)(NewContext(nil, nil, &Gofr{}))
+
+	assert.Equal(t, notFound, err)
+	assert.Nil(t, recordedDiffs(diffs), "same errors are recorded as differences")
+
+	data, err := Canary(canaryHandler(nil, notFound), canaryHandler("order", nil), recorder)(NewContext(nil, nil, &Gofr{}))
+
+	assert.Nil(t, data)
+	assert.Equal(t, notFound, err, "error of the current implementation is not returned")
+	assert.Equal(t, []CanaryDiff{{Path: "$error", Current: notFound.Error()}}, recordedDiffs(diffs))
+}
+
+func TestCanary_RequestBody(t *testing.T) {
+	body := func(c *Context) (interface{}, error) {
+		var order map[string]interface{}
+
+		err := c.Bind(&order)
+
+		return order, err
+	}
+
+	opt, diffs := canaryRecorder(&CanaryOption{})
+	recorded := make(chan *Context, 1)
+	opt.Recorder = func(c *Context, d []CanaryDiff) {
+		recorded <- c
+		diffs <- d
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"item":"tea"}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	c := NewContext(nil, request.NewHTTPRequest(r), &Gofr{})
+
+	// the candidate differs by its error, so that the context it is called with is recorded
+	data, err := Canary(body, func(c *Context) (interface{}, error) {
+		data, _ := body(c)
+		return nil, fmt.Errorf("%v", data)
+	}, opt)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"item": "tea"}, data)
+	assert.Equal(t, []CanaryDiff{{Path: "$error", Candidate: "map[item:tea]"}}, recordedDiffs(diffs),
+		"candidate is not called with the body of the request")
+	assert.NotSame(t, c, <-recorded, "candidate is called with the context of the request")
+}
+
+func TestCanary_MaxConcurrency(t *testing.T) {
+	release := make(chan struct{})
+	called := make(chan struct{}, 2)
+
+	candidate := func(*Context) (interface{}, error) {
+		called <- struct{}{}
+		<-release
+
+		return "order", nil
+	}
+
+	handler := Canary(canaryHandler("order", nil), candidate, &CanaryOption{MaxConcurrency: 1})
+
+	// the response is not delayed by the candidate, which is blocked
+	for i := 0; i < 2; i++ {
+		data, err := handler(NewContext(nil, nil, &Gofr{}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "order", data)
+	}
+
+	<-called
+	close(release)
+
+	select {
+	case <-called:
+		t.Errorf("candidate is called beyond the concurrency")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
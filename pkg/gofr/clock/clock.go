@@ -0,0 +1,56 @@
+// Package clock provides the source of the current time of the application, so that the time can be frozen in tests.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock returns the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+type system struct{}
+
+func (system) Now() time.Time {
+	return time.Now()
+}
+
+// System returns the clock which reads the time of the system.
+func System() Clock {
+	return system{}
+}
+
+// Frozen is a clock whose time changes only when it is set or advanced.
+type Frozen struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// NewFrozen returns a clock frozen at t.
+func NewFrozen(t time.Time) *Frozen {
+	return &Frozen{now: t}
+}
+
+// Now returns the time at which the clock is frozen.
+func (f *Frozen) Now() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.now
+}
+
+// Set freezes the clock at t.
+func (f *Frozen) Set(t time.Time) {
+	f.mu.Lock()
+	f.now = t
+	f.mu.Unlock()
+}
+
+// Advance moves the time of the clock forward by d.
+func (f *Frozen) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
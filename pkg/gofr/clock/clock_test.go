@@ -0,0 +1,31 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	now := System().Now()
+
+	assert.False(t, now.Before(before))
+	assert.False(t, now.After(time.Now()))
+}
+
+func TestFrozen(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+	c := NewFrozen(start)
+
+	assert.Equal(t, start, c.Now())
+
+	c.Advance(time.Minute)
+
+	assert.Equal(t, start.Add(time.Minute), c.Now())
+
+	c.Set(start)
+
+	assert.Equal(t, start, c.Now())
+}
@@ -0,0 +1,158 @@
+package gofr
+
+import (
+	"sort"
+
+	"gofr.dev/pkg/gofr/config"
+)
+
+const maxTypoDistance = 2
+
+// ConfigKey is a config key of the effective config schema of the application.
+type ConfigKey struct {
+	Name string `json:"name"`
+	// Default is the default the key is read with, it is empty when the key is read without a default.
+	Default string `json:"default,omitempty"`
+	// Set is whether the key is set.
+	Set bool `json:"set"`
+	// Replacement is the key which replaces the key, when it is deprecated.
+	Replacement string `json:"replacement,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+}
+
+// DeprecateConfig marks the config key as deprecated, the key is warned when it is set, as it is replaced by the
+// replacement, ex: app.DeprecateConfig("PAYMENTS_URL", "PAYMENTS_SERVICE_URL").
+func (g *Gofr) DeprecateConfig(key, replacement string) {
+	if g.deprecatedConfigs == nil {
+		g.deprecatedConfigs = make(map[string]string)
+	}
+
+	g.deprecatedConfigs[key] = replacement
+}
+
+// ConfigSchema returns the config keys read by the framework and the application, along with the deprecated keys,
+// sorted by name. The values of the keys are not returned, as they can be secrets.
+func (g *Gofr) ConfigSchema() []ConfigKey {
+	keys := make(map[string]ConfigKey)
+
+	if t, ok := g.Config.(*config.Tracked); ok {
+		for _, k := range t.ReadKeys() {
+			keys[k.Name] = ConfigKey{Name: k.Name, Default: k.Default}
+		}
+	}
+
+	for k, replacement := range g.deprecatedConfigs {
+		key := keys[k]
+		key.Name, key.Deprecated, key.Replacement = k, true, replacement
+		keys[k] = key
+	}
+
+	schema := make([]ConfigKey, 0, len(keys))
+
+	for _, k := range keys {
+		k.Set = g.Config.Get(k.Name) != ""
+		schema = append(schema, k)
+	}
+
+	sort.Slice(schema, func(i, j int) bool { return schema[i].Name < schema[j].Name })
+
+	return schema
+}
+
+// ConfigSchemaHandler lists the config keys read by the framework and the application, with their defaults, and
+// whether they are set. It is served on /.well-known/config to the probes, when ProbeAuth is configured.
+func ConfigSchemaHandler(c *Context) (interface{}, error) {
+	return c.Gofr.ConfigSchema(), nil
+}
+
+/*
+checkConfigKeys warns about the deprecated keys which are set, and about the keys defined in the .env files which are
+not read by the framework or the application, ex: HTTP_PROT, along with the key which is likely meant. It is called
+when the application is started, so the keys which are read only after the start, ex: in the handlers, are warned too.
+*/
+func (g *Gofr) checkConfigKeys() {
+	t, ok := g.Config.(*config.Tracked)
+	if !ok {
+		return
+	}
+
+	for k, replacement := range g.deprecatedConfigs {
+		if t.Get(k) != "" {
+			g.Logger.Warnf("config %v is deprecated, use %v instead", k, replacement)
+		}
+	}
+
+	read := t.ReadKeys()
+	known := make(map[string]bool, len(read))
+
+	for _, k := range read {
+		known[k.Name] = true
+	}
+
+	for _, k := range t.DefinedKeys() {
+		if known[k] {
+			continue
+		}
+
+		if suggestion := closestKey(k, read); suggestion != "" {
+			g.Logger.Warnf("config %v is not read by the application, did you mean %v?", k, suggestion)
+		} else {
+			g.Logger.Warnf("config %v is not read by the application", k)
+		}
+	}
+}
+
+// closestKey returns the key read which is closest to the key, when it is likely a typo of it.
+func closestKey(key string, read []config.Key) string {
+	var (
+		closest  string
+		distance = maxTypoDistance + 1
+	)
+
+	for _, k := range read {
+		if d := editDistance(key, k.Name); d < distance {
+			closest, distance = k.Name, d
+		}
+	}
+
+	return closest
+}
+
+// editDistance returns the number of the insertions, deletions and substitutions which change a to b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,63 @@
+package gofr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+func TestGofr_checkConfigKeys(t *testing.T) {
+	b := new(bytes.Buffer)
+	tracked := config.NewTracked(&config.MockConfig{Data: map[string]string{"HTTP_PROT": "8000", "LOG_LEVEL": "INFO",
+		"PAYMENTS_URL": "http://payments", "ZIPKIN_HOST": "localhost"}})
+	g := &Gofr{Config: tracked, Logger: log.NewMockLogger(b)}
+
+	tracked.GetOrDefault("HTTP_PORT", "8000")
+	tracked.Get("LOG_LEVEL")
+	g.DeprecateConfig("PAYMENTS_URL", "PAYMENTS_SERVICE_URL")
+
+	g.checkConfigKeys()
+
+	assert.Contains(t, b.String(), "config HTTP_PROT is not read by the application, did you mean HTTP_PORT?")
+	assert.Contains(t, b.String(), "config ZIPKIN_HOST is not read by the application")
+	assert.Contains(t, b.String(), "config PAYMENTS_URL is deprecated, use PAYMENTS_SERVICE_URL instead")
+	assert.NotContains(t, b.String(), "config LOG_LEVEL")
+	assert.NotContains(t, b.String(), "config PAYMENTS_URL is not read", "the deprecated keys are known")
+}
+
+func TestGofr_ConfigSchema(t *testing.T) {
+	tracked := config.NewTracked(&config.MockConfig{Data: map[string]string{"HTTP_PORT": "8080"}})
+	g := &Gofr{Config: tracked}
+
+	tracked.GetOrDefault("HTTP_PORT", "8000")
+	tracked.GetOrDefault("METRICS_PORT", "2121")
+	g.DeprecateConfig("PAYMENTS_URL", "PAYMENTS_SERVICE_URL")
+
+	expected := []ConfigKey{{Name: "HTTP_PORT", Default: "8000", Set: true}, {Name: "METRICS_PORT", Default: "2121"},
+		{Name: "PAYMENTS_URL", Deprecated: true, Replacement: "PAYMENTS_SERVICE_URL"}}
+
+	resp, err := ConfigSchemaHandler(&Context{Gofr: g})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, resp)
+}
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{"HTTP_PORT", "HTTP_PORT", 0},
+		{"HTTP_PROT", "HTTP_PORT", 2},
+		{"HTTPS_PORT", "HTTP_PORT", 1},
+		{"", "APP_NAME", 8},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.distance, editDistance(tc.a, tc.b), "TEST[%d], failed.\n", i)
+	}
+}
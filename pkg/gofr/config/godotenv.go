@@ -4,6 +4,7 @@ package config
 
 import (
 	"os"
+	"sort"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +13,8 @@ type GoDotEnvProvider struct {
 	// contains unexported fields
 	configFolder string
 	logger       logger
+	// definedKeys are the keys defined by the .env files which are loaded
+	definedKeys map[string]bool
 }
 
 type logger interface {
@@ -29,6 +32,7 @@ func NewGoDotEnvProvider(l logger, configFolder string) *GoDotEnvProvider {
 	provider := &GoDotEnvProvider{
 		configFolder: configFolder,
 		logger:       l,
+		definedKeys:  make(map[string]bool),
 	}
 
 	provider.readConfig(configFolder)
@@ -55,6 +59,7 @@ func (g *GoDotEnvProvider) readConfig(confLocation string) {
 	}
 
 	if err := godotenv.Load(overrideFile); err == nil {
+		g.define(overrideFile)
 		g.logger.Log("Loaded config from file: ", overrideFile)
 	} else if gofrEnv != "" { // log an error if gofr env is set and the file could not be loaded
 		g.logger.Warnf("Failed to load config from file: %v, Err: %v", overrideFile, err)
@@ -63,10 +68,36 @@ func (g *GoDotEnvProvider) readConfig(confLocation string) {
 	if err := godotenv.Load(defaultFile); err != nil {
 		g.logger.Warnf("Failed to load config from file: %v, Err: %v", defaultFile, err)
 	} else {
+		g.define(defaultFile)
 		g.logger.Log("Loaded config from file: ", defaultFile)
 	}
 }
 
+// define records the keys defined by the .env file.
+func (g *GoDotEnvProvider) define(file string) {
+	values, err := godotenv.Read(file)
+	if err != nil {
+		return
+	}
+
+	for k := range values {
+		g.definedKeys[k] = true
+	}
+}
+
+// DefinedKeys returns the keys defined by the .env files which are loaded, sorted by name. The environment variables
+// are not included, as most of them are not meant for the application, ex: PATH.
+func (g *GoDotEnvProvider) DefinedKeys() []string {
+	keys := make([]string, 0, len(g.definedKeys))
+	for k := range g.definedKeys {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
 // Get retrieves the value of an environment variable by its key.
 func (g *GoDotEnvProvider) Get(key string) string {
 	return os.Getenv(key)
@@ -1,5 +1,7 @@
 package config
 
+import "sort"
+
 // MockConfig is a mock type that can be used for testing purposes wherever GoDotEnvProvider's methods are called
 type MockConfig struct {
 	Data map[string]string
@@ -21,3 +23,15 @@ func (m *MockConfig) GetOrDefault(key, defaultValue string) string {
 
 	return defaultValue
 }
+
+// DefinedKeys returns the keys of the Data, sorted by name.
+func (m *MockConfig) DefinedKeys() []string {
+	keys := make([]string, 0, len(m.Data))
+	for k := range m.Data {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
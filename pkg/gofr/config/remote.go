@@ -85,6 +85,16 @@ func (r *RemoteConfig) GetOrDefault(key, defaultValue string) string {
 	return value
 }
 
+// DefinedKeys returns the keys defined by the local config, the keys of the remote config server are not included, as
+// they are refreshed in the background.
+func (r *RemoteConfig) DefinedKeys() []string {
+	if d, ok := r.localConfig.(interface{ DefinedKeys() []string }); ok {
+		return d.DefinedKeys()
+	}
+
+	return nil
+}
+
 func (r *RemoteConfig) refreshConfigs() {
 	//nolint:gosec // need this to skip TLS verification
 	tr := &http.Transport{
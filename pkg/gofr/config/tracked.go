@@ -0,0 +1,91 @@
+package config
+
+import (
+	"sort"
+	"sync"
+)
+
+// Key is a config key read by the application, along with the default it is read with.
+type Key struct {
+	Name    string `json:"name"`
+	Default string `json:"default,omitempty"`
+}
+
+// keys is the record of the keys read from the configs which share it.
+type keys struct {
+	mu   sync.RWMutex
+	read map[string]string
+}
+
+// Tracked records the keys which are read from the config it wraps, so that the keys which are set, but never read, ex:
+// HTTP_PROT, are found, and the keys read by the application are listed.
+type Tracked struct {
+	config
+	keys *keys
+}
+
+// NewTracked returns the config which records the keys read from c.
+func NewTracked(c config) *Tracked {
+	return &Tracked{config: c, keys: &keys{read: make(map[string]string)}}
+}
+
+// Track returns the config which records the keys read from c along with the keys read from t, ex: for the remote config
+// which wraps t.
+func (t *Tracked) Track(c config) *Tracked {
+	return &Tracked{config: c, keys: t.keys}
+}
+
+// Get retrieves the value of the key, and records that the key is read.
+func (t *Tracked) Get(key string) string {
+	t.record(key, "")
+
+	return t.config.Get(key)
+}
+
+// GetOrDefault retrieves the value of the key, or the default value, and records that the key is read along with its
+// default value.
+func (t *Tracked) GetOrDefault(key, defaultValue string) string {
+	t.record(key, defaultValue)
+
+	return t.config.GetOrDefault(key, defaultValue)
+}
+
+// record records the key, the default of a key is kept once it is read with one.
+func (t *Tracked) record(key, defaultValue string) {
+	t.keys.mu.RLock()
+	d, ok := t.keys.read[key]
+	t.keys.mu.RUnlock()
+
+	if ok && (d != "" || defaultValue == "") {
+		return
+	}
+
+	t.keys.mu.Lock()
+	t.keys.read[key] = defaultValue
+	t.keys.mu.Unlock()
+}
+
+// ReadKeys returns the keys read from the config, sorted by name.
+func (t *Tracked) ReadKeys() []Key {
+	t.keys.mu.RLock()
+	defer t.keys.mu.RUnlock()
+
+	read := make([]Key, 0, len(t.keys.read))
+	for name, d := range t.keys.read {
+		read = append(read, Key{Name: name, Default: d})
+	}
+
+	sort.Slice(read, func(i, j int) bool { return read[i].Name < read[j].Name })
+
+	return read
+}
+
+// DefinedKeys returns the keys defined by the config it wraps, or nil when they are not known, ex: for the environment
+// variables which are not set by the .env files.
+func (t *Tracked) DefinedKeys() []string {
+	if d, ok := t.config.(interface{ DefinedKeys() []string }); ok {
+		return d.DefinedKeys()
+	}
+
+	return nil
+}
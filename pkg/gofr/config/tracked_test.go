@@ -0,0 +1,39 @@
+package config
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+func TestTracked(t *testing.T) {
+	m := &MockConfig{Data: map[string]string{"HTTP_PORT": "8000", "HTTP_PROT": "9000"}}
+	tracked := NewTracked(m)
+
+	assert.Equal(t, "8000", tracked.Get("HTTP_PORT"))
+	assert.Equal(t, "100", tracked.GetOrDefault("SUBSCRIBER_RETRY_BACKOFF", "100"))
+	assert.Equal(t, "", tracked.Get("SUBSCRIBER_RETRY_BACKOFF"), "the default of a key is kept once it is read with one")
+
+	other := tracked.Track(&MockConfig{Data: map[string]string{"APP_NAME": "orders"}})
+
+	assert.Equal(t, "orders", other.Get("APP_NAME"))
+
+	expected := []Key{{Name: "APP_NAME"}, {Name: "HTTP_PORT"}, {Name: "SUBSCRIBER_RETRY_BACKOFF", Default: "100"}}
+
+	assert.Equal(t, expected, tracked.ReadKeys(), "the keys read from the configs are shared")
+	assert.Equal(t, []string{"HTTP_PORT", "HTTP_PROT"}, tracked.DefinedKeys())
+	assert.Nil(t, NewTracked(&GoDotEnvProvider{}).Track(nil).DefinedKeys())
+}
+
+func TestGoDotEnvProvider_DefinedKeys(t *testing.T) {
+	t.Setenv("GOFR_ENV", "test")
+
+	keys := NewGoDotEnvProvider(log.NewMockLogger(io.Discard), "../../../configs").DefinedKeys()
+
+	assert.Contains(t, keys, "EXAMPLE", "the keys of the override file are defined")
+	assert.Contains(t, keys, "APP_NAME", "the keys of the default file are defined")
+	assert.NotContains(t, keys, "PATH", "the environment variables are not defined")
+}
@@ -0,0 +1,157 @@
+package gofr
+
+import (
+	ctx "context"
+	"strings"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/gofr/i18n"
+	"gofr.dev/pkg/middleware"
+)
+
+// The values of the context which are exported from the request when they are listed in CONTEXT_EXPORT, the other
+// values are only exported when they are sent by the upstream service, or set using Context.ExportValue.
+const (
+	// ExportTenant is the tenant of the request, sent in the X-Tenant-ID header.
+	ExportTenant = "tenant"
+	// ExportLocale is the most preferred language of the Accept-Language header.
+	ExportLocale = "locale"
+	// ExportSubject is the subject of the JWT, or the user id sent by the gateway.
+	ExportSubject = "subject"
+	// ExportFlags are the feature flags of the request, sent in the X-Feature-Flags header.
+	ExportFlags = "flags"
+
+	featureFlagsHeader = "X-Feature-Flags"
+)
+
+// contextExports returns the names of the values of the context which are forwarded to the downstream services, read
+// from CONTEXT_EXPORT, ex: tenant,locale,subject,flags. No value is forwarded when it is not set.
+func contextExports(c Config) []string {
+	names := splitList(c.Get("CONTEXT_EXPORT"))
+	for i := range names {
+		names[i] = strings.ToLower(names[i])
+	}
+
+	return names
+}
+
+/*
+exportContext returns the context with the exported values of the request, which are forwarded in the X-Context headers,
+ex: X-Context-Tenant, on the calls of the service clients and on the messages published. The values sent by the
+upstream service in the X-Context headers are re-hydrated, and take precedence over the values of the request.
+
+The tenant re-hydrated is the tenant of the request when it has none, the subject is only exported, as it does not
+authenticate the request.
+*/
+func exportContext(c *Context, names []string) ctx.Context {
+	if len(names) == 0 {
+		return c.Context
+	}
+
+	values := middleware.ImportHeaders(names, c.Request().Header.Get)
+
+	for _, name := range names {
+		if values[name] != "" {
+			continue
+		}
+
+		if v := requestValue(name, c); v != "" {
+			values[name] = v
+		}
+	}
+
+	return rehydrate(middleware.WithExportedValues(c.Context, values), values)
+}
+
+// requestValue returns the value of the request of the context which is exported by the name.
+func requestValue(name string, c *Context) string {
+	r := c.Request()
+
+	switch name {
+	case ExportTenant:
+		tenant, _ := r.Context().Value(middleware.TenantIDKey).(string)
+		return tenant
+	case ExportLocale:
+		if tags := i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language")); len(tags) > 0 && tags[0] != "*" {
+			return tags[0]
+		}
+	case ExportSubject:
+		return authenticatedSubject(c)
+	case ExportFlags:
+		return r.Header.Get(featureFlagsHeader)
+	}
+
+	return ""
+}
+
+// rehydrate sets the tenant of the exported values as the tenant of the context, when the context has none.
+func rehydrate(c ctx.Context, values map[string]string) ctx.Context {
+	if tenant := values[ExportTenant]; tenant != "" {
+		if current, _ := c.Value(middleware.TenantIDKey).(string); current == "" {
+			return ctx.WithValue(c, middleware.TenantIDKey, tenant)
+		}
+	}
+
+	return c
+}
+
+// importMessageContext returns the context with the exported values of the headers of a message.
+func importMessageContext(c ctx.Context, names []string, headers map[string]string) ctx.Context {
+	if len(names) == 0 || len(headers) == 0 {
+		return c
+	}
+
+	msg := &pubsub.Message{Headers: headers}
+	values := middleware.ImportHeaders(names, func(key string) string {
+		v, _ := header(msg, key)
+		return v
+	})
+
+	return rehydrate(middleware.WithExportedValues(c, values), values)
+}
+
+// messageHeaders returns a copy of the headers of a message published with the context, with the W3C trace context and
+// the exported values of the context. The headers already set take precedence.
+func messageHeaders(c ctx.Context, headers map[string]string) map[string]string {
+	headers = pubsub.InjectTraceContext(c, headers)
+
+	exported := middleware.ExportHeaders(c)
+	if len(exported) == 0 {
+		return headers
+	}
+
+	h := make(map[string]string, len(headers)+len(exported))
+	for k, v := range exported {
+		h[k] = v
+	}
+
+	for k, v := range headers {
+		h[k] = v
+	}
+
+	return h
+}
+
+// ExportedValue returns the exported value of the context by its name, ex: c.ExportedValue(gofr.ExportTenant), which
+// is either sent by the upstream service, or exported from the request.
+func (c *Context) ExportedValue(name string) string {
+	return middleware.ExportedValues(c.Context)[strings.ToLower(name)]
+}
+
+// ExportValue exports the value by its name, so that it is forwarded to the downstream services called, and on the
+// messages published, using the context, ex: c.ExportValue("region", "eu").
+func (c *Context) ExportValue(name, value string) {
+	base := c.Context
+	if base == nil {
+		base = ctx.Background()
+	}
+
+	values := map[string]string{name: value}
+
+	c.Context = middleware.WithExportedValues(base, values)
+
+	// the contexts of WithTimeout are derived from the context without the default deadline
+	if c.base != nil {
+		c.base = middleware.WithExportedValues(c.base, values)
+	}
+}
@@ -0,0 +1,137 @@
+package gofr
+
+import (
+	ctx "context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
+)
+
+func TestContextExports(t *testing.T) {
+	assert.Equal(t, []string{"tenant", "locale", "region"},
+		contextExports(&config.MockConfig{Data: map[string]string{"CONTEXT_EXPORT": "Tenant, locale,region"}}))
+	assert.Empty(t, contextExports(&config.MockConfig{}))
+}
+
+func TestExportContext(t *testing.T) {
+	names := []string{ExportTenant, ExportLocale, ExportSubject, ExportFlags, "region"}
+
+	testcases := []struct {
+		desc    string
+		trusted string
+		headers map[string]string
+		values  map[string]string
+	}{
+		{"values of the request", "true", map[string]string{"X-Tenant-ID": "acme", "Accept-Language": "de;q=0.9, fr",
+			"X-Authenticated-UserId": "user-1", "X-Feature-Flags": "beta"},
+			map[string]string{"tenant": "acme", "locale": "fr", "subject": "user-1", "flags": "beta"}},
+		{"user id sent by the client is not exported", "", map[string]string{"X-Tenant-ID": "acme",
+			"X-Authenticated-UserId": "admin"}, map[string]string{"tenant": "acme"}},
+		{"values of the upstream service", "", map[string]string{"X-Tenant-ID": "acme", "X-Context-Tenant": "globex",
+			"X-Context-Region": "eu", "X-Context-Zone": "a"}, map[string]string{"tenant": "globex", "region": "eu"}},
+		{"no values", "", map[string]string{"Accept-Language": "*"}, nil},
+	}
+
+	for i, tc := range testcases {
+		g := &Gofr{Config: &config.MockConfig{Data: map[string]string{"TRUSTED_GATEWAY": tc.trusted}}}
+
+		r := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+		for k, v := range tc.headers {
+			r.Header.Set(k, v)
+		}
+
+		middleware.PropagateHeaders(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			c := NewContext(nil, request.NewHTTPRequest(r), g)
+			c.Context = r.Context()
+
+			values := middleware.ExportedValues(exportContext(c, names))
+			if len(values) == 0 {
+				values = nil
+			}
+
+			assert.Equal(t, tc.values, values, "TEST[%d], failed.\n%s", i, tc.desc)
+		})).ServeHTTP(httptest.NewRecorder(), r)
+	}
+}
+
+func TestRehydrate(t *testing.T) {
+	c := rehydrate(ctx.Background(), map[string]string{ExportTenant: "acme"})
+	assert.Equal(t, "acme", c.Value(middleware.TenantIDKey), "the tenant is re-hydrated when the context has none")
+
+	c = rehydrate(ctx.WithValue(ctx.Background(), middleware.TenantIDKey, "globex"), map[string]string{ExportTenant: "acme"})
+	assert.Equal(t, "globex", c.Value(middleware.TenantIDKey), "the tenant of the request takes precedence")
+}
+
+func TestMessageHeaders(t *testing.T) {
+	c := middleware.WithExportedValues(ctx.Background(), map[string]string{"tenant": "acme", "locale": "de"})
+
+	headers := map[string]string{"X-Context-Locale": "fr"}
+
+	assert.Equal(t, map[string]string{"X-Context-Tenant": "acme", "X-Context-Locale": "fr"}, messageHeaders(c, headers),
+		"the headers of the message take precedence")
+	assert.Equal(t, map[string]string{"X-Context-Locale": "fr"}, headers, "the headers passed are not modified")
+	assert.Equal(t, headers, messageHeaders(ctx.Background(), headers))
+}
+
+func TestImportMessageContext(t *testing.T) {
+	headers := map[string]string{"x-context-tenant": "acme", "X-Context-Locale": "de"}
+
+	c := importMessageContext(ctx.Background(), []string{ExportTenant, ExportLocale}, headers)
+
+	assert.Equal(t, map[string]string{"tenant": "acme", "locale": "de"}, middleware.ExportedValues(c))
+	assert.Equal(t, "acme", c.Value(middleware.TenantIDKey))
+	assert.Equal(t, ctx.Background(), importMessageContext(ctx.Background(), nil, headers),
+		"the values are not imported when no value is exported")
+}
+
+func TestContext_ExportValue(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+	c := NewContext(nil, request.NewHTTPRequest(r), &Gofr{})
+
+	c.ExportValue("Region", "eu")
+	c.ExportValue(ExportLocale, "de")
+
+	assert.Equal(t, "eu", c.ExportedValue("region"))
+	assert.Equal(t, "de", c.Locale(), "the exported locale is used when the request has no preference")
+
+	c.base = c.Context
+	c.ExportValue(ExportTenant, "acme")
+
+	timeoutCtx, cancel := c.WithTimeout(0)
+	defer cancel()
+
+	assert.Equal(t, "acme", middleware.ExportedValues(timeoutCtx)[ExportTenant],
+		"the contexts of WithTimeout have the exported values")
+}
+
+func TestServer_ExportContext(t *testing.T) {
+	c := &config.MockConfig{}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard), contextExports: []string{ExportTenant, ExportSubject}}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(middleware.PropagateHeaders, g.Server.contextInjector)
+
+	g.GET("/orders", func(c *Context) (interface{}, error) {
+		headers := middleware.ExportHeaders(c)
+
+		return headers["X-Context-Tenant"] + "," + headers["X-Context-Subject"], nil
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+	r.Header.Set("X-Tenant-ID", "acme")
+	r.Header.Set("X-Context-Subject", "user-1")
+
+	w := httptest.NewRecorder()
+	g.Server.Router.ServeHTTP(w, r)
+
+	assert.True(t, strings.Contains(w.Body.String(), "acme,user-1"), "the values of the request are exported, got %v",
+		w.Body.String())
+}
@@ -1,19 +1,27 @@
 package gofr
 
 import (
+	"bytes"
 	ctx "context"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"go.opentelemetry.io/otel/trace"
 
 	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/i18n"
 	"gofr.dev/pkg/gofr/request"
 	"gofr.dev/pkg/gofr/responder"
 	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
+	"gofr.dev/pkg/middleware/oauth"
 )
 
 // Context represents the context information related to an HTTP or command-line (cmd) request within a GoFr application.
@@ -40,6 +48,14 @@ type Context struct {
 	ServerPush http.Pusher
 	// ServerFlush is the HTTP server flusher used to flush buffered data to the client.
 	ServerFlush http.Flusher
+
+	// httpResp and httpReq are reused by the pooled contexts, so that the responder and request
+	// are not allocated for every request.
+	httpResp responder.HTTP
+	httpReq  request.HTTP
+
+	// base is the context without the default deadline, from which the timeouts of WithTimeout are derived.
+	base ctx.Context
 }
 
 // NewContext creates and returns a new Context instance, encapsulating the incoming HTTP request (r), response writer (w),
@@ -64,6 +80,7 @@ func (c *Context) reset(w responder.Responder, r request.Request) {
 	c.req = r
 	c.resp = w
 	c.Context = nil
+	c.base = nil
 	c.Logger = nil
 }
 
@@ -120,6 +137,84 @@ func (c *Context) Header(key string) string {
 	return c.req.Header(key)
 }
 
+// Locale returns the locale of the request negotiated from the Accept-Language header. When the application has
+// messages, the best matching locale of the messages is returned, else the most preferred language of the request.
+func (c *Context) Locale() string {
+	var header string
+	if c.req != nil {
+		header = c.req.Header("Accept-Language")
+	}
+
+	// the locale exported by the upstream service, ex: for a message, is used when the request has no preference
+	if header == "" {
+		header = c.ExportedValue(ExportLocale)
+	}
+
+	if c.Gofr != nil && c.Messages != nil && len(c.Messages.Locales()) > 0 {
+		return c.Messages.Match(header)
+	}
+
+	if tags := i18n.ParseAcceptLanguage(header); len(tags) > 0 && tags[0] != "*" {
+		return tags[0]
+	}
+
+	return i18n.DefaultLocale
+}
+
+// TimeZoneHeader is the header in which a request can specify its time zone, as an IANA time zone name.
+const TimeZoneHeader = "X-Time-Zone"
+
+const errNotTransactional = errors.Error("pubsub does not support transactions")
+
+// Now returns the current time of the application clock in the time zone of the request.
+func (c *Context) Now() time.Time {
+	var now time.Time
+
+	if c.Gofr != nil && c.Clock != nil {
+		now = c.Clock.Now()
+	} else {
+		now = time.Now()
+	}
+
+	return now.In(c.Location())
+}
+
+// Location returns the time zone of the request. It is resolved from the X-Time-Zone header, then the zoneinfo
+// claim of the user profile, and falls back to the time zone of the application. Invalid time zones are ignored.
+func (c *Context) Location() *time.Location {
+	if c.req != nil {
+		profileZone, _ := c.req.GetClaim("zoneinfo").(string)
+
+		for _, zone := range []string{c.req.Header(TimeZoneHeader), profileZone} {
+			// LoadLocation returns UTC for an empty name
+			if zone == "" {
+				continue
+			}
+
+			if loc, err := time.LoadLocation(zone); err == nil {
+				return loc
+			}
+		}
+	}
+
+	if c.Gofr != nil && c.TimeZone != nil {
+		return c.TimeZone
+	}
+
+	return time.Local
+}
+
+// Translate returns the message of the key in the locale of the request, formatted with the args.
+// The key is returned when the application does not have a message for it.
+func (c *Context) Translate(key string, args ...interface{}) string {
+	var messages *i18n.Bundle
+	if c.Gofr != nil {
+		messages = c.Messages
+	}
+
+	return messages.Translate(c.Locale(), key, args...)
+}
+
 // Log logs the key-value pair into the logs
 func (c *Context) Log(key string, value interface{}) {
 	// This section takes care of middleware logging
@@ -142,6 +237,26 @@ func (c *Context) Log(key string, value interface{}) {
 	c.Logger.AddData(key, value)
 }
 
+// Breadcrumb records an event of the request, ex: a query or a call to a service, which is included in the report
+// of a panic in the request, when the panics are reported to an error tracker.
+func (c *Context) Breadcrumb(category, message string) {
+	if c.Context == nil {
+		return
+	}
+
+	middleware.AddBreadcrumb(c.Context, category, message)
+}
+
+// Metrics returns the labels of the HTTP response metrics of the request, ex: c.Metrics().Label("tier", tier). The labels
+// are configured by METRICS_REQUEST_LABELS, and the labels which are not configured are ignored.
+func (c *Context) Metrics() *middleware.MetricLabels {
+	if c.Context == nil {
+		return nil
+	}
+
+	return middleware.RequestMetricLabels(c.Context)
+}
+
 // SetPathParams sets the URL path variables to the given value. These can be accessed
 // by c.PathParam(key). This method should only be used for testing purposes.
 func (c *Context) SetPathParams(pathParams map[string]string) {
@@ -162,6 +277,16 @@ func (c *Context) GetClaim(claimKey string) interface{} {
 	return c.req.GetClaim(claimKey)
 }
 
+// Subject returns the authenticated user of the request, which is the subject of the JWT, or the user sent by the
+// gateway when TRUSTED_GATEWAY is true. It is empty when the user is not authenticated.
+func (c *Context) Subject() string {
+	if c.req == nil {
+		return ""
+	}
+
+	return authenticatedSubject(c)
+}
+
 // ValidateClaimSub validates the "sub" claim within the JWT (JSON Web Token) claims obtained from the request context.
 // It compares the provided subject parameter with the "sub" claim value in the JWT claims.
 // If the "sub" claim exists and matches the provided subject, the method returns true, indicating a successful validation.
@@ -224,7 +349,11 @@ PublishEventWithOptions publishes message to the pubsub(kafka) configured.
 returns error if publish encounters a failure
 */
 func (c *Context) PublishEventWithOptions(key string, value interface{}, headers map[string]string, options *pubsub.PublishOptions) error {
-	return c.PubSub.PublishEventWithOptions(key, value, headers, options)
+	if err := c.deadlineErr(); err != nil {
+		return err
+	}
+
+	return c.PubSub.PublishEventWithOptions(key, value, messageHeaders(c.Context, headers), options)
 }
 
 /*
@@ -237,7 +366,62 @@ PublishEvent publishes message to the pubsub(kafka) configured.
 	returns error if publish encounters a failure
 */
 func (c *Context) PublishEvent(key string, value interface{}, headers map[string]string) error {
-	return c.PubSub.PublishEvent(key, value, headers)
+	if err := c.deadlineErr(); err != nil {
+		return err
+	}
+
+	return c.PubSub.PublishEvent(key, value, messageHeaders(c.Context, headers))
+}
+
+/*
+PublishEvents publishes the messages to the topic of the pubsub configured in a batch, the topic is read from config
+when it is empty.
+
+	returns pubsub.PublishErrors with the errors of the messages which are not published
+*/
+func (c *Context) PublishEvents(topic string, values []interface{}, headers map[string]string) error {
+	if err := c.deadlineErr(); err != nil {
+		return err
+	}
+
+	return c.PubSub.PublishEvents(topic, values, messageHeaders(c.Context, headers))
+}
+
+/*
+PublishTransaction publishes the messages published by fn, and commits the offset of the consumed message, atomically,
+when the pubsub configured is transactional, ex: kafka with KAFKA_TRANSACTIONAL_ID.
+
+	the consumed message can be nil, when the messages are only published
+	returns error if the pubsub is not transactional, or fn returns an error, in which case the transaction is aborted
+*/
+func (c *Context) PublishTransaction(consumed *pubsub.Message, fn func(p pubsub.Publisher) error) error {
+	if err := c.deadlineErr(); err != nil {
+		return err
+	}
+
+	tx, ok := c.PubSub.(pubsub.Transactional)
+	if !ok {
+		return errNotTransactional
+	}
+
+	return tx.Transaction(consumed, func(p pubsub.Publisher) error {
+		return fn(tracedPublisher{Publisher: p, ctx: c.Context})
+	})
+}
+
+// tracedPublisher sets the trace context of the request on the messages published in a transaction.
+type tracedPublisher struct {
+	pubsub.Publisher
+	ctx ctx.Context
+}
+
+func (t tracedPublisher) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) error {
+	return t.Publisher.PublishEventWithOptions(key, value, messageHeaders(t.ctx, headers), options)
+}
+
+func (t tracedPublisher) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	return t.Publisher.PublishEvent(key, value, messageHeaders(t.ctx, headers))
 }
 
 /*
@@ -257,7 +441,27 @@ func (c *Context) Subscribe(target interface{}) (*pubsub.Message, error) {
 		return message, err
 	}
 
-	return message, c.PubSub.Bind([]byte(message.Value), &target)
+	return message, c.BindMessage(message, &target)
+}
+
+/*
+BindMessage decodes the value of the message into the target, by the content type of the message. The content type is
+read from the Content-Type header of the message, and is PUBSUB_CONTENT_TYPE when the message has none, ex: for the
+producers which do not set the header. JSON, Protobuf, ex: application/x-protobuf, whose target must be a
+proto.Message, and MessagePack, ex: application/msgpack, are decoded. The message is bound by the pubsub, ex: from
+Avro, when it has no content type and PUBSUB_CONTENT_TYPE is not set.
+*/
+func (c *Context) BindMessage(msg *pubsub.Message, target interface{}) error {
+	contentType := msg.ContentType()
+	if contentType == "" && c.Gofr != nil && c.Config != nil {
+		contentType = strings.ToLower(c.Config.Get("PUBSUB_CONTENT_TYPE"))
+	}
+
+	if contentType == "" {
+		return c.PubSub.Bind([]byte(msg.Value), target)
+	}
+
+	return pubsub.Unmarshal(contentType, []byte(msg.Value), target)
 }
 
 /*
@@ -269,3 +473,38 @@ func (c *Context) Subscribe(target interface{}) (*pubsub.Message, error) {
 func (c *Context) SubscribeWithCommit(f pubsub.CommitFunc) (*pubsub.Message, error) {
 	return c.PubSub.SubscribeWithCommit(f)
 }
+
+// detachedRequest copies the request of the context for the work which outlives the request, ex: an operation, with
+// the body, the request of the context without a request, ex: of a command, is an empty request. The copy carries only
+// the path parameters, and the values of the request set by the middlewares, as the context of the request carries the
+// Context of the handler, which is reused by another request once it returns.
+func detachedRequest(c *Context, body []byte) request.Request {
+	var r *http.Request
+
+	if c.req != nil {
+		r = c.req.Request()
+	}
+
+	if r == nil {
+		r = &http.Request{Method: http.MethodGet, URL: &url.URL{}, Header: make(http.Header)}
+	}
+
+	values := ctx.Background()
+
+	for _, key := range []interface{}{middleware.CorrelationIDKey, middleware.ClientIPKey, middleware.AuthenticatedUserIDKey,
+		middleware.B3TraceIDKey, middleware.TenantIDKey, middleware.AuthorizationHeader, middleware.ServiceNameKey,
+		middleware.ExportedValuesKey, oauth.JWTContextKey("claims"), appData} {
+		if v := r.Context().Value(key); v != nil {
+			values = ctx.WithValue(values, key, v)
+		}
+	}
+
+	detached := mux.SetURLVars(r.Clone(values), mux.Vars(r))
+	detached.Body, detached.ContentLength = http.NoBody, 0
+
+	if len(body) > 0 {
+		detached.Body, detached.ContentLength = io.NopCloser(bytes.NewReader(body)), int64(len(body))
+	}
+
+	return request.NewHTTPRequest(detached)
+}
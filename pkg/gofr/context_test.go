@@ -3,17 +3,24 @@ package gofr
 import (
 	"bytes"
 	ctx "context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 
 	"golang.org/x/net/context"
 
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/gofr/clock"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/i18n"
 	"gofr.dev/pkg/gofr/request"
 	"gofr.dev/pkg/middleware"
 	"gofr.dev/pkg/middleware/oauth"
@@ -352,3 +359,188 @@ func Test_GetClaims(t *testing.T) {
 		assert.Equal(t, tc.expOutput, out, "Test case failed", i)
 	}
 }
+
+func TestContext_Locale(t *testing.T) {
+	messages := i18n.NewBundle("en")
+	messages.Add("en", map[string]string{"greeting": "Hello"})
+	messages.Add("fr", map[string]string{"greeting": "Bonjour"})
+
+	tests := []struct {
+		desc           string
+		messages       *i18n.Bundle
+		acceptLanguage string
+		locale         string
+		greeting       string
+	}{
+		{"locale of the messages", messages, "de;q=0.9, fr-CH;q=0.8", "fr", "Bonjour"},
+		{"default locale of the messages", messages, "de", "en", "Hello"},
+		{"preferred language without messages", nil, "de;q=0.9, fr-CH", "fr-CH", "greeting"},
+		{"default locale without header", nil, "", i18n.DefaultLocale, "greeting"},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/greet", http.NoBody)
+		r.Header.Set("Accept-Language", tc.acceptLanguage)
+
+		c := NewContext(nil, request.NewHTTPRequest(r), &Gofr{Messages: tc.messages})
+
+		assert.Equal(t, tc.locale, c.Locale(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.greeting, c.Translate("greeting"), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestContext_Now(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 15, 30, 0, 0, time.UTC)
+	appZone, _ := time.LoadLocation("Europe/Paris")
+
+	tests := []struct {
+		desc     string
+		header   string
+		claims   jwt.MapClaims
+		timeZone *time.Location
+		expZone  string
+	}{
+		{"time zone from header", "Asia/Kolkata", jwt.MapClaims{"zoneinfo": "America/New_York"}, appZone, "Asia/Kolkata"},
+		{"time zone from user profile", "", jwt.MapClaims{"zoneinfo": "America/New_York"}, appZone, "America/New_York"},
+		{"invalid header is ignored", "Mars/Olympus", jwt.MapClaims{"zoneinfo": "America/New_York"}, nil, "America/New_York"},
+		{"time zone of the application", "", nil, appZone, "Europe/Paris"},
+		{"local time zone", "Invalid/Zone", nil, nil, "Local"},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/now", http.NoBody)
+		r.Header.Set(TimeZoneHeader, tc.header)
+
+		if tc.claims != nil {
+			r = r.WithContext(ctx.WithValue(r.Context(), oauth.JWTContextKey("claims"), tc.claims))
+		}
+
+		c := NewContext(nil, request.NewHTTPRequest(r), &Gofr{Clock: clock.NewFrozen(now), TimeZone: tc.timeZone})
+
+		got := c.Now()
+
+		assert.Equal(t, tc.expZone, got.Location().String(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.True(t, now.Equal(got), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestContext_Metrics(t *testing.T) {
+	tests := []struct {
+		desc string
+		c    *Context
+	}{
+		{"context without request", &Context{}},
+		{"request labels are not configured", &Context{Context: ctx.Background()}},
+	}
+
+	for i, tc := range tests {
+		labels := tc.c.Metrics()
+
+		assert.Nil(t, labels, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.NotPanics(t, func() { labels.Label("tier", "gold") }, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// mockTxPubSub runs the transactions with the pubsub itself as the publisher.
+type mockTxPubSub struct {
+	mockConsumer
+	consumed *pubsub.Message
+}
+
+func (m *mockTxPubSub) Transaction(consumed *pubsub.Message, fn func(p pubsub.Publisher) error) error {
+	m.consumed = consumed
+
+	return fn(m)
+}
+
+func TestContext_PublishTransaction(t *testing.T) {
+	consumed := &pubsub.Message{Topic: "orders", Offset: 4}
+	fn := func(pubsub.Publisher) error { return nil }
+
+	ps := &mockTxPubSub{}
+	c := &Context{Context: ctx.Background(), Gofr: &Gofr{}}
+	c.PubSub = ps
+
+	assert.NoError(t, c.PublishTransaction(consumed, fn))
+	assert.Equal(t, consumed, ps.consumed)
+
+	c.PubSub = &mockConsumer{}
+
+	assert.Equal(t, errNotTransactional, c.PublishTransaction(consumed, fn))
+}
+
+// mockBinder binds the messages without a content type, as the backend does.
+type mockBinder struct {
+	mockConsumer
+	bound bool
+}
+
+func (m *mockBinder) Bind([]byte, interface{}) error {
+	m.bound = true
+
+	return nil
+}
+
+func TestContext_BindMessage(t *testing.T) {
+	type order struct {
+		ID int `json:"id"`
+	}
+
+	tests := []struct {
+		desc        string
+		headers     map[string]string
+		contentType string
+		value       string
+		order       order
+		bound       bool
+		err         error
+	}{
+		{"content type of the message", map[string]string{"Content-Type": "application/json"}, "", `{"id": 1}`, order{ID: 1}, false, nil},
+		{"content type of the config", nil, "application/json", `{"id": 2}`, order{ID: 2}, false, nil},
+		{"bound by the pubsub", nil, "", `{"id": 3}`, order{}, true, nil},
+		{"unsupported content type", map[string]string{"Content-Type": "text/csv"}, "", "3", order{}, false,
+			pubsub.ErrUnsupportedContentType},
+	}
+
+	for i, tc := range tests {
+		ps := &mockBinder{}
+		c := &Context{Gofr: &Gofr{Config: &config.MockConfig{Data: map[string]string{"PUBSUB_CONTENT_TYPE": tc.contentType}}}}
+		c.PubSub = ps
+
+		var o order
+
+		err := c.BindMessage(&pubsub.Message{Value: tc.value, Headers: tc.headers}, &o)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.order, o, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.bound, ps.bound, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestDetachedRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/orders/1", bytes.NewBufferString(`{"item":"tea"}`))
+	r = r.WithContext(ctx.WithValue(r.Context(), oauth.JWTContextKey("claims"), jwt.MapClaims{"sub": "alice"}))
+
+	c := NewContext(nil, nil, &Gofr{})
+	c.req = request.NewHTTPRequest(mux.SetURLVars(r.WithContext(ctx.WithValue(r.Context(), gofrContextkey, c)),
+		map[string]string{"id": "1"}))
+
+	tests := []struct {
+		desc string
+		body []byte
+	}{
+		{"without the body", nil},
+		{"with the body", []byte(`{"item":"tea"}`)},
+	}
+
+	for i, tc := range tests {
+		detached := detachedRequest(c, tc.body)
+		body, _ := io.ReadAll(detached.Request().Body)
+
+		assert.Nil(t, detached.Request().Context().Value(gofrContextkey), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, jwt.MapClaims{"sub": "alice"}, detached.Request().Context().Value(oauth.JWTContextKey("claims")),
+			"TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "1", detached.PathParam("id"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, string(tc.body), string(body), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -1,6 +1,12 @@
 package gofr
 
-import "gofr.dev/pkg/gofr/metrics"
+import (
+	"runtime"
+	"runtime/debug"
+
+	"gofr.dev/pkg/gofr/metrics"
+	"gofr.dev/pkg/log"
+)
 
 // NewCounter registers new custom counter metric
 func (g *Gofr) NewCounter(name, help string, labels ...string) error {
@@ -37,3 +43,35 @@ func (g *Gofr) NewSummary(name, help string, labels ...string) error {
 
 	return metrics.NewSummary(g.Metric, name, help, labels...)
 }
+
+// NewInfo registers new custom info metric, whose labels describe the application, ex: its feature flags
+func (g *Gofr) NewInfo(name, help string, labels map[string]string) error {
+	if g.Metric == nil {
+		g.Metric = metrics.NewMetric()
+	}
+
+	return metrics.NewInfo(g.Metric, name, help, labels)
+}
+
+// SetMetricUnit sets the unit of the custom metric, which is exposed in the OpenMetrics format, the name of the metric
+// must end with the unit, ex: payment_amount_dollars
+func (g *Gofr) SetMetricUnit(name, unit string) error {
+	return metrics.SetUnit(name, unit)
+}
+
+// buildInfo returns the labels of the build info metric, ie: the versions of the application, the framework and go,
+// and the revision of the version control the application is built from, when it is known.
+func buildInfo(appName, appVersion string) map[string]string {
+	labels := map[string]string{"app": appName, "version": appVersion, "framework": log.GofrVersion,
+		"go_version": runtime.Version(), "revision": "unknown"}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				labels["revision"] = s.Value
+			}
+		}
+	}
+
+	return labels
+}
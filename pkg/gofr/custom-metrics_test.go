@@ -68,3 +68,13 @@ func Test_NewGauge(t *testing.T) {
 		assert.Equal(t, tc.err, err, "TESTCASE[%d] NewGauge:%v", i, tc.desc)
 	}
 }
+
+func Test_NewInfo(t *testing.T) {
+	testCases, app := initializeTest()
+
+	for i, tc := range testCases {
+		err := app.NewInfo("new_features_info", "New Features", map[string]string{"checkout_v2": "true"})
+
+		assert.Equal(t, tc.err, err, "TESTCASE[%d] NewInfo:%v", i, tc.desc)
+	}
+}
@@ -12,10 +12,17 @@ import (
 	"gofr.dev/pkg"
 	"gofr.dev/pkg/datastore"
 	"gofr.dev/pkg/datastore/pubsub/avro"
+	"gofr.dev/pkg/datastore/pubsub/aws-sqs"
+	"gofr.dev/pkg/datastore/pubsub/cloudevents"
 	"gofr.dev/pkg/datastore/pubsub/eventbridge"
 	"gofr.dev/pkg/datastore/pubsub/eventhub"
+	"gofr.dev/pkg/datastore/pubsub/failover"
 	"gofr.dev/pkg/datastore/pubsub/google"
+	"gofr.dev/pkg/datastore/pubsub/inprocess"
 	"gofr.dev/pkg/datastore/pubsub/kafka"
+	"gofr.dev/pkg/datastore/pubsub/nats"
+	"gofr.dev/pkg/datastore/pubsub/rabbitmq"
+	"gofr.dev/pkg/datastore/pubsub/servicebus"
 	"gofr.dev/pkg/log"
 	awssns "gofr.dev/pkg/notifier/aws-sns"
 )
@@ -152,6 +159,8 @@ func sqlDBConfigFromEnv(c Config, prefix string) *datastore.DBConfig {
 		MaxOpenConn:       openC,
 		MaxIdleConn:       idleC,
 		MaxConnLife:       connL,
+		QueryTags:         getBool(c.Get(prefix + "DB_QUERY_TAGS")),
+		Application:       c.Get("APP_NAME"),
 	}
 }
 
@@ -191,6 +200,7 @@ func kafkaConfigFromEnv(c Config, prefix string) *kafka.Config {
 	}
 
 	disableautocommit, _ := strconv.ParseBool(c.GetOrDefault(prefix+"KAFKA_AUTOCOMMIT_DISABLE", "false"))
+	idempotent, _ := strconv.ParseBool(c.GetOrDefault(prefix+"KAFKA_IDEMPOTENT", "false"))
 
 	// converting the CSV string to slice of string
 	topics := strings.Split(topic, ",")
@@ -200,6 +210,12 @@ func kafkaConfigFromEnv(c Config, prefix string) *kafka.Config {
 			User:      c.Get(prefix + "KAFKA_SASL_USER"),
 			Password:  c.Get(prefix + "KAFKA_SASL_PASS"),
 			Mechanism: c.Get(prefix + "KAFKA_SASL_MECHANISM"),
+			OAuth: kafka.OAuthConfig{
+				TokenURL:     c.Get(prefix + "KAFKA_SASL_OAUTH_TOKEN_URL"),
+				ClientID:     c.Get(prefix + "KAFKA_SASL_OAUTH_CLIENT_ID"),
+				ClientSecret: c.Get(prefix + "KAFKA_SASL_OAUTH_CLIENT_SECRET"),
+				Scopes:       splitList(c.Get(prefix + "KAFKA_SASL_OAUTH_SCOPES")),
+			},
 		},
 		Topics:            topics,
 		MaxRetry:          maxRetry,
@@ -208,6 +224,8 @@ func kafkaConfigFromEnv(c Config, prefix string) *kafka.Config {
 		InitialOffsets:    kafka.OffsetOldest,
 		GroupID:           groupName,
 		DisableAutoCommit: disableautocommit,
+		Idempotent:        idempotent,
+		TransactionalID:   c.Get(prefix + "KAFKA_TRANSACTIONAL_ID"),
 	}
 
 	offset := c.GetOrDefault(prefix+"KAFKA_CONSUMER_OFFSET", "OLDEST")
@@ -257,6 +275,7 @@ func avroConfigFromEnv(c Config, prefix string) *avro.Config {
 		Subject:        c.Get(prefix + "AVRO_SUBJECT"),
 		SchemaUser:     c.Get(prefix + "AVRO_USER"),
 		SchemaPassword: c.Get(prefix + "AVRO_PASSWORD"),
+		Format:         strings.ToLower(c.GetOrDefault(prefix+"SCHEMA_FORMAT", avro.FormatAvro)),
 	}
 }
 
@@ -276,6 +295,111 @@ func eventhubConfigFromEnv(c Config, prefix string) eventhub.Config {
 	}
 }
 
+func inProcessConfigFromEnv(c Config, prefix string) *inprocess.Config {
+	bufferSize, _ := strconv.Atoi(c.Get(prefix + "INPROCESS_BUFFER_SIZE"))
+
+	return &inprocess.Config{
+		Topics:     splitList(c.Get(prefix + "INPROCESS_TOPIC")), // CSV string
+		BufferSize: bufferSize,
+	}
+}
+
+// natsConfigFromEnv returns the configuration of NATS JetStream from the environment variables
+func natsConfigFromEnv(c Config, prefix string) *nats.Config {
+	maxDeliver, _ := strconv.Atoi(c.Get(prefix + "NATS_MAX_DELIVER"))
+
+	return &nats.Config{
+		URL:               c.Get(prefix + "NATS_URL"),
+		Stream:            c.Get(prefix + "NATS_STREAM"),
+		Subjects:          splitList(c.Get(prefix + "NATS_SUBJECT")), // CSV string
+		Consumer:          c.Get(prefix + "NATS_CONSUMER"),
+		AckWait:           seconds(c, prefix+"NATS_ACK_WAIT", 0),
+		MaxDeliver:        maxDeliver,
+		FetchWait:         seconds(c, prefix+"NATS_FETCH_WAIT", 0),
+		User:              c.Get(prefix + "NATS_USER"),
+		Password:          c.Get(prefix + "NATS_PASS"),
+		Token:             c.Get(prefix + "NATS_TOKEN"),
+		CredentialsFile:   c.Get(prefix + "NATS_CREDS_FILE"),
+		ConnRetryDuration: getRetryDuration(c.Get(prefix + "NATS_CONN_RETRY")),
+	}
+}
+
+// rabbitMQConfigFromEnv returns the configuration of RabbitMQ from the environment variables
+func rabbitMQConfigFromEnv(c Config, prefix string) *rabbitmq.Config {
+	prefetch, _ := strconv.Atoi(c.Get(prefix + "RABBITMQ_PREFETCH"))
+
+	return &rabbitmq.Config{
+		URL:               c.Get(prefix + "RABBITMQ_URL"),
+		Exchange:          c.Get(prefix + "RABBITMQ_EXCHANGE"),
+		ExchangeType:      c.Get(prefix + "RABBITMQ_EXCHANGE_TYPE"),
+		Queue:             c.Get(prefix + "RABBITMQ_QUEUE"),
+		BindingKeys:       splitList(c.Get(prefix + "RABBITMQ_BINDING_KEYS")), // CSV string
+		Prefetch:          prefetch,
+		ReconnectDelay:    seconds(c, prefix+"RABBITMQ_RECONNECT_DELAY", 0),
+		ConnRetryDuration: getRetryDuration(c.Get(prefix + "RABBITMQ_CONN_RETRY")),
+	}
+}
+
+// sqsConfigFromEnv returns the configuration of AWS SQS, and of the SNS topic to which the messages are published, from
+// the environment variables
+func sqsConfigFromEnv(c Config, prefix string) *awssqs.Config {
+	return &awssqs.Config{
+		Region:            c.Get(prefix + "SQS_REGION"),
+		AccessKeyID:       c.Get(prefix + "SQS_ACCESS_KEY_ID"),
+		SecretAccessKey:   c.Get(prefix + "SQS_SECRET_ACCESS_KEY"),
+		Endpoint:          c.Get(prefix + "SQS_ENDPOINT"),
+		QueueURL:          c.Get(prefix + "SQS_QUEUE_URL"),
+		TopicArn:          c.Get(prefix + "SQS_SNS_TOPIC_ARN"),
+		WaitTime:          int64(positiveInt(c, prefix+"SQS_WAIT_TIME")),
+		VisibilityTimeout: int64(positiveInt(c, prefix+"SQS_VISIBILITY_TIMEOUT")),
+		MaxMessages:       int64(positiveInt(c, prefix+"SQS_MAX_MESSAGES")),
+	}
+}
+
+// serviceBusConfigFromEnv returns the configuration of Azure Service Bus from the environment variables
+func serviceBusConfigFromEnv(c Config, prefix string) *servicebus.Config {
+	return &servicebus.Config{
+		ConnectionString:    c.Get(prefix + "SERVICEBUS_CONNECTION_STRING"),
+		Namespace:           c.Get(prefix + "SERVICEBUS_NAMESPACE"),
+		TenantID:            c.Get(prefix + "SERVICEBUS_TENANT_ID"),
+		ClientID:            c.Get(prefix + "SERVICEBUS_CLIENT_ID"),
+		ClientSecret:        c.Get(prefix + "SERVICEBUS_CLIENT_SECRET"),
+		Queue:               c.Get(prefix + "SERVICEBUS_QUEUE"),
+		Topic:               c.Get(prefix + "SERVICEBUS_TOPIC"),
+		Subscription:        c.Get(prefix + "SERVICEBUS_SUBSCRIPTION"),
+		Sessions:            getBool(c.Get(prefix + "SERVICEBUS_SESSIONS")),
+		SessionIdleTimeout:  seconds(c, prefix+"SERVICEBUS_SESSION_IDLE_TIMEOUT", 0),
+		MaxDeliveryCount:    positiveInt(c, prefix+"SERVICEBUS_MAX_DELIVERY_COUNT"),
+		DisableAutoComplete: getBool(c.Get(prefix + "SERVICEBUS_DISABLE_AUTO_COMPLETE")),
+	}
+}
+
+// failoverConfigFromEnv returns the configuration of the failover of the pubsub to the secondary region, from the
+// environment variables
+func failoverConfigFromEnv(c Config, prefix string) *failover.Config {
+	return &failover.Config{
+		PrimaryRegion:    c.Get(prefix + "PUBSUB_PRIMARY_REGION"),
+		SecondaryRegion:  c.Get(prefix + "PUBSUB_SECONDARY_REGION"),
+		FailureThreshold: positiveInt(c, prefix+"PUBSUB_FAILURE_THRESHOLD"),
+		FailbackInterval: seconds(c, prefix+"PUBSUB_FAILBACK_INTERVAL", 0),
+	}
+}
+
+// cloudEventsConfigFromEnv returns the configuration of the CloudEvents of the pubsub from the environment variables, the
+// source of the events is the name of the app by default
+func cloudEventsConfigFromEnv(c Config, prefix string) *cloudevents.Config {
+	source := c.Get(prefix + "CLOUDEVENTS_SOURCE")
+	if source == "" {
+		source = c.Get("APP_NAME")
+	}
+
+	return &cloudevents.Config{
+		Mode:   c.Get(prefix + "PUBSUB_CLOUDEVENTS"),
+		Source: source,
+		Type:   c.Get(prefix + "CLOUDEVENTS_TYPE"),
+	}
+}
+
 func eventbridgeConfigFromEnv(c Config, logger log.Logger, prefix string) *eventbridge.Config {
 	retryFrequency, _ := strconv.Atoi(c.Get(prefix + "EVENT_BRIDGE_RETRY_FREQUENCY"))
 	akID := c.Get(prefix + "EVENT_BRIDGE_ACCESS_KEY_ID")
@@ -334,6 +458,11 @@ func googlePubSubConfigFromEnv(c Config, prefix string) google.Config {
 		SubscriptionDetails: &google.Subscription{
 			Name: c.Get(prefix + "GOOGLE_SUBSCRIPTION_NAME"),
 		},
+		EmulatorHost:           c.Get(prefix + "GOOGLE_EMULATOR_HOST"),
+		EnableOrdering:         getBool(c.Get(prefix + "GOOGLE_ENABLE_ORDERING")),
+		MaxOutstandingMessages: positiveInt(c, prefix+"GOOGLE_MAX_OUTSTANDING_MESSAGES"),
+		MaxOutstandingBytes:    positiveInt(c, prefix+"GOOGLE_MAX_OUTSTANDING_BYTES"),
+		NumGoroutines:          positiveInt(c, prefix+"GOOGLE_NUM_GOROUTINES"),
 	}
 }
 
@@ -380,5 +509,31 @@ func clickhouseDBConfigFromEnv(c Config, prefix string) *datastore.ClickHouseCon
 		MaxOpenConn:       openC,
 		MaxIdleConn:       idleC,
 		MaxConnLife:       connL,
+		QueryTags:         getBool(c.Get(prefix + "CLICKHOUSE_QUERY_TAGS")),
+		Application:       c.Get("APP_NAME"),
+	}
+}
+
+func ldapConfigFromEnv(c Config, prefix string) *datastore.LDAPConfig {
+	if prefix != "" {
+		prefix += "_"
+	}
+
+	poolSize, _ := strconv.Atoi(c.Get(prefix + "LDAP_POOL_SIZE"))
+	timeout, _ := strconv.Atoi(c.Get(prefix + "LDAP_TIMEOUT"))
+
+	return &datastore.LDAPConfig{
+		Addr:               c.Get(prefix + "LDAP_ADDR"),
+		UseTLS:             getBool(c.Get(prefix + "LDAP_USE_TLS")),
+		StartTLS:           getBool(c.Get(prefix + "LDAP_START_TLS")),
+		InsecureSkipVerify: getBool(c.Get(prefix + "LDAP_INSECURE_SKIP_VERIFY")),
+		BindDN:             c.Get(prefix + "LDAP_BIND_DN"),
+		BindPassword:       c.Get(prefix + "LDAP_BIND_PASSWORD"),
+		BaseDN:             c.Get(prefix + "LDAP_BASE_DN"),
+		UserFilter:         c.Get(prefix + "LDAP_USER_FILTER"),
+		GroupAttribute:     c.Get(prefix + "LDAP_GROUP_ATTRIBUTE"),
+		PoolSize:           poolSize,
+		Timeout:            timeout,
+		ConnRetryDuration:  getRetryDuration(c.Get(prefix + "LDAP_CONN_RETRY")),
 	}
 }
@@ -0,0 +1,53 @@
+package gofr
+
+import (
+	ctx "context"
+	"time"
+)
+
+// withDeadline sets the default deadline of the application on the context, so that the datastores, the services and
+// the publishers called using the context stop waiting once the latency budget of the request is spent. It returns the
+// function releasing the resources of the deadline, which must be called when the request is served.
+func (c *Context) withDeadline() ctx.CancelFunc {
+	c.base = c.Context
+
+	if c.Gofr == nil || c.Gofr.RequestDeadline <= 0 {
+		return func() {}
+	}
+
+	var cancel ctx.CancelFunc
+
+	c.Context, cancel = ctx.WithTimeout(c.Context, c.Gofr.RequestDeadline)
+
+	return cancel
+}
+
+// WithTimeout returns a context of the request with the timeout instead of the default deadline REQUEST_DEADLINE,
+// ex: for a call which is known to be slower than the others. The cancel function must be called once the call is done.
+//
+//	callCtx, cancel := c.WithTimeout(10 * time.Second)
+//	defer cancel()
+//
+//	resp, err := svc.Get(callCtx, "reports", nil)
+func (c *Context) WithTimeout(timeout time.Duration) (ctx.Context, ctx.CancelFunc) {
+	parent := c.base
+	if parent == nil {
+		parent = c.Context
+	}
+
+	if parent == nil {
+		parent = ctx.Background()
+	}
+
+	return ctx.WithTimeout(parent, timeout)
+}
+
+// deadlineErr returns the error of the context when its deadline is exceeded or it is canceled, so that the calls
+// which do not take a context are not made once the budget of the request is spent.
+func (c *Context) deadlineErr() error {
+	if c.Context == nil {
+		return nil
+	}
+
+	return c.Context.Err()
+}
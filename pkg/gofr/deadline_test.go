@@ -0,0 +1,62 @@
+package gofr
+
+import (
+	ctx "context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_WithDeadline(t *testing.T) {
+	tests := []struct {
+		desc        string
+		deadline    time.Duration
+		hasDeadline bool
+	}{
+		{"no default deadline", 0, false},
+		{"default deadline", time.Second, true},
+	}
+
+	for i, tc := range tests {
+		c := &Context{Context: ctx.Background(), Gofr: &Gofr{RequestDeadline: tc.deadline}}
+
+		cancel := c.withDeadline()
+
+		deadline, ok := c.Deadline()
+
+		assert.Equal(t, tc.hasDeadline, ok, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if ok {
+			assert.WithinDuration(t, time.Now().Add(tc.deadline), deadline, 100*time.Millisecond, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+
+		cancel()
+	}
+}
+
+func TestContext_WithTimeout(t *testing.T) {
+	c := &Context{Context: ctx.Background(), Gofr: &Gofr{RequestDeadline: time.Millisecond}}
+
+	cancel := c.withDeadline()
+	defer cancel()
+
+	callCtx, callCancel := c.WithTimeout(time.Minute)
+	defer callCancel()
+
+	deadline, _ := callCtx.Deadline()
+
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second, "the default deadline must be overridden")
+}
+
+func TestContext_PublishEventDeadline(t *testing.T) {
+	c := &Context{Context: ctx.Background(), Gofr: &Gofr{RequestDeadline: time.Nanosecond}}
+
+	cancel := c.withDeadline()
+	defer cancel()
+
+	<-c.Done()
+
+	assert.ErrorIs(t, c.PublishEvent("key", "value", nil), ctx.DeadlineExceeded)
+	assert.ErrorIs(t, c.PublishEventWithOptions("key", "value", nil, nil), ctx.DeadlineExceeded)
+}
@@ -0,0 +1,65 @@
+package gofr
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"sync"
+)
+
+const (
+	debugTraceHeader = "X-Debug-Trace"
+	debugLogsTrailer = "X-Debug-Logs"
+
+	// maxDebugLogs bounds the size of the logs sent in the trailer, the further logs are only written to the output.
+	maxDebugLogs = 32 * 1024
+)
+
+// debugCapture captures the logs of a request which is traced using X-Debug-Trace.
+type debugCapture struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (d *debugCapture) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.buf.Len()+len(p) > maxDebugLogs {
+		d.truncated = true
+		return len(p), nil
+	}
+
+	return d.buf.Write(p)
+}
+
+// isDebugTraced reports whether the request is traced, ie: the X-Debug-Trace header matches DEBUG_TRACE_TOKEN. The
+// tracing is disabled when DEBUG_TRACE_TOKEN is not set.
+func (s *server) isDebugTraced(r *http.Request) bool {
+	token := r.Header.Get(debugTraceHeader)
+	if s.debugTraceToken == "" || token == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.debugTraceToken)) == 1
+}
+
+// announceDebugLogs declares the trailer of the logs, which has to be done before the response is written.
+func announceDebugLogs(w http.ResponseWriter) {
+	w.Header().Add("Trailer", debugLogsTrailer)
+}
+
+// writeDebugLogs sends the captured logs, as newline delimited JSON encoded in base64, in the X-Debug-Logs trailer.
+func writeDebugLogs(w http.ResponseWriter, d *debugCapture) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	logs := d.buf.Bytes()
+	if d.truncated {
+		logs = append(logs, "...\n"...)
+	}
+
+	w.Header().Set(debugLogsTrailer, base64.StdEncoding.EncodeToString(logs))
+}
@@ -0,0 +1,99 @@
+package gofr
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/middleware"
+)
+
+func TestServer_DebugTrace(t *testing.T) {
+	s := &server{debugTraceToken: "s3cr3t"}
+	s.contextPool.New = func() interface{} {
+		return NewContext(nil, nil, &Gofr{})
+	}
+
+	ts := httptest.NewServer(s.contextInjector(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, _ := r.Context().Value(gofrContextkey).(*Context)
+
+		c.Logger.Debug("order fetched from the cache")
+
+		_, _ = w.Write([]byte(`{"data":"ok"}`))
+	})))
+	defer ts.Close()
+
+	tests := []struct {
+		desc   string
+		token  string
+		traced bool
+	}{
+		{"valid token", "s3cr3t", true},
+		{"invalid token", "guess", false},
+		{"header not set", "", false},
+	}
+
+	for i, tc := range tests {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/orders", http.NoBody)
+		if tc.token != "" {
+			req.Header.Set(debugTraceHeader, tc.token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc) {
+			continue
+		}
+
+		// the trailers are read after the body
+		_, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		logs, _ := base64.StdEncoding.DecodeString(resp.Trailer.Get(debugLogsTrailer))
+
+		assert.Equal(t, tc.traced, strings.Contains(string(logs), "order fetched from the cache"), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestServer_DevErrorPageLogs(t *testing.T) {
+	s := &server{}
+	s.contextPool.New = func() interface{} {
+		return NewContext(nil, nil, &Gofr{})
+	}
+
+	handler := middleware.DevErrorPage(s.contextInjector(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, _ := r.Context().Value(gofrContextkey).(*Context)
+
+		c.Logger.Info("order not found in the cache")
+
+		w.WriteHeader(http.StatusInternalServerError)
+	})))
+
+	r := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+	r.Header.Set("Accept", "text/html")
+
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "order not found in the cache", "logs of the request are not shown in the error page")
+}
+
+func TestDebugCapture_Truncated(t *testing.T) {
+	d := &debugCapture{}
+
+	_, _ = d.Write([]byte(strings.Repeat("a", maxDebugLogs-1)))
+	_, _ = d.Write([]byte("bc"))
+
+	w := httptest.NewRecorder()
+	writeDebugLogs(w, d)
+
+	logs, _ := base64.StdEncoding.DecodeString(w.Header().Get(debugLogsTrailer))
+
+	assert.True(t, strings.HasSuffix(string(logs), "a...\n"), "logs exceeding the limit are not truncated")
+}
@@ -0,0 +1,397 @@
+package gofr
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+const (
+	defaultDoctorTimeout = 5 * time.Second
+
+	doctorCommand = "doctor"
+
+	statusPass = "PASS"
+	statusWarn = "WARN"
+	statusFail = "FAIL"
+)
+
+// diagnosis is the result of a check of the doctor, the check is warned when it fails, but does not stop the app from
+// serving, ex: an optional service which is down.
+type diagnosis struct {
+	kind   string
+	name   string
+	status string
+	detail string
+}
+
+// configRule validates the value of a config, the configs which are not set are not validated.
+type configRule func(value string) error
+
+//nolint:gochecknoglobals // the rules of the configs are read-only, exit is replaced in the tests
+var (
+	exit = os.Exit
+
+	configRules = map[string]configRule{
+		"HTTP_PORT":                    portRule,
+		"HTTPS_PORT":                   portRule,
+		"GRPC_PORT":                    portRule,
+		"METRICS_PORT":                 portRule,
+		"REQUEST_DEADLINE":             intRule(0),
+		"GRPC_HEALTH_CHECK_INTERVAL":   intRule(1),
+		"SUBSCRIBER_CONCURRENCY":       intRule(1),
+		"SUBSCRIBER_RETRIES":           intRule(0),
+		"SUBSCRIBER_RETRY_BACKOFF":     intRule(1),
+		"SUBSCRIBER_RETRY_MAX_BACKOFF": intRule(1),
+		"SUBSCRIBER_RETRY_MULTIPLIER":  floatRule,
+		"SUBSCRIBER_RETRY_JITTER":      floatRule,
+		"OUTBOX_RELAY_INTERVAL":        intRule(1),
+		"OUTBOX_RELAY_BATCH_SIZE":      intRule(1),
+		"LEAK_DETECTION_INTERVAL":      intRule(1),
+		"LEAK_DETECTION_SAMPLES":       intRule(3),
+		"RATE_LIMIT_RATE":              floatRule,
+		"RATE_LIMIT_BURST":             intRule(1),
+		"RATE_LIMIT_QUOTA":             intRule(1),
+		"RATE_LIMIT_QUOTA_PERIOD":      intRule(1),
+		"RATE_LIMIT_RELOAD_INTERVAL":   intRule(1),
+		"PUBSUB_FAILURE_THRESHOLD":     intRule(1),
+		"PUBSUB_FAILBACK_INTERVAL":     intRule(0),
+		"DOCTOR_TIMEOUT":               intRule(1),
+		"RESPONSE_MAX_DEPTH":           intRule(1),
+		"RESPONSE_MAX_BYTES":           intRule(1),
+		"READINESS_THRESHOLD":          floatRule,
+		"VALIDATE_HEADERS":             boolRule,
+		"STARTUP_DIAGNOSTICS":          boolRule,
+		"LEAK_DETECTION":               boolRule,
+		"NOTIFICATION_PREVIEW":         boolRule,
+		"SERVER_TIMING":                boolRule,
+		"ROUTE_CONFLICTS":              oneOfRule(routeConflictsWarn, routeConflictsFail),
+		"LOG_LEVEL":                    oneOfRule("DEBUG", "INFO", "WARN", "ERROR", "FATAL"),
+		"PUBSUB_CLOUDEVENTS":           oneOfRule("structured", "binary"),
+		"RESPONSE_LIMIT_MODE":          oneOfRule(responseLimitError, responseLimitTruncate),
+		"PUBSUB_BACKEND": oneOfRule(datastore.Kafka, datastore.Avro, datastore.EventHub, datastore.EventBridge,
+			datastore.GooglePubSub, datastore.InProcess, datastore.NATS, datastore.RabbitMQ, datastore.AWSSQS, datastore.ServiceBus),
+		"TIME_ZONE":                timeZoneRule,
+		"KEY_FILE":                 fileRule,
+		"CERTIFICATE_FILE":         fileRule,
+		"METRICS_KEY_FILE":         fileRule,
+		"METRICS_CERTIFICATE_FILE": fileRule,
+		"PROBE_CLIENT_CA_FILE":     fileRule,
+	}
+
+	// configRequires are the configs which are required along with a config, when it is set.
+	configRequires = map[string][]string{
+		"KEY_FILE":                 {"CERTIFICATE_FILE"},
+		"CERTIFICATE_FILE":         {"KEY_FILE"},
+		"METRICS_KEY_FILE":         {"METRICS_CERTIFICATE_FILE"},
+		"METRICS_CERTIFICATE_FILE": {"METRICS_KEY_FILE"},
+		"KAFKA_HOSTS":              {"KAFKA_TOPIC"},
+		"DB_HOST":                  {"DB_DIALECT", "DB_PORT"},
+		"CASS_DB_HOST":             {"CASS_DB_KEYSPACE"},
+		"MONGO_DB_HOST":            {"MONGO_DB_NAME"},
+		"OUTBOX_TABLE":             {"DB_HOST"},
+	}
+)
+
+func portRule(value string) error {
+	if p, err := strconv.Atoi(value); err != nil || p < 0 || p > 65535 {
+		return errors.Error("must be a port number between 0 and 65535")
+	}
+
+	return nil
+}
+
+func intRule(minimum int) configRule {
+	return func(value string) error {
+		if n, err := strconv.Atoi(value); err != nil || n < minimum {
+			return fmt.Errorf("must be an integer not less than %v", minimum)
+		}
+
+		return nil
+	}
+}
+
+func floatRule(value string) error {
+	if f, err := strconv.ParseFloat(value, 64); err != nil || f <= 0 {
+		return errors.Error("must be a positive number")
+	}
+
+	return nil
+}
+
+func boolRule(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return errors.Error("must be true or false")
+	}
+
+	return nil
+}
+
+func oneOfRule(values ...string) configRule {
+	return func(value string) error {
+		for _, v := range values {
+			if strings.EqualFold(v, value) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("must be one of %v", strings.Join(values, ", "))
+	}
+}
+
+func timeZoneRule(value string) error {
+	if _, err := time.LoadLocation(value); err != nil {
+		return errors.Error("must be a time zone of the IANA database, ex: Europe/Dublin")
+	}
+
+	return nil
+}
+
+func fileRule(value string) error {
+	if _, err := os.Stat(value); err != nil {
+		return fmt.Errorf("file can not be read: %v", err)
+	}
+
+	return nil
+}
+
+// isDoctorCommand reports whether the app is run with the doctor command, ie: ./app doctor
+func isDoctorCommand(args []string) bool {
+	for _, a := range args {
+		if strings.TrimLeft(a, "-") == doctorCommand {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+Doctor checks the configs, connects to the datastores, the pubsub and the services of the health check, checks that
+the ports of the servers are available, and that none of the routes are shadowed, and writes a report of the checks to
+w. It returns whether none of the checks failed, the optional services which are down are only warned.
+
+Every connection is checked with the timeout of DOCTOR_TIMEOUT seconds (default is 5). The server apps run the doctor
+and exit, instead of starting, when they are run with the doctor argument, ie: ./app doctor, and run it before
+starting when STARTUP_DIAGNOSTICS is true.
+*/
+func (g *Gofr) Doctor(w io.Writer) bool {
+	timeout := seconds(g.Config, "DOCTOR_TIMEOUT", defaultDoctorTimeout)
+
+	diagnoses := g.diagnoseConfigs()
+	diagnoses = append(diagnoses, g.diagnoseConnections(timeout)...)
+	diagnoses = append(diagnoses, g.diagnosePorts()...)
+	diagnoses = append(diagnoses, g.diagnoseRoutes()...)
+
+	return writeDiagnoses(w, diagnoses)
+}
+
+func (g *Gofr) diagnoseConfigs() []diagnosis {
+	keys := make([]string, 0, len(configRules)+len(configRequires))
+
+	for k := range configRules {
+		keys = append(keys, k)
+	}
+
+	for k := range configRequires {
+		if _, ok := configRules[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var diagnoses []diagnosis
+
+	for _, k := range keys {
+		value := g.Config.Get(k)
+		if value == "" {
+			continue
+		}
+
+		d := diagnosis{kind: "config", name: k, status: statusPass}
+
+		if rule, ok := configRules[k]; ok {
+			if err := rule(value); err != nil {
+				d.status, d.detail = statusFail, err.Error()
+			}
+		}
+
+		for _, required := range configRequires[k] {
+			if d.status == statusPass && g.Config.Get(required) == "" {
+				d.status, d.detail = statusFail, required+" is required along with it"
+			}
+		}
+
+		diagnoses = append(diagnoses, d)
+	}
+
+	return diagnoses
+}
+
+func (g *Gofr) diagnoseConnections(timeout time.Duration) []diagnosis {
+	diagnoses := make([]diagnosis, 0, len(g.DatabaseHealth)+len(g.ServiceHealth)+len(g.OptionalServiceHealth))
+
+	for i, h := range g.DatabaseHealth {
+		diagnoses = append(diagnoses, checkConnection(h, "datastore", fmt.Sprintf("datastore %v", i+1), statusFail, timeout))
+	}
+
+	for i, h := range g.ServiceHealth {
+		diagnoses = append(diagnoses, checkConnection(h, "service", fmt.Sprintf("service %v", i+1), statusFail, timeout))
+	}
+
+	for i, h := range g.OptionalServiceHealth {
+		diagnoses = append(diagnoses, checkConnection(h, "service", fmt.Sprintf("optional service %v", i+1), statusWarn, timeout))
+	}
+
+	return diagnoses
+}
+
+// checkConnection checks the health of a dependency, the dependency which does not report its health in the timeout is
+// reported by its default name, as its name is not known.
+func checkConnection(check HealthCheck, kind, name, downStatus string, timeout time.Duration) diagnosis {
+	result := make(chan types.Health, 1)
+
+	go func() { result <- check() }()
+
+	select {
+	case h := <-result:
+		if h.Name != "" {
+			name = h.Name
+		}
+
+		if h.Host != "" {
+			name += " (" + h.Host + ")"
+		}
+
+		if h.Status == pkg.StatusUp {
+			return diagnosis{kind: kind, name: name, status: statusPass}
+		}
+
+		return diagnosis{kind: kind, name: name, status: downStatus, detail: "status is " + h.Status}
+	case <-time.After(timeout):
+		return diagnosis{kind: kind, name: name, status: downStatus, detail: fmt.Sprintf("timed out after %v", timeout)}
+	}
+}
+
+// diagnosePorts checks that the ports of the servers which are started can be listened on.
+func (g *Gofr) diagnosePorts() []diagnosis {
+	if g.Server == nil {
+		return nil
+	}
+
+	s := g.Server
+	ports := []struct {
+		name string
+		port int
+	}{{"HTTP", s.HTTP.Port}}
+
+	if (s.HTTPS.KeyFile != "" && s.HTTPS.CertificateFile != "") || s.HTTPS.ACME != nil {
+		ports = append(ports, struct {
+			name string
+			port int
+		}{"HTTPS", s.HTTPS.Port})
+	}
+
+	if s.GRPC.Port != 0 {
+		ports = append(ports, struct {
+			name string
+			port int
+		}{"GRPC", s.GRPC.Port})
+	}
+
+	if s.MetricsPort != 0 && s.MetricsPort != s.HTTP.Port {
+		ports = append(ports, struct {
+			name string
+			port int
+		}{"metrics", s.MetricsPort})
+	}
+
+	diagnoses := make([]diagnosis, 0, len(ports))
+
+	for _, p := range ports {
+		d := diagnosis{kind: "port", name: fmt.Sprintf("%v :%v", p.name, p.port), status: statusPass}
+
+		l, err := net.Listen("tcp", ":"+strconv.Itoa(p.port))
+		if err != nil {
+			d.status, d.detail = statusFail, err.Error()
+		} else {
+			_ = l.Close()
+		}
+
+		diagnoses = append(diagnoses, d)
+	}
+
+	return diagnoses
+}
+
+// diagnoseRoutes reports the routes which are never served, they are failed when ROUTE_CONFLICTS is fail.
+func (g *Gofr) diagnoseRoutes() []diagnosis {
+	status := statusWarn
+	if strings.EqualFold(g.Config.Get("ROUTE_CONFLICTS"), routeConflictsFail) {
+		status = statusFail
+	}
+
+	conflicts := g.RouteConflicts()
+	diagnoses := make([]diagnosis, 0, len(conflicts))
+
+	for _, c := range conflicts {
+		diagnoses = append(diagnoses, diagnosis{kind: "route", name: c.Route.Method + " " + c.Route.Path, status: status,
+			detail: c.String()})
+	}
+
+	return diagnoses
+}
+
+// writeDiagnoses writes the report of the diagnoses, and returns whether none of them failed.
+func writeDiagnoses(w io.Writer, diagnoses []diagnosis) bool {
+	counts := make(map[string]int)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "CHECK\tNAME\tSTATUS\tDETAIL")
+
+	for _, d := range diagnoses {
+		counts[d.status]++
+
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\n", d.kind, d.name, d.status, d.detail)
+	}
+
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\n%v passed, %v warned, %v failed\n", counts[statusPass], counts[statusWarn], counts[statusFail])
+
+	return counts[statusFail] == 0
+}
+
+/*
+diagnose runs the doctor before the server starts, when the app is run with the doctor argument, or STARTUP_DIAGNOSTICS
+is true. It returns whether the server is to be started, the app run with the doctor argument exits after the report,
+with the exit code 1 when a check failed.
+*/
+func (g *Gofr) diagnose(args []string, w io.Writer) bool {
+	if isDoctorCommand(args) {
+		if !g.Doctor(w) {
+			exit(1)
+		}
+
+		return false
+	}
+
+	if getBool(g.Config.Get("STARTUP_DIAGNOSTICS")) && !g.Doctor(w) {
+		g.Logger.Warn("startup diagnostics failed, check the report of the doctor")
+	}
+
+	return true
+}
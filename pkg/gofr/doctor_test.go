@@ -0,0 +1,133 @@
+package gofr
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+)
+
+func TestGofr_diagnoseConfigs(t *testing.T) {
+	tests := []struct {
+		configs  map[string]string
+		expected []diagnosis
+	}{
+		{map[string]string{}, nil},
+		{map[string]string{"HTTP_PORT": "8000", "LOG_LEVEL": "debug", "PUBSUB_BACKEND": "kafka", "KAFKA_HOSTS": "localhost:9092",
+			"KAFKA_TOPIC": "orders"}, []diagnosis{{"config", "HTTP_PORT", statusPass, ""}, {"config", "KAFKA_HOSTS", statusPass, ""},
+			{"config", "LOG_LEVEL", statusPass, ""}, {"config", "PUBSUB_BACKEND", statusPass, ""}}},
+		{map[string]string{"HTTP_PORT": "80000", "REQUEST_DEADLINE": "-1", "READINESS_THRESHOLD": "high"},
+			[]diagnosis{{"config", "HTTP_PORT", statusFail, "must be a port number between 0 and 65535"},
+				{"config", "READINESS_THRESHOLD", statusFail, "must be a positive number"},
+				{"config", "REQUEST_DEADLINE", statusFail, "must be an integer not less than 0"}}},
+		{map[string]string{"KAFKA_HOSTS": "localhost:9092", "TIME_ZONE": "Mars/Olympus", "VALIDATE_HEADERS": "yes"},
+			[]diagnosis{{"config", "KAFKA_HOSTS", statusFail, "KAFKA_TOPIC is required along with it"},
+				{"config", "TIME_ZONE", statusFail, "must be a time zone of the IANA database, ex: Europe/Dublin"},
+				{"config", "VALIDATE_HEADERS", statusFail, "must be true or false"}}},
+		{map[string]string{"KEY_FILE": "doctor.go"}, []diagnosis{{"config", "KEY_FILE", statusFail,
+			"CERTIFICATE_FILE is required along with it"}}},
+	}
+
+	for i, tc := range tests {
+		g := &Gofr{Config: &config.MockConfig{Data: tc.configs}}
+
+		assert.Equal(t, tc.expected, g.diagnoseConfigs(), "TEST[%d], failed.\n", i)
+	}
+}
+
+func TestGofr_diagnoseConnections(t *testing.T) {
+	slow := make(chan struct{})
+	defer close(slow)
+
+	g := &Gofr{
+		DatabaseHealth: []HealthCheck{
+			func() types.Health { return types.Health{Name: "mongo", Status: pkg.StatusUp, Host: "localhost"} },
+			func() types.Health { return types.Health{Name: "redis", Status: pkg.StatusDown} },
+		},
+		ServiceHealth: []HealthCheck{func() types.Health {
+			<-slow
+			return types.Health{}
+		}},
+		OptionalServiceHealth: []HealthCheck{func() types.Health { return types.Health{Name: "payments", Status: pkg.StatusDown} }},
+	}
+
+	expected := []diagnosis{
+		{"datastore", "mongo (localhost)", statusPass, ""},
+		{"datastore", "redis", statusFail, "status is DOWN"},
+		{"service", "service 1", statusFail, "timed out after 10ms"},
+		{"service", "payments", statusWarn, "status is DOWN"},
+	}
+
+	assert.Equal(t, expected, g.diagnoseConnections(10*time.Millisecond))
+}
+
+func TestGofr_diagnosePorts(t *testing.T) {
+	busy, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("port could not be listened on: %v", err)
+	}
+
+	defer busy.Close()
+
+	httpPort := freePort(t)
+	busyPort := busy.Addr().(*net.TCPAddr).Port
+
+	g := &Gofr{Server: &server{MetricsPort: httpPort}}
+	g.Server.HTTP.Port = httpPort
+	g.Server.GRPC.Port = busyPort
+
+	diagnoses := g.diagnosePorts()
+
+	assert.Len(t, diagnoses, 2, "the metrics served on the HTTP port are not checked")
+	assert.Equal(t, statusPass, diagnoses[0].status)
+	assert.Equal(t, statusFail, diagnoses[1].status, "the port which is in use fails")
+}
+
+func TestGofr_Doctor(t *testing.T) {
+	port := freePort(t)
+	b := new(bytes.Buffer)
+
+	g := &Gofr{Config: &config.MockConfig{Data: map[string]string{"LOG_LEVEL": "TRACE", "DOCTOR_TIMEOUT": "1"}},
+		Server:                &server{},
+		OptionalServiceHealth: []HealthCheck{func() types.Health { return types.Health{Name: "payments"} }}}
+	g.Server.HTTP.Port = port
+
+	assert.False(t, g.Doctor(b))
+	assert.Contains(t, b.String(), "CHECK")
+	assert.Contains(t, b.String(), "must be one of DEBUG, INFO, WARN, ERROR, FATAL")
+	assert.Contains(t, b.String(), "2 passed, 1 warned, 1 failed")
+}
+
+func TestGofr_diagnose(t *testing.T) {
+	defer func() { exit = os.Exit }()
+
+	var code int
+
+	exit = func(c int) { code = c }
+
+	b := new(bytes.Buffer)
+	g := &Gofr{Config: &config.MockConfig{Data: map[string]string{"HTTP_PORT": "http"}}, Server: &server{},
+		Logger: log.NewMockLogger(b)}
+	g.Server.HTTP.Port = freePort(t)
+
+	assert.False(t, g.diagnose([]string{"doctor"}, io.Discard), "the server is not started by the doctor command")
+	assert.Equal(t, 1, code)
+
+	assert.True(t, g.diagnose(nil, io.Discard), "the doctor is not run")
+	assert.Empty(t, b.String())
+
+	g.Config = &config.MockConfig{Data: map[string]string{"STARTUP_DIAGNOSTICS": "true", "HTTP_PORT": "http"}}
+
+	assert.True(t, g.diagnose(nil, io.Discard), "the server is started after the startup diagnostics")
+	assert.True(t, strings.Contains(b.String(), "startup diagnostics failed"))
+}
@@ -0,0 +1,223 @@
+package gofr
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/middleware"
+)
+
+const (
+	// ExperimentUnitUser assigns the variants by the subject of the JWT, or the user id sent by the gateway.
+	ExperimentUnitUser = "user"
+	// ExperimentUnitTenant assigns the variants by the tenant, so that all the users of a tenant see the same variant.
+	ExperimentUnitTenant = "tenant"
+)
+
+// ExperimentDefinition is an A/B experiment, the definitions are read as a JSON array from the config EXPERIMENTS,
+// ex: [{"name":"checkout-flow","variants":[{"name":"control","weight":50},{"name":"one-page","weight":50}]}].
+// The config is read on every assignment, hence the experiments can be changed by a remote config provider.
+type ExperimentDefinition struct {
+	Name string `json:"name"`
+	// Unit is what the variants are assigned by, user or tenant. Default is user.
+	Unit string `json:"unit"`
+	// Variants are assigned in proportion to their weights, the first one is the control.
+	Variants []ExperimentVariant `json:"variants"`
+	// Disabled experiments assign the control to everyone, without exposures.
+	Disabled bool `json:"disabled"`
+}
+
+// ExperimentVariant is a variant of an experiment.
+type ExperimentVariant struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+}
+
+// ExperimentExposure is the event published when a variant of an experiment is assigned to a user or a tenant.
+type ExperimentExposure struct {
+	Experiment    string    `json:"experiment"`
+	Variant       string    `json:"variant"`
+	Unit          string    `json:"unit"`
+	UnitID        string    `json:"unitId"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
+// experiments caches the definitions parsed from the config, until the config is changed.
+type experiments struct {
+	mu          sync.Mutex
+	raw         string
+	definitions map[string]*ExperimentDefinition
+}
+
+func (e *experiments) get(g *Gofr, name string) *ExperimentDefinition {
+	if g == nil || g.Config == nil {
+		return nil
+	}
+
+	raw := g.Config.Get("EXPERIMENTS")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if raw != e.raw || e.definitions == nil {
+		e.raw, e.definitions = raw, parseExperiments(g, raw)
+	}
+
+	return e.definitions[name]
+}
+
+func parseExperiments(g *Gofr, raw string) map[string]*ExperimentDefinition {
+	definitions := make(map[string]*ExperimentDefinition)
+
+	if raw == "" {
+		return definitions
+	}
+
+	var list []*ExperimentDefinition
+
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		g.Logger.Errorf("EXPERIMENTS is invalid, the experiments are disabled: %v", err)
+		return definitions
+	}
+
+	for _, d := range list {
+		if d.Name == "" || len(d.Variants) == 0 {
+			g.Logger.Warnf("experiment %q is ignored, as it does not have a name or variants", d.Name)
+			continue
+		}
+
+		definitions[d.Name] = d
+	}
+
+	return definitions
+}
+
+// Experiment is the assignment of a request to a variant of an experiment.
+type Experiment struct {
+	name       string
+	c          *Context
+	definition *ExperimentDefinition
+
+	once    sync.Once
+	variant string
+}
+
+// Experiment returns the experiment of the name for the request. The variant is assigned by a hash of the experiment
+// name and the user, or the tenant, hence the same user gets the same variant on every request and every instance.
+func (c *Context) Experiment(name string) *Experiment {
+	var definition *ExperimentDefinition
+
+	if c.Gofr != nil {
+		definition = c.Gofr.experiments.get(c.Gofr, name)
+	}
+
+	return &Experiment{name: name, c: c, definition: definition}
+}
+
+// Variant returns the name of the variant assigned to the request. It is empty when the experiment is not defined,
+// and is the control when the experiment is disabled or the request does not have a user or a tenant to assign by.
+// The exposure is published to the topic EXPERIMENTS_EXPOSURE_TOPIC, or the default topic, when pubsub is configured,
+// and is logged otherwise, once for an Experiment.
+func (e *Experiment) Variant() string {
+	e.once.Do(func() {
+		d := e.definition
+		if d == nil {
+			return
+		}
+
+		e.variant = d.Variants[0].Name
+
+		unitID := e.unitID()
+		if d.Disabled || unitID == "" {
+			return
+		}
+
+		e.variant = assignVariant(d, unitID)
+
+		e.expose(unitID)
+	})
+
+	return e.variant
+}
+
+func (e *Experiment) unit() string {
+	if e.definition.Unit == ExperimentUnitTenant {
+		return ExperimentUnitTenant
+	}
+
+	return ExperimentUnitUser
+}
+
+func (e *Experiment) unitID() string {
+	if e.c.req == nil || e.c.Request() == nil {
+		return ""
+	}
+
+	r := e.c.Request()
+
+	if e.unit() == ExperimentUnitTenant {
+		tenant, _ := r.Context().Value(middleware.TenantIDKey).(string)
+		return tenant
+	}
+
+	return authenticatedSubject(e.c)
+}
+
+// assignVariant maps the hash of the experiment name and the unit into the cumulative weights of the variants, the
+// name is hashed along with the unit, so that the assignments of the experiments are independent of each other.
+func assignVariant(d *ExperimentDefinition, unitID string) string {
+	total := 0
+
+	for _, v := range d.Variants {
+		total += max(v.Weight, 0)
+	}
+
+	if total == 0 {
+		return d.Variants[0].Name
+	}
+
+	sum := sha256.Sum256([]byte(d.Name + ":" + unitID))
+	bucket := int(binary.BigEndian.Uint64(sum[:8]) % uint64(total))
+
+	for _, v := range d.Variants {
+		bucket -= max(v.Weight, 0)
+		if bucket < 0 {
+			return v.Name
+		}
+	}
+
+	return d.Variants[0].Name
+}
+
+func (e *Experiment) expose(unitID string) {
+	exposure := ExperimentExposure{
+		Experiment: e.name,
+		Variant:    e.variant,
+		Unit:       e.unit(),
+		UnitID:     unitID,
+		Time:       e.c.Now(),
+		// the request is present, as the unit is read from it
+		CorrelationID: e.c.Request().Header.Get("X-Correlation-ID"),
+	}
+
+	g := e.c.Gofr
+	if g == nil || g.PubSub == nil {
+		if e.c.Logger != nil {
+			e.c.Logger.Infof("experiment %v: %v %v is exposed to the variant %v", e.name, exposure.Unit, unitID, e.variant)
+		}
+
+		return
+	}
+
+	opts := &pubsub.PublishOptions{Topic: g.Config.Get("EXPERIMENTS_EXPOSURE_TOPIC")}
+
+	// the failure to publish an exposure does not fail the request, the variant is still assigned
+	if err := g.PubSub.PublishEventWithOptions(unitID, exposure, nil, opts); err != nil && e.c.Logger != nil {
+		e.c.Logger.Errorf("exposure of experiment %v could not be published: %v", e.name, err)
+	}
+}
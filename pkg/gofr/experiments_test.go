@@ -0,0 +1,137 @@
+package gofr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
+)
+
+const checkoutExperiments = `[
+	{"name": "checkout-flow", "variants": [{"name": "control", "weight": 50}, {"name": "one-page", "weight": 50}]},
+	{"name": "pricing", "unit": "tenant", "variants": [{"name": "control", "weight": 0}, {"name": "discount", "weight": 1}]},
+	{"name": "search", "disabled": true, "variants": [{"name": "control", "weight": 0}, {"name": "semantic", "weight": 1}]},
+	{"name": "invalid", "variants": []}
+]`
+
+type mockExposurePublisher struct {
+	pubsub.PublisherSubscriber
+	topics    []string
+	exposures []ExperimentExposure
+}
+
+func (m *mockExposurePublisher) PublishEventWithOptions(_ string, value interface{}, _ map[string]string,
+	options *pubsub.PublishOptions) error {
+	m.topics = append(m.topics, options.Topic)
+	m.exposures = append(m.exposures, value.(ExperimentExposure))
+
+	return nil
+}
+
+func experimentContext(g *Gofr, key, value interface{}) *Context {
+	r := httptest.NewRequest(http.MethodGet, "/checkout", http.NoBody)
+	if key != nil {
+		r = r.WithContext(context.WithValue(r.Context(), key, value))
+	}
+
+	c := NewContext(nil, request.NewHTTPRequest(r), g)
+	c.Context = r.Context()
+
+	return c
+}
+
+func TestContext_Experiment(t *testing.T) {
+	publisher := &mockExposurePublisher{}
+	c := &config.MockConfig{Data: map[string]string{"EXPERIMENTS": checkoutExperiments, "EXPERIMENTS_EXPOSURE_TOPIC": "exposures",
+		"TRUSTED_GATEWAY": "true"}}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(new(bytes.Buffer))}
+	g.PubSub = publisher
+
+	tests := []struct {
+		desc       string
+		experiment string
+		key        interface{}
+		value      string
+		variant    string
+		exposed    bool
+	}{
+		{"undefined experiment", "unknown", middleware.AuthenticatedUserIDKey, "u-1", "", false},
+		{"experiment without variants", "invalid", middleware.AuthenticatedUserIDKey, "u-1", "", false},
+		{"request without user", "checkout-flow", nil, "", "control", false},
+		{"disabled experiment", "search", middleware.AuthenticatedUserIDKey, "u-1", "control", false},
+		{"assigned by tenant", "pricing", middleware.TenantIDKey, "acme", "discount", true},
+		{"tenant experiment without tenant", "pricing", middleware.AuthenticatedUserIDKey, "u-1", "control", false},
+	}
+
+	for i, tc := range tests {
+		publisher.exposures = nil
+
+		e := experimentContext(g, tc.key, tc.value).Experiment(tc.experiment)
+
+		assert.Equal(t, tc.variant, e.Variant(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.variant, e.Variant(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.exposed, len(publisher.exposures) == 1, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.Equal(t, []string{"exposures"}, publisher.topics)
+}
+
+func TestContext_Experiment_Deterministic(t *testing.T) {
+	c := &config.MockConfig{Data: map[string]string{"EXPERIMENTS": checkoutExperiments, "TRUSTED_GATEWAY": "true"}}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(new(bytes.Buffer))}
+
+	counts := make(map[string]int)
+
+	for i := 0; i < 1000; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		variant := experimentContext(g, middleware.AuthenticatedUserIDKey, user).Experiment("checkout-flow").Variant()
+
+		assert.Equal(t, variant, experimentContext(g, middleware.AuthenticatedUserIDKey, user).Experiment("checkout-flow").Variant(),
+			"the variant of %v is changed", user)
+
+		counts[variant]++
+	}
+
+	assert.InDelta(t, 500, counts["control"], 75)
+	assert.InDelta(t, 500, counts["one-page"], 75)
+
+	// the experiments are read again when the config is changed, ex: by a remote config provider
+	c.Data["EXPERIMENTS"] = `[{"name": "checkout-flow", "variants": [{"name": "one-page", "weight": 1}]}]`
+
+	assert.Equal(t, "one-page", experimentContext(g, middleware.AuthenticatedUserIDKey, "user-1").Experiment("checkout-flow").Variant())
+}
+
+func TestContext_Experiment_UntrustedUser(t *testing.T) {
+	c := &config.MockConfig{Data: map[string]string{"EXPERIMENTS": checkoutExperiments}}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(new(bytes.Buffer))}
+
+	// without a trusted gateway, the user id sent by the client is not used to bucket the request
+	for i := 0; i < 100; i++ {
+		e := experimentContext(g, middleware.AuthenticatedUserIDKey, fmt.Sprintf("user-%d", i)).Experiment("checkout-flow")
+
+		assert.Equal(t, "", e.unitID(), "TEST[%d], failed.\nuser id sent by the client", i)
+		assert.Equal(t, "control", e.Variant(), "TEST[%d], failed.\nuser id sent by the client", i)
+	}
+}
+
+func TestContext_Experiment_LoggedExposure(t *testing.T) {
+	b := new(bytes.Buffer)
+	c := &config.MockConfig{Data: map[string]string{"EXPERIMENTS": checkoutExperiments}}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(b)}
+
+	ctx := experimentContext(g, middleware.TenantIDKey, "acme")
+	ctx.Logger = log.NewMockLogger(b)
+
+	assert.Equal(t, "discount", ctx.Experiment("pricing").Variant())
+	assert.Contains(t, b.String(), "experiment pricing: tenant acme is exposed to the variant discount")
+}
@@ -17,13 +17,20 @@ package gofr
 
 import (
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"gofr.dev/pkg/datastore"
 
+	"gofr.dev/pkg/gofr/clock"
+	"gofr.dev/pkg/gofr/i18n"
 	"gofr.dev/pkg/gofr/metrics"
+	"gofr.dev/pkg/gofr/static"
+	"gofr.dev/pkg/gofr/template"
 	"gofr.dev/pkg/log"
 	"gofr.dev/pkg/notifier"
+	"gofr.dev/pkg/plugin"
 )
 
 // Gofr is a struct that holds essential information about an application built with the GoFr framework.
@@ -64,8 +71,76 @@ type Gofr struct {
 
 	// ServiceHealth is the health check data about the services connected to the application.
 	ServiceHealth []HealthCheck
+	// OptionalServiceHealth is the health check data about the services which the application can serve without,
+	// the application is DEGRADED, but ready, when they are down.
+	OptionalServiceHealth []HealthCheck
 	// DatabaseHealth is the health check data about the databases connected to the application.
 	DatabaseHealth []HealthCheck
+
+	// DependencyWeights is the weight of a dependency, keyed by its health check name, used to compute readiness.
+	// A dependency which is not present has a weight of 1.
+	DependencyWeights map[string]float64
+	// ReadinessThreshold is the sum of the weights of the dependencies which are down, at which the application
+	// is not ready to serve traffic. Default is 1.
+	ReadinessThreshold float64
+
+	// Clock is the source of the current time returned by Context.Now, the system clock is used when it is nil.
+	Clock clock.Clock
+	// TimeZone is the default time zone of the requests, it is set from TIME_ZONE. Local time zone is used when it is nil.
+	TimeZone *time.Location
+	// RequestDeadline is the default deadline of the calls made using the Context of a request or a message, so that a
+	// slow dependency can not spend more than the latency budget of the route. It is set from REQUEST_DEADLINE in
+	// seconds, the calls have no default deadline when it is zero.
+	RequestDeadline time.Duration
+
+	// Messages is the message bundle used to translate the responses, it is loaded from the directory I18N_DIR.
+	Messages *i18n.Bundle
+
+	// Operations stores the state of the operations enqueued using Context.Enqueue, they are stored in memory when
+	// it is nil. The number of workers and the size of the queue are set using OPERATION_WORKERS and OPERATION_QUEUE_SIZE.
+	Operations OperationStore
+	operations *operationQueue
+
+	// outbox publishes the events written using Context.WriteOutbox, it is enabled by OUTBOX_TABLE.
+	outbox *outbox
+
+	// leaks logs the suspected leaks of the resources of the application, it is enabled by LEAK_DETECTION.
+	leaks *leakDetector
+
+	// notificationTemplates are the templates of the notifications sent using Context.Notify, by their names.
+	notificationTemplates map[string]*template.NotificationTemplate
+
+	// contextExports are the names of the values of the context forwarded to the downstream services, CONTEXT_EXPORT.
+	contextExports []string
+
+	routes []*Route
+	// handlers and middlewares are registered by their names, to be used in the routing manifest.
+	handlers    map[string]Handler
+	middlewares map[string]Middleware
+
+	// plugins are the plugins registered by the blank imports of the application, by their names.
+	plugins map[string]plugin.Plugin
+
+	experiments experiments
+
+	// PrivacyAudit records the exports and the erasures of personal data, the records are logged when it is nil.
+	PrivacyAudit    PrivacyAuditTrail
+	privacyHandlers map[string]PrivacyHandler
+
+	// subscriber consumes the messages of the topics registered using Subscribe.
+	subscriber *subscriber
+
+	// routeSwitch holds the routes disabled at runtime using DisableRoute.
+	routeSwitch routeSwitch
+
+	// assets are the static files served using Static.
+	assets *static.Assets
+
+	// shutdownHooks are the hooks of the stages of the shutdown, added using OnShutdown.
+	shutdownHooks map[ShutdownStage][]shutdownHook
+
+	// deprecatedConfigs are the config keys deprecated using DeprecateConfig, along with their replacements.
+	deprecatedConfigs map[string]string
 }
 
 // Start initiates the execution of the application. It checks if there is a command (cmd) associated with the Gofr instance.
@@ -73,48 +148,139 @@ type Gofr struct {
 // If no command is available, it starts the server by calling its Start method, also passing the logger.
 // This method effectively launches the application, handling both command-line and server-based execution scenarios.
 func (g *Gofr) Start() {
+	g.checkConfigKeys()
+
 	if g.cmd != nil {
 		g.cmd.Start(g.Logger)
 	} else {
+		g.checkRouteConflicts()
+
+		if !g.diagnose(os.Args[1:], os.Stdout) {
+			return
+		}
+
+		if g.Server.GRPC.Port != 0 && g.Server.GRPC.health != nil {
+			stop := make(chan struct{})
+			defer close(stop)
+
+			go g.gateGRPCTraffic(g.Server.GRPC.health, g.Server.GRPC.HealthCheckInterval, stop)
+		}
+
+		if g.subscriber != nil {
+			g.subscriber.start()
+		}
+
+		if g.outbox != nil {
+			g.outbox.start(g.DB(), g.PubSub, g.Logger)
+		}
+
+		if g.leaks != nil {
+			g.leaks.start(g.leakProbes(), g.Logger)
+		}
+
 		g.Server.Start(g.Logger)
+		g.shutdown()
 	}
 }
 
-func (g *Gofr) addRoute(method, path string, handler Handler) {
+func (g *Gofr) addRoute(method, path string, handler Handler, middlewares ...string) *Route {
+	mws := make([]Middleware, 0, len(middlewares))
+	for _, name := range middlewares {
+		mws = append(mws, g.middlewares[name])
+	}
+
+	return g.addRouteWith(&Route{Method: method, Path: path, middlewares: middlewares}, handler, mws)
+}
+
+/*
+handle adds the route with the handler wrapped in the middlewares, which are executed in order after the middlewares of
+the server, for ex:
+
+	app.GET("/admin", h.Admin, requireAdmin)
+
+The middlewares of a route are only supported by the default router, the application exits when the router is another.
+*/
+func (g *Gofr) handle(method, path string, handler Handler, middlewares []Middleware) *Route {
+	var names []string
+	for _, m := range middlewares {
+		names = append(names, middlewareName(m))
+	}
+
+	return g.addRouteWith(&Route{Method: method, Path: path, middlewares: names}, handler, middlewares)
+}
+
+// addRouteWith adds the route with the handler wrapped in the middlewares, the names of which are the middlewares of
+// the route.
+func (g *Gofr) addRouteWith(route *Route, handler Handler, middlewares []Middleware) *Route {
+	method, path := route.Method, route.Path
+	handler = route.validate(handler)
+
 	if g.cmd != nil {
 		g.cmd.Router.AddRoute(path, handler) // Ignoring method in CMD App.
 	} else {
 		if path != "/" {
 			path = strings.TrimSuffix(path, "/")
-			g.Server.Router.Route(method, path+"/", handler)
+			g.route(method, path+"/", handler, middlewares)
 		}
+		g.route(method, path, handler, middlewares)
+
+		route.Path = path
+		g.routes = append(g.routes, route)
+	}
+
+	return route
+}
+
+// route creates the route with the handler wrapped in the middlewares, which are executed after the middlewares
+// of the server. The middlewares of a route are only supported by the default router, the application exits when
+// a route with middlewares is added to another router, as serving it without them skips ex: its authentication.
+func (g *Gofr) route(method, path string, handler Handler, middlewares []Middleware) {
+	if len(middlewares) == 0 {
 		g.Server.Router.Route(method, path, handler)
+		return
 	}
+
+	r, ok := g.Server.Router.(*router)
+	if !ok {
+		g.Logger.Errorf("application is not started, as the route %v %v has middlewares, which are not supported by "+
+			"the router", method, path)
+		exit(1)
+
+		return
+	}
+
+	var h http.Handler = handler
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+
+	r.handle(method, path, h)
 }
 
-// GET adds a route for handling HTTP GET requests.
-func (g *Gofr) GET(path string, handler Handler) {
-	g.addRoute(http.MethodGet, path, handler)
+// GET adds a route for handling HTTP GET requests, whose handler is wrapped in the middlewares of the route.
+func (g *Gofr) GET(path string, handler Handler, middlewares ...Middleware) *Route {
+	return g.handle(http.MethodGet, path, handler, middlewares)
 }
 
-// PUT adds a route for handling HTTP PUT requests.
-func (g *Gofr) PUT(path string, handler Handler) {
-	g.addRoute(http.MethodPut, path, handler)
+// PUT adds a route for handling HTTP PUT requests, whose handler is wrapped in the middlewares of the route.
+func (g *Gofr) PUT(path string, handler Handler, middlewares ...Middleware) *Route {
+	return g.handle(http.MethodPut, path, handler, middlewares)
 }
 
-// POST adds a route for handling HTTP POST requests.
-func (g *Gofr) POST(path string, handler Handler) {
-	g.addRoute(http.MethodPost, path, handler)
+// POST adds a route for handling HTTP POST requests, whose handler is wrapped in the middlewares of the route.
+func (g *Gofr) POST(path string, handler Handler, middlewares ...Middleware) *Route {
+	return g.handle(http.MethodPost, path, handler, middlewares)
 }
 
-// DELETE adds a route for handling HTTP DELETE requests.
-func (g *Gofr) DELETE(path string, handler Handler) {
-	g.addRoute(http.MethodDelete, path, handler)
+// DELETE adds a route for handling HTTP DELETE requests, whose handler is wrapped in the middlewares of the route.
+func (g *Gofr) DELETE(path string, handler Handler, middlewares ...Middleware) *Route {
+	return g.handle(http.MethodDelete, path, handler, middlewares)
 }
 
-// PATCH adds a route for handling HTTP PATCH requests.
-func (g *Gofr) PATCH(path string, handler Handler) {
-	g.addRoute(http.MethodPatch, path, handler)
+// PATCH adds a route for handling HTTP PATCH requests, whose handler is wrapped in the middlewares of the route.
+func (g *Gofr) PATCH(path string, handler Handler, middlewares ...Middleware) *Route {
+	return g.handle(http.MethodPatch, path, handler, middlewares)
 }
 
 // Deprecated: EnableSwaggerUI is deprecated. Auto enabled swagger-endpoints.
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"strings"
 
-	"net"
 	"strconv"
 	"time"
 
@@ -15,6 +14,8 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"gofr.dev/pkg/gofr/types"
 	"gofr.dev/pkg/log"
@@ -22,7 +23,14 @@ import (
 
 type GRPC struct {
 	server *grpc.Server
+	health *health.Server
 	Port   int
+	// ListenConfig is where the server listens, it is set using the GRPC_LISTEN_ADDRESSES, GRPC_LISTEN_NETWORK,
+	// GRPC_ALLOWED_CIDRS and GRPC_MAX_CONNECTIONS configs.
+	ListenConfig
+	// HealthCheckInterval is the interval at which the serving status of the gRPC health service is updated
+	// from the readiness of the application.
+	HealthCheckInterval time.Duration
 }
 
 // Server return an object of grpc server
@@ -31,18 +39,26 @@ func (g *GRPC) Server() *grpc.Server {
 }
 
 // NewGRPCServer creates a gRPC server instance with OpenTelemetry tracing, OpenCensus stats handling,
-// unary interceptors for tracing and recovery, and a custom logging interceptor.
+// unary interceptors for tracing and recovery, and a custom logging interceptor. The options, ex: the keepalive
+// parameters, are applied after the interceptors.
 //
 //nolint:staticcheck //will be upgraded to grpc.NewServer in upcoming releases
-func NewGRPCServer() *grpc.Server {
-	return grpc.NewServer(
+func NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	return grpc.NewServer(append([]grpc.ServerOption{
 		grpc.StreamInterceptor(otelgrpc.StreamServerInterceptor(otelgrpc.WithTracerProvider(otel.GetTracerProvider()))),
 		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			otelgrpc.UnaryServerInterceptor(otelgrpc.WithTracerProvider(otel.GetTracerProvider())),
 			grpc_recovery.UnaryServerInterceptor(),
 			LoggingInterceptor(log.NewLogger()),
-		)))
+		))}, opts...)...)
+}
+
+// registerHealthServer registers the standard gRPC health service, its serving status follows the readiness
+// of the application.
+func (g *GRPC) registerHealthServer() {
+	g.health = health.NewServer()
+	healthpb.RegisterHealthServer(g.server, g.health)
 }
 
 // Start initializes and starts the gRPC server on the specified port.
@@ -54,7 +70,7 @@ func (g *GRPC) Start(logger log.Logger) {
 
 	logger.Infof("starting grpc server at %s", addr)
 
-	listener, err := net.Listen("tcp", addr)
+	listener, err := g.listen(g.Port)
 	if err != nil {
 		logger.Errorf("error in starting grpc server at %s: %s", addr, err)
 		return
@@ -125,3 +141,22 @@ func LoggingInterceptor(logger log.Logger) grpc.UnaryServerInterceptor {
 		return handler(ctx, req)
 	}
 }
+
+// stop stops the server once the requests in progress complete, the requests are canceled when the context is done.
+func (g *GRPC) stop(c context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		g.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-c.Done():
+		g.server.Stop()
+
+		return c.Err()
+	}
+}
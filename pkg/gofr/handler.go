@@ -1,7 +1,6 @@
 package gofr
 
 import (
-	"context"
 	"net/http"
 	"strings"
 	"time"
@@ -13,6 +12,7 @@ import (
 	"gofr.dev/pkg/gofr/template"
 	"gofr.dev/pkg/gofr/types"
 	"gofr.dev/pkg/middleware"
+	"gofr.dev/pkg/pii"
 )
 
 // Handler responds to HTTP request
@@ -27,30 +27,55 @@ type Handler func(c *Context) (interface{}, error)
 func (h Handler) ServeHTTP(_ http.ResponseWriter, r *http.Request) {
 	c, _ := r.Context().Value(gofrContextkey).(*Context)
 
-	data, err := h(c)
-
 	route := mux.CurrentRoute(r)
 	path, _ := route.GetPathTemplate()
 	// remove the trailing slash
 	path = strings.TrimSuffix(path, "/")
 
+	var (
+		data interface{}
+		err  error
+	)
+
+	if disabled, ok := c.disabledRoute(r.Method, path); ok {
+		err = routeDisabledError(disabled)
+	} else {
+		timing := middleware.ServerTimingFrom(c.Context)
+		if timing == nil {
+			data, err = h(c)
+		} else {
+			data, err = timeHandler(timing, c, h)
+		}
+
+		data = maskPII(c, data)
+	}
+
 	var errorResp error
 
 	if _, ok := err.(errors.EntityAlreadyExists); ok || err == nil {
 		errorResp = err
 	} else {
 		isPartialResponse := data != nil // since err!=nil we can check if data is not nil
-		errorResp = processErrors(err, path, r.Method, isPartialResponse)
+		errs := processErrors(err, path, r.Method, isPartialResponse, c.Now())
+		localizeErrors(c, errs)
 
-		// set the error in the context, which can be fetched in the logging middleware
-		ctx := context.WithValue(r.Context(), middleware.ErrorMessage, err.Error())
-		*r = *r.Clone(ctx)
+		errorResp = errs
+
+		// record the error, so that it can be logged by the logging middleware
+		middleware.SetHandlerError(r, err)
 	}
 
 	switch res := data.(type) {
 	case types.Response:
 		c.resp.Respond(&res, errorResp)
-	case template.Template, template.File, *types.Response, types.RawWithOptions:
+	case template.Template:
+		// the templates can translate the messages using {{t "key"}}, and refer to the static files using {{asset "name"}}
+		if res.Funcs == nil {
+			res.Funcs = c.templateFuncs()
+		}
+
+		c.resp.Respond(res, errorResp)
+	case template.File, types.FileDownload, types.Stream, *types.Response, types.RawWithOptions, *types.Operation:
 		c.resp.Respond(res, errorResp)
 	case types.Raw:
 		c.resp.Respond(res.Data, errorResp)
@@ -60,11 +85,37 @@ func (h Handler) ServeHTTP(_ http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// timeHandler calls the handler, recording its time excluding the time of the datastores it calls, as they are
+// recorded separately, and begins the timing of the serialization of its response.
+func timeHandler(timing *middleware.ServerTiming, c *Context, h Handler) (interface{}, error) {
+	start, queried := time.Now(), timing.Duration(middleware.TimingDatastore)
+
+	data, err := h(c)
+
+	// the datastores called in parallel can take longer than the handler
+	if d := time.Since(start) - (timing.Duration(middleware.TimingDatastore) - queried); d > 0 {
+		timing.Add(middleware.TimingHandler, d)
+	} else {
+		timing.Add(middleware.TimingHandler, 0)
+	}
+	timing.Begin(middleware.TimingSerialization)
+
+	return data, err
+}
+
+// maskPII masks the fields of the response tagged with pii, unless the caller has the scope pii, to see all the
+// personal data, or pii:<kind>, ex: pii:email, to see the personal data of the kind.
+func maskPII(c *Context, data interface{}) interface{} {
+	return pii.Mask(data, func(kind string) bool {
+		return hasScope(c, "pii") || hasScope(c, "pii:"+kind)
+	})
+}
+
 //nolint:gocognit,gocyclo // cannot be simplified further without hurting readability
-func processErrors(err error, path, method string, isPartialError bool) errors.MultipleErrors {
+func processErrors(err error, path, method string, isPartialError bool, now time.Time) errors.MultipleErrors {
 	var errResp errors.Response
 
-	errResp.Value, errResp.TimeZone = evaluateTimeAndTimeZone()
+	errResp.Value, errResp.TimeZone = evaluateTimeAndTimeZone(now)
 	errResp.Reason = err.Error()
 
 	switch v := err.(type) {
@@ -96,15 +147,9 @@ func processErrors(err error, path, method string, isPartialError bool) errors.M
 	case errors.MultipleErrors:
 		var finalErr errors.MultipleErrors
 		finalErr.StatusCode = v.StatusCode
-		now := time.Now()
-		timeZone, _ := now.Zone()
 
 		for _, v := range v.Errors {
-			resp := errors.Response{}
-			resp.TimeZone = timeZone
-			resp.Value = now.UTC().Format(time.RFC3339)
-
-			errs := processErrors(v, path, method, isPartialError)
+			errs := processErrors(v, path, method, isPartialError, now)
 
 			finalErr.Errors = append(finalErr.Errors, errs.Errors...)
 		}
@@ -130,8 +175,26 @@ func processErrors(err error, path, method string, isPartialError bool) errors.M
 	return errors.MultipleErrors{StatusCode: errResp.StatusCode, Errors: []error{&errResp}}
 }
 
-func evaluateTimeAndTimeZone() (formattedTime, timeZone string) {
-	now := time.Now()
+// localizeErrors translates the reason of the errors when the application has a message for it in the
+// locale of the request, so that the handlers can return the message keys as the reason.
+func localizeErrors(c *Context, errs errors.MultipleErrors) {
+	if c == nil || c.Gofr == nil || c.Messages == nil {
+		return
+	}
+
+	locale := c.Locale()
+
+	for _, err := range errs.Errors {
+		if resp, ok := err.(*errors.Response); ok {
+			if msg, ok := c.Messages.Message(locale, resp.Reason); ok {
+				resp.Reason = msg
+			}
+		}
+	}
+}
+
+// evaluateTimeAndTimeZone returns the time in UTC, along with the name of the time zone of the time.
+func evaluateTimeAndTimeZone(now time.Time) (formattedTime, timeZone string) {
 	formattedTime = now.UTC().Format(time.RFC3339)
 	timeZone, _ = now.Zone()
 
@@ -12,13 +12,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 
 	gofrErrors "gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/clock"
+	"gofr.dev/pkg/gofr/i18n"
 	"gofr.dev/pkg/gofr/request"
 	"gofr.dev/pkg/gofr/responder"
 	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/middleware/oauth"
 )
 
 // CustomError to be used for Err field in errors.Raw
@@ -589,7 +593,8 @@ func Test_processErrors_RawError(t *testing.T) {
 	}
 
 	for i, tc := range testCases {
-		gotErr := processErrors(tc.arguments.error, tc.arguments.path, tc.arguments.method, tc.arguments.isPartialError)
+		gotErr := processErrors(tc.arguments.error, tc.arguments.path, tc.arguments.method, tc.arguments.isPartialError,
+			time.Now())
 
 		assert.Equalf(t, tc.expErr, gotErr, "Testcase [%d] Failed: %v", i+1, tc.desc)
 	}
@@ -631,24 +636,24 @@ func TestHandler_ServeHTTP_WithContentType(t *testing.T) {
 }
 
 func TestEvaluateTimeAndTimeZone(t *testing.T) {
-	expectedFormattedTime := time.Now().UTC().Format(time.RFC3339)
-	expectedTimeZone, _ := time.Now().Zone()
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	now := time.Date(2024, time.March, 10, 15, 30, 0, 0, loc)
 
-	formattedTime, timeZone := evaluateTimeAndTimeZone()
+	formattedTime, timeZone := evaluateTimeAndTimeZone(now)
 
-	if formattedTime != expectedFormattedTime {
-		t.Errorf("expected formatted time %s but got %s", expectedFormattedTime, formattedTime)
+	if formattedTime != "2024-03-10T10:00:00Z" {
+		t.Errorf("expected formatted time 2024-03-10T10:00:00Z but got %s", formattedTime)
 	}
 
-	if timeZone != expectedTimeZone {
-		t.Errorf("expected time zone %s but got %s", expectedTimeZone, timeZone)
+	if timeZone != "IST" {
+		t.Errorf("expected time zone IST but got %s", timeZone)
 	}
 }
 
 // Test_processErrors to test behavior of processErrors function
 func Test_processErrors(t *testing.T) {
-	expectedFormattedTime := time.Now().UTC().Format(time.RFC3339)
-	expectedTimeZone, _ := time.Now().Zone()
+	now := time.Date(2024, time.March, 10, 15, 30, 0, 0, time.UTC)
+	expectedFormattedTime, expectedTimeZone := "2024-03-10T15:30:00Z", "UTC"
 
 	testCases := []struct {
 		desc           string
@@ -690,9 +695,82 @@ func Test_processErrors(t *testing.T) {
 
 		expErr := gofrErrors.MultipleErrors{StatusCode: tc.statusCode, Errors: []error{&errResp}}
 
-		err := processErrors(tc.err, http.MethodGet, "/dummy", tc.isPartialError)
+		err := processErrors(tc.err, http.MethodGet, "/dummy", tc.isPartialError, now)
 
 		assert.Equalf(t, expErr, err, "Test[%d] Failed: %v", i+1, tc.desc)
 		assert.Equalf(t, expErr.Errors, err.Errors, "Test[%d] Failed: %v", i+1, tc.desc)
 	}
 }
+
+func TestHandler_ServeHTTP_LocalizedError(t *testing.T) {
+	g := &Gofr{Messages: i18n.NewBundle("en")}
+	g.Messages.Add("fr", map[string]string{"user_not_found": "Utilisateur introuvable"})
+
+	w := newCustomWriter()
+	r := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+	r.Header.Set("Accept-Language", "fr-FR")
+	r = routeKeySetter(w, r)
+	req := request.NewHTTPRequest(r)
+	resp := responder.NewContextualResponder(w, r)
+	*r = *r.Clone(ctx.WithValue(r.Context(), gofrContextkey, NewContext(resp, req, g)))
+
+	Handler(func(c *Context) (interface{}, error) {
+		return nil, &gofrErrors.Response{StatusCode: http.StatusNotFound, Code: "Not Found", Reason: "user_not_found"}
+	}).ServeHTTP(w, r)
+
+	assert.Contains(t, w.Body, `"reason":"Utilisateur introuvable"`)
+}
+
+func TestHandler_ServeHTTP_ErrorTimestamp(t *testing.T) {
+	g := &Gofr{Clock: clock.NewFrozen(time.Date(2024, time.March, 10, 15, 30, 0, 0, time.UTC))}
+
+	w := newCustomWriter()
+	r := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+	r.Header.Set(TimeZoneHeader, "Asia/Kolkata")
+	r = routeKeySetter(w, r)
+	req := request.NewHTTPRequest(r)
+	resp := responder.NewContextualResponder(w, r)
+	*r = *r.Clone(ctx.WithValue(r.Context(), gofrContextkey, NewContext(resp, req, g)))
+
+	Handler(func(c *Context) (interface{}, error) {
+		return nil, gofrErrors.EntityNotFound{Entity: "user", ID: "1"}
+	}).ServeHTTP(w, r)
+
+	assert.Contains(t, w.Body, `"datetime":{"value":"2024-03-10T15:30:00Z","timezone":"IST"}`)
+}
+
+func TestHandler_ServeHTTP_PII(t *testing.T) {
+	type customer struct {
+		Name  string `json:"name"`
+		Email string `json:"email" pii:"email"`
+	}
+
+	tests := []struct {
+		desc   string
+		scope  string
+		output string
+	}{
+		{"without entitlement", "", `{"data":{"name":"Jane","email":"j***@example.com"}}`},
+		{"entitled to the kind", "read pii:email", `{"data":{"name":"Jane","email":"jane@example.com"}}`},
+		{"entitled to all kinds", "pii", `{"data":{"name":"Jane","email":"jane@example.com"}}`},
+	}
+
+	for i, tc := range tests {
+		w := newCustomWriter()
+		r := httptest.NewRequest(http.MethodGet, "/customers/1", http.NoBody)
+		r = routeKeySetter(w, r)
+
+		if tc.scope != "" {
+			r = r.WithContext(ctx.WithValue(r.Context(), oauth.JWTContextKey("claims"), jwt.MapClaims{"scope": tc.scope}))
+		}
+
+		c := NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), &Gofr{})
+		*r = *r.Clone(ctx.WithValue(r.Context(), gofrContextkey, c))
+
+		Handler(func(*Context) (interface{}, error) {
+			return customer{Name: "Jane", Email: "jane@example.com"}, nil
+		}).ServeHTTP(w, r)
+
+		assert.Equal(t, tc.output, strings.TrimSpace(w.Body), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
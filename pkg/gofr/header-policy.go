@@ -0,0 +1,111 @@
+package gofr
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// HeaderPolicy sets and removes the response headers of the routes under a path prefix, ex: Cache-Control of the
+// routes of /api, so that the headers are set centrally instead of in every handler. The policies of the longer
+// prefixes are applied after the policies of the shorter ones, hence their headers take precedence.
+type HeaderPolicy struct {
+	// Prefix is the path prefix of the routes, ex: /api/v1, it matches /api/v1 and /api/v1/orders but not /api/v10.
+	// All the routes are matched when it is / or empty.
+	Prefix string `json:"prefix" yaml:"prefix"`
+	// Set are the headers set on the responses, overriding the headers set by the handlers.
+	Set map[string]string `json:"set,omitempty" yaml:"set,omitempty"`
+	// Remove are the headers removed from the responses.
+	Remove []string `json:"remove,omitempty" yaml:"remove,omitempty"`
+}
+
+// AddHeaderPolicy adds the response header policy of a route group, ex:
+//
+//	app.AddHeaderPolicy(gofr.HeaderPolicy{Prefix: "/api", Set: map[string]string{"Cache-Control": "no-store", "Vary": "Accept-Language"}})
+func (g *Gofr) AddHeaderPolicy(p HeaderPolicy) {
+	p.Prefix = "/" + strings.Trim(p.Prefix, "/")
+
+	policies := append(g.Server.HeaderPolicies, p)
+
+	// sorted by the length of the prefix, so that the policies of the longer prefixes are applied last
+	sort.SliceStable(policies, func(i, j int) bool { return len(policies[i].Prefix) < len(policies[j].Prefix) })
+
+	g.Server.HeaderPolicies = policies
+}
+
+func (p *HeaderPolicy) matches(path string) bool {
+	if p.Prefix == "/" {
+		return true
+	}
+
+	return path == p.Prefix || strings.HasPrefix(path, p.Prefix+"/")
+}
+
+func (p *HeaderPolicy) apply(h http.Header) {
+	for _, k := range p.Remove {
+		h.Del(k)
+	}
+
+	for k, v := range p.Set {
+		h.Set(k, v)
+	}
+}
+
+// headerPolicies applies the header policies matching the path of the request, when the status of the response is written.
+func headerPolicies(policies []HeaderPolicy) func(http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var matched []*HeaderPolicy
+
+			for i := range policies {
+				if policies[i].matches(r.URL.Path) {
+					matched = append(matched, &policies[i])
+				}
+			}
+
+			if len(matched) == 0 {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			inner.ServeHTTP(&headerPolicyWriter{ResponseWriter: w, policies: matched}, r)
+		})
+	}
+}
+
+// headerPolicyWriter applies the policies before the status and the headers are written.
+type headerPolicyWriter struct {
+	http.ResponseWriter
+	policies    []*HeaderPolicy
+	wroteHeader bool
+}
+
+func (w *headerPolicyWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+
+		for _, p := range w.policies {
+			p.apply(w.Header())
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *headerPolicyWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *headerPolicyWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,59 @@
+package gofr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderPolicies(t *testing.T) {
+	g := &Gofr{Server: &server{}}
+
+	g.AddHeaderPolicy(HeaderPolicy{Prefix: "/api/v1/", Set: map[string]string{"Cache-Control": "max-age=60"},
+		Remove: []string{"X-Powered-By"}})
+	g.AddHeaderPolicy(HeaderPolicy{Prefix: "/", Set: map[string]string{"Cache-Control": "no-store", "Vary": "Accept-Language"}})
+
+	handler := headerPolicies(g.Server.HeaderPolicies)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Powered-By", "gofr")
+		w.Header().Set("Cache-Control", "private")
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	tests := []struct {
+		desc         string
+		path         string
+		cacheControl string
+		poweredBy    string
+	}{
+		{"policy of all the routes", "/health", "no-store", "gofr"},
+		{"policy of the longer prefix takes precedence", "/api/v1/orders", "max-age=60", ""},
+		{"prefix matches the route of the prefix", "/api/v1", "max-age=60", ""},
+		{"prefix does not match a part of a segment", "/api/v10/orders", "no-store", "gofr"},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, http.NoBody))
+
+		assert.Equal(t, tc.cacheControl, w.Header().Get("Cache-Control"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.poweredBy, w.Header().Get("X-Powered-By"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "Accept-Language", w.Header().Get("Vary"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "ok", w.Body.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestHeaderPolicies_NoMatch(t *testing.T) {
+	policies := []HeaderPolicy{{Prefix: "/api", Set: map[string]string{"Cache-Control": "no-store"}}}
+
+	handler := headerPolicies(policies)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", http.NoBody))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
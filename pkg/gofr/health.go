@@ -12,6 +12,26 @@ func HeartBeatHandler(*Context) (interface{}, error) {
 
 type HealthCheck func() types.Health
 
+// ServiceHealthChecker is a client of a downstream service which reports its health, ex: the HTTP service clients.
+type ServiceHealthChecker interface {
+	HealthCheck() types.Health
+	// IsCritical reports whether the application is not ready when the service is down.
+	IsCritical() bool
+}
+
+// AddServices includes the services in the health check and the readiness check. A critical service which is down
+// makes the application not ready, as per its weight in HEALTH_DEPENDENCY_WEIGHTS, whereas an optional service
+// which is down only makes the application DEGRADED.
+func (g *Gofr) AddServices(services ...ServiceHealthChecker) {
+	for _, s := range services {
+		if s.IsCritical() {
+			g.ServiceHealth = append(g.ServiceHealth, s.HealthCheck)
+		} else {
+			g.OptionalServiceHealth = append(g.OptionalServiceHealth, s.HealthCheck)
+		}
+	}
+}
+
 // HealthHandler reports the database health
 func HealthHandler(c *Context) (interface{}, error) {
 	var (
@@ -20,7 +40,11 @@ func HealthHandler(c *Context) (interface{}, error) {
 		healthData healthResp
 	)
 
-	for _, v := range c.ServiceHealth {
+	services := make([]HealthCheck, 0, len(c.ServiceHealth)+len(c.OptionalServiceHealth))
+	services = append(services, c.ServiceHealth...)
+	services = append(services, c.OptionalServiceHealth...)
+
+	for _, v := range services {
 		svcHealth := v()
 
 		if svcHealth.Status == pkg.StatusUp {
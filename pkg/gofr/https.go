@@ -17,6 +17,11 @@ type HTTPS struct {
 	TLSConfig       *tls.Config
 	CertificateFile string
 	KeyFile         string
+	// Config tunes the timeouts and the connections of the server, ReadTimeOut, WriteTimeOut and IdleTimeOut are used
+	// for the timeouts which are not set.
+	Config ServerConfig
+	// ACME manages the certificates of the server, instead of CertificateFile and KeyFile, it is set using ACME_DOMAINS.
+	ACME *ACME
 }
 
 const (
@@ -31,39 +36,55 @@ func (h *HTTPS) StartServer(logger log.Logger, router http.Handler) {
 		h.TLSConfig = h.perfectSSLScoreConfig()
 	}
 
-	srv := &http.Server{
-		Addr:         ":" + strconv.Itoa(h.Port),
-		ReadTimeout:  ReadTimeOut * time.Second,
-		WriteTimeout: WriteTimeOut * time.Second,
-		IdleTimeout:  IdleTimeOut * time.Second,
-		TLSConfig:    h.TLSConfig,
-		Handler:      router,
-	}
+	cfg := h.Config
+	cfg.ReadTimeout = durationOrDefault(cfg.ReadTimeout, ReadTimeOut*time.Second)
+	cfg.WriteTimeout = durationOrDefault(cfg.WriteTimeout, WriteTimeOut*time.Second)
+	cfg.IdleTimeout = durationOrDefault(cfg.IdleTimeout, IdleTimeOut*time.Second)
 
-	certFile, _ := filepath.Abs(h.CertificateFile)
+	srv := cfg.server(":"+strconv.Itoa(h.Port), router)
+	srv.TLSConfig = h.TLSConfig
 
-	_, err := os.Stat(certFile)
-	if err != nil {
-		logger.Error("error in certificate file  ", err)
-		return
-	}
+	var certFile, keyFile string
 
-	keyFile, _ := filepath.Abs(h.KeyFile)
+	if h.ACME != nil {
+		// the certificates are provisioned by the manager, hence the files are not needed
+		srv.TLSConfig = h.ACME.tlsConfig(h.TLSConfig)
+	} else {
+		certFile, _ = filepath.Abs(h.CertificateFile)
 
-	_, err = os.Stat(keyFile)
-	if err != nil {
-		logger.Error("error in certificate key  ", err)
-		return
+		if _, err := os.Stat(certFile); err != nil {
+			logger.Error("error in certificate file  ", err)
+			return
+		}
+
+		keyFile, _ = filepath.Abs(h.KeyFile)
+
+		if _, err := os.Stat(keyFile); err != nil {
+			logger.Error("error in certificate key  ", err)
+			return
+		}
 	}
 
 	logger.Logf("starting https server at :%v", h.Port)
 
-	err = srv.ListenAndServeTLS(certFile, keyFile)
+	listener, err := cfg.listen(h.Port)
+	if err == nil {
+		err = srv.ServeTLS(listener, certFile, keyFile)
+	}
+
 	if err != nil {
 		logger.Error("unable to start HTTPS Server", err)
 	}
 }
 
+func durationOrDefault(d, defaultValue time.Duration) time.Duration {
+	if d == 0 {
+		return defaultValue
+	}
+
+	return d
+}
+
 //nolint:gosec // We are using insecure tls as it is required by http2.
 func (h *HTTPS) perfectSSLScoreConfig() *tls.Config {
 	return &tls.Config{
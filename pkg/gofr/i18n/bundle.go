@@ -0,0 +1,127 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Bundle holds the messages of each locale, keyed by their message key.
+type Bundle struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]string
+}
+
+// NewBundle returns an empty bundle, the messages of the default locale are used when a message is not
+// present for the requested locale.
+func NewBundle(defaultLocale string) *Bundle {
+	if defaultLocale == "" {
+		defaultLocale = DefaultLocale
+	}
+
+	return &Bundle{defaultLocale: defaultLocale, messages: make(map[string]map[string]string)}
+}
+
+// DefaultLocale returns the default locale of the bundle.
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale
+}
+
+// Add adds the messages of a locale to the bundle, the existing messages with the same keys are replaced.
+func (b *Bundle) Add(locale string, messages map[string]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.messages[locale] == nil {
+		b.messages[locale] = make(map[string]string, len(messages))
+	}
+
+	for k, v := range messages {
+		b.messages[locale][k] = v
+	}
+}
+
+// LoadDir adds the messages of the JSON files present in dir, the name of a file denotes its locale. For ex:
+// the messages of the locale fr-FR are read from dir/fr-FR.json, which contains an object of keys and messages.
+func (b *Bundle) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return err
+		}
+
+		var messages map[string]string
+
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("invalid message file %v: %w", filepath.Base(file), err)
+		}
+
+		b.Add(strings.TrimSuffix(filepath.Base(file), ".json"), messages)
+	}
+
+	return nil
+}
+
+// Locales returns the locales which have messages in the bundle, in sorted order.
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	locales := make([]string, 0, len(b.messages))
+	for locale := range b.messages {
+		locales = append(locales, locale)
+	}
+
+	sort.Strings(locales)
+
+	return locales
+}
+
+// Match returns the locale of the bundle which best matches the Accept-Language header.
+func (b *Bundle) Match(acceptLanguage string) string {
+	return Match(ParseAcceptLanguage(acceptLanguage), b.Locales(), b.defaultLocale)
+}
+
+// Message returns the message of the key for the locale. The message is looked up in the locale, its base
+// language and the default locale, in that order.
+func (b *Bundle) Message(locale, key string) (string, bool) {
+	if b == nil {
+		return "", false
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, l := range []string{locale, base(locale), b.defaultLocale} {
+		if msg, ok := b.messages[l][key]; ok {
+			return msg, true
+		}
+	}
+
+	return "", false
+}
+
+// Translate returns the message of the key for the locale, formatted with the args using fmt.Sprintf.
+// The key is returned when the message is not present in the bundle, or the bundle is nil.
+func (b *Bundle) Translate(locale, key string, args ...interface{}) string {
+	msg, ok := b.Message(locale, key)
+	if !ok {
+		msg = key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+
+	return msg
+}
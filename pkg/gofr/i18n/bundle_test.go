@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle_Translate(t *testing.T) {
+	b := NewBundle("en")
+
+	b.Add("en", map[string]string{"greeting": "Hello %v", "bye": "Goodbye"})
+	b.Add("fr", map[string]string{"greeting": "Bonjour %v"})
+
+	tests := []struct {
+		desc   string
+		locale string
+		key    string
+		args   []interface{}
+		msg    string
+	}{
+		{"message of the locale", "fr", "greeting", []interface{}{"Alice"}, "Bonjour Alice"},
+		{"message of the base language", "fr-CA", "greeting", []interface{}{"Alice"}, "Bonjour Alice"},
+		{"message of the default locale", "fr", "bye", nil, "Goodbye"},
+		{"unknown locale", "de", "bye", nil, "Goodbye"},
+		{"unknown key", "fr", "missing", nil, "missing"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.msg, b.Translate(tc.locale, tc.key, tc.args...), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestBundle_NilTranslate(t *testing.T) {
+	var b *Bundle
+
+	assert.Equal(t, "count: 2", b.Translate("en", "count: %v", 2))
+}
+
+func TestBundle_Match(t *testing.T) {
+	b := NewBundle("")
+
+	b.Add("en", map[string]string{})
+	b.Add("fr", map[string]string{})
+
+	assert.Equal(t, DefaultLocale, b.DefaultLocale())
+	assert.Equal(t, []string{"en", "fr"}, b.Locales())
+	assert.Equal(t, "fr", b.Match("de;q=0.9, fr-CH;q=0.8"))
+	assert.Equal(t, "en", b.Match("de"))
+}
+
+func TestBundle_LoadDir(t *testing.T) {
+	dir := t.TempDir()
+
+	_ = os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"greeting": "Hello"}`), 0o600)
+	_ = os.WriteFile(filepath.Join(dir, "fr-FR.json"), []byte(`{"greeting": "Bonjour"}`), 0o600)
+
+	b := NewBundle("en")
+
+	assert.NoError(t, b.LoadDir(dir))
+	assert.Equal(t, []string{"en", "fr-FR"}, b.Locales())
+	assert.Equal(t, "Bonjour", b.Translate("fr-FR", "greeting"))
+
+	_ = os.WriteFile(filepath.Join(dir, "de.json"), []byte(`["invalid"]`), 0o600)
+
+	assert.Error(t, b.LoadDir(dir))
+}
@@ -0,0 +1,95 @@
+// Package i18n provides the negotiation of the locale of a request from the Accept-Language header, and
+// message bundles to translate the strings, templates and errors of the responses.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the locale used when neither the request nor the bundle specifies one.
+const DefaultLocale = "en"
+
+type preference struct {
+	tag     string
+	quality float64
+}
+
+// ParseAcceptLanguage returns the language tags of an Accept-Language header, ordered by their quality.
+// Tags with equal quality retain the order of the header, and tags with a quality of 0 are not acceptable,
+// hence they are not returned. For ex: "fr-CH, fr;q=0.9, en;q=0.8" returns [fr-CH fr en].
+func ParseAcceptLanguage(header string) []string {
+	prefs := make([]preference, 0)
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			v, err := strconv.ParseFloat(q, 64)
+			if err != nil || v < 0 || v > 1 {
+				continue
+			}
+
+			quality = v
+		}
+
+		if quality == 0 {
+			continue
+		}
+
+		prefs = append(prefs, preference{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].quality > prefs[j].quality
+	})
+
+	tags := make([]string, len(prefs))
+	for i := range prefs {
+		tags[i] = prefs[i].tag
+	}
+
+	return tags
+}
+
+// Match returns the supported locale which best matches the preferred tags, in order of preference. A tag
+// matches a locale with the same tag, or with the same base language, ex: fr-CH matches fr, and fr matches fr-FR.
+// The fallback is returned when none of the tags match.
+func Match(preferred, supported []string, fallback string) string {
+	for _, tag := range preferred {
+		if tag == "*" {
+			return fallback
+		}
+
+		for _, locale := range supported {
+			if strings.EqualFold(tag, locale) {
+				return locale
+			}
+		}
+
+		for _, locale := range supported {
+			if strings.EqualFold(base(tag), base(locale)) {
+				return locale
+			}
+		}
+	}
+
+	return fallback
+}
+
+// base returns the primary language subtag of the language tag, ex: en for en-US
+func base(tag string) string {
+	if i := strings.IndexAny(tag, "-_"); i > 0 {
+		return tag[:i]
+	}
+
+	return tag
+}
@@ -0,0 +1,49 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		desc   string
+		header string
+		tags   []string
+	}{
+		{"empty header", "", []string{}},
+		{"ordered by quality", "en;q=0.8, fr-CH, fr;q=0.9, de;q=0.7", []string{"fr-CH", "fr", "en", "de"}},
+		{"equal quality retains order", "da, en-GB;q=0.8, en;q=0.8", []string{"da", "en-GB", "en"}},
+		{"zero quality is excluded", "fr;q=0, en", []string{"en"}},
+		{"invalid quality is excluded", "fr;q=abc, de;q=2, en", []string{"en"}},
+		{"wildcard", "*;q=0.5, es", []string{"es", "*"}},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.tags, ParseAcceptLanguage(tc.header), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	supported := []string{"en", "fr-FR", "pt-BR"}
+
+	tests := []struct {
+		desc      string
+		preferred []string
+		locale    string
+	}{
+		{"exact match", []string{"pt-BR"}, "pt-BR"},
+		{"case insensitive match", []string{"FR-fr"}, "fr-FR"},
+		{"base language of preferred tag", []string{"en-US"}, "en"},
+		{"base language of supported locale", []string{"fr"}, "fr-FR"},
+		{"first matching preference", []string{"de", "pt"}, "pt-BR"},
+		{"wildcard", []string{"de", "*"}, "en"},
+		{"no match", []string{"de"}, "en"},
+		{"no preference", nil, "en"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.locale, Match(tc.preferred, supported, "en"), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
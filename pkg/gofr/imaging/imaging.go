@@ -0,0 +1,143 @@
+// Package imaging provides the validation, resizing and transcoding of the uploaded images. The images are always
+// decoded and encoded again, hence the metadata of the uploads, such as the EXIF data with the GPS location of a
+// photo, is never served back.
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	defaultMaxBytes  = 10 << 20
+	defaultMaxPixels = 40_000_000
+	defaultQuality   = 85
+
+	// ErrTooLarge is returned when the size or the dimensions of an image exceed the limits.
+	ErrTooLarge = errors.Error("image is too large")
+	// ErrUnsupportedFormat is returned when the format of an image is not allowed.
+	ErrUnsupportedFormat = errors.Error("image format is not supported")
+	// ErrInvalidImage is returned when the data is not an image.
+	ErrInvalidImage = errors.Error("invalid image")
+)
+
+// Format denotes the encoding of an image.
+type Format string
+
+const (
+	JPEG Format = "jpeg"
+	PNG  Format = "png"
+	GIF  Format = "gif"
+)
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	return "image/" + string(f)
+}
+
+// Options are the limits which are enforced while decoding an image.
+type Options struct {
+	// MaxBytes is the maximum size of the encoded image. Default is 10 MB.
+	MaxBytes int64
+	// MaxPixels is the maximum width * height of the image, which prevents the small images that decode to huge
+	// bitmaps from exhausting the memory. Default is 40 megapixels.
+	MaxPixels int
+	// Formats are the allowed formats, all the formats are allowed when it is empty.
+	Formats []Format
+}
+
+// Image is a decoded image along with the format it was decoded from.
+type Image struct {
+	image.Image
+	Format Format
+}
+
+// Decode validates and decodes the image read from r. The dimensions are validated before the image is decoded,
+// and the EXIF orientation of JPEG images is applied, as it is lost along with the rest of the metadata.
+func Decode(r io.Reader, opts Options) (*Image, error) {
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBytes
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	cfg, name, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	format := Format(name)
+	if !opts.allowed(format) {
+		return nil, ErrUnsupportedFormat
+	}
+
+	maxPixels := opts.MaxPixels
+	if maxPixels <= 0 {
+		maxPixels = defaultMaxPixels
+	}
+
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > maxPixels/cfg.Height {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	if format == JPEG {
+		img = orient(img, exifOrientation(data))
+	}
+
+	return &Image{Image: img, Format: format}, nil
+}
+
+func (o Options) allowed(f Format) bool {
+	if f != JPEG && f != PNG && f != GIF {
+		return false
+	}
+
+	if len(o.Formats) == 0 {
+		return true
+	}
+
+	for _, allowed := range o.Formats {
+		if allowed == f {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Encode encodes the image in the format, the quality from 1 to 100 is used for JPEG and defaults to 85.
+func Encode(w io.Writer, img image.Image, format Format, quality int) error {
+	switch format {
+	case JPEG:
+		if quality <= 0 || quality > 100 {
+			quality = defaultQuality
+		}
+
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case PNG:
+		return png.Encode(w, img)
+	case GIF:
+		return gif.Encode(w, img, nil)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
@@ -0,0 +1,102 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), A: 255})
+		}
+	}
+
+	return img
+}
+
+func encode(t *testing.T, img image.Image, format Format) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	if err := Encode(&buf, img, format, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	pngData := encode(t, newImage(40, 20), PNG)
+
+	tests := []struct {
+		desc   string
+		data   []byte
+		opts   Options
+		format Format
+		err    error
+	}{
+		{"png", pngData, Options{}, PNG, nil},
+		{"jpeg", encode(t, newImage(40, 20), JPEG), Options{}, JPEG, nil},
+		{"gif", encode(t, newImage(40, 20), GIF), Options{Formats: []Format{GIF}}, GIF, nil},
+		{"not an image", []byte("<svg></svg>"), Options{}, "", ErrInvalidImage},
+		{"format not allowed", pngData, Options{Formats: []Format{JPEG}}, "", ErrUnsupportedFormat},
+		{"size exceeded", pngData, Options{MaxBytes: 10}, "", ErrTooLarge},
+		{"dimensions exceeded", pngData, Options{MaxPixels: 799}, "", ErrTooLarge},
+	}
+
+	for i, tc := range tests {
+		img, err := Decode(bytes.NewReader(tc.data), tc.opts)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err == nil {
+			assert.Equal(t, tc.format, img.Format, "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, image.Rect(0, 0, 40, 20), img.Bounds(), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestEncode_UnsupportedFormat(t *testing.T) {
+	err := Encode(&bytes.Buffer{}, newImage(1, 1), "webp", 0)
+
+	assert.Equal(t, ErrUnsupportedFormat, err)
+}
+
+func TestDecode_StripsMetadata(t *testing.T) {
+	var buf bytes.Buffer
+
+	_ = png.Encode(&buf, newImage(4, 4))
+
+	// a tEXt chunk with a comment is inserted after the IHDR chunk, which ends at the 33rd byte
+	data := buf.Bytes()
+	text := []byte("tEXtComment\x00secret")
+
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(text)-4))
+	chunk = append(chunk, text...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(text))
+
+	data = append(append(append([]byte{}, data[:33]...), chunk...), data[33:]...)
+
+	assert.Contains(t, string(data), "secret")
+
+	img, err := Decode(bytes.NewReader(data), Options{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	out, _, err := Derive(img, Spec{})
+
+	assert.NoError(t, err)
+	assert.NotContains(t, string(out), "secret")
+}
@@ -0,0 +1,139 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+const (
+	exifOrientationTag = 0x0112
+	ifdEntrySize       = 12
+)
+
+// exifOrientation returns the orientation stored in the EXIF data of a JPEG image, from 1 to 8. It returns 1,
+// which denotes an upright image, when the image has no orientation.
+//
+//nolint:gocognit,gocyclo // bounds are checked for every field, as the EXIF data is provided by the client
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	// walk the segments of the JPEG until the APP1 segment which contains the EXIF data
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		// the image data starts after start of scan, the metadata is present before it
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+
+		i += 2 + length
+	}
+
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[offset:]))
+
+	for i := 0; i < entries; i++ {
+		entry := offset + 2 + i*ifdEntrySize
+		if entry+ifdEntrySize > len(tiff) {
+			return 1
+		}
+
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+
+			return 1
+		}
+	}
+
+	return 1
+}
+
+// orient transforms the image as per the EXIF orientation, so that it is upright without the metadata.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	src := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	dstW, dstH := w, h
+	// the orientations from 5 to 8 transpose the image
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx, dy := transform(orientation, x, y, w, h)
+			dst.SetNRGBA(dx, dy, src.NRGBAAt(x, y))
+		}
+	}
+
+	return dst
+}
+
+// transform returns the position of the pixel at x, y of a w * h image after it is made upright.
+func transform(orientation, x, y, w, h int) (dx, dy int) {
+	switch orientation {
+	case 2: // mirrored horizontally
+		return w - 1 - x, y
+	case 3: // rotated by 180
+		return w - 1 - x, h - 1 - y
+	case 4: // mirrored vertically
+		return x, h - 1 - y
+	case 5: // mirrored along the top-left to bottom-right diagonal
+		return y, x
+	case 6: // rotated by 90 counter-clockwise, hence it is rotated clockwise
+		return h - 1 - y, x
+	case 7: // mirrored along the top-right to bottom-left diagonal
+		return h - 1 - y, w - 1 - x
+	case 8: // rotated by 90 clockwise, hence it is rotated counter-clockwise
+		return y, w - 1 - x
+	default:
+		return x, y
+	}
+}
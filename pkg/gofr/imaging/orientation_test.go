@@ -0,0 +1,91 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withOrientation inserts an EXIF segment with the orientation after the start of the JPEG image.
+func withOrientation(jpegData []byte, order binary.AppendByteOrder, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a")
+	if order == binary.LittleEndian {
+		tiff = []byte("II\x2a\x00")
+	}
+
+	tiff = order.AppendUint32(tiff, 8)
+	tiff = order.AppendUint16(tiff, 1)
+	tiff = order.AppendUint16(tiff, exifOrientationTag)
+	tiff = order.AppendUint16(tiff, 3)
+	tiff = order.AppendUint32(tiff, 1)
+	tiff = order.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	return append(append(append([]byte{}, jpegData[:2]...), app1...), jpegData[2:]...)
+}
+
+func TestExifOrientation(t *testing.T) {
+	jpegData := encode(t, newImage(4, 2), JPEG)
+
+	tests := []struct {
+		desc        string
+		data        []byte
+		orientation int
+	}{
+		{"without exif", jpegData, 1},
+		{"big endian", withOrientation(jpegData, binary.BigEndian, 6), 6},
+		{"little endian", withOrientation(jpegData, binary.LittleEndian, 8), 8},
+		{"invalid orientation", withOrientation(jpegData, binary.BigEndian, 9), 1},
+		{"truncated", withOrientation(jpegData, binary.BigEndian, 6)[:20], 1},
+		{"not a jpeg", []byte("GIF89a"), 1},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.orientation, exifOrientation(tc.data), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	src := newImage(3, 2)
+
+	tests := []struct {
+		orientation int
+		bounds      image.Rectangle
+		// position of the top-left pixel of the source in the upright image
+		x, y int
+	}{
+		{1, image.Rect(0, 0, 3, 2), 0, 0},
+		{2, image.Rect(0, 0, 3, 2), 2, 0},
+		{3, image.Rect(0, 0, 3, 2), 2, 1},
+		{4, image.Rect(0, 0, 3, 2), 0, 1},
+		{5, image.Rect(0, 0, 2, 3), 0, 0},
+		{6, image.Rect(0, 0, 2, 3), 1, 0},
+		{7, image.Rect(0, 0, 2, 3), 1, 2},
+		{8, image.Rect(0, 0, 2, 3), 0, 2},
+	}
+
+	for i, tc := range tests {
+		img := orient(src, tc.orientation)
+
+		assert.Equal(t, tc.bounds, img.Bounds(), "TEST[%d], failed.\n%d", i, tc.orientation)
+		assert.Equal(t, src.At(0, 0), img.At(tc.x, tc.y), "TEST[%d], failed.\n%d", i, tc.orientation)
+	}
+}
+
+func TestDecode_Orientation(t *testing.T) {
+	data := withOrientation(encode(t, newImage(40, 20), JPEG), binary.BigEndian, 6)
+
+	img, err := Decode(bytes.NewReader(data), Options{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds(), "orientation is not applied")
+}
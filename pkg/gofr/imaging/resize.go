@@ -0,0 +1,68 @@
+package imaging
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// Mode denotes how an image is resized to the requested dimensions.
+type Mode int
+
+const (
+	// Fit resizes the image to fit within the dimensions, keeping its aspect ratio.
+	Fit Mode = iota
+	// Fill resizes the image to cover the dimensions keeping its aspect ratio, and crops the overflow around the center.
+	Fill
+)
+
+// Resize resizes the image to the dimensions. A dimension which is 0 is derived from the other one, keeping the
+// aspect ratio. The images are never enlarged, as it only increases the size without adding any detail.
+func Resize(img image.Image, width, height int, mode Mode) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	if w == 0 || h == 0 || (width <= 0 && height <= 0) {
+		return img
+	}
+
+	switch {
+	case width <= 0:
+		width = max(1, w*height/h)
+	case height <= 0:
+		height = max(1, h*width/w)
+	}
+
+	// crop is the part of the image which is scaled to the dimensions
+	crop := b
+
+	scaleW, scaleH := width, height
+
+	if mode == Fill {
+		// the aspect ratios are compared by cross multiplying them, as w/h > width/height is same as w*height > width*h
+		if w*height > width*h {
+			cw := h * width / height
+			crop = image.Rect(b.Min.X+(w-cw)/2, b.Min.Y, b.Min.X+(w-cw)/2+cw, b.Max.Y)
+		} else {
+			ch := w * height / width
+			crop = image.Rect(b.Min.X, b.Min.Y+(h-ch)/2, b.Max.X, b.Min.Y+(h-ch)/2+ch)
+		}
+	} else if w*height > width*h {
+		scaleH = max(1, h*width/w)
+	} else {
+		scaleW = max(1, w*height/h)
+	}
+
+	if scaleW >= crop.Dx() && scaleH >= crop.Dy() {
+		if crop == b {
+			return img
+		}
+
+		scaleW, scaleH = crop.Dx(), crop.Dy()
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, scaleW, scaleH))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+
+	return dst
+}
@@ -0,0 +1,35 @@
+package imaging
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResize(t *testing.T) {
+	src := newImage(400, 200)
+
+	tests := []struct {
+		desc          string
+		width, height int
+		mode          Mode
+		bounds        image.Rectangle
+	}{
+		{"fit width", 100, 100, Fit, image.Rect(0, 0, 100, 50)},
+		{"fit height", 400, 50, Fit, image.Rect(0, 0, 100, 50)},
+		{"derived height", 200, 0, Fit, image.Rect(0, 0, 200, 100)},
+		{"derived width", 0, 100, Fill, image.Rect(0, 0, 200, 100)},
+		{"fill crops width", 100, 100, Fill, image.Rect(0, 0, 100, 100)},
+		{"fill crops height", 300, 50, Fill, image.Rect(0, 0, 300, 50)},
+		{"not enlarged", 800, 800, Fit, image.Rect(0, 0, 400, 200)},
+		{"crop of small image", 800, 800, Fill, image.Rect(0, 0, 200, 200)},
+		{"no dimensions", 0, 0, Fit, image.Rect(0, 0, 400, 200)},
+	}
+
+	for i, tc := range tests {
+		img := Resize(src, tc.width, tc.height, tc.mode)
+
+		assert.Equal(t, tc.bounds, img.Bounds(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,79 @@
+package imaging
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr"
+	"gofr.dev/pkg/gofr/template"
+)
+
+// Spec describes a derivative of an image, ex: a thumbnail.
+type Spec struct {
+	Width  int
+	Height int
+	Mode   Mode
+	// Format of the derivative, the format of the image is used when it is empty.
+	Format  Format
+	Quality int
+}
+
+// Derive resizes and encodes the image as per the spec.
+func Derive(img *Image, spec Spec) ([]byte, Format, error) {
+	format := spec.Format
+	if format == "" {
+		format = img.Format
+	}
+
+	var buf bytes.Buffer
+
+	if err := Encode(&buf, Resize(img.Image, spec.Width, spec.Height, spec.Mode), format, spec.Quality); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), format, nil
+}
+
+// Serve returns the encoded image as a response, which can be cached by the clients for maxAge. The ETag of the
+// response is derived from the content, so that the clients can revalidate the image once maxAge is over.
+func Serve(content []byte, format Format, maxAge time.Duration) template.File {
+	sum := sha256.Sum256(content)
+
+	return template.File{
+		Content:     content,
+		ContentType: format.ContentType(),
+		Header: map[string]string{
+			"Cache-Control": "public, max-age=" + strconv.Itoa(int(maxAge.Seconds())),
+			"ETag":          `"` + hex.EncodeToString(sum[:16]) + `"`,
+		},
+	}
+}
+
+// FormImage decodes the image uploaded in the multipart form field of the request. The errors are returned as
+// responses, hence they can be returned by the handlers as they are.
+func FormImage(c *gofr.Context, field string, opts Options) (*Image, error) {
+	f, _, err := c.Request().FormFile(field)
+	if err != nil {
+		return nil, errors.MissingParam{Param: []string{field}}
+	}
+
+	defer f.Close()
+
+	img, err := Decode(f, opts)
+
+	switch err {
+	case nil:
+		return img, nil
+	case ErrTooLarge:
+		return nil, &errors.Response{StatusCode: http.StatusRequestEntityTooLarge, Code: "IMAGE_TOO_LARGE", Reason: err.Error()}
+	case ErrUnsupportedFormat:
+		return nil, &errors.Response{StatusCode: http.StatusUnsupportedMediaType, Code: "UNSUPPORTED_IMAGE_FORMAT", Reason: err.Error()}
+	default:
+		return nil, errors.InvalidParam{Param: []string{field}}
+	}
+}
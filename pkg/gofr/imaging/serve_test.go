@@ -0,0 +1,92 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr"
+	"gofr.dev/pkg/gofr/request"
+)
+
+func TestDerive(t *testing.T) {
+	img := &Image{Image: newImage(400, 200), Format: PNG}
+
+	data, format, err := Derive(img, Spec{Width: 100, Height: 100, Mode: Fill, Format: JPEG, Quality: 70})
+
+	assert.NoError(t, err)
+	assert.Equal(t, JPEG, format)
+
+	derived, err := Decode(bytes.NewReader(data), Options{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, JPEG, derived.Format)
+	assert.Equal(t, image.Rect(0, 0, 100, 100), derived.Bounds())
+}
+
+func TestServe(t *testing.T) {
+	f := Serve([]byte("image"), PNG, time.Hour)
+
+	assert.Equal(t, "image/png", f.ContentType)
+	assert.Equal(t, "public, max-age=3600", f.Header["Cache-Control"])
+	assert.Equal(t, f.Header["ETag"], Serve([]byte("image"), JPEG, time.Minute).Header["ETag"])
+	assert.NotEqual(t, f.Header["ETag"], Serve([]byte("other image"), PNG, time.Hour).Header["ETag"])
+}
+
+func newUploadContext(t *testing.T, field string, data []byte) *gofr.Context {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	w, _ := mw.CreateFormFile(field, "upload")
+	_, _ = w.Write(data)
+	_ = mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/avatars", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	c := gofr.NewContext(nil, request.NewHTTPRequest(r), &gofr.Gofr{})
+	c.Context = context.Background()
+
+	return c
+}
+
+func TestFormImage(t *testing.T) {
+	pngData := encode(t, newImage(40, 20), PNG)
+
+	tests := []struct {
+		desc  string
+		field string
+		data  []byte
+		opts  Options
+		err   error
+	}{
+		{"valid image", "avatar", pngData, Options{}, nil},
+		{"missing field", "photo", pngData, Options{}, errors.MissingParam{Param: []string{"avatar"}}},
+		{"invalid image", "avatar", []byte("text"), Options{}, errors.InvalidParam{Param: []string{"avatar"}}},
+		{"too large", "avatar", pngData, Options{MaxBytes: 10}, &errors.Response{StatusCode: http.StatusRequestEntityTooLarge,
+			Code: "IMAGE_TOO_LARGE", Reason: ErrTooLarge.Error()}},
+		{"unsupported format", "avatar", pngData, Options{Formats: []Format{JPEG}}, &errors.Response{
+			StatusCode: http.StatusUnsupportedMediaType, Code: "UNSUPPORTED_IMAGE_FORMAT", Reason: ErrUnsupportedFormat.Error()}},
+	}
+
+	for i, tc := range tests {
+		img, err := FormImage(newUploadContext(t, tc.field, tc.data), "avatar", tc.opts)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err == nil {
+			assert.Equal(t, PNG, img.Format, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package gofr
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/middleware"
+)
+
+//nolint:gochecknoglobals // the metrics have to be global variables for prometheus
+var (
+	latencyBudgetExceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_http_latency_budget_exceeded_total",
+		Help: "Counter of the requests which took longer than the latency budget of their route",
+	}, []string{"path", "method"})
+
+	_ = prometheus.Register(latencyBudgetExceeded)
+)
+
+/*
+LatencyBudget returns the middleware of a route which declares its latency budget, for ex:
+
+	app.GET("/orders/{id}", h.Get, gofr.LatencyBudget(200*time.Millisecond))
+
+The requests which take longer than the budget are logged at WARN level, along with the time spent in the datastores,
+the handler and the serialization of the response, and are counted by zs_http_latency_budget_exceeded_total. The time
+of the datastores is the time of the SQL and Redis calls made using the context of the request.
+
+The breakdown is sent in the Server-Timing header of the responses as well, when SERVER_TIMING is true, ex:
+
+	Server-Timing: datastore;dur=12.5, handler;dur=3.1, serialization;dur=0.4
+*/
+func LatencyBudget(budget time.Duration) Middleware {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c, _ := r.Context().Value(gofrContextkey).(*Context)
+			if c == nil {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+
+			timing := withServerTiming(c)
+
+			// the responder writes the header of the timing before the response, once its serialization is done
+			tw := &timingWriter{ResponseWriter: w, timing: timing, header: c.Config != nil &&
+				strings.EqualFold(c.Config.Get("SERVER_TIMING"), "true")}
+
+			if c.resp == &c.httpResp {
+				c.httpResp.Reset(tw, r)
+			}
+
+			inner.ServeHTTP(tw, r)
+
+			if elapsed := time.Since(start); elapsed > budget {
+				path, method := routePath(r), r.Method
+
+				latencyBudgetExceeded.WithLabelValues(path, method).Inc()
+
+				if c.Logger != nil {
+					c.Logger.Warnf("%v %v took %v, beyond its latency budget of %v: %v", method, path, elapsed, budget,
+						timing.Header())
+				}
+			}
+		})
+	}
+}
+
+// withServerTiming adds a ServerTiming to the context of the request, so that the datastores called with the context
+// record their time in it.
+func withServerTiming(c *Context) *middleware.ServerTiming {
+	if timing := middleware.ServerTimingFrom(c.Context); timing != nil {
+		return timing
+	}
+
+	timing := &middleware.ServerTiming{}
+
+	c.Context = middleware.WithServerTiming(c.Context, timing)
+
+	// the contexts of WithTimeout are derived from the context without the default deadline
+	if c.base != nil {
+		c.base = middleware.WithServerTiming(c.base, timing)
+	}
+
+	return timing
+}
+
+// routePath returns the path template of the route of the request, without the trailing slash.
+func routePath(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return r.URL.Path
+	}
+
+	path, _ := route.GetPathTemplate()
+
+	return strings.TrimSuffix(path, "/")
+}
+
+// timingWriter ends the timing of the serialization when the response is written, and sets its Server-Timing header.
+type timingWriter struct {
+	http.ResponseWriter
+	timing      *middleware.ServerTiming
+	header      bool
+	wroteHeader bool
+}
+
+func (w *timingWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+
+		w.timing.End(middleware.TimingSerialization)
+
+		if w.header {
+			w.Header().Set("Server-Timing", w.timing.Header())
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *timingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends the buffered response to the client, so that the streamed responses are not held by the writer
+func (w *timingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
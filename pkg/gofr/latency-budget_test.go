@@ -0,0 +1,80 @@
+package gofr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
+)
+
+func TestLatencyBudget(t *testing.T) {
+	c := &config.MockConfig{Data: map[string]string{"SERVER_TIMING": "true"}}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	handler := func(c *Context) (interface{}, error) {
+		// the datastores called with the context record their time
+		middleware.AddServerTiming(c, middleware.TimingDatastore, time.Millisecond)
+
+		time.Sleep(2 * time.Millisecond)
+
+		return "ok", nil
+	}
+
+	g.GET("/budget/slow", handler, LatencyBudget(time.Millisecond))
+	g.GET("/budget/fast", handler, LatencyBudget(time.Minute))
+	g.GET("/budget/none", handler)
+
+	tests := []struct {
+		desc     string
+		target   string
+		timing   bool
+		exceeded float64
+	}{
+		{"beyond the budget", "/budget/slow", true, 1},
+		{"within the budget", "/budget/fast", true, 0},
+		{"route without a budget", "/budget/none", false, 0},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		g.Server.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, http.NoBody))
+
+		header := w.Header().Get("Server-Timing")
+
+		assert.Equal(t, http.StatusOK, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.timing, header != "", "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.exceeded, testutil.ToFloat64(latencyBudgetExceeded.WithLabelValues(tc.target, http.MethodGet)),
+			"TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.timing {
+			assert.True(t, strings.HasPrefix(header, "datastore;dur=1, handler;dur="), "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Contains(t, header, ", serialization;dur=", "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestLatencyBudget_ServerTimingDisabled(t *testing.T) {
+	c := &config.MockConfig{}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	g.GET("/budget", func(*Context) (interface{}, error) { return "ok", nil }, LatencyBudget(time.Minute))
+
+	w := httptest.NewRecorder()
+	g.Server.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/budget", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Server-Timing"), "the timing is only sent when SERVER_TIMING is true")
+}
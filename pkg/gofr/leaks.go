@@ -0,0 +1,278 @@
+package gofr
+
+import (
+	ctx "context"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gofr.dev/pkg/log"
+)
+
+const (
+	defaultLeakInterval = 30 * time.Second
+	defaultLeakSamples  = 10
+	minLeakSamples      = 3
+
+	// maxLeakSites bounds the number of the goroutine creation sites logged for a suspected goroutine leak.
+	maxLeakSites = 5
+
+	goroutinesProbe = "goroutines"
+)
+
+// leakProbe samples a resource of the application, ok is false when the resource can not be sampled.
+type leakProbe struct {
+	name   string
+	sample func() (value float64, ok bool)
+}
+
+/*
+leakDetector samples the goroutines, the open file descriptors and the connections of the datastore pools every
+LEAK_DETECTION_INTERVAL seconds, and logs a suspected leak when a resource grows over LEAK_DETECTION_SAMPLES samples
+without ever decreasing. The sites which created the goroutines that grew are logged with a suspected goroutine leak,
+ex: a subscriber which starts a goroutine for a channel which is never closed.
+
+It is enabled by LEAK_DETECTION, and by default when APP_ENV is dev, as taking the stacks of the goroutines is not meant
+for production.
+*/
+type leakDetector struct {
+	interval time.Duration
+	window   int
+
+	samples map[string][]float64
+	// sites are the number of the goroutines by their creation sites, for each of the goroutine samples
+	sites []map[string]int
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// newLeakDetector returns the leak detector configured by LEAK_DETECTION, it is nil when the detection is not enabled.
+func newLeakDetector(c Config) *leakDetector {
+	enabled := strings.EqualFold(c.Get("APP_ENV"), "dev")
+	if v := c.Get("LEAK_DETECTION"); v != "" {
+		enabled = strings.EqualFold(v, "true")
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	d := &leakDetector{interval: defaultLeakInterval, window: defaultLeakSamples, samples: make(map[string][]float64),
+		stop: make(chan struct{}), done: make(chan struct{})}
+
+	if s := positiveInt(c, "LEAK_DETECTION_INTERVAL"); s > 0 {
+		d.interval = time.Duration(s) * time.Second
+	}
+
+	if n := positiveInt(c, "LEAK_DETECTION_SAMPLES"); n >= minLeakSamples {
+		d.window = n
+	}
+
+	return d
+}
+
+// leakProbes returns the probes of the resources of the application, the datastores which are not connected are not
+// sampled.
+func (g *Gofr) leakProbes() []leakProbe {
+	probes := []leakProbe{
+		{name: goroutinesProbe, sample: func() (float64, bool) { return float64(runtime.NumGoroutine()), true }},
+		{name: "open files", sample: openFiles},
+	}
+
+	if db := g.DB(); db != nil && db.DB != nil {
+		probes = append(probes, leakProbe{name: "sql connections",
+			sample: func() (float64, bool) { return float64(db.Stats().OpenConnections), true }})
+	}
+
+	if g.Redis != nil {
+		probes = append(probes, leakProbe{name: "redis connections",
+			sample: func() (float64, bool) { return float64(g.Redis.PoolStats().TotalConns), true }})
+	}
+
+	return probes
+}
+
+// openFiles returns the number of the open file descriptors of the process, it can only be sampled on Linux.
+func openFiles() (float64, bool) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0, false
+	}
+
+	return float64(len(entries)), true
+}
+
+// start samples the probes till the detector is stopped.
+func (d *leakDetector) start(probes []leakProbe, logger log.Logger) {
+	logger.Infof("leak detection is enabled, the resources are sampled every %v", d.interval)
+
+	go func() {
+		defer close(d.done)
+
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				d.sample(probes, logger)
+			}
+		}
+	}()
+}
+
+// drain stops the sampling.
+func (d *leakDetector) drain(c ctx.Context) error {
+	d.once.Do(func() { close(d.stop) })
+
+	select {
+	case <-d.done:
+		return nil
+	case <-c.Done():
+		return c.Err()
+	}
+}
+
+// sample takes a sample of each of the probes, and logs the resources which grew over the window of the samples. The
+// samples of a resource start over once its leak is logged, so that a leak is logged once per window.
+func (d *leakDetector) sample(probes []leakProbe, logger log.Logger) {
+	for _, p := range probes {
+		value, ok := p.sample()
+		if !ok {
+			continue
+		}
+
+		samples := appendWindow(d.samples[p.name], value, d.window)
+
+		if p.name == goroutinesProbe {
+			d.sites = append(d.sites, goroutineSites())
+			if len(d.sites) > d.window {
+				d.sites = d.sites[len(d.sites)-d.window:]
+			}
+		}
+
+		if len(samples) < d.window || !isGrowing(samples) {
+			d.samples[p.name] = samples
+			continue
+		}
+
+		logger.Warnf("suspected leak of %v: grew from %v to %v over the last %v samples, taken every %v", p.name,
+			samples[0], value, d.window, d.interval)
+
+		if p.name == goroutinesProbe {
+			for _, s := range grownSites(d.sites[0], d.sites[len(d.sites)-1]) {
+				logger.Warnf("suspected leak site: %v more goroutines created by %v", s.growth, s.site)
+			}
+
+			d.sites = d.sites[len(d.sites)-1:]
+		}
+
+		d.samples[p.name] = samples[len(samples)-1:]
+	}
+}
+
+func appendWindow(samples []float64, value float64, window int) []float64 {
+	samples = append(samples, value)
+	if len(samples) > window {
+		samples = samples[len(samples)-window:]
+	}
+
+	return samples
+}
+
+// isGrowing reports whether the samples never decrease, and the last sample is more than the first.
+func isGrowing(samples []float64) bool {
+	for i := 1; i < len(samples); i++ {
+		if samples[i] < samples[i-1] {
+			return false
+		}
+	}
+
+	return samples[len(samples)-1] > samples[0]
+}
+
+// goroutineSites returns the number of the goroutines by the sites which created them, ex:
+// "gofr.dev/pkg/gofr.(*subscriber).start (/app/pkg/gofr/subscriber.go:120)".
+func goroutineSites() map[string]int {
+	buf := make([]byte, 64*1024)
+
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return parseGoroutineSites(string(buf[:n]))
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// parseGoroutineSites parses the creation sites out of the stacks of the goroutines, the goroutines which are not
+// created by a go statement, ie: the main goroutine, are not counted.
+func parseGoroutineSites(stacks string) map[string]int {
+	sites := make(map[string]int)
+	lines := strings.Split(stacks, "\n")
+
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "created by ") {
+			continue
+		}
+
+		fn := strings.TrimPrefix(line, "created by ")
+		// the goroutine which ran the go statement is not a part of the site
+		if j := strings.Index(fn, " in goroutine "); j >= 0 {
+			fn = fn[:j]
+		}
+
+		site := fn
+
+		if i+1 < len(lines) {
+			location := strings.TrimSpace(lines[i+1])
+			if j := strings.LastIndex(location, " +0x"); j >= 0 {
+				location = location[:j]
+			}
+
+			site += " (" + location + ")"
+		}
+
+		sites[site]++
+	}
+
+	return sites
+}
+
+type leakSite struct {
+	site   string
+	growth int
+}
+
+// grownSites returns the sites which created more goroutines since the first sample, the sites which grew the most first.
+func grownSites(first, last map[string]int) []leakSite {
+	var sites []leakSite
+
+	for site, n := range last {
+		if growth := n - first[site]; growth > 0 {
+			sites = append(sites, leakSite{site: site, growth: growth})
+		}
+	}
+
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].growth != sites[j].growth {
+			return sites[i].growth > sites[j].growth
+		}
+
+		return sites[i].site < sites[j].site
+	})
+
+	if len(sites) > maxLeakSites {
+		sites = sites[:maxLeakSites]
+	}
+
+	return sites
+}
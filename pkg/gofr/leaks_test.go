@@ -0,0 +1,155 @@
+package gofr
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+func TestNewLeakDetector(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		configs  map[string]string
+		enabled  bool
+		interval time.Duration
+		window   int
+	}{
+		{"disabled by default", map[string]string{}, false, 0, 0},
+		{"enabled in dev", map[string]string{"APP_ENV": "dev"}, true, defaultLeakInterval, defaultLeakSamples},
+		{"disabled in dev", map[string]string{"APP_ENV": "dev", "LEAK_DETECTION": "false"}, false, 0, 0},
+		{"configured", map[string]string{"LEAK_DETECTION": "true", "LEAK_DETECTION_INTERVAL": "5",
+			"LEAK_DETECTION_SAMPLES": "4"}, true, 5 * time.Second, 4},
+		{"too few samples", map[string]string{"LEAK_DETECTION": "true", "LEAK_DETECTION_SAMPLES": "2"}, true,
+			defaultLeakInterval, defaultLeakSamples},
+	}
+
+	for i, tc := range testcases {
+		d := newLeakDetector(&config.MockConfig{Data: tc.configs})
+
+		if !tc.enabled {
+			assert.Nil(t, d, "TEST[%d], failed.\n%s", i, tc.desc)
+			continue
+		}
+
+		if assert.NotNil(t, d, "TEST[%d], failed.\n%s", i, tc.desc) {
+			assert.Equal(t, tc.interval, d.interval, "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, tc.window, d.window, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestIsGrowing(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		samples []float64
+		exp     bool
+	}{
+		{"grows", []float64{1, 2, 3}, true},
+		{"grows with plateaus", []float64{1, 1, 2, 2}, true},
+		{"flat", []float64{2, 2, 2}, false},
+		{"decreases once", []float64{1, 3, 2, 4}, false},
+	}
+
+	for i, tc := range testcases {
+		assert.Equal(t, tc.exp, isGrowing(tc.samples), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestParseGoroutineSites(t *testing.T) {
+	stacks := `goroutine 1 [running]:
+main.main()
+	/app/main.go:10 +0x1d
+
+goroutine 7 [chan receive]:
+gofr.dev/pkg/gofr.(*subscriber).consume(0xc000010000)
+	/app/pkg/gofr/subscriber.go:80 +0x45
+created by gofr.dev/pkg/gofr.(*subscriber).start in goroutine 1
+	/app/pkg/gofr/subscriber.go:60 +0x65
+
+goroutine 8 [chan receive]:
+gofr.dev/pkg/gofr.(*subscriber).consume(0xc000010000)
+	/app/pkg/gofr/subscriber.go:80 +0x45
+created by gofr.dev/pkg/gofr.(*subscriber).start
+	/app/pkg/gofr/subscriber.go:60 +0x65
+`
+
+	assert.Equal(t, map[string]int{"gofr.dev/pkg/gofr.(*subscriber).start (/app/pkg/gofr/subscriber.go:60)": 2},
+		parseGoroutineSites(stacks))
+}
+
+func TestGrownSites(t *testing.T) {
+	first := map[string]int{"a": 1, "b": 3, "c": 2}
+	last := map[string]int{"a": 4, "b": 3, "c": 3, "d": 1}
+
+	assert.Equal(t, []leakSite{{site: "a", growth: 3}, {site: "c", growth: 1}, {site: "d", growth: 1}},
+		grownSites(first, last))
+}
+
+func TestLeakDetector_Sample(t *testing.T) {
+	b := new(bytes.Buffer)
+	logger := log.NewMockLogger(b)
+	d := &leakDetector{interval: time.Second, window: 3, samples: make(map[string][]float64)}
+
+	values := []float64{1, 2, 3, 2}
+	probes := []leakProbe{
+		{name: "connections", sample: func() (float64, bool) {
+			v := values[0]
+			values = values[1:]
+
+			return v, true
+		}},
+		{name: "files", sample: func() (float64, bool) { return 0, false }},
+	}
+
+	d.sample(probes, logger)
+	d.sample(probes, logger)
+	assert.NotContains(t, b.String(), "suspected leak", "a leak is not suspected before the window is sampled")
+
+	d.sample(probes, logger)
+	assert.Equal(t, 1, strings.Count(b.String(), "suspected leak of connections"))
+	assert.Equal(t, []float64{3}, d.samples["connections"], "the samples start over once the leak is logged")
+	assert.NotContains(t, d.samples, "files", "the probes which can not be sampled are skipped")
+
+	d.sample(probes, logger)
+	assert.Equal(t, []float64{3, 2}, d.samples["connections"])
+}
+
+func TestLeakDetector_Goroutines(t *testing.T) {
+	b := new(bytes.Buffer)
+	d := &leakDetector{interval: time.Second, window: 3, samples: make(map[string][]float64)}
+	stop := make(chan struct{})
+
+	defer close(stop)
+
+	count := 0.0
+	probes := []leakProbe{{name: goroutinesProbe, sample: func() (float64, bool) { count++; return count, true }}}
+
+	for i := 0; i < 3; i++ {
+		d.sample(probes, log.NewMockLogger(b))
+
+		go leakingGoroutine(stop)
+	}
+
+	assert.Contains(t, b.String(), "suspected leak of goroutines")
+	assert.Contains(t, b.String(), "gofr.TestLeakDetector_Goroutines", "the site of the leaked goroutines is logged")
+}
+
+func leakingGoroutine(stop chan struct{}) {
+	<-stop
+}
+
+func TestLeakDetector_Drain(t *testing.T) {
+	d := newLeakDetector(&config.MockConfig{Data: map[string]string{"LEAK_DETECTION": "true"}})
+
+	d.start(nil, log.NewMockLogger(new(bytes.Buffer)))
+
+	assert.NoError(t, d.drain(context.Background()))
+	assert.NoError(t, d.drain(context.Background()), "the detector can be drained again")
+}
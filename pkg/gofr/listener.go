@@ -0,0 +1,226 @@
+package gofr
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/netutil"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/log"
+)
+
+const errListenerClosed = errors.Error("listener is closed")
+
+// ListenConfig is where a server listens, and which connections it accepts.
+type ListenConfig struct {
+	// Addresses are the addresses of the interfaces to listen on, ex: 127.0.0.1 or ::1, the server listens on all the
+	// interfaces when it is empty.
+	Addresses []string
+	// Network is tcp to listen on IPv4 and IPv6 (dual-stack), tcp4 to listen on IPv4 only or tcp6 to listen on IPv6
+	// only, default is tcp.
+	Network string
+	// AllowedCIDRs are the networks from which the connections are accepted, the other connections are closed. The
+	// connections are accepted from all the networks when it is empty.
+	AllowedCIDRs []*net.IPNet
+	// MaxConnections limits the connections served at once, the connections over the limit wait to be accepted.
+	MaxConnections int
+}
+
+// getListenConfig reads the listen config of a server from the configs of the prefix, ex: HTTP_LISTEN_ADDRESSES,
+// HTTP_LISTEN_NETWORK, HTTP_ALLOWED_CIDRS and HTTP_MAX_CONNECTIONS. The invalid networks are logged and skipped.
+func getListenConfig(c Config, prefix string, logger log.Logger) ListenConfig {
+	cfg := ListenConfig{
+		Addresses:      splitList(c.Get(prefix + "_LISTEN_ADDRESSES")),
+		Network:        strings.ToLower(c.Get(prefix + "_LISTEN_NETWORK")),
+		MaxConnections: positiveInt(c, prefix+"_MAX_CONNECTIONS"),
+	}
+
+	for _, cidr := range splitList(c.Get(prefix + "_ALLOWED_CIDRS")) {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			logger.Errorf("%v_ALLOWED_CIDRS has an invalid network %v: %v", prefix, cidr, err)
+			continue
+		}
+
+		cfg.AllowedCIDRs = append(cfg.AllowedCIDRs, network)
+	}
+
+	return cfg
+}
+
+func splitList(s string) []string {
+	var values []string
+
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// listen returns the listener of the port on the addresses of the config.
+func (cfg *ListenConfig) listen(port int) (net.Listener, error) {
+	network := cfg.Network
+	if network == "" {
+		network = "tcp"
+	}
+
+	addresses := cfg.Addresses
+	if len(addresses) == 0 {
+		addresses = []string{""}
+	}
+
+	listeners := make([]net.Listener, 0, len(addresses))
+
+	for _, address := range addresses {
+		// the brackets of the IPv6 addresses are optional, ex: ::1 or [::1]
+		address = strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+
+		l, err := net.Listen(network, net.JoinHostPort(address, strconv.Itoa(port)))
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+
+			return nil, err
+		}
+
+		listeners = append(listeners, l)
+	}
+
+	var listener net.Listener = listeners[0]
+
+	if len(listeners) > 1 {
+		listener = newMultiListener(listeners)
+	}
+
+	if len(cfg.AllowedCIDRs) > 0 {
+		listener = &cidrListener{Listener: listener, allowed: cfg.AllowedCIDRs}
+	}
+
+	if cfg.MaxConnections > 0 {
+		listener = netutil.LimitListener(listener, cfg.MaxConnections)
+	}
+
+	return listener, nil
+}
+
+// multiListener accepts the connections of all its listeners, so that a server is served on many addresses.
+type multiListener struct {
+	listeners []net.Listener
+	conns     chan net.Conn
+	errs      chan error
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newMultiListener(listeners []net.Listener) *multiListener {
+	m := &multiListener{listeners: listeners, conns: make(chan net.Conn), errs: make(chan error), done: make(chan struct{})}
+
+	for _, l := range listeners {
+		go m.accept(l)
+	}
+
+	return m
+}
+
+func (m *multiListener) accept(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			select {
+			case m.errs <- err:
+			case <-m.done:
+				return
+			}
+
+			// the temporary errors are retried by the server, hence the listener keeps accepting
+			//nolint:errorlint // the errors of the listeners are not wrapped
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+
+			return
+		}
+
+		select {
+		case m.conns <- conn:
+		case <-m.done:
+			conn.Close()
+			return
+		}
+	}
+}
+
+func (m *multiListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-m.conns:
+		return conn, nil
+	case err := <-m.errs:
+		return nil, err
+	case <-m.done:
+		return nil, errListenerClosed
+	}
+}
+
+func (m *multiListener) Close() error {
+	var err error
+
+	m.closeOnce.Do(func() {
+		close(m.done)
+
+		for _, l := range m.listeners {
+			if e := l.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	})
+
+	return err
+}
+
+// Addr returns the address of the first listener.
+func (m *multiListener) Addr() net.Addr {
+	return m.listeners[0].Addr()
+}
+
+// cidrListener closes the connections which are not from the allowed networks.
+type cidrListener struct {
+	net.Listener
+	allowed []*net.IPNet
+}
+
+func (c *cidrListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := c.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		if c.isAllowed(conn.RemoteAddr()) {
+			return conn, nil
+		}
+
+		conn.Close()
+	}
+}
+
+func (c *cidrListener) isAllowed(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+
+	for _, network := range c.allowed {
+		if network.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,163 @@
+package gofr
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+func TestGetListenConfig(t *testing.T) {
+	b := new(bytes.Buffer)
+	c := &config.MockConfig{Data: map[string]string{"GRPC_LISTEN_ADDRESSES": "127.0.0.1, ::1", "GRPC_LISTEN_NETWORK": "TCP6",
+		"GRPC_ALLOWED_CIDRS": "10.0.0.0/8,invalid", "GRPC_MAX_CONNECTIONS": "10"}}
+
+	_, network, _ := net.ParseCIDR("10.0.0.0/8")
+
+	assert.Equal(t, ListenConfig{Addresses: []string{"127.0.0.1", "::1"}, Network: "tcp6", AllowedCIDRs: []*net.IPNet{network},
+		MaxConnections: 10}, getListenConfig(c, "GRPC", log.NewMockLogger(b)))
+	assert.Contains(t, b.String(), "GRPC_ALLOWED_CIDRS has an invalid network invalid")
+}
+
+// freePort returns a port which is free on the loopback addresses.
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// acceptConns accepts the connections of the listener, until it is closed.
+func acceptConns(l net.Listener) <-chan net.Conn {
+	accepted := make(chan net.Conn, 10)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			accepted <- conn
+		}
+	}()
+
+	return accepted
+}
+
+func TestListenConfig_Listen_Addresses(t *testing.T) {
+	port := freePort(t)
+	cfg := &ListenConfig{Addresses: []string{"127.0.0.1", "[::1]"}}
+
+	l, err := cfg.listen(port)
+	if err != nil {
+		t.Skipf("loopback addresses are not available: %v", err)
+	}
+
+	defer l.Close()
+
+	accepted := acceptConns(l)
+
+	for i, address := range []string{"127.0.0.1", "[::1]"} {
+		conn, err := net.Dial("tcp", address+":"+strconv.Itoa(port))
+		if !assert.NoError(t, err, "TEST[%d], failed.\n%s", i, address) {
+			continue
+		}
+
+		conn.Close()
+
+		select {
+		case c := <-accepted:
+			c.Close()
+		case <-time.After(time.Second):
+			t.Errorf("TEST[%d], failed.\nconnection to %v is not accepted", i, address)
+		}
+	}
+}
+
+func TestListenConfig_Listen_AllowedCIDRs(t *testing.T) {
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+	_, private, _ := net.ParseCIDR("10.0.0.0/8")
+
+	tests := []struct {
+		desc     string
+		allowed  []*net.IPNet
+		accepted bool
+	}{
+		{"allowed network", []*net.IPNet{private, loopback}, true},
+		{"network which is not allowed", []*net.IPNet{private}, false},
+	}
+
+	for i, tc := range tests {
+		cfg := &ListenConfig{Addresses: []string{"127.0.0.1"}, AllowedCIDRs: tc.allowed}
+
+		l, err := cfg.listen(0)
+		if !assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc) {
+			continue
+		}
+
+		accepted := acceptConns(l)
+
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc) {
+			conn.Close()
+		}
+
+		select {
+		case c := <-accepted:
+			c.Close()
+			assert.True(t, tc.accepted, "TEST[%d], failed.\n%s", i, tc.desc)
+		case <-time.After(200 * time.Millisecond):
+			assert.False(t, tc.accepted, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+
+		l.Close()
+	}
+}
+
+func TestListenConfig_Listen_MaxConnections(t *testing.T) {
+	cfg := &ListenConfig{Addresses: []string{"127.0.0.1"}, MaxConnections: 1}
+
+	l, err := cfg.listen(0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer l.Close()
+
+	accepted := acceptConns(l)
+
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if assert.NoError(t, err) {
+			defer conn.Close()
+		}
+	}
+
+	first := <-accepted
+
+	select {
+	case <-accepted:
+		t.Errorf("connection over the limit is accepted")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	first.Close()
+
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	case <-time.After(time.Second):
+		t.Errorf("connection is not accepted after a connection is closed")
+	}
+}
@@ -1,18 +1,20 @@
 package gofr
 
 import (
+	"crypto/tls"
 	"net/http"
 	"net/http/pprof"
 	"strconv"
 
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-
+	"gofr.dev/pkg/gofr/metrics"
 	"gofr.dev/pkg/log"
 )
 
-func metricsServer(logger log.Logger, port int, route string) *http.Server {
+// metricsServer serves the metrics and the pprof endpoints on the port, the requests are authenticated by auth when it
+// is set, and the server is served over TLS when the certificate and the key files are set.
+func metricsServer(logger log.Logger, port int, route string, auth *ProbeAuth, certFile, keyFile string) *http.Server {
 	mux := http.NewServeMux()
-	mux.Handle(route, promhttp.Handler())
+	mux.Handle(route, metrics.Handler())
 
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
 	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
@@ -26,10 +28,22 @@ func metricsServer(logger log.Logger, port int, route string) *http.Server {
 		Handler: mux,
 	}
 
+	if auth != nil {
+		srv.Handler = auth.handler(mux)
+	}
+
 	logger.Infof("Starting metrics server at :%v", port)
 
 	go func() {
-		err := srv.ListenAndServe()
+		var err error
+
+		if certFile != "" && keyFile != "" {
+			srv.TLSConfig = auth.tlsConfig(&tls.Config{MinVersion: tls.VersionTLS12})
+			err = srv.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+
 		if err != nil {
 			logger.Errorf("error in metrics server %v", err)
 		}
@@ -14,7 +14,7 @@ import (
 
 func TestMetricsServer(t *testing.T) {
 	// Create the server
-	srv := metricsServer(log.NewMockLogger(io.Discard), 2121, "/metrics")
+	srv := metricsServer(log.NewMockLogger(io.Discard), 2121, "/metrics", nil, "", "")
 
 	// Ensure that following routes are working on the server
 	validURLs := []string{
@@ -9,6 +9,8 @@ var (
 	errInvalidMetric = errors.Error("got nil Metric, on creating new custom metric")
 )
 
+// The help text of a metric is required by the OpenMetrics format, the metrics without it are not created.
+
 // NewMetric factory function for custom metric
 func NewMetric() Metric {
 	return newPromVec()
@@ -37,3 +37,12 @@ func Test_New(t *testing.T) {
 		assert.Equal(t, tc.err, err, "TESTCASE[%v] NewSummary", i)
 	}
 }
+
+func Test_NewWithoutHelp(t *testing.T) {
+	metric := NewMetric()
+
+	assert.Equal(t, errMissingHelp, NewCounter(metric, "counter_without_help", "", "id"))
+	assert.Equal(t, errMissingHelp, NewHistogram(metric, "histogram_without_help", "", []float64{.5, 1}))
+	assert.Equal(t, errMissingHelp, NewGauge(metric, "gauge_without_help", " "))
+	assert.Equal(t, errMissingHelp, NewSummary(metric, "summary_without_help", ""))
+}
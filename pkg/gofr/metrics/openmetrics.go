@@ -0,0 +1,234 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	errMissingHelp = errors.Error("help text of the metric is empty")
+	errInvalidUnit = errors.Error("name of the metric does not end with its unit")
+	errInvalidInfo = errors.Error("name of the info metric does not end with _info")
+
+	infoSuffix  = "_info"
+	totalSuffix = "_total"
+)
+
+//nolint:gochecknoglobals // the metadata of the metrics is shared by the metrics and the handler, like the registry
+var (
+	metadataMu sync.RWMutex
+	units      = make(map[string]string)
+	infos      = make(map[string]bool)
+)
+
+/*
+SetUnit sets the unit of the metric, which is exposed in the UNIT metadata of the OpenMetrics format. The name of the
+metric must end with the unit, followed by _total for a counter, ex: request_duration_seconds for the unit seconds.
+*/
+func SetUnit(name, unit string) error {
+	if unit == "" || !strings.HasSuffix(strings.TrimSuffix(name, totalSuffix), "_"+unit) {
+		return errInvalidUnit
+	}
+
+	metadataMu.Lock()
+	units[name] = unit
+	metadataMu.Unlock()
+
+	return nil
+}
+
+/*
+NewInfo adds a new info metric, whose labels describe the application, ex: its build or its feature flags. It is
+exposed with the value 1 and the type info in the OpenMetrics format, and as a gauge in the Prometheus text format. The
+name of the metric must end with _info.
+*/
+func NewInfo(m Metric, name, help string, labels map[string]string) error {
+	if m == nil {
+		return errInvalidMetric
+	}
+
+	if _, ok := m.(*promVec); !ok {
+		return errInvalidType
+	}
+
+	if !strings.HasSuffix(name, infoSuffix) {
+		return errInvalidInfo
+	}
+
+	if strings.TrimSpace(help) == "" {
+		return errMissingHelp
+	}
+
+	info := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help, ConstLabels: labels})
+
+	if err := prometheus.Register(info); err != nil {
+		return metricErr
+	}
+
+	info.Set(1)
+
+	metadataMu.Lock()
+	infos[name] = true
+	metadataMu.Unlock()
+
+	return nil
+}
+
+/*
+Handler serves the metrics of the default registry, in the OpenMetrics format when the scraper accepts it, ex:
+Prometheus with the OpenMetrics scrape protocol, and in the Prometheus text format otherwise. The OpenMetrics format
+has the units and the info metrics, along with the created timestamps of the counters, the summaries and the
+histograms, so that their resets are detected.
+*/
+func Handler() http.Handler {
+	text := promhttp.Handler()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := expfmt.NegotiateIncludingOpenMetrics(r.Header)
+		if format != expfmt.FmtOpenMetrics_1_0_0 && format != expfmt.FmtOpenMetrics_0_0_1 {
+			text.ServeHTTP(w, r)
+			return
+		}
+
+		families, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			http.Error(w, "error gathering metrics: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", string(format))
+
+		_ = WriteOpenMetrics(w, families)
+	})
+}
+
+// WriteOpenMetrics writes the metric families in the OpenMetrics text format, along with the final EOF line.
+func WriteOpenMetrics(out io.Writer, families []*dto.MetricFamily) error {
+	w := bufio.NewWriter(out)
+
+	metadataMu.RLock()
+	defer metadataMu.RUnlock()
+
+	for _, family := range families {
+		if err := writeFamily(w, family); err != nil {
+			return err
+		}
+	}
+
+	if _, err := expfmt.FinalizeOpenMetrics(w); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// writeFamily writes the metadata of the family, followed by the samples of its metrics, each of which is followed by
+// its created timestamp.
+func writeFamily(w *bufio.Writer, family *dto.MetricFamily) error {
+	name, metricType := family.GetName(), family.GetType()
+	short, typeName := name, strings.ToLower(metricType.String())
+
+	switch {
+	case metricType == dto.MetricType_COUNTER && strings.HasSuffix(name, totalSuffix):
+		short = strings.TrimSuffix(name, totalSuffix)
+	case metricType == dto.MetricType_COUNTER, metricType == dto.MetricType_UNTYPED:
+		// a counter without _total is not a valid counter, hence it is written as unknown, like expfmt does
+		typeName = "unknown"
+	case metricType == dto.MetricType_GAUGE && infos[name]:
+		short, typeName = strings.TrimSuffix(name, infoSuffix), "info"
+	}
+
+	if family.Help != nil {
+		_, _ = w.WriteString("# HELP " + short + " " + escape(family.GetHelp()) + "\n")
+	}
+
+	_, _ = w.WriteString("# TYPE " + short + " " + typeName + "\n")
+
+	if unit, ok := units[name]; ok {
+		_, _ = w.WriteString("# UNIT " + short + " " + unit + "\n")
+	}
+
+	for _, m := range family.Metric {
+		if err := writeSamples(w, family, m); err != nil {
+			return err
+		}
+
+		if created := createdTimestamp(m, typeName); created != nil {
+			_, _ = w.WriteString(short + "_created" + labels(m.Label) + " " +
+				strconv.FormatFloat(float64(created.AsTime().UnixNano())/1e9, 'f', -1, 64) + "\n")
+		}
+	}
+
+	return nil
+}
+
+// writeSamples writes the samples of the metric using expfmt, without the metadata of the family.
+func writeSamples(w *bufio.Writer, family *dto.MetricFamily, m *dto.Metric) error {
+	single := &dto.MetricFamily{Name: family.Name, Type: family.Type, Metric: []*dto.Metric{m}}
+
+	var buf bytes.Buffer
+
+	if _, err := expfmt.MetricFamilyToOpenMetrics(&buf, single); err != nil {
+		return err
+	}
+
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if line != "" && !strings.HasPrefix(line, "# ") {
+			_, _ = w.WriteString(line)
+		}
+	}
+
+	return nil
+}
+
+// createdTimestamp returns the created timestamp of the counters, the summaries and the histograms.
+func createdTimestamp(m *dto.Metric, typeName string) *timestamppb.Timestamp {
+	switch typeName {
+	case "counter":
+		return m.GetCounter().GetCreatedTimestamp()
+	case "summary":
+		return m.GetSummary().GetCreatedTimestamp()
+	case "histogram":
+		return m.GetHistogram().GetCreatedTimestamp()
+	}
+
+	return nil
+}
+
+// labels returns the labels in the text format, ex: {method="GET",status="200"}.
+func labels(pairs []*dto.LabelPair) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	sorted := make([]*dto.LabelPair, len(pairs))
+	copy(sorted, pairs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].GetName() < sorted[j].GetName() })
+
+	s := make([]string, 0, len(sorted))
+
+	for _, p := range sorted {
+		s = append(s, p.GetName()+`="`+escape(p.GetValue())+`"`)
+	}
+
+	return "{" + strings.Join(s, ",") + "}"
+}
+
+// escape escapes the backslashes, the new lines and the double quotes of a label value or of a help text.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetUnit(t *testing.T) {
+	testcases := []struct {
+		desc string
+		name string
+		unit string
+		err  error
+	}{
+		{"gauge with unit", "queue_size_bytes", "bytes", nil},
+		{"counter with unit", "payment_amount_dollars_total", "dollars", nil},
+		{"name without unit", "queue_size", "bytes", errInvalidUnit},
+		{"empty unit", "queue_size_bytes", "", errInvalidUnit},
+	}
+
+	for i, tc := range testcases {
+		assert.Equal(t, tc.err, SetUnit(tc.name, tc.unit), "TESTCASE[%v] %v", i, tc.desc)
+	}
+}
+
+func TestNewInfo(t *testing.T) {
+	testcases := []struct {
+		desc   string
+		metric Metric
+		name   string
+		help   string
+		err    error
+	}{
+		{"success-case", NewMetric(), "test_features_info", "Feature flags", nil},
+		{"duplicate", NewMetric(), "test_features_info", "Feature flags", metricErr},
+		{"name without _info", NewMetric(), "test_features", "Feature flags", errInvalidInfo},
+		{"empty help", NewMetric(), "test_flags_info", " ", errMissingHelp},
+		{"mock metric", NewMockMetric(gomock.NewController(t)), "test_flags_info", "Feature flags", errInvalidType},
+		{"nil metric", nil, "test_flags_info", "Feature flags", errInvalidMetric},
+	}
+
+	for i, tc := range testcases {
+		err := NewInfo(tc.metric, tc.name, tc.help, map[string]string{"checkout_v2": "true"})
+		assert.Equal(t, tc.err, err, "TESTCASE[%v] %v", i, tc.desc)
+	}
+}
+
+func TestWriteOpenMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "om_payment_amount_dollars_total",
+		Help: "Amount of the payments"}, []string{"method"})
+	info := prometheus.NewGauge(prometheus.GaugeOpts{Name: "om_build_info", Help: "Build of the app",
+		ConstLabels: prometheus.Labels{"version": "1.0"}})
+
+	registry.MustRegister(counter, info)
+
+	counter.WithLabelValues("card").Add(10)
+	info.Set(1)
+
+	assert.NoError(t, SetUnit("om_payment_amount_dollars_total", "dollars"))
+
+	metadataMu.Lock()
+	infos["om_build_info"] = true
+	metadataMu.Unlock()
+
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+
+	b := new(bytes.Buffer)
+
+	assert.NoError(t, WriteOpenMetrics(b, families))
+
+	out := b.String()
+
+	assert.Contains(t, out, "# HELP om_build Build of the app\n# TYPE om_build info\nom_build_info{version=\"1.0\"} 1")
+	assert.Contains(t, out, "# HELP om_payment_amount_dollars Amount of the payments\n"+
+		"# TYPE om_payment_amount_dollars counter\n# UNIT om_payment_amount_dollars dollars\n"+
+		"om_payment_amount_dollars_total{method=\"card\"} 10.0\nom_payment_amount_dollars_created{method=\"card\"} ")
+	assert.True(t, strings.HasSuffix(out, "# EOF\n"))
+}
+
+func TestHandler(t *testing.T) {
+	testcases := []struct {
+		accept      string
+		contentType string
+	}{
+		{"application/openmetrics-text; version=1.0.0", "application/openmetrics-text; version=1.0.0; charset=utf-8"},
+		{"text/plain", "text/plain; version=0.0.4; charset=utf-8"},
+	}
+
+	for i, tc := range testcases {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody)
+		req.Header.Set("Accept", tc.accept)
+
+		w := httptest.NewRecorder()
+		Handler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, "TESTCASE[%v]", i)
+		assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"), "TESTCASE[%v]", i)
+	}
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"strings"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"gofr.dev/pkg/errors"
@@ -114,6 +116,10 @@ func (p *promVec) ObserveSummary(name string, val float64, labels ...string) err
 }
 
 func (p *promVec) registerCounter(name, help string, labels ...string) error {
+	if strings.TrimSpace(help) == "" {
+		return errMissingHelp
+	}
+
 	counter := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: name,
@@ -131,6 +137,10 @@ func (p *promVec) registerCounter(name, help string, labels ...string) error {
 }
 
 func (p *promVec) registerHistogram(name, help string, buckets []float64, labels ...string) error {
+	if strings.TrimSpace(help) == "" {
+		return errMissingHelp
+	}
+
 	histogram := prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    name,
@@ -149,6 +159,10 @@ func (p *promVec) registerHistogram(name, help string, buckets []float64, labels
 }
 
 func (p *promVec) registerGauge(name, help string, labels ...string) error {
+	if strings.TrimSpace(help) == "" {
+		return errMissingHelp
+	}
+
 	gauge := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: name,
@@ -166,6 +180,10 @@ func (p *promVec) registerGauge(name, help string, labels ...string) error {
 }
 
 func (p *promVec) registerSummary(name, help string, labels ...string) error {
+	if strings.TrimSpace(help) == "" {
+		return errMissingHelp
+	}
+
 	summary := prometheus.NewSummaryVec(
 		prometheus.SummaryOpts{
 			Name: name,
@@ -0,0 +1,96 @@
+package gofr
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Module is a reusable functional block, ex: billing, which packages its routes, migrations, subscriptions and health
+// checks, so that it can be mounted into any application with Mount.
+type Module struct {
+	Name string
+	// Migrate runs the migrations of the module, ex: with migration.Migrate, before its routes are added.
+	Migrate func(g *Gofr) error
+	// Routes adds the routes of the module, the paths of which are relative to the path the module is mounted on.
+	Routes func(r *ModuleRouter)
+	// Subscriptions are the handlers of the topics consumed by the module.
+	Subscriptions map[string]SubscribeHandler
+	// HealthChecks are the services the module depends on, which are added with AddServices.
+	HealthChecks []ServiceHealthChecker
+}
+
+// ModuleRouter adds the routes of a module under the path it is mounted on.
+type ModuleRouter struct {
+	g      *Gofr
+	prefix string
+}
+
+// Mount mounts the module on the path, ex: app.Mount("/billing", billing.Module()). The module is not mounted when its
+// migrations fail, and the error is returned.
+func (g *Gofr) Mount(path string, m *Module) error {
+	if m.Migrate != nil {
+		if err := m.Migrate(g); err != nil {
+			g.Logger.Errorf("module %v is not mounted, as its migrations failed: %v", m.Name, err)
+			return err
+		}
+	}
+
+	if m.Routes != nil {
+		m.Routes(&ModuleRouter{g: g, prefix: "/" + strings.Trim(path, "/")})
+	}
+
+	for topic, handler := range m.Subscriptions {
+		g.Subscribe(topic, handler)
+	}
+
+	g.AddServices(m.HealthChecks...)
+
+	g.Logger.Infof("module %v is mounted on %v", m.Name, path)
+
+	return nil
+}
+
+// GET adds a route of the module for handling HTTP GET requests, whose handler is wrapped in the middlewares.
+func (r *ModuleRouter) GET(path string, handler Handler, middlewares ...Middleware) *Route {
+	return r.g.handle(http.MethodGet, r.path(path), handler, middlewares)
+}
+
+// PUT adds a route of the module for handling HTTP PUT requests, whose handler is wrapped in the middlewares.
+func (r *ModuleRouter) PUT(path string, handler Handler, middlewares ...Middleware) *Route {
+	return r.g.handle(http.MethodPut, r.path(path), handler, middlewares)
+}
+
+// POST adds a route of the module for handling HTTP POST requests, whose handler is wrapped in the middlewares.
+func (r *ModuleRouter) POST(path string, handler Handler, middlewares ...Middleware) *Route {
+	return r.g.handle(http.MethodPost, r.path(path), handler, middlewares)
+}
+
+// DELETE adds a route of the module for handling HTTP DELETE requests, whose handler is wrapped in the middlewares.
+func (r *ModuleRouter) DELETE(path string, handler Handler, middlewares ...Middleware) *Route {
+	return r.g.handle(http.MethodDelete, r.path(path), handler, middlewares)
+}
+
+// PATCH adds a route of the module for handling HTTP PATCH requests, whose handler is wrapped in the middlewares.
+func (r *ModuleRouter) PATCH(path string, handler Handler, middlewares ...Middleware) *Route {
+	return r.g.handle(http.MethodPatch, r.path(path), handler, middlewares)
+}
+
+// path returns the path of the route under the mount path, a module mounted on "/" keeps the paths of its routes.
+func (r *ModuleRouter) path(path string) string {
+	return joinPath(r.prefix, path)
+}
+
+// joinPath returns the path of the route under the prefix, which is either "/" or has no trailing slash.
+func joinPath(prefix, path string) string {
+	path = "/" + strings.TrimPrefix(path, "/")
+
+	if prefix == "/" {
+		return path
+	}
+
+	if path == "/" {
+		return prefix
+	}
+
+	return prefix + path
+}
@@ -0,0 +1,94 @@
+package gofr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+)
+
+func billingModule() *Module {
+	return &Module{
+		Name: "billing",
+		Routes: func(r *ModuleRouter) {
+			r.GET("/", func(*Context) (interface{}, error) { return "invoices", nil })
+			r.POST("/invoices/{id}", func(c *Context) (interface{}, error) { return c.PathParam("id"), nil })
+		},
+		Subscriptions: map[string]SubscribeHandler{"payments": func(*Context, *pubsub.Message) error { return nil }},
+		HealthChecks:  []ServiceHealthChecker{mockService{health: types.Health{Name: "ledger"}, critical: true}},
+	}
+}
+
+func TestGofr_Mount(t *testing.T) {
+	c := &config.MockConfig{}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	assert.NoError(t, g.Mount("/billing/", billingModule()))
+
+	tests := []struct {
+		desc   string
+		method string
+		target string
+		status int
+		body   string
+	}{
+		{"root of the module", http.MethodGet, "/billing", http.StatusOK, "invoices"},
+		{"route of the module", http.MethodPost, "/billing/invoices/7", http.StatusCreated, "7"},
+		{"route outside the module", http.MethodPost, "/invoices/7", http.StatusNotFound, ""},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		g.Server.Router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, http.NoBody))
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Contains(t, w.Body.String(), tc.body, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.Contains(t, g.subscriber.handlers, "payments")
+	assert.Len(t, g.ServiceHealth, 1)
+}
+
+func TestGofr_Mount_MigrationFailure(t *testing.T) {
+	c := &config.MockConfig{}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.Server = NewServer(c, g)
+
+	m := billingModule()
+	m.Migrate = func(*Gofr) error { return errors.Error("table exists") }
+
+	err := g.Mount("/billing", m)
+
+	assert.Equal(t, errors.Error("table exists"), err)
+	assert.Empty(t, g.routes, "routes of the module are added when its migrations fail")
+	assert.Nil(t, g.subscriber, "subscriptions of the module are added when its migrations fail")
+}
+
+func TestModuleRouter_path(t *testing.T) {
+	tests := []struct {
+		desc   string
+		prefix string
+		path   string
+		exp    string
+	}{
+		{"nested path", "/billing", "invoices", "/billing/invoices"},
+		{"root path", "/billing", "/", "/billing"},
+		{"mounted on root", "/", "/invoices", "/invoices"},
+	}
+
+	for i, tc := range tests {
+		r := &ModuleRouter{prefix: tc.prefix}
+
+		assert.Equal(t, tc.exp, r.path(tc.path), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
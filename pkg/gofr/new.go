@@ -15,18 +15,30 @@ import (
 	"gofr.dev/pkg/datastore"
 	"gofr.dev/pkg/datastore/pubsub"
 	"gofr.dev/pkg/datastore/pubsub/avro"
+	"gofr.dev/pkg/datastore/pubsub/aws-sqs"
+	"gofr.dev/pkg/datastore/pubsub/cloudevents"
 	"gofr.dev/pkg/datastore/pubsub/eventbridge"
 	"gofr.dev/pkg/datastore/pubsub/eventhub"
+	"gofr.dev/pkg/datastore/pubsub/failover"
 	"gofr.dev/pkg/datastore/pubsub/google"
+	"gofr.dev/pkg/datastore/pubsub/inprocess"
 	"gofr.dev/pkg/datastore/pubsub/kafka"
+	"gofr.dev/pkg/datastore/pubsub/nats"
+	"gofr.dev/pkg/datastore/pubsub/protobuf"
+	"gofr.dev/pkg/datastore/pubsub/rabbitmq"
+	"gofr.dev/pkg/datastore/pubsub/servicebus"
 	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/clock"
 	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/i18n"
+	"gofr.dev/pkg/gofr/metrics"
 	"gofr.dev/pkg/gofr/request"
 	"gofr.dev/pkg/gofr/responder"
 	"gofr.dev/pkg/log"
 	"gofr.dev/pkg/middleware"
 	"gofr.dev/pkg/notifier"
 	awssns "gofr.dev/pkg/notifier/aws-sns"
+	"gofr.dev/pkg/pii"
 )
 
 //nolint:gochecknoglobals // need to declare global variable to push metrics
@@ -51,10 +63,23 @@ func NewWithConfig(c Config) (g *Gofr) {
 	// Here we do things based on what is provided by Config
 	logger := log.NewLogger()
 
+	// the keys read are tracked, so that the keys which are set, but never read, are warned when the app is started
+	tracked := config.NewTracked(c)
+	c = tracked
+
 	gofr := &Gofr{
-		Logger:         logger,
-		Config:         c,
-		DatabaseHealth: []HealthCheck{},
+		Logger:            logger,
+		Config:            c,
+		DatabaseHealth:    []HealthCheck{},
+		DependencyWeights: getDependencyWeights(c),
+		operations:        newOperationQueue(c),
+		outbox:            newOutbox(c),
+		leaks:             newLeakDetector(c),
+		contextExports:    contextExports(c),
+	}
+
+	if threshold, err := strconv.ParseFloat(c.Get("READINESS_THRESHOLD"), 64); err == nil && threshold > 0 {
+		gofr.ReadinessThreshold = threshold
 	}
 
 	gofr.DataStore.Logger = logger
@@ -75,11 +100,13 @@ func NewWithConfig(c Config) (g *Gofr) {
 
 	remoteConfigURL := c.Get("REMOTE_CONFIG_URL")
 	if remoteConfigURL != "" {
-		c = config.NewRemoteConfigProvider(c, remoteConfigURL, appName, logger)
+		c = tracked.Track(config.NewRemoteConfigProvider(c, remoteConfigURL, appName, logger))
 		gofr.Config = c
 	}
 
 	frameworkInfo.WithLabelValues(appName+"-"+appVers, "gofr-"+log.GofrVersion).Set(1)
+	_ = metrics.NewInfo(metrics.NewMetric(), "zs_build_info", "Build information of the application",
+		buildInfo(appName, appVers))
 
 	s := NewServer(c, gofr)
 	gofr.Server = s
@@ -100,10 +127,16 @@ func NewWithConfig(c Config) (g *Gofr) {
 		s.HTTP.Port = 8000
 	}
 
+	s.HTTP.Config = getServerConfig(c, logger)
+
 	// HTTPS Initialisation
+	s.HTTPS.Config = s.HTTP.Config
+	s.HTTPS.ACME = getACME(c)
 	s.HTTPS.KeyFile = c.Get("KEY_FILE")
 	s.HTTPS.CertificateFile = c.Get("CERTIFICATE_FILE")
 
+	s.ProbeAuth = getProbeAuth(c, logger)
+
 	p, err = strconv.Atoi(c.Get("HTTPS_PORT"))
 	s.HTTPS.Port = p
 
@@ -117,6 +150,12 @@ func NewWithConfig(c Config) (g *Gofr) {
 		s.GRPC.Port = p
 	}
 
+	s.GRPC.ListenConfig = getListenConfig(c, "GRPC", logger)
+
+	if interval, err := strconv.Atoi(c.Get("GRPC_HEALTH_CHECK_INTERVAL")); err == nil && interval > 0 {
+		s.GRPC.HealthCheckInterval = time.Duration(interval) * time.Second
+	}
+
 	// Set Metrics Port
 	s.initializeMetricServerConfig(c)
 
@@ -125,9 +164,32 @@ func NewWithConfig(c Config) (g *Gofr) {
 
 	initializeDataStores(c, logger, gofr)
 
+	initializePlugins(c, gofr)
+
 	initializeNotifiers(c, gofr)
 
-	s.GRPC.server = NewGRPCServer()
+	initializeMessages(c, gofr)
+
+	// the tokenized personal data is masked when the key is not set
+	if key := c.Get("PII_TOKEN_KEY"); key != "" {
+		pii.SetTokenKey([]byte(key))
+	}
+
+	gofr.Clock = clock.System()
+
+	if zone := c.Get("TIME_ZONE"); zone != "" {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			logger.Warnf("TIME_ZONE %v is invalid, local time zone will be used", zone)
+		}
+
+		gofr.TimeZone = loc
+	}
+
+	gofr.RequestDeadline = seconds(c, "REQUEST_DEADLINE", 0)
+
+	s.GRPC.server = NewGRPCServer(grpcServerOptions(c)...)
+	s.GRPC.registerHealthServer()
 
 	return gofr
 }
@@ -141,6 +203,9 @@ func (s *server) initializeMetricServerConfig(c Config) {
 	if route := c.Get("METRICS_ROUTE"); route != "" {
 		s.MetricsRoute = "/" + strings.TrimPrefix(route, "/")
 	}
+
+	s.MetricsCertificateFile = c.Get("METRICS_CERTIFICATE_FILE")
+	s.MetricsKeyFile = c.Get("METRICS_KEY_FILE")
 }
 
 func initializePubSub(c Config, logger log.Logger, g *Gofr) {
@@ -158,7 +223,80 @@ func initializePubSub(c Config, logger log.Logger, g *Gofr) {
 		initializeEventBridge(c, logger, g)
 	case datastore.GooglePubSub:
 		initializeGooglePubSub(c, g)
+	case datastore.InProcess:
+		initializeInProcess(c, g)
+	case datastore.NATS:
+		initializeNATS(c, g)
+	case datastore.RabbitMQ:
+		initializeRabbitMQ(c, g)
+	case datastore.AWSSQS:
+		initializeSQS(c, g)
+	case datastore.ServiceBus:
+		initializeServiceBus(c, g)
 	}
+
+	initializeFailover(c, logger, g)
+
+	initializeCloudEvents(c, logger, g)
+}
+
+// initializeCloudEvents publishes the messages of the pubsub as CloudEvents, when PUBSUB_CLOUDEVENTS is set to the
+// content mode of the events, structured or binary.
+func initializeCloudEvents(c Config, logger log.Logger, g *Gofr) {
+	cfg := cloudEventsConfigFromEnv(c, "")
+	if cfg.Mode == "" || g.PubSub == nil {
+		return
+	}
+
+	ps, err := cloudevents.New(cfg, g.PubSub)
+	if err != nil {
+		logger.Errorf("CloudEvents could not be initialized, Mode: %v, error: %v", cfg.Mode, err)
+
+		return
+	}
+
+	g.PubSub = ps
+
+	logger.Infof("CloudEvents initialized, Mode: %v, Source: %v", cfg.Mode, cfg.Source)
+}
+
+/*
+initializeFailover fails the pubsub over to the pubsub of the secondary region, when PUBSUB_SECONDARY_PREFIX is set. The
+secondary pubsub is configured by the configs with the prefix, ie: DR_PUBSUB_BACKEND and DR_KAFKA_HOSTS for the prefix DR.
+*/
+func initializeFailover(c Config, logger log.Logger, g *Gofr) {
+	prefix := c.Get("PUBSUB_SECONDARY_PREFIX")
+	if prefix == "" {
+		return
+	}
+
+	if g.PubSub == nil {
+		logger.Errorf("PubSub failover could not be initialized, the primary pubsub is not initialized")
+
+		return
+	}
+
+	ps, err := newFailover(c, logger, "", prefix, g.PubSub)
+	if err != nil {
+		logger.Errorf("PubSub failover could not be initialized, Prefix: %v, error: %v", prefix, err)
+
+		return
+	}
+
+	g.PubSub = ps
+
+	logger.Infof("PubSub failover initialized, Secondary backend: %v", c.Get(prefix+"_PUBSUB_BACKEND"))
+}
+
+// newFailover returns the failover of the primary pubsub to the pubsub configured by the configs with the secondary prefix
+func newFailover(c Config, l log.Logger, prefix, secondaryPrefix string,
+	primary pubsub.PublisherSubscriber) (pubsub.PublisherSubscriber, error) {
+	secondary, err := newPubSubFromConfigs(c, l, secondaryPrefix+"_")
+	if err != nil {
+		return nil, err
+	}
+
+	return failover.New(failoverConfigFromEnv(c, prefix), primary, secondary, l)
 }
 
 // InitializePubSubFromConfigs initialize pubsub object using the configuration provided
@@ -167,6 +305,26 @@ func InitializePubSubFromConfigs(c Config, l log.Logger, prefix string) (pubsub.
 		prefix += "_"
 	}
 
+	ps, err := newPubSubFromConfigs(c, l, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if secondaryPrefix := c.Get(prefix + "PUBSUB_SECONDARY_PREFIX"); secondaryPrefix != "" {
+		if ps, err = newFailover(c, l, prefix, secondaryPrefix, ps); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg := cloudEventsConfigFromEnv(c, prefix); cfg.Mode != "" {
+		return cloudevents.New(cfg, ps)
+	}
+
+	return ps, nil
+}
+
+// newPubSubFromConfigs returns the pubsub of the backend configured by the configs with the prefix, without its failover
+func newPubSubFromConfigs(c Config, l log.Logger, prefix string) (pubsub.PublisherSubscriber, error) {
 	pubsubBackend := c.Get(prefix + "PUBSUB_BACKEND")
 	if pubsubBackend == "" {
 		return nil, errors.DataStoreNotInitialized{DBName: "PubSub", Reason: "pubsub backend not provided"}
@@ -179,6 +337,21 @@ func InitializePubSubFromConfigs(c Config, l log.Logger, prefix string) (pubsub.
 		return initializeEventhubFromConfigs(c, prefix)
 	case datastore.EventBridge:
 		return initializeEventBridgeFromConfigs(c, l, prefix)
+	case datastore.InProcess:
+		return inprocess.New(inProcessConfigFromEnv(c, prefix))
+	case datastore.NATS:
+		return nats.New(natsConfigFromEnv(c, prefix), l)
+	case datastore.RabbitMQ:
+		return rabbitmq.New(rabbitMQConfigFromEnv(c, prefix), l)
+	case datastore.AWSSQS:
+		return awssqs.New(sqsConfigFromEnv(c, prefix), l)
+	case datastore.ServiceBus:
+		return servicebus.New(serviceBusConfigFromEnv(c, prefix), l)
+	case datastore.GooglePubSub:
+		// the prefix is separated from the keys by googlePubSubConfigFromEnv
+		cfg := googlePubSubConfigFromEnv(c, strings.TrimSuffix(prefix, "_"))
+
+		return google.New(&cfg, l)
 	}
 
 	return nil, errors.DataStoreNotInitialized{DBName: "Pubsub", Reason: "invalid pubsub backend"}
@@ -204,7 +377,7 @@ func initializeAvro(c *avro.Config, g *Gofr) {
 		g.Logger.Error("Schema registry URL is required for Avro")
 	}
 
-	ps, err := avro.NewWithConfig(c, g.PubSub)
+	ps, err := newSchemaPubSub(c, g.PubSub)
 	if err != nil {
 		g.Logger.Errorf("Avro could not be initialized! SchemaRegistry: %v SchemaVersion: %v, Subject: %v, Error: %v",
 			c.URL, c.Version, c.Subject, err)
@@ -226,6 +399,16 @@ func initializeAvroFromConfigs(c *avro.Config, ps pubsub.PublisherSubscriber) (p
 		return nil, errors.DataStoreNotInitialized{DBName: "Avro", Reason: "Kafka/Eventhub not provided"}
 	}
 
+	return newSchemaPubSub(c, ps)
+}
+
+// newSchemaPubSub returns the pubsub which serializes the messages in the format of the config, using the schemas of
+// the schema registry.
+func newSchemaPubSub(c *avro.Config, ps pubsub.PublisherSubscriber) (pubsub.PublisherSubscriber, error) {
+	if c != nil && c.Format == avro.FormatProtobuf {
+		return protobuf.NewWithConfig(c, ps)
+	}
+
 	return avro.NewWithConfig(c, ps)
 }
 
@@ -305,6 +488,9 @@ func initializeDataStores(c Config, logger log.Logger, g *Gofr) {
 
 	// ClickHouseDB
 	initializeClickHouseDB(c, g)
+
+	// LDAP
+	initializeLDAP(c, g)
 }
 
 func initializeDynamoDB(c Config, g *Gofr) {
@@ -538,6 +724,100 @@ func initializeEventBridgeFromConfigs(c Config, l log.Logger, prefix string) (*e
 	return eventbridge.New(cfg)
 }
 
+func initializeInProcess(c Config, g *Gofr) {
+	cfg := inProcessConfigFromEnv(c, "")
+
+	bus, err := inprocess.New(cfg)
+	if err != nil {
+		g.Logger.Errorf("In-process pubsub could not be initialized, error: %v", err)
+
+		return
+	}
+
+	g.PubSub = bus
+	g.DatabaseHealth = append(g.DatabaseHealth, g.PubSubHealthCheck)
+
+	g.Logger.Infof("In-process pubsub initialized, topics: %v", strings.Join(cfg.Topics, ","))
+}
+
+func initializeNATS(c Config, g *Gofr) {
+	cfg := natsConfigFromEnv(c, "")
+	if cfg.URL == "" {
+		return
+	}
+
+	var err error
+
+	// the health of NATS is down until it is connected, as the client which could not connect is nil
+	g.PubSub, err = nats.New(cfg, g.Logger)
+	g.DatabaseHealth = append(g.DatabaseHealth, g.PubSubHealthCheck)
+
+	if err != nil {
+		g.Logger.Errorf("NATS could not be initialized, URL: %v, Stream: %v, error: %v", cfg.URL, cfg.Stream, err)
+
+		go natsRetry(cfg, g)
+
+		return
+	}
+
+	g.Logger.Infof("NATS initialized, Stream: %v, Subjects: %v", cfg.Stream, strings.Join(cfg.Subjects, ","))
+}
+
+func initializeRabbitMQ(c Config, g *Gofr) {
+	cfg := rabbitMQConfigFromEnv(c, "")
+	if cfg.URL == "" {
+		return
+	}
+
+	var err error
+
+	g.PubSub, err = rabbitmq.New(cfg, g.Logger)
+	g.DatabaseHealth = append(g.DatabaseHealth, g.PubSubHealthCheck)
+
+	if err != nil {
+		g.Logger.Errorf("RabbitMQ could not be initialized, Exchange: %v, error: %v", cfg.Exchange, err)
+
+		go rabbitMQRetry(cfg, g)
+
+		return
+	}
+
+	g.Logger.Infof("RabbitMQ initialized, Exchange: %v, Queue: %v", cfg.Exchange, cfg.Queue)
+}
+
+// initializeSQS initializes the client of SQS, it is not retried as the client connects on its first request
+func initializeSQS(c Config, g *Gofr) {
+	cfg := sqsConfigFromEnv(c, "")
+
+	client, err := awssqs.New(cfg, g.Logger)
+	if err != nil {
+		g.Logger.Errorf("SQS could not be initialized, Queue: %v, Topic: %v, error: %v", cfg.QueueURL, cfg.TopicArn, err)
+
+		return
+	}
+
+	g.PubSub = client
+	g.DatabaseHealth = append(g.DatabaseHealth, g.PubSubHealthCheck)
+
+	g.Logger.Infof("SQS initialized, Queue: %v, Topic: %v", cfg.QueueURL, cfg.TopicArn)
+}
+
+func initializeServiceBus(c Config, g *Gofr) {
+	cfg := serviceBusConfigFromEnv(c, "")
+
+	client, err := servicebus.New(cfg, g.Logger)
+	if err != nil {
+		g.Logger.Errorf("Azure Service Bus could not be initialized, Queue: %v, Topic: %v, error: %v", cfg.Queue, cfg.Topic, err)
+
+		return
+	}
+
+	g.PubSub = client
+	g.DatabaseHealth = append(g.DatabaseHealth, g.PubSubHealthCheck)
+
+	g.Logger.Infof("Azure Service Bus initialized, Queue: %v, Topic: %v", cfg.Queue, cfg.Topic)
+}
+
 func initializeEventhub(c Config, g *Gofr) {
 	hosts := c.Get("EVENTHUB_NAMESPACE")
 	topic := c.Get("EVENTHUB_NAME")
@@ -755,6 +1035,23 @@ func InitializeSolrFromConfigs(c Config, prefix string) (datastore.Client, error
 	return datastore.NewSolrClient(host, port), nil
 }
 
+// initializeMessages loads the message bundle of the application from I18N_DIR, default is ./i18n
+func initializeMessages(c Config, g *Gofr) {
+	g.Messages = i18n.NewBundle(c.GetOrDefault("DEFAULT_LOCALE", i18n.DefaultLocale))
+
+	dir := c.GetOrDefault("I18N_DIR", "./i18n")
+	if _, err := os.Stat(dir); err != nil {
+		return
+	}
+
+	if err := g.Messages.LoadDir(dir); err != nil {
+		g.Logger.Errorf("messages could not be loaded from %v, error: %v", dir, err)
+		return
+	}
+
+	g.Logger.Infof("messages loaded for locales: %v", g.Messages.Locales())
+}
+
 func initializeNotifiers(c Config, g *Gofr) {
 	notifierBackend := c.Get("NOTIFIER_BACKEND")
 
@@ -845,3 +1142,34 @@ func initializeClickHouseDB(c Config, g *Gofr) {
 		g.Logger.Infof("ClickHouse connected, HostName: %s, Port: %s", clickHouseConfig.Host, clickHouseConfig.Port)
 	}
 }
+
+// initializeLDAP initializes the LDAP client in the Gofr struct if both LDAP_ADDR and LDAP_BASE_DN are set.
+// LDAP_ADDR alone is still used by the LDAP middleware, which does not need the datastore.
+func initializeLDAP(c Config, g *Gofr) {
+	ldapConfig := ldapConfigFromEnv(c, "")
+
+	if ldapConfig.Addr == "" || ldapConfig.BaseDN == "" {
+		return
+	}
+
+	var err error
+
+	g.LDAP, err = datastore.NewLDAP(g.Logger, ldapConfig)
+	g.DatabaseHealth = append(g.DatabaseHealth, g.LDAPHealthCheck)
+
+	if err != nil {
+		g.Logger.Errorf("could not connect to LDAP, Addr: %s, BaseDN: %s, Error: %v\n", ldapConfig.Addr, ldapConfig.BaseDN, err)
+
+		go ldapRetry(ldapConfig, g)
+
+		return
+	}
+
+	g.Logger.Infof("LDAP connected, Addr: %s, BaseDN: %s", ldapConfig.Addr, ldapConfig.BaseDN)
+}
+
+// InitializeLDAPFromConfigs initializes LDAP
+func InitializeLDAPFromConfigs(c Config, l log.Logger, prefix string) (datastore.LDAP, error) {
+	cfg := ldapConfigFromEnv(c, prefix)
+	return datastore.NewLDAP(l, cfg)
+}
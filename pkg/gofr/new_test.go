@@ -14,6 +14,8 @@ import (
 
 	"gofr.dev/pkg/datastore/pubsub"
 	"gofr.dev/pkg/datastore/pubsub/avro"
+	"gofr.dev/pkg/datastore/pubsub/cloudevents"
+	"gofr.dev/pkg/datastore/pubsub/failover"
 	"gofr.dev/pkg/datastore/pubsub/kafka"
 	"gofr.dev/pkg/errors"
 	"gofr.dev/pkg/gofr/config"
@@ -820,6 +822,151 @@ func Test_InitializePubSubFromConfigs(t *testing.T) {
 	assert.Equal(t, expErr, err, "Test case failed")
 }
 
+func Test_InitializePubSubFromConfigs_InProcess(t *testing.T) {
+	cfg := &config.MockConfig{Data: map[string]string{
+		"PRE_PUBSUB_BACKEND":        "inprocess",
+		"PRE_INPROCESS_TOPIC":       "orders, payments",
+		"PRE_INPROCESS_BUFFER_SIZE": "5",
+	}}
+
+	ps, err := InitializePubSubFromConfigs(cfg, log.NewMockLogger(io.Discard), "PRE")
+	assert.NoError(t, err)
+
+	err = ps.PublishEvent("", map[string]int{"id": 1}, nil)
+	assert.NoError(t, err)
+
+	msg, err := ps.Subscribe()
+	assert.NoError(t, err)
+	assert.Equal(t, "orders", msg.Topic)
+	assert.Equal(t, `{"id":1}`, msg.Value)
+}
+
+func Test_InitializePubSubFromConfigs_Failover(t *testing.T) {
+	cfg := &config.MockConfig{Data: map[string]string{
+		"PRE_PUBSUB_BACKEND":          "inprocess",
+		"PRE_INPROCESS_TOPIC":         "orders",
+		"PRE_PUBSUB_SECONDARY_PREFIX": "DR",
+		"DR_PUBSUB_BACKEND":           "inprocess",
+		"DR_INPROCESS_TOPIC":          "orders",
+	}}
+
+	ps, err := InitializePubSubFromConfigs(cfg, log.NewMockLogger(io.Discard), "PRE")
+	assert.NoError(t, err)
+	assert.IsType(t, &failover.Failover{}, ps)
+
+	assert.NoError(t, ps.PublishEvent("", "placed", nil))
+
+	msg, err := ps.Subscribe()
+	assert.NoError(t, err)
+	assert.Equal(t, `"placed"`, msg.Value)
+
+	cfg.Data["DR_PUBSUB_BACKEND"] = ""
+
+	_, err = InitializePubSubFromConfigs(cfg, log.NewMockLogger(io.Discard), "PRE")
+	assert.Error(t, err, "the secondary pubsub is required")
+}
+
+func Test_InitializePubSubFromConfigs_CloudEvents(t *testing.T) {
+	cfg := &config.MockConfig{Data: map[string]string{
+		"PRE_PUBSUB_BACKEND":     "inprocess",
+		"PRE_INPROCESS_TOPIC":    "orders",
+		"PRE_PUBSUB_CLOUDEVENTS": "structured",
+	}}
+
+	ps, err := InitializePubSubFromConfigs(cfg, log.NewMockLogger(io.Discard), "PRE")
+	assert.NoError(t, err)
+	assert.IsType(t, &cloudevents.CloudEvents{}, ps)
+
+	assert.NoError(t, ps.PublishEvent("", "placed", nil))
+
+	msg, err := ps.Subscribe()
+	assert.NoError(t, err)
+	assert.Equal(t, `"placed"`, msg.Value)
+	assert.Equal(t, "1.0", msg.Headers["ce_specversion"])
+
+	cfg.Data["PRE_PUBSUB_CLOUDEVENTS"] = "batched"
+
+	_, err = InitializePubSubFromConfigs(cfg, log.NewMockLogger(io.Discard), "PRE")
+	assert.Error(t, err)
+}
+
+func Test_initializeFailover(t *testing.T) {
+	b := new(bytes.Buffer)
+	cfg := &config.MockConfig{Data: map[string]string{
+		"PUBSUB_BACKEND":          "inprocess",
+		"INPROCESS_TOPIC":         "orders",
+		"PUBSUB_SECONDARY_PREFIX": "DR",
+		"PUBSUB_PRIMARY_REGION":   "eu-west",
+		"DR_PUBSUB_BACKEND":       "inprocess",
+		"DR_INPROCESS_TOPIC":      "orders",
+	}}
+
+	g := &Gofr{Logger: log.NewMockLogger(b)}
+
+	initializePubSub(cfg, g.Logger, g)
+
+	assert.IsType(t, &failover.Failover{}, g.PubSub)
+	assert.Contains(t, b.String(), "PubSub failover initialized")
+}
+
+func Test_InitializePubSubFromConfigs_NATS(t *testing.T) {
+	cfg := &config.MockConfig{Data: map[string]string{
+		"PRE_PUBSUB_BACKEND": "nats",
+		"PRE_NATS_URL":       "nats://localhost:4222",
+	}}
+
+	_, err := InitializePubSubFromConfigs(cfg, log.NewMockLogger(io.Discard), "PRE")
+
+	assert.EqualError(t, err, "nats stream is not provided")
+}
+
+func Test_InitializePubSubFromConfigs_RabbitMQ(t *testing.T) {
+	cfg := &config.MockConfig{Data: map[string]string{
+		"PRE_PUBSUB_BACKEND": "rabbitmq",
+		"PRE_RABBITMQ_URL":   "amqp://localhost:5672/",
+	}}
+
+	_, err := InitializePubSubFromConfigs(cfg, log.NewMockLogger(io.Discard), "PRE")
+
+	assert.EqualError(t, err, "rabbitmq exchange is not provided")
+}
+
+func Test_InitializePubSubFromConfigs_GooglePubSub(t *testing.T) {
+	cfg := &config.MockConfig{Data: map[string]string{
+		"PRE_PUBSUB_BACKEND":          "google",
+		"PRE_GOOGLE_PROJECT_ID":       "test123",
+		"PRE_GOOGLE_TOPIC_NAME":       "test",
+		"PRE_GOOGLE_EMULATOR_HOST":    "localhost:1",
+		"PRE_GOOGLE_TIMEOUT_DURATION": "1",
+	}}
+
+	_, err := InitializePubSubFromConfigs(cfg, log.NewMockLogger(io.Discard), "PRE")
+
+	assert.Error(t, err, "emulator which is not running must not be connected")
+}
+
+func Test_InitializePubSubFromConfigs_SQS(t *testing.T) {
+	cfg := &config.MockConfig{Data: map[string]string{
+		"PRE_PUBSUB_BACKEND": "aws-sqs",
+		"PRE_SQS_REGION":     "us-east-1",
+	}}
+
+	_, err := InitializePubSubFromConfigs(cfg, log.NewMockLogger(io.Discard), "PRE")
+
+	assert.EqualError(t, err, "sqs queue url or sns topic arn is not provided")
+}
+
+func Test_InitializePubSubFromConfigs_ServiceBus(t *testing.T) {
+	cfg := &config.MockConfig{Data: map[string]string{
+		"PRE_PUBSUB_BACKEND":               "servicebus",
+		"PRE_SERVICEBUS_CONNECTION_STRING": "Endpoint=sb://orders.servicebus.windows.net/;SharedAccessKeyName=send;SharedAccessKey=secret",
+	}}
+
+	_, err := InitializePubSubFromConfigs(cfg, log.NewMockLogger(io.Discard), "PRE")
+
+	assert.EqualError(t, err, "service bus queue or topic is not provided")
+}
+
 func Test_InitializeAWSSNSFromConfigs(t *testing.T) {
 	b := new(bytes.Buffer)
 	logger := log.NewMockLogger(b)
@@ -866,7 +1013,11 @@ func Test_InitializeSQLFromConfigs(t *testing.T) {
 func Test_RemoteConfig(t *testing.T) {
 	cfg := &config.MockConfig{Data: map[string]string{"REMOTE_CONFIG_URL": "http://dummy", "DB_NAME": "mock-db"}}
 	app := NewWithConfig(cfg)
-	assert.IsType(t, &config.RemoteConfig{}, app.Config, "Test case failed.")
+
+	tracked, ok := app.Config.(*config.Tracked)
+
+	assert.True(t, ok, "Test case failed.")
+	assert.Contains(t, tracked.ReadKeys(), config.Key{Name: "REMOTE_NAMESPACE"}, "the keys read by the remote config are tracked")
 }
 
 func Test_initializeGooglePubSub(t *testing.T) {
@@ -0,0 +1,146 @@
+package gofr
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/template"
+	"gofr.dev/pkg/gofr/types"
+)
+
+const (
+	errNotifierDisabled = errors.Error("notifier is not initialized")
+)
+
+// notificationPreview is a notification template listed by the preview endpoint, along with the sample of its data.
+type notificationPreview struct {
+	Name    string      `json:"name"`
+	Channel string      `json:"channel"`
+	Data    interface{} `json:"data"`
+}
+
+/*
+AddNotificationTemplate registers the notification template by its name, so that the notifications are sent using
+Context.Notify, for ex:
+
+	app.AddNotificationTemplate(&template.NotificationTemplate{Name: "order-shipped", Channel: template.ChannelEmail,
+		Data: OrderShipped{Order: "A-1", Customer: "Ada"}})
+
+The templates are previewed in dev mode, or when NOTIFICATION_PREVIEW is true, rendered exactly as they are sent:
+GET /.well-known/notifications lists the templates along with their sample data, GET
+/.well-known/notifications/{name} renders a template with its sample data, and POST /.well-known/notifications/{name}
+renders it with the data of the body. The preview is the HTML body of an email, or the text of an SMS, with
+?format=raw, so that it can be opened in a browser, else it is the notification in JSON.
+*/
+func (g *Gofr) AddNotificationTemplate(t *template.NotificationTemplate) {
+	if g.notificationTemplates == nil {
+		g.notificationTemplates = make(map[string]*template.NotificationTemplate)
+
+		if g.Server != nil && notificationPreviews(g.Config) {
+			g.Server.Router.Route(http.MethodGet, pkg.PathNotifications, g.notificationTemplatesHandler)
+			g.Server.Router.Route(http.MethodGet, pkg.PathNotificationPreview, g.previewNotificationHandler)
+			g.Server.Router.Route(http.MethodPost, pkg.PathNotificationPreview, g.previewNotificationHandler)
+		}
+	}
+
+	g.notificationTemplates[t.Name] = t
+}
+
+// notificationPreviews reports whether the templates are previewed, they are by default in dev mode.
+func notificationPreviews(c Config) bool {
+	if c == nil {
+		return false
+	}
+
+	if v := c.Get("NOTIFICATION_PREVIEW"); v != "" {
+		return strings.EqualFold(v, "true")
+	}
+
+	return strings.EqualFold(c.Get("APP_ENV"), "dev")
+}
+
+// Notify renders the notification of the template with the data, and publishes it using the notifier, with the
+// attributes. The notification published is returned, ex: to be logged.
+func (c *Context) Notify(name string, data interface{}, attributes map[string]interface{}) (*template.Notification, error) {
+	n, err := c.renderNotification(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Notifier == nil {
+		return nil, errNotifierDisabled
+	}
+
+	if err := c.Notifier.Publish(n, attributes); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+func (c *Context) renderNotification(name string, data interface{}) (*template.Notification, error) {
+	t, ok := c.notificationTemplates[name]
+	if !ok {
+		return nil, errors.EntityNotFound{Entity: "notification template", ID: name}
+	}
+
+	return t.Render(data)
+}
+
+// notificationTemplatesHandler lists the notification templates, along with the samples of their data.
+func (g *Gofr) notificationTemplatesHandler(*Context) (interface{}, error) {
+	previews := make([]notificationPreview, 0, len(g.notificationTemplates))
+
+	for _, t := range g.notificationTemplates {
+		previews = append(previews, notificationPreview{Name: t.Name, Channel: t.Channel, Data: t.Data})
+	}
+
+	sort.Slice(previews, func(i, j int) bool { return previews[i].Name < previews[j].Name })
+
+	return types.Raw{Data: previews}, nil
+}
+
+// previewNotificationHandler renders the notification template of the path, with its sample data, or with the data of
+// the body of a POST request.
+func (g *Gofr) previewNotificationHandler(c *Context) (interface{}, error) {
+	name := c.PathParam("name")
+
+	t, ok := g.notificationTemplates[name]
+	if !ok {
+		return nil, errors.EntityNotFound{Entity: "notification template", ID: name}
+	}
+
+	data := t.Data
+
+	if c.Request().Method == http.MethodPost {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if data, err = t.Bind(body); err != nil {
+			return nil, &errors.Response{StatusCode: http.StatusBadRequest, Code: "Invalid Notification Data",
+				Reason: err.Error()}
+		}
+	}
+
+	n, err := t.Render(data)
+	if err != nil {
+		return nil, &errors.Response{StatusCode: http.StatusUnprocessableEntity, Code: "Invalid Notification Template",
+			Reason: err.Error()}
+	}
+
+	if c.Param("format") != "raw" {
+		return types.Raw{Data: n}, nil
+	}
+
+	if n.HTML != "" {
+		return template.File{Content: []byte(n.HTML), ContentType: "text/html; charset=utf-8"}, nil
+	}
+
+	return template.File{Content: []byte(n.Text), ContentType: "text/plain; charset=utf-8"}, nil
+}
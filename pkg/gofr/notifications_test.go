@@ -0,0 +1,139 @@
+package gofr
+
+import (
+	ctx "context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/template"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/notifier"
+)
+
+type welcome struct {
+	Name string `json:"name"`
+}
+
+// mockNotifier records the values published.
+type mockNotifier struct {
+	notifier.Notifier
+	published []interface{}
+}
+
+func (m *mockNotifier) Publish(value interface{}, _ map[string]interface{}) error {
+	m.published = append(m.published, value)
+
+	return nil
+}
+
+func notificationTemplates(t *testing.T) (email, sms *template.NotificationTemplate) {
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"welcome.subject.txt": "Welcome {{.Name}}",
+		"welcome.html":        "<h1>Hi {{.Name}}</h1>",
+		"welcome-sms.txt":     "Hi {{.Name}}",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &template.NotificationTemplate{Name: "welcome", Channel: template.ChannelEmail, Directory: dir,
+			Data: welcome{Name: "Ada"}},
+		&template.NotificationTemplate{Name: "welcome-sms", Channel: template.ChannelSMS, Directory: dir,
+			Data: welcome{Name: "Ada"}}
+}
+
+func TestGofr_NotificationPreviews(t *testing.T) {
+	c := &config.MockConfig{Data: map[string]string{"APP_ENV": "dev"}}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	email, sms := notificationTemplates(t)
+	g.AddNotificationTemplate(email)
+	g.AddNotificationTemplate(sms)
+
+	tests := []struct {
+		desc   string
+		method string
+		target string
+		body   string
+		status int
+		resp   string
+	}{
+		{"list of the templates", http.MethodGet, "/.well-known/notifications", "", http.StatusOK,
+			`[{"name":"welcome","channel":"email","data":{"name":"Ada"}},` +
+				`{"name":"welcome-sms","channel":"sms","data":{"name":"Ada"}}]`},
+		{"sample data", http.MethodGet, "/.well-known/notifications/welcome", "", http.StatusOK,
+			`{"name":"welcome","channel":"email","subject":"Welcome Ada","html":"\u003ch1\u003eHi Ada\u003c/h1\u003e"}`},
+		{"data of the body", http.MethodPost, "/.well-known/notifications/welcome-sms", `{"name":"Grace"}`,
+			http.StatusCreated, `{"name":"welcome-sms","channel":"sms","text":"Hi Grace","segments":1}`},
+		{"raw HTML", http.MethodGet, "/.well-known/notifications/welcome?format=raw", "", http.StatusOK,
+			"<h1>Hi Ada</h1>"},
+		{"raw text", http.MethodGet, "/.well-known/notifications/welcome-sms?format=raw", "", http.StatusOK, "Hi Ada"},
+		{"invalid data", http.MethodPost, "/.well-known/notifications/welcome", `{"user":"Grace"}`,
+			http.StatusBadRequest, "Invalid Notification Data"},
+		{"unknown template", http.MethodGet, "/.well-known/notifications/goodbye", "", http.StatusNotFound, ""},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		g.Server.Router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body)))
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Contains(t, w.Body.String(), tc.resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestGofr_NotificationPreviewsDisabled(t *testing.T) {
+	c := &config.MockConfig{Data: map[string]string{"APP_ENV": "dev", "NOTIFICATION_PREVIEW": "false"}}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	email, _ := notificationTemplates(t)
+	g.AddNotificationTemplate(email)
+
+	w := httptest.NewRecorder()
+	g.Server.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/notifications", http.NoBody))
+
+	assert.Equal(t, http.StatusNotFound, w.Code, "the templates are not previewed when it is disabled")
+}
+
+func TestContext_Notify(t *testing.T) {
+	email, _ := notificationTemplates(t)
+	n := &mockNotifier{}
+
+	g := &Gofr{Config: &config.MockConfig{}}
+	g.AddNotificationTemplate(email)
+
+	c := &Context{Context: ctx.Background(), Gofr: g}
+
+	_, err := c.Notify("welcome", welcome{Name: "Grace"}, nil)
+	assert.Equal(t, errNotifierDisabled, err)
+
+	g.Notifier = n
+
+	sent, err := c.Notify("welcome", welcome{Name: "Grace"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{sent}, n.published, "the notification is published as it is previewed")
+	assert.Equal(t, "Welcome Grace", sent.Subject)
+
+	_, err = c.Notify("goodbye", welcome{}, nil)
+	assert.Equal(t, errors.EntityNotFound{Entity: "notification template", ID: "goodbye"}, err)
+
+	_, err = c.Notify("welcome", types.Raw{}, nil)
+	assert.Equal(t, template.ErrNotificationData, err)
+}
@@ -0,0 +1,397 @@
+package gofr
+
+import (
+	ctx "context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+const (
+	defaultOperationWorkers   = 4
+	defaultOperationQueueSize = 100
+	operationRetention        = time.Hour
+
+	errOperationsDisabled = errors.Error("operations are not enabled, EnableOperations has to be called on the application")
+)
+
+//nolint:gochecknoglobals // operationDuplicates has to be a global variable for prometheus
+var (
+	operationDuplicates = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "zs_operation_duplicates_total",
+		Help: "Counter for the operations which were not enqueued, as an operation with their key was already enqueued",
+	})
+
+	_ = prometheus.Register(operationDuplicates)
+)
+
+// OperationStore stores the state of the operations enqueued using Context.Enqueue. The operations are kept in memory
+// by default, a shared store, ex: on Redis, is required to poll the operations of an application with several instances.
+type OperationStore interface {
+	Save(c ctx.Context, op *types.Operation) error
+	// Get returns errors.EntityNotFound when the operation does not exist.
+	Get(c ctx.Context, id string) (*types.Operation, error)
+}
+
+// memoryOperations stores the operations in memory, the completed operations are removed after an hour.
+type memoryOperations struct {
+	mu  sync.Mutex
+	ops map[string]types.Operation
+	// done are the completed operations, in the order they are completed
+	done []completedOperation
+}
+
+type completedOperation struct {
+	id string
+	at time.Time
+}
+
+func (m *memoryOperations) Save(_ ctx.Context, op *types.Operation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for len(m.done) > 0 && time.Since(m.done[0].at) > operationRetention {
+		delete(m.ops, m.done[0].id)
+		m.done = m.done[1:]
+	}
+
+	if prev, ok := m.ops[op.ID]; op.Done() && (!ok || !prev.Done()) {
+		m.done = append(m.done, completedOperation{id: op.ID, at: time.Now()})
+	}
+
+	m.ops[op.ID] = *op
+
+	return nil
+}
+
+func (m *memoryOperations) Get(_ ctx.Context, id string) (*types.Operation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op, ok := m.ops[id]
+	if !ok {
+		return nil, errors.EntityNotFound{Entity: "operation", ID: id}
+	}
+
+	return &op, nil
+}
+
+type operationJob struct {
+	op      *types.Operation
+	work    Handler
+	context *Context
+}
+
+// operationQueue runs the operations using a fixed number of workers, which are started on the first operation.
+type operationQueue struct {
+	once    sync.Once
+	workers int
+	jobs    chan operationJob
+	memory  *memoryOperations
+	// enabled is whether the state of the operations is served, which is required to enqueue them
+	enabled bool
+	// pending are the operations queued or running, which are waited for on shutdown
+	pending sync.WaitGroup
+
+	// keys are the operations enqueued with a key, in the order they are enqueued, which are held for the retention
+	mu       sync.Mutex
+	keys     map[string]*operationKey
+	keyOrder []*operationKey
+}
+
+type operationKey struct {
+	key string
+	id  string
+	at  time.Time
+	// enqueued is closed once the operation of the key is enqueued, or has failed to be enqueued
+	enqueued chan struct{}
+}
+
+func newOperationQueue(c Config) *operationQueue {
+	q := &operationQueue{
+		workers: defaultOperationWorkers,
+		memory:  &memoryOperations{ops: make(map[string]types.Operation)},
+		keys:    make(map[string]*operationKey),
+	}
+
+	if workers, err := strconv.Atoi(c.Get("OPERATION_WORKERS")); err == nil && workers > 0 {
+		q.workers = workers
+	}
+
+	size := defaultOperationQueueSize
+	if s, err := strconv.Atoi(c.Get("OPERATION_QUEUE_SIZE")); err == nil && s > 0 {
+		size = s
+	}
+
+	q.jobs = make(chan operationJob, size)
+
+	return q
+}
+
+// EnableOperations serves the state of the operations enqueued using Context.Enqueue on /operations/{id}, it has
+// to be called before the operations are enqueued, ex: in main.
+func (g *Gofr) EnableOperations() {
+	if g.operations == nil || g.operations.enabled {
+		return
+	}
+
+	g.operations.enabled = true
+
+	if g.Server != nil {
+		g.Server.Router.Route(http.MethodGet, pkg.PathOperation, OperationHandler)
+	}
+}
+
+// operationPath returns the path of the status of the operation, along with the prefix of the router.
+func (g *Gofr) operationPath(id string) string {
+	var prefix string
+
+	if g.Server != nil {
+		if r, ok := g.Server.Router.(*router); ok {
+			prefix = r.prefix
+		}
+	}
+
+	return prefix + strings.Replace(pkg.PathOperation, "{id}", id, 1)
+}
+
+func (g *Gofr) operationStore() OperationStore {
+	if g.Operations != nil {
+		return g.Operations
+	}
+
+	return g.operations.memory
+}
+
+// Enqueue queues the work to be processed in the background, and returns the operation which tracks its state. The
+// handler returns the operation, so that the request is responded with 202 Accepted and the status URL of the
+// operation, which responds the result of the work once it completes.
+//
+// The work runs with a context which carries the values of the request, ex: the logger and the correlation ID, but
+// not its cancellation. The context has a copy of the request without its body, as the body is closed once the handler
+// returns, hence the body has to be bound before the work is enqueued. An error with status code 503 is returned when
+// the queue is full. The operations are enqueued once EnableOperations is called, and their state is served to the
+// user who enqueued them.
+func (c *Context) Enqueue(work Handler) (*types.Operation, error) {
+	if c.operations == nil || !c.operations.enabled {
+		return nil, errOperationsDisabled
+	}
+
+	now := time.Now().UTC()
+	op := &types.Operation{ID: uuid.NewString(), Status: types.OperationPending, CreatedAt: now, UpdatedAt: now}
+	op.StatusURL = c.operationPath(op.ID)
+
+	if c.req != nil {
+		op.Owner = c.Subject()
+	}
+
+	if err := c.operationStore().Save(c, op); err != nil {
+		return nil, err
+	}
+
+	// the worker updates its own copy of the operation, as the operation returned is responded concurrently
+	state := *op
+
+	job := operationJob{op: &state, work: work, context: &Context{
+		Context: ctx.WithoutCancel(c.Context),
+		Gofr:    c.Gofr,
+		Logger:  c.Logger,
+		req:     detachedRequest(c, nil),
+	}}
+
+	q := c.operations
+	q.once.Do(func() {
+		for i := 0; i < q.workers; i++ {
+			go q.run()
+		}
+	})
+
+	q.pending.Add(1)
+
+	select {
+	case q.jobs <- job:
+		return op, nil
+	default:
+		q.pending.Done()
+
+		op.Status, op.Error, op.UpdatedAt = types.OperationFailed, "queue is full", time.Now().UTC()
+		_ = c.operationStore().Save(c, op)
+
+		return nil, &errors.Response{StatusCode: http.StatusServiceUnavailable, Code: "Queue Full",
+			Reason: "too many operations are in progress, retry later"}
+	}
+}
+
+/*
+EnqueueWithKey enqueues the work like Enqueue, unless an operation with the key was enqueued in the last hour, and has
+not failed. Then the operation is returned instead, so that the work is processed once, even when the client retries
+the request, ex: with the key of the header Idempotency-Key. The key of a request is scoped by its authenticated user,
+so that the users sending the same key do not get the operations of each other.
+
+The keys are claimed atomically by the instance of the application, the retries which reach other instances are not
+deduplicated.
+*/
+func (c *Context) EnqueueWithKey(key string, work Handler) (*types.Operation, error) {
+	if key == "" {
+		return c.Enqueue(work)
+	}
+
+	if c.req != nil {
+		key = c.Subject() + "\x00" + key
+	}
+
+	q := c.operations
+
+	for {
+		// the key is claimed with the lock held, so that the concurrent requests with the key enqueue one operation,
+		// the store is called without the lock, so that a slow store does not hold up the requests with other keys
+		q.mu.Lock()
+		q.expireKeys()
+
+		k, ok := q.keys[key]
+		if !ok {
+			claim := &operationKey{key: key, at: time.Now(), enqueued: make(chan struct{})}
+			q.keys[key] = claim
+			q.keyOrder = append(q.keyOrder, claim)
+			q.mu.Unlock()
+
+			return c.enqueueClaimed(claim, work)
+		}
+
+		q.mu.Unlock()
+
+		<-k.enqueued
+
+		if k.id != "" {
+			if op, err := c.operationStore().Get(c, k.id); err == nil && op.Status != types.OperationFailed {
+				operationDuplicates.Inc()
+
+				return op, nil
+			}
+		}
+
+		// the operation of the key has failed, or was not enqueued, hence the key is claimed again
+		q.mu.Lock()
+		if q.keys[key] == k {
+			delete(q.keys, key)
+		}
+		q.mu.Unlock()
+	}
+}
+
+// enqueueClaimed enqueues the work of the key claimed, and releases the requests waiting for the key.
+func (c *Context) enqueueClaimed(claim *operationKey, work Handler) (*types.Operation, error) {
+	defer close(claim.enqueued)
+
+	op, err := c.Enqueue(work)
+	if err != nil {
+		return nil, err
+	}
+
+	claim.id = op.ID
+
+	return op, nil
+}
+
+// expireKeys removes the keys which are held longer than the retention, it is called with the lock held.
+func (q *operationQueue) expireKeys() {
+	for len(q.keyOrder) > 0 && time.Since(q.keyOrder[0].at) > operationRetention {
+		claim := q.keyOrder[0]
+
+		// a key which is claimed again is held till the retention of its last claim
+		if q.keys[claim.key] == claim {
+			delete(q.keys, claim.key)
+		}
+
+		q.keyOrder = q.keyOrder[1:]
+	}
+}
+
+func (q *operationQueue) run() {
+	for job := range q.jobs {
+		job.process()
+		q.pending.Done()
+	}
+}
+
+// drain waits for the operations queued or running to complete, or for the context to be done.
+func (q *operationQueue) drain(c ctx.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		q.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-c.Done():
+		return c.Err()
+	}
+}
+
+func (j *operationJob) process() {
+	c, op := j.context, j.op
+	store := c.operationStore()
+
+	op.Status, op.UpdatedAt = types.OperationRunning, time.Now().UTC()
+	j.save(store)
+
+	result, err := j.execute()
+
+	op.UpdatedAt = time.Now().UTC()
+
+	if err != nil {
+		op.Status, op.Error = types.OperationFailed, err.Error()
+	} else {
+		op.Status, op.Result = types.OperationSucceeded, result
+	}
+
+	j.save(store)
+}
+
+// execute runs the work, a panic in the work fails the operation instead of stopping the worker.
+func (j *operationJob) execute() (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			j.context.Logger.Errorf("operation %v panicked: %v", j.op.ID, r)
+
+			err = errors.Error("operation failed unexpectedly")
+		}
+	}()
+
+	return j.work(j.context)
+}
+
+func (j *operationJob) save(store OperationStore) {
+	if err := store.Save(j.context, j.op); err != nil {
+		j.context.Logger.Errorf("state of operation %v could not be saved: %v", j.op.ID, err)
+	}
+}
+
+// OperationHandler responds the current state of the operation with 200 OK, and is served on
+// /operations/{id}. The operation of another user is not found.
+func OperationHandler(c *Context) (interface{}, error) {
+	id := c.PathParam("id")
+
+	op, err := c.operationStore().Get(c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if op.Owner != "" && op.Owner != c.Subject() {
+		return nil, errors.EntityNotFound{Entity: "operation", ID: id}
+	}
+
+	return types.Response{Data: op}, nil
+}
@@ -0,0 +1,287 @@
+package gofr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/middleware/oauth"
+)
+
+func operationContext(c Config) *Context {
+	req := httptest.NewRequest(http.MethodGet, "/reports?format=csv", http.NoBody)
+
+	g := &Gofr{Config: c, operations: newOperationQueue(c)}
+	g.EnableOperations()
+
+	ctx := NewContext(nil, request.NewHTTPRequest(req), g)
+	ctx.Context = context.Background()
+
+	return ctx
+}
+
+// waitForOperation polls the operation until it completes, as a client of /operations/{id} does.
+func waitForOperation(t *testing.T, c *Context, id string) *types.Operation {
+	for i := 0; i < 100; i++ {
+		op, err := c.operationStore().Get(c, id)
+		if assert.NoError(t, err) && op.Done() {
+			return op
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("operation %v did not complete", id)
+
+	return nil
+}
+
+func TestContext_Enqueue(t *testing.T) {
+	tests := []struct {
+		desc   string
+		work   Handler
+		status string
+		result interface{}
+		err    string
+	}{
+		{"succeeded", func(*Context) (interface{}, error) { return "report.csv", nil }, types.OperationSucceeded, "report.csv", ""},
+		{"failed", func(*Context) (interface{}, error) { return nil, errors.EntityNotFound{Entity: "report", ID: "1"} },
+			types.OperationFailed, nil, "No 'report' found for Id: '1'"},
+		{"panicked", func(*Context) (interface{}, error) { panic("nil map") }, types.OperationFailed, nil,
+			"operation failed unexpectedly"},
+		{"request of the operation", func(c *Context) (interface{}, error) { return c.Param("format"), nil },
+			types.OperationSucceeded, "csv", ""},
+	}
+
+	c := operationContext(&config.MockConfig{})
+
+	for i, tc := range tests {
+		op, err := c.Enqueue(tc.work)
+		if !assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc) {
+			continue
+		}
+
+		assert.Equal(t, "/operations/"+op.ID, op.StatusURL, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		done := waitForOperation(t, c, op.ID)
+
+		assert.Equal(t, tc.status, done.Status, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.result, done.Result, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.err, done.Error, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestContext_EnqueueDisabled(t *testing.T) {
+	c := operationContext(&config.MockConfig{})
+	c.operations.enabled = false
+
+	_, err := c.Enqueue(func(*Context) (interface{}, error) { return nil, nil })
+
+	assert.Equal(t, errOperationsDisabled, err)
+}
+
+func TestMemoryOperations_Save(t *testing.T) {
+	m := &memoryOperations{ops: make(map[string]types.Operation)}
+
+	_ = m.Save(context.Background(), &types.Operation{ID: "1", Status: types.OperationSucceeded})
+	_ = m.Save(context.Background(), &types.Operation{ID: "2", Status: types.OperationRunning})
+
+	m.done[0].at = time.Now().Add(-2 * operationRetention)
+
+	_ = m.Save(context.Background(), &types.Operation{ID: "2", Status: types.OperationFailed})
+
+	_, err := m.Get(context.Background(), "1")
+	assert.Equal(t, errors.EntityNotFound{Entity: "operation", ID: "1"}, err, "operation is removed after the retention")
+
+	op, err := m.Get(context.Background(), "2")
+	if assert.NoError(t, err) {
+		assert.Equal(t, types.OperationFailed, op.Status)
+	}
+
+	assert.Len(t, m.done, 1)
+}
+
+func TestContext_EnqueueQueueFull(t *testing.T) {
+	c := operationContext(&config.MockConfig{Data: map[string]string{"OPERATION_WORKERS": "1", "OPERATION_QUEUE_SIZE": "1"}})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	block := func(*Context) (interface{}, error) {
+		<-release
+		return nil, nil
+	}
+
+	// the first operation is run by the worker, the second one is queued
+	_, err := c.Enqueue(block)
+	assert.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = c.Enqueue(block)
+	assert.NoError(t, err)
+
+	_, err = c.Enqueue(block)
+	if assert.IsType(t, &errors.Response{}, err) {
+		assert.Equal(t, http.StatusServiceUnavailable, err.(*errors.Response).StatusCode)
+	}
+}
+
+func TestContext_EnqueueWithKey(t *testing.T) {
+	c := operationContext(&config.MockConfig{})
+	before := testutil.ToFloat64(operationDuplicates)
+
+	fail := func(*Context) (interface{}, error) { return nil, errors.Error("connection refused") }
+	succeed := func(*Context) (interface{}, error) { return "report.csv", nil }
+
+	failed, err := c.EnqueueWithKey("report-1", fail)
+	assert.NoError(t, err)
+	waitForOperation(t, c, failed.ID)
+
+	// the failed operation does not hold the key, so that the work is enqueued again
+	op, err := c.EnqueueWithKey("report-1", succeed)
+	assert.NoError(t, err)
+	assert.NotEqual(t, failed.ID, op.ID)
+
+	duplicate, err := c.EnqueueWithKey("report-1", succeed)
+	assert.NoError(t, err)
+	assert.Equal(t, op.ID, duplicate.ID, "operation with the key must be returned")
+
+	other, err := c.EnqueueWithKey("report-2", succeed)
+	assert.NoError(t, err)
+	assert.NotEqual(t, op.ID, other.ID)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(operationDuplicates))
+
+	c.operations.keyOrder[0].at = time.Now().Add(-2 * operationRetention)
+	c.operations.expireKeys()
+
+	assert.Len(t, c.operations.keys, 2, "key claimed again is held till the retention of its last claim")
+}
+
+func TestContext_EnqueueWithPrefix(t *testing.T) {
+	c := operationContext(&config.MockConfig{})
+	c.Server = &server{Router: NewRouter()}
+	c.Server.Router.Prefix("/api")
+
+	op, err := c.Enqueue(func(*Context) (interface{}, error) { return nil, nil })
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "/api/operations/"+op.ID, op.StatusURL)
+	}
+}
+
+// slowOperations is a store whose saves of the operations block till the store is released.
+type slowOperations struct {
+	*memoryOperations
+	release chan struct{}
+}
+
+func (s *slowOperations) Save(c context.Context, op *types.Operation) error {
+	<-s.release
+	return s.memoryOperations.Save(c, op)
+}
+
+func TestContext_EnqueueWithKey_SlowStore(t *testing.T) {
+	c := operationContext(&config.MockConfig{})
+	store := &slowOperations{memoryOperations: c.operations.memory, release: make(chan struct{})}
+	c.Operations = store
+
+	work := func(*Context) (interface{}, error) { return nil, nil }
+	blocked := make(chan struct{})
+
+	go func() {
+		_, _ = c.EnqueueWithKey("report-1", work)
+		close(blocked)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	// the keys are not locked while the operation of report-1 is being saved, so that the other keys are claimed
+	if assert.True(t, c.operations.mu.TryLock(), "keys are locked while the operation is saved") {
+		claimed := len(c.operations.keys)
+		c.operations.mu.Unlock()
+
+		assert.Equal(t, 1, claimed, "key is not claimed before the operation is saved")
+	}
+
+	close(store.release)
+	<-blocked
+
+	op, err := c.EnqueueWithKey("report-1", work)
+	if assert.NoError(t, err) {
+		duplicate, err := c.EnqueueWithKey("report-1", work)
+
+		assert.NoError(t, err)
+		assert.Equal(t, op.ID, duplicate.ID)
+	}
+}
+
+func TestOperationHandler(t *testing.T) {
+	c := operationContext(&config.MockConfig{})
+
+	op, _ := c.Enqueue(func(*Context) (interface{}, error) { return 1, nil })
+	waitForOperation(t, c, op.ID)
+
+	c.SetPathParams(map[string]string{"id": op.ID})
+
+	data, err := OperationHandler(c)
+
+	assert.NoError(t, err)
+
+	if res, ok := data.(types.Response); assert.True(t, ok) {
+		assert.Equal(t, types.OperationSucceeded, res.Data.(*types.Operation).Status)
+	}
+
+	c.SetPathParams(map[string]string{"id": "unknown"})
+
+	_, err = OperationHandler(c)
+
+	assert.Equal(t, errors.EntityNotFound{Entity: "operation", ID: "unknown"}, err)
+}
+
+func TestOperationHandler_Owner(t *testing.T) {
+	c := operationContext(&config.MockConfig{})
+	alice, bob := userContext(c, "alice"), userContext(c, "bob")
+
+	op, _ := alice.Enqueue(func(*Context) (interface{}, error) { return 1, nil })
+	waitForOperation(t, c, op.ID)
+
+	assert.Equal(t, "alice", op.Owner)
+
+	for i, tc := range []struct {
+		desc string
+		c    *Context
+		err  error
+	}{
+		{"operation of the user", alice, nil},
+		{"operation of another user", bob, errors.EntityNotFound{Entity: "operation", ID: op.ID}},
+	} {
+		tc.c.SetPathParams(map[string]string{"id": op.ID})
+
+		_, err := OperationHandler(tc.c)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// userContext returns a context of the application of c, whose request is authenticated as the user.
+func userContext(c *Context, user string) *Context {
+	req := httptest.NewRequest(http.MethodGet, "/reports", http.NoBody)
+	req = req.WithContext(context.WithValue(req.Context(), oauth.JWTContextKey("claims"), jwt.MapClaims{"sub": user}))
+
+	uc := NewContext(nil, request.NewHTTPRequest(req), c.Gofr)
+	uc.Context = req.Context()
+
+	return uc
+}
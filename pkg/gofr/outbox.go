@@ -0,0 +1,382 @@
+package gofr
+
+import (
+	ctx "context"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/log"
+)
+
+const (
+	defaultOutboxInterval  = time.Second
+	defaultOutboxBatchSize = 100
+
+	errOutboxDisabled     = errors.Error("outbox is not enabled, OUTBOX_TABLE is not set")
+	errInvalidOutboxTable = errors.Error("OUTBOX_TABLE is not a valid table name")
+)
+
+// OutboxEventIDHeader is the header of the events published by the outbox relay, of which the value is unique to the
+// event. An event is published again when the relay stops before it is marked, the consumers which must not process
+// an event twice skip the events whose id they have processed.
+const OutboxEventIDHeader = "Outbox-Event-Id"
+
+//nolint:gochecknoglobals // tableNameRegex is compiled once, and the metric has to be a global variable for prometheus
+var (
+	tableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+	outboxDuplicates = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "zs_outbox_duplicate_events_total",
+		Help: "Counter for the outbox events which were not published, as they were claimed by another relay",
+	})
+
+	_ = prometheus.Register(outboxDuplicates)
+)
+
+// OutboxEvent is an event written to the outbox in the transaction of a data change, which is published once the
+// transaction is committed.
+type OutboxEvent struct {
+	// AggregateKey is the key of the published message, the events of a key are published in the order they are
+	// written, ex: the id of the order whose events are published.
+	AggregateKey string
+	// Topic is the topic the event is published to, the topic of the publisher is used when it is empty.
+	Topic string
+	// Value is published as is when it is []byte, and in JSON otherwise.
+	Value   interface{}
+	Headers map[string]string
+}
+
+/*
+outbox publishes the events written to the outbox table OUTBOX_TABLE to the pubsub of the application. The table is
+created by the application, ex: on MySQL
+
+	CREATE TABLE gofr_outbox (
+		id BIGINT AUTO_INCREMENT PRIMARY KEY,
+		aggregate_key VARCHAR(255) NOT NULL,
+		topic VARCHAR(255) NOT NULL,
+		value BLOB NOT NULL,
+		headers TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		published_at TIMESTAMP NULL
+	)
+
+The relay reads the events which are not published every OUTBOX_RELAY_INTERVAL milliseconds, OUTBOX_RELAY_BATCH_SIZE
+events at a time, in the order they are written. An event is claimed by marking it as published before it is published,
+the events which are already marked by the relay of another instance are skipped, and the mark is released when the
+pubsub does not acknowledge the event. The claims are committed once the batch is published, so an event is published
+at least once, ie: it is published again when the relay stops before committing. The events are published with the
+header Outbox-Event-Id, so that the consumers can skip the events published again. The events of a key which follow an
+event which is not published are not published, till the event is, so that the events of a key are published in order.
+The events are locked while they are published on MySQL and Postgres, so that the relays of the instances of the
+application do not publish them concurrently.
+*/
+type outbox struct {
+	table     string
+	dialect   string
+	interval  time.Duration
+	batchSize int
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+type outboxRecord struct {
+	id           int64
+	aggregateKey string
+	topic        string
+	value        []byte
+	headers      string
+}
+
+// newOutbox returns the outbox configured by OUTBOX_TABLE, it is nil when the outbox is not enabled.
+func newOutbox(c Config) *outbox {
+	table := c.Get("OUTBOX_TABLE")
+	if table == "" {
+		return nil
+	}
+
+	o := &outbox{table: table, dialect: strings.ToLower(c.Get("DB_DIALECT")), interval: defaultOutboxInterval,
+		batchSize: defaultOutboxBatchSize, stop: make(chan struct{}), done: make(chan struct{})}
+
+	if ms, err := strconv.Atoi(c.Get("OUTBOX_RELAY_INTERVAL")); err == nil && ms > 0 {
+		o.interval = time.Duration(ms) * time.Millisecond
+	}
+
+	if n := positiveInt(c, "OUTBOX_RELAY_BATCH_SIZE"); n > 0 {
+		o.batchSize = n
+	}
+
+	return o
+}
+
+/*
+WriteOutbox writes the event to the outbox in the transaction, so that the event is published if, and only if, the
+transaction is committed, for ex:
+
+	tx, err := c.DB().BeginTx(c, nil)
+	...
+	_, err = tx.ExecContext(c, "UPDATE orders SET status = ? WHERE id = ?", "shipped", id)
+	...
+	err = c.WriteOutbox(tx, gofr.OutboxEvent{AggregateKey: id, Topic: "orders", Value: event})
+	...
+	err = tx.Commit()
+
+The trace context of the request is written with the headers of the event, so that the consumers of the event are
+traced as part of the request.
+*/
+func (c *Context) WriteOutbox(tx *datastore.SQLTx, event OutboxEvent) error {
+	if c.Gofr == nil || c.outbox == nil {
+		return errOutboxDisabled
+	}
+
+	if !tableNameRegex.MatchString(c.outbox.table) {
+		return errInvalidOutboxTable
+	}
+
+	value, ok := event.Value.([]byte)
+	if !ok {
+		var err error
+
+		if value, err = json.Marshal(event.Value); err != nil {
+			return err
+		}
+	}
+
+	headers, err := json.Marshal(messageHeaders(c.Context, event.Headers))
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec // the table name is validated, and is not a user input
+	query := "INSERT INTO " + c.outbox.table + " (aggregate_key, topic, value, headers, created_at) VALUES (" +
+		c.outbox.placeholders(1, 5) + ")"
+
+	_, err = tx.ExecContext(c.outboxContext(), query, event.AggregateKey, event.Topic, value, string(headers),
+		time.Now().UTC())
+
+	return err
+}
+
+func (c *Context) outboxContext() ctx.Context {
+	if c.Context == nil {
+		return ctx.Background()
+	}
+
+	return c.Context
+}
+
+// start runs the relay till the outbox is stopped.
+func (o *outbox) start(db *datastore.SQLClient, publisher pubsub.PublisherSubscriber, logger log.Logger) {
+	if !tableNameRegex.MatchString(o.table) {
+		logger.Errorf("outbox relay is not started: %v", errInvalidOutboxTable)
+		close(o.done)
+
+		return
+	}
+
+	go func() {
+		defer close(o.done)
+
+		ticker := time.NewTicker(o.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-o.stop:
+				return
+			case <-ticker.C:
+				if _, err := o.relay(db, publisher, logger); err != nil {
+					logger.Errorf("outbox relay failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// drain stops the relay, once the events being published are marked.
+func (o *outbox) drain(c ctx.Context) error {
+	o.once.Do(func() { close(o.stop) })
+
+	select {
+	case <-o.done:
+		return nil
+	case <-c.Done():
+		return c.Err()
+	}
+}
+
+// relay publishes a batch of the events which are not published, and returns the number of the events published.
+func (o *outbox) relay(db *datastore.SQLClient, publisher pubsub.PublisherSubscriber, logger log.Logger) (int, error) {
+	if publisher == nil {
+		return 0, errors.Error("pubsub is not initialized")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	records, err := o.pending(tx)
+	if err != nil {
+		_ = tx.Rollback()
+
+		return 0, err
+	}
+
+	published := 0
+	// blocked are the keys whose event is not published, their following events are published by the next relay
+	blocked := make(map[string]bool)
+
+	for _, r := range records {
+		if blocked[r.aggregateKey] {
+			continue
+		}
+
+		claimed, err := o.claim(tx, r.id)
+		if err != nil {
+			_ = tx.Rollback()
+
+			return published, err
+		}
+
+		if !claimed {
+			outboxDuplicates.Inc()
+
+			continue
+		}
+
+		if err := publish(publisher, o.table, r); err != nil {
+			logger.Errorf("could not publish the event %v of the key %v from the outbox: %v", r.id, r.aggregateKey, err)
+
+			blocked[r.aggregateKey] = true
+
+			if err := o.release(tx, r.id); err != nil {
+				_ = tx.Rollback()
+
+				return published, err
+			}
+
+			continue
+		}
+
+		published++
+	}
+
+	return published, tx.Commit()
+}
+
+// claim marks the event as published, unless it is already marked, and reports whether it is marked by the relay.
+func (o *outbox) claim(tx *datastore.SQLTx, id int64) (bool, error) {
+	//nolint:gosec // the table name is validated, and is not a user input
+	query := "UPDATE " + o.table + " SET published_at = " + o.placeholders(1, 1) + " WHERE id = " + o.placeholders(2, 1) +
+		" AND published_at IS NULL"
+
+	res, err := tx.Exec(query, time.Now().UTC(), id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+
+	return n == 1, err
+}
+
+// release removes the mark of the event, which is claimed but not published.
+func (o *outbox) release(tx *datastore.SQLTx, id int64) error {
+	//nolint:gosec // the table name is validated, and is not a user input
+	_, err := tx.Exec("UPDATE "+o.table+" SET published_at = NULL WHERE id = "+o.placeholders(1, 1), id)
+
+	return err
+}
+
+// pending reads the events which are not published, in the order they are written.
+func (o *outbox) pending(tx *datastore.SQLTx) ([]outboxRecord, error) {
+	//nolint:gosec // the table name is validated, and is not a user input
+	query := "SELECT id, aggregate_key, topic, value, headers FROM " + o.table + " WHERE published_at IS NULL ORDER BY id"
+
+	switch o.dialect {
+	case "mssql":
+		query = "SELECT TOP " + strconv.Itoa(o.batchSize) + " id, aggregate_key, topic, value, headers FROM " + o.table +
+			" WITH (UPDLOCK, ROWLOCK) WHERE published_at IS NULL ORDER BY id"
+	case "mysql", "postgres":
+		query += " LIMIT " + strconv.Itoa(o.batchSize) + " FOR UPDATE"
+	default:
+		query += " LIMIT " + strconv.Itoa(o.batchSize)
+	}
+
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var records []outboxRecord
+
+	for rows.Next() {
+		var r outboxRecord
+
+		if err := rows.Scan(&r.id, &r.aggregateKey, &r.topic, &r.value, &r.headers); err != nil {
+			return nil, err
+		}
+
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
+
+// publish publishes the event with the key, the topic and the headers it is written with, along with its id.
+func publish(publisher pubsub.PublisherSubscriber, table string, r outboxRecord) error {
+	headers := make(map[string]string)
+
+	if r.headers != "" {
+		if err := json.Unmarshal([]byte(r.headers), &headers); err != nil {
+			return err
+		}
+	}
+
+	headers[OutboxEventIDHeader] = table + ":" + strconv.FormatInt(r.id, 10)
+
+	var options *pubsub.PublishOptions
+
+	if r.topic != "" {
+		options = &pubsub.PublishOptions{Topic: r.topic}
+	}
+
+	return publisher.PublishEventWithOptions(r.aggregateKey, r.value, headers, options)
+}
+
+// placeholders returns n placeholders of the dialect starting at the position from, separated by commas.
+func (o *outbox) placeholders(from, n int) string {
+	return placeholders(o.dialect, from, n)
+}
+
+// placeholders returns n placeholders of the SQL dialect starting at the position from, separated by commas, ex:
+// "$1, $2" on postgres and "?, ?" on mysql.
+func placeholders(dialect string, from, n int) string {
+	p := make([]string, n)
+
+	for i := range p {
+		switch dialect {
+		case "postgres":
+			p[i] = "$" + strconv.Itoa(from+i)
+		case "mssql":
+			p[i] = "@p" + strconv.Itoa(from+i)
+		default:
+			p[i] = "?"
+		}
+	}
+
+	return strings.Join(p, ", ")
+}
@@ -0,0 +1,140 @@
+package gofr
+
+import (
+	"bytes"
+	ctx "context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+// mockOutboxPublisher fails to publish the events of the key failKey, and records the keys of the published events.
+type mockOutboxPublisher struct {
+	pubsub.PublisherSubscriber
+	failKey string
+	keys    []string
+	topics  []string
+	ids     []string
+}
+
+func (m *mockOutboxPublisher) PublishEventWithOptions(key string, _ interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) error {
+	if key == m.failKey {
+		return errors.Error("broker not available")
+	}
+
+	m.keys = append(m.keys, key)
+	m.ids = append(m.ids, headers[OutboxEventIDHeader])
+
+	if options != nil {
+		m.topics = append(m.topics, options.Topic)
+	}
+
+	return nil
+}
+
+func TestNewOutbox(t *testing.T) {
+	assert.Nil(t, newOutbox(&config.MockConfig{}), "the outbox is not enabled without OUTBOX_TABLE")
+
+	o := newOutbox(&config.MockConfig{Data: map[string]string{"OUTBOX_TABLE": "outbox", "DB_DIALECT": "Postgres",
+		"OUTBOX_RELAY_INTERVAL": "500", "OUTBOX_RELAY_BATCH_SIZE": "10"}})
+
+	assert.Equal(t, "postgres", o.dialect)
+	assert.Equal(t, 500*time.Millisecond, o.interval)
+	assert.Equal(t, 10, o.batchSize)
+	assert.Equal(t, "$2, $3", o.placeholders(2, 2))
+}
+
+func TestContext_WriteOutbox(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	client := &datastore.SQLClient{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO outbox \\(aggregate_key, topic, value, headers, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs("order-1", "orders", []byte(`{"status":"shipped"}`), `{"X-Tenant":"t1"}`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	tx, _ := client.Begin()
+
+	c := &Context{Context: ctx.Background(), Gofr: &Gofr{outbox: &outbox{table: "outbox", dialect: "mysql"}}}
+
+	err := c.WriteOutbox(tx, OutboxEvent{AggregateKey: "order-1", Topic: "orders",
+		Value: map[string]string{"status": "shipped"}, Headers: map[string]string{"X-Tenant": "t1"}})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	c.outbox.table = "outbox; DROP TABLE orders"
+	assert.Equal(t, errInvalidOutboxTable, c.WriteOutbox(tx, OutboxEvent{}))
+
+	c.outbox = nil
+	assert.Equal(t, errOutboxDisabled, c.WriteOutbox(tx, OutboxEvent{}))
+}
+
+func TestOutbox_Relay(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "aggregate_key", "topic", "value", "headers"}).
+		AddRow(1, "order-1", "orders", []byte(`{}`), `{}`).
+		AddRow(2, "order-2", "", []byte(`{}`), "").
+		AddRow(3, "order-1", "orders", []byte(`{}`), `{}`).
+		AddRow(4, "order-3", "", []byte(`{}`), "")
+
+	claim := "UPDATE outbox SET published_at = \\$1 WHERE id = \\$2 AND published_at IS NULL"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, aggregate_key, topic, value, headers FROM outbox WHERE published_at IS NULL " +
+		"ORDER BY id LIMIT 100 FOR UPDATE").WillReturnRows(rows)
+	mock.ExpectExec(claim).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE outbox SET published_at = NULL WHERE id = \\$1").WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(claim).WithArgs(sqlmock.AnyArg(), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	// the event 4 is claimed by the relay of another instance
+	mock.ExpectExec(claim).WithArgs(sqlmock.AnyArg(), 4).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	duplicates := testutil.ToFloat64(outboxDuplicates)
+
+	publisher := &mockOutboxPublisher{failKey: "order-1"}
+	o := &outbox{table: "outbox", dialect: "postgres", batchSize: defaultOutboxBatchSize}
+
+	published, err := o.relay(&datastore.SQLClient{DB: db}, publisher, log.NewMockLogger(new(bytes.Buffer)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []string{"order-2"}, publisher.keys, "the events which follow a failed event of the key are not published")
+	assert.Equal(t, []string{"outbox:2"}, publisher.ids)
+	assert.Equal(t, duplicates+1, testutil.ToFloat64(outboxDuplicates))
+	assert.Empty(t, publisher.topics, "the topic of the publisher is used when the event has no topic")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutbox_Drain(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "aggregate_key", "topic", "value", "headers"}))
+	mock.ExpectCommit()
+
+	o := newOutbox(&config.MockConfig{Data: map[string]string{"OUTBOX_TABLE": "outbox", "OUTBOX_RELAY_INTERVAL": "10"}})
+
+	o.start(&datastore.SQLClient{DB: db}, &mockOutboxPublisher{}, log.NewMockLogger(new(bytes.Buffer)))
+
+	time.Sleep(15 * time.Millisecond)
+
+	assert.NoError(t, o.drain(ctx.Background()))
+	assert.NoError(t, o.drain(ctx.Background()), "the outbox can be drained again")
+}
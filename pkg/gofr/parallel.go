@@ -0,0 +1,142 @@
+package gofr
+
+import (
+	ctx "context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"gofr.dev/pkg/errors"
+)
+
+// Call is a downstream or datastore call run by Parallel, ctx is the context of the request along with the span of
+// the call, so that the calls made using it are traced as its children.
+type Call func(ctx ctx.Context) (interface{}, error)
+
+// Parallel runs the calls of a request concurrently, it is created using Context.Parallel.
+type Parallel struct {
+	c     *Context
+	calls []Call
+	limit int
+}
+
+// Parallel returns the calls to be run concurrently by Run, for ex:
+//
+//	results, err := c.Parallel(
+//		func(ctx context.Context) (interface{}, error) { return users.Get(ctx, "users/"+id, nil) },
+//		func(ctx context.Context) (interface{}, error) { return orders.Get(ctx, "orders", params) },
+//	).WithLimit(2).Run()
+func (c *Context) Parallel(calls ...Call) *Parallel {
+	return &Parallel{c: c, calls: calls}
+}
+
+// WithLimit sets the number of the calls which run at the same time, all the calls run at the same time when it is
+// not set, or when it is not positive.
+func (p *Parallel) WithLimit(n int) *Parallel {
+	p.limit = n
+
+	return p
+}
+
+/*
+Run runs the calls and waits for them to return. The results are in the order of the calls, the result of a call which
+failed is nil. The errors of the calls are returned as errors.MultipleErrors, in the order of the calls, with the status
+code of the first call which failed, so that the request is responded as the handler returned its error. The calls
+which are not started before the deadline of the request is exceeded, or the request is canceled, fail with the error
+of the context. Every call is traced by its own span, and a call which panics fails with a 500 error.
+*/
+func (p *Parallel) Run() ([]interface{}, error) {
+	parent := ctx.Context(ctx.Background())
+	if p.c != nil && p.c.Context != nil {
+		parent = p.c.Context
+	}
+
+	limit := p.limit
+	if limit <= 0 || limit > len(p.calls) {
+		limit = len(p.calls)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, limit)
+		results = make([]interface{}, len(p.calls))
+		errs    = make([]error, len(p.calls))
+	)
+
+	for i, call := range p.calls {
+		select {
+		case sem <- struct{}{}:
+		case <-parent.Done():
+			errs[i] = parent.Err()
+			continue
+		}
+
+		// the deadline can be exceeded while a call is waiting for its turn
+		if err := parent.Err(); err != nil {
+			<-sem
+
+			errs[i] = err
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, call Call) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			results[i], errs[i] = runCall(parent, i, call)
+		}(i, call)
+	}
+
+	wg.Wait()
+
+	var failed []error
+
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+
+	if len(failed) > 0 {
+		status := processErrors(failed[0], "", "", true, time.Now()).StatusCode
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+
+		return results, errors.MultipleErrors{StatusCode: status, Errors: failed}
+	}
+
+	return results, nil
+}
+
+// runCall runs the call in its own span, the panic of the call is recovered as its error.
+func runCall(parent ctx.Context, i int, call Call) (result interface{}, err error) {
+	tr := trace.SpanFromContext(parent).TracerProvider().Tracer("gofr-context")
+
+	spanCtx, span := tr.Start(parent, "parallel-"+strconv.Itoa(i), trace.WithAttributes(attribute.Int("parallel.index", i)))
+	defer span.End()
+
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, &errors.Response{StatusCode: http.StatusInternalServerError, Code: "Internal Server Error",
+				Reason: fmt.Sprintf("call %v panicked: %v", i, r)}
+		}
+
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}()
+
+	return call(spanCtx)
+}
@@ -0,0 +1,132 @@
+package gofr
+
+import (
+	"bytes"
+	ctx "context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/gofr/responder"
+	"gofr.dev/pkg/log"
+)
+
+func TestContext_Parallel(t *testing.T) {
+	errCall := errors.Error("service unavailable")
+
+	c := &Context{Context: ctx.Background()}
+
+	results, err := c.Parallel(
+		func(ctx.Context) (interface{}, error) { return 1, nil },
+		func(ctx.Context) (interface{}, error) { return nil, errCall },
+		func(ctx.Context) (interface{}, error) { panic("nil map") },
+		func(ctx.Context) (interface{}, error) { return "4", nil },
+	).Run()
+
+	assert.Equal(t, []interface{}{1, nil, nil, "4"}, results)
+	assert.Equal(t, errors.MultipleErrors{StatusCode: http.StatusInternalServerError, Errors: []error{errCall,
+		&errors.Response{StatusCode: http.StatusInternalServerError, Code: "Internal Server Error",
+			Reason: "call 2 panicked: nil map"}}}, err)
+}
+
+func TestParallel_StatusCode(t *testing.T) {
+	notFound := func(ctx.Context) (interface{}, error) { return nil, errors.EntityNotFound{Entity: "user", ID: "1"} }
+	invalid := func(ctx.Context) (interface{}, error) { return nil, errors.InvalidParam{Param: []string{"id"}} }
+	unavailable := func(ctx.Context) (interface{}, error) {
+		return nil, &errors.Response{StatusCode: http.StatusServiceUnavailable, Reason: "service unavailable"}
+	}
+	unknown := func(ctx.Context) (interface{}, error) { return nil, &errors.Response{Reason: "unknown"} }
+	panics := func(ctx.Context) (interface{}, error) { panic("nil map") }
+
+	tests := []struct {
+		desc   string
+		calls  []Call
+		status int
+	}{
+		{"status of the first failed call", []Call{notFound, invalid}, http.StatusNotFound},
+		{"status of the error response", []Call{unavailable, notFound}, http.StatusServiceUnavailable},
+		{"error response without status", []Call{unknown}, http.StatusInternalServerError},
+		{"panic", []Call{panics, invalid}, http.StatusInternalServerError},
+	}
+
+	for i, tc := range tests {
+		_, err := (&Context{Context: ctx.Background()}).Parallel(tc.calls...).Run()
+
+		if assert.IsType(t, errors.MultipleErrors{}, err, "TEST[%d], failed.\n%s", i, tc.desc) {
+			assert.Equal(t, tc.status, err.(errors.MultipleErrors).StatusCode, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestParallel_PanicResponse(t *testing.T) {
+	w := newCustomWriter()
+	r := routeKeySetter(w, httptest.NewRequest(http.MethodGet, "/users", http.NoBody))
+	resp := responder.NewContextualResponder(w, r)
+	c := NewContext(resp, request.NewHTTPRequest(r), &Gofr{Logger: log.NewMockLogger(new(bytes.Buffer))})
+	c.Context = ctx.Background()
+	r = r.WithContext(ctx.WithValue(r.Context(), gofrContextkey, c))
+
+	Handler(func(c *Context) (interface{}, error) {
+		results, err := c.Parallel(func(ctx.Context) (interface{}, error) { panic("nil map") }).Run()
+		if err != nil {
+			return nil, err
+		}
+
+		return results, nil
+	}).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Status)
+	assert.Contains(t, w.Body, "call 0 panicked: nil map")
+}
+
+func TestParallel_WithLimit(t *testing.T) {
+	var running, maxRunning int32
+
+	call := func(ctx.Context) (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		return nil, nil
+	}
+
+	c := &Context{Context: ctx.Background()}
+
+	_, err := c.Parallel(call, call, call, call, call).WithLimit(2).Run()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), maxRunning)
+}
+
+func TestParallel_Deadline(t *testing.T) {
+	deadline, cancel := ctx.WithTimeout(ctx.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	c := &Context{Context: deadline}
+
+	slow := func(ctx ctx.Context) (interface{}, error) {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	results, err := c.Parallel(slow, slow).WithLimit(1).Run()
+
+	assert.Equal(t, []interface{}{nil, nil}, results)
+	assert.Equal(t, errors.MultipleErrors{StatusCode: http.StatusInternalServerError,
+		Errors: []error{ctx.DeadlineExceeded, ctx.DeadlineExceeded}}, err)
+}
@@ -0,0 +1,59 @@
+package gofr
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/plugin"
+)
+
+// initializePlugins initializes the plugins registered by the blank imports of the application, and adds their health
+// checks, metrics and middlewares. A pubsub plugin is the pubsub of the application when PUBSUB_BACKEND is its name.
+func initializePlugins(c Config, g *Gofr) {
+	for _, name := range plugin.Registered() {
+		g.addPlugin(c, name, plugin.New(name))
+	}
+}
+
+func (g *Gofr) addPlugin(c Config, name string, p plugin.Plugin) {
+	if err := p.Init(c, g.Logger); err != nil {
+		g.Logger.Errorf("plugin %v could not be initialized: %v", name, err)
+		return
+	}
+
+	if g.plugins == nil {
+		g.plugins = make(map[string]plugin.Plugin)
+	}
+
+	g.plugins[name] = p
+
+	if h, ok := p.(plugin.HealthChecker); ok {
+		g.DatabaseHealth = append(g.DatabaseHealth, h.HealthCheck)
+	}
+
+	if m, ok := p.(plugin.MetricsCollector); ok {
+		for _, collector := range m.Collectors() {
+			if err := prometheus.Register(collector); err != nil {
+				g.Logger.Warnf("metrics of plugin %v could not be registered: %v", name, err)
+			}
+		}
+	}
+
+	if m, ok := p.(plugin.Middleware); ok && g.Server != nil {
+		g.Server.Router.Use(m.Middleware())
+	}
+
+	if ps, ok := p.(pubsub.PublisherSubscriber); ok && strings.EqualFold(c.Get("PUBSUB_BACKEND"), name) {
+		g.PubSub = ps
+	}
+
+	g.Logger.Infof("plugin %v initialized", name)
+}
+
+// Plugin returns the plugin registered by the name, ex: app.Plugin("couchbase").(*couchbase.Client). It returns nil
+// when the plugin is not registered, or could not be initialized.
+func (g *Gofr) Plugin(name string) plugin.Plugin {
+	return g.plugins[name]
+}
@@ -0,0 +1,73 @@
+package gofr
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/plugin"
+)
+
+type mockStorePlugin struct {
+	pubsub.PublisherSubscriber
+	err error
+}
+
+func (m *mockStorePlugin) Init(plugin.Config, log.Logger) error { return m.err }
+
+func (m *mockStorePlugin) HealthCheck() types.Health {
+	return types.Health{Name: "mock-store", Status: pkg.StatusUp}
+}
+
+func (m *mockStorePlugin) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{prometheus.NewCounter(prometheus.CounterOpts{Name: "zs_mock_store_queries_total"})}
+}
+
+func (m *mockStorePlugin) Middleware() func(inner http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Mock-Store", "true")
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestGofr_addPlugin(t *testing.T) {
+	b := new(bytes.Buffer)
+	c := &config.MockConfig{Data: map[string]string{"PUBSUB_BACKEND": "mock-store"}}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(b)}
+	g.Server = NewServer(c, g)
+
+	store := &mockStorePlugin{}
+
+	g.addPlugin(c, "mock-store", store)
+	g.addPlugin(c, "mock-broken", &mockStorePlugin{err: errors.Error("connection refused")})
+
+	assert.Equal(t, store, g.Plugin("mock-store"))
+	assert.Nil(t, g.Plugin("mock-broken"))
+	assert.Contains(t, b.String(), "plugin mock-broken could not be initialized: connection refused")
+
+	assert.Equal(t, store, g.PubSub)
+
+	if assert.Len(t, g.DatabaseHealth, 1) {
+		assert.Equal(t, "mock-store", g.DatabaseHealth[0]().Name)
+	}
+
+	g.Server.Router.Use(g.Server.contextInjector)
+	g.GET("/hello", func(*Context) (interface{}, error) { return helloWorld, nil })
+
+	w := httptest.NewRecorder()
+	g.Server.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hello", http.NoBody))
+
+	assert.Equal(t, "true", w.Header().Get("X-Mock-Store"))
+}
@@ -0,0 +1,221 @@
+package gofr
+
+import (
+	ctx "context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+// actions of the privacy audit records
+const (
+	PrivacyActionExport = "export"
+	PrivacyActionErase  = "erase"
+
+	defaultPrivacyScope = "privacy"
+)
+
+// PrivacyHandler exports and erases the personal data of a subject which is held by a store, to fulfill the
+// subject-access and the right-to-be-forgotten requests. Either of the functions can be nil.
+type PrivacyHandler struct {
+	// Export returns the personal data of the subject, it is responded under the name of the store.
+	Export func(c *Context, subjectID string) (interface{}, error)
+	// Erase deletes, or anonymizes, the personal data of the subject. It must be idempotent, as a failed erasure
+	// is fulfilled by requesting it again.
+	Erase func(c *Context, subjectID string) error
+}
+
+// PrivacyRecord is the audit record of an export or an erasure of the personal data of a subject by a store.
+type PrivacyRecord struct {
+	Action        string    `json:"action"`
+	SubjectID     string    `json:"subjectId"`
+	Store         string    `json:"store"`
+	Actor         string    `json:"actor"`
+	Status        string    `json:"status"`
+	Error         string    `json:"error,omitempty"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
+// PrivacyAuditTrail records the exports and the erasures of the personal data. The records are logged when the
+// application does not have an audit trail, the records must be kept in a durable store to be presented to an auditor.
+type PrivacyAuditTrail interface {
+	Record(c ctx.Context, r *PrivacyRecord) error
+}
+
+// RegisterPrivacyHandler registers the export and the erasure of the personal data held by the store. On the first
+// registration, the following routes are added, which require an authenticated user, who is either the subject, or
+// has the scope PRIVACY_SCOPE (default is privacy). The user is the subject of the JWT, or the user sent by the gateway
+// when TRUSTED_GATEWAY is true:
+//
+//	GET /privacy/subjects/{id}     responds the personal data of the subject, keyed by the store
+//	DELETE /privacy/subjects/{id}  erases the personal data of the subject as an operation, responded with 202 Accepted
+//
+// A command-line application gets the commands "privacy export -id=<subject>" and "privacy erase -id=<subject>" instead,
+// to fulfill the requests as jobs.
+func (g *Gofr) RegisterPrivacyHandler(store string, h PrivacyHandler) {
+	if g.privacyHandlers == nil {
+		g.privacyHandlers = make(map[string]PrivacyHandler)
+		g.addPrivacyRoutes()
+	}
+
+	g.privacyHandlers[store] = h
+}
+
+func (g *Gofr) addPrivacyRoutes() {
+	if g.cmd != nil {
+		g.cmd.Router.AddRoute("privacy export", ExportSubjectHandler)
+		g.cmd.Router.AddRoute("privacy erase", EraseSubjectHandler)
+
+		return
+	}
+
+	g.EnableOperations()
+	g.GET(pkg.PathPrivacySubject, authorizePrivacy(ExportSubjectHandler)).
+		Doc("Export personal data", "Returns the personal data of the subject held by every store", "privacy")
+	g.DELETE(pkg.PathPrivacySubject, authorizePrivacy(func(c *Context) (interface{}, error) {
+		// the operation runs without the request, hence the subject and the actor are read before it is enqueued
+		r := newPrivacyRequest(c)
+
+		return c.Enqueue(func(c *Context) (interface{}, error) { return r.erase(c) })
+	})).Doc("Erase personal data", "Erases the personal data of the subject from every store, as an operation", "privacy")
+}
+
+// authorizePrivacy allows the subject to access their own data, and the users with the privacy scope to access the
+// data of any subject.
+func authorizePrivacy(handler Handler) Handler {
+	return requireAuth(&RouteAuth{Required: true}, func(c *Context) (interface{}, error) {
+		scope := defaultPrivacyScope
+		if c.Config != nil && c.Config.Get("PRIVACY_SCOPE") != "" {
+			scope = c.Config.Get("PRIVACY_SCOPE")
+		}
+
+		if authenticatedSubject(c) != c.PathParam("id") && !hasScope(c, scope) {
+			return nil, &errors.Response{StatusCode: http.StatusForbidden, Code: "Forbidden",
+				Reason: "personal data of other subjects requires the scope " + scope}
+		}
+
+		return handler(c)
+	})
+}
+
+// privacyRequest is an export or an erasure requested for a subject.
+type privacyRequest struct {
+	subjectID     string
+	actor         string
+	correlationID string
+}
+
+// newPrivacyRequest reads the subject from the path of the request, or from the flag id of the command, the actor
+// of a command is the operator who runs it.
+func newPrivacyRequest(c *Context) privacyRequest {
+	r := privacyRequest{subjectID: c.PathParam("id"), actor: "command-line"}
+
+	if req := c.Request(); req != nil {
+		r.actor = authenticatedSubject(c)
+		r.correlationID = req.Header.Get("X-Correlation-ID")
+	}
+
+	return r
+}
+
+// ExportSubjectHandler responds the personal data of the subject held by the stores, keyed by the name of the store.
+func ExportSubjectHandler(c *Context) (interface{}, error) {
+	r := newPrivacyRequest(c)
+	if r.subjectID == "" {
+		return nil, errors.MissingParam{Param: []string{"id"}}
+	}
+
+	data := make(map[string]interface{})
+
+	for _, store := range c.privacyStores() {
+		export := c.privacyHandlers[store].Export
+		if export == nil {
+			continue
+		}
+
+		d, err := export(c, r.subjectID)
+		r.audit(c, PrivacyActionExport, store, err)
+
+		if err != nil {
+			return nil, err
+		}
+
+		data[store] = d
+	}
+
+	return types.Response{Data: data}, nil
+}
+
+// EraseSubjectHandler erases the personal data of the subject from all the stores.
+func EraseSubjectHandler(c *Context) (interface{}, error) {
+	r := newPrivacyRequest(c)
+	if r.subjectID == "" {
+		return nil, errors.MissingParam{Param: []string{"id"}}
+	}
+
+	return r.erase(c)
+}
+
+// erase continues with the other stores when a store fails, and the stores which failed are returned in the error.
+func (r *privacyRequest) erase(c *Context) (interface{}, error) {
+	var failed []string
+
+	for _, store := range c.privacyStores() {
+		erase := c.privacyHandlers[store].Erase
+		if erase == nil {
+			continue
+		}
+
+		err := erase(c, r.subjectID)
+		r.audit(c, PrivacyActionErase, store, err)
+
+		if err != nil {
+			failed = append(failed, store)
+		}
+	}
+
+	if len(failed) > 0 {
+		return nil, errors.Error("personal data could not be erased from " + strings.Join(failed, ", "))
+	}
+
+	return "personal data of " + r.subjectID + " is erased", nil
+}
+
+// privacyStores returns the names of the stores sorted, so that the stores are called in the same order every time.
+func (g *Gofr) privacyStores() []string {
+	stores := make([]string, 0, len(g.privacyHandlers))
+
+	for store := range g.privacyHandlers {
+		stores = append(stores, store)
+	}
+
+	sort.Strings(stores)
+
+	return stores
+}
+
+func (r *privacyRequest) audit(c *Context, action, store string, err error) {
+	record := &PrivacyRecord{Action: action, SubjectID: r.subjectID, Store: store, Actor: r.actor, Status: "succeeded",
+		CorrelationID: r.correlationID, Time: time.Now().UTC()}
+
+	if err != nil {
+		record.Status, record.Error = "failed", err.Error()
+	}
+
+	if c.PrivacyAudit == nil {
+		c.Logger.Infof("privacy audit: %v of subject %v by store %v %v, actor: %q, error: %q",
+			action, r.subjectID, store, record.Status, record.Actor, record.Error)
+
+		return
+	}
+
+	if err := c.PrivacyAudit.Record(c, record); err != nil {
+		c.Logger.Errorf("privacy audit record of %v of subject %v could not be saved: %v", action, r.subjectID, err)
+	}
+}
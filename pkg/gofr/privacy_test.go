@@ -0,0 +1,183 @@
+package gofr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
+	"gofr.dev/pkg/middleware/oauth"
+)
+
+type mockPrivacyAudit struct {
+	mu      sync.Mutex
+	records []PrivacyRecord
+}
+
+func (m *mockPrivacyAudit) Record(_ context.Context, r *PrivacyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records = append(m.records, *r)
+
+	return nil
+}
+
+func (m *mockPrivacyAudit) get() []PrivacyRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]PrivacyRecord{}, m.records...)
+}
+
+func privacyApp() (*Gofr, *mockPrivacyAudit) {
+	c := &config.MockConfig{}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(new(bytes.Buffer)), operations: newOperationQueue(c)}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	audit := &mockPrivacyAudit{}
+	g.PrivacyAudit = audit
+
+	g.RegisterPrivacyHandler("orders", PrivacyHandler{
+		Export: func(_ *Context, id string) (interface{}, error) { return []string{"order of " + id}, nil },
+		Erase:  func(*Context, string) error { return nil },
+	})
+	g.RegisterPrivacyHandler("profiles", PrivacyHandler{
+		Export: func(_ *Context, id string) (interface{}, error) { return map[string]string{"id": id}, nil },
+	})
+
+	return g, audit
+}
+
+func TestPrivacy_Routes(t *testing.T) {
+	g, audit := privacyApp()
+
+	tests := []struct {
+		desc   string
+		method string
+		header string
+		user   string
+		status int
+	}{
+		{"unauthenticated", http.MethodGet, "", "", http.StatusUnauthorized},
+		{"user of untrusted gateway", http.MethodGet, "u-1", "", http.StatusUnauthorized},
+		{"data of other subject", http.MethodGet, "", "u-2", http.StatusForbidden},
+		{"export", http.MethodGet, "", "u-1", http.StatusOK},
+		{"erase", http.MethodDelete, "", "u-1", http.StatusAccepted},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(tc.method, "/privacy/subjects/u-1", http.NoBody)
+		if tc.header != "" {
+			r.Header.Set("X-Authenticated-UserId", tc.header)
+			r = r.WithContext(context.WithValue(r.Context(), middleware.AuthenticatedUserIDKey, tc.header))
+		}
+
+		if tc.user != "" {
+			r = r.WithContext(context.WithValue(r.Context(), oauth.JWTContextKey("claims"), jwt.MapClaims{"sub": tc.user}))
+		}
+
+		w := httptest.NewRecorder()
+		g.Server.Router.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.status != http.StatusAccepted {
+			continue
+		}
+
+		var resp struct {
+			Data types.Operation `json:"data"`
+		}
+
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		op := waitForOperation(t, &Context{Context: context.Background(), Gofr: g}, resp.Data.ID)
+		assert.Equal(t, types.OperationSucceeded, op.Status, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.Equal(t, []string{"export orders u-1", "export profiles u-1", "erase orders u-1"}, auditedActions(audit.get()))
+}
+
+func TestPrivacy_TrustedGateway(t *testing.T) {
+	g, _ := privacyApp()
+	g.Config = &config.MockConfig{Data: map[string]string{"TRUSTED_GATEWAY": "true"}}
+
+	r := httptest.NewRequest(http.MethodGet, "/privacy/subjects/u-1", http.NoBody)
+	r = r.WithContext(context.WithValue(r.Context(), middleware.AuthenticatedUserIDKey, "u-1"))
+
+	w := httptest.NewRecorder()
+	g.Server.Router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func auditedActions(records []PrivacyRecord) []string {
+	actions := make([]string, 0, len(records))
+
+	for _, r := range records {
+		actions = append(actions, r.Action+" "+r.Store+" "+r.Actor)
+	}
+
+	return actions
+}
+
+func TestNewPrivacyRequest_Actor(t *testing.T) {
+	tests := []struct {
+		desc    string
+		trusted string
+		actor   string
+	}{
+		{"user id sent by the client", "", ""},
+		{"user id sent by the trusted gateway", "true", "u-1"},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodDelete, "/privacy/subjects/u-2", http.NoBody)
+		r = r.WithContext(context.WithValue(r.Context(), middleware.AuthenticatedUserIDKey, "u-1"))
+
+		g := &Gofr{Config: &config.MockConfig{Data: map[string]string{"TRUSTED_GATEWAY": tc.trusted}}}
+
+		assert.Equal(t, tc.actor, newPrivacyRequest(NewContext(nil, request.NewHTTPRequest(r), g)).actor,
+			"TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestEraseSubjectHandler(t *testing.T) {
+	g, audit := privacyApp()
+	g.Config = &config.MockConfig{Data: map[string]string{"TRUSTED_GATEWAY": "true"}}
+	g.RegisterPrivacyHandler("payments", PrivacyHandler{
+		Erase: func(*Context, string) error { return errors.Error("connection refused") },
+	})
+
+	r := httptest.NewRequest(http.MethodDelete, "/privacy/subjects/u-1", http.NoBody)
+	r = r.WithContext(context.WithValue(r.Context(), middleware.AuthenticatedUserIDKey, "admin"))
+
+	c := NewContext(nil, request.NewHTTPRequest(r), g)
+	c.Context = r.Context()
+	c.Logger = g.Logger
+	c.SetPathParams(map[string]string{"id": "u-1"})
+
+	_, err := EraseSubjectHandler(c)
+
+	assert.EqualError(t, err, "personal data could not be erased from payments")
+
+	records := audit.get()
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, PrivacyRecord{Action: PrivacyActionErase, SubjectID: "u-1", Store: "payments", Actor: "admin",
+			Status: "failed", Error: "connection refused", Time: records[1].Time}, records[1])
+	}
+}
@@ -0,0 +1,147 @@
+package gofr
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/log"
+)
+
+// ProbeAuth protects the metrics, the pprof, the health, the routes and the config endpoints, which expose the internal
+// details of the application, the routes and the config endpoints are served only when the ProbeAuth is set. A request
+// is allowed when it has the bearer token, a client certificate signed by the client CAs (mTLS), or comes from the
+// allowed networks, ex: the nodes whose kubelets probe the application.
+type ProbeAuth struct {
+	// Token is matched against the bearer token of the Authorization header, it is set using PROBE_AUTH_TOKEN.
+	Token string
+	// ClientCAs verify the client certificates of the requests, it is read from PROBE_CLIENT_CA_FILE. The certificates
+	// are requested on the HTTPS port, and on the metrics port when it is served over TLS.
+	ClientCAs *x509.CertPool
+	// AllowedCIDRs are the networks whose requests are allowed without a token or a certificate, it is set using
+	// PROBE_ALLOWED_CIDRS.
+	AllowedCIDRs []*net.IPNet
+}
+
+// getProbeAuth reads the probe auth from the configs, it is nil when none of them is set. The invalid networks are
+// logged and skipped, and the endpoints are protected by the token and the networks when the CA file is invalid.
+func getProbeAuth(c Config, logger log.Logger) *ProbeAuth {
+	auth := ProbeAuth{Token: c.Get("PROBE_AUTH_TOKEN")}
+
+	for _, cidr := range splitList(c.Get("PROBE_ALLOWED_CIDRS")) {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			logger.Errorf("PROBE_ALLOWED_CIDRS has an invalid network %v: %v", cidr, err)
+			continue
+		}
+
+		auth.AllowedCIDRs = append(auth.AllowedCIDRs, network)
+	}
+
+	file := c.Get("PROBE_CLIENT_CA_FILE")
+	if file != "" {
+		pem, err := os.ReadFile(file)
+		if err != nil {
+			logger.Errorf("PROBE_CLIENT_CA_FILE %v could not be read: %v", file, err)
+		} else if auth.ClientCAs = x509.NewCertPool(); !auth.ClientCAs.AppendCertsFromPEM(pem) {
+			logger.Errorf("PROBE_CLIENT_CA_FILE %v has no PEM encoded certificate", file)
+		}
+	}
+
+	if auth.Token == "" && file == "" && c.Get("PROBE_ALLOWED_CIDRS") == "" {
+		return nil
+	}
+
+	return &auth
+}
+
+// allowed reports whether the request has the token, a verified client certificate, or comes from an allowed network.
+// The remote address of the connection is used for the networks, as the forwarded headers can be set by the clients.
+func (p *ProbeAuth) allowed(r *http.Request) bool {
+	if p.Token != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(p.Token)) == 1 {
+			return true
+		}
+	}
+
+	if p.ClientCAs != nil && r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		opts := x509.VerifyOptions{Roots: p.ClientCAs, Intermediates: x509.NewCertPool(),
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+
+		for _, cert := range r.TLS.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+
+		if _, err := r.TLS.PeerCertificates[0].Verify(opts); err == nil {
+			return true
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		for _, network := range p.AllowedCIDRs {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// handler rejects the requests which are not allowed with 401 Unauthorized.
+func (p *ProbeAuth) handler(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !p.allowed(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+			return
+		}
+
+		inner.ServeHTTP(w, r)
+	})
+}
+
+// middleware protects the health, the routes and the config endpoints, and the metrics route when it is served on the
+// HTTP port, the other routes are not affected.
+func (p *ProbeAuth) middleware(metricsRoute string) func(inner http.Handler) http.Handler {
+	protected := map[string]bool{pkg.PathHealthCheck: true, pkg.PathHeartBeat: true, pkg.PathReady: true,
+		pkg.PathRoutes: true, pkg.PathConfig: true, metricsRoute: true}
+
+	return func(inner http.Handler) http.Handler {
+		auth := p.handler(inner)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if protected[r.URL.Path] {
+				auth.ServeHTTP(w, r)
+				return
+			}
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
+// tlsConfig requests the client certificates, without requiring them, so that the clients without a certificate are
+// still served, and the certificates are verified by allowed against the client CAs.
+func (p *ProbeAuth) tlsConfig(cfg *tls.Config) *tls.Config {
+	if p == nil || p.ClientCAs == nil || cfg.ClientAuth != tls.NoClientCert {
+		return cfg
+	}
+
+	cfg = cfg.Clone()
+	cfg.ClientAuth = tls.RequestClientCert
+
+	return cfg
+}
@@ -0,0 +1,68 @@
+package gofr
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+func Test_getProbeAuth(t *testing.T) {
+	b := new(bytes.Buffer)
+
+	auth := getProbeAuth(&config.MockConfig{Data: map[string]string{"PROBE_AUTH_TOKEN": "secret",
+		"PROBE_ALLOWED_CIDRS": "10.0.0.0/8, invalid"}}, log.NewMockLogger(b))
+
+	_, network, _ := net.ParseCIDR("10.0.0.0/8")
+
+	assert.Equal(t, &ProbeAuth{Token: "secret", AllowedCIDRs: []*net.IPNet{network}}, auth)
+	assert.Contains(t, b.String(), "PROBE_ALLOWED_CIDRS has an invalid network invalid")
+
+	assert.Nil(t, getProbeAuth(&config.MockConfig{Data: map[string]string{}}, log.NewMockLogger(b)))
+}
+
+func TestProbeAuth_middleware(t *testing.T) {
+	_, network, _ := net.ParseCIDR("10.0.0.0/8")
+	auth := &ProbeAuth{Token: "secret", AllowedCIDRs: []*net.IPNet{network}}
+
+	handler := auth.middleware("/metrics")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		desc       string
+		path       string
+		auth       string
+		remoteAddr string
+		expected   int
+	}{
+		{"health without token", pkg.PathHealthCheck, "", "192.168.0.1:1234", http.StatusUnauthorized},
+		{"heartbeat with invalid token", pkg.PathHeartBeat, "Bearer invalid", "192.168.0.1:1234", http.StatusUnauthorized},
+		{"metrics with token", "/metrics", "Bearer secret", "192.168.0.1:1234", http.StatusOK},
+		{"ready from allowed network", pkg.PathReady, "", "10.1.2.3:1234", http.StatusOK},
+		{"config without token", pkg.PathConfig, "", "192.168.0.1:1234", http.StatusUnauthorized},
+		{"routes without token", pkg.PathRoutes, "", "192.168.0.1:1234", http.StatusUnauthorized},
+		{"other route without token", "/hello", "", "192.168.0.1:1234", http.StatusOK},
+	}
+
+	for i, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, http.NoBody)
+		req.RemoteAddr = tc.remoteAddr
+
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.expected, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
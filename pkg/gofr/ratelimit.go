@@ -0,0 +1,264 @@
+package gofr
+
+import (
+	ctx "context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/middleware"
+	"gofr.dev/pkg/middleware/oauth"
+)
+
+const (
+	defaultRateLimitAdminScope = "ratelimits:admin"
+	defaultRateLimitRedisKey   = "gofr:rate-limits"
+
+	errInvalidLimitTable = errors.Error("table of the rate limits is not a valid table name")
+)
+
+/*
+SQLLimitStore stores the limits of the tenants in a SQL table, ex: on MySQL
+
+	CREATE TABLE rate_limits (
+		tenant VARCHAR(255) PRIMARY KEY,
+		rate DOUBLE NOT NULL,
+		burst INT NOT NULL,
+		quota BIGINT NOT NULL
+	)
+*/
+type SQLLimitStore struct {
+	DB    *datastore.SQLClient
+	Table string
+	// Dialect is the dialect of the placeholders of the queries, ex: postgres. Default is mysql.
+	Dialect string
+}
+
+func (s SQLLimitStore) Limits(c ctx.Context) (map[string]middleware.TenantLimit, error) {
+	if !tableNameRegex.MatchString(s.Table) {
+		return nil, errInvalidLimitTable
+	}
+
+	//nolint:gosec // the table name is validated, and is not a user input
+	rows, err := s.DB.QueryContext(c, "SELECT tenant, rate, burst, quota FROM "+s.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	limits := make(map[string]middleware.TenantLimit)
+
+	for rows.Next() {
+		var (
+			tenant string
+			limit  middleware.TenantLimit
+		)
+
+		if err := rows.Scan(&tenant, &limit.Rate, &limit.Burst, &limit.Quota); err != nil {
+			return nil, err
+		}
+
+		limits[tenant] = limit
+	}
+
+	return limits, rows.Err()
+}
+
+// SetLimit replaces the limit of the tenant in a transaction, so that it works on the dialects without an upsert.
+func (s SQLLimitStore) SetLimit(c ctx.Context, tenant string, limit middleware.TenantLimit) error {
+	if !tableNameRegex.MatchString(s.Table) {
+		return errInvalidLimitTable
+	}
+
+	dialect := strings.ToLower(s.Dialect)
+
+	tx, err := s.DB.BeginTx(c, nil)
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec // the table name is validated, and is not a user input
+	if _, err = tx.ExecContext(c, "DELETE FROM "+s.Table+" WHERE tenant = "+placeholders(dialect, 1, 1), tenant); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	//nolint:gosec // the table name is validated, and is not a user input
+	query := "INSERT INTO " + s.Table + " (tenant, rate, burst, quota) VALUES (" + placeholders(dialect, 1, 4) + ")"
+
+	if _, err = tx.ExecContext(c, query, tenant, limit.Rate, limit.Burst, limit.Quota); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RedisLimitStore stores the limits of the tenants in a Redis hash, the fields are the tenants and the values are
+// the limits in JSON. Default Key is gofr:rate-limits.
+type RedisLimitStore struct {
+	Redis datastore.Redis
+	Key   string
+}
+
+func (s RedisLimitStore) Limits(c ctx.Context) (map[string]middleware.TenantLimit, error) {
+	values, err := s.Redis.HGetAll(c, s.key()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(map[string]middleware.TenantLimit, len(values))
+
+	for tenant, value := range values {
+		var limit middleware.TenantLimit
+
+		if err := json.Unmarshal([]byte(value), &limit); err != nil {
+			return nil, err
+		}
+
+		limits[tenant] = limit
+	}
+
+	return limits, nil
+}
+
+func (s RedisLimitStore) SetLimit(c ctx.Context, tenant string, limit middleware.TenantLimit) error {
+	value, err := json.Marshal(limit)
+	if err != nil {
+		return err
+	}
+
+	return s.Redis.HSet(c, s.key(), tenant, value).Err()
+}
+
+func (s RedisLimitStore) key() string {
+	if s.Key == "" {
+		return defaultRateLimitRedisKey
+	}
+
+	return s.Key
+}
+
+/*
+EnableRateLimits limits the requests of every tenant with the limits of the store. The limits of the tenants which
+are not in the store are RATE_LIMIT_RATE requests per second, with a burst of RATE_LIMIT_BURST, and RATE_LIMIT_QUOTA
+requests per RATE_LIMIT_QUOTA_PERIOD seconds, default is 24 hours, which are shared by these tenants. The limits are
+reloaded every RATE_LIMIT_RELOAD_INTERVAL seconds, default is 30.
+
+The tenant is the claim RATE_LIMIT_CLAIM of the verified JWT, default is tenant. The requests without the claim are
+limited by the value of the header RATE_LIMIT_HEADER, when it is set, which must be a credential of the tenant, ex:
+X-API-Key, as a client sending the value of another tenant gets its limit.
+
+The limits are listed by GET /.well-known/rate-limits, and the limit of a tenant is changed at runtime by
+PUT /.well-known/rate-limits/{tenant} with the limit in the body, ex: {"rate": 10, "burst": 20, "quota": 100000}. They
+are allowed to the users with the scope RATE_LIMIT_ADMIN_SCOPE, default is ratelimits:admin.
+*/
+func (g *Gofr) EnableRateLimits(store middleware.LimitStore) *middleware.RateLimiter {
+	c := g.Config
+
+	cfg := middleware.RateLimitConfig{
+		Store:          store,
+		Identity:       rateLimitTenant(c.GetOrDefault("RATE_LIMIT_CLAIM", "tenant"), c.Get("RATE_LIMIT_HEADER")),
+		ReloadInterval: seconds(c, "RATE_LIMIT_RELOAD_INTERVAL", 0),
+		QuotaPeriod:    seconds(c, "RATE_LIMIT_QUOTA_PERIOD", 0),
+		Logger:         g.Logger,
+	}
+
+	if rate, err := strconv.ParseFloat(c.Get("RATE_LIMIT_RATE"), 64); err == nil && rate > 0 {
+		cfg.Default.Rate = rate
+	}
+
+	cfg.Default.Burst = positiveInt(c, "RATE_LIMIT_BURST")
+
+	if quota, err := strconv.ParseInt(c.Get("RATE_LIMIT_QUOTA"), 10, 64); err == nil && quota > 0 {
+		cfg.Default.Quota = quota
+	}
+
+	limiter := middleware.NewRateLimiter(cfg)
+
+	if g.Server == nil {
+		return limiter
+	}
+
+	if err := limiter.Reload(ctx.Background()); err != nil {
+		g.Logger.Errorf("could not load the rate limits, the default limit is used till they are reloaded: %v", err)
+	}
+
+	reload, stop := ctx.WithCancel(ctx.Background())
+
+	go limiter.Run(reload)
+
+	g.OnShutdown(ShutdownIntake, "rate limits", func(ctx.Context) error { stop(); return nil })
+
+	g.Server.UseMiddleware(limiter.Handler)
+
+	authorize := func(handler Handler) Handler {
+		return func(c *Context) (interface{}, error) {
+			scope := defaultRateLimitAdminScope
+			if c.Config != nil && c.Config.Get("RATE_LIMIT_ADMIN_SCOPE") != "" {
+				scope = c.Config.Get("RATE_LIMIT_ADMIN_SCOPE")
+			}
+
+			return requireAuth(&RouteAuth{Required: true, Scopes: []string{scope}}, handler)(c)
+		}
+	}
+
+	g.Server.Router.Route(http.MethodGet, pkg.PathRateLimits, authorize(rateLimitsHandler(limiter)))
+	g.Server.Router.Route(http.MethodPut, pkg.PathRateLimit, authorize(setRateLimitHandler(limiter)))
+
+	return limiter
+}
+
+// rateLimitTenant returns the tenant of the request from the claim of its verified JWT, or else from the header.
+func rateLimitTenant(claim, header string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		if claims, ok := r.Context().Value(oauth.JWTContextKey("claims")).(jwt.MapClaims); ok {
+			if tenant, ok := claims[claim].(string); ok && tenant != "" {
+				return tenant
+			}
+		}
+
+		if header == "" {
+			return ""
+		}
+
+		return r.Header.Get(header)
+	}
+}
+
+// rateLimitsHandler lists the limits of the tenants.
+func rateLimitsHandler(limiter *middleware.RateLimiter) Handler {
+	return func(*Context) (interface{}, error) {
+		return limiter.Limits(), nil
+	}
+}
+
+// setRateLimitHandler changes the limit of the tenant of the path to the limit of the body.
+func setRateLimitHandler(limiter *middleware.RateLimiter) Handler {
+	return func(c *Context) (interface{}, error) {
+		var limit middleware.TenantLimit
+
+		if err := c.Bind(&limit); err != nil || limit.Rate < 0 || limit.Burst < 0 || limit.Quota < 0 {
+			return nil, errors.InvalidParam{Param: []string{"body"}}
+		}
+
+		tenant := c.PathParam("tenant")
+
+		if err := limiter.SetLimit(c, tenant, limit); err != nil {
+			return nil, err
+		}
+
+		c.Logger.Infof("rate limit of the tenant %v is changed to %+v by %v", tenant, limit, authenticatedSubject(c))
+
+		return limit, nil
+	}
+}
@@ -0,0 +1,166 @@
+package gofr
+
+import (
+	"bytes"
+	ctx "context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/datastore"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
+	"gofr.dev/pkg/middleware/oauth"
+)
+
+func TestSQLLimitStore(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	store := SQLLimitStore{DB: &datastore.SQLClient{DB: db}, Table: "rate_limits", Dialect: "Postgres"}
+
+	mock.ExpectQuery("SELECT tenant, rate, burst, quota FROM rate_limits").
+		WillReturnRows(sqlmock.NewRows([]string{"tenant", "rate", "burst", "quota"}).AddRow("acme", 10.0, 20, 1000))
+
+	limits, err := store.Limits(ctx.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]middleware.TenantLimit{"acme": {Rate: 10, Burst: 20, Quota: 1000}}, limits)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM rate_limits WHERE tenant = \\$1").WithArgs("acme").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO rate_limits \\(tenant, rate, burst, quota\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\)").
+		WithArgs("acme", 5.0, 0, int64(0)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, store.SetLimit(ctx.Background(), "acme", middleware.TenantLimit{Rate: 5}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	store.Table = "rate_limits; DROP TABLE orders"
+
+	_, err = store.Limits(ctx.Background())
+	assert.Equal(t, errInvalidLimitTable, err)
+	assert.Equal(t, errInvalidLimitTable, store.SetLimit(ctx.Background(), "acme", middleware.TenantLimit{}))
+}
+
+func TestRateLimitTenant(t *testing.T) {
+	tests := []struct {
+		desc   string
+		header string
+		claims jwt.MapClaims
+		tenant string
+	}{
+		{"claim of the JWT", "X-API-Key", jwt.MapClaims{"tenant": "acme"}, "acme"},
+		{"header without the claim", "X-API-Key", jwt.MapClaims{"sub": "alice"}, "key-1"},
+		{"header is not set", "", nil, ""},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+		r.Header.Set("X-API-Key", "key-1")
+
+		if tc.claims != nil {
+			r = r.WithContext(ctx.WithValue(r.Context(), oauth.JWTContextKey("claims"), tc.claims))
+		}
+
+		assert.Equal(t, tc.tenant, rateLimitTenant("tenant", tc.header)(r), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestSetRateLimitHandler(t *testing.T) {
+	limiter := middleware.NewRateLimiter(middleware.RateLimitConfig{})
+
+	tests := []struct {
+		desc string
+		body string
+		resp interface{}
+		err  error
+	}{
+		{"valid limit", `{"rate": 10, "burst": 20}`, middleware.TenantLimit{Rate: 10, Burst: 20}, nil},
+		{"negative rate", `{"rate": -1}`, nil, errors.InvalidParam{Param: []string{"body"}}},
+		{"invalid body", `{`, nil, errors.InvalidParam{Param: []string{"body"}}},
+	}
+
+	for i, tc := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/.well-known/rate-limits/acme", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		req = mux.SetURLVars(req, map[string]string{"tenant": "acme"})
+
+		resp, err := setRateLimitHandler(limiter)(NewContext(nil, request.NewHTTPRequest(req), &Gofr{}))
+
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.Equal(t, map[string]middleware.TenantLimit{"acme": {Rate: 10, Burst: 20}}, limiter.Limits())
+}
+
+func TestSetRateLimitHandler_Actor(t *testing.T) {
+	tests := []struct {
+		desc    string
+		trusted string
+		log     string
+	}{
+		{"user id sent by the client", "", "by \""},
+		{"user id sent by the trusted gateway", "true", "by admin"},
+	}
+
+	for i, tc := range tests {
+		b := new(bytes.Buffer)
+		g := &Gofr{Config: &config.MockConfig{Data: map[string]string{"TRUSTED_GATEWAY": tc.trusted}}}
+
+		req := httptest.NewRequest(http.MethodPut, "/.well-known/rate-limits/acme", strings.NewReader(`{"rate": 10}`))
+		req.Header.Set("Content-Type", "application/json")
+		req = mux.SetURLVars(req, map[string]string{"tenant": "acme"})
+		req = req.WithContext(ctx.WithValue(req.Context(), middleware.AuthenticatedUserIDKey, "admin"))
+
+		c := NewContext(nil, request.NewHTTPRequest(req), g)
+		c.Logger = log.NewMockLogger(b)
+
+		_, err := setRateLimitHandler(middleware.NewRateLimiter(middleware.RateLimitConfig{}))(c)
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Contains(t, b.String(), tc.log, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// staticLimitStore is a store of the limits which are not changed.
+type staticLimitStore map[string]middleware.TenantLimit
+
+func (s staticLimitStore) Limits(ctx.Context) (map[string]middleware.TenantLimit, error) {
+	return s, nil
+}
+
+func (s staticLimitStore) SetLimit(_ ctx.Context, tenant string, limit middleware.TenantLimit) error {
+	s[tenant] = limit
+	return nil
+}
+
+func TestEnableRateLimits_Prefix(t *testing.T) {
+	c := &config.MockConfig{Data: map[string]string{}}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(new(bytes.Buffer))}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Prefix("/api")
+
+	g.EnableRateLimits(staticLimitStore{})
+	g.GET("/orders", func(*Context) (interface{}, error) { return nil, nil })
+
+	routes := fmt.Sprint(g.Server.Router)
+
+	// the admin routes of the rate limits are well known, hence they are not served under the prefix
+	assert.Contains(t, routes, "GET /api/orders ")
+	assert.Contains(t, routes, "GET "+pkg.PathRateLimits+" ")
+	assert.Contains(t, routes, "PUT "+pkg.PathRateLimit+" ")
+	assert.NotContains(t, routes, "/api/.well-known")
+}
@@ -0,0 +1,176 @@
+package gofr
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+const (
+	// defaultDependencyWeight makes every dependency critical unless configured otherwise,
+	// so that readiness behaves like the health-check by default.
+	defaultDependencyWeight   = 1.0
+	defaultReadinessThreshold = 1.0
+	defaultReadinessInterval  = 10 * time.Second
+)
+
+type readinessReport struct {
+	status  string
+	ready   bool
+	down    []string
+	details healthDetails
+}
+
+// ReadinessHandler reports if the application can serve traffic. Each dependency has a weight, configured using
+// HEALTH_DEPENDENCY_WEIGHTS, and the application is not ready when the sum of the weights of the dependencies which
+// are down reaches READINESS_THRESHOLD. For ex: with HEALTH_DEPENDENCY_WEIGHTS=redis:0.5 the application is ready,
+// but DEGRADED, when only redis is down, whereas it is not ready when any other dependency is down.
+func ReadinessHandler(c *Context) (interface{}, error) {
+	report := c.Gofr.checkReadiness()
+
+	if !report.ready {
+		return nil, &errors.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Code:       "Service Unavailable",
+			Reason:     fmt.Sprintf("dependencies down: %v", strings.Join(report.down, ", ")),
+		}
+	}
+
+	return types.Raw{Data: map[string]interface{}{"details": report.details, "status": report.status}}, nil
+}
+
+func (g *Gofr) checkReadiness() readinessReport {
+	var (
+		report       readinessReport
+		downWeight   float64
+		optionalDown bool
+	)
+
+	check := func(h types.Health) {
+		if h.Status == pkg.StatusUp {
+			return
+		}
+
+		weight := g.dependencyWeight(h.Name)
+		if weight > 0 {
+			report.down = append(report.down, h.Name)
+		}
+
+		downWeight += weight
+	}
+
+	for _, v := range g.ServiceHealth {
+		h := v()
+		check(h)
+
+		report.details.Services = append(report.details.Services, h)
+	}
+
+	// the optional services do not affect the readiness, but are reported as down
+	for _, v := range g.OptionalServiceHealth {
+		h := v()
+		if h.Status != pkg.StatusUp {
+			optionalDown = true
+
+			report.down = append(report.down, h.Name)
+		}
+
+		report.details.Services = append(report.details.Services, h)
+	}
+
+	for _, v := range g.DatabaseHealth {
+		h := v()
+		check(h)
+
+		report.details.Databases = append(report.details.Databases, h)
+	}
+
+	report.details.App = getAppDetails(g.Config)
+
+	threshold := g.ReadinessThreshold
+	if threshold <= 0 {
+		threshold = defaultReadinessThreshold
+	}
+
+	sort.Strings(report.down)
+
+	report.ready = downWeight < threshold
+
+	switch {
+	case !report.ready:
+		report.status = pkg.StatusDown
+	case downWeight > 0 || optionalDown:
+		report.status = pkg.StatusDegraded
+	default:
+		report.status = pkg.StatusUp
+	}
+
+	return report
+}
+
+func (g *Gofr) dependencyWeight(name string) float64 {
+	if w, ok := g.DependencyWeights[strings.ToLower(name)]; ok {
+		return w
+	}
+
+	return defaultDependencyWeight
+}
+
+// gateGRPCTraffic periodically sets the serving status of the gRPC health service from the readiness of the
+// application, so that load balancers stop routing traffic to an instance whose critical dependencies are down.
+func (g *Gofr) gateGRPCTraffic(healthServer *health.Server, interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		interval = defaultReadinessInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status := healthpb.HealthCheckResponse_SERVING
+		if !g.checkReadiness().ready {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+
+		// empty service name denotes the overall health of the server
+		healthServer.SetServingStatus("", status)
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			healthServer.Shutdown()
+			return
+		}
+	}
+}
+
+// getDependencyWeights parses the weights in the format name:weight,name:weight
+func getDependencyWeights(c Config) map[string]float64 {
+	weights := make(map[string]float64)
+
+	for _, pair := range strings.Split(c.Get("HEALTH_DEPENDENCY_WEIGHTS"), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			continue
+		}
+
+		w, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || w < 0 {
+			continue
+		}
+
+		weights[strings.ToLower(strings.TrimSpace(name))] = w
+	}
+
+	return weights
+}
@@ -0,0 +1,164 @@
+package gofr
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/types"
+)
+
+func healthCheck(name, status string) HealthCheck {
+	return func() types.Health {
+		return types.Health{Name: name, Status: status}
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	weights := map[string]float64{"redis": 0.5, "elasticsearch": 0.5, "audit": 0}
+
+	tests := []struct {
+		desc      string
+		databases []HealthCheck
+		services  []HealthCheck
+		status    string
+		err       error
+	}{
+		{"all dependencies up", []HealthCheck{healthCheck("sql", pkg.StatusUp)}, nil, pkg.StatusUp, nil},
+		{"optional dependency down", []HealthCheck{healthCheck("sql", pkg.StatusUp), healthCheck("redis", pkg.StatusDown)},
+			nil, pkg.StatusDegraded, nil},
+		{"ignored dependency down", nil, []HealthCheck{healthCheck("audit", pkg.StatusDown)}, pkg.StatusUp, nil},
+		{"critical dependency down", []HealthCheck{healthCheck("sql", pkg.StatusDown), healthCheck("redis", pkg.StatusUp)},
+			nil, "", &errors.Response{StatusCode: http.StatusServiceUnavailable, Code: "Service Unavailable",
+				Reason: "dependencies down: sql"}},
+		{"optional dependencies reach threshold", []HealthCheck{healthCheck("redis", pkg.StatusDown),
+			healthCheck("elasticsearch", pkg.StatusDown)}, []HealthCheck{healthCheck("audit", pkg.StatusDown)},
+			"", &errors.Response{StatusCode: http.StatusServiceUnavailable, Code: "Service Unavailable",
+				Reason: "dependencies down: elasticsearch, redis"}},
+	}
+
+	for i, tc := range tests {
+		g := &Gofr{
+			Config:            &config.MockConfig{},
+			DatabaseHealth:    tc.databases,
+			ServiceHealth:     tc.services,
+			DependencyWeights: weights,
+		}
+
+		resp, err := ReadinessHandler(NewContext(nil, nil, g))
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if err != nil {
+			continue
+		}
+
+		data, _ := resp.(types.Raw).Data.(map[string]interface{})
+
+		assert.Equal(t, tc.status, data["status"], "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestReadiness_Threshold(t *testing.T) {
+	g := &Gofr{
+		Config:             &config.MockConfig{},
+		DatabaseHealth:     []HealthCheck{healthCheck("sql", pkg.StatusDown)},
+		ReadinessThreshold: 2,
+	}
+
+	report := g.checkReadiness()
+
+	assert.True(t, report.ready)
+	assert.Equal(t, pkg.StatusDegraded, report.status)
+}
+
+func Test_getDependencyWeights(t *testing.T) {
+	c := &config.MockConfig{Data: map[string]string{
+		"HEALTH_DEPENDENCY_WEIGHTS": "Redis:0.5, sql:2,invalid,kafka:abc,mongo:-1",
+	}}
+
+	assert.Equal(t, map[string]float64{"redis": 0.5, "sql": 2}, getDependencyWeights(c))
+}
+
+func TestGofr_gateGRPCTraffic(t *testing.T) {
+	var status atomic.Value
+
+	status.Store(pkg.StatusDown)
+
+	g := &Gofr{Config: &config.MockConfig{}, DatabaseHealth: []HealthCheck{func() types.Health {
+		return types.Health{Name: "sql", Status: status.Load().(string)}
+	}}}
+	healthServer := health.NewServer()
+	stop := make(chan struct{})
+
+	go g.gateGRPCTraffic(healthServer, 10*time.Millisecond, stop)
+
+	time.Sleep(50 * time.Millisecond)
+
+	resp, err := healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	status.Store(pkg.StatusUp)
+
+	time.Sleep(50 * time.Millisecond)
+
+	resp, _ = healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	close(stop)
+}
+
+type mockService struct {
+	health   types.Health
+	critical bool
+}
+
+func (m mockService) HealthCheck() types.Health {
+	return m.health
+}
+
+func (m mockService) IsCritical() bool {
+	return m.critical
+}
+
+func TestGofr_AddServices(t *testing.T) {
+	tests := []struct {
+		desc     string
+		services []ServiceHealthChecker
+		ready    bool
+		status   string
+		down     []string
+	}{
+		{"all services up", []ServiceHealthChecker{mockService{types.Health{Name: "orders", Status: pkg.StatusUp}, true},
+			mockService{types.Health{Name: "reviews", Status: pkg.StatusUp}, false}}, true, pkg.StatusUp, nil},
+		{"optional service down", []ServiceHealthChecker{mockService{types.Health{Name: "orders", Status: pkg.StatusUp}, true},
+			mockService{types.Health{Name: "reviews", Status: pkg.StatusDown}, false}}, true, pkg.StatusDegraded, []string{"reviews"}},
+		{"critical service down", []ServiceHealthChecker{mockService{types.Health{Name: "orders", Status: pkg.StatusDown}, true},
+			mockService{types.Health{Name: "reviews", Status: pkg.StatusUp}, false}}, false, pkg.StatusDown, []string{"orders"}},
+	}
+
+	for i, tc := range tests {
+		g := &Gofr{Config: &config.MockConfig{}}
+
+		g.AddServices(tc.services...)
+
+		report := g.checkReadiness()
+
+		assert.Equal(t, tc.ready, report.ready, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.status, report.status, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.down, report.down, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Len(t, report.details.Services, 2, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
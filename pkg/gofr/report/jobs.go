@@ -0,0 +1,258 @@
+package report
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/middleware"
+)
+
+const defaultRetention = time.Hour
+
+// IdempotencyKeyHeader is the header of which the value is the key of the report, a repeated request with the key
+// is responded with the report of the first request, instead of generating the report again.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+//nolint:gochecknoglobals // duplicateJobs has to be a global variable for prometheus
+var (
+	duplicateJobs = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "zs_report_duplicate_jobs",
+		Help: "Counter for the report jobs which were suppressed, as a job with their key was already submitted",
+	})
+
+	_ = prometheus.Register(duplicateJobs)
+)
+
+// Status denotes the state of a report which is generated asynchronously.
+type Status string
+
+const (
+	Pending   Status = "PENDING"
+	Completed Status = "COMPLETED"
+	Failed    Status = "FAILED"
+)
+
+// Generate generates a report, ex: using PDF or XLSX.
+type Generate func(ctx context.Context) (types.FileDownload, error)
+
+// Job is a report which is generated asynchronously.
+type Job struct {
+	ID          string     `json:"id"`
+	Status      Status     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+
+	file types.FileDownload
+	key  string
+	done chan struct{}
+}
+
+// Jobs generates the reports in the background, so that the reports which take longer than the timeout of a
+// request can be downloaded once they are generated. The reports are held in memory for the retention period
+// after they are generated.
+type Jobs struct {
+	// Timeout is the maximum duration of generating a report, there is no limit when it is 0.
+	Timeout time.Duration
+	// Retention is the duration for which a generated report can be downloaded. Default is 1 hour.
+	Retention time.Duration
+
+	mu   sync.RWMutex
+	jobs map[string]*Job
+	// keys maps the idempotency keys to the ids of their jobs
+	keys map[string]string
+}
+
+// Submit starts generating a report in the background, and returns the job of the report. The values of the
+// context are passed on to generate, but the report is not cancelled when the context is done.
+func (j *Jobs) Submit(ctx context.Context, generate Generate) Job {
+	return j.SubmitWithKey(ctx, "", generate)
+}
+
+// SubmitWithKey submits the report like Submit, unless a job with the key is pending, or is completed and not expired.
+// Then the job is returned instead, so that the report is generated once, even when the client retries the request.
+// A failed job does not hold its key, so that the retries generate the report again.
+func (j *Jobs) SubmitWithKey(ctx context.Context, key string, generate Generate) Job {
+	j.mu.Lock()
+
+	if j.jobs == nil {
+		j.jobs = make(map[string]*Job)
+		j.keys = make(map[string]string)
+	}
+
+	j.expire()
+
+	// the key is claimed with the lock held, so that the concurrent requests with the key start one job
+	if existing, ok := j.jobs[j.keys[key]]; ok && key != "" && existing.Status != Failed {
+		duplicate := *existing
+
+		j.mu.Unlock()
+		duplicateJobs.Inc()
+
+		return duplicate
+	}
+
+	job := &Job{ID: uuid.NewString(), Status: Pending, CreatedAt: time.Now(), key: key, done: make(chan struct{})}
+
+	j.jobs[job.ID] = job
+	submitted := *job
+
+	if key != "" {
+		j.keys[key] = job.ID
+	}
+
+	j.mu.Unlock()
+
+	go j.run(context.WithoutCancel(ctx), job, generate)
+
+	return submitted
+}
+
+func (j *Jobs) run(ctx context.Context, job *Job, generate Generate) {
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+
+	file, err := generate(ctx)
+	now := time.Now()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	defer close(job.done)
+
+	job.CompletedAt = &now
+
+	if err != nil {
+		job.Status = Failed
+		job.Error = err.Error()
+
+		return
+	}
+
+	job.Status = Completed
+	job.file = file
+}
+
+// expire removes the reports whose retention period is over, it is called with the lock held.
+func (j *Jobs) expire() {
+	retention := j.Retention
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+
+	for id, job := range j.jobs {
+		if job.CompletedAt != nil && time.Since(*job.CompletedAt) > retention {
+			delete(j.jobs, id)
+
+			if job.key != "" && j.keys[job.key] == id {
+				delete(j.keys, job.key)
+			}
+		}
+	}
+}
+
+// Get returns the job with the id, the second value is false when the job does not exist or has expired.
+func (j *Jobs) Get(id string) (Job, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.expire()
+
+	job, ok := j.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+
+	return *job, true
+}
+
+// Generate generates the report, and returns it when it is generated within wait. Otherwise, the job is returned
+// so that the client can poll its status, and download the report once it is completed. The requests of the
+// authenticated users with the header Idempotency-Key are submitted with the key, scoped by the tenant, the user
+// and the route, so that a retried request does not generate the report again.
+func (j *Jobs) Generate(c *gofr.Context, wait time.Duration, generate Generate) (interface{}, error) {
+	// the pooled context is reused by another request once the handler returns, hence only its context is passed on
+	job := j.SubmitWithKey(c.Context, idempotencyKey(c), generate)
+
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+
+	select {
+	case <-deadline.C:
+		return job, nil
+	case <-job.done:
+		completed, ok := j.Get(job.ID)
+		if !ok {
+			return nil, errors.EntityNotFound{Entity: "report", ID: job.ID}
+		}
+
+		return j.result(completed)
+	}
+}
+
+// idempotencyKey scopes the Idempotency-Key of the request, so that the clients sending the same key do not get the
+// reports of each other. The key of an unauthenticated request is ignored, as its client cannot be told apart.
+func idempotencyKey(c *gofr.Context) string {
+	key := c.Header(IdempotencyKeyHeader)
+	subject := c.Subject()
+
+	if key == "" || subject == "" {
+		return ""
+	}
+
+	tenant, _ := c.Context.Value(middleware.TenantIDKey).(string)
+	r := c.Request()
+
+	return strings.Join([]string{tenant, subject, r.Method, r.URL.Path, key}, "\x00")
+}
+
+// StatusHandler responds with the job whose id is the path parameter id, ex: GET /reports/{id}
+func (j *Jobs) StatusHandler(c *gofr.Context) (interface{}, error) {
+	id := c.PathParam("id")
+
+	job, ok := j.Get(id)
+	if !ok {
+		return nil, errors.EntityNotFound{Entity: "report", ID: id}
+	}
+
+	return job, nil
+}
+
+// DownloadHandler responds with the report of the job whose id is the path parameter id, ex:
+// GET /reports/{id}/download. A conflict is responded while the report is being generated.
+func (j *Jobs) DownloadHandler(c *gofr.Context) (interface{}, error) {
+	id := c.PathParam("id")
+
+	job, ok := j.Get(id)
+	if !ok {
+		return nil, errors.EntityNotFound{Entity: "report", ID: id}
+	}
+
+	if job.Status == Pending {
+		return nil, &errors.Response{StatusCode: http.StatusConflict, Code: "REPORT_PENDING",
+			Reason: "report " + id + " is being generated"}
+	}
+
+	return j.result(job)
+}
+
+func (j *Jobs) result(job Job) (interface{}, error) {
+	if job.Status == Failed {
+		return nil, &errors.Response{StatusCode: http.StatusInternalServerError, Code: "REPORT_FAILED",
+			Reason: "report " + job.ID + " could not be generated: " + job.Error}
+	}
+
+	return job.file, nil
+}
@@ -0,0 +1,222 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	gofrErrors "gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr"
+	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/middleware/oauth"
+)
+
+func newContext(id string) *gofr.Context {
+	r := httptest.NewRequest(http.MethodGet, "/reports/"+id, http.NoBody)
+	r = mux.SetURLVars(r, map[string]string{"id": id})
+
+	c := gofr.NewContext(nil, request.NewHTTPRequest(r), &gofr.Gofr{})
+	c.Context = context.Background()
+
+	return c
+}
+
+func TestJobs_Generate(t *testing.T) {
+	release := make(chan struct{})
+	report := types.FileDownload{Content: []byte("id,item"), FileName: "orders.csv"}
+
+	tests := []struct {
+		desc     string
+		generate Generate
+		resp     interface{}
+		err      error
+	}{
+		{"generated within wait", func(context.Context) (types.FileDownload, error) { return report, nil }, report, nil},
+		{"generation failed", func(context.Context) (types.FileDownload, error) { return types.FileDownload{}, errors.New("db down") },
+			nil, &gofrErrors.Response{StatusCode: http.StatusInternalServerError, Code: "REPORT_FAILED"}},
+	}
+
+	jobs := &Jobs{}
+
+	for i, tc := range tests {
+		resp, err := jobs.Generate(newContext(""), time.Second, tc.generate)
+
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err != nil {
+			e, _ := err.(*gofrErrors.Response)
+
+			assert.Equal(t, http.StatusInternalServerError, e.StatusCode, "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Contains(t, e.Reason, "db down", "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+
+	// the job is returned when the report is not generated within wait
+	resp, err := jobs.Generate(newContext(""), 10*time.Millisecond, func(context.Context) (types.FileDownload, error) {
+		<-release
+		return report, nil
+	})
+
+	assert.NoError(t, err)
+
+	job, ok := resp.(Job)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	assert.Equal(t, Pending, job.Status)
+
+	_, err = jobs.DownloadHandler(newContext(job.ID))
+
+	assert.Equal(t, http.StatusConflict, err.(*gofrErrors.Response).StatusCode)
+
+	close(release)
+	<-job.done
+
+	status, _ := jobs.StatusHandler(newContext(job.ID))
+
+	assert.Equal(t, Completed, status.(Job).Status)
+
+	resp, err = jobs.DownloadHandler(newContext(job.ID))
+
+	assert.NoError(t, err)
+	assert.Equal(t, report, resp)
+}
+
+func TestJobs_NotFound(t *testing.T) {
+	jobs := &Jobs{}
+	expErr := gofrErrors.EntityNotFound{Entity: "report", ID: "unknown"}
+
+	_, err := jobs.StatusHandler(newContext("unknown"))
+
+	assert.Equal(t, expErr, err)
+
+	_, err = jobs.DownloadHandler(newContext("unknown"))
+
+	assert.Equal(t, expErr, err)
+}
+
+func TestJobs_Expire(t *testing.T) {
+	jobs := &Jobs{Retention: time.Millisecond}
+
+	job := jobs.Submit(context.Background(), func(context.Context) (types.FileDownload, error) {
+		return types.FileDownload{}, nil
+	})
+
+	<-job.done
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := jobs.Get(job.ID)
+
+	assert.False(t, ok, "report is not removed after the retention period")
+}
+
+func TestJobs_Timeout(t *testing.T) {
+	jobs := &Jobs{Timeout: 10 * time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	job := jobs.Submit(ctx, func(ctx context.Context) (types.FileDownload, error) {
+		<-ctx.Done()
+		return types.FileDownload{}, ctx.Err()
+	})
+
+	// the report is not cancelled along with the request
+	cancel()
+	<-job.done
+
+	job, _ = jobs.Get(job.ID)
+
+	assert.Equal(t, Failed, job.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), job.Error)
+}
+
+func TestJobs_SubmitWithKey(t *testing.T) {
+	var generated int
+
+	jobs := &Jobs{}
+	before := testutil.ToFloat64(duplicateJobs)
+	generate := func(context.Context) (types.FileDownload, error) {
+		generated++
+
+		if generated == 1 {
+			return types.FileDownload{}, errors.New("db down")
+		}
+
+		return types.FileDownload{Content: []byte("id,item")}, nil
+	}
+
+	failed := jobs.SubmitWithKey(context.Background(), "orders-2024", generate)
+	<-failed.done
+
+	// the failed job does not hold the key, so that the report is generated again
+	job := jobs.SubmitWithKey(context.Background(), "orders-2024", generate)
+	<-job.done
+
+	duplicate := jobs.SubmitWithKey(context.Background(), "orders-2024", generate)
+	other := jobs.SubmitWithKey(context.Background(), "orders-2025", generate)
+	<-other.done
+
+	assert.NotEqual(t, failed.ID, job.ID)
+	assert.Equal(t, job.ID, duplicate.ID, "job with the key must be returned")
+	assert.Equal(t, Completed, duplicate.Status)
+	assert.NotEqual(t, job.ID, other.ID)
+	assert.Equal(t, 3, generated)
+	assert.Equal(t, before+1, testutil.ToFloat64(duplicateJobs))
+}
+
+func TestJobs_Generate_IdempotencyKey(t *testing.T) {
+	var generated int
+
+	jobs := &Jobs{}
+	generate := func(context.Context) (types.FileDownload, error) {
+		generated++
+		return types.FileDownload{Content: []byte("id,item")}, nil
+	}
+
+	// the retried request of alice is deduplicated, the requests of bob and of an unauthenticated client are not
+	for i, user := range []string{"alice", "alice", "bob", ""} {
+		resp, err := jobs.Generate(keyedContext(user, "orders-2024"), time.Second, generate)
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, "request with idempotency key")
+		assert.Equal(t, types.FileDownload{Content: []byte("id,item")}, resp, "TEST[%d], failed.\n%s", i,
+			"request with idempotency key")
+	}
+
+	assert.Equal(t, 3, generated, "report must be generated once for the retried request of a user")
+}
+
+func keyedContext(user, key string) *gofr.Context {
+	r := httptest.NewRequest(http.MethodPost, "/reports", http.NoBody)
+	r.Header.Set(IdempotencyKeyHeader, key)
+
+	if user != "" {
+		r = r.WithContext(context.WithValue(r.Context(), oauth.JWTContextKey("claims"), jwt.MapClaims{"sub": user}))
+	}
+
+	c := gofr.NewContext(nil, request.NewHTTPRequest(r), &gofr.Gofr{})
+	c.Context = r.Context()
+
+	return c
+}
+
+func TestJobs_Expire_Key(t *testing.T) {
+	jobs := &Jobs{Retention: time.Millisecond}
+	generate := func(context.Context) (types.FileDownload, error) { return types.FileDownload{}, nil }
+
+	job := jobs.SubmitWithKey(context.Background(), "orders-2024", generate)
+	<-job.done
+	time.Sleep(5 * time.Millisecond)
+
+	assert.NotEqual(t, job.ID, jobs.SubmitWithKey(context.Background(), "orders-2024", generate).ID,
+		"key is not released after the retention period")
+}
@@ -0,0 +1,64 @@
+// Package report provides the generation of PDF and XLSX reports, which are returned as a types.FileDownload,
+// and the asynchronous generation of the reports which take longer than a request should.
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"gofr.dev/pkg/gofr/template"
+	"gofr.dev/pkg/gofr/types"
+)
+
+const (
+	pdfContentType  = "application/pdf"
+	xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Renderer converts an HTML document to a PDF document. It is implemented by CommandRenderer, and can be
+// implemented using a headless browser or a rendering service.
+type Renderer interface {
+	Render(ctx context.Context, html []byte) ([]byte, error)
+}
+
+// CommandRenderer renders the PDF using an external command, which reads the HTML from its standard input and
+// writes the PDF to its standard output, ex: wkhtmltopdf --quiet - -
+type CommandRenderer struct {
+	Command string
+	Args    []string
+}
+
+// Render runs the command with the HTML as its input, and returns the output of the command.
+func (r CommandRenderer) Render(ctx context.Context, html []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	//nolint:gosec // the command is configured by the application, not by the request
+	cmd := exec.CommandContext(ctx, r.Command, r.Args...)
+	cmd.Stdin = bytes.NewReader(html)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("rendering pdf using %v failed: %w: %v", r.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// PDF renders the HTML template, and converts it to a PDF using the renderer.
+func PDF(ctx context.Context, renderer Renderer, tmpl *template.Template, fileName string) (types.FileDownload, error) {
+	html, err := tmpl.Render()
+	if err != nil {
+		return types.FileDownload{}, err
+	}
+
+	content, err := renderer.Render(ctx, html)
+	if err != nil {
+		return types.FileDownload{}, err
+	}
+
+	return types.FileDownload{Content: content, ContentType: pdfContentType, FileName: fileName}, nil
+}
@@ -0,0 +1,36 @@
+package report
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/template"
+)
+
+func TestPDF(t *testing.T) {
+	dir := t.TempDir()
+
+	_ = os.WriteFile(filepath.Join(dir, "invoice.html"), []byte(`<h1>{{.}}</h1>`), 0o600)
+
+	tmpl := &template.Template{Directory: dir, File: "invoice.html", Data: "Invoice 42"}
+
+	// cat renders the html as it is, which verifies that the rendered template is passed to the renderer
+	file, err := PDF(context.Background(), CommandRenderer{Command: "cat"}, tmpl, "invoice.pdf")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<h1>Invoice 42</h1>", string(file.Content))
+	assert.Equal(t, "application/pdf", file.ContentType)
+	assert.Equal(t, "invoice.pdf", file.FileName)
+
+	_, err = PDF(context.Background(), CommandRenderer{Command: "false"}, tmpl, "invoice.pdf")
+
+	assert.Error(t, err, "failure of the renderer is not returned")
+
+	_, err = PDF(context.Background(), CommandRenderer{Command: "cat"}, &template.Template{Directory: dir, File: "missing.html"}, "")
+
+	assert.Error(t, err, "missing template is not returned")
+}
@@ -0,0 +1,190 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gofr.dev/pkg/gofr/types"
+)
+
+const maxSheetName = 31
+
+// Sheet is a worksheet of an XLSX report. The cells are written as numbers when they are numeric, as booleans
+// when they are bool and as text otherwise, time.Time is written in RFC 3339 format.
+type Sheet struct {
+	Name string
+	Rows [][]interface{}
+}
+
+// part is a file of the XLSX package
+type part struct {
+	name  string
+	write func(w io.Writer)
+}
+
+// XLSX returns the sheets as an XLSX workbook, the workbook has a sheet named Sheet1 when no sheets are given.
+func XLSX(fileName string, sheets ...Sheet) (types.FileDownload, error) {
+	if len(sheets) == 0 {
+		sheets = []Sheet{{Name: "Sheet1"}}
+	}
+
+	if err := validateSheets(sheets); err != nil {
+		return types.FileDownload{}, err
+	}
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	parts := []part{
+		{"[Content_Types].xml", func(w io.Writer) { writeContentTypes(w, len(sheets)) }},
+		{"_rels/.rels", writeRootRels},
+		{"xl/workbook.xml", func(w io.Writer) { writeWorkbook(w, sheets) }},
+		{"xl/_rels/workbook.xml.rels", func(w io.Writer) { writeWorkbookRels(w, len(sheets)) }},
+	}
+
+	for i := range sheets {
+		sheet := sheets[i]
+		parts = append(parts, part{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), func(w io.Writer) { writeSheet(w, sheet) }})
+	}
+
+	for _, p := range parts {
+		w, err := zw.Create(p.name)
+		if err != nil {
+			return types.FileDownload{}, err
+		}
+
+		p.write(w)
+	}
+
+	if err := zw.Close(); err != nil {
+		return types.FileDownload{}, err
+	}
+
+	return types.FileDownload{Content: buf.Bytes(), ContentType: xlsxContentType, FileName: fileName}, nil
+}
+
+func validateSheets(sheets []Sheet) error {
+	names := make(map[string]bool, len(sheets))
+
+	for _, s := range sheets {
+		if s.Name == "" || len(s.Name) > maxSheetName || strings.ContainsAny(s.Name, `[]:*?/\`) {
+			return fmt.Errorf("invalid sheet name %q", s.Name)
+		}
+
+		if names[strings.ToLower(s.Name)] {
+			return fmt.Errorf("duplicate sheet name %q", s.Name)
+		}
+
+		names[strings.ToLower(s.Name)] = true
+	}
+
+	return nil
+}
+
+func writeContentTypes(w io.Writer, sheets int) {
+	_, _ = io.WriteString(w, xml.Header+
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`+
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`+
+		`<Default Extension="xml" ContentType="application/xml"/>`+
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(w, `<Override PartName="/xl/worksheets/sheet%d.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+
+	_, _ = io.WriteString(w, `</Types>`)
+}
+
+func writeRootRels(w io.Writer) {
+	_, _ = io.WriteString(w, xml.Header+
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" `+
+		`Target="xl/workbook.xml"/></Relationships>`)
+}
+
+func writeWorkbook(w io.Writer, sheets []Sheet) {
+	_, _ = io.WriteString(w, xml.Header+
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+
+	for i, s := range sheets {
+		fmt.Fprintf(w, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(s.Name), i+1, i+1)
+	}
+
+	_, _ = io.WriteString(w, `</sheets></workbook>`)
+}
+
+func writeWorkbookRels(w io.Writer, sheets int) {
+	_, _ = io.WriteString(w, xml.Header+`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(w, `<Relationship Id="rId%d" `+
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" `+
+			`Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+
+	_, _ = io.WriteString(w, `</Relationships>`)
+}
+
+func writeSheet(w io.Writer, s Sheet) {
+	_, _ = io.WriteString(w, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for r, row := range s.Rows {
+		fmt.Fprintf(w, `<row r="%d">`, r+1)
+
+		for c, value := range row {
+			if value != nil {
+				writeCell(w, column(c)+strconv.Itoa(r+1), value)
+			}
+		}
+
+		_, _ = io.WriteString(w, `</row>`)
+	}
+
+	_, _ = io.WriteString(w, `</sheetData></worksheet>`)
+}
+
+func writeCell(w io.Writer, ref string, value interface{}) {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		fmt.Fprintf(w, `<c r="%s"><v>%v</v></c>`, ref, v)
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+
+		fmt.Fprintf(w, `<c r="%s" t="b"><v>%d</v></c>`, ref, b)
+	case time.Time:
+		fmt.Fprintf(w, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, v.Format(time.RFC3339))
+	default:
+		fmt.Fprintf(w, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escape(fmt.Sprint(v)))
+	}
+}
+
+// column returns the name of the column at the zero based index, ex: A for 0, Z for 25 and AA for 26
+func column(i int) string {
+	name := ""
+
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+
+	return name
+}
+
+func escape(s string) string {
+	var b strings.Builder
+
+	_ = xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}
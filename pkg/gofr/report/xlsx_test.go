@@ -0,0 +1,82 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXLSX(t *testing.T) {
+	created := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	file, err := XLSX("orders.xlsx", Sheet{Name: "Orders", Rows: [][]interface{}{
+		{"id", "item", "paid", "created"},
+		{1, "pen & <ink>", true, created},
+		{2.5, nil, false},
+	}}, Sheet{Name: "Summary"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "orders.xlsx", file.FileName)
+	assert.Equal(t, xlsxContentType, file.ContentType)
+
+	zr, err := zip.NewReader(bytes.NewReader(file.Content), int64(len(file.Content)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	parts := make(map[string]string)
+
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		b, _ := io.ReadAll(rc)
+		_ = rc.Close()
+
+		parts[f.Name] = string(b)
+	}
+
+	assert.Len(t, parts, 6)
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Summary" sheetId="2" r:id="rId2"/>`)
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+
+	assert.Contains(t, sheet, `<c r="A2"><v>1</v></c>`)
+	assert.Contains(t, sheet, `<c r="B2" t="inlineStr"><is><t xml:space="preserve">pen &amp; &lt;ink&gt;</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="C2" t="b"><v>1</v></c>`)
+	assert.Contains(t, sheet, `<c r="D2" t="inlineStr"><is><t>2023-05-01T10:00:00Z</t></is></c>`)
+	assert.NotContains(t, sheet, `r="B3"`, "nil cells are written")
+}
+
+func TestXLSX_InvalidSheet(t *testing.T) {
+	tests := []struct {
+		desc   string
+		sheets []Sheet
+	}{
+		{"empty name", []Sheet{{}}},
+		{"long name", []Sheet{{Name: "a sheet name longer than 31 characters"}}},
+		{"invalid character", []Sheet{{Name: "2023/05"}}},
+		{"duplicate name", []Sheet{{Name: "Orders"}, {Name: "orders"}}},
+	}
+
+	for i, tc := range tests {
+		_, err := XLSX("orders.xlsx", tc.sheets...)
+
+		assert.Error(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestColumn(t *testing.T) {
+	tests := []struct {
+		index int
+		name  string
+	}{
+		{0, "A"}, {25, "Z"}, {26, "AA"}, {51, "AZ"}, {52, "BA"}, {701, "ZZ"}, {702, "AAA"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.name, column(tc.index), "TEST[%d], failed.\n%d", i, tc.index)
+	}
+}
@@ -0,0 +1,43 @@
+package request
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const cborContentType = "application/cbor"
+
+// isMsgpack checks the content type for the registered MessagePack type, along with the unregistered
+// types used by the older clients.
+func isMsgpack(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/msgpack") || strings.HasPrefix(contentType, "application/x-msgpack") ||
+		strings.HasPrefix(contentType, "application/vnd.msgpack")
+}
+
+// decodeMsgpack decodes the MessagePack body into i. The fields are matched by their json tags, so that
+// the same type can be bound from JSON and MessagePack requests.
+func decodeMsgpack(body []byte, i interface{}, strict bool) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(body))
+	dec.SetCustomStructTag("json")
+	dec.DisallowUnknownFields(strict)
+
+	return dec.Decode(i)
+}
+
+// decodeCBOR decodes the CBOR body into i, the fields are matched by their cbor tags, falling back to the json tags.
+func decodeCBOR(body []byte, i interface{}, strict bool) error {
+	opts := cbor.DecOptions{}
+	if strict {
+		opts.ExtraReturnErrors = cbor.ExtraDecErrorUnknownField
+	}
+
+	dm, err := opts.DecMode()
+	if err != nil {
+		return err
+	}
+
+	return dm.Unmarshal(body, i)
+}
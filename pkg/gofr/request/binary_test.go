@@ -0,0 +1,78 @@
+package request
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type device struct {
+	ID      string  `json:"id"`
+	Reading float64 `json:"reading"`
+}
+
+func encodeMsgpack(t *testing.T, v interface{}) []byte {
+	var buf bytes.Buffer
+
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+
+	if err := enc.Encode(v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestHTTP_BindBinary(t *testing.T) {
+	cborBody, _ := cbor.Marshal(map[string]interface{}{"id": "d1", "reading": 21.5})
+	cborUnknown, _ := cbor.Marshal(map[string]interface{}{"id": "d1", "unit": "C"})
+
+	tests := []struct {
+		desc        string
+		contentType string
+		body        []byte
+		strict      bool
+		expErr      bool
+	}{
+		{"msgpack", "application/msgpack", encodeMsgpack(t, map[string]interface{}{"id": "d1", "reading": 21.5}), false, false},
+		{"unregistered msgpack type", "application/x-msgpack", encodeMsgpack(t, device{ID: "d1", Reading: 21.5}), false, false},
+		{"cbor", "application/cbor", cborBody, false, false},
+		{"strict msgpack", "application/msgpack", encodeMsgpack(t, device{ID: "d1", Reading: 21.5}), true, false},
+		{"strict msgpack with unknown field", "application/msgpack",
+			encodeMsgpack(t, map[string]interface{}{"id": "d1", "unit": "C"}), true, true},
+		{"strict cbor with unknown field", "application/cbor", cborUnknown, true, true},
+		{"invalid cbor", "application/cbor", []byte{0xff}, false, true},
+	}
+
+	for i, tc := range tests {
+		req := httptest.NewRequest(http.MethodPost, urlHTTPDummy, bytes.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+
+		h := HTTP{req: req}
+
+		var (
+			d   device
+			err error
+		)
+
+		if tc.strict {
+			err = h.BindStrict(&d)
+		} else {
+			err = h.Bind(&d)
+		}
+
+		if tc.expErr {
+			assert.Error(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+			continue
+		}
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, device{ID: "d1", Reading: 21.5}, d, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
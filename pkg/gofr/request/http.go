@@ -34,6 +34,12 @@ func NewHTTPRequest(r *http.Request) Request {
 	}
 }
 
+// Reset reinitializes h with the request r, so that an HTTP value can be reused across requests.
+func (h *HTTP) Reset(r *http.Request) {
+	h.req = r
+	h.pathParams = nil
+}
+
 // Request returns the underlying HTTP request
 func (h *HTTP) Request() *http.Request {
 	return h.req
@@ -88,7 +94,6 @@ func (h *HTTP) Params() map[string]string {
 // PathParam returns the route values for the given key, if any
 func (h *HTTP) PathParam(key string) string {
 	if h.pathParams == nil {
-		h.pathParams = make(map[string]string)
 		h.pathParams = mux.Vars(h.req)
 	}
 
@@ -115,6 +120,8 @@ func (h *HTTP) Header(key string) string {
 // Bind checks the Content-Type to select a binding encoding automatically.
 // Depending on the "Content-Type" header different bindings are used:
 // - XML binding is used in case of: "application/xml" or "text/xml"
+// - MessagePack binding is used in case of: "application/msgpack"
+// - CBOR binding is used in case of: "application/cbor"
 // - JSON binding is used by default
 // It decodes the json payload into the type specified as a pointer.
 // It returns an error if the decoding fails.
@@ -129,6 +136,10 @@ func (h *HTTP) Bind(i interface{}) error {
 	switch {
 	case strings.HasPrefix(cType, "text/xml"), strings.HasPrefix(cType, "application/xml"):
 		return xml.Unmarshal(body, &i)
+	case isMsgpack(cType):
+		return decodeMsgpack(body, i, false)
+	case strings.HasPrefix(cType, cborContentType):
+		return decodeCBOR(body, i, false)
 	case strings.HasPrefix(cType, "multipart/form-data"):
 		if err := h.req.ParseMultipartForm(0); err != nil {
 			return err
@@ -150,6 +161,7 @@ func (h *HTTP) Bind(i interface{}) error {
 // BindStrict checks the "Content-Type" header to select a binding encoding automatically.
 // Depending on the "Content-Type" header, different bindings are used:
 // - XML binding is used in case of "application/xml" or "text/xml" content type.
+// - MessagePack and CBOR bindings are used in case of "application/msgpack" and "application/cbor" content types.
 // - JSON binding is used by default.
 // It decodes the JSON or XML payload into the type specified as a pointer.
 // It returns an error if the decoding fails, and it disallows unknown fields
@@ -161,9 +173,13 @@ func (h *HTTP) BindStrict(i interface{}) error {
 	}
 
 	cType := h.req.Header.Get("Content-type")
-	switch cType {
-	case "text/xml", "application/xml":
+	switch {
+	case cType == "text/xml", cType == "application/xml":
 		return xml.Unmarshal(body, &i)
+	case isMsgpack(cType):
+		return decodeMsgpack(body, i, true)
+	case strings.HasPrefix(cType, cborContentType):
+		return decodeCBOR(body, i, true)
 	default:
 		dec := json.NewDecoder(h.req.Body)
 		dec.DisallowUnknownFields()
@@ -26,6 +26,22 @@ func TestNewHTTPRequest(*testing.T) {
 	NewHTTPRequest(httptest.NewRequest("GET", urlHTTPDummy, http.NoBody))
 }
 
+func TestHTTP_Reset(t *testing.T) {
+	h := &HTTP{}
+
+	r1 := mux.SetURLVars(httptest.NewRequest(http.MethodGet, urlHTTPDummy+"/1", http.NoBody), map[string]string{"id": "1"})
+	h.Reset(r1)
+
+	assert.Equal(t, "1", h.PathParam("id"))
+
+	// path params of the previous request must not be retained on reuse
+	r2 := mux.SetURLVars(httptest.NewRequest(http.MethodGet, urlHTTPDummy+"/2", http.NoBody), map[string]string{"id": "2"})
+	h.Reset(r2)
+
+	assert.Equal(t, "2", h.PathParam("id"))
+	assert.Equal(t, r2, h.Request())
+}
+
 func TestHTTP_String(t *testing.T) {
 	var (
 		h      HTTP
@@ -0,0 +1,53 @@
+package responder
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// binaryResponseType returns the binary response type acceptable to the client. JSON, XML and text responses
+// are not negotiated using the Accept header, they are decided by the Content-Type of the request.
+func binaryResponseType(accept string) (responseType, bool) {
+	if accept == "" {
+		return JSON, false
+	}
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(mediaRange, ";")
+
+		if !acceptable(params) {
+			continue
+		}
+
+		switch resType := getResponseContentType(strings.TrimSpace(mediaType), JSON); resType {
+		case MSGPACK, CBOR:
+			return resType, true
+		}
+	}
+
+	return JSON, false
+}
+
+// acceptable checks the quality parameter of a media range, a quality of 0 denotes that it is not acceptable.
+func acceptable(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+			v, err := strconv.ParseFloat(q, 64)
+			return err == nil && v > 0
+		}
+	}
+
+	return true
+}
+
+// encodeMsgpack encodes the response using the json tags of the fields, so that the keys of the
+// MessagePack response are same as the keys of the JSON response.
+func encodeMsgpack(buf *bytes.Buffer, response interface{}) error {
+	enc := msgpack.NewEncoder(buf)
+	enc.SetCustomStructTag("json")
+
+	return enc.Encode(response)
+}
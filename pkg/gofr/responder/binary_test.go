@@ -0,0 +1,81 @@
+package responder
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+)
+
+func Test_binaryResponseType(t *testing.T) {
+	tests := []struct {
+		desc    string
+		accept  string
+		resType responseType
+		ok      bool
+	}{
+		{"no accept header", "", JSON, false},
+		{"json", "application/json", JSON, false},
+		{"msgpack", "application/msgpack", MSGPACK, true},
+		{"cbor with other types", "text/html, application/cbor;q=0.9, */*;q=0.8", CBOR, true},
+		{"not acceptable", "application/msgpack;q=0, application/json", JSON, false},
+	}
+
+	for i, tc := range tests {
+		resType, ok := binaryResponseType(tc.accept)
+
+		assert.Equal(t, tc.resType, resType, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.ok, ok, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestHTTP_RespondBinary(t *testing.T) {
+	tests := []struct {
+		desc        string
+		accept      string
+		contentType string
+		decode      func([]byte, interface{}) error
+	}{
+		{"msgpack negotiated using accept", "application/msgpack", "application/msgpack", msgpack.Unmarshal},
+		{"cbor negotiated using accept", "application/cbor", "application/cbor", cbor.Unmarshal},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/devices/d1", http.NoBody)
+		r.Header.Set("Accept", tc.accept)
+
+		NewContextualResponder(w, r).Respond(&types.Response{Data: map[string]interface{}{"id": "d1"}}, nil)
+
+		var body map[string]map[string]interface{}
+
+		assert.NoError(t, tc.decode(w.Body.Bytes(), &body), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "d1", body["data"]["id"], "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestHTTP_RespondBinaryError(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/devices/d1", http.NoBody)
+	r.Header.Set("Content-Type", "application/cbor")
+
+	err := errors.MultipleErrors{StatusCode: http.StatusNotFound, Errors: []error{&errors.Response{Code: "Entity Not Found",
+		Reason: "device not found"}}}
+
+	NewContextualResponder(w, r).Respond(&types.Response{}, err)
+
+	var body struct {
+		Errors []errors.Response `json:"errors"`
+	}
+
+	assert.NoError(t, cbor.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "device not found", body.Errors[0].Reason)
+}
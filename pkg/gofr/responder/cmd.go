@@ -4,8 +4,10 @@ package responder
 
 import (
 	"fmt"
+	"os"
 
 	"gofr.dev/pkg/gofr/template"
+	"gofr.dev/pkg/gofr/types"
 	"gofr.dev/pkg/log"
 )
 
@@ -23,7 +25,8 @@ type CMD struct {
 // Respond logs errors and prints responses based on the data and error provided.
 // If an error is not nil, it logs the error using the provided logger and also prints it to the standard output.
 // If the data is of type template.Template, it renders and prints the template content.
-// If the data is of type template.File, it prints the content of the file.
+// If the data is of type template.File or types.FileDownload, it prints the content of the file.
+// If the data is of type types.Stream, it writes the stream to the standard output.
 // For other data types, it prints the data to the standard output.
 func (c *CMD) Respond(data interface{}, err error) {
 	// added the logger to log the error in case of CMD application
@@ -57,5 +60,20 @@ func (c *CMD) Respond(data interface{}, err error) {
 		return
 	}
 
+	if f, ok := data.(types.FileDownload); ok {
+		fmt.Println(string(f.Content))
+
+		return
+	}
+
+	if s, ok := data.(types.Stream); ok {
+		if err = s.Write(os.Stdout); err != nil {
+			c.Logger.Error(err)
+			fmt.Println(err)
+		}
+
+		return
+	}
+
 	fmt.Println(data)
 }
@@ -0,0 +1,387 @@
+package responder
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	// DefaultMaxDepth is a depth of the nested objects and arrays which is deep enough for the responses of most of the
+	// APIs, ex: to be set as the MaxDepth of the limits.
+	DefaultMaxDepth = 64
+
+	// truncatedHeader is set on the responses which are truncated to their limits.
+	truncatedHeader = "X-Response-Truncated"
+
+	guardCycle = "cycle"
+	guardDepth = "depth"
+	guardSize  = "size"
+)
+
+// SerializationLimits caps the responses, so that a handler returning an unbounded structure, ex: an ORM entity whose
+// relations refer back to it, fails cleanly instead of exhausting the memory while it is serialized.
+type SerializationLimits struct {
+	// MaxDepth is the depth of the nested objects and arrays of a response, the depth is not limited when it is zero.
+	// A reference to an object being serialized, ie: a cycle, is never serialized.
+	MaxDepth int
+	// MaxBytes is the size of a serialized response, the size is not limited when it is zero.
+	MaxBytes int
+	// Truncate serializes the objects and arrays beyond the depth, and the cycles, as null, instead of responding with
+	// an error, the truncated responses have the X-Response-Truncated header. The cycles are truncated even when the
+	// depth is not limited. The responses beyond the size, and the XML responses, can not be truncated, hence they are
+	// always responded with an error.
+	Truncate bool
+}
+
+//nolint:gochecknoglobals // the limits are shared by the responders, and the metrics have to be global for prometheus
+var (
+	serializationLimits atomic.Value
+
+	responseGuarded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_http_response_guarded_total",
+		Help: "Counter of the responses which exceeded the serialization limits, by the limit exceeded",
+	}, []string{"reason", "truncated"})
+
+	_ = prometheus.Register(responseGuarded)
+)
+
+//nolint:gochecknoinits // the default limits have to be set before the first response
+func init() {
+	serializationLimits.Store(SerializationLimits{})
+}
+
+// SetSerializationLimits sets the limits of the responses. The responses are not walked when neither the depth is
+// limited nor truncated, then a cycle fails the JSON responses, while the encoders of the other types recurse into it,
+// hence MaxDepth has to be set for the handlers whose responses can have cycles.
+func SetSerializationLimits(l SerializationLimits) {
+	serializationLimits.Store(l)
+}
+
+func limits() SerializationLimits {
+	l, _ := serializationLimits.Load().(SerializationLimits)
+
+	return l
+}
+
+// guardError is the error of a response which exceeds a limit.
+type guardError struct {
+	reason string
+	path   string
+	limit  int
+}
+
+func (e *guardError) Error() string {
+	switch e.reason {
+	case guardCycle:
+		return fmt.Sprintf("response refers to itself at %v", e.path)
+	case guardDepth:
+		return fmt.Sprintf("response exceeds the depth of %v at %v", e.limit, e.path)
+	default:
+		return fmt.Sprintf("response exceeds the size of %v bytes", e.limit)
+	}
+}
+
+// responseError returns the error responded instead of a response which exceeds a limit, or can not be encoded.
+func responseError(err error) errors.MultipleErrors {
+	return errors.MultipleErrors{StatusCode: http.StatusInternalServerError, Errors: []error{&errors.Response{
+		StatusCode: http.StatusInternalServerError, Code: "Response Not Serializable", Reason: err.Error()}}}
+}
+
+// guard checks the response against the depth limit, and returns the response to serialize, which is the truncated
+// response when the limits allow truncation, along with whether it is truncated.
+func guard(l SerializationLimits, response interface{}, canTruncate bool) (guarded interface{}, truncated bool,
+	err *guardError) {
+	if l.MaxDepth <= 0 && !l.Truncate || response == nil {
+		return response, false, nil
+	}
+
+	w := &walker{maxDepth: l.MaxDepth}
+
+	if err = w.check(reflect.ValueOf(response), 0, "$"); err == nil {
+		return response, false, nil
+	}
+
+	if !l.Truncate || !canTruncate {
+		responseGuarded.WithLabelValues(err.reason, "false").Inc()
+		return nil, false, err
+	}
+
+	responseGuarded.WithLabelValues(err.reason, "true").Inc()
+
+	return w.prune(reflect.ValueOf(response), 0), true, nil
+}
+
+// walker walks a response the way encoding/json serializes it, keeping the references on the path being walked, so
+// that a cycle is found the first time an object is repeated.
+type walker struct {
+	maxDepth int
+	path     []uintptr
+}
+
+// tooDeep returns whether the depth is beyond the depth limit, the depth is not limited when it is zero.
+func (w *walker) tooDeep(depth int) bool {
+	return w.maxDepth > 0 && depth > w.maxDepth
+}
+
+//nolint:gochecknoglobals // the types are compared to, they are used as constants
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// isLeaf reports whether the value serializes itself, ex: time.Time, hence it is not walked.
+func isLeaf(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+func (w *walker) enter(ref uintptr) bool {
+	for _, p := range w.path {
+		if p == ref {
+			return false
+		}
+	}
+
+	w.path = append(w.path, ref)
+
+	return true
+}
+
+func (w *walker) leave() {
+	w.path = w.path[:len(w.path)-1]
+}
+
+// reference returns the reference of a value which can refer back to an object being walked.
+func reference(v reflect.Value) (uintptr, bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map:
+		return v.Pointer(), true
+	case reflect.Slice:
+		return v.Pointer(), v.Len() > 0
+	default:
+		return 0, false
+	}
+}
+
+//nolint:exhaustive // the other kinds are serialized as they are
+func (w *walker) check(v reflect.Value, depth int, path string) *guardError {
+	if !v.IsValid() || isNilable(v) && v.IsNil() || isLeaf(v.Type()) {
+		return nil
+	}
+
+	if ref, ok := reference(v); ok {
+		if !w.enter(ref) {
+			return &guardError{reason: guardCycle, path: path}
+		}
+
+		defer w.leave()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return w.check(v.Elem(), depth, path)
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+
+		if depth++; w.tooDeep(depth) {
+			return &guardError{reason: guardDepth, path: path, limit: w.maxDepth}
+		}
+
+		var err *guardError
+
+		w.children(v, func(key string, child reflect.Value) bool {
+			err = w.check(child, depth, path+key)
+			return err == nil
+		})
+
+		return err
+	}
+
+	return nil
+}
+
+// prune returns the value as the objects and arrays which encoding/json serializes it as, with the cycles, and the
+// objects and arrays beyond the depth, as nil.
+//
+//nolint:exhaustive // the other kinds are serialized as they are
+func (w *walker) prune(v reflect.Value, depth int) interface{} {
+	if !v.IsValid() || isNilable(v) && v.IsNil() {
+		return nil
+	}
+
+	// the fields of the unexported embedded structs can not be read, as encoding/json reads them
+	if !v.CanInterface() {
+		return nil
+	}
+
+	if isLeaf(v.Type()) || v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+		return v.Interface()
+	}
+
+	if ref, ok := reference(v); ok {
+		if !w.enter(ref) {
+			return nil
+		}
+
+		defer w.leave()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return w.prune(v.Elem(), depth)
+	case reflect.Struct, reflect.Map:
+		if depth++; w.tooDeep(depth) {
+			return nil
+		}
+
+		object := make(map[string]interface{})
+
+		w.children(v, func(key string, child reflect.Value) bool {
+			object[strings.TrimPrefix(key, ".")] = w.prune(child, depth)
+			return true
+		})
+
+		return object
+	case reflect.Slice, reflect.Array:
+		if depth++; w.tooDeep(depth) {
+			return nil
+		}
+
+		array := make([]interface{}, 0, v.Len())
+
+		w.children(v, func(_ string, child reflect.Value) bool {
+			array = append(array, w.prune(child, depth))
+			return true
+		})
+
+		return array
+	}
+
+	return v.Interface()
+}
+
+// children calls fn with the serialized children of an object or an array, along with their keys, ex: .name or [0],
+// till fn returns false.
+//
+//nolint:exhaustive // only the objects and the arrays have children
+func (w *walker) children(v reflect.Value, fn func(key string, child reflect.Value) bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		fields(v, fn)
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if !fn("."+mapKey(iter.Key()), iter.Value()) {
+				return
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !fn("["+strconv.Itoa(i)+"]", v.Index(i)) {
+				return
+			}
+		}
+	}
+}
+
+// fields calls fn with the fields of the struct which encoding/json serializes, the fields of the embedded structs are
+// the fields of the struct.
+func fields(v reflect.Value, fn func(key string, child reflect.Value) bool) bool {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		field := v.Field(i)
+
+		if f.Anonymous && name == "" {
+			embedded := field
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				if !fields(embedded, fn) {
+					return false
+				}
+
+				continue
+			}
+		}
+
+		if !f.IsExported() || strings.Contains(opts, "omitempty") && isEmpty(field) {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		if !fn("."+name, field) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mapKey returns the key of a map as encoding/json serializes it.
+func mapKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+
+	if m, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+
+	return fmt.Sprint(k.Interface())
+}
+
+func isNilable(v reflect.Value) bool {
+	switch v.Kind() { //nolint:exhaustive // the other kinds can not be nil
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return true
+	default:
+		return false
+	}
+}
+
+// isEmpty reports whether the value is omitted by omitempty.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() { //nolint:exhaustive // the other kinds are never empty
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return v.IsZero()
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
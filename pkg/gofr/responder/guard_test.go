@@ -0,0 +1,102 @@
+package responder
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type order struct {
+	ID      string     `json:"id"`
+	Items   []*item    `json:"items"`
+	Created time.Time  `json:"created"`
+	Note    string     `json:"note,omitempty"`
+	Secret  string     `json:"-"`
+	Parent  *order     `json:"parent,omitempty"`
+	Shipped *time.Time `json:"shipped,omitempty"`
+}
+
+type item struct {
+	SKU   string `json:"sku"`
+	Order *order `json:"order"`
+}
+
+// nested returns the map nested to the depth.
+func nested(depth int) map[string]interface{} {
+	m := map[string]interface{}{}
+
+	for i := 1; i < depth; i++ {
+		m = map[string]interface{}{"child": m}
+	}
+
+	return m
+}
+
+func cyclicOrder() *order {
+	o := &order{ID: "A-1", Created: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Secret: "s"}
+	o.Items = []*item{{SKU: "tea", Order: o}}
+
+	return o
+}
+
+func TestHTTP_RespondGuarded(t *testing.T) {
+	shared := &item{SKU: "tea"}
+
+	tests := []struct {
+		desc      string
+		limits    SerializationLimits
+		resType   responseType
+		data      interface{}
+		status    int
+		body      string
+		truncated bool
+	}{
+		{"within the limits", SerializationLimits{MaxDepth: 3}, JSON, nested(3), http.StatusOK,
+			`{"child":{"child":{}}}`, false},
+		{"object repeated without a cycle", SerializationLimits{MaxDepth: 3}, JSON, []*item{shared, shared},
+			http.StatusOK, `[{"sku":"tea","order":null},{"sku":"tea","order":null}]`, false},
+		{"beyond the depth", SerializationLimits{MaxDepth: 3}, JSON, nested(4), http.StatusInternalServerError,
+			"response exceeds the depth of 3 at $.child.child.child", false},
+		{"cycle", SerializationLimits{MaxDepth: DefaultMaxDepth}, JSON, cyclicOrder(), http.StatusInternalServerError,
+			"response refers to itself at $.items[0].order", false},
+		{"cycle in msgpack", SerializationLimits{MaxDepth: DefaultMaxDepth}, MSGPACK, cyclicOrder(),
+			http.StatusInternalServerError, "response refers to itself at $.items[0].order", false},
+		{"truncated cycle", SerializationLimits{MaxDepth: DefaultMaxDepth, Truncate: true}, JSON, cyclicOrder(),
+			http.StatusOK, `{"created":"2024-01-02T00:00:00Z","id":"A-1","items":[{"order":null,"sku":"tea"}]}`, true},
+		{"truncated depth", SerializationLimits{MaxDepth: 2, Truncate: true}, JSON, nested(4), http.StatusOK,
+			`{"child":{"child":null}}`, true},
+		{"cycle not truncated in XML", SerializationLimits{MaxDepth: DefaultMaxDepth, Truncate: true}, XML,
+			cyclicOrder(), http.StatusInternalServerError, "response refers to itself", false},
+		{"beyond the size", SerializationLimits{MaxDepth: DefaultMaxDepth, MaxBytes: 10}, JSON,
+			map[string]string{"name": strings.Repeat("a", 10)}, http.StatusInternalServerError,
+			"response exceeds the size of 10 bytes", false},
+		{"limits disabled", SerializationLimits{}, JSON, nested(100), http.StatusOK,
+			strings.Repeat(`{"child":`, 99) + "{}" + strings.Repeat("}", 99), false},
+		{"cycle without limits", SerializationLimits{}, JSON, cyclicOrder(), http.StatusInternalServerError,
+			"encountered a cycle", false},
+		{"msgpack without limits", SerializationLimits{}, MSGPACK, nested(100), http.StatusOK, "child", false},
+		{"truncated cycle without a depth", SerializationLimits{Truncate: true}, JSON, cyclicOrder(), http.StatusOK,
+			`{"created":"2024-01-02T00:00:00Z","id":"A-1","items":[{"order":null,"sku":"tea"}]}`, true},
+		{"deep response truncated without a depth", SerializationLimits{Truncate: true}, JSON, nested(100), http.StatusOK,
+			strings.Repeat(`{"child":`, 99) + "{}" + strings.Repeat("}", 99), false},
+	}
+
+	defer SetSerializationLimits(SerializationLimits{})
+
+	for i, tc := range tests {
+		SetSerializationLimits(tc.limits)
+
+		w := httptest.NewRecorder()
+		h := HTTP{w: w, method: http.MethodGet, resType: tc.resType}
+
+		h.Respond(tc.data, nil)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Contains(t, w.Body.String(), tc.body, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.truncated, w.Header().Get(truncatedHeader) == "true", "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -8,9 +8,12 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -26,6 +29,8 @@ const (
 	JSON responseType = iota
 	XML
 	TEXT
+	MSGPACK
+	CBOR
 )
 
 type HTTP struct {
@@ -34,14 +39,23 @@ type HTTP struct {
 	w             http.ResponseWriter
 	resType       responseType
 	correlationID string
+	ifNoneMatch   string
 }
 
 // NewContextualResponder creates an HTTP responder which gives JSON/XML response based on context
 func NewContextualResponder(w http.ResponseWriter, r *http.Request) Responder {
-	route := mux.CurrentRoute(r)
+	responder := &HTTP{}
+	responder.Reset(w, r)
 
+	return responder
+}
+
+// Reset reinitializes h for the request r, so that an HTTP value can be reused across requests
+// instead of allocating a new responder for every request.
+func (h *HTTP) Reset(w http.ResponseWriter, r *http.Request) {
 	var path string
-	if route != nil {
+
+	if route := mux.CurrentRoute(r); route != nil {
 		path, _ = route.GetPathTemplate()
 		// remove the trailing slash
 		path = strings.TrimSuffix(path, "/")
@@ -49,29 +63,24 @@ func NewContextualResponder(w http.ResponseWriter, r *http.Request) Responder {
 
 	var correlationID string
 
-	val := r.Context().Value(middleware.CorrelationIDKey)
-	if val != nil {
-		correlationID = val.(string)
+	if val, ok := r.Context().Value(middleware.CorrelationIDKey).(string); ok {
+		correlationID = val
 	}
 
-	responder := &HTTP{
-		w:             w,
-		method:        r.Method,
-		path:          path,
-		correlationID: correlationID,
-	}
+	h.w = w
+	h.method = r.Method
+	h.path = path
+	h.correlationID = correlationID
+	h.ifNoneMatch = r.Header.Get("If-None-Match")
 
-	cType := r.Header.Get("Content-type")
-	switch cType {
-	case "text/xml", "application/xml":
-		responder.resType = XML
-	case "text/plain":
-		responder.resType = TEXT
-	default:
-		responder.resType = JSON
+	// binary formats are negotiated using the Accept header, as the clients which send JSON requests,
+	// or requests without a body, can still prefer a compact response
+	if resType, ok := binaryResponseType(r.Header.Get("Accept")); ok {
+		h.resType = resType
+		return
 	}
 
-	return responder
+	h.resType = getResponseContentType(r.Header.Get("Content-type"), JSON)
 }
 
 // Respond generates an HTTP response based on the provided data and error.
@@ -98,12 +107,48 @@ func (h HTTP) Respond(data interface{}, err error) {
 	}
 
 	if f, ok := data.(template.File); ok {
+		setHeaders(f.Header, h.w)
 		h.w.Header().Set("Content-Type", f.ContentType)
+
+		if etag := h.w.Header().Get("ETag"); etag != "" && etagMatches(h.ifNoneMatch, etag) {
+			h.w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
 		_, _ = h.w.Write(f.Content)
 
 		return
 	}
 
+	// the operations are responded with the status URL, which the client polls until the operation completes
+	if op, ok := data.(*types.Operation); ok && err == nil {
+		h.w.Header().Set("Location", op.StatusURL)
+		h.processResponse(http.StatusAccepted, &types.Response{Data: op})
+
+		return
+	}
+
+	if f, ok := data.(types.FileDownload); ok {
+		if err == nil {
+			h.download(f)
+			return
+		}
+
+		// the error is responded instead of the partially generated file
+		data = &types.Response{}
+	}
+
+	if s, ok := data.(types.Stream); ok {
+		if err == nil {
+			if err = h.stream(s); err == nil {
+				return
+			}
+		}
+
+		// the error is responded, as nothing of the stream is written
+		data = &types.Response{}
+	}
+
 	var (
 		response   interface{}
 		statusCode int
@@ -132,6 +177,130 @@ func (h HTTP) Respond(data interface{}, err error) {
 	h.processResponse(statusCode, response)
 }
 
+// download writes the file as an attachment, so that the clients save it with its file name.
+func (h HTTP) download(f types.FileDownload) {
+	contentType := f.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	h.w.Header().Set("Content-Type", contentType)
+	h.w.Header().Set("Content-Length", strconv.Itoa(len(f.Content)))
+
+	disposition := "attachment"
+
+	if f.FileName != "" {
+		// FormatMediaType encodes the names which are not ASCII, and returns empty when the name is invalid
+		if d := mime.FormatMediaType(disposition, map[string]string{"filename": f.FileName}); d != "" {
+			disposition = d
+		}
+	}
+
+	h.w.Header().Set("Content-Disposition", disposition)
+	h.w.WriteHeader(http.StatusOK)
+	_, _ = h.w.Write(f.Content)
+}
+
+// stream writes the stream as it is generated, flushing it to the client. The error of the stream is returned when
+// nothing is written, else the response is aborted, as the status and the headers are already sent to the client.
+func (h HTTP) stream(s types.Stream) error {
+	contentType := s.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	sw := &streamWriter{ResponseWriter: h.w, contentType: contentType}
+
+	if s.FileName != "" {
+		sw.disposition = "attachment"
+
+		if d := mime.FormatMediaType(sw.disposition, map[string]string{"filename": s.FileName}); d != "" {
+			sw.disposition = d
+		}
+	}
+
+	if err := s.Write(sw); err != nil {
+		if !sw.wroteHeader {
+			return streamError(err)
+		}
+
+		panic(http.ErrAbortHandler)
+	}
+
+	sw.Flush()
+
+	return nil
+}
+
+// streamError converts the error of a stream to the errors which are responded by Respond
+func streamError(err error) error {
+	if e, ok := err.(errors.MultipleErrors); ok {
+		return e
+	}
+
+	e, ok := err.(*errors.Response)
+	if !ok {
+		e = &errors.Response{Code: "Internal Server Error", Reason: err.Error()}
+	}
+
+	if e.StatusCode == 0 {
+		e.StatusCode = http.StatusInternalServerError
+	}
+
+	return errors.MultipleErrors{StatusCode: e.StatusCode, Errors: []error{e}}
+}
+
+// streamWriter writes the headers of the stream with its first write, so that the errors which occur before it
+// can still be responded.
+type streamWriter struct {
+	http.ResponseWriter
+	contentType string
+	disposition string
+	wroteHeader bool
+}
+
+func (w *streamWriter) writeHeader() {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+
+	w.Header().Set("Content-Type", w.contentType)
+
+	if w.disposition != "" {
+		w.Header().Set("Content-Disposition", w.disposition)
+	}
+
+	w.ResponseWriter.WriteHeader(http.StatusOK)
+}
+
+func (w *streamWriter) Write(b []byte) (int, error) {
+	w.writeHeader()
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *streamWriter) Flush() {
+	w.writeHeader()
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// etagMatches reports whether the If-None-Match header matches the etag, so that the client can use its cached copy.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
 // setHeaders will set the value of header.
 // If the header given is content-type or x-correlation-id it will not set that
 func setHeaders(headers map[string]string, w http.ResponseWriter) {
@@ -149,31 +318,82 @@ func setHeaders(headers map[string]string, w http.ResponseWriter) {
 	}
 }
 
+// processResponse encodes the response before writing the status, so that the responses which exceed the
+// serialization limits are responded with an error instead.
 func (h HTTP) processResponse(statusCode int, response interface{}) {
+	var (
+		contentType string
+		encode      func(buf *bytes.Buffer, response interface{}) error
+	)
+
 	switch h.resType {
 	case JSON:
-		h.w.Header().Set("Content-type", "application/json")
-		h.w.WriteHeader(statusCode)
-
-		if response != nil {
-			_ = json.NewEncoder(h.w).Encode(response)
+		contentType, encode = jsonContentType, func(buf *bytes.Buffer, response interface{}) error {
+			return json.NewEncoder(buf).Encode(response)
 		}
-
 	case XML:
-		h.w.Header().Set("Content-type", "application/xml")
-		h.w.WriteHeader(statusCode)
-
-		if response != nil {
-			_ = xml.NewEncoder(h.w).Encode(response)
+		contentType, encode = xmlContentType, func(buf *bytes.Buffer, response interface{}) error {
+			return xml.NewEncoder(buf).Encode(response)
 		}
 	case TEXT:
-		h.w.Header().Set("Content-type", "text/plain")
+		h.w.Header().Set(contentTypeHeader, textContentType)
 		h.w.WriteHeader(statusCode)
 
 		if response != nil {
 			_, _ = fmt.Fprintf(h.w, "%s", response)
 		}
+
+		return
+	case MSGPACK:
+		contentType, encode = msgpackContentType, encodeMsgpack
+	case CBOR:
+		contentType, encode = cborContentType, func(buf *bytes.Buffer, response interface{}) error {
+			return cbor.NewEncoder(buf).Encode(response)
+		}
+	default:
+		return
+	}
+
+	h.w.Header().Set(contentTypeHeader, contentType)
+
+	if response == nil {
+		h.w.WriteHeader(statusCode)
+		return
 	}
+
+	l := limits()
+
+	// XML is encoded using the fields of the structs, hence the truncated response can not be encoded as XML
+	guarded, truncated, err := guard(l, response, h.resType != XML)
+	if err != nil {
+		statusCode, guarded = http.StatusInternalServerError, responseError(err)
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if encodeErr := encode(buf, guarded); encodeErr != nil {
+		buf.Reset()
+
+		statusCode = http.StatusInternalServerError
+		_ = encode(buf, responseError(encodeErr))
+	}
+
+	if l.MaxBytes > 0 && buf.Len() > l.MaxBytes {
+		responseGuarded.WithLabelValues(guardSize, "false").Inc()
+
+		buf.Reset()
+
+		statusCode, truncated = http.StatusInternalServerError, false
+		_ = encode(buf, responseError(&guardError{reason: guardSize, limit: l.MaxBytes}))
+	}
+
+	if truncated {
+		h.w.Header().Set(truncatedHeader, "true")
+	}
+
+	h.w.WriteHeader(statusCode)
+	_, _ = h.w.Write(buf.Bytes())
 }
 
 func getStatusCode(method string, data interface{}, err error) int {
@@ -289,6 +509,10 @@ func getResponseContentType(ctype string, defaults responseType) responseType {
 		return TEXT
 	case "application/json":
 		return JSON
+	case "application/msgpack", "application/x-msgpack", "application/vnd.msgpack":
+		return MSGPACK
+	case "application/cbor":
+		return CBOR
 	default:
 		return defaults
 	}
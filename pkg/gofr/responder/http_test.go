@@ -3,6 +3,7 @@ package responder
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -175,6 +176,131 @@ func deleteDefaultTemplate() {
 	}
 }
 
+func TestHTTP_Respond_FileDownload(t *testing.T) {
+	tests := []struct {
+		desc        string
+		file        types.FileDownload
+		err         error
+		statusCode  int
+		contentType string
+		disposition string
+	}{
+		{"file with name", types.FileDownload{Content: []byte("%PDF"), ContentType: "application/pdf", FileName: "invoice 42.pdf"},
+			nil, http.StatusOK, "application/pdf", `attachment; filename="invoice 42.pdf"`},
+		{"file without name", types.FileDownload{Content: []byte("data")},
+			nil, http.StatusOK, "application/octet-stream", "attachment"},
+		{"error", types.FileDownload{Content: []byte("data"), FileName: "orders.csv"},
+			gofrErrors.MultipleErrors{StatusCode: http.StatusInternalServerError, Errors: []error{&gofrErrors.Response{Reason: "db down"}}},
+			http.StatusInternalServerError, "application/json", ""},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		h := HTTP{w: w, resType: JSON}
+
+		h.Respond(tc.file, tc.err)
+
+		assert.Equal(t, tc.statusCode, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.disposition, w.Header().Get("Content-Disposition"), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err == nil {
+			assert.Equal(t, tc.file.Content, w.Body.Bytes(), "TEST[%d], failed.\n%s", i, tc.desc)
+		} else {
+			assert.Contains(t, w.Body.String(), "db down", "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestHTTP_Respond_Stream(t *testing.T) {
+	errQuery := gofrErrors.Error("db down")
+
+	tests := []struct {
+		desc        string
+		stream      types.Stream
+		statusCode  int
+		contentType string
+		disposition string
+		body        string
+	}{
+		{"stream with name", types.Stream{ContentType: "text/csv", FileName: "orders.csv", Write: func(w io.Writer) error {
+			_, err := io.WriteString(w, "id\n1\n")
+			return err
+		}}, http.StatusOK, "text/csv", "attachment; filename=orders.csv", "id\n1\n"},
+		{"stream without name", types.Stream{Write: func(io.Writer) error { return nil }},
+			http.StatusOK, "application/octet-stream", "", ""},
+		{"error before the stream is written", types.Stream{ContentType: "text/csv", Write: func(io.Writer) error {
+			return errQuery
+		}}, http.StatusInternalServerError, "application/json", "", "db down"},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		h := HTTP{w: w, resType: JSON}
+
+		h.Respond(tc.stream, nil)
+
+		assert.Equal(t, tc.statusCode, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.disposition, w.Header().Get("Content-Disposition"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Contains(t, w.Body.String(), tc.body, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.True(t, w.Flushed || tc.statusCode != http.StatusOK, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestHTTP_Respond_StreamAborted(t *testing.T) {
+	h := HTTP{w: httptest.NewRecorder(), resType: JSON}
+
+	s := types.Stream{Write: func(w io.Writer) error {
+		_, _ = io.WriteString(w, "id\n")
+		return gofrErrors.Error("connection reset")
+	}}
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { h.Respond(s, nil) },
+		"the partially written stream must be aborted")
+}
+
+func TestHTTP_Respond_FileNotModified(t *testing.T) {
+	f := template.File{Content: []byte("image"), ContentType: "image/png",
+		Header: map[string]string{"ETag": `"abc"`, "Cache-Control": "public, max-age=60"}}
+
+	tests := []struct {
+		desc        string
+		ifNoneMatch string
+		statusCode  int
+		body        string
+	}{
+		{"without If-None-Match", "", http.StatusOK, "image"},
+		{"matching etag", `"xyz", "abc"`, http.StatusNotModified, ""},
+		{"weak etag", `W/"abc"`, http.StatusNotModified, ""},
+		{"any etag", "*", http.StatusNotModified, ""},
+		{"stale etag", `"xyz"`, http.StatusOK, "image"},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		h := HTTP{w: w, ifNoneMatch: tc.ifNoneMatch}
+
+		h.Respond(f, nil)
+
+		assert.Equal(t, tc.statusCode, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.body, w.Body.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, `"abc"`, w.Header().Get("ETag"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestHTTP_Respond_Operation(t *testing.T) {
+	w := httptest.NewRecorder()
+	h := HTTP{w: w, method: http.MethodPost, resType: JSON}
+
+	h.Respond(&types.Operation{ID: "42", Status: types.OperationPending, StatusURL: "/operations/42"}, nil)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/operations/42", w.Header().Get("Location"))
+	assert.Contains(t, w.Body.String(), `"data":{"id":"42","status":"PENDING","statusUrl":"/operations/42"`)
+}
+
 func TestHTTP_Respond_PartialError(t *testing.T) {
 	w := httptest.NewRecorder()
 
@@ -263,6 +389,9 @@ func TestGetResponseContentType(t *testing.T) {
 		{"application/xml", XML},
 		{"text/plain", TEXT},
 		{"application/json", JSON},
+		{"application/msgpack", MSGPACK},
+		{"application/x-msgpack", MSGPACK},
+		{"application/cbor", CBOR},
 		{"unknown/type", defaultType},
 	}
 
@@ -0,0 +1,38 @@
+package responder
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBufferSize limits the size of the buffers returned to the pool, so that
+// an occasional large response does not keep a large buffer alive.
+const maxPooledBufferSize = 64 << 10
+
+const (
+	contentTypeHeader = "Content-Type"
+
+	jsonContentType    = "application/json"
+	xmlContentType     = "application/xml"
+	textContentType    = "text/plain"
+	msgpackContentType = "application/msgpack"
+	cborContentType    = "application/cbor"
+)
+
+//nolint:gochecknoglobals // the buffer pool has to be shared by all the responders
+var bufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+func getBuffer() *bytes.Buffer {
+	buf, _ := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	bufferPool.Put(buf)
+}
@@ -0,0 +1,81 @@
+package responder
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTP_Reset(t *testing.T) {
+	var h HTTP
+
+	r := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+	r.Header.Set("Content-type", "application/xml")
+
+	h.Reset(httptest.NewRecorder(), r)
+
+	assert.Equal(t, XML, h.resType)
+	assert.Equal(t, http.MethodPost, h.method)
+
+	// a reused responder must not retain the state of the previous request
+	w := httptest.NewRecorder()
+	h.Reset(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.Equal(t, JSON, h.resType)
+	assert.Equal(t, http.MethodGet, h.method)
+
+	h.Respond(map[string]string{"key": "value"}, nil)
+
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "{\"key\":\"value\"}\n", w.Body.String())
+}
+
+func TestHTTP_RespondContentTypeNotShared(t *testing.T) {
+	var h HTTP
+
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+
+	w := httptest.NewRecorder()
+	h.Reset(w, r)
+	h.Respond(map[string]string{"key": "value"}, nil)
+
+	// a middleware changing the header of a response must not change the header of the other responses
+	w.Header()["Content-Type"][0] = "application/problem+json"
+
+	w = httptest.NewRecorder()
+	h.Reset(w, r)
+	h.Respond(map[string]string{"key": "value"}, nil)
+
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+}
+
+func Test_putBuffer(t *testing.T) {
+	buf := getBuffer()
+	buf.WriteString("data")
+
+	putBuffer(buf)
+
+	assert.Zero(t, getBuffer().Len(), "buffer from the pool should be empty")
+
+	// large buffers are not returned to the pool, this must not panic
+	putBuffer(bytes.NewBuffer(make([]byte, 0, maxPooledBufferSize+1)))
+}
+
+func BenchmarkHTTP_Respond(b *testing.B) {
+	var h HTTP
+
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	data := map[string]interface{}{"id": 1, "name": "gofr"}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+
+		h.Reset(w, r)
+		h.Respond(data, nil)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"gofr.dev/pkg/datastore/pubsub/eventhub"
 	"gofr.dev/pkg/datastore/pubsub/google"
 	"gofr.dev/pkg/datastore/pubsub/kafka"
+	"gofr.dev/pkg/datastore/pubsub/nats"
+	"gofr.dev/pkg/datastore/pubsub/rabbitmq"
 	awssns "gofr.dev/pkg/notifier/aws-sns"
 )
 
@@ -69,6 +71,42 @@ func eventbridgeRetry(c *eventbridge.Config, g *Gofr) {
 	}
 }
 
+// natsRetry retries connecting to NATS, once connection is successful, retrying is terminated
+func natsRetry(c *nats.Config, g *Gofr) {
+	for {
+		time.Sleep(time.Duration(c.ConnRetryDuration) * time.Second)
+
+		g.Logger.Debug("Retrying NATS connection")
+
+		n, err := nats.New(c, g.Logger)
+		if err == nil {
+			g.PubSub = n
+
+			g.Logger.Infof("NATS initialized successfully, Stream: %v", c.Stream)
+
+			break
+		}
+	}
+}
+
+// rabbitMQRetry retries connecting to RabbitMQ, once connection is successful, retrying is terminated
+func rabbitMQRetry(c *rabbitmq.Config, g *Gofr) {
+	for {
+		time.Sleep(time.Duration(c.ConnRetryDuration) * time.Second)
+
+		g.Logger.Debug("Retrying RabbitMQ connection")
+
+		r, err := rabbitmq.New(c, g.Logger)
+		if err == nil {
+			g.PubSub = r
+
+			g.Logger.Infof("RabbitMQ initialized successfully, Exchange: %v", c.Exchange)
+
+			break
+		}
+	}
+}
+
 // eventhubRetry retries connecting to eventhub
 // once connection is successful, retrying is terminated
 // also while retrying to connect to eventhub, initializes avro as well if configs are set
@@ -277,3 +315,20 @@ func clickHouseRetry(c *datastore.ClickHouseConfig, g *Gofr) {
 		}
 	}
 }
+
+func ldapRetry(c *datastore.LDAPConfig, g *Gofr) {
+	for {
+		time.Sleep(time.Duration(c.ConnRetryDuration) * time.Second)
+
+		g.Logger.Debug("Retrying LDAP connection")
+
+		l, err := datastore.NewLDAP(g.Logger, c)
+		if err == nil {
+			g.LDAP = l
+
+			g.Logger.Info("LDAP initialized successfully")
+
+			break
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package gofr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	routeConflictsWarn = "warn"
+	routeConflictsFail = "fail"
+
+	defaultVariablePattern = "[^/]+"
+)
+
+// RouteConflict is a route which is never served, as the requests it matches are served by a route of the same method
+// registered before it, ex: GET /users/new registered after GET /users/{id}.
+type RouteConflict struct {
+	Route Route `json:"route"`
+	// ShadowedBy is the route registered before Route, which serves its requests.
+	ShadowedBy Route `json:"shadowedBy"`
+	// Duplicate is whether both the routes match the same requests, ex: GET /users/{id} and GET /users/{userID}.
+	Duplicate bool `json:"duplicate"`
+}
+
+func (c RouteConflict) String() string {
+	if c.Duplicate {
+		return fmt.Sprintf("%v %v is a duplicate of %v %v", c.Route.Method, c.Route.Path, c.ShadowedBy.Method, c.ShadowedBy.Path)
+	}
+
+	return fmt.Sprintf("%v %v is shadowed by %v %v, which is registered before it", c.Route.Method, c.Route.Path,
+		c.ShadowedBy.Method, c.ShadowedBy.Path)
+}
+
+// RouteConflicts returns the routes which are never served, as the routes registered before them match all their
+// requests, in the order of the registration of the routes.
+func (g *Gofr) RouteConflicts() []RouteConflict {
+	var prefix string
+
+	if g.Server != nil {
+		if r, ok := g.Server.Router.(*router); ok {
+			prefix = r.prefix
+		}
+	}
+
+	routes := make([]Route, 0, len(g.routes))
+	patterns := make([][]string, 0, len(g.routes))
+
+	for _, r := range g.routes {
+		route := Route{Method: r.Method, Path: r.Path}
+		if prefix != "" && !isWellKnownEndPoint(route.Path) {
+			route.Path = prefix + route.Path
+		}
+
+		routes = append(routes, route)
+		patterns = append(patterns, segmentPatterns(route.Path))
+	}
+
+	var conflicts []RouteConflict
+
+	for j := range routes {
+		for i := 0; i < j; i++ {
+			if routes[i].Method != routes[j].Method {
+				continue
+			}
+
+			if covered, duplicate := covers(patterns[i], patterns[j]); covered {
+				conflicts = append(conflicts, RouteConflict{Route: routes[j], ShadowedBy: routes[i], Duplicate: duplicate})
+				break
+			}
+		}
+	}
+
+	return conflicts
+}
+
+/*
+checkRouteConflicts logs the routes which are never served, and exits the application when ROUTE_CONFLICTS is fail, so
+that a route shadowed by a route registered before it is found when the application is started. The conflicts are
+logged as warnings by default.
+*/
+func (g *Gofr) checkRouteConflicts() {
+	conflicts := g.RouteConflicts()
+	if len(conflicts) == 0 {
+		return
+	}
+
+	if !strings.EqualFold(g.Config.GetOrDefault("ROUTE_CONFLICTS", routeConflictsWarn), routeConflictsFail) {
+		for _, c := range conflicts {
+			g.Logger.Warnf("route %v", c)
+		}
+
+		return
+	}
+
+	for _, c := range conflicts {
+		g.Logger.Errorf("route %v", c)
+	}
+
+	g.Logger.Errorf("application is not started, as %v routes are never served", len(conflicts))
+
+	exit(1)
+}
+
+// segmentPatterns returns the patterns of the segments of the path, a segment which is a literal is returned as it is,
+// and the other segments are returned as the regular expressions of their variables, so that the names of the variables
+// do not matter, ex: /users/{id:[0-9]+} is [users, ^(?:[0-9]+)$].
+func segmentPatterns(path string) []string {
+	segments := splitSegments(strings.Trim(path, "/"))
+	patterns := make([]string, len(segments))
+
+	for i, s := range segments {
+		if !strings.Contains(s, "{") {
+			patterns[i] = s
+			continue
+		}
+
+		var b strings.Builder
+
+		for s != "" {
+			start := strings.Index(s, "{")
+			if start < 0 {
+				b.WriteString(regexp.QuoteMeta(s))
+				break
+			}
+
+			end := variableEnd(s, start)
+			b.WriteString(regexp.QuoteMeta(s[:start]))
+
+			pattern := defaultVariablePattern
+			if _, p, ok := strings.Cut(s[start+1:end], ":"); ok {
+				pattern = p
+			}
+
+			b.WriteString("(?:" + pattern + ")")
+
+			s = s[end+1:]
+		}
+
+		patterns[i] = "^" + b.String() + "$"
+	}
+
+	return patterns
+}
+
+// splitSegments splits the path by the slashes which are not in the variables, ex: the slashes of {path:.*/.*}.
+func splitSegments(path string) []string {
+	var (
+		segments []string
+		depth    int
+		start    int
+	)
+
+	for i, c := range path {
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case c == '/' && depth == 0:
+			segments = append(segments, path[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(segments, path[start:])
+}
+
+// variableEnd returns the index of the brace which closes the variable starting at start, the braces of its regular
+// expression are matched, ex: {id:[0-9]{3}}.
+func variableEnd(s string, start int) int {
+	depth := 0
+
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(s) - 1
+}
+
+// covers returns whether the route of the patterns a matches all the requests of the route of the patterns b, and
+// whether both the routes match the same requests. The variables whose patterns differ are not compared, unless the
+// segment of b is a literal, so that only the conflicts which are certain are reported.
+func covers(a, b []string) (covered, duplicate bool) {
+	if len(a) != len(b) {
+		return false, false
+	}
+
+	duplicate = true
+
+	for i := range a {
+		switch {
+		case a[i] == b[i]:
+		case !isPattern(a[i]):
+			return false, false
+		case !isPattern(b[i]):
+			re, err := regexp.Compile(a[i])
+			if err != nil || !re.MatchString(b[i]) {
+				return false, false
+			}
+
+			duplicate = false
+		case a[i] == "^(?:"+defaultVariablePattern+")$" && !strings.Contains(b[i], ".*"):
+			duplicate = false
+		default:
+			return false, false
+		}
+	}
+
+	return true, duplicate
+}
+
+func isPattern(segment string) bool {
+	return strings.HasPrefix(segment, "^") && strings.HasSuffix(segment, "$")
+}
@@ -0,0 +1,81 @@
+package gofr
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+func noopHandler(*Context) (interface{}, error) {
+	return nil, nil
+}
+
+func TestGofr_RouteConflicts(t *testing.T) {
+	g := &Gofr{Server: &server{Router: NewRouter()}}
+
+	g.GET("/users/{id}", noopHandler)
+	g.GET("/users/new", noopHandler)
+	g.POST("/users/new", noopHandler)
+	g.GET("/users/{userID}/", noopHandler)
+	g.GET("/orders/{id:[0-9]+}", noopHandler)
+	g.GET("/orders/latest", noopHandler)
+	g.GET("/orders/42", noopHandler)
+	g.GET("/files/{path:.*}", noopHandler)
+	g.GET("/files/{name}", noopHandler)
+	g.GET("/reports/{name}.csv", noopHandler)
+	g.GET("/reports/daily.csv", noopHandler)
+	g.DELETE("/users/{id}", noopHandler)
+	g.DELETE("/users/{id}", noopHandler)
+
+	g.Server.Router.Prefix("/api")
+
+	expected := []RouteConflict{
+		{Route: Route{Method: "GET", Path: "/api/users/new"}, ShadowedBy: Route{Method: "GET", Path: "/api/users/{id}"}},
+		{Route: Route{Method: "GET", Path: "/api/users/{userID}"}, ShadowedBy: Route{Method: "GET", Path: "/api/users/{id}"},
+			Duplicate: true},
+		{Route: Route{Method: "GET", Path: "/api/orders/42"}, ShadowedBy: Route{Method: "GET", Path: "/api/orders/{id:[0-9]+}"}},
+		{Route: Route{Method: "GET", Path: "/api/reports/daily.csv"}, ShadowedBy: Route{Method: "GET", Path: "/api/reports/{name}.csv"}},
+		{Route: Route{Method: "DELETE", Path: "/api/users/{id}"}, ShadowedBy: Route{Method: "DELETE", Path: "/api/users/{id}"},
+			Duplicate: true},
+	}
+
+	assert.Equal(t, expected, g.RouteConflicts())
+	assert.Equal(t, "GET /api/users/new is shadowed by GET /api/users/{id}, which is registered before it", expected[0].String())
+	assert.Equal(t, "DELETE /api/users/{id} is a duplicate of DELETE /api/users/{id}", expected[4].String())
+}
+
+func TestGofr_checkRouteConflicts(t *testing.T) {
+	defer func() { exit = os.Exit }()
+
+	var code int
+
+	exit = func(c int) { code = c }
+
+	tests := []struct {
+		configs map[string]string
+		code    int
+		log     string
+	}{
+		{map[string]string{}, 0, "route GET /users/new is shadowed by GET /users/{id}"},
+		{map[string]string{"ROUTE_CONFLICTS": "FAIL"}, 1, "application is not started, as 1 routes are never served"},
+	}
+
+	for i, tc := range tests {
+		code = 0
+		b := new(bytes.Buffer)
+
+		g := &Gofr{Config: &config.MockConfig{Data: tc.configs}, Logger: log.NewMockLogger(b), Server: &server{Router: NewRouter()}}
+		g.GET("/users/{id}", noopHandler)
+		g.GET("/users/new", noopHandler)
+
+		g.checkRouteConflicts()
+
+		assert.Equal(t, tc.code, code, "TEST[%d], failed.\n", i)
+		assert.Contains(t, b.String(), tc.log, "TEST[%d], failed.\n", i)
+	}
+}
@@ -0,0 +1,81 @@
+package gofr
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RouteGroup adds routes which share a path prefix and middlewares, so that the prefix and the authentication of the
+// routes are not repeated on every route.
+type RouteGroup struct {
+	g           *Gofr
+	prefix      string
+	middlewares []Middleware
+}
+
+/*
+Group returns a group of routes under the prefix, whose handlers are wrapped in the middlewares, for ex:
+
+	v1 := app.Group("/api/v1", middleware.OAuth(options))
+	v1.GET("/users/{id}", handler.Get)
+
+	admin := v1.Group("/admin", requireAdmin)
+	admin.DELETE("/users/{id}", handler.Delete)
+
+The middlewares of a group are executed in order, after the middlewares of the server and of its parent groups. The
+middlewares of a group are only supported by the default router.
+*/
+func (g *Gofr) Group(prefix string, middlewares ...Middleware) *RouteGroup {
+	return &RouteGroup{g: g, prefix: "/" + strings.Trim(prefix, "/"), middlewares: middlewares}
+}
+
+// Group returns a group of routes nested in the group, which inherits the prefix and the middlewares of the group.
+func (rg *RouteGroup) Group(prefix string, middlewares ...Middleware) *RouteGroup {
+	mws := make([]Middleware, 0, len(rg.middlewares)+len(middlewares))
+	mws = append(append(mws, rg.middlewares...), middlewares...)
+
+	return &RouteGroup{g: rg.g, prefix: joinPath(rg.prefix, strings.Trim(prefix, "/")), middlewares: mws}
+}
+
+// Use adds the middlewares to the routes of the group which are added after it.
+func (rg *RouteGroup) Use(middlewares ...Middleware) {
+	rg.middlewares = append(rg.middlewares, middlewares...)
+}
+
+// GET adds a route of the group for handling HTTP GET requests, the middlewares of the route are executed after the
+// middlewares of the group.
+func (rg *RouteGroup) GET(path string, handler Handler, middlewares ...Middleware) *Route {
+	return rg.addRoute(http.MethodGet, path, handler, middlewares)
+}
+
+// PUT adds a route of the group for handling HTTP PUT requests, the middlewares of the route are executed after the
+// middlewares of the group.
+func (rg *RouteGroup) PUT(path string, handler Handler, middlewares ...Middleware) *Route {
+	return rg.addRoute(http.MethodPut, path, handler, middlewares)
+}
+
+// POST adds a route of the group for handling HTTP POST requests, the middlewares of the route are executed after the
+// middlewares of the group.
+func (rg *RouteGroup) POST(path string, handler Handler, middlewares ...Middleware) *Route {
+	return rg.addRoute(http.MethodPost, path, handler, middlewares)
+}
+
+// DELETE adds a route of the group for handling HTTP DELETE requests, the middlewares of the route are executed after the
+// middlewares of the group.
+func (rg *RouteGroup) DELETE(path string, handler Handler, middlewares ...Middleware) *Route {
+	return rg.addRoute(http.MethodDelete, path, handler, middlewares)
+}
+
+// PATCH adds a route of the group for handling HTTP PATCH requests, the middlewares of the route are executed after the
+// middlewares of the group.
+func (rg *RouteGroup) PATCH(path string, handler Handler, middlewares ...Middleware) *Route {
+	return rg.addRoute(http.MethodPatch, path, handler, middlewares)
+}
+
+func (rg *RouteGroup) addRoute(method, path string, handler Handler, middlewares []Middleware) *Route {
+	// the middlewares are copied, so that the middlewares added later to the group are not added to the route
+	mws := make([]Middleware, 0, len(rg.middlewares)+len(middlewares))
+	mws = append(append(mws, rg.middlewares...), middlewares...)
+
+	return rg.g.handle(method, joinPath(rg.prefix, path), handler, mws)
+}
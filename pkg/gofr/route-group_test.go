@@ -0,0 +1,161 @@
+package gofr
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+// headerMiddleware appends the value to the X-Groups header of the response, to record the order of the middlewares.
+func headerMiddleware(value string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Groups", value)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestGofr_Group(t *testing.T) {
+	c := &config.MockConfig{}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	handler := func(c *Context) (interface{}, error) { return c.PathParam("id"), nil }
+
+	v1 := g.Group("/api/v1/", headerMiddleware("v1"))
+	v1.GET("/", func(*Context) (interface{}, error) { return "index", nil })
+	v1.GET("/users/{id}", handler)
+
+	admin := v1.Group("admin/", headerMiddleware("admin"))
+	admin.DELETE("/users/{id}", handler)
+
+	v1.Use(headerMiddleware("late"))
+	v1.POST("/users/{id}", handler)
+	v1.PUT("/users/{id}", handler, headerMiddleware("route"))
+
+	g.GET("/users/{id}", handler)
+
+	tests := []struct {
+		desc    string
+		method  string
+		target  string
+		status  int
+		body    string
+		headers []string
+	}{
+		{"root of the group", http.MethodGet, "/api/v1", http.StatusOK, "index", []string{"v1"}},
+		{"route of the group", http.MethodGet, "/api/v1/users/7", http.StatusOK, "7", []string{"v1"}},
+		{"nested group", http.MethodDelete, "/api/v1/admin/users/7", http.StatusNoContent, "", []string{"v1", "admin"}},
+		{"middleware added later", http.MethodPost, "/api/v1/users/7", http.StatusCreated, "7", []string{"v1", "late"}},
+		{"middleware of the route", http.MethodPut, "/api/v1/users/7", http.StatusOK, "7", []string{"v1", "late", "route"}},
+		{"route outside the group", http.MethodGet, "/users/7", http.StatusOK, "7", nil},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		g.Server.Router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, http.NoBody))
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Contains(t, w.Body.String(), tc.body, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.headers, w.Header().Values("X-Groups"), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	routes := g.Routes()
+
+	assert.Equal(t, "/api/v1/admin/users/{id}", routes[1].Path)
+	assert.Equal(t, []string{"gofr.headerMiddleware", "gofr.headerMiddleware"}, routes[1].middlewares,
+		"the middlewares of the groups are listed with the route")
+}
+
+// customRouter is a router other than the default one, which does not support the middlewares of the routes.
+type customRouter struct {
+	Router
+}
+
+func TestGofr_GroupCustomRouter(t *testing.T) {
+	defer func() { exit = os.Exit }()
+
+	var code int
+
+	exit = func(c int) { code = c }
+
+	b := new(bytes.Buffer)
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(b), Server: &server{Router: customRouter{NewRouter()}}}
+
+	g.Group("/admin", headerMiddleware("auth")).GET("/users", func(*Context) (interface{}, error) { return "ok", nil })
+
+	assert.Equal(t, 1, code, "the application exits, instead of serving the route without the middlewares of the group")
+	assert.Contains(t, b.String(), "route GET /admin/users has middlewares, which are not supported by the router")
+}
+
+func TestGofr_RouteMiddlewares(t *testing.T) {
+	c := &config.MockConfig{}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	handler := func(*Context) (interface{}, error) { return "ok", nil }
+
+	g.GET("/admin", handler, headerMiddleware("auth"), headerMiddleware("audit"))
+	g.GET("/public", handler)
+
+	tests := []struct {
+		desc    string
+		target  string
+		headers []string
+	}{
+		{"route with middlewares", "/admin", []string{"auth", "audit"}},
+		{"route without middlewares", "/public", nil},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		g.Server.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.headers, w.Header().Values("X-Groups"), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	routes := g.Routes()
+
+	assert.Equal(t, "/admin", routes[0].Path)
+	assert.Equal(t, []string{"gofr.headerMiddleware", "gofr.headerMiddleware"}, routes[0].middlewares)
+	assert.Nil(t, routes[1].middlewares)
+}
+
+func TestGofr_RouteMiddlewaresCustomRouter(t *testing.T) {
+	defer func() { exit = os.Exit }()
+
+	var code int
+
+	exit = func(c int) { code = c }
+
+	b := new(bytes.Buffer)
+	router := customRouter{NewRouter()}
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(b), Server: &server{Router: router}}
+	handler := func(*Context) (interface{}, error) { return "ok", nil }
+
+	g.GET("/public", handler)
+
+	assert.Equal(t, 0, code, "the routes without middlewares are served by the router")
+
+	g.GET("/admin", handler, headerMiddleware("auth"))
+
+	assert.Equal(t, 1, code, "the application exits, instead of serving the route without its middlewares")
+	assert.Contains(t, b.String(), "route GET /admin has middlewares, which are not supported by the router")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", http.NoBody))
+
+	assert.Equal(t, http.StatusNotFound, w.Code, "the route is not served without its middlewares")
+}
@@ -0,0 +1,221 @@
+package gofr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/middleware"
+)
+
+// RouteManifest is a routing table, which maps the routes to the handlers and the middlewares registered by their
+// names, so that the routes can be disabled or their authentication changed without changing the code, ex:
+//
+//	routes:
+//	  - method: GET
+//	    path: /orders/{id}
+//	    handler: getOrder
+//	    middleware: [audit]
+//	    auth:
+//	      scopes: [orders.read]
+//	  - method: DELETE
+//	    path: /orders/{id}
+//	    handler: deleteOrder
+//	    disabled: true
+//	headers:
+//	  - prefix: /orders
+//	    set:
+//	      Cache-Control: no-store
+type RouteManifest struct {
+	Routes []ManifestRoute `json:"routes" yaml:"routes"`
+	// Headers are the response header policies of the route groups.
+	Headers []HeaderPolicy `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+// ManifestRoute is a route of the routing manifest.
+type ManifestRoute struct {
+	Method string `json:"method" yaml:"method"`
+	Path   string `json:"path" yaml:"path"`
+	// Handler is the name of the handler registered using RegisterHandler.
+	Handler string `json:"handler" yaml:"handler"`
+	// Middleware is the name of the middlewares registered using RegisterMiddleware, which are executed in order
+	// after the middlewares of the server.
+	Middleware []string   `json:"middleware,omitempty" yaml:"middleware,omitempty"`
+	Auth       *RouteAuth `json:"auth,omitempty" yaml:"auth,omitempty"`
+	// Disabled routes are not added, so that they are not served.
+	Disabled    bool     `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	Summary     string   `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// RouteAuth is the authentication required by a route, in addition to the authentication of the server. The user is
+// authenticated by the OAuth middleware or by the X-Authenticated-UserId header.
+type RouteAuth struct {
+	// Required rejects the requests of the unauthenticated users with 401 Unauthorized.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+	// Scopes are required in the scope claim of the JWT, the requests missing any of them are rejected with 403 Forbidden.
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// RegisterHandler registers the handler by its name, so that it can be routed using the routing manifest.
+func (g *Gofr) RegisterHandler(name string, handler Handler) {
+	if g.handlers == nil {
+		g.handlers = make(map[string]Handler)
+	}
+
+	g.handlers[name] = handler
+}
+
+// RegisterMiddleware registers the middleware by its name, so that it can be added to the routes of the routing manifest.
+func (g *Gofr) RegisterMiddleware(name string, m Middleware) {
+	if g.middlewares == nil {
+		g.middlewares = make(map[string]Middleware)
+	}
+
+	g.middlewares[name] = m
+}
+
+// LoadRoutes adds the routes of the routing manifest in the file, which is YAML or JSON based on its extension.
+func (g *Gofr) LoadRoutes(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var m RouteManifest
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &m)
+	case ".json":
+		err = json.Unmarshal(data, &m)
+	default:
+		return fmt.Errorf("routing manifest %v is neither YAML nor JSON", filepath.Base(file))
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid routing manifest %v: %w", filepath.Base(file), err)
+	}
+
+	return g.AddRoutes(&m)
+}
+
+// AddRoutes adds the routes of the routing manifest. No route is added when any route refers to a handler or a
+// middleware which is not registered.
+func (g *Gofr) AddRoutes(m *RouteManifest) error {
+	for i := range m.Routes {
+		if err := g.validateManifestRoute(&m.Routes[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range m.Routes {
+		r := &m.Routes[i]
+
+		if r.Disabled {
+			g.Logger.Infof("route %v %v is disabled by the routing manifest", r.Method, r.Path)
+			continue
+		}
+
+		handler := g.handlers[r.Handler]
+		if r.Auth != nil {
+			handler = requireAuth(r.Auth, handler)
+		}
+
+		g.addRoute(strings.ToUpper(r.Method), r.Path, handler, r.Middleware...).Doc(r.Summary, r.Description, r.Tags...)
+	}
+
+	for _, p := range m.Headers {
+		g.AddHeaderPolicy(p)
+	}
+
+	return nil
+}
+
+func (g *Gofr) validateManifestRoute(r *ManifestRoute) error {
+	switch strings.ToUpper(r.Method) {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return fmt.Errorf("route %v %v has an invalid method", r.Method, r.Path)
+	}
+
+	if _, ok := g.handlers[r.Handler]; !ok {
+		return fmt.Errorf("route %v %v refers to the handler %q which is not registered", r.Method, r.Path, r.Handler)
+	}
+
+	if g.Server != nil && len(r.Middleware) > 0 {
+		if _, ok := g.Server.Router.(*router); !ok {
+			return fmt.Errorf("route %v %v has middlewares, which are not supported by the router", r.Method, r.Path)
+		}
+	}
+
+	for _, name := range r.Middleware {
+		if _, ok := g.middlewares[name]; !ok {
+			return fmt.Errorf("route %v %v refers to the middleware %q which is not registered", r.Method, r.Path, name)
+		}
+	}
+
+	return nil
+}
+
+// requireAuth rejects the requests of the unauthenticated users, and of the users without the scopes of the route.
+func requireAuth(auth *RouteAuth, handler Handler) Handler {
+	return func(c *Context) (interface{}, error) {
+		if !auth.Required && len(auth.Scopes) == 0 {
+			return handler(c)
+		}
+
+		if c.GetClaims() == nil && authenticatedSubject(c) == "" {
+			return nil, &errors.Response{StatusCode: http.StatusUnauthorized, Code: "Unauthenticated",
+				Reason: "authentication is required"}
+		}
+
+		for _, scope := range auth.Scopes {
+			if !hasScope(c, scope) {
+				return nil, &errors.Response{StatusCode: http.StatusForbidden, Code: "Forbidden",
+					Reason: fmt.Sprintf("scope %v is required", scope)}
+			}
+		}
+
+		return handler(c)
+	}
+}
+
+/*
+authenticatedSubject returns the subject of the verified JWT. The user id of the X-Authenticated-UserId header is
+sent by the client, hence it is used only when TRUSTED_GATEWAY is true, i.e. the application is reachable only through
+a gateway which authenticates the users and overwrites the header.
+*/
+func authenticatedSubject(c *Context) string {
+	if sub, ok := c.GetClaim("sub").(string); ok && sub != "" {
+		return sub
+	}
+
+	if c.Gofr == nil || c.Config == nil || !strings.EqualFold(c.Config.Get("TRUSTED_GATEWAY"), "true") {
+		return ""
+	}
+
+	userID, _ := c.Request().Context().Value(middleware.AuthenticatedUserIDKey).(string)
+
+	return userID
+}
+
+// hasScope reports whether the scope is in the space separated scope claim of the JWT.
+func hasScope(c *Context, scope string) bool {
+	scopes, _ := c.GetClaim("scope").(string)
+
+	for _, s := range strings.Fields(scopes) {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
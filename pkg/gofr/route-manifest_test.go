@@ -0,0 +1,140 @@
+package gofr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware/oauth"
+)
+
+const routeManifest = `
+routes:
+  - method: get
+    path: /orders/{id}
+    handler: getOrder
+    middleware: [audit]
+    auth:
+      scopes: [orders.read]
+  - method: DELETE
+    path: /orders/{id}
+    handler: deleteOrder
+    disabled: true
+  - method: POST
+    path: /orders
+    handler: createOrder
+    summary: Create an order
+`
+
+func newManifestApp() *Gofr {
+	c := &config.MockConfig{}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.Server = NewServer(c, g)
+	// the context is injected on starting the server
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	handler := func(c *Context) (interface{}, error) { return "order", nil }
+
+	g.RegisterHandler("getOrder", handler)
+	g.RegisterHandler("deleteOrder", handler)
+	g.RegisterHandler("createOrder", handler)
+	g.RegisterMiddleware("audit", func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Audited", "true")
+			inner.ServeHTTP(w, r)
+		})
+	})
+
+	return g
+}
+
+func TestGofr_LoadRoutes(t *testing.T) {
+	g := newManifestApp()
+
+	file := filepath.Join(t.TempDir(), "routes.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte(routeManifest), 0600))
+	assert.NoError(t, g.LoadRoutes(file))
+
+	tests := []struct {
+		desc    string
+		method  string
+		target  string
+		userID  string
+		sub     string
+		scope   string
+		status  int
+		audited bool
+	}{
+		{"unauthenticated", http.MethodGet, "/orders/1", "", "", "", http.StatusUnauthorized, true},
+		{"user of untrusted gateway", http.MethodGet, "/orders/1", "alice", "", "", http.StatusUnauthorized, true},
+		{"authenticated without scope", http.MethodGet, "/orders/1", "", "alice", "", http.StatusForbidden, true},
+		{"authenticated with scope", http.MethodGet, "/orders/1", "", "", "orders.write orders.read", http.StatusOK, true},
+		{"disabled route", http.MethodDelete, "/orders/1", "", "", "", http.StatusMethodNotAllowed, false},
+		{"route without auth", http.MethodPost, "/orders", "", "", "", http.StatusCreated, false},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(tc.method, tc.target, http.NoBody)
+
+		if tc.userID != "" {
+			r.Header.Set("X-Authenticated-UserId", tc.userID)
+		}
+
+		if tc.sub != "" || tc.scope != "" {
+			r = r.WithContext(context.WithValue(r.Context(), oauth.JWTContextKey("claims"),
+				jwt.MapClaims{"sub": tc.sub, "scope": tc.scope}))
+		}
+
+		w := httptest.NewRecorder()
+		g.Server.Router.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.audited, w.Header().Get("X-Audited") == "true", "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	routes := g.Routes()
+
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "Create an order", routes[0].Summary)
+	assert.Contains(t, routes[1].Middleware, "audit")
+}
+
+func TestGofr_LoadRoutesInvalid(t *testing.T) {
+	tests := []struct {
+		desc     string
+		file     string
+		manifest string
+		err      string
+	}{
+		{"unsupported extension", "routes.toml", "", "routing manifest routes.toml is neither YAML nor JSON"},
+		{"invalid JSON", "routes.json", "{", "invalid routing manifest routes.json: unexpected end of JSON input"},
+		{"handler not registered", "routes.json", `{"routes":[{"method":"GET","path":"/orders","handler":"listOrders"}]}`,
+			`route GET /orders refers to the handler "listOrders" which is not registered`},
+		{"middleware not registered", "routes.yml",
+			"routes:\n  - method: GET\n    path: /orders/{id}\n    handler: getOrder\n    middleware: [cache]",
+			`route GET /orders/{id} refers to the middleware "cache" which is not registered`},
+		{"invalid method", "routes.yml", "routes:\n  - {method: TRACE, path: /orders, handler: getOrder}",
+			"route TRACE /orders has an invalid method"},
+	}
+
+	for i, tc := range tests {
+		g := newManifestApp()
+
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, tc.file), []byte(tc.manifest), 0600))
+
+		err := g.LoadRoutes(filepath.Join(dir, tc.file))
+
+		assert.EqualError(t, err, tc.err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Empty(t, g.Routes(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,190 @@
+package gofr
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gofr.dev/pkg/errors"
+)
+
+const defaultRouteAdminScope = "routes:admin"
+
+// DisabledRoute is a route which is disabled at runtime, its requests are rejected with 503 Service Unavailable.
+type DisabledRoute struct {
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Reason     string    `json:"reason"`
+	DisabledBy string    `json:"disabledBy,omitempty"`
+	DisabledAt time.Time `json:"disabledAt"`
+}
+
+// routeSwitch holds the routes disabled at runtime, keyed by the method and the path template of the route. The
+// routes are disabled on the instance only, hence they are enabled again when it is restarted.
+type routeSwitch struct {
+	mu       sync.RWMutex
+	disabled map[string]DisabledRoute
+}
+
+func routeSwitchKey(method, path string) string {
+	// the HEAD requests are served by the GET routes
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+
+	return strings.ToUpper(method) + " " + path
+}
+
+// DisableRoute disables the route of the method and the path template, ex: DisableRoute("DELETE", "/orders/{id}",
+// "exploited"), so that it is shut off without redeploying the application. Its requests are rejected with 503
+// Service Unavailable and the reason, until it is enabled using EnableRoute.
+func (g *Gofr) DisableRoute(method, path, reason string) (*DisabledRoute, error) {
+	return g.disableRoute(method, path, reason, "")
+}
+
+func (g *Gofr) disableRoute(method, path, reason, actor string) (*DisabledRoute, error) {
+	path = strings.TrimSuffix(path, "/")
+
+	if !g.hasRoute(method, path) {
+		return nil, errors.EntityNotFound{Entity: "route", ID: strings.ToUpper(method) + " " + path}
+	}
+
+	route := DisabledRoute{Method: strings.ToUpper(method), Path: path, Reason: reason, DisabledBy: actor, DisabledAt: time.Now()}
+
+	g.routeSwitch.mu.Lock()
+
+	if g.routeSwitch.disabled == nil {
+		g.routeSwitch.disabled = make(map[string]DisabledRoute)
+	}
+
+	g.routeSwitch.disabled[routeSwitchKey(method, path)] = route
+
+	g.routeSwitch.mu.Unlock()
+
+	g.Logger.Warnf("route %v %v is disabled: %v", route.Method, route.Path, reason)
+
+	return &route, nil
+}
+
+// EnableRoute enables the route disabled using DisableRoute, it returns false when the route is not disabled.
+func (g *Gofr) EnableRoute(method, path string) bool {
+	key := routeSwitchKey(method, strings.TrimSuffix(path, "/"))
+
+	g.routeSwitch.mu.Lock()
+	_, ok := g.routeSwitch.disabled[key]
+	delete(g.routeSwitch.disabled, key)
+	g.routeSwitch.mu.Unlock()
+
+	if ok {
+		g.Logger.Infof("route %v is enabled", key)
+	}
+
+	return ok
+}
+
+// DisabledRoutes returns the routes which are disabled, sorted by path and method.
+func (g *Gofr) DisabledRoutes() []DisabledRoute {
+	g.routeSwitch.mu.RLock()
+
+	routes := make([]DisabledRoute, 0, len(g.routeSwitch.disabled))
+	for _, r := range g.routeSwitch.disabled {
+		routes = append(routes, r)
+	}
+
+	g.routeSwitch.mu.RUnlock()
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes
+}
+
+// disabledRoute returns the route of the request, when it is disabled.
+func (c *Context) disabledRoute(method, path string) (DisabledRoute, bool) {
+	if c == nil || c.Gofr == nil {
+		return DisabledRoute{}, false
+	}
+
+	g := c.Gofr
+
+	g.routeSwitch.mu.RLock()
+	defer g.routeSwitch.mu.RUnlock()
+
+	route, ok := g.routeSwitch.disabled[routeSwitchKey(method, path)]
+
+	return route, ok
+}
+
+// hasRoute reports whether the route is registered, the routes of the framework can not be disabled, so that the
+// routes disabling them are not disabled.
+func (g *Gofr) hasRoute(method, path string) bool {
+	for _, r := range g.Routes() {
+		if strings.EqualFold(r.Method, method) && r.Path == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// routeDisabledError is the error of the requests of a disabled route.
+func routeDisabledError(route DisabledRoute) error {
+	return &errors.Response{StatusCode: http.StatusServiceUnavailable, Code: "Route Disabled",
+		Reason: fmt.Sprintf("route %v %v is disabled: %v", route.Method, route.Path, route.Reason)}
+}
+
+// authorizeRouteAdmin allows the users with the scope ROUTE_ADMIN_SCOPE (default is routes:admin) to disable and
+// enable the routes.
+func authorizeRouteAdmin(handler Handler) Handler {
+	return func(c *Context) (interface{}, error) {
+		scope := defaultRouteAdminScope
+		if c.Config != nil && c.Config.Get("ROUTE_ADMIN_SCOPE") != "" {
+			scope = c.Config.Get("ROUTE_ADMIN_SCOPE")
+		}
+
+		return requireAuth(&RouteAuth{Required: true, Scopes: []string{scope}}, handler)(c)
+	}
+}
+
+// DisabledRoutesHandler lists the routes which are disabled.
+func DisabledRoutesHandler(c *Context) (interface{}, error) {
+	return c.Gofr.DisabledRoutes(), nil
+}
+
+// DisableRouteHandler disables the route of the method and the path in the body, ex:
+// {"method": "DELETE", "path": "/orders/{id}", "reason": "exploited"}.
+func DisableRouteHandler(c *Context) (interface{}, error) {
+	var body DisabledRoute
+
+	if err := c.Bind(&body); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	if body.Method == "" || body.Path == "" || body.Reason == "" {
+		return nil, errors.MissingParam{Param: []string{"method", "path", "reason"}}
+	}
+
+	return c.Gofr.disableRoute(body.Method, body.Path, body.Reason, authenticatedSubject(c))
+}
+
+// EnableRouteHandler enables the route of the query parameters method and path.
+func EnableRouteHandler(c *Context) (interface{}, error) {
+	method, path := c.Param("method"), c.Param("path")
+	if method == "" || path == "" {
+		return nil, errors.MissingParam{Param: []string{"method", "path"}}
+	}
+
+	if !c.Gofr.EnableRoute(method, path) {
+		return nil, errors.EntityNotFound{Entity: "disabled route", ID: strings.ToUpper(method) + " " + path}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,134 @@
+package gofr
+
+import (
+	"bytes"
+	ctx "context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
+	"gofr.dev/pkg/middleware/oauth"
+)
+
+func TestGofr_DisableRoute(t *testing.T) {
+	c := &config.MockConfig{}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(new(bytes.Buffer))}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	g.GET("/orders/{id}", func(*Context) (interface{}, error) { return "order", nil })
+	g.Server.Router.Route(http.MethodGet, pkg.PathDisabledRoutes, authorizeRouteAdmin(DisabledRoutesHandler))
+	g.Server.Router.Route(http.MethodPost, pkg.PathDisabledRoutes, authorizeRouteAdmin(DisableRouteHandler))
+	g.Server.Router.Route(http.MethodDelete, pkg.PathDisabledRoutes, authorizeRouteAdmin(EnableRouteHandler))
+
+	admin := jwt.MapClaims{"sub": "ops", "scope": "routes:admin"}
+
+	tests := []struct {
+		desc   string
+		method string
+		target string
+		body   string
+		claims jwt.MapClaims
+		status int
+	}{
+		{"unauthenticated", http.MethodPost, pkg.PathDisabledRoutes, `{"method":"GET","path":"/orders/{id}","reason":"x"}`,
+			nil, http.StatusUnauthorized},
+		{"without the scope", http.MethodPost, pkg.PathDisabledRoutes, `{"method":"GET","path":"/orders/{id}","reason":"x"}`,
+			jwt.MapClaims{"sub": "u-1"}, http.StatusForbidden},
+		{"unknown route", http.MethodPost, pkg.PathDisabledRoutes, `{"method":"GET","path":"/users","reason":"x"}`,
+			admin, http.StatusNotFound},
+		{"missing reason", http.MethodPost, pkg.PathDisabledRoutes, `{"method":"GET","path":"/orders/{id}"}`,
+			admin, http.StatusBadRequest},
+		{"enabled route", http.MethodGet, "/orders/1", "", nil, http.StatusOK},
+		{"disable", http.MethodPost, pkg.PathDisabledRoutes, `{"method":"GET","path":"/orders/{id}","reason":"exploited"}`,
+			admin, http.StatusCreated},
+		{"disabled route", http.MethodGet, "/orders/1", "", nil, http.StatusServiceUnavailable},
+		{"HEAD of disabled route", http.MethodHead, "/orders/1", "", nil, http.StatusServiceUnavailable},
+		{"enable", http.MethodDelete, pkg.PathDisabledRoutes + "?method=GET&path=/orders/{id}", "", admin, http.StatusNoContent},
+		{"enable route which is not disabled", http.MethodDelete, pkg.PathDisabledRoutes + "?method=GET&path=/orders/{id}", "",
+			admin, http.StatusNotFound},
+		{"enabled route again", http.MethodGet, "/orders/1", "", nil, http.StatusOK},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		if tc.claims != nil {
+			r = r.WithContext(ctx.WithValue(r.Context(), oauth.JWTContextKey("claims"), tc.claims))
+		}
+
+		w := httptest.NewRecorder()
+		g.Server.Router.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.status == http.StatusServiceUnavailable && tc.method == http.MethodGet {
+			assert.Contains(t, w.Body.String(), "route GET /orders/{id} is disabled: exploited", "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestGofr_DisabledRoutes(t *testing.T) {
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(new(bytes.Buffer))}
+	g.Server = NewServer(g.Config, g)
+
+	g.GET("/orders", func(*Context) (interface{}, error) { return nil, nil })
+	g.DELETE("/orders/{id}", func(*Context) (interface{}, error) { return nil, nil })
+
+	_, err := g.DisableRoute(http.MethodDelete, "/orders/{id}/", "exploited")
+	assert.NoError(t, err)
+
+	_, err = g.DisableRoute("get", "/orders", "slow")
+	assert.NoError(t, err)
+
+	_, err = g.DisableRoute(http.MethodGet, pkg.PathDisabledRoutes, "x")
+	assert.Error(t, err, "the routes of the framework can not be disabled")
+
+	routes := g.DisabledRoutes()
+	if assert.Len(t, routes, 2) {
+		assert.Equal(t, []string{"GET /orders slow", "DELETE /orders/{id} exploited"},
+			[]string{routes[0].Method + " " + routes[0].Path + " " + routes[0].Reason, routes[1].Method + " " + routes[1].Path + " " +
+				routes[1].Reason})
+	}
+
+	assert.True(t, g.EnableRoute(http.MethodDelete, "/orders/{id}"))
+	assert.Len(t, g.DisabledRoutes(), 1)
+}
+
+func TestDisableRouteHandler_DisabledBy(t *testing.T) {
+	tests := []struct {
+		desc    string
+		trusted string
+		claims  jwt.MapClaims
+		by      string
+	}{
+		{"user id sent by the client", "", jwt.MapClaims{"scope": "routes:admin"}, ""},
+		{"user id sent by the trusted gateway", "true", jwt.MapClaims{"scope": "routes:admin"}, "ceo"},
+		{"subject of the JWT", "", jwt.MapClaims{"sub": "ops", "scope": "routes:admin"}, "ops"},
+	}
+
+	for i, tc := range tests {
+		c := &config.MockConfig{Data: map[string]string{"TRUSTED_GATEWAY": tc.trusted}}
+		g := &Gofr{Config: c, Logger: log.NewMockLogger(new(bytes.Buffer))}
+		g.Server = NewServer(c, g)
+		g.GET("/orders", func(*Context) (interface{}, error) { return nil, nil })
+
+		r := httptest.NewRequest(http.MethodPost, pkg.PathDisabledRoutes,
+			strings.NewReader(`{"method":"GET","path":"/orders","reason":"slow"}`))
+		r = r.WithContext(ctx.WithValue(ctx.WithValue(r.Context(), oauth.JWTContextKey("claims"), tc.claims),
+			middleware.AuthenticatedUserIDKey, "ceo"))
+
+		resp, err := DisableRouteHandler(NewContext(nil, request.NewHTTPRequest(r), g))
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.by, resp.(*DisabledRoute).DisabledBy, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
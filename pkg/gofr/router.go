@@ -3,6 +3,9 @@ package gofr
 import (
 	"fmt"
 	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
 	"strings"
 
 	"gofr.dev/pkg"
@@ -31,6 +34,8 @@ type router struct {
 	mux.Router
 
 	prefix string
+	// middlewares is the name of the middlewares in the order in which they are executed
+	middlewares []string
 }
 
 // NewRouter returns an implementation of Router interface. Right now, it uses gorilla mux as underlying Router. One can
@@ -51,6 +56,7 @@ func (r *router) Use(middleware ...Middleware) {
 	mwf := make([]mux.MiddlewareFunc, 0, len(middleware))
 	for _, m := range middleware {
 		mwf = append(mwf, mux.MiddlewareFunc(m))
+		r.middlewares = append(r.middlewares, middlewareName(m))
 	}
 
 	r.Router.Use(mwf...)
@@ -66,6 +72,12 @@ func (r *router) Prefix(prefix string) {
 // this was written to be the standard way to create a route, any additional syntactic
 // sugar can be added on top of this by defining methods on the gofr struct and calling this.
 func (r *router) Route(method, path string, handler Handler) {
+	r.handle(method, path, handler)
+}
+
+// handle creates a route served by an http.Handler, so that the handler of a route can be wrapped in the middlewares
+// of the route.
+func (r *router) handle(method, path string, handler http.Handler) {
 	if r.prefix != "" && !isWellKnownEndPoint(path) {
 		path = r.prefix + path
 	}
@@ -113,6 +125,21 @@ func (r *router) CatchAllRoute(h Handler) {
 	r.Router.PathPrefix("/").Handler(h)
 }
 
+//nolint:gochecknoglobals // closureSuffix is compiled once, and used as a constant
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+$|-fm$`)
+
+// middlewareName returns the name of the function which created the middleware, ex: middleware.Logging
+func middlewareName(m Middleware) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(m).Pointer())
+	if fn == nil {
+		return ""
+	}
+
+	name := closureSuffix.ReplaceAllString(fn.Name(), "")
+
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
 func contains(elem []string, key string) bool {
 	for _, v := range elem {
 		if v == key {
@@ -125,6 +152,8 @@ func contains(elem []string, key string) bool {
 
 // isWellKnownEndPoint checks whether the given path is a well-known endpoint
 func isWellKnownEndPoint(path string) bool {
-	return path == pkg.PathHealthCheck || path == pkg.PathHeartBeat || path == pkg.PathOpenAPI ||
-		path == pkg.PathSwagger || path == pkg.PathSwaggerWithPathParam
+	return path == pkg.PathHealthCheck || path == pkg.PathHeartBeat || path == pkg.PathReady || path == pkg.PathRoutes ||
+		path == pkg.PathDisabledRoutes || path == pkg.PathConfig || path == pkg.PathOpenAPI || path == pkg.PathSwagger || path == pkg.PathSwaggerWithPathParam ||
+		path == pkg.PathNotifications || path == pkg.PathNotificationPreview ||
+		path == pkg.PathRateLimits || path == pkg.PathRateLimit
 }
@@ -47,19 +47,31 @@ func TestRouter_WellKnownEndpoint(t *testing.T) {
 	}{
 		{"case when route is /hello-world and  prefix is empty", "/hello-world", "",
 			"GET /hello-world HEAD /hello-world GET /.well-known/health-check " + "HEAD /.well-known/health-check GET " +
-				"/.well-known/heartbeat HEAD /.well-known/heartbeat "},
+				"/.well-known/heartbeat HEAD /.well-known/heartbeat " +
+				"GET /.well-known/ready HEAD /.well-known/ready " +
+				"GET /.well-known/routes/disabled HEAD /.well-known/routes/disabled " +
+				"POST /.well-known/routes/disabled DELETE /.well-known/routes/disabled "},
 		{"case when route is /hello-world and prefix is /api", "/hello-world", "/api",
 			"GET /api/hello-world HEAD /api/hello-world GET /.well-known/health-check HEAD" +
-				" /.well-known/health-check GET /.well-known/heartbeat HEAD /.well-known/heartbeat "},
+				" /.well-known/health-check GET /.well-known/heartbeat HEAD /.well-known/heartbeat " +
+				"GET /.well-known/ready HEAD /.well-known/ready " +
+				"GET /.well-known/routes/disabled HEAD /.well-known/routes/disabled " +
+				"POST /.well-known/routes/disabled DELETE /.well-known/routes/disabled "},
 		{"case when route is hello-world and prefix is api/", "hello-world", "api/", ""},
 		{"case when route is hello-world/ and prefix is api/", "hello-world/", "api/", ""},
 		{"case when route is /hello-world/ and prefix is empty", "/hello-world/", "", "GET /hello-world HEAD /hello-world " +
-			"GET /.well-known/health-check HEAD /.well-known/health-check GET /.well-known/heartbeat HEAD /.well-known/heartbeat "},
+			"GET /.well-known/health-check HEAD /.well-known/health-check GET /.well-known/heartbeat HEAD /.well-known/heartbeat " +
+			"GET /.well-known/ready HEAD /.well-known/ready " +
+			"GET /.well-known/routes/disabled HEAD /.well-known/routes/disabled " +
+			"POST /.well-known/routes/disabled DELETE /.well-known/routes/disabled "},
 		{"case when route is /hello-world and when prefix is api ", "/hello-world", "api", ""},
 		{"case when route is /hello-world and prefix is api/", "/hello-world", "api/", ""},
 		{"case when route is /hello-world when prefix is /api/", "/hello-world", "/api/", "GET /api//hello-world HEAD" +
 			" /api//hello-world GET /.well-known/health-check HEAD /.well-known/health-check GET" +
-			" /.well-known/heartbeat HEAD /.well-known/heartbeat "},
+			" /.well-known/heartbeat HEAD /.well-known/heartbeat " +
+			"GET /.well-known/ready HEAD /.well-known/ready " +
+			"GET /.well-known/routes/disabled HEAD /.well-known/routes/disabled " +
+			"POST /.well-known/routes/disabled DELETE /.well-known/routes/disabled "},
 	}
 	for i, tc := range testcases {
 		g := New()
@@ -84,9 +96,12 @@ func Test_isWellKnownEndPoint(t *testing.T) {
 	}{
 		{"success case when health check path is given", pkg.PathHealthCheck, true},
 		{"success case when heart beat path is given", pkg.PathHeartBeat, true},
+		{"success case when ready path is given", pkg.PathReady, true},
 		{"success case when openAPI path is given", pkg.PathOpenAPI, true},
 		{"success case when swagger path is given", pkg.PathSwagger, true},
 		{"success case when swagger with pathparam path is given", pkg.PathSwaggerWithPathParam, true},
+		{"success case when rate limits path is given", pkg.PathRateLimits, true},
+		{"success case when rate limit of a tenant path is given", pkg.PathRateLimit, true},
 		{"failure case as path is incomplete", "/.well-known/health", false},
 	}
 	for i, tc := range testcase {
@@ -0,0 +1,252 @@
+package gofr
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/middleware/openapi"
+)
+
+const defaultRequestMaxBytes = 10 << 20
+
+// Route is a route registered on the application. The documentation of the route is listed by RoutesHandler, and is
+// used to generate the OpenAPI document of the application.
+type Route struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Summary     string   `json:"summary,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// Middleware is the name of the middlewares which are executed before the handler of the route.
+	Middleware []string `json:"middleware,omitempty"`
+	// RequestSchema is the name of the JSON Schema document the request body of the route is validated against.
+	RequestSchema string `json:"schema,omitempty"`
+
+	// middlewares is the name of the middlewares of the route, which are executed after the middlewares of the server.
+	middlewares []string
+	schema      *openapi.JSONSchema
+	schemaErr   error
+}
+
+// Doc documents the route, for ex:
+//
+//	app.GET("/users/{id}", handler.Get).Doc("Get a user", "Returns the user with the given id", "users")
+func (r *Route) Doc(summary, description string, tags ...string) *Route {
+	r.Summary = summary
+	r.Description = description
+	r.Tags = tags
+
+	return r
+}
+
+/*
+Schema validates the JSON request body of the route against the JSON Schema document name of fsys, before the handler
+is called, for ex:
+
+	//go:embed schemas
+	var schemas embed.FS
+
+	app.POST("/users", handler.Create).Schema(schemas, "schemas/create-user.json")
+
+The requests whose body does not match the schema are rejected with 400, along with the field and the reason of the
+mismatch. The requests of a route whose schema cannot be loaded are rejected with 500, as the body cannot be validated.
+*/
+func (r *Route) Schema(fsys fs.FS, name string) *Route {
+	r.RequestSchema = name
+	r.schema, r.schemaErr = openapi.LoadJSONSchema(fsys, name)
+
+	return r
+}
+
+// validate wraps the handler of the route, so that the request body is validated against the schema of the route,
+// the schema is read on every request, as it is set after the handler is registered.
+func (r *Route) validate(handler Handler) Handler {
+	return func(c *Context) (interface{}, error) {
+		if r.RequestSchema == "" {
+			return handler(c)
+		}
+
+		if r.schemaErr != nil {
+			c.Logger.Errorf("could not load the JSON Schema %v of the route %v %v: %v", r.RequestSchema, r.Method, r.Path,
+				r.schemaErr)
+
+			return nil, &errors.Response{StatusCode: http.StatusInternalServerError, Code: "Invalid Schema",
+				Reason: "the schema " + r.RequestSchema + " of the route could not be loaded"}
+		}
+
+		limit := defaultRequestMaxBytes
+		if c.Config != nil && positiveInt(c.Config, "REQUEST_MAX_BYTES") > 0 {
+			limit = positiveInt(c.Config, "REQUEST_MAX_BYTES")
+		}
+
+		if err := r.validateBody(c.Request(), int64(limit)); err != nil {
+			return nil, err
+		}
+
+		return handler(c)
+	}
+}
+
+/*
+validateBody validates the JSON body of the request against the schema, the body is restored for the handler. The
+body is decoded as it is read, and is rejected with 413 Request Entity Too Large when it is larger than the limit,
+REQUEST_MAX_BYTES, default is 10 MB.
+*/
+func (r *Route) validateBody(req *http.Request, limit int64) error {
+	var body bytes.Buffer
+
+	dec := json.NewDecoder(io.TeeReader(http.MaxBytesReader(nil, req.Body, limit), &body))
+
+	var value interface{}
+
+	err := dec.Decode(&value)
+	if err == nil {
+		// the body is read till the end, so that it is restored as a whole, and has nothing after the value
+		if _, err = dec.Token(); err == io.EOF {
+			err = nil
+		} else if err == nil {
+			err = errors.Error("invalid character after top-level value")
+		}
+	}
+
+	req.Body = io.NopCloser(&body)
+
+	//nolint:errorlint // the error of the reader is not wrapped by the decoder
+	if _, ok := err.(*http.MaxBytesError); ok {
+		return &errors.Response{StatusCode: http.StatusRequestEntityTooLarge, Code: "Request Entity Too Large",
+			Reason: "the request body is larger than " + strconv.FormatInt(limit, 10) + " bytes"}
+	}
+
+	if err != nil {
+		return &errors.Response{StatusCode: http.StatusBadRequest, Code: "Invalid Request Body",
+			Reason: "the request body is not a valid JSON", Detail: map[string]string{"schema": r.RequestSchema}}
+	}
+
+	if err = r.schema.Validate(value); err != nil {
+		detail := map[string]string{"schema": r.RequestSchema}
+
+		//nolint:errorlint // the errors of the schema are not wrapped
+		if v, ok := err.(openapi.ValidationError); ok {
+			detail["field"], detail["reason"] = v.Field, v.Reason
+		}
+
+		return &errors.Response{StatusCode: http.StatusBadRequest, Code: "Invalid Request Body",
+			Reason: err.Error(), Detail: detail}
+	}
+
+	return nil
+}
+
+// Routes returns the routes registered on the application, sorted by path and method.
+func (g *Gofr) Routes() []Route {
+	var (
+		prefix      string
+		middlewares []string
+	)
+
+	if g.Server != nil {
+		if r, ok := g.Server.Router.(*router); ok {
+			prefix = r.prefix
+			middlewares = r.middlewares
+		}
+	}
+
+	routes := make([]Route, 0, len(g.routes))
+
+	for _, r := range g.routes {
+		route := *r
+		route.Middleware = append(append([]string{}, middlewares...), r.middlewares...)
+
+		if prefix != "" && !isWellKnownEndPoint(route.Path) {
+			route.Path = prefix + route.Path
+		}
+
+		routes = append(routes, route)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes
+}
+
+// RoutesHandler lists the routes of the application along with their middlewares and documentation. The OpenAPI
+// document generated from the routes is returned when the query parameter format is set to openapi.
+// It is served on /.well-known/routes to the probes, when ProbeAuth is configured.
+func RoutesHandler(c *Context) (interface{}, error) {
+	routes := c.Gofr.Routes()
+
+	if c.Param("format") == "openapi" {
+		return types.Raw{Data: generateOpenAPI(getAppDetails(c.Gofr.Config), routes)}, nil
+	}
+
+	return types.Raw{Data: routes}, nil
+}
+
+//nolint:gochecknoglobals // pathParamRegex is compiled once, and used as a constant
+var pathParamRegex = regexp.MustCompile(`{([^}:]+)(:[^}]*)?}`)
+
+// generateOpenAPI generates an OpenAPI document describing the paths, the path parameters and the documentation
+// of the routes. The schemas of the requests and responses are not known, hence they are not documented.
+func generateOpenAPI(app types.AppDetails, routes []Route) map[string]interface{} {
+	paths := make(map[string]map[string]interface{})
+
+	for _, r := range routes {
+		// the regular expressions of the path parameters are not part of the OpenAPI path template
+		path := pathParamRegex.ReplaceAllString(r.Path, "{$1}")
+
+		if paths[path] == nil {
+			paths[path] = make(map[string]interface{})
+		}
+
+		op := map[string]interface{}{
+			"responses": map[string]interface{}{"default": map[string]string{"description": http.StatusText(http.StatusOK)}},
+		}
+
+		if r.Summary != "" {
+			op["summary"] = r.Summary
+		}
+
+		if r.Description != "" {
+			op["description"] = r.Description
+		}
+
+		if len(r.Tags) > 0 {
+			op["tags"] = r.Tags
+		}
+
+		params := make([]map[string]interface{}, 0)
+
+		for _, m := range pathParamRegex.FindAllStringSubmatch(r.Path, -1) {
+			params = append(params, map[string]interface{}{
+				"name": m[1], "in": "path", "required": true, "schema": map[string]string{"type": "string"},
+			})
+		}
+
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		paths[path][strings.ToLower(r.Method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info":    map[string]string{"title": app.Name, "version": app.Version},
+		"paths":   paths,
+	}
+}
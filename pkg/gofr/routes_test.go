@@ -0,0 +1,125 @@
+package gofr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/gofr/request"
+	"gofr.dev/pkg/gofr/types"
+)
+
+func TestGofr_Routes(t *testing.T) {
+	g := New()
+	handler := func(c *Context) (interface{}, error) { return helloWorld, nil }
+
+	g.Server.Router.Prefix("/api")
+	g.GET("/users/{id:[0-9]+}", handler).Doc("Get user", "Returns the user with the given id", "users")
+	g.POST("/users/", handler)
+
+	routes := g.Routes()
+
+	assert.Len(t, routes, 2)
+	assert.Equal(t, Route{Method: http.MethodPost, Path: "/api/users", Middleware: routes[0].Middleware}, routes[0])
+	assert.Equal(t, "/api/users/{id:[0-9]+}", routes[1].Path)
+	assert.Equal(t, "Get user", routes[1].Summary)
+	assert.Equal(t, "Returns the user with the given id", routes[1].Description)
+	assert.Equal(t, []string{"users"}, routes[1].Tags)
+	assert.Contains(t, routes[1].Middleware, "middleware.Logging")
+	assert.Contains(t, routes[1].Middleware, "gofr.(*server).wsConnCreate")
+}
+
+func TestRoutesHandler(t *testing.T) {
+	g := &Gofr{Config: &config.MockConfig{}}
+
+	g.routes = []*Route{
+		{Method: http.MethodGet, Path: "/users/{id:[0-9]+}", Summary: "Get user", Tags: []string{"users"}},
+		{Method: http.MethodDelete, Path: "/users/{id:[0-9]+}"},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/routes", http.NoBody)
+
+	resp, err := RoutesHandler(NewContext(nil, request.NewHTTPRequest(req), g))
+
+	assert.NoError(t, err)
+	assert.Equal(t, types.Raw{Data: g.Routes()}, resp)
+
+	req = httptest.NewRequest(http.MethodGet, "/.well-known/routes?format=openapi", http.NoBody)
+
+	resp, err = RoutesHandler(NewContext(nil, request.NewHTTPRequest(req), g))
+
+	assert.NoError(t, err)
+
+	doc, _ := resp.(types.Raw).Data.(map[string]interface{})
+	paths, _ := doc["paths"].(map[string]map[string]interface{})
+
+	assert.Len(t, paths, 1)
+	assert.Contains(t, paths["/users/{id}"], "get")
+	assert.Contains(t, paths["/users/{id}"], "delete")
+
+	get, _ := paths["/users/{id}"]["get"].(map[string]interface{})
+
+	assert.Equal(t, "Get user", get["summary"])
+	assert.Equal(t, []string{"users"}, get["tags"])
+	assert.Equal(t, []map[string]interface{}{{"name": "id", "in": "path", "required": true,
+		"schema": map[string]string{"type": "string"}}}, get["parameters"])
+}
+
+func TestRoute_Schema(t *testing.T) {
+	schemas := fstest.MapFS{"create-user.json": {Data: []byte(`{"type": "object", "required": ["name"],
+		"properties": {"name": {"type": "string"}}}`)}}
+
+	handler := func(c *Context) (interface{}, error) {
+		var user struct {
+			Name string `json:"name"`
+		}
+
+		err := c.Bind(&user)
+
+		return user.Name, err
+	}
+
+	tests := []struct {
+		desc   string
+		schema string
+		body   string
+		resp   interface{}
+		err    error
+	}{
+		{"valid body", "create-user.json", `{"name": "john"}`, "john", nil},
+		{"missing field", "create-user.json", `{}`, nil, &errors.Response{StatusCode: http.StatusBadRequest,
+			Code: "Invalid Request Body", Reason: "name: required field is missing",
+			Detail: map[string]string{"schema": "create-user.json", "field": "name", "reason": "required field is missing"}}},
+		{"invalid JSON", "create-user.json", `{`, nil, &errors.Response{StatusCode: http.StatusBadRequest,
+			Code: "Invalid Request Body", Reason: "the request body is not a valid JSON",
+			Detail: map[string]string{"schema": "create-user.json"}}},
+		{"value after the body", "create-user.json", `{"name": "john"} {}`, nil, &errors.Response{
+			StatusCode: http.StatusBadRequest, Code: "Invalid Request Body", Reason: "the request body is not a valid JSON",
+			Detail: map[string]string{"schema": "create-user.json"}}},
+		{"body above the limit", "create-user.json", `{"name": "` + strings.Repeat("j", 64) + `"}`, nil,
+			&errors.Response{StatusCode: http.StatusRequestEntityTooLarge, Code: "Request Entity Too Large",
+				Reason: "the request body is larger than 64 bytes"}},
+		{"missing schema", "missing.json", `{"name": "john"}`, nil, &errors.Response{
+			StatusCode: http.StatusInternalServerError, Code: "Invalid Schema",
+			Reason: "the schema missing.json of the route could not be loaded"}},
+	}
+
+	for i, tc := range tests {
+		route := (&Route{Method: http.MethodPost, Path: "/users"}).Schema(schemas, tc.schema)
+
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := route.validate(handler)(NewContext(nil, request.NewHTTPRequest(req),
+			&Gofr{Config: &config.MockConfig{Data: map[string]string{"REQUEST_MAX_BYTES": "64"}}}))
+
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,161 @@
+package gofr
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"gofr.dev/pkg/log"
+)
+
+const (
+	defaultSlowThreshold = time.Second
+	// maxPendingTraces and maxPendingSpans bound the memory used by the spans of the traces which are not yet decided.
+	maxPendingTraces = 10000
+	maxPendingSpans  = 512
+)
+
+// adaptiveSampler samples a ratio of the traces, and records the spans of the other traces, so that the traces of the
+// slow and the failed requests are exported by tailProcessor once the requests complete.
+type adaptiveSampler struct {
+	ratio trace.Sampler
+}
+
+func (s adaptiveSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	if psc := oteltrace.SpanContextFromContext(p.ParentContext); psc.IsValid() && psc.IsSampled() {
+		return trace.SamplingResult{Decision: trace.RecordAndSample, Tracestate: psc.TraceState()}
+	}
+
+	res := s.ratio.ShouldSample(p)
+	if res.Decision == trace.Drop {
+		res.Decision = trace.RecordOnly
+	}
+
+	return res
+}
+
+func (s adaptiveSampler) Description() string {
+	return fmt.Sprintf("AdaptiveSampler{%v}", s.ratio.Description())
+}
+
+// tailProcessor forwards the sampled spans to the next processor. The spans which are only recorded are held until
+// the local root span of their trace ends, and are forwarded as sampled when the root span is slower than the
+// threshold or a span of the trace has failed; the spans which end after the root span are dropped.
+type tailProcessor struct {
+	next      trace.SpanProcessor
+	threshold time.Duration
+
+	mu      sync.Mutex
+	pending map[oteltrace.TraceID][]trace.ReadOnlySpan
+}
+
+func newTailProcessor(next trace.SpanProcessor, threshold time.Duration) *tailProcessor {
+	return &tailProcessor{next: next, threshold: threshold, pending: make(map[oteltrace.TraceID][]trace.ReadOnlySpan)}
+}
+
+func (p *tailProcessor) OnStart(parent context.Context, s trace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *tailProcessor) OnEnd(s trace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		p.next.OnEnd(s)
+		return
+	}
+
+	spans := p.hold(s)
+	if spans == nil || !p.keep(s, spans) {
+		return
+	}
+
+	for _, span := range spans {
+		p.next.OnEnd(sampledSpan{span})
+	}
+}
+
+// hold adds the span to the pending spans of its trace, and returns them once the local root span ends.
+func (p *tailProcessor) hold(s trace.ReadOnlySpan) []trace.ReadOnlySpan {
+	id := s.SpanContext().TraceID()
+	root := !s.Parent().IsValid() || s.Parent().IsRemote()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	spans, ok := p.pending[id]
+	if !ok && !root && len(p.pending) >= maxPendingTraces {
+		return nil
+	}
+
+	if len(spans) < maxPendingSpans {
+		spans = append(spans, s)
+	}
+
+	if !root {
+		p.pending[id] = spans
+		return nil
+	}
+
+	delete(p.pending, id)
+
+	return spans
+}
+
+func (p *tailProcessor) keep(root trace.ReadOnlySpan, spans []trace.ReadOnlySpan) bool {
+	if root.EndTime().Sub(root.StartTime()) >= p.threshold {
+		return true
+	}
+
+	for _, s := range spans {
+		if s.Status().Code == codes.Error {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *tailProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *tailProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// sampledSpan marks a recorded span as sampled, as the exporters export only the sampled spans.
+type sampledSpan struct {
+	trace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() oteltrace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
+
+// adaptiveTracerProvider returns the tracer provider which always samples the failed requests and the requests slower
+// than TRACER_SLOW_THRESHOLD milliseconds, and samples TRACER_RATIO of the other requests.
+func adaptiveTracerProvider(c Config, logger log.Logger, processor trace.SpanProcessor, r *resource.Resource) *trace.TracerProvider {
+	ratio, err := strconv.ParseFloat(c.Get("TRACER_RATIO"), 64)
+	if err != nil {
+		ratio = 0.1
+
+		logger.Warn("TRACER_RATIO is not set.'0.1' will be used by default")
+	}
+
+	threshold := defaultSlowThreshold
+	if ms, err := strconv.Atoi(c.Get("TRACER_SLOW_THRESHOLD")); err == nil && ms > 0 {
+		threshold = time.Duration(ms) * time.Millisecond
+	}
+
+	return trace.NewTracerProvider(
+		trace.WithSampler(adaptiveSampler{ratio: trace.TraceIDRatioBased(ratio)}),
+		trace.WithSpanProcessor(newTailProcessor(processor, threshold)),
+		trace.WithResource(r))
+}
@@ -0,0 +1,76 @@
+package gofr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestAdaptiveSampling(t *testing.T) {
+	tests := []struct {
+		desc     string
+		ratio    float64
+		duration time.Duration
+		failed   bool
+		spans    int
+	}{
+		{"fast request not sampled", 0, 0, false, 0},
+		{"fast request sampled by the ratio", 1, 0, false, 2},
+		{"slow request", 0, 60 * time.Millisecond, false, 2},
+		{"failed request", 0, 0, true, 2},
+	}
+
+	for i, tc := range tests {
+		exporter := tracetest.NewInMemoryExporter()
+
+		tp := trace.NewTracerProvider(
+			trace.WithSampler(adaptiveSampler{ratio: trace.TraceIDRatioBased(tc.ratio)}),
+			trace.WithSpanProcessor(newTailProcessor(trace.NewSimpleSpanProcessor(exporter), 50*time.Millisecond)))
+
+		tracer := tp.Tracer("test")
+
+		ctx, root := tracer.Start(context.Background(), "GET /orders")
+		_, child := tracer.Start(ctx, "SELECT orders")
+
+		if tc.failed {
+			child.SetStatus(codes.Error, "connection refused")
+		}
+
+		child.End()
+		time.Sleep(tc.duration)
+		root.End()
+
+		spans := exporter.GetSpans()
+
+		assert.Len(t, spans, tc.spans, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		for _, s := range spans {
+			assert.True(t, s.SpanContext.IsSampled(), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+
+		_ = tp.Shutdown(context.Background())
+	}
+}
+
+func TestTailProcessor_PendingSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	p := newTailProcessor(trace.NewSimpleSpanProcessor(exporter), time.Hour)
+
+	tp := trace.NewTracerProvider(trace.WithSampler(adaptiveSampler{ratio: trace.NeverSample()}), trace.WithSpanProcessor(p))
+	tracer := tp.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "GET /orders")
+	_, child := tracer.Start(ctx, "SELECT orders")
+
+	child.End()
+	assert.Len(t, p.pending, 1, "spans of the trace are not held until the root span ends")
+
+	root.End()
+	assert.Empty(t, p.pending)
+	assert.Empty(t, exporter.GetSpans())
+}
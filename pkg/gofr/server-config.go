@@ -0,0 +1,121 @@
+package gofr
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+
+	"gofr.dev/pkg/gofr/responder"
+	"gofr.dev/pkg/log"
+)
+
+// defaultReadHeaderTimeout stops the slow clients, which send the headers slowly to hold the connections, the other
+// timeouts of the HTTP server are not set by default, as they cut the streamed responses.
+const defaultReadHeaderTimeout = 5 * time.Second
+
+// The responses which exceed the serialization limits are responded with an error, or truncated to the limits.
+const (
+	responseLimitError    = "error"
+	responseLimitTruncate = "truncate"
+)
+
+// ServerConfig tunes the connections of an HTTP server, the servers use their defaults for the zero values.
+type ServerConfig struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+
+	ListenConfig
+}
+
+// server returns the HTTP server of the handler, tuned by the config.
+func (cfg *ServerConfig) server(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// getServerConfig reads the config of the HTTP and the HTTPS servers, the timeouts are in seconds.
+func getServerConfig(c Config, logger log.Logger) ServerConfig {
+	return ServerConfig{
+		ReadTimeout:       seconds(c, "HTTP_READ_TIMEOUT", 0),
+		ReadHeaderTimeout: seconds(c, "HTTP_READ_HEADER_TIMEOUT", defaultReadHeaderTimeout),
+		WriteTimeout:      seconds(c, "HTTP_WRITE_TIMEOUT", 0),
+		IdleTimeout:       seconds(c, "HTTP_IDLE_TIMEOUT", 0),
+		MaxHeaderBytes:    positiveInt(c, "HTTP_MAX_HEADER_BYTES"),
+		ListenConfig:      getListenConfig(c, "HTTP", logger),
+	}
+}
+
+// grpcServerOptions returns the limits and the keepalive parameters of the gRPC server set in the config, the
+// defaults of gRPC are used for the parameters which are not set.
+func grpcServerOptions(c Config) []grpc.ServerOption {
+	var opts []grpc.ServerOption
+
+	if n := positiveInt(c, "GRPC_MAX_CONCURRENT_STREAMS"); n > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(uint32(n)))
+	}
+
+	params := keepalive.ServerParameters{
+		MaxConnectionIdle:     seconds(c, "GRPC_MAX_CONNECTION_IDLE", 0),
+		MaxConnectionAge:      seconds(c, "GRPC_MAX_CONNECTION_AGE", 0),
+		MaxConnectionAgeGrace: seconds(c, "GRPC_MAX_CONNECTION_AGE_GRACE", 0),
+		Time:                  seconds(c, "GRPC_KEEPALIVE_TIME", 0),
+		Timeout:               seconds(c, "GRPC_KEEPALIVE_TIMEOUT", 0),
+	}
+
+	if params != (keepalive.ServerParameters{}) {
+		opts = append(opts, grpc.KeepaliveParams(params))
+	}
+
+	minTime := seconds(c, "GRPC_KEEPALIVE_MIN_TIME", 0)
+	permitWithoutStream := strings.EqualFold(c.Get("GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM"), "true")
+
+	// the clients which ping more often than the policy allows are disconnected
+	if minTime > 0 || permitWithoutStream {
+		opts = append(opts, grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             minTime,
+			PermitWithoutStream: permitWithoutStream,
+		}))
+	}
+
+	return opts
+}
+
+func seconds(c Config, key string, defaultValue time.Duration) time.Duration {
+	if s, err := strconv.Atoi(c.Get(key)); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second
+	}
+
+	return defaultValue
+}
+
+func positiveInt(c Config, key string) int {
+	if n, err := strconv.Atoi(c.Get(key)); err == nil && n > 0 {
+		return n
+	}
+
+	return 0
+}
+
+// serializationLimits returns the limits of the responses, the responses which exceed them are responded with an
+// error, or truncated when RESPONSE_LIMIT_MODE is truncate.
+func serializationLimits(c Config) responder.SerializationLimits {
+	return responder.SerializationLimits{
+		MaxDepth: positiveInt(c, "RESPONSE_MAX_DEPTH"),
+		MaxBytes: positiveInt(c, "RESPONSE_MAX_BYTES"),
+		Truncate: strings.EqualFold(c.Get("RESPONSE_LIMIT_MODE"), responseLimitTruncate),
+	}
+}
@@ -0,0 +1,56 @@
+package gofr
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+func TestGetServerConfig(t *testing.T) {
+	tests := []struct {
+		desc     string
+		data     map[string]string
+		expected ServerConfig
+	}{
+		{"defaults", map[string]string{}, ServerConfig{ReadHeaderTimeout: defaultReadHeaderTimeout}},
+		{"configured", map[string]string{"HTTP_READ_TIMEOUT": "10", "HTTP_READ_HEADER_TIMEOUT": "2", "HTTP_WRITE_TIMEOUT": "30",
+			"HTTP_IDLE_TIMEOUT": "120", "HTTP_MAX_HEADER_BYTES": "8192", "HTTP_MAX_CONNECTIONS": "1000"},
+			ServerConfig{ReadTimeout: 10 * time.Second, ReadHeaderTimeout: 2 * time.Second, WriteTimeout: 30 * time.Second,
+				IdleTimeout: 120 * time.Second, MaxHeaderBytes: 8192, ListenConfig: ListenConfig{MaxConnections: 1000}}},
+		{"read header timeout disabled", map[string]string{"HTTP_READ_HEADER_TIMEOUT": "0"}, ServerConfig{}},
+		{"invalid values", map[string]string{"HTTP_READ_TIMEOUT": "-1", "HTTP_MAX_CONNECTIONS": "many"},
+			ServerConfig{ReadHeaderTimeout: defaultReadHeaderTimeout}},
+	}
+
+	for i, tc := range tests {
+		cfg := getServerConfig(&config.MockConfig{Data: tc.data}, log.NewMockLogger(io.Discard))
+
+		assert.Equal(t, tc.expected, cfg, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestGRPCServerOptions(t *testing.T) {
+	tests := []struct {
+		desc    string
+		data    map[string]string
+		options int
+	}{
+		{"defaults", map[string]string{}, 0},
+		{"max concurrent streams", map[string]string{"GRPC_MAX_CONCURRENT_STREAMS": "100"}, 1},
+		{"keepalive", map[string]string{"GRPC_KEEPALIVE_TIME": "60", "GRPC_MAX_CONNECTION_AGE": "300"}, 1},
+		{"all", map[string]string{"GRPC_MAX_CONCURRENT_STREAMS": "100", "GRPC_KEEPALIVE_TIMEOUT": "10",
+			"GRPC_KEEPALIVE_MIN_TIME": "30", "GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM": "true"}, 3},
+	}
+
+	for i, tc := range tests {
+		opts := grpcServerOptions(&config.MockConfig{Data: tc.data})
+
+		assert.Len(t, opts, tc.options, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.NotNil(t, NewGRPCServer(opts...), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,140 @@
+package gofr
+
+import (
+	ctx "context"
+	"strings"
+	"time"
+)
+
+// ShutdownStage is a stage of the shutdown of the application, the stages run in order, and a stage starts once the
+// hooks of the previous stage complete or its timeout is over.
+type ShutdownStage int
+
+const (
+	// ShutdownIntake stops accepting new work, ex: the gRPC requests and the messages of the subscribers. It runs
+	// once the HTTP server is stopped.
+	ShutdownIntake ShutdownStage = iota
+	// ShutdownDrain waits for the work in progress to complete, ex: the messages being processed and the operations
+	// queued using Context.Enqueue.
+	ShutdownDrain
+	// ShutdownClose closes the connections of the application, the hooks of the application run before the
+	// connections of the datastores are closed.
+	ShutdownClose
+)
+
+//nolint:gochecknoglobals // the defaults of the stages are not changed
+var shutdownStages = []struct {
+	name    string
+	timeout time.Duration
+}{
+	ShutdownIntake: {"intake", 5 * time.Second},
+	ShutdownDrain:  {"drain", 30 * time.Second},
+	ShutdownClose:  {"close", 5 * time.Second},
+}
+
+func (s ShutdownStage) String() string {
+	if s < 0 || int(s) >= len(shutdownStages) {
+		return "unknown"
+	}
+
+	return shutdownStages[s].name
+}
+
+// ShutdownHook is run on the shutdown of the application, it must return once the context is done.
+type ShutdownHook func(c ctx.Context) error
+
+type shutdownHook struct {
+	name string
+	run  ShutdownHook
+}
+
+// OnShutdown runs the hook in the stage of the shutdown, ex: app.OnShutdown(gofr.ShutdownDrain, "outbox relay",
+// relay.Stop). The hooks of a stage run in the order in which they are added.
+func (g *Gofr) OnShutdown(stage ShutdownStage, name string, hook ShutdownHook) {
+	if g.shutdownHooks == nil {
+		g.shutdownHooks = make(map[ShutdownStage][]shutdownHook)
+	}
+
+	g.shutdownHooks[stage] = append(g.shutdownHooks[stage], shutdownHook{name: name, run: hook})
+}
+
+// shutdown runs the stages of the shutdown, with the hooks of the framework and the hooks of the application. The
+// timeout of a stage is set using SHUTDOWN_<STAGE>_TIMEOUT in seconds, ex: SHUTDOWN_DRAIN_TIMEOUT.
+func (g *Gofr) shutdown() {
+	for stage, hooks := range g.stageHooks() {
+		name := ShutdownStage(stage).String()
+		timeout := shutdownStages[stage].timeout
+
+		if g.Config != nil {
+			timeout = seconds(g.Config, "SHUTDOWN_"+strings.ToUpper(name)+"_TIMEOUT", timeout)
+		}
+
+		g.Logger.Infof("shutdown stage %v started", name)
+
+		start := time.Now()
+
+		g.runStage(name, timeout, hooks)
+
+		g.Logger.Infof("shutdown stage %v completed in %v", name, time.Since(start))
+	}
+}
+
+// stageHooks returns the hooks of the stages, in the order of the stages.
+func (g *Gofr) stageHooks() [][]shutdownHook {
+	stages := make([][]shutdownHook, len(shutdownStages))
+
+	if g.Server != nil && g.Server.GRPC.server != nil && g.Server.GRPC.Port != 0 {
+		stages[ShutdownIntake] = append(stages[ShutdownIntake], shutdownHook{name: "grpc server", run: g.Server.GRPC.stop})
+	}
+
+	if g.subscriber != nil {
+		stages[ShutdownIntake] = append(stages[ShutdownIntake], shutdownHook{name: "subscribers",
+			run: func(ctx.Context) error { g.subscriber.stopConsuming(); return nil }})
+		stages[ShutdownDrain] = append(stages[ShutdownDrain], shutdownHook{name: "subscribers", run: g.subscriber.drain})
+	}
+
+	if g.operations != nil {
+		stages[ShutdownDrain] = append(stages[ShutdownDrain], shutdownHook{name: "operations", run: g.operations.drain})
+	}
+
+	if g.outbox != nil {
+		stages[ShutdownDrain] = append(stages[ShutdownDrain], shutdownHook{name: "outbox", run: g.outbox.drain})
+	}
+
+	if g.leaks != nil {
+		stages[ShutdownIntake] = append(stages[ShutdownIntake], shutdownHook{name: "leak detector", run: g.leaks.drain})
+	}
+
+	for stage := range stages {
+		stages[stage] = append(stages[stage], g.shutdownHooks[ShutdownStage(stage)]...)
+	}
+
+	stages[ShutdownClose] = append(stages[ShutdownClose], shutdownHook{name: "datastores",
+		run: func(ctx.Context) error { return g.DataStore.Close() }})
+
+	return stages
+}
+
+// runStage runs the hooks in order, the hooks which are not run before the timeout of the stage are skipped.
+func (g *Gofr) runStage(stage string, timeout time.Duration, hooks []shutdownHook) {
+	stageCtx, cancel := ctx.WithTimeout(ctx.Background(), timeout)
+	defer cancel()
+
+	for i, h := range hooks {
+		errs := make(chan error, 1)
+
+		go func(h shutdownHook) { errs <- h.run(stageCtx) }(h)
+
+		select {
+		case err := <-errs:
+			if err != nil {
+				g.Logger.Errorf("shutdown of %v failed: %v", h.name, err)
+			}
+		case <-stageCtx.Done():
+			g.Logger.Errorf("shutdown stage %v timed out after %v on %v, %v hooks are skipped", stage, timeout, h.name,
+				len(hooks)-i-1)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package gofr
+
+import (
+	"bytes"
+	ctx "context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+func TestGofr_ShutdownOrder(t *testing.T) {
+	b := new(bytes.Buffer)
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(b)}
+
+	var order []string
+
+	hook := func(name string) ShutdownHook {
+		return func(ctx.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	g.OnShutdown(ShutdownClose, "cache", hook("cache"))
+	g.OnShutdown(ShutdownDrain, "outbox relay", hook("outbox relay"))
+	g.OnShutdown(ShutdownIntake, "scheduler", hook("scheduler"))
+	g.OnShutdown(ShutdownDrain, "jobs", func(ctx.Context) error {
+		order = append(order, "jobs")
+		return errors.Error("jobs are lost")
+	})
+
+	g.shutdown()
+
+	assert.Equal(t, []string{"scheduler", "outbox relay", "jobs", "cache"}, order)
+	assert.Contains(t, b.String(), "shutdown stage intake started")
+	assert.Contains(t, b.String(), "shutdown stage close completed")
+	assert.Contains(t, b.String(), "shutdown of jobs failed: jobs are lost")
+}
+
+func TestGofr_ShutdownStageTimeout(t *testing.T) {
+	b := new(bytes.Buffer)
+	g := &Gofr{Config: &config.MockConfig{Data: map[string]string{"SHUTDOWN_DRAIN_TIMEOUT": "0"}}, Logger: log.NewMockLogger(b)}
+
+	closed := false
+
+	g.OnShutdown(ShutdownDrain, "relay", func(c ctx.Context) error {
+		<-c.Done()
+		return c.Err()
+	})
+	g.OnShutdown(ShutdownDrain, "skipped", func(ctx.Context) error { return nil })
+	g.OnShutdown(ShutdownClose, "cache", func(ctx.Context) error {
+		closed = true
+		return nil
+	})
+
+	g.shutdown()
+
+	assert.Contains(t, b.String(), "shutdown stage drain timed out after 0s on relay, 1 hooks are skipped")
+	assert.True(t, closed, "the next stage must run after the stage times out")
+}
+
+func TestOperationQueue_Drain(t *testing.T) {
+	q := newOperationQueue(&config.MockConfig{})
+	q.pending.Add(1)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.pending.Done()
+	}()
+
+	assert.NoError(t, q.drain(ctx.Background()))
+
+	q.pending.Add(1)
+	defer q.pending.Done()
+
+	expired, cancel := ctx.WithTimeout(ctx.Background(), time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, ctx.DeadlineExceeded, q.drain(expired))
+}
+
+func TestShutdownStage_String(t *testing.T) {
+	for i, tc := range []struct {
+		stage ShutdownStage
+		name  string
+	}{{ShutdownIntake, "intake"}, {ShutdownDrain, "drain"}, {ShutdownClose, "close"}, {ShutdownStage(5), "unknown"}} {
+		assert.Equal(t, tc.name, tc.stage.String(), "TEST[%d], failed.\n%s", i, tc.name)
+	}
+}
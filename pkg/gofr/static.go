@@ -0,0 +1,43 @@
+package gofr
+
+import (
+	"net/http"
+	"strings"
+
+	"gofr.dev/pkg/gofr/static"
+)
+
+// Static serves the files of the directory under the path prefix, ex: app.Static("/static", "./static"). The templates
+// refer to the fingerprinted files using {{asset "css/app.css"}}, which are cached by the clients for a year, and the
+// precompressed variants of the files, ex: app.css.br and app.css.gz, are served to the clients which accept them.
+func (g *Gofr) Static(prefix, dir string) error {
+	assets, err := static.New(dir, prefix)
+	if err != nil {
+		return err
+	}
+
+	g.assets = assets
+
+	g.addRoute(http.MethodGet, "/"+strings.Trim(prefix, "/")+"/{file:.*}", func(c *Context) (interface{}, error) {
+		f, err := assets.File(c.PathParam("file"), c.Header("Accept-Encoding"))
+		if err != nil {
+			// the files are responded as they are, hence the errors are responded without the file
+			return nil, err
+		}
+
+		return f, nil
+	})
+
+	return nil
+}
+
+// templateFuncs are the functions which can be called from the templates.
+func (c *Context) templateFuncs() map[string]interface{} {
+	funcs := map[string]interface{}{"t": c.Translate}
+
+	if c.Gofr != nil && c.Gofr.assets != nil {
+		funcs["asset"] = c.Gofr.assets.Path
+	}
+
+	return funcs
+}
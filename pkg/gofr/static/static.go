@@ -0,0 +1,186 @@
+// Package static serves the static files of a directory, ex: the stylesheets and the scripts of the web pages. The
+// files are fingerprinted by their content, so that they can be cached by the clients forever, and the precompressed
+// variants of the files are served to the clients which accept them.
+package static
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/template"
+)
+
+const (
+	// immutableMaxAge is the max-age of the fingerprinted files, which change their names when their content changes.
+	immutableMaxAge = 365 * 24 * 60 * 60
+	// hashLength is the length of the fingerprint in the names of the files.
+	hashLength = 8
+)
+
+// encodings are the precompressed variants of the files, in the order of preference.
+//
+//nolint:gochecknoglobals // the encodings are not changed
+var encodings = []struct {
+	name, ext string
+}{{"br", ".br"}, {"gzip", ".gz"}}
+
+// Assets are the static files of a directory served under a path prefix. The fingerprints are computed when the
+// assets are loaded, hence the files changed afterward are served with the fingerprints of their earlier content.
+type Assets struct {
+	dir    string
+	prefix string
+
+	// fingerprinted is the fingerprinted name of a file, ex: css/app.css is css/app.1a2b3c4d.css
+	fingerprinted map[string]string
+	// names is the name of a file by its fingerprinted name
+	names map[string]string
+	// hashes are the hashes of the content of the files, used as their ETags
+	hashes map[string]string
+}
+
+// New loads the files of the directory, which are served under the path prefix, ex: /static. The precompressed
+// variants of a file are the files with the extensions .br and .gz, ex: app.css.br, and are not served on their own.
+func New(dir, prefix string) (*Assets, error) {
+	a := &Assets{
+		dir:           dir,
+		prefix:        "/" + strings.Trim(prefix, "/"),
+		fingerprinted: make(map[string]string),
+		names:         make(map[string]string),
+		hashes:        make(map[string]string),
+	}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || isVariant(p) {
+			return err
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(rel)
+		sum := sha256.Sum256(content)
+		hash := hex.EncodeToString(sum[:])
+
+		ext := path.Ext(name)
+		fingerprinted := strings.TrimSuffix(name, ext) + "." + hash[:hashLength] + ext
+
+		a.fingerprinted[name] = fingerprinted
+		a.names[fingerprinted] = name
+		a.hashes[name] = hash
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func isVariant(p string) bool {
+	for _, e := range encodings {
+		if strings.HasSuffix(p, e.ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Path returns the URL path of the fingerprinted file, ex: Path("css/app.css") is /static/css/app.1a2b3c4d.css. It is
+// used by the templates to refer to the files, ex: <link rel="stylesheet" href="{{asset "css/app.css"}}">. The path of
+// the file itself is returned when the file is not found.
+func (a *Assets) Path(name string) string {
+	name = strings.TrimPrefix(name, "/")
+
+	if f, ok := a.fingerprinted[name]; ok {
+		name = f
+	}
+
+	return a.prefix + "/" + name
+}
+
+// File returns the file of the name, which is either the name of a file or its fingerprinted name, encoded using the
+// accepted encoding, ex: the value of the header Accept-Encoding. The fingerprinted files are cached by the clients
+// for a year, the other files are revalidated using their ETags.
+func (a *Assets) File(name, acceptEncoding string) (template.File, error) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+
+	cacheControl := "no-cache"
+
+	if n, ok := a.names[name]; ok {
+		name = n
+		cacheControl = "public, max-age=" + strconv.Itoa(immutableMaxAge) + ", immutable"
+	}
+
+	hash, ok := a.hashes[name]
+	if !ok {
+		return template.File{}, errors.FileNotFound{FileName: name, Path: a.prefix}
+	}
+
+	file := filepath.Join(a.dir, filepath.FromSlash(name))
+	header := map[string]string{"Cache-Control": cacheControl, "ETag": `"` + hash + `"`}
+
+	for _, e := range encodings {
+		content, err := os.ReadFile(file + e.ext)
+		if err != nil {
+			continue
+		}
+
+		// the clients which do not accept the encoding are served by the caches with the other variants
+		header["Vary"] = "Accept-Encoding"
+
+		if accepts(acceptEncoding, e.name) {
+			header["Content-Encoding"] = e.name
+			header["ETag"] = `"` + hash + "-" + e.name + `"`
+
+			return template.File{Content: content, ContentType: contentType(name), Header: header}, nil
+		}
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return template.File{}, errors.FileNotFound{FileName: name, Path: a.prefix}
+	}
+
+	return template.File{Content: content, ContentType: contentType(name), Header: header}, nil
+}
+
+// accepts reports whether the encoding is in the Accept-Encoding header, and is not refused using q=0.
+func accepts(acceptEncoding, encoding string) bool {
+	for _, v := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(v), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), encoding) {
+			continue
+		}
+
+		q := strings.ReplaceAll(params, " ", "")
+
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+
+	return false
+}
+
+func contentType(name string) string {
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+
+	return "application/octet-stream"
+}
@@ -0,0 +1,112 @@
+package static
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestAssets_Path(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"css/app.css": "body{}", "css/app.css.br": "br"})
+
+	a, err := New(dir, "static/")
+
+	assert.NoError(t, err)
+
+	tests := []struct {
+		desc string
+		name string
+		path string
+	}{
+		{"fingerprinted file", "css/app.css", "/static/css/app.7c98040a.css"},
+		{"leading slash", "/css/app.css", "/static/css/app.7c98040a.css"},
+		{"unknown file", "js/app.js", "/static/js/app.js"},
+		{"precompressed variant is not fingerprinted", "css/app.css.br", "/static/css/app.css.br"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.path, a.Path(tc.name), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestAssets_File(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"css/app.css": "body{}", "css/app.css.br": "br", "css/app.css.gz": "gz",
+		"js/app.js": "alert()"})
+
+	a, err := New(dir, "/static")
+
+	assert.NoError(t, err)
+
+	tests := []struct {
+		desc           string
+		name           string
+		acceptEncoding string
+		content        string
+		encoding       string
+		cacheControl   string
+	}{
+		{"brotli is preferred", "css/app.7c98040a.css", "gzip, br", "br", "br", "public, max-age=31536000, immutable"},
+		{"gzip", "css/app.7c98040a.css", "gzip", "gz", "gzip", "public, max-age=31536000, immutable"},
+		{"brotli is refused", "css/app.7c98040a.css", "br;q=0, gzip", "gz", "gzip", "public, max-age=31536000, immutable"},
+		{"no encoding is accepted", "css/app.css", "", "body{}", "", "no-cache"},
+		{"file without variants", "js/app.js", "br", "alert()", "", "no-cache"},
+	}
+
+	for i, tc := range tests {
+		f, err := a.File(tc.name, tc.acceptEncoding)
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.content, string(f.Content), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.encoding, f.Header["Content-Encoding"], "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.cacheControl, f.Header["Cache-Control"], "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.NotEmpty(t, f.Header["ETag"], "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	f, _ := a.File("css/app.css", "")
+	assert.Equal(t, "text/css; charset=utf-8", f.ContentType)
+	assert.Equal(t, "Accept-Encoding", f.Header["Vary"])
+}
+
+func TestAssets_FileNotFound(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"app.css": "body{}"})
+
+	a, err := New(dir, "/static")
+
+	assert.NoError(t, err)
+
+	for i, name := range []string{"missing.css", "../app.css.go", "app.css.br"} {
+		_, err := a.File(name, "br")
+
+		assert.IsType(t, errors.FileNotFound{}, err, "TEST[%d], failed.\n%s", i, name)
+	}
+}
+
+func TestNew_InvalidDirectory(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing"), "/static")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+package gofr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+func TestGofr_Static(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.css"), []byte("body{}"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.css.gz"), []byte("gz"), 0600))
+
+	c := &config.MockConfig{}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.Server = NewServer(c, g)
+	g.Server.Router.Use(g.Server.contextInjector)
+
+	assert.NoError(t, g.Static("/static", dir))
+
+	tests := []struct {
+		desc           string
+		target         string
+		acceptEncoding string
+		status         int
+		body           string
+	}{
+		{"fingerprinted file", "/static/app.7c98040a.css", "", http.StatusOK, "body{}"},
+		{"precompressed variant", "/static/app.css", "gzip", http.StatusOK, "gz"},
+		{"missing file", "/static/app.js", "", http.StatusNotFound, ""},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, tc.target, http.NoBody)
+		r.Header.Set("Accept-Encoding", tc.acceptEncoding)
+
+		w := httptest.NewRecorder()
+		g.Server.Router.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.status == http.StatusOK {
+			assert.Equal(t, tc.body, w.Body.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+
+	funcs := (&Context{Gofr: g}).templateFuncs()
+
+	assert.Equal(t, "/static/app.7c98040a.css", funcs["asset"].(func(string) string)("app.css"))
+}
@@ -0,0 +1,186 @@
+package gofr
+
+import (
+	ctx "context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"gofr.dev/pkg/datastore/pubsub"
+)
+
+const (
+	defaultBatchSize   = 100
+	defaultBatchWindow = time.Second
+)
+
+// SubscribeBatchHandler processes a batch of the messages consumed from a topic, the batch is retried when an error is
+// returned.
+type SubscribeBatchHandler func(c *Context, msgs []*pubsub.Message) error
+
+/*
+SubscribeBatch registers the handler of the batches of the messages of the topic, which are consumed by the managed
+loop of Subscribe, for the handlers which write to the sinks that prefer batches, ex: ClickHouse.
+
+A batch is passed to the handler once it has size messages (default is 100), or once window (default is 1 second) has
+passed since its first message was consumed, whichever is first. The messages of a batch are committed together once the
+handler returns, by committing the offset of the last message of every partition. A failed batch is retried as a failed
+message of Subscribe, and is given up as a whole.
+*/
+func (g *Gofr) SubscribeBatch(topic string, size int, window time.Duration, handler SubscribeBatchHandler) {
+	g.SubscribeBatchWithOptions(topic, size, window, handler, SubscribeOptions{})
+}
+
+// SubscribeBatchWithOptions registers the handler of the batches of the messages of the topic, like SubscribeBatch, with
+// the messages filtered, and the failed batches handled, as per the options. The messages of a failed batch are published
+// one by one to the DLQ topic.
+func (g *Gofr) SubscribeBatchWithOptions(topic string, size int, window time.Duration, handler SubscribeBatchHandler,
+	options SubscribeOptions) {
+	if g.subscriber == nil {
+		g.subscriber = newSubscriber(g)
+	}
+
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+
+	if window <= 0 {
+		window = defaultBatchWindow
+	}
+
+	g.subscriber.handlers[topic] = subscription{options: options, batchHandler: handler, batchSize: size,
+		batchWindow: window, batches: make(chan *pubsub.Message)}
+}
+
+type partition struct {
+	topic     string
+	partition int
+}
+
+// batch is the batch being collected, offsets are the offsets of the last consumed message of its partitions, including
+// the messages which are filtered out of the batch, so that they are committed along with it.
+type batch struct {
+	messages []*pubsub.Message
+	offsets  map[partition]int64
+}
+
+func (b *batch) add(msg *pubsub.Message, matches bool) {
+	if b.offsets == nil {
+		b.offsets = make(map[partition]int64)
+	}
+
+	b.offsets[partition{topic: msg.Topic, partition: msg.Partition}] = msg.Offset
+
+	if matches {
+		b.messages = append(b.messages, msg)
+	}
+}
+
+// batch collects the messages of the subscription in batches, and processes them. The batch being collected is processed
+// when the application is stopped.
+func (s *subscriber) batch(topic string, sub subscription) {
+	defer s.wg.Done()
+
+	var (
+		b       batch
+		timer   *time.Timer
+		expired <-chan time.Time
+	)
+
+	flush := func() {
+		if timer != nil {
+			timer.Stop()
+		}
+
+		expired = nil
+
+		s.processBatch(topic, sub, b)
+		b = batch{}
+	}
+
+	for {
+		select {
+		case msg := <-sub.batches:
+			if len(b.offsets) == 0 {
+				timer = time.NewTimer(sub.batchWindow)
+				expired = timer.C
+			}
+
+			b.add(msg, sub.matches(msg))
+
+			if len(b.messages) >= sub.batchSize {
+				flush()
+			}
+		case <-expired:
+			flush()
+		case <-s.stop:
+			if len(b.offsets) > 0 {
+				flush()
+			}
+
+			return
+		}
+	}
+}
+
+/*
+processBatch passes the messages of the batch to the handler of the subscription, and commits the batch once it is
+processed, or given up. The batch whose attempts are interrupted by the application stopping is not committed, so that
+it is consumed again.
+*/
+func (s *subscriber) processBatch(topic string, sub subscription, b batch) {
+	if len(b.messages) == 0 {
+		s.commit(b)
+		return
+	}
+
+	// the span of the batch is linked to the traces of its messages, as it has more than one parent
+	links := make([]trace.Link, 0, len(b.messages))
+
+	for _, msg := range b.messages {
+		if sc := trace.SpanContextFromContext(pubsub.ExtractTraceContext(ctx.Background(), msg.Headers)); sc.IsValid() {
+			links = append(links, trace.Link{SpanContext: sc})
+		}
+	}
+
+	span, c := s.context("subscribe batch "+topic, nil, trace.WithLinks(links...))
+	defer span.End()
+
+	desc := fmt.Sprintf("batch of %v messages of topic %v", len(b.messages), topic)
+
+	done := observe(topic, len(b.messages))
+
+	attempts, err := s.retryHandler(c, topic, sub, desc, func(c *Context) error { return sub.batchHandler(c, b.messages) })
+
+	done(err)
+
+	if err == nil {
+		s.commit(b)
+		return
+	}
+
+	c.Logger.Errorf("%v could not be processed: %v", desc, err)
+
+	if attempts < sub.retryPolicy(s.retry).MaxAttempts {
+		return
+	}
+
+	// the batch is not committed when a message of it is not published to the DLQ topic, so that it is consumed again
+	if sub.options.DLQTopic != "" {
+		for _, msg := range b.messages {
+			if !s.deadLetter(c, msg, sub.options.DLQTopic, attempts, err) {
+				return
+			}
+		}
+	}
+
+	s.commit(b)
+}
+
+// commit commits the offset of the last message of every partition of the batch.
+func (s *subscriber) commit(b batch) {
+	for p, offset := range b.offsets {
+		s.g.PubSub.CommitOffset(pubsub.TopicPartition{Topic: p.topic, Partition: p.partition, Offset: offset})
+	}
+}
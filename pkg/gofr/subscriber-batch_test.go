@@ -0,0 +1,150 @@
+package gofr
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+// mockBatchConsumer consumes the messages sent on its channel without committing them, and records the messages
+// published to the DLQ topic.
+type mockBatchConsumer struct {
+	mockDLQ
+}
+
+func newMockBatchConsumer() *mockBatchConsumer {
+	return &mockBatchConsumer{mockDLQ: mockDLQ{mockConsumer: mockConsumer{messages: make(chan *pubsub.Message)},
+		published: make(chan *pubsub.Message, 10)}}
+}
+
+func TestGofr_SubscribeBatch(t *testing.T) {
+	ps := newMockBatchConsumer()
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(io.Discard)}
+	g.PubSub = ps
+
+	batches := make(chan []string, 2)
+	handled := make(chan string, 1)
+
+	g.SubscribeBatch("events", 2, time.Hour, func(_ *Context, msgs []*pubsub.Message) error {
+		values := make([]string, 0, len(msgs))
+		for _, msg := range msgs {
+			values = append(values, msg.Value)
+		}
+
+		batches <- values
+
+		return nil
+	})
+	g.Subscribe("orders", func(_ *Context, msg *pubsub.Message) error {
+		handled <- msg.Value
+		return nil
+	})
+
+	g.subscriber.start()
+
+	ps.messages <- &pubsub.Message{Topic: "events", Partition: 0, Offset: 7, Value: "a"}
+	ps.messages <- &pubsub.Message{Topic: "orders", Partition: 0, Offset: 3, Value: "created"}
+
+	assert.Equal(t, "created", <-handled)
+	assert.Equal(t, []pubsub.TopicPartition{{Topic: "orders", Offset: 3}}, ps.commits(),
+		"the messages of the other subscriptions are committed as they are consumed")
+
+	ps.messages <- &pubsub.Message{Topic: "events", Partition: 1, Offset: 4, Value: "b"}
+
+	assert.Equal(t, []string{"a", "b"}, <-batches)
+
+	ps.messages <- &pubsub.Message{Topic: "events", Partition: 0, Offset: 8, Value: "c"}
+	// the next message is consumed once the previous message is collected in the batch
+	ps.messages <- &pubsub.Message{Topic: "orders", Partition: 0, Offset: 4, Value: "paid"}
+
+	assert.Equal(t, "paid", <-handled)
+
+	g.subscriber.shutdown()
+
+	assert.Equal(t, []string{"c"}, <-batches, "the batch being collected is processed when the application is stopped")
+	assert.ElementsMatch(t, []pubsub.TopicPartition{{Topic: "orders", Offset: 3}, {Topic: "events", Offset: 7},
+		{Topic: "events", Partition: 1, Offset: 4}, {Topic: "orders", Offset: 4}, {Topic: "events", Offset: 8}}, ps.commits())
+}
+
+func TestGofr_SubscribeBatchWindow(t *testing.T) {
+	ps := newMockBatchConsumer()
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(io.Discard)}
+	g.PubSub = ps
+
+	batches := make(chan int, 1)
+
+	g.SubscribeBatchWithOptions("events", 10, 50*time.Millisecond, func(_ *Context, msgs []*pubsub.Message) error {
+		batches <- len(msgs)
+		return nil
+	}, SubscribeOptions{Filters: []MessageFilter{HeaderEquals("type", "click")}})
+
+	g.subscriber.start()
+
+	ps.messages <- &pubsub.Message{Topic: "events", Offset: 1, Headers: map[string]string{"type": "click"}}
+	ps.messages <- &pubsub.Message{Topic: "events", Offset: 2, Headers: map[string]string{"type": "view"}}
+
+	assert.Equal(t, 1, <-batches, "the batch is processed once the window has passed")
+
+	g.subscriber.shutdown()
+
+	assert.Equal(t, []pubsub.TopicPartition{{Topic: "events", Offset: 2}}, ps.commits(),
+		"the messages which are filtered out are committed along with the batch")
+}
+
+func TestGofr_SubscribeBatchDLQ(t *testing.T) {
+	ps := newMockBatchConsumer()
+	c := &config.MockConfig{Data: map[string]string{"SUBSCRIBER_RETRY_BACKOFF": "1"}}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.PubSub = ps
+
+	var tries int
+
+	g.SubscribeBatchWithOptions("events", 2, time.Hour, func(*Context, []*pubsub.Message) error {
+		tries++
+		return errors.Error("sink is down")
+	}, SubscribeOptions{MaxAttempts: 2, DLQTopic: "events-dlq"})
+
+	g.subscriber.start()
+
+	ps.messages <- &pubsub.Message{Topic: "events", Offset: 1, Key: "1", Value: "a"}
+	ps.messages <- &pubsub.Message{Topic: "events", Offset: 2, Key: "2", Value: "b"}
+
+	first, second := <-ps.published, <-ps.published
+
+	g.subscriber.shutdown()
+
+	assert.Equal(t, 2, tries)
+	assert.Equal(t, "a", first.Value)
+	assert.Equal(t, "b", second.Value)
+	assert.Equal(t, "sink is down", second.Headers[dlqHeaderError])
+	assert.Equal(t, []pubsub.TopicPartition{{Topic: "events", Offset: 2}}, ps.commits(), "the batch is committed once it is given up")
+}
+
+func TestGofr_SubscribeBatchDLQ_PublishError(t *testing.T) {
+	ps := newMockBatchConsumer()
+	ps.err = errors.Error("broker down")
+	g := &Gofr{Config: &config.MockConfig{Data: map[string]string{"SUBSCRIBER_RETRY_BACKOFF": "1"}},
+		Logger: log.NewMockLogger(io.Discard)}
+	g.PubSub = ps
+
+	g.SubscribeBatchWithOptions("events", 1, time.Hour, func(*Context, []*pubsub.Message) error {
+		return errors.Error("sink is down")
+	}, SubscribeOptions{MaxAttempts: 1, DLQTopic: "events-dlq"})
+
+	g.subscriber.start()
+
+	ps.messages <- &pubsub.Message{Topic: "events", Offset: 1, Value: "a"}
+
+	<-ps.published
+
+	g.subscriber.shutdown()
+
+	assert.Empty(t, ps.commits(), "the batch is not committed when it is not published to the DLQ topic")
+}
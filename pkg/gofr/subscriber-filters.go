@@ -0,0 +1,198 @@
+package gofr
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/datastore/pubsub"
+)
+
+//nolint:gochecknoglobals // the metrics have to be global variables for prometheus
+var (
+	subscriberFiltered = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_subscriber_filtered_total",
+		Help: "Counter for the messages which are skipped by the filters of their subscription",
+	}, []string{"topic"})
+
+	_ = prometheus.Register(subscriberFiltered)
+)
+
+// MessageFilter returns whether a consumed message is passed to the handler of its subscription. The messages which are
+// filtered out are skipped before the handler is called, ex: the messages of other tenants on a shared topic.
+type MessageFilter func(msg *pubsub.Message) bool
+
+// HeaderEquals filters the messages which have the header with the value.
+func HeaderEquals(key, value string) MessageFilter {
+	return func(msg *pubsub.Message) bool {
+		v, ok := header(msg, key)
+
+		return ok && v == value
+	}
+}
+
+// HeaderIn filters the messages which have the header with one of the values, ex: HeaderIn("event-type",
+// "order.created", "order.updated").
+func HeaderIn(key string, values ...string) MessageFilter {
+	return func(msg *pubsub.Message) bool {
+		v, ok := header(msg, key)
+		if !ok {
+			return false
+		}
+
+		for _, value := range values {
+			if v == value {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// HeaderMatches filters the messages which have the header with a value matching the pattern, the * of the pattern
+// matches any sequence of characters, ex: HeaderMatches("event-type", "order.*").
+func HeaderMatches(key, pattern string) MessageFilter {
+	return func(msg *pubsub.Message) bool {
+		v, ok := header(msg, key)
+
+		return ok && matchPattern(pattern, v)
+	}
+}
+
+// header returns the value of the header, the key is matched without case when the message does not have it as is, as
+// the backends do not all keep the case of the headers.
+func header(msg *pubsub.Message, key string) (string, bool) {
+	if v, ok := msg.Headers[key]; ok {
+		return v, true
+	}
+
+	for k, v := range msg.Headers {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// matchPattern matches the value against the pattern, whose * matches any sequence of characters, including slashes.
+func matchPattern(pattern, value string) bool {
+	// path.Match does not match the slashes with *, hence they are replaced by a character which is not a meta character
+	const slash = "\x00"
+
+	ok, err := path.Match(strings.ReplaceAll(pattern, "/", slash), strings.ReplaceAll(value, "/", slash))
+
+	return err == nil && ok
+}
+
+/*
+JSONPath filters the messages whose JSON value has a value at the path for which the predicate is true. The path is
+a dot separated list of fields and array indexes, ex: $.order.items[0].sku, the messages which are not JSON, or do not
+have the path, are filtered out.
+
+The value is decoded as by encoding/json, hence the numbers are float64.
+*/
+func JSONPath(path string, predicate func(value interface{}) bool) MessageFilter {
+	steps := parseJSONPath(path)
+
+	return func(msg *pubsub.Message) bool {
+		var doc interface{}
+
+		if err := json.Unmarshal([]byte(msg.Value), &doc); err != nil {
+			return false
+		}
+
+		value, ok := lookupJSONPath(doc, steps)
+
+		return ok && predicate(value)
+	}
+}
+
+// JSONPathEquals filters the messages whose JSON value has the value at the path, the value is compared as JSON, so
+// that the int 5 equals the number 5 of the message.
+func JSONPathEquals(path string, value interface{}) MessageFilter {
+	var expected interface{}
+
+	if b, err := json.Marshal(value); err == nil {
+		_ = json.Unmarshal(b, &expected)
+	}
+
+	return JSONPath(path, func(v interface{}) bool { return reflect.DeepEqual(expected, v) })
+}
+
+// JSONPathExists filters the messages whose JSON value has a value at the path, including null.
+func JSONPathExists(path string) MessageFilter {
+	return JSONPath(path, func(interface{}) bool { return true })
+}
+
+// parseJSONPath returns the fields and the array indexes of the path, the indexes are the steps which are ints.
+func parseJSONPath(path string) []interface{} {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+
+	steps := make([]interface{}, 0)
+
+	for _, field := range strings.Split(path, ".") {
+		name, indexes, _ := strings.Cut(field, "[")
+		if name != "" {
+			steps = append(steps, name)
+		}
+
+		if indexes == "" {
+			continue
+		}
+
+		for _, index := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+			if i, err := strconv.Atoi(index); err == nil {
+				steps = append(steps, i)
+			} else {
+				// the keys which are quoted in the brackets, ex: ['order-id']
+				steps = append(steps, strings.Trim(index, `'"`))
+			}
+		}
+	}
+
+	return steps
+}
+
+func lookupJSONPath(doc interface{}, steps []interface{}) (interface{}, bool) {
+	for _, step := range steps {
+		switch s := step.(type) {
+		case string:
+			obj, ok := doc.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			if doc, ok = obj[s]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := doc.([]interface{})
+			if !ok || s < 0 || s >= len(arr) {
+				return nil, false
+			}
+
+			doc = arr[s]
+		}
+	}
+
+	return doc, true
+}
+
+// matches returns whether the message passes all the filters of the subscription. The messages which do not match are
+// counted as filtered.
+func (sub subscription) matches(msg *pubsub.Message) bool {
+	for _, f := range sub.options.Filters {
+		if !f(msg) {
+			subscriberFiltered.WithLabelValues(msg.Topic).Inc()
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,121 @@
+package gofr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+func TestMessageFilters(t *testing.T) {
+	msg := &pubsub.Message{Headers: map[string]string{"tenant": "acme"},
+		Value: `{"type":"order.created","order":{"total":5,"items":[{"sku":"A-1"}],"order-id":"o-1","coupon":null}}`}
+
+	testcases := []struct {
+		desc   string
+		filter MessageFilter
+		exp    bool
+	}{
+		{"header equals", HeaderEquals("tenant", "acme"), true},
+		{"header differs", HeaderEquals("tenant", "globex"), false},
+		{"header is missing", HeaderEquals("region", ""), false},
+		{"field equals", JSONPathEquals("$.type", "order.created"), true},
+		{"number equals", JSONPathEquals("$.order.total", 5), true},
+		{"array index", JSONPathEquals("$.order.items[0].sku", "A-1"), true},
+		{"quoted key", JSONPathEquals("$.order['order-id']", "o-1"), true},
+		{"index out of range", JSONPathExists("$.order.items[1]"), false},
+		{"null exists", JSONPathExists("$.order.coupon"), true},
+		{"field is missing", JSONPathExists("$.customer"), false},
+		{"field is not an object", JSONPathExists("$.type.name"), false},
+		{"predicate", JSONPath("order.total", func(v interface{}) bool { return v.(float64) > 3 }), true},
+	}
+
+	for i, tc := range testcases {
+		assert.Equal(t, tc.exp, tc.filter(msg), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.False(t, JSONPathExists("$")(&pubsub.Message{Value: "not json"}), "the messages which are not JSON are filtered out")
+}
+
+func TestHeaderFilters(t *testing.T) {
+	msg := &pubsub.Message{Headers: map[string]string{"Event-Type": "order.created", "source": "shop/eu"}}
+
+	testcases := []struct {
+		desc   string
+		filter MessageFilter
+		exp    bool
+	}{
+		{"key without case", HeaderEquals("event-type", "order.created"), true},
+		{"value in", HeaderIn("event-type", "order.updated", "order.created"), true},
+		{"value not in", HeaderIn("event-type", "order.updated"), false},
+		{"missing header in", HeaderIn("region", ""), false},
+		{"exact pattern", HeaderMatches("event-type", "order.created"), true},
+		{"wildcard pattern", HeaderMatches("event-type", "order.*"), true},
+		{"pattern differs", HeaderMatches("event-type", "payment.*"), false},
+		{"wildcard across slashes", HeaderMatches("source", "shop*"), true},
+		{"invalid pattern", HeaderMatches("event-type", "order.["), false},
+		{"missing header pattern", HeaderMatches("region", "*"), false},
+	}
+
+	for i, tc := range testcases {
+		assert.Equal(t, tc.exp, tc.filter(msg), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestSubscription_Matches(t *testing.T) {
+	sub := subscription{options: SubscribeOptions{
+		Filters: []MessageFilter{HeaderMatches("event-type", "order.*"), HeaderEquals("tenant", "acme")}}}
+
+	testcases := []struct {
+		desc    string
+		headers map[string]string
+		exp     bool
+	}{
+		{"filters match", map[string]string{"event-type": "order.created", "tenant": "acme"}, true},
+		{"header does not match", map[string]string{"event-type": "payment.created", "tenant": "acme"}, false},
+		{"header is missing", map[string]string{"tenant": "acme"}, false},
+		{"filter does not match", map[string]string{"event-type": "order.created", "tenant": "globex"}, false},
+	}
+
+	for i, tc := range testcases {
+		msg := &pubsub.Message{Topic: "orders", Headers: tc.headers}
+
+		assert.Equal(t, tc.exp, sub.matches(msg), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestGofr_SubscribeFilters(t *testing.T) {
+	consumer := &mockConsumer{messages: make(chan *pubsub.Message)}
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(new(bytes.Buffer))}
+	g.PubSub = consumer
+
+	handled := make(chan string, 3)
+
+	g.SubscribeWithOptions("orders", func(_ *Context, msg *pubsub.Message) error {
+		handled <- msg.Key
+
+		return nil
+	}, SubscribeOptions{Filters: []MessageFilter{HeaderEquals("tenant", "acme"), JSONPathEquals("$.status", "paid")}})
+
+	g.subscriber.start()
+
+	for _, msg := range []*pubsub.Message{
+		{Topic: "orders", Key: "1", Value: `{"status":"paid"}`, Headers: map[string]string{"tenant": "acme"}},
+		{Topic: "orders", Key: "2", Value: `{"status":"paid"}`, Headers: map[string]string{"tenant": "globex"}},
+		{Topic: "orders", Key: "3", Value: `{"status":"open"}`, Headers: map[string]string{"tenant": "acme"}},
+		{Topic: "orders", Key: "4", Value: `{"status":"paid"}`, Headers: map[string]string{"tenant": "acme"}},
+	} {
+		consumer.messages <- msg
+	}
+
+	assert.Equal(t, "1", <-handled)
+	assert.Equal(t, "4", <-handled)
+
+	g.subscriber.shutdown()
+
+	assert.Empty(t, handled, "the messages which are filtered out are not handled")
+}
@@ -0,0 +1,119 @@
+package gofr
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const defaultRetryMultiplier = 2
+
+//nolint:gochecknoglobals // the metrics have to be global variables for prometheus
+var (
+	subscriberRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_subscriber_retries_total",
+		Help: "Counter for the retries of the messages, and of the batches, whose handlers failed",
+	}, []string{"topic"})
+
+	_ = prometheus.Register(subscriberRetries)
+)
+
+/*
+RetryPolicy is how a failed message, or batch, of a subscription is retried before it is given up, ie: published to its
+DLQ topic, or logged. The delay before a retry is the delay before the previous retry times the multiplier, capped by
+the max delay, and randomized by the jitter. The fields which are not set are the defaults of the subscriber.
+*/
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a message is processed before it is given up, default is SUBSCRIBER_RETRIES + 1.
+	MaxAttempts int
+	// InitialDelay is the delay before the first retry, default is SUBSCRIBER_RETRY_BACKOFF milliseconds (100).
+	InitialDelay time.Duration
+	// Multiplier multiplies the delay on every retry, default is SUBSCRIBER_RETRY_MULTIPLIER (2).
+	Multiplier float64
+	// MaxDelay is the maximum delay before a retry, default is SUBSCRIBER_RETRY_MAX_BACKOFF milliseconds, the delay is
+	// not capped when it is 0.
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay it is randomized by, ex: 0.2 waits for 80% to 120% of the delay, so that the
+	// retries of the consumers which fail together are spread. Default is SUBSCRIBER_RETRY_JITTER (0).
+	Jitter float64
+}
+
+// retryPolicy reads the default retry policy of the subscriptions from the configs, the invalid configs are ignored.
+func retryPolicy(c Config) RetryPolicy {
+	p := RetryPolicy{MaxAttempts: defaultSubscriberRetries + 1, InitialDelay: defaultSubscriberBackoff,
+		Multiplier: defaultRetryMultiplier}
+
+	if c == nil {
+		return p
+	}
+
+	if n, err := strconv.Atoi(c.Get("SUBSCRIBER_RETRIES")); err == nil && n >= 0 {
+		p.MaxAttempts = n + 1
+	}
+
+	if ms, err := strconv.Atoi(c.Get("SUBSCRIBER_RETRY_BACKOFF")); err == nil && ms > 0 {
+		p.InitialDelay = time.Duration(ms) * time.Millisecond
+	}
+
+	if f, err := strconv.ParseFloat(c.Get("SUBSCRIBER_RETRY_MULTIPLIER"), 64); err == nil && f >= 1 {
+		p.Multiplier = f
+	}
+
+	if ms, err := strconv.Atoi(c.Get("SUBSCRIBER_RETRY_MAX_BACKOFF")); err == nil && ms > 0 {
+		p.MaxDelay = time.Duration(ms) * time.Millisecond
+	}
+
+	if f, err := strconv.ParseFloat(c.Get("SUBSCRIBER_RETRY_JITTER"), 64); err == nil && f > 0 && f <= 1 {
+		p.Jitter = f
+	}
+
+	return p
+}
+
+// withDefaults returns the policy with the fields which are not set taken from the defaults.
+func (p RetryPolicy) withDefaults(defaults RetryPolicy) RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = defaults.InitialDelay
+	}
+
+	if p.Multiplier < 1 {
+		p.Multiplier = defaults.Multiplier
+	}
+
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+
+	if p.Jitter <= 0 || p.Jitter > 1 {
+		p.Jitter = defaults.Jitter
+	}
+
+	return p
+}
+
+// delay returns the delay before the retry which follows the attempt, the first attempt being 1.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(attempt-1))
+
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		//nolint:gosec // the jitter does not need a cryptographically secure random number
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+
+	if d > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(d)
+}
@@ -0,0 +1,56 @@
+package gofr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+)
+
+func Test_retryPolicy(t *testing.T) {
+	testcases := []struct {
+		desc     string
+		configs  map[string]string
+		expected RetryPolicy
+	}{
+		{"defaults", map[string]string{}, RetryPolicy{MaxAttempts: 4, InitialDelay: 100 * time.Millisecond, Multiplier: 2}},
+		{"configs", map[string]string{"SUBSCRIBER_RETRIES": "0", "SUBSCRIBER_RETRY_BACKOFF": "50",
+			"SUBSCRIBER_RETRY_MULTIPLIER": "1.5", "SUBSCRIBER_RETRY_MAX_BACKOFF": "1000", "SUBSCRIBER_RETRY_JITTER": "0.2"},
+			RetryPolicy{MaxAttempts: 1, InitialDelay: 50 * time.Millisecond, Multiplier: 1.5, MaxDelay: time.Second, Jitter: 0.2}},
+		{"invalid configs", map[string]string{"SUBSCRIBER_RETRY_MULTIPLIER": "0.5", "SUBSCRIBER_RETRY_JITTER": "2"},
+			RetryPolicy{MaxAttempts: 4, InitialDelay: 100 * time.Millisecond, Multiplier: 2}},
+	}
+
+	for i, tc := range testcases {
+		assert.Equal(t, tc.expected, retryPolicy(&config.MockConfig{Data: tc.configs}), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestRetryPolicy_withDefaults(t *testing.T) {
+	defaults := RetryPolicy{MaxAttempts: 4, InitialDelay: 100 * time.Millisecond, Multiplier: 2, Jitter: 0.1}
+
+	p := RetryPolicy{MaxAttempts: 2, MaxDelay: time.Second}.withDefaults(defaults)
+
+	assert.Equal(t, RetryPolicy{MaxAttempts: 2, InitialDelay: 100 * time.Millisecond, Multiplier: 2, MaxDelay: time.Second,
+		Jitter: 0.1}, p)
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	p := RetryPolicy{InitialDelay: 100 * time.Millisecond, Multiplier: 3, MaxDelay: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, p.delay(1))
+	assert.Equal(t, 300*time.Millisecond, p.delay(2))
+	assert.Equal(t, 900*time.Millisecond, p.delay(3))
+	assert.Equal(t, time.Second, p.delay(4), "the delay is capped by the max delay")
+
+	p.Jitter = 0.5
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		d := p.delay(1)
+
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond, "the delay is randomized within the jitter")
+		assert.LessOrEqual(t, d, 150*time.Millisecond, "the delay is randomized within the jitter")
+	}
+}
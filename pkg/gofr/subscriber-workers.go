@@ -0,0 +1,124 @@
+package gofr
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/datastore/pubsub"
+)
+
+// Ordering is the order in which the workers of a subscription process its messages.
+type Ordering int
+
+const (
+	// OrderByKey processes the messages of a key by the same worker, in the order they are consumed. It is the default.
+	OrderByKey Ordering = iota
+	// OrderByPartition processes the messages of a partition by the same worker, in the order they are consumed, ex: for
+	// the handlers which rely on the order of the partitions of a Kafka topic.
+	OrderByPartition
+	// Unordered processes a message by any worker which is free, so that a slow message does not hold up the messages
+	// behind it, the messages are not processed in order.
+	Unordered
+)
+
+//nolint:gochecknoglobals // the metrics have to be global variables for prometheus
+var (
+	subscriberInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "zs_subscriber_in_flight_messages",
+		Help: "Gauge for the messages being processed by the subscribers",
+	}, []string{"topic"})
+
+	subscriberProcessing = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "zs_subscriber_processing_duration_seconds",
+		Help:    "Histogram for the duration of processing a message, or a batch, including its retries",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"topic", "status"})
+
+	_ = prometheus.Register(subscriberInFlight)
+	_ = prometheus.Register(subscriberProcessing)
+)
+
+// workerPool is the workers which process the messages of the subscriptions, the ordered pools have a channel for every
+// worker, and the unordered pools have a channel shared by all the workers.
+type workerPool struct {
+	size     int
+	ordering Ordering
+	messages []chan *pubsub.Message
+}
+
+func newWorkerPool(size int, ordering Ordering) *workerPool {
+	channels := size
+	if ordering == Unordered {
+		channels = 1
+	}
+
+	p := &workerPool{size: size, ordering: ordering, messages: make([]chan *pubsub.Message, channels)}
+	for i := range p.messages {
+		p.messages[i] = make(chan *pubsub.Message)
+	}
+
+	return p
+}
+
+// channel returns the channel of the worker of the message, so that the messages of a key, or of a partition, are
+// processed in order.
+func (p *workerPool) channel(msg *pubsub.Message) chan<- *pubsub.Message {
+	if len(p.messages) == 1 {
+		return p.messages[0]
+	}
+
+	key := msg.Key
+	if p.ordering == OrderByPartition {
+		key = msg.Topic + "/" + strconv.Itoa(msg.Partition)
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return p.messages[h.Sum32()%uint32(len(p.messages))]
+}
+
+// start starts the workers of the pool.
+func (s *subscriber) startPool(p *workerPool) {
+	for i := 0; i < p.size; i++ {
+		s.wg.Add(1)
+
+		go s.work(p.messages[i%len(p.messages)])
+	}
+}
+
+// pool returns the workers of the subscription, the subscriptions which set neither the concurrency, nor the ordering,
+// share the workers of SUBSCRIBER_CONCURRENCY.
+func (s *subscriber) pool(options SubscribeOptions) *workerPool {
+	if options.Concurrency <= 0 && options.Ordering == OrderByKey {
+		return s.workers
+	}
+
+	size := options.Concurrency
+	if size <= 0 {
+		size = s.workers.size
+	}
+
+	return newWorkerPool(size, options.Ordering)
+}
+
+// observe records the messages of the topic being processed, it returns the function which records their processing once
+// it is completed.
+func observe(topic string, messages int) func(err error) {
+	start := time.Now()
+
+	subscriberInFlight.WithLabelValues(topic).Add(float64(messages))
+
+	return func(err error) {
+		status := "success"
+		if err != nil {
+			status = "failure"
+		}
+
+		subscriberInFlight.WithLabelValues(topic).Sub(float64(messages))
+		subscriberProcessing.WithLabelValues(topic, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,113 @@
+package gofr
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+func TestWorkerPool_channel(t *testing.T) {
+	byPartition := newWorkerPool(4, OrderByPartition)
+
+	assert.Len(t, byPartition.messages, 4)
+	assert.Equal(t, byPartition.channel(&pubsub.Message{Topic: "orders", Partition: 1, Key: "a"}),
+		byPartition.channel(&pubsub.Message{Topic: "orders", Partition: 1, Key: "b"}),
+		"the messages of a partition are processed by the same worker")
+
+	byKey := newWorkerPool(4, OrderByKey)
+
+	assert.Equal(t, byKey.channel(&pubsub.Message{Partition: 1, Key: "a"}), byKey.channel(&pubsub.Message{Partition: 2, Key: "a"}),
+		"the messages of a key are processed by the same worker")
+
+	unordered := newWorkerPool(4, Unordered)
+
+	assert.Len(t, unordered.messages, 1, "the workers share the channel of the messages")
+	assert.Equal(t, 4, unordered.size)
+}
+
+func TestSubscriber_pool(t *testing.T) {
+	s := newSubscriber(&Gofr{Config: &config.MockConfig{Data: map[string]string{"SUBSCRIBER_CONCURRENCY": "3"}}})
+
+	tests := []struct {
+		options  SubscribeOptions
+		size     int
+		ordering Ordering
+		shared   bool
+	}{
+		{SubscribeOptions{}, 3, OrderByKey, true},
+		{SubscribeOptions{Concurrency: 8}, 8, OrderByKey, false},
+		{SubscribeOptions{Ordering: OrderByPartition}, 3, OrderByPartition, false},
+		{SubscribeOptions{Concurrency: 2, Ordering: Unordered}, 2, Unordered, false},
+	}
+
+	for i, tc := range tests {
+		p := s.pool(tc.options)
+
+		assert.Equal(t, tc.size, p.size, "TEST[%d], failed.\n", i)
+		assert.Equal(t, tc.ordering, p.ordering, "TEST[%d], failed.\n", i)
+		assert.Equal(t, tc.shared, p == s.workers, "TEST[%d], failed.\n", i)
+	}
+}
+
+func TestGofr_SubscribeWithOptions_Concurrency(t *testing.T) {
+	consumer := &mockConsumer{messages: make(chan *pubsub.Message)}
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(new(bytes.Buffer))}
+	g.PubSub = consumer
+
+	started, release := make(chan string, 2), make(chan struct{})
+
+	g.SubscribeWithOptions("exports", func(_ *Context, msg *pubsub.Message) error {
+		started <- msg.Value
+		<-release
+
+		if msg.Value == "b" {
+			return errors.Error("disk full")
+		}
+
+		return nil
+	}, SubscribeOptions{Concurrency: 2, Ordering: Unordered, MaxAttempts: 1})
+
+	g.subscriber.start()
+
+	consumer.messages <- &pubsub.Message{Topic: "exports", Key: "1", Value: "a"}
+	consumer.messages <- &pubsub.Message{Topic: "exports", Key: "1", Value: "b"}
+
+	// both the messages of the key are processed at once, as the subscription is unordered
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("the messages were not processed concurrently")
+		}
+	}
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(subscriberInFlight.WithLabelValues("exports")))
+
+	close(release)
+	g.subscriber.shutdown()
+
+	assert.Equal(t, float64(0), testutil.ToFloat64(subscriberInFlight.WithLabelValues("exports")))
+	assert.Equal(t, uint64(1), samples(t, "exports", "success"))
+	assert.Equal(t, uint64(1), samples(t, "exports", "failure"))
+}
+
+// samples returns the number of the observations of the processing of the messages of the topic.
+func samples(t *testing.T, topic, status string) uint64 {
+	m := &dto.Metric{}
+
+	if err := subscriberProcessing.WithLabelValues(topic, status).(prometheus.Histogram).Write(m); err != nil {
+		t.Fatalf("metric could not be read: %v", err)
+	}
+
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,463 @@
+package gofr
+
+import (
+	ctx "context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/log"
+)
+
+const (
+	defaultSubscriberRetries = 3
+	defaultSubscriberBackoff = 100 * time.Millisecond
+	subscriberStopTimeout    = 5 * time.Second
+
+	dlqHeaderTopic     = "X-Dead-Letter-Topic"
+	dlqHeaderPartition = "X-Dead-Letter-Partition"
+	dlqHeaderOffset    = "X-Dead-Letter-Offset"
+	dlqHeaderAttempts  = "X-Dead-Letter-Attempts"
+	dlqHeaderError     = "X-Dead-Letter-Error"
+	dlqHeaderTime      = "X-Dead-Letter-Time"
+)
+
+// SubscribeHandler processes a message consumed from a topic, the message is retried when an error is returned.
+type SubscribeHandler func(c *Context, msg *pubsub.Message) error
+
+// Subscribe registers the handler of the messages of the topic, which are consumed from the pubsub of the application
+// by a managed loop, once the application is started. The topic must be one of the topics the pubsub consumes from, ex:
+// KAFKA_TOPIC, the messages of all the topics are passed to the handler, when it is the only handler.
+//
+// The messages are processed by SUBSCRIBER_CONCURRENCY workers (default is 1) shared by the subscriptions, the messages
+// of the same key are processed by the same worker, so that their order is kept. A subscription can have its own
+// workers with SubscribeWithOptions, so that a slow handler does not hold up the messages of the other subscriptions. A
+// failed message is retried SUBSCRIBER_RETRIES times (default is 3), waiting for SUBSCRIBER_RETRY_BACKOFF milliseconds
+// (default is 100) multiplied by SUBSCRIBER_RETRY_MULTIPLIER (default is 2) on every retry, and is logged when it still
+// fails, or is published to a DLQ topic with SubscribeWithOptions, which can also set the RetryPolicy of the
+// subscription. The messages being processed are completed when the application is stopped. A message is committed once
+// it is handled, or given up, so that the message which is not processed before the application is stopped is consumed
+// again.
+func (g *Gofr) Subscribe(topic string, handler SubscribeHandler) {
+	g.SubscribeWithOptions(topic, handler, SubscribeOptions{})
+}
+
+// SubscribeOptions configures which messages of a subscription are handled, and how the failed messages are handled.
+type SubscribeOptions struct {
+	// MaxAttempts is the number of times a message is processed before it is given up, default is SUBSCRIBER_RETRIES + 1.
+	//
+	// Deprecated: use Retry.MaxAttempts instead, it is used when Retry.MaxAttempts is not set.
+	MaxAttempts int
+	// Retry is how a failed message is retried before it is given up, the fields which are not set are the defaults of
+	// the SUBSCRIBER_RETRY* configs.
+	Retry RetryPolicy
+	// DLQTopic is the topic a message is published to when it is given up, along with the X-Dead-Letter-* headers
+	// describing the failure, so that it can be inspected and replayed. The message is only logged when it is empty.
+	DLQTopic string
+	// Filters are the filters a message has to pass to be handled, ex: HeaderEquals("tenant", "acme") or
+	// HeaderMatches("event-type", "order.*"). The messages which do not pass the filters are committed and skipped,
+	// without calling the handler or being traced. All the messages are handled when it is empty.
+	Filters []MessageFilter
+	// Concurrency is the number of workers of the subscription, which are not shared with the other subscriptions. The
+	// subscription shares the workers of SUBSCRIBER_CONCURRENCY when it is 0. It is ignored by SubscribeBatch.
+	Concurrency int
+	// Ordering is the order in which the workers of the subscription process its messages, default is OrderByKey. The
+	// subscription has its own workers when it is set, SUBSCRIBER_CONCURRENCY of them when Concurrency is 0.
+	Ordering Ordering
+}
+
+// SubscribeWithOptions registers the handler of the messages of the topic, like Subscribe, with the failed messages
+// handled as per the options.
+func (g *Gofr) SubscribeWithOptions(topic string, handler SubscribeHandler, options SubscribeOptions) {
+	if g.subscriber == nil {
+		g.subscriber = newSubscriber(g)
+	}
+
+	g.subscriber.handlers[topic] = subscription{handler: handler, options: options, workers: g.subscriber.pool(options)}
+}
+
+/*
+UsePubSubInterceptors wraps the publishes and the subscribes of the pubsub of the application in the interceptors, the
+same way UseMiddleware wraps the HTTP handlers, ex: to propagate the tracing headers, or to encrypt the payloads. The
+first interceptor is the outermost, and the interceptors of later calls are inside the interceptors of earlier calls.
+
+It is called once the pubsub is initialized, ie: after New, the pubsub which is reconnected by the retries of a failed
+connection is not intercepted.
+*/
+func (g *Gofr) UsePubSubInterceptors(interceptors ...pubsub.Interceptor) {
+	if g.PubSub == nil {
+		g.Logger.Errorf("pubsub interceptors are not used, as pubsub is not configured")
+		return
+	}
+
+	g.PubSub = pubsub.Intercept(g.PubSub, interceptors...)
+}
+
+type subscription struct {
+	handler SubscribeHandler
+	options SubscribeOptions
+	workers *workerPool
+
+	// batchHandler, batchSize and batchWindow are set for the subscriptions of SubscribeBatch, whose messages are sent
+	// on batches to be collected in batches
+	batchHandler SubscribeBatchHandler
+	batchSize    int
+	batchWindow  time.Duration
+	batches      chan *pubsub.Message
+}
+
+// retryPolicy returns the retry policy of the messages, or the batches, of the subscription.
+func (sub subscription) retryPolicy(defaults RetryPolicy) RetryPolicy {
+	p := sub.options.Retry
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = sub.options.MaxAttempts
+	}
+
+	return p.withDefaults(defaults)
+}
+
+type subscriber struct {
+	g        *Gofr
+	handlers map[string]subscription
+	workers  *workerPool
+	retry    RetryPolicy
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newSubscriber(g *Gofr) *subscriber {
+	s := &subscriber{
+		g:        g,
+		handlers: make(map[string]subscription),
+		retry:    retryPolicy(g.Config),
+		stop:     make(chan struct{}),
+	}
+
+	concurrency := 1
+
+	if g.Config != nil {
+		if n, err := strconv.Atoi(g.Config.Get("SUBSCRIBER_CONCURRENCY")); err == nil && n > 0 {
+			concurrency = n
+		}
+	}
+
+	s.workers = newWorkerPool(concurrency, OrderByKey)
+
+	return s
+}
+
+// start starts the workers and the loop which consumes the messages.
+func (s *subscriber) start() {
+	if s.g.PubSub == nil {
+		s.g.Logger.Errorf("subscriptions of %v topics are not started, as pubsub is not configured", len(s.handlers))
+		return
+	}
+
+	s.startPool(s.workers)
+
+	for topic, sub := range s.handlers {
+		switch {
+		case sub.batches != nil:
+			s.wg.Add(1)
+
+			go s.batch(topic, sub)
+		case sub.workers != s.workers:
+			s.startPool(sub.workers)
+		}
+	}
+
+	go s.consume()
+}
+
+// shutdown stops consuming the messages, and waits for the messages being processed to complete.
+func (s *subscriber) shutdown() {
+	s.stopConsuming()
+
+	stopCtx, cancel := ctx.WithTimeout(ctx.Background(), subscriberStopTimeout)
+	defer cancel()
+
+	if err := s.drain(stopCtx); err != nil {
+		s.g.Logger.Warnf("subscribers did not complete the messages in %v", subscriberStopTimeout)
+	}
+}
+
+// stopConsuming stops consuming the messages, the messages being processed are completed. The message being waited for
+// is not committed, it is consumed again once the pubsub is closed.
+func (s *subscriber) stopConsuming() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// drain waits for the messages being processed to complete, or for the context to be done.
+func (s *subscriber) drain(c ctx.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-c.Done():
+		return c.Err()
+	}
+}
+
+func (s *subscriber) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *subscriber) consume() {
+	for !s.stopped() {
+		msg, err := s.subscribe()
+		if err != nil && s.stopped() {
+			return
+		}
+
+		if err != nil {
+			s.g.Logger.Errorf("message could not be consumed: %v", err)
+			s.wait(s.retry.InitialDelay)
+
+			continue
+		}
+
+		messages := s.channel(msg)
+
+		select {
+		case messages <- msg:
+		case <-s.stop:
+			s.g.Logger.Warnf("message of topic %v at offset %v is not processed, as the application is stopping, it is "+
+				"not committed", msg.Topic, msg.Offset)
+			return
+		}
+	}
+}
+
+// subscribe consumes the next message without committing it, so that a message is committed once it is processed, and
+// is consumed again when the application is stopped before, that is, the messages are delivered at least once. The
+// messages of a batch are committed once the batch is processed.
+func (s *subscriber) subscribe() (*pubsub.Message, error) {
+	return s.g.PubSub.SubscribeWithCommit(func(*pubsub.Message) (bool, bool) { return false, false })
+}
+
+// commitMessage commits the message once it is processed, or given up.
+func (s *subscriber) commitMessage(msg *pubsub.Message) {
+	s.g.PubSub.CommitOffset(pubsub.TopicPartition{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset})
+}
+
+// channel returns the channel the message is processed from, the messages of the topics without a subscription are
+// passed to the shared workers, which ignore them.
+func (s *subscriber) channel(msg *pubsub.Message) chan<- *pubsub.Message {
+	sub, ok := s.subscription(msg.Topic)
+
+	switch {
+	case !ok:
+		return s.workers.channel(msg)
+	case sub.batches != nil:
+		return sub.batches
+	default:
+		return sub.workers.channel(msg)
+	}
+}
+
+func (s *subscriber) work(messages <-chan *pubsub.Message) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case msg := <-messages:
+			s.process(msg)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *subscriber) subscription(topic string) (subscription, bool) {
+	if sub, ok := s.handlers[topic]; ok {
+		return sub, true
+	}
+
+	// the backends which do not have topics, ex: eventhub, pass the messages to the only handler
+	if len(s.handlers) == 1 {
+		for _, sub := range s.handlers {
+			return sub, true
+		}
+	}
+
+	return subscription{}, false
+}
+
+func (s *subscriber) process(msg *pubsub.Message) {
+	sub, ok := s.subscription(msg.Topic)
+	if !ok {
+		s.g.Logger.Warnf("message of topic %v is ignored, as the topic does not have a subscription", msg.Topic)
+		s.commitMessage(msg)
+
+		return
+	}
+
+	if !sub.matches(msg) {
+		s.g.Logger.Debugf("message of topic %v at offset %v is skipped by the filters of the subscription", msg.Topic, msg.Offset)
+		s.commitMessage(msg)
+
+		return
+	}
+
+	span, c := s.context("subscribe "+msg.Topic, msg.Headers)
+	defer span.End()
+
+	desc := fmt.Sprintf("message of topic %v at offset %v", msg.Topic, msg.Offset)
+
+	done := observe(msg.Topic, 1)
+
+	attempts, err := s.retryHandler(c, msg.Topic, sub, desc, func(c *Context) error { return sub.handler(c, msg) })
+
+	done(err)
+
+	if err == nil {
+		s.commitMessage(msg)
+		return
+	}
+
+	c.Logger.Errorf("%v could not be processed: %v", desc, err)
+
+	// the message is neither dead lettered nor committed when its attempts are interrupted by the application stopping,
+	// so that it is consumed again
+	if attempts < sub.retryPolicy(s.retry).MaxAttempts {
+		return
+	}
+
+	// the message which is not published to the DLQ topic is not committed, so that it is consumed again
+	if sub.options.DLQTopic != "" && !s.deadLetter(c, msg, sub.options.DLQTopic, attempts, err) {
+		return
+	}
+
+	s.commitMessage(msg)
+}
+
+// retryHandler calls the handler until it succeeds, it is called the max attempts of the retry policy of the
+// subscription, or the application is stopped. It returns the number of attempts, and the error of the last attempt.
+func (s *subscriber) retryHandler(c *Context, topic string, sub subscription, desc string,
+	handler func(c *Context) error) (int, error) {
+	policy := sub.retryPolicy(s.retry)
+	msgCtx := c.Context
+
+	var (
+		err      error
+		attempts int
+	)
+
+	for attempts < policy.MaxAttempts {
+		if attempts > 0 {
+			if !s.wait(policy.delay(attempts)) {
+				break
+			}
+
+			subscriberRetries.WithLabelValues(topic).Inc()
+		}
+
+		attempts++
+
+		// every attempt has the default deadline, so that the retries of a timed out attempt are not failed at once
+		c.Context = msgCtx
+		cancel := c.withDeadline()
+		err = callSubscribeHandler(handler, c)
+
+		cancel()
+
+		if err == nil {
+			return attempts, nil
+		}
+
+		c.Logger.Warnf("%v failed on attempt %v: %v", desc, attempts, err)
+	}
+
+	return attempts, err
+}
+
+// deadLetter publishes the message to the DLQ topic, with the headers of the message and of its failure, it returns
+// whether the message is published.
+func (s *subscriber) deadLetter(c *Context, msg *pubsub.Message, topic string, attempts int, err error) bool {
+	headers := make(map[string]string, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+
+	headers[dlqHeaderTopic] = msg.Topic
+	headers[dlqHeaderPartition] = strconv.Itoa(msg.Partition)
+	headers[dlqHeaderOffset] = strconv.FormatInt(msg.Offset, 10)
+	headers[dlqHeaderAttempts] = strconv.Itoa(attempts)
+	headers[dlqHeaderError] = err.Error()
+	headers[dlqHeaderTime] = time.Now().UTC().Format(time.RFC3339)
+
+	options := &pubsub.PublishOptions{Topic: topic, Timestamp: time.Now()}
+
+	if pubErr := s.g.PubSub.PublishEventWithOptions(msg.Key, []byte(msg.Value), headers, options); pubErr != nil {
+		c.Logger.Errorf("message of topic %v at offset %v could not be published to the DLQ topic %v: %v",
+			msg.Topic, msg.Offset, topic, pubErr)
+
+		return false
+	}
+
+	c.Logger.Infof("message of topic %v at offset %v is published to the DLQ topic %v", msg.Topic, msg.Offset, topic)
+
+	return true
+}
+
+// context returns the context of the span, which is a child of the span of the W3C trace context of the headers, so
+// that the message is traced along with the request which published it. It is logged with the correlation ID of the
+// headers, or with the trace ID of the span when the correlation ID is empty. The values exported by the publisher are
+// re-hydrated.
+func (s *subscriber) context(spanName string, headers map[string]string, opts ...trace.SpanStartOption) (trace.Span, *Context) {
+	opts = append(opts, trace.WithSpanKind(trace.SpanKindConsumer))
+
+	traceCtx, span := otel.Tracer("gofr-subscriber").Start(pubsub.ExtractTraceContext(ctx.Background(), headers), spanName,
+		opts...)
+
+	correlationID := headers["X-Correlation-ID"]
+	if correlationID == "" {
+		correlationID = span.SpanContext().TraceID().String()
+	}
+
+	logger := log.NewCorrelationLogger(correlationID)
+	log.SetSpan(logger, span.SpanContext())
+
+	traceCtx = importMessageContext(traceCtx, s.g.contextExports, headers)
+
+	return span, &Context{Context: log.NewContext(traceCtx, logger), Gofr: s.g, Logger: logger}
+}
+
+// wait waits for the duration, and returns false when the application is stopped in the meantime.
+func (s *subscriber) wait(d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-s.stop:
+		return false
+	}
+}
+
+// callSubscribeHandler calls the handler, a panic of which is returned as its error, so that the message is retried.
+func callSubscribeHandler(handler func(c *Context) error, c *Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler(c)
+}
@@ -0,0 +1,343 @@
+package gofr
+
+import (
+	"bytes"
+	ctx "context"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+
+	"gofr.dev/pkg/datastore/pubsub"
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/gofr/config"
+	"gofr.dev/pkg/log"
+)
+
+// mockConsumer consumes the messages sent on its channel without committing them, and records the offsets committed.
+type mockConsumer struct {
+	pubsub.PublisherSubscriber
+	messages chan *pubsub.Message
+
+	mu        sync.Mutex
+	committed []pubsub.TopicPartition
+}
+
+func (m *mockConsumer) Subscribe() (*pubsub.Message, error) {
+	msg, ok := <-m.messages
+	if !ok {
+		return nil, errors.Error("consumer is closed")
+	}
+
+	return msg, nil
+}
+
+func (m *mockConsumer) SubscribeWithCommit(f pubsub.CommitFunc) (*pubsub.Message, error) {
+	msg, err := m.Subscribe()
+	if err == nil {
+		f(msg)
+	}
+
+	return msg, err
+}
+
+func (m *mockConsumer) CommitOffset(offsets pubsub.TopicPartition) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.committed = append(m.committed, offsets)
+}
+
+func (m *mockConsumer) commits() []pubsub.TopicPartition {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]pubsub.TopicPartition(nil), m.committed...)
+}
+
+// subscriberResults records the attempts of the messages, by their values.
+type subscriberResults struct {
+	mu       sync.Mutex
+	attempts map[string]int
+	done     sync.WaitGroup
+}
+
+func (r *subscriberResults) record(msg *pubsub.Message) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attempts[msg.Topic+"/"+msg.Value]++
+
+	return r.attempts[msg.Topic+"/"+msg.Value]
+}
+
+func TestGofr_Subscribe(t *testing.T) {
+	b := new(bytes.Buffer)
+	c := &config.MockConfig{Data: map[string]string{"SUBSCRIBER_CONCURRENCY": "2", "SUBSCRIBER_RETRIES": "2",
+		"SUBSCRIBER_RETRY_BACKOFF": "1"}}
+	consumer := &mockConsumer{messages: make(chan *pubsub.Message)}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(b)}
+	g.PubSub = consumer
+
+	results := &subscriberResults{attempts: make(map[string]int)}
+
+	g.Subscribe("orders", func(ctx *Context, msg *pubsub.Message) error {
+		defer results.done.Done()
+
+		assert.NotNil(t, ctx.Logger)
+
+		switch attempt := results.record(msg); msg.Value {
+		case "flaky":
+			if attempt == 1 {
+				return errors.Error("connection refused")
+			}
+		case "broken":
+			panic("nil map")
+		}
+
+		return nil
+	})
+	g.Subscribe("payments", func(_ *Context, msg *pubsub.Message) error {
+		defer results.done.Done()
+
+		results.record(msg)
+
+		return nil
+	})
+
+	g.subscriber.start()
+
+	// an attempt of every message, a retry of the flaky message, and two retries of the broken message
+	results.done.Add(7)
+
+	for _, msg := range []*pubsub.Message{{Topic: "orders", Key: "1", Value: "created"}, {Topic: "orders", Key: "2", Value: "flaky"},
+		{Topic: "orders", Key: "3", Value: "broken"}, {Topic: "payments", Key: "1", Value: "captured"}, {Topic: "refunds", Value: "x"}} {
+		consumer.messages <- msg
+	}
+
+	results.done.Wait()
+	g.subscriber.shutdown()
+
+	assert.Equal(t, map[string]int{"orders/created": 1, "orders/flaky": 2, "orders/broken": 3, "payments/captured": 1}, results.attempts)
+	assert.Contains(t, b.String(), "message of topic refunds is ignored, as the topic does not have a subscription")
+	assert.Len(t, consumer.commits(), 5, "processed, given up and ignored messages must be committed")
+}
+
+func TestSubscriber_CommitAfterHandler(t *testing.T) {
+	consumer := &mockConsumer{messages: make(chan *pubsub.Message)}
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(io.Discard)}
+	g.PubSub = consumer
+
+	handled := make(chan struct{})
+
+	g.Subscribe("orders", func(*Context, *pubsub.Message) error {
+		defer close(handled)
+
+		assert.Empty(t, consumer.commits(), "message must not be committed before it is handled")
+
+		return nil
+	})
+
+	g.subscriber.start()
+
+	consumer.messages <- &pubsub.Message{Topic: "orders", Partition: 1, Offset: 7}
+
+	<-handled
+	g.subscriber.shutdown()
+
+	assert.Equal(t, []pubsub.TopicPartition{{Topic: "orders", Partition: 1, Offset: 7}}, consumer.commits())
+}
+
+func TestSubscriber_Shutdown(t *testing.T) {
+	consumer := &mockConsumer{messages: make(chan *pubsub.Message)}
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(new(bytes.Buffer))}
+	g.PubSub = consumer
+
+	processing, completed := make(chan struct{}), make(chan struct{})
+
+	g.Subscribe("orders", func(*Context, *pubsub.Message) error {
+		close(processing)
+		time.Sleep(50 * time.Millisecond)
+		close(completed)
+
+		return nil
+	})
+
+	g.subscriber.start()
+
+	consumer.messages <- &pubsub.Message{Topic: "orders"}
+
+	<-processing
+	g.subscriber.shutdown()
+
+	select {
+	case <-completed:
+	default:
+		t.Errorf("shutdown returned before the message being processed is completed")
+	}
+}
+
+func TestSubscriber_ShutdownWhileConsuming(t *testing.T) {
+	b := new(bytes.Buffer)
+	consumer := &mockConsumer{messages: make(chan *pubsub.Message)}
+	g := &Gofr{Config: &config.MockConfig{}, Logger: log.NewMockLogger(b)}
+	g.PubSub = consumer
+
+	g.Subscribe("orders", func(*Context, *pubsub.Message) error { return nil })
+	g.subscriber.start()
+
+	stopCtx, cancel := ctx.WithTimeout(ctx.Background(), time.Second)
+	defer cancel()
+
+	g.subscriber.stopConsuming()
+
+	assert.NoError(t, g.subscriber.drain(stopCtx))
+
+	// the consume waiting for a message fails once the pubsub is closed, and is not logged as a failure
+	close(consumer.messages)
+	time.Sleep(10 * time.Millisecond)
+
+	assert.NotContains(t, b.String(), "could not be consumed")
+	assert.Empty(t, consumer.commits())
+}
+
+func TestSubscriber_ShutdownWhileRetrying(t *testing.T) {
+	c := &config.MockConfig{Data: map[string]string{"SUBSCRIBER_RETRY_BACKOFF": "60000"}}
+	ps := &mockDLQ{mockConsumer: mockConsumer{messages: make(chan *pubsub.Message)},
+		published: make(chan *pubsub.Message, 1)}
+	g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+	g.PubSub = ps
+
+	failed := make(chan struct{})
+
+	g.SubscribeWithOptions("orders", func(*Context, *pubsub.Message) error {
+		close(failed)
+		return errors.Error("connection refused")
+	}, SubscribeOptions{DLQTopic: "orders-dlq"})
+
+	g.subscriber.start()
+
+	ps.messages <- &pubsub.Message{Topic: "orders", Offset: 42}
+
+	// the application is stopped while the message waits for its retry
+	<-failed
+	g.subscriber.shutdown()
+
+	assert.Empty(t, ps.commits(), "message whose retries are interrupted must not be committed")
+	assert.Empty(t, ps.published, "message whose retries are interrupted must not be dead lettered")
+}
+
+// mockDLQ consumes the messages sent on its channel, and records the messages published to it.
+type mockDLQ struct {
+	mockConsumer
+	published chan *pubsub.Message
+	err       error
+}
+
+func (m *mockDLQ) PublishEventWithOptions(key string, value interface{}, headers map[string]string,
+	options *pubsub.PublishOptions) error {
+	m.published <- &pubsub.Message{Topic: options.Topic, Key: key, Value: string(value.([]byte)), Headers: headers}
+
+	return m.err
+}
+
+func TestGofr_SubscribeWithOptions(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		options   SubscribeOptions
+		pubErr    error
+		tries     int
+		committed []pubsub.TopicPartition
+	}{
+		{"max attempts of the subscription", SubscribeOptions{MaxAttempts: 3, DLQTopic: "orders-dlq"}, nil, 3,
+			[]pubsub.TopicPartition{{Topic: "orders", Partition: 3, Offset: 42}}},
+		{"message is not committed when it is not published to the DLQ topic", SubscribeOptions{DLQTopic: "orders-dlq"},
+			errors.Error("broker down"), 2, nil},
+		{"retry policy of the subscription", SubscribeOptions{Retry: RetryPolicy{MaxAttempts: 4, Multiplier: 1},
+			DLQTopic: "orders-dlq"}, nil, 4, []pubsub.TopicPartition{{Topic: "orders", Partition: 3, Offset: 42}}},
+	}
+
+	for i, tc := range testcases {
+		c := &config.MockConfig{Data: map[string]string{"SUBSCRIBER_RETRIES": "1", "SUBSCRIBER_RETRY_BACKOFF": "1"}}
+		ps := &mockDLQ{mockConsumer: mockConsumer{messages: make(chan *pubsub.Message)},
+			published: make(chan *pubsub.Message, 1), err: tc.pubErr}
+		g := &Gofr{Config: c, Logger: log.NewMockLogger(io.Discard)}
+		g.PubSub = ps
+
+		var tries int
+
+		g.SubscribeWithOptions("orders", func(*Context, *pubsub.Message) error {
+			tries++
+			return errors.Error("invalid order")
+		}, tc.options)
+
+		g.subscriber.start()
+
+		ps.messages <- &pubsub.Message{Topic: "orders", Partition: 3, Offset: 42, Key: "1", Value: `{"id":1}`,
+			Headers: map[string]string{"X-Correlation-ID": "abc"}}
+
+		msg := <-ps.published
+
+		g.subscriber.shutdown()
+
+		assert.Equal(t, tc.tries, tries, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.committed, ps.commits(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "orders-dlq", msg.Topic, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "1", msg.Key, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, `{"id":1}`, msg.Value, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "abc", msg.Headers["X-Correlation-ID"], "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "orders", msg.Headers[dlqHeaderTopic], "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "3", msg.Headers[dlqHeaderPartition], "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "42", msg.Headers[dlqHeaderOffset], "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, strconv.Itoa(tc.tries), msg.Headers[dlqHeaderAttempts], "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "invalid order", msg.Headers[dlqHeaderError], "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.NotEmpty(t, msg.Headers[dlqHeaderTime], "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestGofr_UsePubSubInterceptors(t *testing.T) {
+	b := new(bytes.Buffer)
+	g := &Gofr{Logger: log.NewMockLogger(b)}
+
+	g.UsePubSubInterceptors(pubsub.Interceptor{})
+
+	assert.Nil(t, g.PubSub)
+	assert.Contains(t, b.String(), "pubsub interceptors are not used, as pubsub is not configured")
+
+	consumer := &mockConsumer{messages: make(chan *pubsub.Message, 1)}
+	g.PubSub = consumer
+
+	g.UsePubSubInterceptors(pubsub.Interceptor{Subscribe: func(next pubsub.SubscribeFunc) pubsub.SubscribeFunc {
+		return func() (*pubsub.Message, error) {
+			msg, err := next()
+			if err == nil {
+				msg.Value = strings.ToUpper(msg.Value)
+			}
+
+			return msg, err
+		}
+	}})
+
+	consumer.messages <- &pubsub.Message{Value: "created"}
+
+	msg, err := g.PubSub.Subscribe()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATED", msg.Value)
+}
+
+func TestSubscriber_contextTraceParent(t *testing.T) {
+	s := &subscriber{g: &Gofr{}}
+
+	span, c := s.context("subscribe orders", map[string]string{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"})
+	defer span.End()
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(c).TraceID().String(),
+		"the span of the message is in the trace of its publisher")
+}
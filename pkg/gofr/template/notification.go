@@ -0,0 +1,224 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	// ChannelEmail notifications have a subject, and an HTML and/or a text body.
+	ChannelEmail = "email"
+	// ChannelSMS notifications only have a text.
+	ChannelSMS = "sms"
+
+	// smsLength is the number of the characters of an SMS, the text of a longer SMS is sent in segments of
+	// smsSegmentLength characters, as the header of the concatenation takes the rest.
+	smsLength        = 160
+	smsSegmentLength = 153
+
+	// ErrNotificationData is returned when the data of a notification is not of the type of the data of its template.
+	ErrNotificationData = errors.Error("data of the notification is not of the type of the data of its template")
+	// ErrUnknownChannel is returned when the channel of a notification template is neither email nor sms.
+	ErrUnknownChannel = errors.Error("channel of the notification template is neither email nor sms")
+)
+
+// Notification is a notification rendered from its template, as it is sent by the notifier.
+type Notification struct {
+	Name    string `json:"name"`
+	Channel string `json:"channel"`
+	Subject string `json:"subject,omitempty"`
+	HTML    string `json:"html,omitempty"`
+	Text    string `json:"text,omitempty"`
+	// Segments is the number of the segments the text of an SMS is sent in.
+	Segments int `json:"segments,omitempty"`
+}
+
+/*
+NotificationTemplate renders the notifications of a channel from the templates of its name in the directory, ex: for the
+email order-shipped
+
+	order-shipped.subject.txt	the subject of the email
+	order-shipped.html		the HTML body of the email, escaped by html/template
+	order-shipped.txt		the text body of the email, or the text of the SMS
+
+The body templates which do not exist are not rendered, an email has at least one of them, and an SMS has the text.
+
+Data is a sample of the data of the notifications, ex: OrderShipped{Order: "A-1"}, which is used to preview the
+template. The data of a notification must be of its type, and the templates can only refer to the fields it has, so
+that a typo in a template fails the preview, rather than the notification sent.
+*/
+type NotificationTemplate struct {
+	Name    string
+	Channel string
+	// Directory is the directory of the templates, default is the static directory of the working directory.
+	Directory string
+	Data      interface{}
+	// Funcs are the functions which can be called from the templates.
+	Funcs map[string]interface{}
+}
+
+// Render renders the notification with the data, which must be of the type of the data of the template.
+func (t *NotificationTemplate) Render(data interface{}) (*Notification, error) {
+	if indirectType(data) != indirectType(t.Data) {
+		return nil, ErrNotificationData
+	}
+
+	n := &Notification{Name: t.Name, Channel: t.Channel}
+
+	var err error
+
+	switch t.Channel {
+	case ChannelEmail:
+		if n.Subject, _, err = t.renderText(".subject.txt", data); err != nil {
+			return nil, err
+		}
+
+		n.Subject = strings.TrimSpace(n.Subject)
+
+		html, hasHTML, err := t.renderHTML(data)
+		if err != nil {
+			return nil, err
+		}
+
+		text, hasText, err := t.renderText(".txt", data)
+		if err != nil {
+			return nil, err
+		}
+
+		if !hasHTML && !hasText {
+			return nil, errors.FileNotFound{Path: t.directory(), FileName: t.Name + ".html"}
+		}
+
+		n.HTML, n.Text = html, text
+	case ChannelSMS:
+		text, ok, err := t.renderText(".txt", data)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, errors.FileNotFound{Path: t.directory(), FileName: t.Name + ".txt"}
+		}
+
+		n.Text = strings.TrimSpace(text)
+		n.Segments = smsSegments(n.Text)
+	default:
+		return nil, ErrUnknownChannel
+	}
+
+	return n, nil
+}
+
+// Bind binds the JSON data to a new value of the type of the data of the template, the fields the type does not have
+// are rejected, so that the data previewed is the data the notifications are sent with.
+func (t *NotificationTemplate) Bind(data []byte) (interface{}, error) {
+	typ := indirectType(t.Data)
+	if typ == nil {
+		return nil, ErrNotificationData
+	}
+
+	v := reflect.New(typ)
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v.Interface()); err != nil {
+		return nil, err
+	}
+
+	if reflect.TypeOf(t.Data).Kind() == reflect.Ptr {
+		return v.Interface(), nil
+	}
+
+	return v.Elem().Interface(), nil
+}
+
+func (t *NotificationTemplate) directory() string {
+	if t.Directory != "" {
+		return t.Directory
+	}
+
+	rootLocation, _ := os.Getwd()
+
+	return rootLocation + "/static"
+}
+
+// read returns the template of the suffix, ok is false when it does not exist.
+func (t *NotificationTemplate) read(suffix string) (content string, ok bool, err error) {
+	b, err := os.ReadFile(filepath.Join(t.directory(), t.Name+suffix))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return string(b), true, nil
+}
+
+func (t *NotificationTemplate) renderText(suffix string, data interface{}) (string, bool, error) {
+	content, ok, err := t.read(suffix)
+	if !ok || err != nil {
+		return "", ok, err
+	}
+
+	tmpl, err := template.New(t.Name + suffix).Option("missingkey=error").Funcs(t.Funcs).Parse(content)
+	if err != nil {
+		return "", true, err
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", true, err
+	}
+
+	return b.String(), true, nil
+}
+
+func (t *NotificationTemplate) renderHTML(data interface{}) (string, bool, error) {
+	content, ok, err := t.read(".html")
+	if !ok || err != nil {
+		return "", ok, err
+	}
+
+	tmpl, err := htmltemplate.New(t.Name + ".html").Option("missingkey=error").Funcs(t.Funcs).Parse(content)
+	if err != nil {
+		return "", true, err
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", true, err
+	}
+
+	return b.String(), true, nil
+}
+
+// smsSegments returns the number of the segments the text is sent in.
+func smsSegments(text string) int {
+	n := utf8.RuneCountInString(text)
+	if n <= smsLength {
+		return 1
+	}
+
+	return (n + smsSegmentLength - 1) / smsSegmentLength
+}
+
+func indirectType(v interface{}) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,99 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+)
+
+type orderShipped struct {
+	Order    string `json:"order"`
+	Customer string `json:"customer"`
+}
+
+func writeNotificationTemplates(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestNotificationTemplate_Render(t *testing.T) {
+	dir := writeNotificationTemplates(t, map[string]string{
+		"shipped.subject.txt": "Order {{.Order}} is shipped\n",
+		"shipped.html":        "<p>Hi {{.Customer}}</p>",
+		"shipped.txt":         "Hi {{.Customer}}",
+		"text-only.txt":       "Hi {{.Customer}}",
+		"sms.txt":             "Order {{.Order}} is shipped\n",
+		"long.txt":            strings.Repeat("a", 161),
+		"typo.txt":            "Hi {{.Name}}",
+	})
+
+	data := orderShipped{Order: "A-1", Customer: "<Ada>"}
+
+	testcases := []struct {
+		desc string
+		tmpl NotificationTemplate
+		data interface{}
+		exp  *Notification
+		err  error
+	}{
+		{"email", NotificationTemplate{Name: "shipped", Channel: ChannelEmail, Directory: dir, Data: orderShipped{}}, data,
+			&Notification{Name: "shipped", Channel: ChannelEmail, Subject: "Order A-1 is shipped",
+				HTML: "<p>Hi &lt;Ada&gt;</p>", Text: "Hi <Ada>"}, nil},
+		{"email without HTML", NotificationTemplate{Name: "text-only", Channel: ChannelEmail, Directory: dir,
+			Data: &orderShipped{}}, &data, &Notification{Name: "text-only", Channel: ChannelEmail, Text: "Hi <Ada>"}, nil},
+		{"sms", NotificationTemplate{Name: "sms", Channel: ChannelSMS, Directory: dir, Data: orderShipped{}}, data,
+			&Notification{Name: "sms", Channel: ChannelSMS, Text: "Order A-1 is shipped", Segments: 1}, nil},
+		{"sms in segments", NotificationTemplate{Name: "long", Channel: ChannelSMS, Directory: dir, Data: orderShipped{}},
+			data, &Notification{Name: "long", Channel: ChannelSMS, Text: strings.Repeat("a", 161), Segments: 2}, nil},
+		{"data of another type", NotificationTemplate{Name: "sms", Channel: ChannelSMS, Directory: dir,
+			Data: orderShipped{}}, map[string]string{"Order": "A-1"}, nil, ErrNotificationData},
+		{"unknown channel", NotificationTemplate{Name: "sms", Channel: "push", Directory: dir, Data: orderShipped{}}, data,
+			nil, ErrUnknownChannel},
+		{"missing template", NotificationTemplate{Name: "missing", Channel: ChannelSMS, Directory: dir,
+			Data: orderShipped{}}, data, nil, errors.FileNotFound{Path: dir, FileName: "missing.txt"}},
+		{"missing email templates", NotificationTemplate{Name: "missing", Channel: ChannelEmail, Directory: dir,
+			Data: orderShipped{}}, data, nil, errors.FileNotFound{Path: dir, FileName: "missing.html"}},
+	}
+
+	for i, tc := range testcases {
+		n, err := tc.tmpl.Render(tc.data)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.exp, n, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	_, err := (&NotificationTemplate{Name: "typo", Channel: ChannelSMS, Directory: dir, Data: orderShipped{}}).Render(data)
+	assert.ErrorContains(t, err, "can't evaluate field Name", "the templates can only refer to the fields of the data")
+}
+
+func TestNotificationTemplate_Bind(t *testing.T) {
+	tmpl := NotificationTemplate{Data: orderShipped{}}
+
+	data, err := tmpl.Bind([]byte(`{"order":"A-1","customer":"Ada"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, orderShipped{Order: "A-1", Customer: "Ada"}, data)
+
+	_, err = tmpl.Bind([]byte(`{"order":"A-1","name":"Ada"}`))
+	assert.ErrorContains(t, err, "unknown field", "the fields the data does not have are rejected")
+
+	tmpl.Data = &orderShipped{}
+
+	data, err = tmpl.Bind([]byte(`{"order":"A-1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &orderShipped{Order: "A-1"}, data)
+
+	_, err = (&NotificationTemplate{}).Bind([]byte(`{}`))
+	assert.Equal(t, ErrNotificationData, err)
+}
@@ -30,6 +30,8 @@ type File struct {
 	Content []byte
 	// ContentType holds the info about the file type
 	ContentType string
+	// Header holds the headers which are set in the response, ex: Cache-Control
+	Header map[string]string
 }
 
 // Template contains the info about the file and implements a renderer to render the file
@@ -42,6 +44,8 @@ type Template struct {
 	Data interface{}
 	// Type denotes the file type which is an integer
 	Type fileType
+	// Funcs are the functions which can be called from the template, ex: {{t "greeting"}}
+	Funcs map[string]interface{}
 }
 
 // Render compiles and executes the template, returning the rendered content.
@@ -55,7 +59,7 @@ func (t *Template) Render() ([]byte, error) {
 		defaultLocation = rootLocation + "/static"
 	}
 
-	templ, err := template.New(t.File).ParseFiles(defaultLocation + "/" + t.File)
+	templ, err := template.New(t.File).Funcs(t.Funcs).ParseFiles(defaultLocation + "/" + t.File)
 	if err != nil {
 		return nil, errors.FileNotFound{Path: t.Directory, FileName: t.File}
 	}
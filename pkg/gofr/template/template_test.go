@@ -150,3 +150,21 @@ func TestTemplate_fileContentType(t1 *testing.T) {
 		_ = os.Remove(tc.fileName)
 	}
 }
+
+func TestTemplate_RenderWithFuncs(t *testing.T) {
+	dir := t.TempDir()
+
+	_ = os.WriteFile(dir+"/greet.html", []byte(`<p>{{t "greeting"}}, {{.}}</p>`), 0o600)
+
+	tmpl := Template{Directory: dir, File: "greet.html", Data: "Alice", Type: HTML,
+		Funcs: map[string]interface{}{"t": func(key string) string { return "Bonjour" }}}
+
+	b, err := tmpl.Render()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(b) != "<p>Bonjour, Alice</p>" {
+		t.Errorf("expected: <p>Bonjour, Alice</p>, got: %s", b)
+	}
+}
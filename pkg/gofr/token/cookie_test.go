@@ -0,0 +1,70 @@
+package token
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookieCodec_EncodeDecode(t *testing.T) {
+	ks, _ := NewKeySet(Key{ID: "k1", Algorithm: HS256, Secret: []byte("secret-1")})
+	codec := NewCookieCodec(ks)
+
+	encoded, err := codec.Encode("session", []byte("user-1"))
+	assert.NoError(t, err)
+
+	_ = ks.Rotate(Key{ID: "k2", Algorithm: HS256, Secret: []byte("secret-2")})
+
+	tamperedKid := "k2" + encoded[2:]
+
+	tests := []struct {
+		desc    string
+		name    string
+		encoded string
+		value   []byte
+		err     error
+	}{
+		{"decoded after rotation", "session", encoded, []byte("user-1"), nil},
+		{"different cookie name", "other", encoded, nil, ErrInvalidCookie},
+		{"different key", "session", tamperedKid, nil, ErrInvalidCookie},
+		{"unknown key", "session", "k3" + encoded[2:], nil, ErrInvalidCookie},
+		{"missing kid", "session", "abc", nil, ErrInvalidCookie},
+		{"invalid base64", "session", "k1.%%%", nil, ErrInvalidCookie},
+		{"short payload", "session", "k1.YWJj", nil, ErrInvalidCookie},
+	}
+
+	for i, tc := range tests {
+		value, err := codec.Decode(tc.name, tc.encoded)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.value, value, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestCookieCodec_NewCookieAndRead(t *testing.T) {
+	ks, _ := NewKeySet(Key{ID: "k1", Algorithm: HS256, Secret: []byte("secret")})
+	codec := NewCookieCodec(ks)
+
+	cookie, err := codec.NewCookie("session", []byte("user-1"), time.Hour)
+	assert.NoError(t, err)
+
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+	assert.Equal(t, 3600, cookie.MaxAge)
+
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	r.AddCookie(cookie)
+
+	value, err := codec.Read(r, "session")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("user-1"), value)
+
+	_, err = codec.Read(r, "missing")
+
+	assert.Equal(t, http.ErrNoCookie, err)
+}
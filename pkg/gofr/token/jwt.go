@@ -0,0 +1,137 @@
+package token
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"gofr.dev/pkg/errors"
+)
+
+// ErrInvalidToken is returned when a token cannot be parsed, has an invalid signature or is expired.
+const ErrInvalidToken = errors.Error("invalid token")
+
+// Issuer signs and verifies JWTs with the keys of a KeySet. The kid header of every token
+// is set to the id of the key used for signing it.
+type Issuer struct {
+	keys     *KeySet
+	name     string
+	audience string
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// IssuerOptions configures the registered claims set by the Issuer.
+type IssuerOptions struct {
+	// Issuer is set as the iss claim and is validated during verification when not empty.
+	Issuer string
+	// Audience is set as the aud claim and is validated during verification when not empty.
+	Audience string
+	// TTL is the lifetime of the tokens, the exp claim is not set when TTL is zero.
+	TTL time.Duration
+}
+
+// NewIssuer creates an Issuer which signs tokens with the active key of keys.
+func NewIssuer(keys *KeySet, opts IssuerOptions) *Issuer {
+	return &Issuer{keys: keys, name: opts.Issuer, audience: opts.Audience, ttl: opts.TTL, now: time.Now}
+}
+
+// Sign returns a signed JWT for the claims. The iat, iss, aud and exp claims are set by the
+// Issuer, unless they are already present in claims.
+func (i *Issuer) Sign(claims jwt.MapClaims) (string, error) {
+	key, err := i.keys.Active()
+	if err != nil {
+		return "", err
+	}
+
+	c := make(jwt.MapClaims, len(claims))
+	for k, v := range claims {
+		c[k] = v
+	}
+
+	now := i.now()
+
+	setDefault(c, "iat", now.Unix())
+
+	if i.name != "" {
+		setDefault(c, "iss", i.name)
+	}
+
+	if i.audience != "" {
+		setDefault(c, "aud", i.audience)
+	}
+
+	if i.ttl > 0 {
+		setDefault(c, "exp", now.Add(i.ttl).Unix())
+	}
+
+	t := jwt.NewWithClaims(signingMethod(key.Algorithm), c)
+	t.Header["kid"] = key.ID
+
+	if key.Algorithm == RS256 {
+		return t.SignedString(key.PrivateKey)
+	}
+
+	return t.SignedString(key.Secret)
+}
+
+// Verify validates the signature of the token with the key referenced by its kid header, and validates the
+// exp, nbf, iss and aud claims. The claims of the token are returned when it is valid.
+func (i *Issuer) Verify(token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+
+	// the time based claims are validated below using the clock of the Issuer
+	parser := jwt.NewParser(jwt.WithoutClaimsValidation())
+
+	_, err := parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		key, err := i.keys.Get(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.Method.Alg() != key.Algorithm {
+			return nil, ErrInvalidToken
+		}
+
+		if key.Algorithm == RS256 {
+			return &key.PrivateKey.PublicKey, nil
+		}
+
+		return key.Secret, nil
+	})
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	now := i.now().Unix()
+
+	if !claims.VerifyExpiresAt(now, false) || !claims.VerifyNotBefore(now, false) {
+		return nil, ErrInvalidToken
+	}
+
+	if i.name != "" && !claims.VerifyIssuer(i.name, true) {
+		return nil, ErrInvalidToken
+	}
+
+	if i.audience != "" && !claims.VerifyAudience(i.audience, true) {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func setDefault(claims jwt.MapClaims, name string, value interface{}) {
+	if _, ok := claims[name]; !ok {
+		claims[name] = value
+	}
+}
+
+func signingMethod(alg string) jwt.SigningMethod {
+	if alg == RS256 {
+		return jwt.SigningMethodRS256
+	}
+
+	return jwt.SigningMethodHS256
+}
@@ -0,0 +1,90 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssuer_SignVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []Key{
+		{ID: "hmac", Algorithm: HS256, Secret: []byte("secret")},
+		{ID: "rsa", Algorithm: RS256, PrivateKey: rsaKey},
+	}
+
+	for i, k := range keys {
+		ks, _ := NewKeySet(k)
+		issuer := NewIssuer(ks, IssuerOptions{Issuer: "gofr", Audience: "api", TTL: time.Minute})
+
+		signed, err := issuer.Sign(jwt.MapClaims{"sub": "user-1"})
+		assert.NoError(t, err, "TEST[%d], failed.", i)
+
+		parsed, _, _ := jwt.NewParser().ParseUnverified(signed, jwt.MapClaims{})
+		assert.Equal(t, k.ID, parsed.Header["kid"], "TEST[%d], failed.", i)
+
+		claims, err := issuer.Verify(signed)
+		assert.NoError(t, err, "TEST[%d], failed.", i)
+		assert.Equal(t, "user-1", claims["sub"], "TEST[%d], failed.", i)
+		assert.Equal(t, "gofr", claims["iss"], "TEST[%d], failed.", i)
+	}
+}
+
+func TestIssuer_VerifyAfterRotation(t *testing.T) {
+	ks, _ := NewKeySet(Key{ID: "k1", Algorithm: HS256, Secret: []byte("secret-1")})
+	issuer := NewIssuer(ks, IssuerOptions{})
+
+	old, _ := issuer.Sign(jwt.MapClaims{"sub": "user-1"})
+
+	_ = ks.Rotate(Key{ID: "k2", Algorithm: HS256, Secret: []byte("secret-2")})
+
+	_, err := issuer.Verify(old)
+	assert.NoError(t, err, "token signed before rotation should be valid")
+
+	ks.Remove("k1")
+
+	_, err = issuer.Verify(old)
+	assert.Equal(t, ErrInvalidToken, err, "token signed with a removed key should be invalid")
+}
+
+func TestIssuer_VerifyInvalid(t *testing.T) {
+	ks, _ := NewKeySet(Key{ID: "k1", Algorithm: HS256, Secret: []byte("secret")})
+	issuer := NewIssuer(ks, IssuerOptions{Issuer: "gofr", TTL: time.Minute})
+
+	valid, _ := issuer.Sign(jwt.MapClaims{"sub": "user-1"})
+
+	otherKS, _ := NewKeySet(Key{ID: "k1", Algorithm: HS256, Secret: []byte("other")})
+	forged, _ := NewIssuer(otherKS, IssuerOptions{Issuer: "gofr"}).Sign(jwt.MapClaims{"sub": "user-1"})
+
+	wrongIssuer, _ := NewIssuer(ks, IssuerOptions{Issuer: "other"}).Sign(jwt.MapClaims{"sub": "user-1"})
+
+	expired := NewIssuer(ks, IssuerOptions{Issuer: "gofr", TTL: time.Minute})
+	expired.now = func() time.Time { return time.Now().Add(-time.Hour) }
+	expiredToken, _ := expired.Sign(jwt.MapClaims{"sub": "user-1"})
+
+	tests := []struct {
+		desc  string
+		token string
+		err   error
+	}{
+		{"valid token", valid, nil},
+		{"different secret", forged, ErrInvalidToken},
+		{"different issuer", wrongIssuer, ErrInvalidToken},
+		{"expired token", expiredToken, ErrInvalidToken},
+		{"malformed token", "abc.def", ErrInvalidToken},
+	}
+
+	for i, tc := range tests {
+		_, err := issuer.Verify(tc.token)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,171 @@
+// Package token provides utilities for gofr applications that issue credentials: signing and verifying JWTs,
+// encrypting cookies and rotating refresh tokens. Keys are identified by a key id (kid), which allows keys to be
+// rotated while tokens signed with older keys are still accepted.
+package token
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"strings"
+	"sync"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	// HS256 signs tokens with HMAC SHA-256 using a shared secret.
+	HS256 = "HS256"
+	// RS256 signs tokens with RSA SHA-256 using a private key, tokens can be verified using the public key.
+	RS256 = "RS256"
+
+	// ErrNoActiveKey is returned when a token has to be signed or encrypted but the KeySet has no active key.
+	ErrNoActiveKey = errors.Error("no active signing key")
+	// ErrUnknownKey is returned when the kid of a token does not match any key of the KeySet.
+	ErrUnknownKey = errors.Error("unknown key id")
+	// ErrInvalidKey is returned when a key is missing an id or the material required by its algorithm.
+	ErrInvalidKey = errors.Error("invalid key")
+)
+
+// Config provides the configs used to load keys, gofr.Config satisfies this interface.
+type Config interface {
+	Get(string) string
+}
+
+// Key is a signing key identified by ID. Secret is used for HS256, PrivateKey for RS256.
+type Key struct {
+	ID         string
+	Algorithm  string
+	Secret     []byte
+	PrivateKey *rsa.PrivateKey
+}
+
+func (k *Key) validate() error {
+	if k.ID == "" {
+		return ErrInvalidKey
+	}
+
+	switch k.Algorithm {
+	case HS256:
+		if len(k.Secret) == 0 {
+			return ErrInvalidKey
+		}
+	case RS256:
+		if k.PrivateKey == nil {
+			return ErrInvalidKey
+		}
+	default:
+		return ErrInvalidKey
+	}
+
+	return nil
+}
+
+// KeySet holds the keys used for signing and verification. The active key is used to sign new tokens,
+// all the keys in the set are used to verify tokens. It is safe for concurrent use.
+type KeySet struct {
+	mu     sync.RWMutex
+	keys   map[string]*Key
+	active string
+}
+
+// NewKeySet creates a KeySet from the given keys, the first key is the active key.
+func NewKeySet(keys ...Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key, len(keys))}
+
+	for i := len(keys) - 1; i >= 0; i-- {
+		if err := ks.Rotate(keys[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return ks, nil
+}
+
+// NewKeySetFromConfig creates a KeySet of HS256 keys from the config TOKEN_KEYS, prefixed with prefix when it is
+// not empty. TOKEN_KEYS is a comma separated list of kid:base64-secret pairs, the first pair is the active key,
+// ex: TOKEN_KEYS=2024-02:c2VjcmV0LTI=,2024-01:c2VjcmV0LTE=
+func NewKeySetFromConfig(c Config, prefix string) (*KeySet, error) {
+	if prefix != "" {
+		prefix += "_"
+	}
+
+	var keys []Key
+
+	for _, pair := range strings.Split(c.Get(prefix+"TOKEN_KEYS"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+
+		keys = append(keys, Key{ID: id, Algorithm: HS256, Secret: secret})
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrNoActiveKey
+	}
+
+	return NewKeySet(keys...)
+}
+
+// Rotate adds the key to the set and makes it the active key. Previously added keys are retained,
+// so tokens signed with them can still be verified until they are removed.
+func (ks *KeySet) Rotate(key Key) error {
+	if err := key.validate(); err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.keys[key.ID] = &key
+	ks.active = key.ID
+
+	return nil
+}
+
+// Remove deletes the key from the set, tokens signed with it will no longer be valid.
+// The active key cannot be removed.
+func (ks *KeySet) Remove(id string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if id != ks.active {
+		delete(ks.keys, id)
+	}
+}
+
+// Active returns the key used for signing.
+func (ks *KeySet) Active() (*Key, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[ks.active]
+	if !ok {
+		return nil, ErrNoActiveKey
+	}
+
+	return key, nil
+}
+
+// Get returns the key with the given id.
+func (ks *KeySet) Get(id string) (*Key, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
@@ -0,0 +1,83 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/gofr/config"
+)
+
+func TestNewKeySetFromConfig(t *testing.T) {
+	tests := []struct {
+		desc     string
+		keys     string
+		activeID string
+		err      error
+	}{
+		{"first key is active", "k2:c2VjcmV0LTI=, k1:c2VjcmV0LTE=", "k2", nil},
+		{"missing config", "", "", ErrNoActiveKey},
+		{"missing kid separator", "c2VjcmV0", "", ErrInvalidKey},
+		{"invalid base64", "k1:%%%", "", ErrInvalidKey},
+		{"empty kid", ":c2VjcmV0", "", ErrInvalidKey},
+	}
+
+	for i, tc := range tests {
+		c := &config.MockConfig{Data: map[string]string{"AUTH_TOKEN_KEYS": tc.keys}}
+
+		ks, err := NewKeySetFromConfig(c, "AUTH")
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if err != nil {
+			continue
+		}
+
+		active, _ := ks.Active()
+
+		assert.Equal(t, tc.activeID, active.ID, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestKeySet_RotateAndRemove(t *testing.T) {
+	ks, err := NewKeySet(Key{ID: "k1", Algorithm: HS256, Secret: []byte("secret-1")})
+
+	assert.NoError(t, err)
+
+	err = ks.Rotate(Key{ID: "k2", Algorithm: HS256, Secret: []byte("secret-2")})
+
+	assert.NoError(t, err)
+
+	active, _ := ks.Active()
+
+	assert.Equal(t, "k2", active.ID)
+
+	// the active key is never removed
+	ks.Remove("k2")
+	ks.Remove("k1")
+
+	_, err = ks.Get("k2")
+	assert.NoError(t, err)
+
+	_, err = ks.Get("k1")
+	assert.Equal(t, ErrUnknownKey, err)
+}
+
+func TestKeySet_InvalidKeys(t *testing.T) {
+	keys := []Key{
+		{Algorithm: HS256, Secret: []byte("secret")},
+		{ID: "k1", Algorithm: HS256},
+		{ID: "k1", Algorithm: RS256},
+		{ID: "k1", Algorithm: "none", Secret: []byte("secret")},
+	}
+
+	for i, k := range keys {
+		_, err := NewKeySet(k)
+
+		assert.Equal(t, ErrInvalidKey, err, "TEST[%d], failed.", i)
+	}
+
+	_, err := (&KeySet{keys: map[string]*Key{}}).Active()
+
+	assert.Equal(t, ErrNoActiveKey, err)
+}
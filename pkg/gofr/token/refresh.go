@@ -0,0 +1,213 @@
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	refreshTokenBytes = 32
+
+	// ErrRefreshTokenInvalid is returned when a refresh token is unknown, expired or its family was revoked.
+	ErrRefreshTokenInvalid = errors.Error("invalid refresh token")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is presented again. This indicates
+	// that the token was leaked, hence all the tokens issued from the same login are revoked.
+	ErrRefreshTokenReused = errors.Error("refresh token reused")
+)
+
+// RefreshRecord is the state of a refresh token persisted in a RefreshStore.
+type RefreshRecord struct {
+	Subject string
+	// Family is shared by all the tokens rotated from the same initial token.
+	Family    string
+	ExpiresAt time.Time
+	Used      bool
+}
+
+// RefreshStore persists refresh tokens, tokens are identified by the hash of their value.
+type RefreshStore interface {
+	Save(ctx context.Context, id string, record RefreshRecord) error
+	Get(ctx context.Context, id string) (RefreshRecord, error)
+	// MarkUsed marks the token as used, it returns false if the token was already used.
+	MarkUsed(ctx context.Context, id string) (bool, error)
+	RevokeFamily(ctx context.Context, family string) error
+}
+
+// RefreshTokens issues opaque refresh tokens and rotates them on every use. A token can be exchanged only once,
+// presenting it again revokes every token of its family.
+type RefreshTokens struct {
+	store RefreshStore
+	ttl   time.Duration
+}
+
+// NewRefreshTokens creates RefreshTokens which are valid for ttl.
+func NewRefreshTokens(store RefreshStore, ttl time.Duration) *RefreshTokens {
+	return &RefreshTokens{store: store, ttl: ttl}
+}
+
+// Issue creates the first refresh token of a new family for the subject.
+func (rt *RefreshTokens) Issue(ctx context.Context, subject string) (string, error) {
+	family, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	return rt.issue(ctx, subject, family)
+}
+
+// Rotate exchanges the refresh token for a new token of the same family, and returns the subject of the token.
+func (rt *RefreshTokens) Rotate(ctx context.Context, token string) (subject, newToken string, err error) {
+	id := hashToken(token)
+
+	record, err := rt.store.Get(ctx, id)
+	if err != nil {
+		return "", "", ErrRefreshTokenInvalid
+	}
+
+	if time.Now().After(record.ExpiresAt) {
+		return "", "", ErrRefreshTokenInvalid
+	}
+
+	ok, err := rt.store.MarkUsed(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !ok {
+		if err = rt.store.RevokeFamily(ctx, record.Family); err != nil {
+			return "", "", err
+		}
+
+		return "", "", ErrRefreshTokenReused
+	}
+
+	newToken, err = rt.issue(ctx, record.Subject, record.Family)
+	if err != nil {
+		return "", "", err
+	}
+
+	return record.Subject, newToken, nil
+}
+
+// Revoke invalidates the token along with all the tokens of its family, it should be used on logout.
+func (rt *RefreshTokens) Revoke(ctx context.Context, token string) error {
+	record, err := rt.store.Get(ctx, hashToken(token))
+	if err != nil {
+		return ErrRefreshTokenInvalid
+	}
+
+	return rt.store.RevokeFamily(ctx, record.Family)
+}
+
+func (rt *RefreshTokens) issue(ctx context.Context, subject, family string) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	record := RefreshRecord{Subject: subject, Family: family, ExpiresAt: time.Now().Add(rt.ttl)}
+
+	if err = rt.store.Save(ctx, hashToken(token), record); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken ensures that the raw tokens are never persisted.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryRefreshStore is an in-memory RefreshStore, it is suitable for tests and single instance applications.
+type MemoryRefreshStore struct {
+	mu      sync.Mutex
+	records map[string]RefreshRecord
+}
+
+// NewMemoryRefreshStore creates an empty MemoryRefreshStore.
+func NewMemoryRefreshStore() *MemoryRefreshStore {
+	return &MemoryRefreshStore{records: make(map[string]RefreshRecord)}
+}
+
+// Save stores the record, expired records are removed on every save.
+func (m *MemoryRefreshStore) Save(_ context.Context, id string, record RefreshRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	for k, r := range m.records {
+		if now.After(r.ExpiresAt) {
+			delete(m.records, k)
+		}
+	}
+
+	m.records[id] = record
+
+	return nil
+}
+
+// Get returns the record with the given id.
+func (m *MemoryRefreshStore) Get(_ context.Context, id string) (RefreshRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.records[id]
+	if !ok {
+		return RefreshRecord{}, ErrRefreshTokenInvalid
+	}
+
+	return r, nil
+}
+
+// MarkUsed marks the record as used, it returns false if it was already used.
+func (m *MemoryRefreshStore) MarkUsed(_ context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.records[id]
+	if !ok {
+		return false, ErrRefreshTokenInvalid
+	}
+
+	if r.Used {
+		return false, nil
+	}
+
+	r.Used = true
+	m.records[id] = r
+
+	return true, nil
+}
+
+// RevokeFamily deletes all the records of the family.
+func (m *MemoryRefreshStore) RevokeFamily(_ context.Context, family string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k, r := range m.records {
+		if r.Family == family {
+			delete(m.records, k)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package token
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokens_Rotate(t *testing.T) {
+	ctx := context.Background()
+	rt := NewRefreshTokens(NewMemoryRefreshStore(), time.Hour)
+
+	first, err := rt.Issue(ctx, "user-1")
+	assert.NoError(t, err)
+
+	subject, second, err := rt.Rotate(ctx, first)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", subject)
+	assert.NotEqual(t, first, second)
+
+	// reusing the rotated token revokes the whole family
+	_, _, err = rt.Rotate(ctx, first)
+	assert.Equal(t, ErrRefreshTokenReused, err)
+
+	_, _, err = rt.Rotate(ctx, second)
+	assert.Equal(t, ErrRefreshTokenInvalid, err)
+}
+
+func TestRefreshTokens_Invalid(t *testing.T) {
+	ctx := context.Background()
+	expired := NewRefreshTokens(NewMemoryRefreshStore(), -time.Minute)
+
+	token, _ := expired.Issue(ctx, "user-1")
+
+	_, _, err := expired.Rotate(ctx, token)
+	assert.Equal(t, ErrRefreshTokenInvalid, err)
+
+	_, _, err = expired.Rotate(ctx, "unknown")
+	assert.Equal(t, ErrRefreshTokenInvalid, err)
+}
+
+func TestRefreshTokens_Revoke(t *testing.T) {
+	ctx := context.Background()
+	rt := NewRefreshTokens(NewMemoryRefreshStore(), time.Hour)
+
+	token, _ := rt.Issue(ctx, "user-1")
+	other, _ := rt.Issue(ctx, "user-1")
+
+	assert.NoError(t, rt.Revoke(ctx, token))
+
+	_, _, err := rt.Rotate(ctx, token)
+	assert.Equal(t, ErrRefreshTokenInvalid, err)
+
+	// tokens of other families are not affected
+	_, _, err = rt.Rotate(ctx, other)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrRefreshTokenInvalid, rt.Revoke(ctx, "unknown"))
+}
@@ -73,6 +73,10 @@ func (e *exporter) getZipkinExporter(c Config, logger log.Logger) (*trace.Tracer
 		return nil, err
 	}
 
+	if isAdaptiveSampling(c) {
+		return adaptiveTracerProvider(c, logger, batcher, r), nil
+	}
+
 	isAlwaysSample, err := strconv.ParseBool(c.Get("TRACER_ALWAYS_SAMPLE"))
 	if err != nil {
 		logger.Warn("TRACER_ALWAYS_SAMPLE is not set.'false' will be used by default")
@@ -109,6 +113,10 @@ func getGCPExporter(c Config, logger log.Logger) (*trace.TracerProvider, error)
 		return nil, err
 	}
 
+	if isAdaptiveSampling(c) {
+		return adaptiveTracerProvider(c, logger, trace.NewBatchSpanProcessor(exporter), r), nil
+	}
+
 	isAlwaysSample, err := strconv.ParseBool(c.Get("TRACER_ALWAYS_SAMPLE"))
 	if err != nil {
 		logger.Warn("TRACER_ALWAYS_SAMPLE is not set.'false' will be used by default")
@@ -135,6 +143,12 @@ func getGCPExporter(c Config, logger log.Logger) (*trace.TracerProvider, error)
 	return tp, nil
 }
 
+// isAdaptiveSampling reports whether the traces are sampled using adaptiveTracerProvider, which is set using
+// TRACER_SAMPLER=adaptive.
+func isAdaptiveSampling(c Config) bool {
+	return strings.EqualFold(c.Get("TRACER_SAMPLER"), "adaptive")
+}
+
 func getResource(c Config) (*resource.Resource, error) {
 	attributes := []attribute.KeyValue{
 		attribute.String(string(semconv.TelemetrySDKLanguageKey), "go"),
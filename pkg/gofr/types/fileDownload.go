@@ -0,0 +1,11 @@
+package types
+
+// FileDownload denotes a file which is saved by the client, instead of being displayed, ex: a generated report
+type FileDownload struct {
+	// Content holds the file data
+	Content []byte
+	// ContentType denotes the type of the file, application/octet-stream is used when it is empty
+	ContentType string
+	// FileName is the name with which the client saves the file
+	FileName string
+}
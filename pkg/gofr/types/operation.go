@@ -0,0 +1,34 @@
+package types
+
+import "time"
+
+// The states of an Operation.
+const (
+	OperationPending   = "PENDING"
+	OperationRunning   = "RUNNING"
+	OperationSucceeded = "SUCCEEDED"
+	OperationFailed    = "FAILED"
+)
+
+// Operation is a long-running operation, which is processed after the request is responded. A handler which returns
+// an Operation is responded with 202 Accepted, and the Location header set to the status URL of the operation.
+type Operation struct {
+	ID     string `json:"id" xml:"id"`
+	Status string `json:"status" xml:"status"`
+	// StatusURL is the URL which returns the current state of the operation.
+	StatusURL string `json:"statusUrl" xml:"statusUrl"`
+	// Result is the data returned by the operation once it has succeeded.
+	Result interface{} `json:"result,omitempty" xml:"result,omitempty"`
+	// Error is the reason of the failure of the operation.
+	Error     string    `json:"error,omitempty" xml:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" xml:"updatedAt"`
+	// Owner is the authenticated user who enqueued the operation, the state of the operation is not served to the
+	// other users. It is empty when the operation is enqueued without an authenticated user.
+	Owner string `json:"owner,omitempty" xml:"owner,omitempty"`
+}
+
+// Done reports whether the operation has completed, either successfully or not.
+func (o *Operation) Done() bool {
+	return o.Status == OperationSucceeded || o.Status == OperationFailed
+}
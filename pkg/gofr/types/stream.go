@@ -0,0 +1,15 @@
+package types
+
+import "io"
+
+// Stream denotes a response which is written to the client as it is generated, instead of being held in memory,
+// ex: the rows of a large query. As the response is written to the connection, a slow client slows down the generation.
+type Stream struct {
+	// ContentType denotes the type of the stream, application/octet-stream is used when it is empty
+	ContentType string
+	// FileName is the name with which the client saves the stream, the stream is displayed when it is empty
+	FileName string
+	// Write writes the stream, the writer implements http.Flusher to send the written data to the client. The error is
+	// responded when nothing is written, else the response is aborted, so that the client does not take it as complete
+	Write func(w io.Writer) error
+}
@@ -0,0 +1,24 @@
+package log
+
+import "context"
+
+type loggerKey struct{}
+
+// NewContext returns a context which carries the logger, so that the logs of the calls made with the context, ex: the
+// queries of the datastores, carry the data of the logger.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger carried by the context, or the fallback when the context does not carry a logger.
+func FromContext(ctx context.Context, fallback Logger) Logger {
+	if ctx == nil {
+		return fallback
+	}
+
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok && l != nil {
+		return l
+	}
+
+	return fallback
+}
@@ -0,0 +1,29 @@
+package log
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	fallback := NewMockLogger(io.Discard)
+	requestLogger := NewCorrelationLogger("b00ff8de800911ec8f6502bfe7568078")
+
+	tests := []struct {
+		desc   string
+		ctx    context.Context
+		logger Logger
+	}{
+		{"logger in context", NewContext(context.Background(), requestLogger), requestLogger},
+		{"logger not in context", context.Background(), fallback},
+		//nolint:staticcheck // the nil context is handled by FromContext
+		{"nil context", nil, fallback},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.logger, FromContext(tc.ctx, fallback), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/gookit/color"
+
+	"gofr.dev/pkg/pii"
 )
 
 type entry struct {
@@ -18,6 +20,8 @@ type entry struct {
 	System        map[string]interface{} `json:"system"`
 	App           appInfo                `json:"app"`
 	CorrelationID string                 `json:"correlationId,omitempty"`
+	TraceID       string                 `json:"traceId,omitempty"`
+	SpanID        string                 `json:"spanId,omitempty"`
 	Data          map[string]interface{} `json:"data,omitempty"` // Used to support middleware log data
 }
 
@@ -76,6 +80,9 @@ func entryFromInputs(format string, args ...interface{}) (e *entry, data map[str
 		Data: make(map[string]interface{}),
 	}
 
+	// the personal data is never logged, whoever the caller is
+	args = maskArgs(args)
+
 	// No need for array if size is only 1
 	if len(args) == 1 {
 		j, hashMap := isJSON(args[0])
@@ -104,6 +111,15 @@ func entryFromInputs(format string, args ...interface{}) (e *entry, data map[str
 				delete(hashMap, "correlationId")
 			}
 
+			// the performance logs carry the trace of the request
+			if isPerformanceLog {
+				e.TraceID, _ = hashMap["traceId"].(string)
+				e.SpanID, _ = hashMap["spanId"].(string)
+
+				delete(hashMap, "traceId")
+				delete(hashMap, "spanId")
+			}
+
 			if m, ok := hashMap["message"]; ok && isPerformanceLog {
 				e.Message = m.(string)
 
@@ -166,6 +182,30 @@ func entryFromInputs(format string, args ...interface{}) (e *entry, data map[str
 	return e, data, isPerformanceLog
 }
 
+// maskArgs masks the fields of the args tagged with pii, the args are copied when an arg is masked, so that the
+// values of the caller are not changed.
+func maskArgs(args []interface{}) []interface{} {
+	var masked []interface{}
+
+	for i, arg := range args {
+		if !pii.Contains(arg) {
+			continue
+		}
+
+		if masked == nil {
+			masked = append([]interface{}{}, args...)
+		}
+
+		masked[i] = pii.Mask(arg, nil)
+	}
+
+	if masked == nil {
+		return args
+	}
+
+	return masked
+}
+
 func populateData(e *entry, s string) string {
 	if len(e.Data) > 0 {
 		if v, ok := e.Data["errorMessage"]; ok {
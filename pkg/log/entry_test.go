@@ -192,3 +192,41 @@ func Test_AppDataWithoutPersistence(t *testing.T) {
 		}
 	}
 }
+
+func TestEntryFromInputs_PII(t *testing.T) {
+	type user struct {
+		Name  string `json:"name"`
+		Email string `json:"email" pii:"email"`
+	}
+
+	u := user{Name: "Jane", Email: "jane@example.com"}
+
+	_, data, _ := entryFromInputs("", "created user", u)
+
+	if !reflect.DeepEqual(data, map[string]interface{}{"name": "Jane", "email": "j***@example.com"}) {
+		t.Errorf("Failed. Expected the email to be masked, Got: %v", data)
+	}
+
+	e, _, _ := entryFromInputs("created user %v", u)
+
+	if e.Message != "created user {Jane j***@example.com}" {
+		t.Errorf("Failed. Expected the email to be masked, Got: %v", e.Message)
+	}
+
+	if u.Email != "jane@example.com" {
+		t.Errorf("Failed. The value of the caller is changed, Got: %v", u.Email)
+	}
+}
+
+func TestEntryFromInputs_PerformanceLogTrace(t *testing.T) {
+	e, _, _ := entryFromInputs("", map[string]interface{}{"method": "GET", "uri": "/hello", "duration": 10,
+		"traceId": "4bf92f3577b34da6a3ce929d0e0e4736", "spanId": "00f067aa0ba902b7"})
+
+	if e.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || e.SpanID != "00f067aa0ba902b7" {
+		t.Errorf("Failed. Expected the trace of the request, Got: %v %v", e.TraceID, e.SpanID)
+	}
+
+	if _, ok := e.Data["traceId"]; ok {
+		t.Errorf("Failed. Expected the trace to be removed from the data, Got: %v", e.Data)
+	}
+}
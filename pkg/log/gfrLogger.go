@@ -20,8 +20,15 @@ type logger struct {
 	// App Specific Data for the logger
 	app           appInfo
 	correlationID string
+	// traceID and spanID are the span of the request, they are set using SetSpan
+	traceID string
+	spanID  string
 
 	isTerminal bool
+
+	// debug logs all the levels regardless of the level of the application, and writes the entries to capture as well
+	debug   bool
+	capture io.Writer
 }
 
 type appInfo struct {
@@ -59,7 +66,7 @@ func (l *logger) log(level level, format string, args ...interface{}) {
 
 	mu.Unlock()
 
-	if lvl < level {
+	if lvl < level && !l.debug {
 		return // No need to do anything if we are not going to log it.
 	}
 
@@ -98,6 +105,18 @@ func (l *logger) log(level level, format string, args ...interface{}) {
 	// Deleting the correlationId in case of any duplication.
 	delete(e.App.Data, "correlationID")
 
+	if l.traceID != "" {
+		e.TraceID, e.SpanID = l.traceID, l.spanID
+	}
+
+	if exporter := getOTLPExporter(l.app); exporter != nil {
+		exporter.export(e)
+	}
+
+	if l.capture != nil {
+		_ = json.NewEncoder(l.capture).Encode(e)
+	}
+
 	if l.isTerminal {
 		fmt.Fprint(l.out, e.TerminalOutput())
 	} else {
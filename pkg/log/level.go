@@ -37,6 +37,22 @@ func (l level) String() string {
 	return logLevel
 }
 
+// severityNumber returns the OpenTelemetry severity number of the level.
+func (l level) severityNumber() int {
+	switch l {
+	case Fatal:
+		return 21 //nolint:gomnd // severity numbers of OpenTelemetry
+	case Error:
+		return 17 //nolint:gomnd // severity numbers of OpenTelemetry
+	case Warn:
+		return 13 //nolint:gomnd // severity numbers of OpenTelemetry
+	case Debug:
+		return 5 //nolint:gomnd // severity numbers of OpenTelemetry
+	default:
+		return 9 //nolint:gomnd // severity numbers of OpenTelemetry
+	}
+}
+
 const (
 	redColor    = 31
 	yellowColor = 33
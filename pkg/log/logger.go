@@ -1,6 +1,7 @@
 package log
 
 import (
+	"io"
 	"os"
 	"sync"
 )
@@ -41,7 +42,6 @@ func newLogger() *logger {
 		Name:      name,
 		Version:   version,
 		Framework: "gofr-" + GofrVersion,
-		Data:      make(map[string]interface{}),
 		syncData:  &sync.Map{},
 	}
 
@@ -67,3 +67,24 @@ func NewCorrelationLogger(correlationID string) Logger {
 
 	return l
 }
+
+// NewCaptureLogger creates and returns a new logger instance with a specified correlation ID, of which the entries
+// are written to capture as JSON, in addition to the output, at the level of the application.
+func NewCaptureLogger(correlationID string, capture io.Writer) Logger {
+	l := newLogger()
+	l.correlationID = correlationID
+	l.capture = capture
+
+	return l
+}
+
+// NewDebugLogger creates and returns a new logger instance with a specified correlation ID, which logs at DEBUG level
+// regardless of the level of the application. The entries are written to capture as JSON, in addition to the output.
+func NewDebugLogger(correlationID string, capture io.Writer) Logger {
+	l := newLogger()
+	l.correlationID = correlationID
+	l.debug = true
+	l.capture = capture
+
+	return l
+}
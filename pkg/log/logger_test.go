@@ -1,6 +1,8 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"testing"
 
@@ -59,3 +61,56 @@ func TestNewCorrelationLogger(t *testing.T) {
 
 	assert.Equal(t, l.correlationID, val.correlationID, "test failed as log value didn't match")
 }
+
+func TestNewDebugLogger(t *testing.T) {
+	mu.Lock()
+	lvl := rls.level
+	rls.level = Warn
+	mu.Unlock()
+
+	defer func() {
+		mu.Lock()
+		rls.level = lvl
+		mu.Unlock()
+	}()
+
+	capture := new(bytes.Buffer)
+
+	// the level of the application is WARN, the debug logger logs the DEBUG entries nonetheless
+	l := NewDebugLogger("b00ff8de800911ec8f6502bfe7568078", capture)
+	l.Debug("fetching the order")
+
+	var e map[string]interface{}
+
+	if assert.NoError(t, json.Unmarshal(capture.Bytes(), &e)) {
+		assert.Equal(t, "fetching the order", e["message"])
+		assert.Equal(t, "DEBUG", e["level"])
+		assert.Equal(t, "b00ff8de800911ec8f6502bfe7568078", e["correlationId"])
+	}
+}
+
+func TestNewCaptureLogger(t *testing.T) {
+	mu.Lock()
+	lvl := rls.level
+	rls.level = Warn
+	mu.Unlock()
+
+	defer func() {
+		mu.Lock()
+		rls.level = lvl
+		mu.Unlock()
+	}()
+
+	capture := new(bytes.Buffer)
+
+	l := NewCaptureLogger("b00ff8de800911ec8f6502bfe7568078", capture)
+	l.Debug("fetching the order")
+	l.Warn("order is not cached")
+
+	var e map[string]interface{}
+
+	if assert.NoError(t, json.Unmarshal(capture.Bytes(), &e), "entries below the level of the application are captured") {
+		assert.Equal(t, "order is not cached", e["message"])
+		assert.Equal(t, "WARN", e["level"])
+	}
+}
@@ -0,0 +1,207 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	otlpBatchSize     = 512
+	otlpQueueSize     = 4096
+	otlpFlushInterval = time.Second
+	otlpTimeout       = 5 * time.Second
+)
+
+//nolint:gochecknoglobals // the exporter is shared by all the loggers of the application
+var (
+	otlpOnce     sync.Once
+	otlpExporter *otlpLogExporter
+)
+
+// SetSpan adds the trace and the span IDs of the span context to the records of the logger, so that the logs are
+// correlated with the traces, ex: by an observability backend to which the logs and the traces are exported.
+func SetSpan(l Logger, sc trace.SpanContext) {
+	gl, ok := l.(*logger)
+	if !ok || !sc.IsValid() {
+		return
+	}
+
+	gl.traceID = sc.TraceID().String()
+	gl.spanID = sc.SpanID().String()
+}
+
+// otlpLogExporter exports the log records to an OpenTelemetry collector, using OTLP over HTTP with the JSON encoding.
+// The records are exported in batches, and are dropped when the collector can not keep up, so that the logging does
+// not block the application.
+type otlpLogExporter struct {
+	endpoint string
+	resource otlpResource
+	client   *http.Client
+	records  chan otlpLogRecord
+}
+
+// getOTLPExporter returns the exporter of the endpoint OTLP_LOGS_ENDPOINT, ex: http://collector:4318/v1/logs, or nil
+// when the logs are not exported.
+func getOTLPExporter(app appInfo) *otlpLogExporter {
+	otlpOnce.Do(func() {
+		endpoint := os.Getenv("OTLP_LOGS_ENDPOINT")
+		if endpoint == "" {
+			return
+		}
+
+		otlpExporter = &otlpLogExporter{
+			endpoint: endpoint,
+			resource: otlpResource{Attributes: []otlpAttribute{
+				stringAttribute("service.name", app.Name),
+				stringAttribute("service.version", app.Version),
+			}},
+			client:  &http.Client{Timeout: otlpTimeout},
+			records: make(chan otlpLogRecord, otlpQueueSize),
+		}
+
+		go otlpExporter.run()
+	})
+
+	return otlpExporter
+}
+
+func (o *otlpLogExporter) export(e *entry) {
+	select {
+	case o.records <- newOTLPLogRecord(e):
+	default:
+	}
+}
+
+func (o *otlpLogExporter) run() {
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]otlpLogRecord, 0, otlpBatchSize)
+
+	for {
+		select {
+		case r := <-o.records:
+			if batch = append(batch, r); len(batch) < otlpBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		o.send(batch)
+
+		batch = batch[:0]
+	}
+}
+
+func (o *otlpLogExporter) send(records []otlpLogRecord) {
+	body, _ := json.Marshal(otlpLogsRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource:  o.resource,
+		ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: "gofr", Version: GofrVersion}, LogRecords: records}},
+	}}})
+
+	resp, err := o.client.Post(o.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// the failure is not logged using the logger, as the log would be exported again
+		fmt.Fprintf(os.Stderr, "%v logs could not be exported to %v: %v\n", len(records), o.endpoint, err)
+		return
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		fmt.Fprintf(os.Stderr, "%v logs could not be exported to %v: status %v\n", len(records), o.endpoint, resp.StatusCode)
+	}
+}
+
+// the types of the OTLP JSON encoding, the IDs of the traces and the spans are hex encoded, and the 64 bit integers
+// are strings, see https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+type (
+	otlpLogsRequest struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+
+	otlpResourceLogs struct {
+		Resource  otlpResource    `json:"resource"`
+		ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+
+	otlpScopeLogs struct {
+		Scope      otlpScope       `json:"scope"`
+		LogRecords []otlpLogRecord `json:"logRecords"`
+	}
+
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	otlpLogRecord struct {
+		TimeUnixNano   string          `json:"timeUnixNano"`
+		SeverityNumber int             `json:"severityNumber"`
+		SeverityText   string          `json:"severityText"`
+		Body           otlpValue       `json:"body"`
+		Attributes     []otlpAttribute `json:"attributes,omitempty"`
+		TraceID        string          `json:"traceId,omitempty"`
+		SpanID         string          `json:"spanId,omitempty"`
+	}
+
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+
+	otlpValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+func stringAttribute(key string, value interface{}) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: fmt.Sprint(value)}}
+}
+
+func newOTLPLogRecord(e *entry) otlpLogRecord {
+	r := otlpLogRecord{
+		TimeUnixNano:   strconv.FormatInt(e.Time.UnixNano(), 10),
+		SeverityNumber: e.Level.severityNumber(),
+		SeverityText:   e.Level.String(),
+		Body:           otlpValue{StringValue: fmt.Sprint(e.Message)},
+		TraceID:        e.TraceID,
+		SpanID:         e.SpanID,
+	}
+
+	if e.CorrelationID != "" {
+		r.Attributes = append(r.Attributes, stringAttribute("correlationId", e.CorrelationID))
+	}
+
+	for _, data := range []map[string]interface{}{e.App.Data, e.Data} {
+		keys := make([]string, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+
+		// the attributes are sorted, so that the records of the same data are alike
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			r.Attributes = append(r.Attributes, stringAttribute(k, data[k]))
+		}
+	}
+
+	return r
+}
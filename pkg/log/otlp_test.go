@@ -0,0 +1,70 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetSpan(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	tests := []struct {
+		desc     string
+		sc       trace.SpanContext
+		expected string
+	}{
+		{"valid span", trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}),
+			`"traceId":"4bf92f3577b34da6a3ce929d0e0e4736","spanId":"00f067aa0ba902b7"`},
+		{"invalid span", trace.SpanContext{}, ""},
+	}
+
+	for i, tc := range tests {
+		b := new(bytes.Buffer)
+		l := NewMockLogger(b)
+
+		SetSpan(l, tc.sc)
+		l.Info("hello")
+
+		if tc.expected == "" {
+			assert.NotContains(t, b.String(), "traceId", "TEST[%d], failed.\n%s", i, tc.desc)
+			continue
+		}
+
+		assert.Contains(t, b.String(), tc.expected, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestOTLPLogExporter_Send(t *testing.T) {
+	var req otlpLogsRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&req)
+	}))
+	defer server.Close()
+
+	o := &otlpLogExporter{endpoint: server.URL, client: server.Client(),
+		resource: otlpResource{Attributes: []otlpAttribute{stringAttribute("service.name", "orders")}}}
+
+	o.send([]otlpLogRecord{newOTLPLogRecord(&entry{Level: Warn, Message: "slow query", Time: time.Unix(1, 0),
+		CorrelationID: "abc", TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7",
+		Data: map[string]interface{}{"duration": 12}})})
+
+	expected := otlpLogsRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: otlpResource{Attributes: []otlpAttribute{stringAttribute("service.name", "orders")}},
+		ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: "gofr", Version: GofrVersion}, LogRecords: []otlpLogRecord{{
+			TimeUnixNano: "1000000000", SeverityNumber: 13, SeverityText: "WARN", Body: otlpValue{StringValue: "slow query"},
+			Attributes: []otlpAttribute{stringAttribute("correlationId", "abc"), stringAttribute("duration", 12)},
+			TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7",
+		}}}},
+	}}}
+
+	assert.Equal(t, expected, req)
+}
@@ -4,13 +4,26 @@ package log
 import (
 	"io"
 	"os"
+	"sync"
 
 	"golang.org/x/crypto/ssh/terminal"
 )
 
+// stdoutIsTerminal caches the terminal check for os.Stdout, a logger is created for every request
+// and the check requires a system call.
+//
+//nolint:gochecknoglobals // the result of the check does not change during the lifetime of the process
+var stdoutIsTerminal = sync.OnceValue(func() bool {
+	return terminal.IsTerminal(int(os.Stdout.Fd()))
+})
+
 func checkIfTerminal(w io.Writer) bool {
 	switch v := w.(type) {
 	case *os.File:
+		if v == os.Stdout {
+			return stdoutIsTerminal()
+		}
+
 		return terminal.IsTerminal(int(v.Fd()))
 	default:
 		return false
@@ -0,0 +1,266 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultArchiveBodySize = 1 << 20
+
+// ErrInvalidArchive is returned when an archive cannot be decrypted with the key.
+const ErrInvalidArchive Error = "invalid archive"
+
+// Archiver stores the archives, ex: in an object store. It is implemented by file.Archiver.
+type Archiver interface {
+	Archive(ctx context.Context, key string, data []byte) error
+}
+
+// Archive is a copy of a request and its response, which is archived for audits.
+type Archive struct {
+	CorrelationID string            `json:"correlationId"`
+	Timestamp     time.Time         `json:"timestamp"`
+	RetainUntil   *time.Time        `json:"retainUntil,omitempty"`
+	Method        string            `json:"method"`
+	URI           string            `json:"uri"`
+	Headers       map[string]string `json:"headers"`
+	Request       []byte            `json:"request"`
+	Status        int               `json:"status"`
+	Response      []byte            `json:"response"`
+	// Truncated is true when a body exceeded the MaxBodySize, and only its beginning is archived.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ArchiveConfig configures the routes which are archived, and how the archives are stored.
+type ArchiveConfig struct {
+	Archiver Archiver
+	// Routes are the path prefixes of the archived routes, optionally preceded by the method, ex: "POST /payments".
+	Routes []string
+	// Key is the AES key, of 16, 24 or 32 bytes, which encrypts the archives. The archives are not encrypted when it is empty.
+	Key []byte
+	// Prefix is prepended to the keys of the archives, which are of the form <prefix>/<yyyy>/<mm>/<dd>/<id>.
+	Prefix string
+	// Retention is recorded in the archives as their retain until time. The date in the keys of the archives allows
+	// the object store lifecycle rules to delete them once the retention period is over.
+	Retention time.Duration
+	// MaxBodySize is the number of bytes of each body which are archived. Default is 1 MB.
+	MaxBodySize int64
+	Logger      logger
+}
+
+// BodyArchive archives a copy of the request and response bodies of the configured routes. The bodies are copied
+// as they are read and written by the handler, and the archives are stored after the response is written, so that
+// archiving does not delay the responses.
+func BodyArchive(cfg ArchiveConfig) func(inner http.Handler) http.Handler {
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultArchiveBodySize
+	}
+
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.archived(r) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			req := &limitedBuffer{limit: cfg.MaxBodySize}
+
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = readCloser{Reader: io.TeeReader(r.Body, req), Closer: r.Body}
+			}
+
+			aw := &archiveResponseWriter{ResponseWriter: w, body: limitedBuffer{limit: cfg.MaxBodySize}, status: http.StatusOK}
+
+			inner.ServeHTTP(aw, r)
+
+			a := Archive{
+				Timestamp: start.UTC(),
+				Method:    r.Method,
+				URI:       r.URL.RequestURI(),
+				Headers:   archivedHeaders(r.Header),
+				Request:   req.Bytes(),
+				Status:    aw.status,
+				Response:  aw.body.Bytes(),
+				Truncated: req.truncated || aw.body.truncated,
+			}
+
+			a.CorrelationID, _ = r.Context().Value(CorrelationIDKey).(string)
+
+			if cfg.Retention > 0 {
+				retainUntil := a.Timestamp.Add(cfg.Retention)
+				a.RetainUntil = &retainUntil
+			}
+
+			go cfg.store(&a)
+		})
+	}
+}
+
+func (cfg *ArchiveConfig) archived(r *http.Request) bool {
+	return matchRoutes(cfg.Routes, r)
+}
+
+// matchRoutes reports whether the request matches any of the routes, which are path prefixes optionally preceded
+// by the method, ex: "POST /payments".
+func matchRoutes(routes []string, r *http.Request) bool {
+	for _, route := range routes {
+		method, prefix, ok := strings.Cut(route, " ")
+		if !ok {
+			method, prefix = "", route
+		}
+
+		if (method == "" || strings.EqualFold(method, r.Method)) && strings.HasPrefix(r.URL.Path, strings.TrimSpace(prefix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cfg *ArchiveConfig) store(a *Archive) {
+	data, err := json.Marshal(a)
+	if err == nil && len(cfg.Key) > 0 {
+		data, err = encryptArchive(cfg.Key, data)
+	}
+
+	id := a.CorrelationID
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	key := fmt.Sprintf("%v/%v-%v", a.Timestamp.Format("2006/01/02"), a.Timestamp.Format("150405.000000000"), id)
+	if cfg.Prefix != "" {
+		key = strings.TrimSuffix(cfg.Prefix, "/") + "/" + key
+	}
+
+	if err == nil {
+		err = cfg.Archiver.Archive(context.Background(), key, data)
+	}
+
+	if err != nil && cfg.Logger != nil {
+		cfg.Logger.Errorf("archiving %v %v failed: %v", a.Method, a.URI, err)
+	}
+}
+
+// archivedHeaders returns the headers of the request, except the credentials which must not be retained.
+func archivedHeaders(h http.Header) map[string]string {
+	headers := make(map[string]string, len(h))
+
+	for k, v := range h {
+		switch http.CanonicalHeaderKey(k) {
+		case "Authorization", "Cookie", "Proxy-Authorization", "X-Api-Key", "X-Debug-Trace":
+			continue
+		}
+
+		headers[k] = strings.Join(v, ",")
+	}
+
+	return headers
+}
+
+func encryptArchive(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	// the nonce is prepended to the archive, as it is required for decryption
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// DecryptArchive decrypts an archive which was encrypted by BodyArchive using the key.
+func DecryptArchive(key, data []byte) (*Archive, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrInvalidArchive
+	}
+
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	var a Archive
+
+	if err := json.Unmarshal(plain, &a); err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	return &a, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// limitedBuffer retains the first limit bytes written to it, and discards the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - int64(b.Len()); int64(len(p)) > remaining {
+		b.truncated = true
+		_, _ = b.Buffer.Write(p[:remaining])
+
+		return len(p), nil
+	}
+
+	return b.Buffer.Write(p)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// archiveResponseWriter copies the response body, while writing it to the client.
+type archiveResponseWriter struct {
+	http.ResponseWriter
+	body   limitedBuffer
+	status int
+}
+
+func (w *archiveResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *archiveResponseWriter) Write(b []byte) (int, error) {
+	_, _ = w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *archiveResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockArchiver struct {
+	mu       sync.Mutex
+	archives map[string][]byte
+	stored   chan struct{}
+}
+
+func (m *mockArchiver) Archive(_ context.Context, key string, data []byte) error {
+	m.mu.Lock()
+	m.archives[key] = data
+	m.mu.Unlock()
+
+	m.stored <- struct{}{}
+
+	return nil
+}
+
+func TestBodyArchive(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	archiver := &mockArchiver{archives: make(map[string][]byte), stored: make(chan struct{}, 1)}
+
+	handler := BodyArchive(ArchiveConfig{Archiver: archiver, Routes: []string{"POST /payments"}, Key: key,
+		Prefix: "audit/", Retention: 24 * time.Hour, MaxBodySize: 8})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+		_, _ = w.Write(body)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/payments/1?mode=card", strings.NewReader(`{"amount":10}`))
+	r.Header.Set("Authorization", "Bearer token")
+	r.Header.Set("Content-Type", "application/json")
+	r = r.WithContext(context.WithValue(r.Context(), CorrelationIDKey, "b5a2c1"))
+
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, `{"id":1}{"amount":10}`, w.Body.String(), "response is modified by archiving")
+
+	select {
+	case <-archiver.stored:
+	case <-time.After(time.Second):
+		t.Fatal("archive is not stored")
+	}
+
+	archiver.mu.Lock()
+	defer archiver.mu.Unlock()
+
+	for k, data := range archiver.archives {
+		assert.True(t, strings.HasPrefix(k, "audit/"), k)
+		assert.True(t, strings.HasSuffix(k, "-b5a2c1"), k)
+
+		_, err := DecryptArchive([]byte("fedcba9876543210fedcba9876543210"), data)
+
+		assert.Equal(t, ErrInvalidArchive, err, "archive is decrypted with a different key")
+
+		a, err := DecryptArchive(key, data)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, "/payments/1?mode=card", a.URI)
+		assert.Equal(t, http.StatusCreated, a.Status)
+		assert.Equal(t, `{"amount`, string(a.Request))
+		assert.Equal(t, `{"id":1}`, string(a.Response))
+		assert.True(t, a.Truncated)
+		assert.Equal(t, a.Timestamp.Add(24*time.Hour), *a.RetainUntil)
+		assert.Equal(t, "application/json", a.Headers["Content-Type"])
+		assert.NotContains(t, a.Headers, "Authorization", "credentials are archived")
+	}
+}
+
+func TestBodyArchive_Routes(t *testing.T) {
+	cfg := ArchiveConfig{Routes: []string{"POST /payments", "/refunds"}}
+
+	tests := []struct {
+		desc     string
+		method   string
+		path     string
+		archived bool
+	}{
+		{"method and prefix match", http.MethodPost, "/payments/1", true},
+		{"method does not match", http.MethodGet, "/payments/1", false},
+		{"any method", http.MethodGet, "/refunds", true},
+		{"route not selected", http.MethodPost, "/orders", false},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(tc.method, tc.path, http.NoBody)
+
+		assert.Equal(t, tc.archived, cfg.archived(r), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestBodyArchive_Unencrypted(t *testing.T) {
+	archiver := &mockArchiver{archives: make(map[string][]byte), stored: make(chan struct{}, 1)}
+
+	handler := BodyArchive(ArchiveConfig{Archiver: archiver, Routes: []string{"/"}})(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", http.NoBody))
+
+	<-archiver.stored
+
+	archiver.mu.Lock()
+	defer archiver.mu.Unlock()
+
+	for _, data := range archiver.archives {
+		assert.Contains(t, string(data), `"status":200`)
+		assert.NotContains(t, string(data), "retainUntil")
+	}
+}
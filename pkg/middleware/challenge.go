@@ -0,0 +1,286 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gofr.dev/pkg/log"
+)
+
+const (
+	// HCaptchaVerifyURL is the siteverify endpoint of hCaptcha.
+	HCaptchaVerifyURL = "https://api.hcaptcha.com/siteverify"
+	// TurnstileVerifyURL is the siteverify endpoint of Cloudflare Turnstile.
+	TurnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+
+	// CaptchaTokenHeader carries the response token generated by the CAPTCHA widget on the client.
+	CaptchaTokenHeader = "X-Captcha-Token"
+	// PoWChallengeHeader carries the proof-of-work challenge, it is set by the server on rejected requests
+	// and has to be sent back along with PoWNonceHeader.
+	PoWChallengeHeader = "X-PoW-Challenge"
+	// PoWNonceHeader carries the nonce which solves the challenge.
+	PoWNonceHeader = "X-PoW-Nonce"
+
+	defaultCaptchaTimeout = 5 * time.Second
+	defaultPoWDifficulty  = 20
+	defaultPoWTTL         = 5 * time.Minute
+	powRandomBytes        = 16
+	powChallengeParts     = 4
+)
+
+// ChallengeRoutes maps a regex of the path to the methods which require a challenge. All the methods of a
+// matching path require a challenge when no methods are given.
+type ChallengeRoutes map[string][]string
+
+// CaptchaOptions configures the verification of CAPTCHA tokens. Any provider implementing the siteverify API,
+// like hCaptcha or Turnstile, can be used.
+type CaptchaOptions struct {
+	VerifyURL string
+	Secret    string
+	// Client is used to call VerifyURL, a client with a 5 second timeout is used when it is nil.
+	Client *http.Client
+}
+
+// ProofOfWorkOptions configures the stateless proof-of-work challenge.
+type ProofOfWorkOptions struct {
+	// Secret is used to sign the challenges, so that the server does not have to store them.
+	Secret []byte
+	// Difficulty is the number of leading zero bits required in the hash of the solution, default is 20.
+	Difficulty int
+	// TTL is the duration for which a challenge is valid, default is 5 minutes.
+	TTL time.Duration
+}
+
+// Captcha middleware verifies the token in the X-Captcha-Token header with the CAPTCHA provider,
+// for the requests matching the routes.
+func Captcha(logger log.Logger, opts CaptchaOptions, routes ChallengeRoutes) func(inner http.Handler) http.Handler {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: defaultCaptchaTimeout}
+	}
+
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !challengeRequired(logger, routes, r) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			if err := verifyCaptcha(logger, &opts, r); err != nil {
+				challengeError(w, r, logger, err)
+				return
+			}
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
+func verifyCaptcha(logger log.Logger, opts *CaptchaOptions, r *http.Request) error {
+	token := r.Header.Get(CaptchaTokenHeader)
+	if token == "" {
+		return ErrChallengeNeeded
+	}
+
+	form := url.Values{"secret": {opts.Secret}, "response": {token}}
+
+	if ip, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		form.Set("remoteip", ip)
+	}
+
+	resp, err := opts.Client.PostForm(opts.VerifyURL, form)
+	if err != nil {
+		logger.Errorf("CAPTCHA verification failed, error: %v", err)
+		return ErrServiceDown
+	}
+
+	defer resp.Body.Close()
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		logger.Errorf("invalid CAPTCHA verification response, error: %v", err)
+		return ErrServiceDown
+	}
+
+	if !result.Success {
+		logger.Debugf("CAPTCHA verification failed with error codes: %v", result.ErrorCodes)
+		return ErrChallengeFailed
+	}
+
+	return nil
+}
+
+// ProofOfWork middleware requires the client to solve a proof-of-work challenge for the requests matching the
+// routes. A rejected request gets a new challenge in the X-PoW-Challenge header, the client has to find a nonce
+// for which the SHA-256 of "challenge:nonce" has Difficulty leading zero bits, and retry the request with both
+// the challenge and the nonce. Challenges are signed, hence no state is kept on the server, a solved challenge
+// can be reused until it expires.
+func ProofOfWork(logger log.Logger, opts ProofOfWorkOptions, routes ChallengeRoutes) func(inner http.Handler) http.Handler {
+	if opts.Difficulty <= 0 {
+		opts.Difficulty = defaultPoWDifficulty
+	}
+
+	if opts.TTL <= 0 {
+		opts.TTL = defaultPoWTTL
+	}
+
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !challengeRequired(logger, routes, r) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			if err := verifyProofOfWork(&opts, r.Header.Get(PoWChallengeHeader), r.Header.Get(PoWNonceHeader)); err != nil {
+				challenge, genErr := newPoWChallenge(&opts)
+				if genErr != nil {
+					logger.Errorf("unable to generate proof-of-work challenge, error: %v", genErr)
+					challengeError(w, r, logger, ErrServiceDown)
+
+					return
+				}
+
+				w.Header().Set(PoWChallengeHeader, challenge)
+				challengeError(w, r, logger, err)
+
+				return
+			}
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SolveProofOfWork returns the nonce which solves the challenge. It is meant for Go clients and tests,
+// browsers are expected to solve the challenge in javascript.
+func SolveProofOfWork(challenge string) string {
+	parts := strings.Split(challenge, ".")
+	if len(parts) != powChallengeParts {
+		return ""
+	}
+
+	difficulty, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return ""
+	}
+
+	for nonce := 0; ; nonce++ {
+		n := strconv.Itoa(nonce)
+		if leadingZeroBits(challenge, n) >= difficulty {
+			return n
+		}
+	}
+}
+
+// newPoWChallenge returns a challenge of the format expiry.difficulty.random.signature
+func newPoWChallenge(opts *ProofOfWorkOptions) (string, error) {
+	b := make([]byte, powRandomBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	payload := fmt.Sprintf("%d.%d.%s", time.Now().Add(opts.TTL).Unix(), opts.Difficulty, hex.EncodeToString(b))
+
+	return payload + "." + signPoW(opts.Secret, payload), nil
+}
+
+func verifyProofOfWork(opts *ProofOfWorkOptions, challenge, nonce string) error {
+	if challenge == "" || nonce == "" {
+		return ErrChallengeNeeded
+	}
+
+	parts := strings.Split(challenge, ".")
+	if len(parts) != powChallengeParts {
+		return ErrChallengeFailed
+	}
+
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(signPoW(opts.Secret, payload))) {
+		return ErrChallengeFailed
+	}
+
+	expiry, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || time.Now().Unix() > expiry {
+		return ErrChallengeFailed
+	}
+
+	// the difficulty is read from the signed challenge, so that changing the configured
+	// difficulty does not invalidate the challenges already issued
+	difficulty, err := strconv.Atoi(parts[1])
+	if err != nil || leadingZeroBits(challenge, nonce) < difficulty {
+		return ErrChallengeFailed
+	}
+
+	return nil
+}
+
+func signPoW(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func leadingZeroBits(challenge, nonce string) int {
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+
+	count := 0
+
+	for _, b := range sum {
+		count += bits.LeadingZeros8(b)
+
+		if b != 0 {
+			break
+		}
+	}
+
+	return count
+}
+
+func challengeRequired(logger log.Logger, routes ChallengeRoutes, r *http.Request) bool {
+	for exp, methods := range routes {
+		ok, err := regexp.MatchString(exp, r.URL.EscapedPath())
+		if err != nil {
+			logger.Errorf("regex error: %v", err)
+
+			continue
+		}
+
+		if !ok {
+			continue
+		}
+
+		if len(methods) == 0 {
+			return true
+		}
+
+		for _, m := range methods {
+			if strings.EqualFold(m, r.Method) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func challengeError(w http.ResponseWriter, r *http.Request, logger log.Logger, err error) {
+	description, code := GetDescription(err)
+	e := FetchErrResponseWithCode(code, description, err.Error())
+	ErrorResponse(w, r, logger, *e)
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+func TestCaptcha(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+
+		if r.PostForm.Get("secret") == "secret" && r.PostForm.Get("response") == "valid" {
+			_, _ = w.Write([]byte(`{"success": true}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+	}))
+	defer provider.Close()
+
+	routes := ChallengeRoutes{"^/signup$": {http.MethodPost}, "^/password-reset$": nil}
+	handler := Captcha(log.NewMockLogger(io.Discard), CaptchaOptions{VerifyURL: provider.URL, Secret: "secret"}, routes)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		desc       string
+		method     string
+		target     string
+		token      string
+		statusCode int
+	}{
+		{"valid token", http.MethodPost, "/signup", "valid", http.StatusOK},
+		{"invalid token", http.MethodPost, "/signup", "invalid", http.StatusForbidden},
+		{"missing token", http.MethodPost, "/signup", "", http.StatusForbidden},
+		{"method not protected", http.MethodGet, "/signup", "", http.StatusOK},
+		{"all methods protected", http.MethodGet, "/password-reset", "", http.StatusForbidden},
+		{"route not protected", http.MethodPost, "/login", "", http.StatusOK},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(tc.method, tc.target, http.NoBody)
+
+		if tc.token != "" {
+			r.Header.Set(CaptchaTokenHeader, tc.token)
+		}
+
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.statusCode, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestProofOfWork(t *testing.T) {
+	opts := ProofOfWorkOptions{Secret: []byte("secret"), Difficulty: 8}
+	handler := ProofOfWork(log.NewMockLogger(io.Discard), opts, ChallengeRoutes{"^/signup$": nil})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// the first request is rejected with a challenge
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/signup", http.NoBody))
+
+	challenge := w.Header().Get(PoWChallengeHeader)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotEmpty(t, challenge)
+
+	solution := SolveProofOfWork(challenge)
+	tampered := strings.Replace(challenge, ".8.", ".1.", 1)
+
+	tests := []struct {
+		desc       string
+		challenge  string
+		nonce      string
+		statusCode int
+	}{
+		{"solved challenge", challenge, solution, http.StatusOK},
+		{"wrong nonce", challenge, "not-a-solution", http.StatusForbidden},
+		{"tampered difficulty", tampered, SolveProofOfWork(tampered), http.StatusForbidden},
+		{"malformed challenge", "abc", "1", http.StatusForbidden},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/signup", http.NoBody)
+		r.Header.Set(PoWChallengeHeader, tc.challenge)
+		r.Header.Set(PoWNonceHeader, tc.nonce)
+
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.statusCode, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func Test_verifyProofOfWorkExpired(t *testing.T) {
+	opts := &ProofOfWorkOptions{Secret: []byte("secret"), Difficulty: 1, TTL: -time.Minute}
+
+	challenge, err := newPoWChallenge(opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ErrChallengeFailed, verifyProofOfWork(opts, challenge, SolveProofOfWork(challenge)))
+	assert.Equal(t, ErrChallengeNeeded, verifyProofOfWork(opts, "", ""))
+}
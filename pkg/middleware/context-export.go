@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ContextHeaderPrefix is the prefix of the headers in which the exported values of the context are forwarded to the
+// downstream services, ex: X-Context-Tenant.
+const ContextHeaderPrefix = "X-Context-"
+
+type exportedValues string
+
+// ExportedValuesKey is the key of the values of the context of a request which are forwarded to the downstream services.
+const ExportedValuesKey exportedValues = "exportedValues"
+
+// WithExportedValues returns the context with the values added to its exported values, keyed by their names, ex:
+// tenant. The exported values of the context are not modified.
+func WithExportedValues(ctx context.Context, values map[string]string) context.Context {
+	if len(values) == 0 {
+		return ctx
+	}
+
+	current := ExportedValues(ctx)
+
+	merged := make(map[string]string, len(current)+len(values))
+	for k, v := range current {
+		merged[k] = v
+	}
+
+	for k, v := range values {
+		merged[strings.ToLower(k)] = v
+	}
+
+	return context.WithValue(ctx, ExportedValuesKey, merged)
+}
+
+// ExportedValues returns the exported values of the context, keyed by their names. It must not be modified.
+func ExportedValues(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+
+	values, _ := ctx.Value(ExportedValuesKey).(map[string]string)
+
+	return values
+}
+
+// ExportHeaders returns the headers of the exported values of the context, ex: X-Context-Tenant, the values which are
+// empty are not exported.
+func ExportHeaders(ctx context.Context) map[string]string {
+	values := ExportedValues(ctx)
+	if len(values) == 0 {
+		return nil
+	}
+
+	headers := make(map[string]string, len(values))
+
+	for name, v := range values {
+		if v != "" {
+			headers[ContextHeader(name)] = v
+		}
+	}
+
+	return headers
+}
+
+// ContextHeader returns the header in which the exported value of the name is forwarded, ex: X-Context-Tenant.
+func ContextHeader(name string) string {
+	return http.CanonicalHeaderKey(ContextHeaderPrefix + name)
+}
+
+// ImportHeaders returns the exported values of the names from the headers, which are sent by the upstream service.
+func ImportHeaders(names []string, header func(key string) string) map[string]string {
+	values := make(map[string]string)
+
+	for _, name := range names {
+		if v := header(ContextHeader(name)); v != "" {
+			values[strings.ToLower(name)] = v
+		}
+	}
+
+	return values
+}
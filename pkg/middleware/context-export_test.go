@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithExportedValues(t *testing.T) {
+	ctx := WithExportedValues(context.Background(), map[string]string{"Tenant": "acme"})
+	child := WithExportedValues(ctx, map[string]string{"locale": "de", "tenant": "globex"})
+
+	assert.Equal(t, map[string]string{"tenant": "acme"}, ExportedValues(ctx), "the parent values are not modified")
+	assert.Equal(t, map[string]string{"tenant": "globex", "locale": "de"}, ExportedValues(child))
+	assert.Equal(t, ctx, WithExportedValues(ctx, nil), "the context is returned as it is without values")
+	assert.Nil(t, ExportedValues(context.Background()))
+}
+
+func TestExportHeaders(t *testing.T) {
+	ctx := WithExportedValues(context.Background(), map[string]string{"tenant": "acme", "feature-flags": "beta",
+		"locale": ""})
+
+	assert.Equal(t, map[string]string{"X-Context-Tenant": "acme", "X-Context-Feature-Flags": "beta"}, ExportHeaders(ctx))
+	assert.Nil(t, ExportHeaders(context.Background()))
+}
+
+func TestImportHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Context-Tenant", "acme")
+	h.Set("X-Context-Subject", "user-1")
+
+	assert.Equal(t, map[string]string{"tenant": "acme"}, ImportHeaders([]string{"tenant", "locale"}, h.Get),
+		"only the values of the names are imported")
+}
@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	devErrorKey contextKey = "devError"
+
+	// maxDevErrorLogs bounds the logs of a request shown in the error page, the further logs are only written to the output
+	maxDevErrorLogs = 64 * 1024
+)
+
+// devError collects the details of a request which are shown in the error page, ie: its logs and its panic.
+type devError struct {
+	mu        sync.Mutex
+	logs      bytes.Buffer
+	truncated bool
+	panic     *PanicReport
+}
+
+func (d *devError) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.logs.Len()+len(p) > maxDevErrorLogs {
+		d.truncated = true
+		return len(p), nil
+	}
+
+	return d.logs.Write(p)
+}
+
+func (d *devError) setPanic(report *PanicReport) {
+	d.mu.Lock()
+	d.panic = report
+	d.mu.Unlock()
+}
+
+// DevErrorLogs returns the writer to which the logs of the request are to be written, so that they are shown in its
+// error page. It returns nil when the request is not served by DevErrorPage.
+func DevErrorLogs(ctx context.Context) io.Writer {
+	if d, ok := ctx.Value(devErrorKey).(*devError); ok {
+		return d
+	}
+
+	return nil
+}
+
+// DevErrorPage renders an HTML page for the server errors of the requests from a browser, with the error, the stack
+// trace of a panic, the details of the request and its logs, in place of the JSON error. It must only be used in
+// development, as the page discloses the internals of the application, and it has to wrap the Recover middleware so
+// that the panics are shown as well.
+func DevErrorPage(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept"), "text/html") {
+			inner.ServeHTTP(w, r)
+			return
+		}
+
+		d := &devError{}
+		r = r.WithContext(context.WithValue(r.Context(), devErrorKey, d))
+
+		dw := &devErrorWriter{ResponseWriter: w}
+
+		inner.ServeHTTP(dw, r)
+
+		if dw.status >= http.StatusInternalServerError {
+			renderDevErrorPage(w, r, dw, d)
+		}
+	})
+}
+
+// devErrorWriter holds back the responses of the server errors, which are replaced by the error page.
+type devErrorWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *devErrorWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+
+	w.status = status
+
+	if status < http.StatusInternalServerError {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *devErrorWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.status >= http.StatusInternalServerError {
+		return w.body.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush is ignored for the server errors, as their response is held back for the error page.
+func (w *devErrorWriter) Flush() {
+	if w.status >= http.StatusInternalServerError {
+		return
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+type devErrorPageData struct {
+	Status    int
+	Text      string
+	Error     string
+	Method    string
+	URL       string
+	Route     string
+	Headers   []string
+	Stack     []StackFrame
+	Response  string
+	Logs      []string
+	Truncated bool
+}
+
+func renderDevErrorPage(w http.ResponseWriter, r *http.Request, dw *devErrorWriter, d *devError) {
+	data := devErrorPageData{Status: dw.status, Text: http.StatusText(dw.status), Method: r.Method,
+		URL: r.URL.String(), Response: dw.body.String()}
+
+	if err := HandlerError(r); err != nil {
+		data.Error = err.Error()
+	}
+
+	if route := mux.CurrentRoute(r); route != nil {
+		data.Route, _ = route.GetPathTemplate()
+	}
+
+	for k, v := range archivedHeaders(r.Header) {
+		data.Headers = append(data.Headers, k+": "+v)
+	}
+
+	sort.Strings(data.Headers)
+
+	d.mu.Lock()
+
+	if d.panic != nil {
+		data.Error, data.Stack = "panic: "+d.panic.Message, d.panic.Stack
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(d.logs.Bytes()))
+	scanner.Buffer(nil, maxDevErrorLogs)
+
+	for scanner.Scan() {
+		data.Logs = append(data.Logs, scanner.Text())
+	}
+
+	data.Truncated = d.truncated
+
+	d.mu.Unlock()
+
+	var page bytes.Buffer
+
+	if err := devErrorTemplate.Execute(&page, data); err != nil {
+		page.Reset()
+		fmt.Fprintf(&page, "%d %s: %s", data.Status, data.Text, data.Error)
+	}
+
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(dw.status)
+	_, _ = w.Write(page.Bytes())
+}
+
+//nolint:gochecknoglobals // the template is parsed once
+var devErrorTemplate = template.Must(template.New("devError").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Status}} {{.Text}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 { color: #b00020; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: .2em; }
+pre { background: #f6f6f6; padding: 1em; overflow-x: auto; }
+.note { color: #777; }
+</style>
+</head>
+<body>
+<h1>{{.Status}} {{.Text}}</h1>
+<pre>{{.Error}}</pre>
+<p class="note">This page is shown as APP_ENV is dev, the clients in other environments get the JSON error.</p>
+{{if .Stack}}<h2>Stack trace</h2>
+<pre>{{range .Stack}}{{.Function}}
+	{{.File}}:{{.Line}}
+{{end}}</pre>{{end}}
+<h2>Request</h2>
+<pre>{{.Method}} {{.URL}}{{if .Route}}
+Route: {{.Route}}{{end}}
+
+{{range .Headers}}{{.}}
+{{end}}</pre>
+{{if .Response}}<h2>Response</h2>
+<pre>{{.Response}}</pre>{{end}}
+<h2>Logs</h2>
+<pre>{{range .Logs}}{{.}}
+{{else}}No logs are recorded for the request.
+{{end}}{{if .Truncated}}...
+{{end}}</pre>
+</body>
+</html>
+`))
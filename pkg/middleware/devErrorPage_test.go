@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/log"
+)
+
+// devErrorHandler logs the request, and fails the requests to /error and /panic
+func devErrorHandler(w http.ResponseWriter, r *http.Request) {
+	if logs := DevErrorLogs(r.Context()); logs != nil {
+		_, _ = fmt.Fprintln(logs, `{"message":"fetching the order"}`)
+	}
+
+	switch r.URL.Path {
+	case "/error":
+		SetHandlerError(r, errors.Error("order could not be fetched"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"errors":[{"code":"Internal Server Error"}]}`))
+	case "/panic":
+		panic("nil order")
+	default:
+		_, _ = w.Write([]byte(`{"data":"order"}`))
+	}
+}
+
+func TestDevErrorPage(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(DevErrorPage, Recover(log.NewMockLogger(io.Discard)))
+	router.PathPrefix("/").HandlerFunc(devErrorHandler)
+
+	tests := []struct {
+		desc        string
+		target      string
+		accept      string
+		status      int
+		contentType string
+		body        []string
+	}{
+		{"error of a browser request", "/error", "text/html,application/xhtml+xml", http.StatusInternalServerError,
+			"text/html; charset=utf-8", []string{"order could not be fetched", "fetching the order", "GET /error",
+				"Accept: text/html", "Internal Server Error"}},
+		{"panic of a browser request", "/panic", "text/html", http.StatusInternalServerError, "text/html; charset=utf-8",
+			[]string{"panic: nil order", "middleware.devErrorHandler", "devErrorPage_test.go"}},
+		{"error of an API request", "/error", "application/json", http.StatusInternalServerError, "application/json",
+			[]string{`{"errors":[{"code":"Internal Server Error"}]}`}},
+		{"success of a browser request", "/orders", "text/html", http.StatusOK, "", []string{`{"data":"order"}`}},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, tc.target, http.NoBody)
+		r.Header.Set("Accept", tc.accept)
+
+		ctx, _ := WithErrorCarrier(r.Context())
+		r = r.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		for _, b := range tc.body {
+			assert.Contains(t, w.Body.String(), b, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestDevErrorLogs_NotServed(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+
+	assert.Nil(t, DevErrorLogs(r.Context()))
+}
@@ -10,17 +10,26 @@ func (e Error) Error() string {
 }
 
 const (
-	ErrInvalidToken    = Error("invalid_token")
-	ErrInvalidRequest  = Error("invalid_request")
-	ErrServiceDown     = Error("service_unavailable")
-	ErrInvalidHeader   = Error("invalid_header")
-	ErrMissingHeader   = Error("missing_header")
-	ErrUnauthorised    = Error("missing_permission")
-	ErrUnauthenticated = Error("failed_auth")
-	ErrInvalidAppKey   = Error("app_key_should_be_more_than_12_bytes")
+	ErrInvalidToken     = Error("invalid_token")
+	ErrInvalidRequest   = Error("invalid_request")
+	ErrServiceDown      = Error("service_unavailable")
+	ErrInvalidHeader    = Error("invalid_header")
+	ErrMissingHeader    = Error("missing_header")
+	ErrUnauthorised     = Error("missing_permission")
+	ErrUnauthenticated  = Error("failed_auth")
+	ErrInvalidAppKey    = Error("app_key_should_be_more_than_12_bytes")
+	ErrChallengeNeeded  = Error("challenge_required")
+	ErrChallengeFailed  = Error("challenge_failed")
+	ErrMissingSignature = Error("missing_signature")
+	ErrInvalidSignature = Error("invalid_signature")
+	ErrOverloaded       = Error("overloaded")
+	ErrRateLimited      = Error("rate_limited")
+	ErrQuotaExceeded    = Error("quota_exceeded")
 )
 
 // GetDescription maps specific error types to their corresponding descriptions and HTTP status codes.
+//
+//nolint:gocyclo // the errors are mapped in a single switch, so that all the descriptions are in one place
 func GetDescription(err error) (description string, statusCode int) {
 	var authErr = "Authorization error"
 
@@ -46,6 +55,27 @@ func GetDescription(err error) (description string, statusCode int) {
 	case ErrUnauthenticated:
 		description = authErr
 		statusCode = http.StatusUnauthorized
+	case ErrChallengeNeeded:
+		description = "Verification challenge response is missing"
+		statusCode = http.StatusForbidden
+	case ErrChallengeFailed:
+		description = "Verification challenge failed"
+		statusCode = http.StatusForbidden
+	case ErrMissingSignature:
+		description = "Missing service signature"
+		statusCode = http.StatusUnauthorized
+	case ErrInvalidSignature:
+		description = "The service signature is invalid or has expired"
+		statusCode = http.StatusUnauthorized
+	case ErrOverloaded:
+		description = "Too many requests are in progress, retry later"
+		statusCode = http.StatusServiceUnavailable
+	case ErrRateLimited:
+		description = "The rate limit of the tenant is exceeded, retry later"
+		statusCode = http.StatusTooManyRequests
+	case ErrQuotaExceeded:
+		description = "The quota of the tenant is exhausted for the period"
+		statusCode = http.StatusTooManyRequests
 	}
 
 	return description, statusCode
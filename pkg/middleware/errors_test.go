@@ -20,6 +20,9 @@ func Test_GetDescription(t *testing.T) {
 		{"invalid Header", ErrInvalidHeader, "Invalid Authorization header", 400},
 		{"unauthorized", ErrUnauthorised, "Authorization error", 403},
 		{"Unauthenticated", ErrUnauthenticated, "Authorization error", 401},
+		{"challenge required", ErrChallengeNeeded, "Verification challenge response is missing", 403},
+		{"challenge failed", ErrChallengeFailed, "Verification challenge failed", 403},
+		{"overloaded", ErrOverloaded, "Too many requests are in progress, retry later", 503},
 	}
 	for i, tc := range tests {
 		desc, output := GetDescription(tc.input)
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+const errorCarrierKey contextKey = "errorCarrier"
+
+// ErrorCarrier holds the error returned by the handler of a request. It is added to the request context by the
+// Logging middleware, so that the handler can record its error without replacing the request, and the middlewares
+// wrapping the handler can read the error after the handler returns.
+type ErrorCarrier struct {
+	mu  sync.RWMutex
+	err error
+}
+
+// Set records the error of the handler.
+func (e *ErrorCarrier) Set(err error) {
+	e.mu.Lock()
+	e.err = err
+	e.mu.Unlock()
+}
+
+// Err returns the error recorded by the handler, if any.
+func (e *ErrorCarrier) Err() error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.err
+}
+
+// WithErrorCarrier returns a copy of ctx carrying a new ErrorCarrier, along with the carrier.
+func WithErrorCarrier(ctx context.Context) (context.Context, *ErrorCarrier) {
+	carrier := &ErrorCarrier{}
+
+	return context.WithValue(ctx, errorCarrierKey, carrier), carrier
+}
+
+// SetHandlerError records the error of the handler in the ErrorCarrier of the request. It returns false
+// when the request does not have an ErrorCarrier.
+func SetHandlerError(r *http.Request, err error) bool {
+	carrier, ok := r.Context().Value(errorCarrierKey).(*ErrorCarrier)
+	if !ok {
+		return false
+	}
+
+	carrier.Set(err)
+
+	return true
+}
+
+// HandlerError returns the error recorded by the handler of the request, it returns nil when no error was recorded.
+func HandlerError(r *http.Request) error {
+	carrier, ok := r.Context().Value(errorCarrierKey).(*ErrorCarrier)
+	if !ok {
+		return nil
+	}
+
+	return carrier.Err()
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/log"
+)
+
+func TestHandlerError(t *testing.T) {
+	err := errors.Error("handler failed")
+
+	req := httptest.NewRequest(http.MethodGet, "/dummy", http.NoBody)
+
+	assert.False(t, SetHandlerError(req, err), "request without a carrier should not record the error")
+	assert.Nil(t, HandlerError(req))
+
+	ctx, carrier := WithErrorCarrier(context.Background())
+	req = req.WithContext(ctx)
+
+	assert.True(t, SetHandlerError(req, err))
+	assert.Equal(t, err, HandlerError(req))
+	assert.Equal(t, err, carrier.Err())
+}
+
+func TestLogging_HandlerError(t *testing.T) {
+	b := new(bytes.Buffer)
+	logger := log.NewMockLogger(b)
+
+	var reqInHandler *http.Request
+
+	handler := Logging(logger, "")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqInHandler = r
+
+		SetHandlerError(r, errors.Error("carrier-error"))
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/dummy", http.NoBody)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.NotSame(t, req, reqInHandler, "the request of the caller should not be modified")
+	assert.Nil(t, req.Context().Value(CorrelationIDKey), "the request of the caller should not be modified")
+	assert.True(t, strings.Contains(b.String(), "carrier-error"), "expected the handler error in the logs, got: %v", b.String())
+}
@@ -73,6 +73,7 @@ func ValidateHeaders(envHeaders string, logger logger) func(inner http.Handler)
 func ExemptPath(r *http.Request) bool {
 	return strings.HasSuffix(r.URL.Path, "/metrics") ||
 		strings.HasSuffix(r.URL.Path, "/.well-known/health-check") || strings.HasSuffix(r.URL.Path, "/.well-known/heartbeat") ||
+		strings.HasSuffix(r.URL.Path, "/.well-known/ready") || strings.HasSuffix(r.URL.Path, "/.well-known/routes") ||
 		strings.HasSuffix(r.URL.Path, "/.well-known/openapi.json") || strings.Contains(r.URL.Path, "/swagger") ||
 		strings.Contains(r.URL.Path, "/.well-known/swagger")
 }
@@ -10,6 +10,7 @@ type (
 	authenticatedUserID string
 	authorizationHeader string
 	b3TraceID           string
+	tenantID            string
 )
 
 const (
@@ -17,6 +18,7 @@ const (
 	AuthenticatedUserIDKey authenticatedUserID = "authUserID"
 	AuthorizationHeader    authorizationHeader = "authorization"
 	B3TraceIDKey           b3TraceID           = "b3traceID"
+	TenantIDKey            tenantID            = "tenantID"
 )
 
 // PropagateHeaders propagates all the required headers through the context
@@ -26,6 +28,7 @@ func PropagateHeaders(inner http.Handler) http.Handler {
 		authUserID := r.Header.Get("X-Authenticated-UserId")
 		authorizationHeader := r.Header.Get("Authorization")
 		b3TraceID := r.Header.Get("X-B3-TraceID")
+		tenant := r.Header.Get("X-Tenant-ID")
 
 		ctx := context.WithValue(r.Context(), ClientIPKey, trueClientIP)
 
@@ -37,12 +40,14 @@ func PropagateHeaders(inner http.Handler) http.Handler {
 			ctx = context.WithValue(ctx, AuthenticatedUserIDKey, authUserID)
 		}
 
+		if tenant != "" {
+			ctx = context.WithValue(ctx, TenantIDKey, tenant)
+		}
+
 		if authorizationHeader != "" {
 			ctx = context.WithValue(ctx, AuthorizationHeader, authorizationHeader)
 		}
 
-		*r = *r.Clone(ctx)
-
-		inner.ServeHTTP(w, r)
+		inner.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
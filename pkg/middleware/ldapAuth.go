@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+
+	"gofr.dev/pkg/log"
+)
+
+// LDAPAuthenticator validates the credentials of a user against a directory, and returns the groups of the user.
+// datastore.LDAP satisfies this interface.
+type LDAPAuthenticator interface {
+	Authenticate(username, password string) ([]string, error)
+}
+
+// LDAPAuth middleware authenticates every request, except the well-known endpoints, using the basic auth credentials
+// against the LDAPAuthenticator. If a path matches a regex in regexToMethodGroup, the user must also be a member of
+// one of the groups configured for the request method.
+func LDAPAuth(logger log.Logger, authenticator LDAPAuthenticator,
+	regexToMethodGroup map[string][]MethodGroup) func(inner http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		if authenticator == nil {
+			logger.Warn("LDAP Auth Middleware not enabled due to nil authenticator")
+			return inner
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ExemptPath(r) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			if err := validateLDAPUser(logger, authenticator, regexToMethodGroup, r); err != nil {
+				description, code := GetDescription(err)
+				e := FetchErrResponseWithCode(code, description, err.Error())
+				ErrorResponse(w, r, logger, *e)
+
+				return
+			}
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
+func validateLDAPUser(logger log.Logger, authenticator LDAPAuthenticator, regexToMethodGroup map[string][]MethodGroup,
+	r *http.Request) error {
+	user, pass, err := getUsernameAndPassword(r.Header.Get("Authorization"))
+	if err != nil {
+		return err
+	}
+
+	groups, err := authenticator.Authenticate(user, pass)
+	if err != nil {
+		logger.Debugf("LDAP authentication failed for user %v: %v", user, err)
+
+		return ErrUnauthenticated
+	}
+
+	requiredGroups := ldapRequiredGroups(logger, regexToMethodGroup, r)
+
+	entry := CacheEntry{authorized: true, groups: make(map[string]bool, len(groups))}
+	for _, g := range groups {
+		entry.groups[g] = true
+	}
+
+	if !validateGroups(requiredGroups, entry) {
+		return ErrUnauthorised
+	}
+
+	return nil
+}
+
+func ldapRequiredGroups(logger log.Logger, regexToMethodGroup map[string][]MethodGroup, r *http.Request) []string {
+	for exp, mthGrps := range regexToMethodGroup {
+		ok, err := regexp.MatchString(exp, r.URL.EscapedPath())
+		if err != nil {
+			logger.Errorf("regex error: %v", err)
+
+			continue
+		}
+
+		if !ok {
+			continue
+		}
+
+		if groups := getRequiredGroups(mthGrps, r.Method); len(groups) > 0 {
+			return groups
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+type mockAuthenticator struct{}
+
+func (mockAuthenticator) Authenticate(username, password string) ([]string, error) {
+	if username == "user" && password == "pass" {
+		return []string{"readers"}, nil
+	}
+
+	return nil, ErrUnauthenticated
+}
+
+func TestLDAPAuth(t *testing.T) {
+	regexToMethodGroup := map[string][]MethodGroup{
+		"^/books$": {{Method: "GET", Group: "readers"}, {Method: "POST", Group: "writers"}},
+	}
+
+	handler := LDAPAuth(log.NewMockLogger(io.Discard), mockAuthenticator{}, regexToMethodGroup)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	tests := []struct {
+		desc       string
+		method     string
+		target     string
+		authHeader string
+		statusCode int
+	}{
+		// "dXNlcjpwYXNz" is user:pass, "dXNlcjp3cm9uZw==" is user:wrong
+		{"member of required group", http.MethodGet, "/books", "Basic dXNlcjpwYXNz", http.StatusOK},
+		{"not a member of required group", http.MethodPost, "/books", "Basic dXNlcjpwYXNz", http.StatusForbidden},
+		{"no group required", http.MethodGet, "/authors", "Basic dXNlcjpwYXNz", http.StatusOK},
+		{"invalid credentials", http.MethodGet, "/authors", "Basic dXNlcjp3cm9uZw==", http.StatusUnauthorized},
+		{"missing header", http.MethodGet, "/books", "", http.StatusUnauthorized},
+		{"invalid header", http.MethodGet, "/books", "Bearer token", http.StatusBadRequest},
+		{"exempt path", http.MethodGet, "/.well-known/heartbeat", "", http.StatusOK},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(tc.method, tc.target, http.NoBody)
+
+		if tc.authHeader != "" {
+			r.Header.Set("Authorization", tc.authHeader)
+		}
+
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.statusCode, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestLDAPAuth_NilAuthenticator(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	handler := LDAPAuth(log.NewMockLogger(io.Discard), nil, nil)(inner)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -16,7 +16,10 @@ import (
 
 type message string
 
-// ErrorMessage is a constant used for error logging.
+// ErrorMessage is the context key of the error message logged by the Logging middleware.
+//
+// Deprecated: setting the error message in the context requires the request to be replaced, use SetHandlerError
+// to record the error of a handler and HandlerError to read it.
 const ErrorMessage message = "errorMessage"
 
 // StatusResponseWriter defines own Response Writer to be used for logging of status - as http.ResponseWriter does not let us read status.
@@ -33,9 +36,18 @@ func (w *StatusResponseWriter) WriteHeader(status int) {
 	w.ResponseWriter.WriteHeader(status)
 }
 
+// Flush sends the buffered response to the client, so that the streamed responses are not held by the writer
+func (w *StatusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // LogLine represents a structured log entry, including various details like correlation ID, request method, response status, and more.
 type LogLine struct {
 	CorrelationID  string                 `json:"correlationId"`
+	TraceID        string                 `json:"traceId,omitempty"`
+	SpanID         string                 `json:"spanId,omitempty"`
 	Type           string                 `json:"type"`
 	StartTimestamp time.Time              `json:"startTimestamp"`
 	Duration       int64                  `json:"duration"`
@@ -77,8 +89,8 @@ func Logging(logger logger, omitHeaders string) func(inner http.Handler) http.Ha
 			start := time.Now()
 
 			correlationID := getCorrelationID(r)
-			ctx := context.WithValue(r.Context(), CorrelationIDKey, correlationID)
-			*r = *r.Clone(ctx)
+			ctx, _ := WithErrorCarrier(context.WithValue(r.Context(), CorrelationIDKey, correlationID))
+			r = r.WithContext(ctx)
 
 			srw := &StatusResponseWriter{ResponseWriter: w}
 			defer func(res *StatusResponseWriter, req *http.Request) {
@@ -98,6 +110,10 @@ func Logging(logger logger, omitHeaders string) func(inner http.Handler) http.Ha
 
 				l.ErrorMessage = populateMessage(r, res.status)
 
+				if sc := trace.SpanFromContext(req.Context()).SpanContext(); sc.IsValid() {
+					l.TraceID, l.SpanID = sc.TraceID().String(), sc.SpanID().String()
+				}
+
 				if logger != nil {
 					// fetch the appData from request context and generate a map of type map[string]interface{}, if appData is nil
 					// then getAppData will return empty map
@@ -190,6 +206,11 @@ func fetchHeaders(omitHeaders map[string]bool, reqHeaders http.Header) map[strin
 	// Don't want to log the Cookie.
 	delete(headers, "Cookie")
 
+	// the token of X-Debug-Trace enables the debug logs of the requests
+	if _, ok := headers["X-Debug-Trace"]; ok {
+		headers["X-Debug-Trace"] = "xxx-masked-value-xxx"
+	}
+
 	return headers
 }
 
@@ -243,6 +264,10 @@ func populateMessage(r *http.Request, statusCode int) string {
 	var msg string
 
 	if statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices {
+		if err := HandlerError(r); err != nil {
+			return err.Error()
+		}
+
 		msg, _ = r.Context().Value(ErrorMessage).(string)
 	}
 
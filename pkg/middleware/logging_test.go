@@ -170,7 +170,12 @@ func TestLoggingCorrelationID(t *testing.T) {
 func TestLoggingCorrelationContext(t *testing.T) {
 	b := new(bytes.Buffer)
 	logger := log.NewMockLogger(b)
-	handler := Logging(logger, "")(&MockHandler{})
+
+	var cID string
+
+	handler := Logging(logger, "")(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		cID, _ = r.Context().Value(CorrelationIDKey).(string)
+	}))
 
 	const correlationID = "b00ff8de800911ec8f6502bfe7568078"
 
@@ -188,8 +193,6 @@ func TestLoggingCorrelationContext(t *testing.T) {
 		req.Header.Add(tc.header, correlationID)
 		handler.ServeHTTP(MockWriteHandler{}, req)
 
-		cID, _ := req.Context().Value(CorrelationIDKey).(string)
-
 		if cID != correlationID {
 			t.Errorf("TEST[%d], failed.\n%s\nCorrelationID is not present in the request context.", i, tc.desc)
 		}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	metricLabelsKey contextKey = "metricLabels"
+
+	// OtherLabelValue is recorded in place of the values of a label which exceed its limit of distinct values.
+	OtherLabelValue = "other"
+
+	defaultMaxLabelValues = 100
+
+	errLabelsAfterServe = errors.Error("request labels can not be added once the requests are served")
+)
+
+// labelRegistry holds the extra labels of the HTTP response metrics, along with the distinct values seen of each label.
+type labelRegistry struct {
+	mu        sync.Mutex
+	names     []string
+	maxValues int
+	seen      map[string]map[string]bool
+}
+
+//nolint:gochecknoglobals // the labels are shared by the requests, as the metrics are
+var (
+	requestLabels = &labelRegistry{}
+
+	// metricsRegisterer registers the HTTP response metric, it is replaced by the tests
+	metricsRegisterer = prometheus.DefaultRegisterer
+)
+
+// SetRequestLabels adds the labels to the zs_http_response metric, the values of which are set for a request with
+// MetricLabels.Label. A label records up to maxValues distinct values (default is 100), and the values beyond are
+// recorded as OtherLabelValue, so that a label can not create an unbounded number of series. The labels can only be
+// set before the first request is served, as the labels of a metric can not change once it is registered.
+func SetRequestLabels(names []string, maxValues int) error {
+	labels := make([]string, 0, len(httpResponseLabels)+len(names))
+	labels = append(labels, httpResponseLabels...)
+	labels = append(labels, names...)
+
+	vec := prometheus.NewHistogramVec(httpResponseOpts, labels)
+
+	var err error
+
+	registered := true
+
+	httpResponseOnce.Do(func() {
+		registered = false
+
+		if err = metricsRegisterer.Register(vec); err != nil {
+			_ = metricsRegisterer.Register(httpResponse)
+		}
+	})
+
+	if registered {
+		return errLabelsAfterServe
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if maxValues <= 0 {
+		maxValues = defaultMaxLabelValues
+	}
+
+	requestLabels.mu.Lock()
+	defer requestLabels.mu.Unlock()
+
+	httpResponse = vec
+	requestLabels.names = names
+	requestLabels.maxValues = maxValues
+	requestLabels.seen = make(map[string]map[string]bool, len(names))
+
+	return nil
+}
+
+// new returns the labels of a request, it returns nil when no labels are configured.
+func (l *labelRegistry) new() *MetricLabels {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.names) == 0 {
+		return nil
+	}
+
+	return &MetricLabels{registry: l, names: l.names, set: make(map[string]string, len(l.names))}
+}
+
+// bound returns the value, or OtherLabelValue when the label already has the maximum number of distinct values.
+func (l *labelRegistry) bound(name, value string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seen, ok := l.seen[name]
+	if !ok {
+		seen = make(map[string]bool)
+		l.seen[name] = seen
+	}
+
+	if seen[value] {
+		return value
+	}
+
+	if len(seen) >= l.maxValues {
+		return OtherLabelValue
+	}
+
+	seen[value] = true
+
+	return value
+}
+
+// MetricLabels are the values of the extra labels of the HTTP response metrics of a request.
+type MetricLabels struct {
+	registry *labelRegistry
+	names    []string
+
+	mu  sync.Mutex
+	set map[string]string
+}
+
+// RequestMetricLabels returns the metric labels of the request of the context. It returns nil when no labels are
+// configured, on which Label does nothing.
+func RequestMetricLabels(ctx context.Context) *MetricLabels {
+	labels, _ := ctx.Value(metricLabelsKey).(*MetricLabels)
+
+	return labels
+}
+
+// Label sets the value of the label for the request, the labels which are not configured are ignored.
+func (m *MetricLabels) Label(name, value string) {
+	if m == nil || !m.configured(name) {
+		return
+	}
+
+	value = m.registry.bound(name, value)
+
+	m.mu.Lock()
+	m.set[name] = value
+	m.mu.Unlock()
+}
+
+func (m *MetricLabels) configured(name string) bool {
+	for _, n := range m.names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// values returns the values of the labels in the order they are configured, the labels which are not set are empty.
+func (m *MetricLabels) values() []string {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	values := make([]string, len(m.names))
+	for i, n := range m.names {
+		values[i] = m.set[n]
+	}
+
+	return values
+}
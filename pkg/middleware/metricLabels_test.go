@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// resetRequestLabels registers the HTTP response metric in a new registry, and restores the metric after the test.
+func resetRequestLabels(t *testing.T) {
+	registerer, vec, labels := metricsRegisterer, httpResponse, requestLabels
+
+	metricsRegisterer = prometheus.NewRegistry()
+	httpResponse = prometheus.NewHistogramVec(httpResponseOpts, httpResponseLabels)
+	httpResponseOnce = sync.Once{}
+	requestLabels = &labelRegistry{}
+
+	t.Cleanup(func() {
+		metricsRegisterer, httpResponse, requestLabels = registerer, vec, labels
+	})
+}
+
+func TestSetRequestLabels(t *testing.T) {
+	resetRequestLabels(t)
+
+	assert.NoError(t, SetRequestLabels([]string{"tier", "client"}, 2))
+	assert.Equal(t, errLabelsAfterServe, SetRequestLabels([]string{"tier"}, 2), "labels are replaced once registered")
+
+	r := mux.NewRouter()
+	r.Use(PrometheusMiddleware)
+	r.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		labels := RequestMetricLabels(r.Context())
+		labels.Label("tier", r.URL.Query().Get("tier"))
+		labels.Label("user", "ignored")
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		desc  string
+		tier  string
+		label string
+		count uint64
+	}{
+		{"first value", "gold", "gold", 1},
+		{"second value", "silver", "silver", 1},
+		{"value beyond the limit", "bronze", OtherLabelValue, 1},
+		{"value already seen", "gold", "gold", 2},
+	}
+
+	for i, tc := range tests {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders?tier="+tc.tier, http.NoBody))
+
+		observer, err := httpResponse.GetMetricWithLabelValues("/orders", http.MethodGet, "200", tc.label, "")
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		var m dto.Metric
+
+		_ = observer.(prometheus.Metric).Write(&m)
+
+		assert.Equal(t, tc.count, m.GetHistogram().GetSampleCount(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.Equal(t, 3, testutil.CollectAndCount(httpResponse), "series of the label are not bounded")
+}
+
+func TestSetRequestLabels_Invalid(t *testing.T) {
+	resetRequestLabels(t)
+
+	assert.Error(t, SetRequestLabels([]string{"status"}, 0), "duplicate label is added")
+	assert.Nil(t, requestLabels.new(), "labels are configured when they could not be added")
+	assert.Equal(t, 0, testutil.CollectAndCount(httpResponse), "default metric is not kept")
+
+	newTestRouter().ServeHTTP(httptest.NewRecorder(), newTestRequest(http.MethodGet, "/api/v1/orders"))
+
+	assert.Equal(t, 1, testutil.CollectAndCount(httpResponse))
+}
+
+func TestSetRequestLabels_AfterServe(t *testing.T) {
+	resetRequestLabels(t)
+
+	newTestRouter().ServeHTTP(httptest.NewRecorder(), newTestRequest(http.MethodGet, "/api/v1/orders"))
+
+	assert.Equal(t, errLabelsAfterServe, SetRequestLabels([]string{"tier"}, 0))
+}
+
+func TestMetricLabels_Nil(t *testing.T) {
+	var labels *MetricLabels
+
+	labels.Label("tier", "gold")
+
+	assert.Nil(t, labels.values())
+}
@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MirroredHeader is set on the mirrored requests, so that the mirror target can distinguish them, ex: to
+	// skip the side effects which must not happen twice.
+	MirroredHeader = "X-Mirrored-Request"
+
+	defaultMirrorTimeout     = 5 * time.Second
+	defaultMirrorConcurrency = 10
+)
+
+//nolint:gochecknoglobals // the metric is shared by all the mirrored routes
+var mirroredRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "zs_mirrored_requests_total",
+	Help: "Counter of the mirrored requests, by whether the response of the mirror matched the response of the primary",
+}, []string{"method", "result"})
+
+// MirrorDiff is the difference between the response of the application and the response of the mirror target
+// for a mirrored request.
+type MirrorDiff struct {
+	CorrelationID string `json:"correlationId"`
+	Method        string `json:"method"`
+	URI           string `json:"uri"`
+	PrimaryStatus int    `json:"primaryStatus"`
+	MirrorStatus  int    `json:"mirrorStatus,omitempty"`
+	// BodyDiffers is true when the bodies differ. The JSON bodies are compared by their values, so that the order of
+	// the fields and the formatting do not make them differ.
+	BodyDiffers bool   `json:"bodyDiffers"`
+	Primary     []byte `json:"primary,omitempty"`
+	Mirror      []byte `json:"mirror,omitempty"`
+	// Error is the error in calling the mirror target.
+	Error string `json:"error,omitempty"`
+}
+
+// MirrorConfig configures the routes whose traffic is mirrored, and the target to which it is mirrored.
+type MirrorConfig struct {
+	// Target is the base URL of the mirror target, ex: http://orders-v2:8000
+	Target string
+	// Routes are the path prefixes of the mirrored routes, optionally preceded by the method, ex: "GET /orders".
+	// All the routes are mirrored when it is empty.
+	Routes []string
+	// Percentage of the requests which are mirrored, from 0 to 100.
+	Percentage float64
+	// Client calls the mirror target. Default is a client with a timeout of 5 seconds.
+	Client *http.Client
+	// UnsafeMethods mirrors the requests of all the methods, by default only the requests of the safe methods, GET,
+	// HEAD and OPTIONS, are mirrored, as the other requests would make the changes twice when the mirror target
+	// shares the datastores or the downstream services of the application.
+	UnsafeMethods bool
+	// ForwardCredentials sends the Authorization, Proxy-Authorization and Cookie headers to the mirror target, they
+	// are removed by default, so that the credentials of the users are not disclosed to the mirror target.
+	ForwardCredentials bool
+	// MaxConcurrency is the number of the mirrored requests which are in flight at a time, the requests are not
+	// mirrored while the limit is reached, so that a slow mirror target does not pile up goroutines. Default is 10.
+	MaxConcurrency int
+	// MaxBodySize is the size of the bodies which are compared. The requests with a larger body are not mirrored,
+	// and only the status is compared for the responses with a larger body. Default is 1 MB.
+	MaxBodySize int64
+	// Recorder records the differences, ex: to a log or a table. The differences are logged when it is nil.
+	Recorder func(ctx context.Context, diff *MirrorDiff)
+	Logger   logger
+
+	inFlight chan struct{}
+}
+
+// Mirror sends a copy of a percentage of the requests of the configured routes to the mirror target, and records
+// the differences between its responses and the responses of the application, for validating a rewritten service
+// on the production traffic before switching over to it. The mirrored requests are sent after the response is
+// written, so that mirroring neither delays nor changes the responses, and the response of the mirror is discarded.
+// Only the requests of the safe methods are mirrored, and without the credentials, unless configured otherwise.
+func Mirror(cfg MirrorConfig) func(inner http.Handler) http.Handler {
+	_ = prometheus.Register(mirroredRequests)
+
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultArchiveBodySize
+	}
+
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: defaultMirrorTimeout}
+	}
+
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = defaultMirrorConcurrency
+	}
+
+	cfg.inFlight = make(chan struct{}, cfg.MaxConcurrency)
+
+	cfg.Target = strings.TrimRight(cfg.Target, "/")
+
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.mirrored(r) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			req := &limitedBuffer{limit: cfg.MaxBodySize}
+
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = readCloser{Reader: io.TeeReader(r.Body, req), Closer: r.Body}
+			}
+
+			header := r.Header.Clone()
+
+			if !cfg.ForwardCredentials {
+				header.Del("Authorization")
+				header.Del("Proxy-Authorization")
+				header.Del("Cookie")
+			}
+
+			aw := &archiveResponseWriter{ResponseWriter: w, body: limitedBuffer{limit: cfg.MaxBodySize}, status: http.StatusOK}
+
+			inner.ServeHTTP(aw, r)
+
+			// the part of the body which is not read by the handler is read, so that the whole body is mirrored
+			if r.Body != nil && r.Body != http.NoBody {
+				_, _ = io.Copy(io.Discard, io.LimitReader(r.Body, cfg.MaxBodySize+1))
+			}
+
+			if req.truncated {
+				return
+			}
+
+			d := &MirrorDiff{Method: r.Method, URI: r.URL.RequestURI(), PrimaryStatus: aw.status}
+			d.CorrelationID, _ = r.Context().Value(CorrelationIDKey).(string)
+
+			select {
+			case cfg.inFlight <- struct{}{}:
+				go func() {
+					defer func() { <-cfg.inFlight }()
+
+					cfg.mirror(d, header, req.Bytes(), aw.body.Bytes(), aw.body.truncated)
+				}()
+			default:
+				mirroredRequests.WithLabelValues(d.Method, "dropped").Inc()
+			}
+		})
+	}
+}
+
+func (cfg *MirrorConfig) mirrored(r *http.Request) bool {
+	if r.Header.Get(MirroredHeader) != "" || ExemptPath(r) {
+		return false
+	}
+
+	if !cfg.UnsafeMethods && r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions {
+		return false
+	}
+
+	if len(cfg.Routes) > 0 && !matchRoutes(cfg.Routes, r) {
+		return false
+	}
+
+	//nolint:gosec // the sampling of the requests does not need a secure random number
+	return rand.Float64()*100 < cfg.Percentage
+}
+
+func (cfg *MirrorConfig) mirror(d *MirrorDiff, header http.Header, body, primary []byte, truncated bool) {
+	ctx := context.Background()
+
+	req, err := http.NewRequestWithContext(ctx, d.Method, cfg.Target+d.URI, bytes.NewReader(body))
+	if err != nil {
+		cfg.record(ctx, d, "error", err)
+		return
+	}
+
+	req.Header = header
+	req.Header.Set(MirroredHeader, "true")
+
+	resp, err := cfg.Client.Do(req)
+	if err != nil {
+		cfg.record(ctx, d, "error", err)
+		return
+	}
+
+	defer resp.Body.Close()
+
+	mirror, err := io.ReadAll(io.LimitReader(resp.Body, cfg.MaxBodySize+1))
+	if err != nil {
+		cfg.record(ctx, d, "error", err)
+		return
+	}
+
+	d.MirrorStatus = resp.StatusCode
+
+	if !truncated && int64(len(mirror)) <= cfg.MaxBodySize {
+		d.BodyDiffers = !equalBodies(primary, mirror)
+	}
+
+	if d.MirrorStatus == d.PrimaryStatus && !d.BodyDiffers {
+		mirroredRequests.WithLabelValues(d.Method, "match").Inc()
+		return
+	}
+
+	d.Primary, d.Mirror = primary, mirror
+
+	cfg.record(ctx, d, "diff", nil)
+}
+
+func (cfg *MirrorConfig) record(ctx context.Context, d *MirrorDiff, result string, err error) {
+	mirroredRequests.WithLabelValues(d.Method, result).Inc()
+
+	if err != nil {
+		d.Error = err.Error()
+	}
+
+	if cfg.Recorder != nil {
+		cfg.Recorder(ctx, d)
+		return
+	}
+
+	switch {
+	case cfg.Logger == nil:
+	case err != nil:
+		cfg.Logger.Errorf("mirroring %v %v failed: %v", d.Method, d.URI, err)
+	default:
+		cfg.Logger.Errorf("mirrored response of %v %v differs, status %v, mirror status %v, body differs %v",
+			d.Method, d.URI, d.PrimaryStatus, d.MirrorStatus, d.BodyDiffers)
+	}
+}
+
+// equalBodies compares the bodies by their JSON values, when both of them are JSON, and byte by byte otherwise.
+func equalBodies(a, b []byte) bool {
+	var x, y interface{}
+
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return bytes.Equal(a, b)
+	}
+
+	return reflect.DeepEqual(x, y)
+}
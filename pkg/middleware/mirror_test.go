@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mirroredRequest struct {
+	method, uri, body, header string
+}
+
+func TestMirror(t *testing.T) {
+	received := make(chan mirroredRequest, 1)
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- mirroredRequest{r.Method, r.URL.RequestURI(), string(body), r.Header.Get(MirroredHeader)}
+
+		if strings.HasPrefix(r.URL.Path, "/orders/2") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// the same values as the primary, in a different order and format
+		_, _ = w.Write([]byte(`{ "status": "ok", "id": 1 }`))
+	}))
+	defer target.Close()
+
+	diffs := make(chan *MirrorDiff, 1)
+
+	handler := Mirror(MirrorConfig{Target: target.URL + "/", Routes: []string{"POST /orders"}, Percentage: 100,
+		UnsafeMethods: true, Recorder: func(_ context.Context, d *MirrorDiff) { diffs <- d }})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the body is not read by the handler, it is still mirrored
+		_, _ = w.Write([]byte(`{"id":1,"status":"ok"}`))
+	}))
+
+	tests := []struct {
+		desc     string
+		method   string
+		target   string
+		mirrored bool
+		diff     *MirrorDiff
+	}{
+		{"route not mirrored", http.MethodGet, "/orders/1", false, nil},
+		{"matching response", http.MethodPost, "/orders/1?v=1", true, nil},
+		{"differing response", http.MethodPost, "/orders/2", true,
+			&MirrorDiff{Method: http.MethodPost, URI: "/orders/2", PrimaryStatus: http.StatusOK, MirrorStatus: http.StatusNotFound,
+				BodyDiffers: true, Primary: []byte(`{"id":1,"status":"ok"}`), Mirror: []byte{}}},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, strings.NewReader(`{"item":"book"}`)))
+
+		assert.Equal(t, `{"id":1,"status":"ok"}`, w.Body.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if !tc.mirrored {
+			select {
+			case <-received:
+				t.Errorf("TEST[%d], failed.\n%s, request is mirrored", i, tc.desc)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			continue
+		}
+
+		select {
+		case r := <-received:
+			assert.Equal(t, mirroredRequest{tc.method, tc.target, `{"item":"book"}`, "true"}, r, "TEST[%d], failed.\n%s", i, tc.desc)
+		case <-time.After(time.Second):
+			t.Fatalf("TEST[%d], failed.\n%s, request is not mirrored", i, tc.desc)
+		}
+
+		select {
+		case d := <-diffs:
+			assert.Equal(t, tc.diff, d, "TEST[%d], failed.\n%s", i, tc.desc)
+		case <-time.After(100 * time.Millisecond):
+			assert.Nil(t, tc.diff, "TEST[%d], failed.\n%s, difference is not recorded", i, tc.desc)
+		}
+	}
+}
+
+func TestMirror_Skipped(t *testing.T) {
+	tests := []struct {
+		desc       string
+		percentage float64
+		header     string
+		method     string
+		path       string
+	}{
+		{"not sampled", 0, "", http.MethodGet, "/orders"},
+		{"mirrored request", 100, "true", http.MethodGet, "/orders"},
+		{"well-known path", 100, "", http.MethodGet, "/.well-known/health-check"},
+		{"unsafe method", 100, "", http.MethodPost, "/orders"},
+	}
+
+	for i, tc := range tests {
+		received := make(chan struct{}, 1)
+
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received <- struct{}{}
+		}))
+
+		noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+		handler := Mirror(MirrorConfig{Target: target.URL, Percentage: tc.percentage})(noop)
+
+		r := httptest.NewRequest(tc.method, tc.path, http.NoBody)
+		if tc.header != "" {
+			r.Header.Set(MirroredHeader, tc.header)
+		}
+
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		select {
+		case <-received:
+			t.Errorf("TEST[%d], failed.\n%s, request is mirrored", i, tc.desc)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		target.Close()
+	}
+}
+
+func TestMirror_Credentials(t *testing.T) {
+	tests := []struct {
+		desc    string
+		forward bool
+		header  http.Header
+	}{
+		{"credentials removed", false, http.Header{"X-Tenant": {"t1"}}},
+		{"credentials forwarded", true, http.Header{"X-Tenant": {"t1"}, "Authorization": {"Bearer token"},
+			"Proxy-Authorization": {"Basic cHJveHk="}, "Cookie": {"session=1"}}},
+	}
+
+	for i, tc := range tests {
+		received := make(chan http.Header, 1)
+
+		target := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			received <- r.Header
+		}))
+
+		noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+		handler := Mirror(MirrorConfig{Target: target.URL, Percentage: 100, ForwardCredentials: tc.forward})(noop)
+
+		r := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+		r.Header.Set("X-Tenant", "t1")
+		r.Header.Set("Authorization", "Bearer token")
+		r.Header.Set("Proxy-Authorization", "Basic cHJveHk=")
+		r.Header.Set("Cookie", "session=1")
+
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		select {
+		case h := <-received:
+			for _, name := range []string{"X-Tenant", "Authorization", "Proxy-Authorization", "Cookie"} {
+				assert.Equal(t, tc.header.Get(name), h.Get(name), "TEST[%d], failed.\n%s, header %v", i, tc.desc, name)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("TEST[%d], failed.\n%s, request is not mirrored", i, tc.desc)
+		}
+
+		target.Close()
+	}
+}
+
+func TestMirror_MaxConcurrency(t *testing.T) {
+	received := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	target := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	defer target.Close()
+
+	noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	handler := Mirror(MirrorConfig{Target: target.URL, Percentage: 100, MaxConcurrency: 1})(noop)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/1", http.NoBody))
+
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("request is not mirrored")
+	}
+
+	// the first mirrored request is still in flight, the second one is not mirrored
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/2", http.NoBody))
+
+	select {
+	case <-received:
+		t.Error("request is mirrored above the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+}
+
+func TestEqualBodies(t *testing.T) {
+	tests := []struct {
+		desc  string
+		a, b  string
+		equal bool
+	}{
+		{"same JSON values", `{"a":1,"b":[1,2]}`, `{ "b": [1, 2], "a": 1 }`, true},
+		{"different JSON values", `{"a":1}`, `{"a":2}`, false},
+		{"same text", `ok`, `ok`, true},
+		{"different text", `ok`, `ok `, false},
+		{"empty bodies", ``, ``, true},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.equal, equalBodies([]byte(tc.a), []byte(tc.b)), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
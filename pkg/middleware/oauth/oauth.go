@@ -50,8 +50,7 @@ func Auth(logger log.Logger, options Options) func(inner http.Handler) http.Hand
 			if err == nil {
 				// if user is verified ,setting it in the context pool
 				ctx := context.WithValue(req.Context(), jwtClaimsKey, token.Claims)
-				*req = *req.Clone(ctx)
-				inner.ServeHTTP(w, req)
+				inner.ServeHTTP(w, req.WithContext(ctx))
 				return
 			}
 			logger.Errorf("Client authentication failed for given token with Error : %v", err)
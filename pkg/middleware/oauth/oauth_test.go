@@ -240,11 +240,18 @@ func Test_RequestClone(t *testing.T) {
 		JWKPath:           getTestServerURL(),
 	}
 
-	handler := Auth(log.NewLogger(), options)(&MockHandlerForOAuth{})
+	var val interface{}
+
+	handler := Auth(log.NewLogger(), options)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		val = r.Context().Value(JWTContextKey("claims"))
+	}))
 	handler.ServeHTTP(w, request)
 
-	val := request.Context().Value(JWTContextKey("claims"))
 	if val == nil {
 		t.Errorf("Test case failed. Expected: %v, got: %v", val, nil)
 	}
+
+	if request.Context().Value(JWTContextKey("claims")) != nil {
+		t.Errorf("Test case failed. The request of the caller should not be modified")
+	}
 }
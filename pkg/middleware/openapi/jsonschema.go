@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io/fs"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	defsRefPrefix        = "#/$defs/"
+	definitionsRefPrefix = "#/definitions/"
+	rootRef              = "#"
+
+	// ErrInvalidJSONSchema is returned when the document cannot be parsed as a JSON Schema.
+	ErrInvalidJSONSchema = errors.Error("invalid JSON Schema document")
+)
+
+// JSONSchema is a parsed JSON Schema document, with the schemas of its $defs and definitions resolvable by $ref.
+type JSONSchema struct {
+	root *Schema
+	refs map[string]*Schema
+}
+
+type jsonSchemaDocument struct {
+	Defs        map[string]*Schema `json:"$defs"`
+	Definitions map[string]*Schema `json:"definitions"`
+}
+
+// LoadJSONSchema reads and parses the JSON Schema document name of fsys, ex: an embed.FS of the schemas of the API.
+func LoadJSONSchema(fsys fs.FS, name string) (*JSONSchema, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJSONSchema(data)
+}
+
+/*
+NewJSONSchema parses a JSON Schema document. The keywords of the OpenAPI schema object are supported, along with the
+type arrays of JSON Schema, ex: ["string", "null"], and the references to the document and to its $defs and
+definitions, ex: #/$defs/Address.
+*/
+func NewJSONSchema(data []byte) (*JSONSchema, error) {
+	var (
+		root Schema
+		doc  jsonSchemaDocument
+	)
+
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, ErrInvalidJSONSchema
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, ErrInvalidJSONSchema
+	}
+
+	s := &JSONSchema{root: &root, refs: map[string]*Schema{rootRef: &root}}
+
+	for name, def := range doc.Defs {
+		s.refs[defsRefPrefix+name] = def
+	}
+
+	for name, def := range doc.Definitions {
+		s.refs[definitionsRefPrefix+name] = def
+	}
+
+	return s, nil
+}
+
+// Validate checks the decoded JSON value against the schema, the error is a ValidationError.
+func (s *JSONSchema) Validate(value interface{}) error {
+	return s.root.Validate(value, s.refs)
+}
+
+// UnmarshalJSON parses the schema, a type array of JSON Schema is read as nullable when it has null, and as anyOf the
+// types when it has more than one other type.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	// alias does not have the UnmarshalJSON method, hence it is decoded by encoding/json
+	type alias Schema
+
+	var raw struct {
+		*alias
+		Type json.RawMessage `json:"type"`
+	}
+
+	raw.alias = (*alias)(s)
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if len(raw.Type) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw.Type, &s.Type); err == nil {
+		return nil
+	}
+
+	var types []string
+
+	if err := json.Unmarshal(raw.Type, &types); err != nil {
+		return err
+	}
+
+	var anyOf []*Schema
+
+	for _, t := range types {
+		if t == "null" {
+			s.Nullable = true
+		} else {
+			anyOf = append(anyOf, &Schema{Type: t})
+		}
+	}
+
+	switch len(anyOf) {
+	case 0:
+	case 1:
+		s.Type = anyOf[0].Type
+	default:
+		s.AnyOf = append(s.AnyOf, anyOf...)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package openapi
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchema_Validate(t *testing.T) {
+	fsys := fstest.MapFS{"create-user.json": {Data: []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string"},
+			"nickname": {"type": ["string", "null"]},
+			"id": {"type": ["string", "integer"]},
+			"address": {"$ref": "#/$defs/Address"},
+			"manager": {"$ref": "#/definitions/User"}
+		},
+		"$defs": {"Address": {"type": "object", "required": ["city"]}},
+		"definitions": {"User": {"$ref": "#"}}
+	}`)}}
+
+	schema, err := LoadJSONSchema(fsys, "create-user.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		desc string
+		body interface{}
+		err  error
+	}{
+		{"valid", map[string]interface{}{"name": "john", "nickname": nil, "id": float64(1)}, nil},
+		{"missing required", map[string]interface{}{}, ValidationError{"name", "required field is missing"}},
+		{"type array", map[string]interface{}{"name": "john", "id": true},
+			ValidationError{"id", "value does not match any of the schemas"}},
+		{"$defs reference", map[string]interface{}{"name": "john", "address": map[string]interface{}{}},
+			ValidationError{"address.city", "required field is missing"}},
+		{"root reference", map[string]interface{}{"name": "john", "manager": map[string]interface{}{"name": 1}},
+			ValidationError{"manager.name", "value must be of type string"}},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.err, schema.Validate(tc.body), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestLoadJSONSchema_Error(t *testing.T) {
+	fsys := fstest.MapFS{"invalid.json": {Data: []byte(`{"type": 1}`)}}
+
+	_, err := LoadJSONSchema(fsys, "invalid.json")
+	assert.Equal(t, ErrInvalidJSONSchema, err)
+
+	_, err = LoadJSONSchema(fsys, "missing.json")
+	assert.Error(t, err)
+}
@@ -0,0 +1,280 @@
+// Package openapi validates HTTP requests and responses against an OpenAPI 3 document at runtime. It is meant
+// to be used in development and staging environments, to catch drift between the handlers and the contract.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Schema is the subset of the OpenAPI schema object used for validation.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Nullable             bool               `json:"nullable"`
+	Enum                 []interface{}      `json:"enum"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	AllOf                []*Schema          `json:"allOf"`
+	AnyOf                []*Schema          `json:"anyOf"`
+	OneOf                []*Schema          `json:"oneOf"`
+
+	Minimum   *float64 `json:"minimum"`
+	Maximum   *float64 `json:"maximum"`
+	MinLength *int     `json:"minLength"`
+	MaxLength *int     `json:"maxLength"`
+	MinItems  *int     `json:"minItems"`
+	MaxItems  *int     `json:"maxItems"`
+	Pattern   string   `json:"pattern"`
+}
+
+// ValidationError describes a value which does not match the schema, Field is the JSON path of the value.
+type ValidationError struct {
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Reason
+	}
+
+	return fmt.Sprintf("%v: %v", e.Field, e.Reason)
+}
+
+// Validate checks the decoded JSON value against the schema. refs resolves the $ref of the schema and its children.
+func (s *Schema) Validate(value interface{}, refs map[string]*Schema) error {
+	return s.validate("", value, refs)
+}
+
+//nolint:gocyclo,gocognit // each case is a separate keyword of the schema, splitting them reduces readability
+func (s *Schema) validate(field string, value interface{}, refs map[string]*Schema) error {
+	s, err := s.resolve(refs)
+	if err != nil {
+		return ValidationError{Field: field, Reason: err.Error()}
+	}
+
+	if value == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+
+		return ValidationError{Field: field, Reason: "value must not be null"}
+	}
+
+	if err = s.validateComposition(field, value, refs); err != nil {
+		return err
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		return ValidationError{Field: field, Reason: fmt.Sprintf("value must be one of %v", s.Enum)}
+	}
+
+	switch s.Type {
+	case "":
+		return nil
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return typeError(field, s.Type)
+		}
+
+		return s.validateObject(field, obj, refs)
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return typeError(field, s.Type)
+		}
+
+		return s.validateArray(field, arr, refs)
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return typeError(field, s.Type)
+		}
+
+		return s.validateString(field, str)
+	case "integer", "number":
+		num, ok := value.(float64)
+		if !ok || (s.Type == "integer" && num != math.Trunc(num)) {
+			return typeError(field, s.Type)
+		}
+
+		return s.validateNumber(field, num)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return typeError(field, s.Type)
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) resolve(refs map[string]*Schema) (*Schema, error) {
+	// a chain of references is followed, with a limit to guard against cycles
+	const maxDepth = 32
+
+	for i := 0; s.Ref != "" && i < maxDepth; i++ {
+		resolved, ok := refs[s.Ref]
+		if !ok {
+			return nil, fmt.Errorf("unresolved reference %v", s.Ref)
+		}
+
+		s = resolved
+	}
+
+	return s, nil
+}
+
+func (s *Schema) validateComposition(field string, value interface{}, refs map[string]*Schema) error {
+	for _, sub := range s.AllOf {
+		if err := sub.validate(field, value, refs); err != nil {
+			return err
+		}
+	}
+
+	if len(s.AnyOf) > 0 && countMatches(s.AnyOf, field, value, refs) == 0 {
+		return ValidationError{Field: field, Reason: "value does not match any of the schemas"}
+	}
+
+	if len(s.OneOf) > 0 && countMatches(s.OneOf, field, value, refs) != 1 {
+		return ValidationError{Field: field, Reason: "value must match exactly one of the schemas"}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateObject(field string, obj map[string]interface{}, refs map[string]*Schema) error {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			return ValidationError{Field: join(field, name), Reason: "required field is missing"}
+		}
+	}
+
+	// additionalProperties is either a boolean or a schema
+	var additional *Schema
+
+	allowAdditional := true
+
+	if len(s.AdditionalProperties) > 0 {
+		if err := json.Unmarshal(s.AdditionalProperties, &allowAdditional); err != nil {
+			allowAdditional = true
+			additional = &Schema{}
+			_ = json.Unmarshal(s.AdditionalProperties, additional)
+		}
+	}
+
+	for name, v := range obj {
+		prop, ok := s.Properties[name]
+
+		switch {
+		case ok:
+			if err := prop.validate(join(field, name), v, refs); err != nil {
+				return err
+			}
+		case !allowAdditional:
+			return ValidationError{Field: join(field, name), Reason: "field is not allowed"}
+		case additional != nil:
+			if err := additional.validate(join(field, name), v, refs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateArray(field string, arr []interface{}, refs map[string]*Schema) error {
+	if s.MinItems != nil && len(arr) < *s.MinItems {
+		return ValidationError{Field: field, Reason: fmt.Sprintf("must have at least %v items", *s.MinItems)}
+	}
+
+	if s.MaxItems != nil && len(arr) > *s.MaxItems {
+		return ValidationError{Field: field, Reason: fmt.Sprintf("must have at most %v items", *s.MaxItems)}
+	}
+
+	if s.Items == nil {
+		return nil
+	}
+
+	for i, v := range arr {
+		if err := s.Items.validate(fmt.Sprintf("%v[%v]", field, i), v, refs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateString(field, str string) error {
+	length := len([]rune(str))
+
+	if s.MinLength != nil && length < *s.MinLength {
+		return ValidationError{Field: field, Reason: fmt.Sprintf("length must be at least %v", *s.MinLength)}
+	}
+
+	if s.MaxLength != nil && length > *s.MaxLength {
+		return ValidationError{Field: field, Reason: fmt.Sprintf("length must be at most %v", *s.MaxLength)}
+	}
+
+	if s.Pattern != "" {
+		ok, err := regexp.MatchString(s.Pattern, str)
+		if err != nil || !ok {
+			return ValidationError{Field: field, Reason: fmt.Sprintf("must match the pattern %v", s.Pattern)}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateNumber(field string, num float64) error {
+	if s.Minimum != nil && num < *s.Minimum {
+		return ValidationError{Field: field, Reason: fmt.Sprintf("must be greater than or equal to %v", *s.Minimum)}
+	}
+
+	if s.Maximum != nil && num > *s.Maximum {
+		return ValidationError{Field: field, Reason: fmt.Sprintf("must be less than or equal to %v", *s.Maximum)}
+	}
+
+	return nil
+}
+
+func countMatches(schemas []*Schema, field string, value interface{}, refs map[string]*Schema) int {
+	matches := 0
+
+	for _, sub := range schemas {
+		if sub.validate(field, value, refs) == nil {
+			matches++
+		}
+	}
+
+	return matches
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func typeError(field, expected string) error {
+	return ValidationError{Field: field, Reason: "value must be of type " + expected}
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+
+	return strings.Join([]string{field, name}, ".")
+}
@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	var schema Schema
+
+	_ = json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["name", "age"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 5, "pattern": "^[a-z]+$"},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"role": {"type": "string", "enum": ["admin", "user"]},
+			"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+			"address": {"$ref": "#/components/schemas/Address"},
+			"nickname": {"type": "string", "nullable": true},
+			"id": {"oneOf": [{"type": "string"}, {"type": "integer"}]}
+		}
+	}`), &schema)
+
+	refs := map[string]*Schema{
+		"#/components/schemas/Address": {Type: "object", Required: []string{"city"}},
+	}
+
+	tests := []struct {
+		desc string
+		body string
+		err  error
+	}{
+		{"valid", `{"name": "john", "age": 30, "tags": ["a"], "address": {"city": "x"}, "nickname": null, "id": 1}`, nil},
+		{"missing required", `{"name": "john"}`, ValidationError{"age", "required field is missing"}},
+		{"wrong type", `{"name": "john", "age": "30"}`, ValidationError{"age", "value must be of type integer"}},
+		{"not an integer", `{"name": "john", "age": 3.5}`, ValidationError{"age", "value must be of type integer"}},
+		{"above maximum", `{"name": "john", "age": 200}`, ValidationError{"age", "must be less than or equal to 150"}},
+		{"too short", `{"name": "j", "age": 1}`, ValidationError{"name", "length must be at least 2"}},
+		{"pattern mismatch", `{"name": "JOHN", "age": 1}`, ValidationError{"name", "must match the pattern ^[a-z]+$"}},
+		{"not in enum", `{"name": "john", "age": 1, "role": "root"}`, ValidationError{"role", "value must be one of [admin user]"}},
+		{"too many items", `{"name": "john", "age": 1, "tags": ["a", "b", "c"]}`, ValidationError{"tags", "must have at most 2 items"}},
+		{"invalid item", `{"name": "john", "age": 1, "tags": [1]}`, ValidationError{"tags[0]", "value must be of type string"}},
+		{"invalid reference", `{"name": "john", "age": 1, "address": {}}`, ValidationError{"address.city", "required field is missing"}},
+		{"additional field", `{"name": "john", "age": 1, "extra": 1}`, ValidationError{"extra", "field is not allowed"}},
+		{"null not allowed", `{"name": null, "age": 1}`, ValidationError{"name", "value must not be null"}},
+		{"oneOf mismatch", `{"name": "john", "age": 1, "id": true}`, ValidationError{"id", "value must match exactly one of the schemas"}},
+	}
+
+	for i, tc := range tests {
+		var value interface{}
+
+		_ = json.Unmarshal([]byte(tc.body), &value)
+
+		err := schema.Validate(value, refs)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestSchema_UnresolvedRef(t *testing.T) {
+	err := (&Schema{Ref: "#/components/schemas/Missing"}).Validate("x", nil)
+
+	assert.Equal(t, ValidationError{Reason: "unresolved reference #/components/schemas/Missing"}, err)
+}
+
+func TestValidationError_Error(t *testing.T) {
+	assert.Equal(t, "age: required field is missing", ValidationError{"age", "required field is missing"}.Error())
+	assert.Equal(t, "request body is required", ValidationError{Reason: "request body is required"}.Error())
+}
@@ -0,0 +1,238 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	schemaRefPrefix      = "#/components/schemas/"
+	parameterRefPrefix   = "#/components/parameters/"
+	requestBodyRefPrefix = "#/components/requestBodies/"
+	responseRefPrefix    = "#/components/responses/"
+
+	jsonContentType = "application/json"
+
+	// ErrInvalidSpec is returned when the document cannot be parsed as an OpenAPI document.
+	ErrInvalidSpec = errors.Error("invalid OpenAPI document")
+)
+
+// Spec is the parsed OpenAPI document, with the path templates compiled for matching requests.
+type Spec struct {
+	routes []route
+	refs   map[string]*Schema
+}
+
+// Operation describes the parameters, request body and responses of a method on a path.
+type Operation struct {
+	Parameters  []*Parameter         `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path, query or header parameter of an operation.
+type Parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody is the request body of an operation, only the JSON content is validated.
+type RequestBody struct {
+	Ref      string               `json:"$ref"`
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a documented response of an operation, only the JSON content is validated.
+type Response struct {
+	Ref     string               `json:"$ref"`
+	Content map[string]MediaType `json:"content"`
+}
+
+// MediaType holds the schema of a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type route struct {
+	template   string
+	regex      *regexp.Regexp
+	params     []string
+	operations map[string]*Operation
+}
+
+type document struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas       map[string]*Schema      `json:"schemas"`
+		Parameters    map[string]*Parameter   `json:"parameters"`
+		RequestBodies map[string]*RequestBody `json:"requestBodies"`
+		Responses     map[string]*Response    `json:"responses"`
+	} `json:"components"`
+}
+
+//nolint:gochecknoglobals // methods is used as a constant
+var methods = map[string]bool{"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true,
+	"patch": true, "trace": true}
+
+// LoadSpec reads and parses the OpenAPI document at path.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSpec(data)
+}
+
+// NewSpec parses an OpenAPI 3 document in JSON format.
+func NewSpec(data []byte) (*Spec, error) {
+	var doc document
+
+	if err := json.Unmarshal(data, &doc); err != nil || doc.Paths == nil {
+		return nil, ErrInvalidSpec
+	}
+
+	spec := &Spec{refs: make(map[string]*Schema, len(doc.Components.Schemas))}
+
+	for name, s := range doc.Components.Schemas {
+		spec.refs[schemaRefPrefix+name] = s
+	}
+
+	for template, item := range doc.Paths {
+		r, err := newRoute(template, item, &doc)
+		if err != nil {
+			return nil, err
+		}
+
+		spec.routes = append(spec.routes, r)
+	}
+
+	// literal paths take precedence over templated paths, ex: /users/me over /users/{id}
+	sort.SliceStable(spec.routes, func(i, j int) bool {
+		if len(spec.routes[i].params) != len(spec.routes[j].params) {
+			return len(spec.routes[i].params) < len(spec.routes[j].params)
+		}
+
+		return spec.routes[i].template < spec.routes[j].template
+	})
+
+	return spec, nil
+}
+
+func newRoute(template string, item map[string]json.RawMessage, doc *document) (route, error) {
+	r := route{template: template, operations: make(map[string]*Operation)}
+
+	// parameters defined on the path are shared by all the operations of the path
+	var shared []*Parameter
+
+	if raw, ok := item["parameters"]; ok {
+		if err := json.Unmarshal(raw, &shared); err != nil {
+			return r, ErrInvalidSpec
+		}
+	}
+
+	for method, raw := range item {
+		if !methods[method] {
+			continue
+		}
+
+		var op Operation
+
+		if err := json.Unmarshal(raw, &op); err != nil {
+			return r, ErrInvalidSpec
+		}
+
+		op.Parameters = resolveParameters(append(append([]*Parameter{}, shared...), op.Parameters...), doc)
+
+		if op.RequestBody != nil && op.RequestBody.Ref != "" {
+			op.RequestBody = doc.Components.RequestBodies[strings.TrimPrefix(op.RequestBody.Ref, requestBodyRefPrefix)]
+		}
+
+		for code, resp := range op.Responses {
+			if resp != nil && resp.Ref != "" {
+				op.Responses[code] = doc.Components.Responses[strings.TrimPrefix(resp.Ref, responseRefPrefix)]
+			}
+		}
+
+		r.operations[strings.ToUpper(method)] = &op
+	}
+
+	pattern := "^" + regexp.QuoteMeta(template) + "$"
+
+	for _, m := range regexp.MustCompile(`\\\{([^/]+?)\\}`).FindAllStringSubmatch(pattern, -1) {
+		r.params = append(r.params, m[1])
+	}
+
+	pattern = regexp.MustCompile(`\\\{[^/]+?\\}`).ReplaceAllString(pattern, "([^/]+)")
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return r, ErrInvalidSpec
+	}
+
+	r.regex = regex
+
+	return r, nil
+}
+
+// resolveParameters replaces the references with the parameters defined in the components. An operation
+// parameter overrides a path parameter with the same name and location.
+func resolveParameters(params []*Parameter, doc *document) []*Parameter {
+	resolved := make([]*Parameter, 0, len(params))
+	index := make(map[string]int, len(params))
+
+	for _, p := range params {
+		if p.Ref != "" {
+			p = doc.Components.Parameters[strings.TrimPrefix(p.Ref, parameterRefPrefix)]
+		}
+
+		if p == nil {
+			continue
+		}
+
+		key := p.In + ":" + p.Name
+		if i, ok := index[key]; ok {
+			resolved[i] = p
+			continue
+		}
+
+		index[key] = len(resolved)
+		resolved = append(resolved, p)
+	}
+
+	return resolved
+}
+
+// Find returns the operation documented for the method and path, along with the values of the path parameters.
+// The returned path template is empty when the path is not documented.
+func (s *Spec) Find(method, path string) (op *Operation, template string, pathParams map[string]string) {
+	for _, r := range s.routes {
+		matches := r.regex.FindStringSubmatch(path)
+		if matches == nil {
+			continue
+		}
+
+		pathParams = make(map[string]string, len(r.params))
+		for i, name := range r.params {
+			pathParams[name] = matches[i+1]
+		}
+
+		return r.operations[method], r.template, pathParams
+	}
+
+	return nil, "", nil
+}
+
+// Refs returns the schemas defined in the components, keyed by their reference.
+func (s *Spec) Refs() map[string]*Schema {
+	return s.refs
+}
@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSpec = `{
+  "openapi": "3.0.1",
+  "paths": {
+    "/users/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+      "get": {
+        "parameters": [{"$ref": "#/components/parameters/Verbose"}],
+        "responses": {
+          "200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+          "4XX": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/users/me": {
+      "get": {"responses": {"200": {"description": "current user"}}}
+    },
+    "/users": {
+      "post": {
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+        },
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "User": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {"name": {"type": "string"}}
+      }
+    },
+    "parameters": {
+      "Verbose": {"name": "verbose", "in": "query", "schema": {"type": "boolean"}}
+    },
+    "responses": {
+      "Error": {"content": {"application/json": {"schema": {"type": "object", "required": ["errors"]}}}}
+    }
+  }
+}`
+
+func TestNewSpec_Find(t *testing.T) {
+	spec, err := NewSpec([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc       string
+		method     string
+		path       string
+		template   string
+		pathParams map[string]string
+		found      bool
+	}{
+		{"templated path", "GET", "/users/10", "/users/{id}", map[string]string{"id": "10"}, true},
+		{"literal path takes precedence", "GET", "/users/me", "/users/me", map[string]string{}, true},
+		{"method not documented", "DELETE", "/users/10", "/users/{id}", map[string]string{"id": "10"}, false},
+		{"path not documented", "GET", "/orders", "", nil, false},
+	}
+
+	for i, tc := range tests {
+		op, template, pathParams := spec.Find(tc.method, tc.path)
+
+		assert.Equal(t, tc.found, op != nil, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.template, template, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.pathParams, pathParams, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestNewSpec_ResolvesReferences(t *testing.T) {
+	spec, _ := NewSpec([]byte(testSpec))
+
+	op, _, _ := spec.Find("GET", "/users/1")
+
+	assert.Len(t, op.Parameters, 2)
+	assert.Equal(t, "verbose", op.Parameters[1].Name)
+	assert.NotNil(t, op.Responses["4XX"].Content["application/json"].Schema)
+	assert.Contains(t, spec.Refs(), "#/components/schemas/User")
+}
+
+func TestNewSpec_Invalid(t *testing.T) {
+	for i, doc := range []string{`not json`, `{"openapi": "3.0.1"}`, `{"paths": {"/a": {"get": []}}}`} {
+		_, err := NewSpec([]byte(doc))
+
+		assert.Equal(t, ErrInvalidSpec, err, "TEST[%d], failed.", i)
+	}
+
+	_, err := LoadSpec("./missing.json")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,258 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
+)
+
+// Mode decides the action taken by the Validator on a mismatch.
+type Mode string
+
+const (
+	// ModeLog logs the mismatches, requests and responses are not modified.
+	ModeLog Mode = "log"
+	// ModeReject responds with 400 for an invalid request, and with 500 for a response which does not match the spec.
+	ModeReject Mode = "reject"
+)
+
+// Validator is a middleware which validates the parameters and JSON body of the requests, and the status code
+// and JSON body of the responses, against the spec. Paths which are not documented are not validated.
+//
+// Responses are buffered to be validated, hence the middleware should not be used with streaming handlers.
+func Validator(logger log.Logger, spec *Spec, mode Mode) func(inner http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if middleware.ExemptPath(r) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			op, template, pathParams := spec.Find(r.Method, r.URL.Path)
+			if op == nil {
+				logger.Debugf("openapi: %v %v is not documented", r.Method, r.URL.Path)
+				inner.ServeHTTP(w, r)
+
+				return
+			}
+
+			if err := spec.validateRequest(op, pathParams, r); err != nil {
+				logger.Warnf("openapi: request %v %v does not match the spec: %v", r.Method, template, err)
+
+				if mode == ModeReject {
+					e := middleware.FetchErrResponseWithCode(http.StatusBadRequest, err.Error(), "Invalid Request")
+					middleware.ErrorResponse(w, r, nil, *e)
+
+					return
+				}
+			}
+
+			rec := &recorder{header: make(http.Header), status: http.StatusOK}
+
+			inner.ServeHTTP(rec, r)
+
+			if err := spec.validateResponse(op, rec); err != nil {
+				logger.Warnf("openapi: response of %v %v does not match the spec: %v", r.Method, template, err)
+
+				if mode == ModeReject {
+					e := middleware.FetchErrResponseWithCode(http.StatusInternalServerError, err.Error(), "Invalid Response")
+					middleware.ErrorResponse(w, r, nil, *e)
+
+					return
+				}
+			}
+
+			rec.flush(w)
+		})
+	}
+}
+
+func (s *Spec) validateRequest(op *Operation, pathParams map[string]string, r *http.Request) error {
+	for _, p := range op.Parameters {
+		value, present := parameterValue(p, pathParams, r)
+
+		if !present {
+			if p.Required {
+				return ValidationError{Field: p.Name, Reason: fmt.Sprintf("required %v parameter is missing", p.In)}
+			}
+
+			continue
+		}
+
+		if p.Schema == nil {
+			continue
+		}
+
+		if err := p.Schema.Validate(coerce(p.Schema, s.refs, value), s.refs); err != nil {
+			return ValidationError{Field: p.Name, Reason: err.Error()}
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	// the body is restored for the handler
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(body) == 0 {
+		if op.RequestBody.Required {
+			return ValidationError{Reason: "request body is required"}
+		}
+
+		return nil
+	}
+
+	return s.validateBody(op.RequestBody.Content, r.Header.Get("Content-Type"), body)
+}
+
+func (s *Spec) validateResponse(op *Operation, rec *recorder) error {
+	code := strconv.Itoa(rec.status)
+
+	resp, ok := op.Responses[code]
+	if !ok {
+		// ranges like 2XX and the default response match any undocumented status code
+		resp, ok = op.Responses[code[:1]+"XX"]
+	}
+
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+
+	if !ok {
+		return ValidationError{Reason: fmt.Sprintf("status code %v is not documented", rec.status)}
+	}
+
+	if resp == nil || rec.body.Len() == 0 {
+		return nil
+	}
+
+	return s.validateBody(resp.Content, rec.header.Get("Content-Type"), rec.body.Bytes())
+}
+
+func (s *Spec) validateBody(content map[string]MediaType, contentType string, body []byte) error {
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	if mediaType == "" {
+		mediaType = jsonContentType
+	}
+
+	if len(content) > 0 {
+		if _, ok := content[mediaType]; !ok {
+			return ValidationError{Reason: fmt.Sprintf("content type %v is not documented", mediaType)}
+		}
+	}
+
+	media, ok := content[jsonContentType]
+	if mediaType != jsonContentType || !ok || media.Schema == nil {
+		return nil
+	}
+
+	var value interface{}
+
+	if err := json.Unmarshal(body, &value); err != nil {
+		return ValidationError{Reason: "body is not a valid JSON"}
+	}
+
+	return media.Schema.Validate(value, s.refs)
+}
+
+func parameterValue(p *Parameter, pathParams map[string]string, r *http.Request) (string, bool) {
+	switch p.In {
+	case "path":
+		v, ok := pathParams[p.Name]
+		return v, ok
+	case "query":
+		values, ok := r.URL.Query()[p.Name]
+		if !ok || len(values) == 0 {
+			return "", false
+		}
+
+		return values[0], true
+	case "header":
+		v := r.Header.Get(p.Name)
+		return v, v != ""
+	case "cookie":
+		c, err := r.Cookie(p.Name)
+		if err != nil {
+			return "", false
+		}
+
+		return c.Value, true
+	}
+
+	return "", false
+}
+
+// coerce converts the parameter to the type of its schema, so that it can be validated like a JSON value.
+// The value is returned as it is when it cannot be converted, and fails the type validation.
+func coerce(schema *Schema, refs map[string]*Schema, value string) interface{} {
+	if resolved, err := schema.resolve(refs); err == nil {
+		schema = resolved
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "array":
+		items := strings.Split(value, ",")
+		arr := make([]interface{}, len(items))
+
+		for i, item := range items {
+			arr[i] = item
+
+			if schema.Items != nil {
+				arr[i] = coerce(schema.Items, refs, item)
+			}
+		}
+
+		return arr
+	}
+
+	return value
+}
+
+// recorder buffers the response so that it can be validated before it is written.
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *recorder) flush(w http.ResponseWriter) {
+	for k, v := range r.header {
+		w.Header()[k] = v
+	}
+
+	w.WriteHeader(r.status)
+	_, _ = w.Write(r.body.Bytes())
+}
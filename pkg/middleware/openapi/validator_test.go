@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+func newTestHandler(status int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the request body must be readable by the handler after validation
+		_, _ = io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func TestValidator_Reject(t *testing.T) {
+	spec, _ := NewSpec([]byte(testSpec))
+
+	tests := []struct {
+		desc       string
+		method     string
+		target     string
+		body       string
+		respStatus int
+		respBody   string
+		statusCode int
+	}{
+		{"valid request and response", "GET", "/users/1?verbose=true", "", 200, `{"name": "john"}`, 200},
+		{"invalid path param", "GET", "/users/abc", "", 200, `{"name": "john"}`, 400},
+		{"invalid query param", "GET", "/users/1?verbose=maybe", "", 200, `{"name": "john"}`, 400},
+		{"missing request body", "POST", "/users", "", 201, "", 400},
+		{"invalid request body", "POST", "/users", `{"age": 1}`, 201, "", 400},
+		{"valid request body", "POST", "/users", `{"name": "john"}`, 201, "", 201},
+		{"invalid response body", "GET", "/users/1", "", 200, `{"age": 1}`, 500},
+		{"response matched by range", "GET", "/users/1", "", 404, `{"errors": []}`, 404},
+		{"undocumented status code", "GET", "/users/1", "", 500, `{}`, 500},
+		{"undocumented path", "GET", "/orders", "", 200, `anything`, 200},
+		{"exempt path", "GET", "/.well-known/health-check", "", 200, `{}`, 200},
+	}
+
+	for i, tc := range tests {
+		handler := Validator(log.NewMockLogger(io.Discard), spec, ModeReject)(newTestHandler(tc.respStatus, tc.respBody))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.statusCode, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestValidator_Log(t *testing.T) {
+	spec, _ := NewSpec([]byte(testSpec))
+	b := new(bytes.Buffer)
+
+	handler := Validator(log.NewMockLogger(b), spec, ModeLog)(newTestHandler(http.StatusOK, `{"age": 1}`))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/abc", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"age": 1}`, w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Contains(t, b.String(), "request GET /users/{id} does not match the spec")
+	assert.Contains(t, b.String(), "response of GET /users/{id} does not match the spec")
+}
+
+func TestValidator_ContentType(t *testing.T) {
+	spec, _ := NewSpec([]byte(testSpec))
+
+	handler := Validator(log.NewMockLogger(io.Discard), spec, ModeReject)(newTestHandler(http.StatusCreated, ""))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("name=john"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	breadcrumbsKey contextKey = "breadcrumbs"
+
+	maxBreadcrumbs      = 20
+	panicReportTimeout  = 5 * time.Second
+	maxPanicStackFrames = 64
+)
+
+// PanicReporter ships the panic reports to an error tracker, ex: errortracker.Sentry.
+type PanicReporter interface {
+	ReportPanic(ctx context.Context, report *PanicReport) error
+}
+
+// StackFrame is a frame of the stack trace of a panic.
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Breadcrumb is an event which happened in a request before it panicked, ex: a query or a call to a service.
+type Breadcrumb struct {
+	Time     time.Time `json:"time"`
+	Category string    `json:"category"`
+	Message  string    `json:"message"`
+}
+
+// PanicReport is a panic along with the stack trace, the metadata of the request and the breadcrumbs.
+type PanicReport struct {
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+	// Stack is the stack trace, from the frame which panicked to the outermost frame.
+	Stack         []StackFrame           `json:"stack"`
+	CorrelationID string                 `json:"correlationId,omitempty"`
+	Method        string                 `json:"method,omitempty"`
+	URL           string                 `json:"url,omitempty"`
+	Route         string                 `json:"route,omitempty"`
+	Headers       map[string]string      `json:"headers,omitempty"`
+	AppData       map[string]interface{} `json:"appData,omitempty"`
+	Breadcrumbs   []Breadcrumb           `json:"breadcrumbs,omitempty"`
+}
+
+type breadcrumbs struct {
+	mu    sync.Mutex
+	trail []Breadcrumb
+}
+
+// WithBreadcrumbs returns a context in which the breadcrumbs are recorded, the Recover middleware sets it in the
+// requests when a PanicReporter is configured.
+func WithBreadcrumbs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, breadcrumbsKey, &breadcrumbs{})
+}
+
+// AddBreadcrumb records an event in the context, which is included in the report of a panic. Only the most recent
+// events are retained, and nothing is recorded when the context does not record the breadcrumbs.
+func AddBreadcrumb(ctx context.Context, category, message string) {
+	b, ok := ctx.Value(breadcrumbsKey).(*breadcrumbs)
+	if !ok {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.trail) == maxBreadcrumbs {
+		b.trail = b.trail[1:]
+	}
+
+	b.trail = append(b.trail, Breadcrumb{Time: time.Now(), Category: category, Message: message})
+}
+
+// NewPanicReport creates the report of a recovered panic. It must be called in the deferred function which recovers
+// the panic, as it captures the stack trace of the panic from the current stack. The request is nil for the panics
+// which do not happen in a request, ex: in a consumer.
+func NewPanicReport(ctx context.Context, recovered interface{}, r *http.Request) *PanicReport {
+	report := &PanicReport{Message: panicMessage(recovered), Time: time.Now().UTC(), Stack: panicStack(), AppData: getAppData(ctx)}
+
+	report.CorrelationID, _ = ctx.Value(CorrelationIDKey).(string)
+
+	if b, ok := ctx.Value(breadcrumbsKey).(*breadcrumbs); ok {
+		b.mu.Lock()
+		report.Breadcrumbs = append([]Breadcrumb(nil), b.trail...)
+		b.mu.Unlock()
+	}
+
+	if r == nil {
+		return report
+	}
+
+	report.Method, report.Headers = r.Method, archivedHeaders(r.Header)
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	report.URL = scheme + "://" + r.Host + r.URL.RequestURI()
+
+	if route := mux.CurrentRoute(r); route != nil {
+		report.Route, _ = route.GetPathTemplate()
+	}
+
+	return report
+}
+
+// ReportPanic sends the report to the reporters in the background, so that the response is not delayed.
+func ReportPanic(logger logger, report *PanicReport, reporters ...PanicReporter) {
+	for _, reporter := range reporters {
+		go func(reporter PanicReporter) {
+			ctx, cancel := context.WithTimeout(context.Background(), panicReportTimeout)
+			defer cancel()
+
+			if err := reporter.ReportPanic(ctx, report); err != nil && logger != nil {
+				logger.Errorf("panic report could not be sent: %v", err)
+			}
+		}(reporter)
+	}
+}
+
+func panicMessage(recovered interface{}) string {
+	switch t := recovered.(type) {
+	case string:
+		return t
+	case error:
+		return t.Error()
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// panicStack returns the frames of the stack which panicked, ie: the frames below the frame of runtime.gopanic.
+// The whole stack is returned when it is not called during a panic.
+func panicStack() []StackFrame {
+	pcs := make([]uintptr, maxPanicStackFrames)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	var stack []StackFrame
+
+	for more := true; more; {
+		var frame runtime.Frame
+
+		frame, more = frames.Next()
+
+		if frame.Function == "runtime.gopanic" {
+			stack = stack[:0]
+			continue
+		}
+
+		stack = append(stack, StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+	}
+
+	// the frames of the runtime, ex: of a nil pointer dereference, are not relevant for the application
+	for len(stack) > 1 && strings.HasPrefix(stack[0].Function, "runtime.") {
+		stack = stack[1:]
+	}
+
+	return stack
+}
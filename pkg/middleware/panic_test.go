@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+type mockPanicReporter struct {
+	reports chan *PanicReport
+}
+
+func (m *mockPanicReporter) ReportPanic(_ context.Context, report *PanicReport) error {
+	m.reports <- report
+	return nil
+}
+
+func TestAddBreadcrumb(t *testing.T) {
+	// nothing is recorded in a context without breadcrumbs
+	AddBreadcrumb(context.Background(), "sql", "SELECT 1")
+
+	ctx := WithBreadcrumbs(context.Background())
+
+	for i := 0; i < maxBreadcrumbs+5; i++ {
+		AddBreadcrumb(ctx, "sql", strconv.Itoa(i))
+	}
+
+	report := NewPanicReport(ctx, "panic", nil)
+
+	assert.Len(t, report.Breadcrumbs, maxBreadcrumbs)
+	assert.Equal(t, "5", report.Breadcrumbs[0].Message, "oldest breadcrumbs are not discarded")
+	assert.Equal(t, strconv.Itoa(maxBreadcrumbs+4), report.Breadcrumbs[maxBreadcrumbs-1].Message)
+}
+
+func TestNewPanicReport(t *testing.T) {
+	var report *PanicReport
+
+	func() {
+		defer func() {
+			report = NewPanicReport(context.Background(), recover(), nil)
+		}()
+
+		var a []int
+		a[1] = 1
+	}()
+
+	assert.Contains(t, report.Message, "index out of range")
+	assert.Empty(t, report.URL)
+
+	if assert.NotEmpty(t, report.Stack) {
+		assert.Equal(t, "gofr.dev/pkg/middleware.TestNewPanicReport.func1", report.Stack[0].Function)
+		assert.Contains(t, report.Stack[0].File, "panic_test.go")
+	}
+}
+
+func TestRecover_Reporters(t *testing.T) {
+	reporter := &mockPanicReporter{reports: make(chan *PanicReport, 1)}
+
+	muxRouter := mux.NewRouter()
+	muxRouter.Use(Recover(log.NewMockLogger(io.Discard), reporter))
+	muxRouter.HandleFunc("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		AddBreadcrumb(r.Context(), "service", "GET payments")
+		panic("order not found")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/1?expand=items", http.NoBody)
+	req.Header.Set("X-Correlation-ID", "b00ff8de800911ec8f6502bfe7568078")
+	req.Header.Set("Authorization", "Bearer token")
+	req = req.WithContext(context.WithValue(req.Context(), CorrelationIDKey, "b00ff8de800911ec8f6502bfe7568078"))
+
+	w := httptest.NewRecorder()
+
+	muxRouter.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	select {
+	case report := <-reporter.reports:
+		assert.Equal(t, "order not found", report.Message)
+		assert.Equal(t, http.MethodGet, report.Method)
+		assert.Equal(t, "http://example.com/orders/1?expand=items", report.URL)
+		assert.Equal(t, "/orders/{id}", report.Route)
+		assert.Equal(t, "b00ff8de800911ec8f6502bfe7568078", report.CorrelationID)
+		assert.Equal(t, "b00ff8de800911ec8f6502bfe7568078", report.Headers["X-Correlation-Id"])
+		assert.NotContains(t, report.Headers, "Authorization", "credentials are not removed")
+
+		if assert.Len(t, report.Breadcrumbs, 1) {
+			assert.Equal(t, "GET payments", report.Breadcrumbs[0].Message)
+		}
+	case <-time.After(time.Second):
+		t.Error("panic is not reported")
+	}
+}
@@ -0,0 +1,494 @@
+/*
+Package policy runs the Lua policies of an organisation on the requests and the responses, so that the rules common to
+all the services, ex: required headers or security headers, are enforced without redeploying the services. The
+policies are reloaded as they change, ex: when they are mounted from a config map.
+
+A policy defines either or both of the hooks:
+
+	function on_request(req)
+	  if req.headers["X-Api-Version"] == nil then
+	    return {status = 400, body = "X-Api-Version header is required"}
+	  end
+
+	  req.headers["X-Org"] = "acme"
+	end
+
+	function on_response(req, res)
+	  res.headers["Strict-Transport-Security"] = "max-age=63072000"
+	  res.headers["Server"] = nil
+	end
+
+The request has the fields method, path, query and headers, and the response has the fields status and headers. The
+changes made by the hooks to the path, the query, the headers and the status are applied to the request and the response.
+on_request rejects the request by returning a table with the status, and optionally the body, of the error response.
+*/
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
+)
+
+const (
+	requestHook  = "on_request"
+	responseHook = "on_response"
+
+	defaultReloadInterval = 30 * time.Second
+	defaultTimeout        = 100 * time.Millisecond
+)
+
+// Config configures the directory of the policies, and how they are run.
+type Config struct {
+	// Dir is the directory of the policies, the *.lua files in it are run in the order of their names.
+	Dir string
+	// ReloadInterval is the interval at which the directory is checked for changes to the policies. Default is 30 seconds.
+	ReloadInterval time.Duration
+	// Timeout is the time for which a hook runs before it fails. Default is 100 milliseconds.
+	Timeout time.Duration
+	// FailOpen skips an on_request hook which fails, instead of responding with 500 Internal Server Error. A failing
+	// on_response hook never changes the response.
+	FailOpen bool
+	Logger   log.Logger
+}
+
+// script is a compiled policy. The Lua states running it are pooled, as a state cannot be used concurrently.
+type script struct {
+	name        string
+	proto       *lua.FunctionProto
+	timeout     time.Duration
+	hasResponse bool
+	states      sync.Pool
+}
+
+type policies struct {
+	cfg Config
+
+	mu      sync.RWMutex
+	scripts []*script
+	// version identifies the names, sizes and modification times of the loaded files, to detect the changes.
+	version string
+}
+
+// New loads the policies in the directory, and returns the middleware running them. It returns an error when a policy
+// cannot be compiled. A policy which cannot be compiled on reloading is logged, and the loaded policies are kept.
+func New(cfg Config) (func(inner http.Handler) http.Handler, error) {
+	if cfg.ReloadInterval <= 0 {
+		cfg.ReloadInterval = defaultReloadInterval
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	p := &policies{cfg: cfg}
+
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+
+	go p.watch()
+
+	return p.middleware, nil
+}
+
+func (p *policies) watch() {
+	ticker := time.NewTicker(p.cfg.ReloadInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := p.load(); err != nil {
+			p.cfg.Logger.Errorf("policies could not be reloaded: %v", err)
+		}
+	}
+}
+
+func (p *policies) load() error {
+	files, err := filepath.Glob(filepath.Join(p.cfg.Dir, "*.lua"))
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(files)
+
+	var version strings.Builder
+
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&version, "%v:%v:%v;", f, info.Size(), info.ModTime().UnixNano())
+	}
+
+	p.mu.RLock()
+	unchanged := version.String() == p.version && p.scripts != nil
+	p.mu.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	scripts := make([]*script, 0, len(files))
+
+	for _, f := range files {
+		s, err := compile(f, p.cfg.Timeout)
+		if err != nil {
+			return err
+		}
+
+		scripts = append(scripts, s)
+	}
+
+	p.mu.Lock()
+	p.scripts, p.version = scripts, version.String()
+	p.mu.Unlock()
+
+	p.cfg.Logger.Infof("%v policies loaded from %v", len(scripts), p.cfg.Dir)
+
+	return nil
+}
+
+func compile(file string, timeout time.Duration) (*script, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	name := filepath.Base(file)
+
+	chunk, err := parse.Parse(f, name)
+	if err != nil {
+		return nil, fmt.Errorf("policy %v is invalid: %w", name, err)
+	}
+
+	proto, err := lua.Compile(chunk, name)
+	if err != nil {
+		return nil, fmt.Errorf("policy %v is invalid: %w", name, err)
+	}
+
+	s := &script{name: name, proto: proto, timeout: timeout}
+
+	L, err := s.newState()
+	if err != nil {
+		return nil, err
+	}
+
+	s.hasResponse = L.GetGlobal(responseHook).Type() == lua.LTFunction
+	s.states.Put(L)
+
+	return s, nil
+}
+
+// newState creates a Lua state with the hooks of the policy defined. Only the base, table, string and math libraries
+// are opened, without the functions loading files, so that the policies cannot access the file system.
+func (s *script) newState() (*lua.LState, error) {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+
+	for _, fn := range []string{"dofile", "loadfile", "load", "loadstring"} {
+		L.SetGlobal(fn, lua.LNil)
+	}
+
+	L.Push(L.NewFunctionFromProto(s.proto))
+
+	if err := L.PCall(0, lua.MultRet, nil); err != nil {
+		L.Close()
+		return nil, fmt.Errorf("policy %v is invalid: %w", s.name, err)
+	}
+
+	return L, nil
+}
+
+func (s *script) state() (*lua.LState, error) {
+	if L, ok := s.states.Get().(*lua.LState); ok {
+		return L, nil
+	}
+
+	return s.newState()
+}
+
+// call runs the hook, if the policy defines it, with the arguments created on the state of the call. The state of a
+// failed call is closed instead of being reused.
+func (s *script) call(ctx context.Context, hook string, args func(L *lua.LState) []lua.LValue,
+	result func(ret lua.LValue, args []lua.LValue)) error {
+	L, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	fn := L.GetGlobal(hook)
+	if fn.Type() != lua.LTFunction {
+		s.states.Put(L)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	L.SetContext(ctx)
+
+	values := args(L)
+
+	if err := L.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, values...); err != nil {
+		L.Close()
+		return err
+	}
+
+	result(L.Get(-1), values)
+
+	L.Pop(1)
+	L.RemoveContext()
+	s.states.Put(L)
+
+	return nil
+}
+
+func (p *policies) middleware(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if middleware.ExemptPath(r) {
+			inner.ServeHTTP(w, r)
+			return
+		}
+
+		p.mu.RLock()
+		scripts := p.scripts
+		p.mu.RUnlock()
+
+		for _, s := range scripts {
+			if rejected := p.onRequest(s, w, r); rejected {
+				return
+			}
+		}
+
+		for _, s := range scripts {
+			if s.hasResponse {
+				rw := &responseWriter{ResponseWriter: w}
+				rw.hook = func(status int) int { return p.onResponse(scripts, r, w.Header(), status) }
+
+				inner.ServeHTTP(rw, r)
+
+				// the hooks run for the responses without a body as well
+				rw.WriteHeader(http.StatusOK)
+
+				return
+			}
+		}
+
+		inner.ServeHTTP(w, r)
+	})
+}
+
+// onRequest runs the on_request hook of the policy, and responds when the request is rejected or the hook fails.
+func (p *policies) onRequest(s *script, w http.ResponseWriter, r *http.Request) bool {
+	var status int
+
+	var body string
+
+	err := s.call(r.Context(), requestHook, func(L *lua.LState) []lua.LValue {
+		return []lua.LValue{requestTable(L, r)}
+	}, func(ret lua.LValue, args []lua.LValue) {
+		applyRequest(args[0].(*lua.LTable), r)
+
+		if t, ok := ret.(*lua.LTable); ok {
+			status, _ = toInt(t.RawGetString("status"))
+			body = lua.LVAsString(t.RawGetString("body"))
+		}
+	})
+
+	if err != nil {
+		p.cfg.Logger.Errorf("policy %v failed on request %v %v: %v", s.name, r.Method, r.URL.Path, err)
+
+		if p.cfg.FailOpen {
+			return false
+		}
+
+		status, body = http.StatusInternalServerError, ""
+	}
+
+	if status == 0 {
+		return false
+	}
+
+	if body == "" {
+		body = fmt.Sprintf("request rejected by policy %v", s.name)
+	}
+
+	e := middleware.FetchErrResponseWithCode(status, body, http.StatusText(status))
+	middleware.ErrorResponse(w, r, nil, *e)
+
+	return true
+}
+
+// onResponse runs the on_response hooks before the response is written, and returns the status of the response.
+func (p *policies) onResponse(scripts []*script, r *http.Request, h http.Header, status int) int {
+	for _, s := range scripts {
+		err := s.call(r.Context(), responseHook, func(L *lua.LState) []lua.LValue {
+			res := L.NewTable()
+			res.RawSetString("status", lua.LNumber(status))
+			res.RawSetString("headers", headersTable(L, h))
+
+			return []lua.LValue{requestTable(L, r), res}
+		}, func(_ lua.LValue, args []lua.LValue) {
+			res := args[1].(*lua.LTable)
+
+			if code, ok := toInt(res.RawGetString("status")); ok {
+				status = code
+			}
+
+			applyHeaders(res.RawGetString("headers"), h)
+		})
+
+		if err != nil {
+			p.cfg.Logger.Errorf("policy %v failed on response of %v %v: %v", s.name, r.Method, r.URL.Path, err)
+		}
+	}
+
+	return status
+}
+
+func requestTable(L *lua.LState, r *http.Request) *lua.LTable {
+	req := L.NewTable()
+	req.RawSetString("method", lua.LString(r.Method))
+	req.RawSetString("path", lua.LString(r.URL.Path))
+
+	query := L.NewTable()
+
+	for k, v := range r.URL.Query() {
+		query.RawSetString(k, lua.LString(v[0]))
+	}
+
+	req.RawSetString("query", query)
+	req.RawSetString("headers", headersTable(L, r.Header))
+
+	return req
+}
+
+func headersTable(L *lua.LState, h http.Header) *lua.LTable {
+	t := L.NewTable()
+
+	for k := range h {
+		t.RawSetString(k, lua.LString(h.Get(k)))
+	}
+
+	return t
+}
+
+func applyRequest(req *lua.LTable, r *http.Request) {
+	if path, ok := req.RawGetString("path").(lua.LString); ok && string(path) != r.URL.Path {
+		r.URL.Path, r.URL.RawPath = string(path), ""
+	}
+
+	if query, ok := req.RawGetString("query").(*lua.LTable); ok {
+		values := r.URL.Query()
+		changed := false
+
+		for k := range values {
+			if query.RawGetString(k) == lua.LNil {
+				values.Del(k)
+
+				changed = true
+			}
+		}
+
+		query.ForEach(func(k, v lua.LValue) {
+			if values.Get(k.String()) != v.String() {
+				values.Set(k.String(), v.String())
+
+				changed = true
+			}
+		})
+
+		if changed {
+			r.URL.RawQuery = values.Encode()
+		}
+	}
+
+	applyHeaders(req.RawGetString("headers"), r.Header)
+}
+
+// applyHeaders applies the changes made to the headers table, the values of the headers which are not changed are kept
+// as they are, including the multiple values of a header.
+func applyHeaders(v lua.LValue, h http.Header) {
+	t, ok := v.(*lua.LTable)
+	if !ok {
+		return
+	}
+
+	for k := range h {
+		if t.RawGetString(k) == lua.LNil {
+			h.Del(k)
+		}
+	}
+
+	t.ForEach(func(k, v lua.LValue) {
+		if h.Get(k.String()) != v.String() {
+			h.Set(k.String(), v.String())
+		}
+	})
+}
+
+func toInt(v lua.LValue) (int, bool) {
+	n, ok := v.(lua.LNumber)
+
+	return int(n), ok
+}
+
+// responseWriter runs the on_response hooks before the status and the headers are written.
+type responseWriter struct {
+	http.ResponseWriter
+	hook        func(status int) int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+
+	w.ResponseWriter.WriteHeader(w.hook(status))
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
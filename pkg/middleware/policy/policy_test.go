@@ -0,0 +1,157 @@
+package policy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+const headersPolicy = `
+function on_request(req)
+  if req.headers["X-Api-Version"] == nil then
+    return {status = 400, body = "X-Api-Version header is required"}
+  end
+
+  req.headers["X-Org"] = "acme"
+  req.query["debug"] = nil
+end
+
+function on_response(req, res)
+  res.headers["Strict-Transport-Security"] = "max-age=63072000"
+  res.headers["Server"] = nil
+
+  if req.path == "/teapot" then
+    res.status = 418
+  end
+end
+`
+
+const rewritePolicy = `
+function on_request(req)
+  if string.sub(req.path, 1, 4) == "/v1/" then
+    req.path = "/" .. string.sub(req.path, 5)
+  end
+end
+`
+
+func writePolicies(t *testing.T, policies map[string]string) string {
+	dir := t.TempDir()
+
+	for name, policy := range policies {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(policy), 0600))
+	}
+
+	return dir
+}
+
+func TestNew(t *testing.T) {
+	dir := writePolicies(t, map[string]string{"10-headers.lua": headersPolicy, "20-rewrite.lua": rewritePolicy})
+
+	mw, err := New(Config{Dir: dir, Logger: log.NewMockLogger(io.Discard)})
+	assert.NoError(t, err)
+
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "gofr")
+		w.Header().Set("X-Path", r.URL.RequestURI())
+		w.Header().Set("X-Org", r.Header.Get("X-Org"))
+	}))
+
+	tests := []struct {
+		desc    string
+		target  string
+		version string
+		status  int
+		path    string
+	}{
+		{"rejected request", "/orders", "", http.StatusBadRequest, ""},
+		{"changed request", "/v1/orders?debug=true&page=2", "1", http.StatusOK, "/orders?page=2"},
+		{"changed status", "/teapot", "1", http.StatusTeapot, "/teapot"},
+		{"health check", "/.well-known/health-check", "", http.StatusOK, "/.well-known/health-check"},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, tc.target, http.NoBody)
+		if tc.version != "" {
+			r.Header.Set("X-Api-Version", tc.version)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.path, w.Header().Get("X-Path"), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.path != "" && tc.version != "" {
+			assert.Equal(t, "acme", w.Header().Get("X-Org"), "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, "max-age=63072000", w.Header().Get("Strict-Transport-Security"), "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Empty(t, w.Header().Get("Server"), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestNew_Failures(t *testing.T) {
+	loop := `function on_request(req) while true do end end`
+
+	tests := []struct {
+		desc     string
+		policy   string
+		failOpen bool
+		status   int
+	}{
+		{"runtime error", `function on_request(req) error("boom") end`, false, http.StatusInternalServerError},
+		{"timeout", loop, false, http.StatusInternalServerError},
+		{"fail open", loop, true, http.StatusOK},
+	}
+
+	for i, tc := range tests {
+		dir := writePolicies(t, map[string]string{"policy.lua": tc.policy})
+
+		mw, err := New(Config{Dir: dir, Timeout: 10 * time.Millisecond, FailOpen: tc.failOpen, Logger: log.NewMockLogger(io.Discard)})
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		w := httptest.NewRecorder()
+		mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	logger := log.NewMockLogger(io.Discard)
+
+	_, err := New(Config{Dir: writePolicies(t, map[string]string{"invalid.lua": "function on_request("}), Logger: logger})
+	assert.ErrorContains(t, err, "policy invalid.lua is invalid")
+
+	_, err = New(Config{Dir: writePolicies(t, map[string]string{"io.lua": `dofile("/etc/passwd")`}), Logger: logger})
+	assert.ErrorContains(t, err, "policy io.lua is invalid", "policies must not access the file system")
+}
+
+func TestPolicies_Reload(t *testing.T) {
+	dir := writePolicies(t, map[string]string{"policy.lua": `function on_request(req) return {status = 403} end`})
+
+	p := &policies{cfg: Config{Dir: dir, Timeout: defaultTimeout, Logger: log.NewMockLogger(io.Discard)}}
+	assert.NoError(t, p.load())
+
+	handler := p.middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// the modification time is changed, as the file may be rewritten within the resolution of the file system
+	file := filepath.Join(dir, "policy.lua")
+	assert.NoError(t, os.WriteFile(file, []byte(`function on_request(req) end`), 0600))
+	assert.NoError(t, os.Chtimes(file, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	assert.NoError(t, p.load())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,260 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultMaxConcurrent  = 100
+	defaultPriorityQueue  = 100
+	defaultPriorityWait   = 10 * time.Second
+	priorityRetryAfterSec = "1"
+)
+
+//nolint:gochecknoglobals // metrics need to be initialized only once
+var (
+	priorityQueued = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "zs_http_priority_queue_length",
+		Help: "Gauge of the requests waiting for a slot, by priority class",
+	}, []string{"class"})
+
+	priorityRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_http_priority_rejected_total",
+		Help: "Counter of the requests rejected as the queue of their priority class was full or they waited too long",
+	}, []string{"class"})
+
+	_ = prometheus.Register(priorityQueued)
+	_ = prometheus.Register(priorityRejected)
+)
+
+// PriorityClass is a class of requests, which waits in its own queue when all the slots are in use.
+type PriorityClass struct {
+	Name string
+	// Routes are the path prefixes of the routes in the class, optionally preceded by the method, ex: "POST /exports".
+	Routes []string
+	// Header and Values select the requests in the class by a header, ex: X-Tenant-Tier with the values premium and gold.
+	Header string
+	Values []string
+	// Weight is the share of the freed slots which are given to the class, relative to the weights of the other classes
+	// with waiting requests. Default is 1.
+	Weight int
+	// QueueSize is the number of requests of the class which can wait for a slot, the further requests are rejected
+	// with 503 Service Unavailable. Default is 100.
+	QueueSize int
+}
+
+// PriorityConfig configures the number of requests handled concurrently, and the classes of the waiting requests.
+type PriorityConfig struct {
+	// MaxConcurrent is the number of requests which are handled concurrently. Default is 100.
+	MaxConcurrent int
+	// Classes are matched in order, a class with neither routes nor a header matches all the requests. The requests
+	// which match no class are in the last class.
+	Classes []PriorityClass
+	// MaxWait is the time a request waits for a slot before it is rejected with 503 Service Unavailable. Default is
+	// 10 seconds.
+	MaxWait time.Duration
+	Logger  logger
+}
+
+// Prioritize limits the number of requests handled concurrently, and queues the further requests by their priority
+// class. As a slot frees up, it is given to a waiting request of a class chosen by the weights of the classes, so that
+// a class with a higher weight is served more often, while the classes with a lower weight are not starved. The health
+// checks and the other well-known endpoints are not limited, so that a saturated instance is not restarted by its probes.
+func Prioritize(cfg PriorityConfig) func(inner http.Handler) http.Handler {
+	p := newPrioritizer(&cfg)
+
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ExemptPath(r) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			q := p.classify(r)
+
+			if !p.acquire(r, q) {
+				priorityRejected.WithLabelValues(q.class.Name).Inc()
+
+				w.Header().Set("Retry-After", priorityRetryAfterSec)
+				description, code := GetDescription(ErrOverloaded)
+				ErrorResponse(w, r, cfg.Logger, *FetchErrResponseWithCode(code, description, ErrOverloaded.Error()))
+
+				return
+			}
+
+			defer p.release()
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
+// prioritizer hands out the slots, the waiting requests are given a slot by closing their channel.
+type prioritizer struct {
+	mu      sync.Mutex
+	active  int
+	max     int
+	maxWait time.Duration
+	queues  []*priorityQueue
+}
+
+type priorityQueue struct {
+	class   PriorityClass
+	waiting []chan struct{}
+	// current is the score of the class in the smooth weighted round-robin of the freed slots.
+	current int
+}
+
+func newPrioritizer(cfg *PriorityConfig) *prioritizer {
+	p := &prioritizer{max: cfg.MaxConcurrent, maxWait: cfg.MaxWait}
+
+	if p.max <= 0 {
+		p.max = defaultMaxConcurrent
+	}
+
+	if p.maxWait <= 0 {
+		p.maxWait = defaultPriorityWait
+	}
+
+	classes := cfg.Classes
+	if len(classes) == 0 {
+		classes = []PriorityClass{{Name: "default"}}
+	}
+
+	for _, c := range classes {
+		if c.Weight <= 0 {
+			c.Weight = 1
+		}
+
+		if c.QueueSize <= 0 {
+			c.QueueSize = defaultPriorityQueue
+		}
+
+		p.queues = append(p.queues, &priorityQueue{class: c})
+	}
+
+	return p
+}
+
+func (p *prioritizer) classify(r *http.Request) *priorityQueue {
+	for _, q := range p.queues {
+		if q.class.matches(r) {
+			return q
+		}
+	}
+
+	return p.queues[len(p.queues)-1]
+}
+
+func (c *PriorityClass) matches(r *http.Request) bool {
+	if len(c.Routes) == 0 && c.Header == "" {
+		return true
+	}
+
+	if len(c.Routes) > 0 && matchRoutes(c.Routes, r) {
+		return true
+	}
+
+	value := r.Header.Get(c.Header)
+	if c.Header == "" || value == "" {
+		return false
+	}
+
+	for _, v := range c.Values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// acquire returns true once the request has a slot, and false when the queue of its class is full, the request waited
+// for MaxWait or the request was canceled.
+func (p *prioritizer) acquire(r *http.Request, q *priorityQueue) bool {
+	p.mu.Lock()
+
+	if p.active < p.max {
+		p.active++
+		p.mu.Unlock()
+
+		return true
+	}
+
+	if len(q.waiting) >= q.class.QueueSize {
+		p.mu.Unlock()
+		return false
+	}
+
+	ready := make(chan struct{})
+	q.waiting = append(q.waiting, ready)
+	priorityQueued.WithLabelValues(q.class.Name).Inc()
+
+	p.mu.Unlock()
+
+	timer := time.NewTimer(p.maxWait)
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+		return true
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, w := range q.waiting {
+		if w == ready {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			priorityQueued.WithLabelValues(q.class.Name).Dec()
+
+			return false
+		}
+	}
+
+	// the slot was given to the request while it stopped waiting, so it is handled
+	return true
+}
+
+// release gives the slot to a waiting request, of the class with the highest score in the smooth weighted round-robin.
+func (p *prioritizer) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var (
+		next  *priorityQueue
+		total int
+	)
+
+	for _, q := range p.queues {
+		if len(q.waiting) == 0 {
+			continue
+		}
+
+		q.current += q.class.Weight
+		total += q.class.Weight
+
+		if next == nil || q.current > next.current {
+			next = q
+		}
+	}
+
+	if next == nil {
+		p.active--
+		return
+	}
+
+	next.current -= total
+
+	ready := next.waiting[0]
+	next.waiting = next.waiting[1:]
+	priorityQueued.WithLabelValues(next.class.Name).Dec()
+
+	close(ready)
+}
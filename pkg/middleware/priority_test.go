@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityClass_matches(t *testing.T) {
+	tests := []struct {
+		desc    string
+		class   PriorityClass
+		target  string
+		tier    string
+		matches bool
+	}{
+		{"class without routes and header", PriorityClass{}, "/orders", "", true},
+		{"matching route", PriorityClass{Routes: []string{"GET /orders"}}, "/orders/1", "", true},
+		{"route not matching", PriorityClass{Routes: []string{"POST /orders"}}, "/orders/1", "", false},
+		{"matching header", PriorityClass{Header: "X-Tenant-Tier", Values: []string{"gold", "premium"}}, "/orders", "premium", true},
+		{"header not matching", PriorityClass{Header: "X-Tenant-Tier", Values: []string{"premium"}}, "/orders", "free", false},
+		{"header missing", PriorityClass{Header: "X-Tenant-Tier", Values: []string{""}}, "/orders", "", false},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, tc.target, http.NoBody)
+		if tc.tier != "" {
+			r.Header.Set("X-Tenant-Tier", tc.tier)
+		}
+
+		assert.Equal(t, tc.matches, tc.class.matches(r), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestPrioritizer_WeightedDispatch(t *testing.T) {
+	p := newPrioritizer(&PriorityConfig{MaxConcurrent: 1, Classes: []PriorityClass{
+		{Name: "premium", Header: "X-Tenant-Tier", Values: []string{"premium"}, Weight: 3},
+		{Name: "bulk"},
+	}})
+
+	r := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+	premium := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+	premium.Header.Set("X-Tenant-Tier", "premium")
+
+	assert.True(t, p.acquire(r, p.classify(r)))
+
+	dispatched := make(chan string)
+
+	for _, req := range []*http.Request{premium, premium, premium, r, r, r} {
+		req, q := req, p.classify(req)
+
+		go func() {
+			if p.acquire(req, q) {
+				dispatched <- q.class.Name
+			}
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		return len(p.queues[0].waiting) == 3 && len(p.queues[1].waiting) == 3
+	}, time.Second, time.Millisecond)
+
+	order := make([]string, 0, 6)
+
+	for i := 0; i < 6; i++ {
+		p.release()
+
+		order = append(order, <-dispatched)
+	}
+
+	assert.Equal(t, []string{"premium", "premium", "bulk", "premium", "bulk", "bulk"}, order)
+}
+
+func TestPrioritize_Saturated(t *testing.T) {
+	block := make(chan struct{})
+	handled := make(chan struct{}, 1)
+
+	handler := Prioritize(PriorityConfig{MaxConcurrent: 1, MaxWait: 50 * time.Millisecond,
+		Classes: []PriorityClass{{Name: "default", QueueSize: 1}}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			handled <- struct{}{}
+			<-block
+		}
+	}))
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", http.NoBody))
+
+	<-handled
+
+	queued := make(chan *httptest.ResponseRecorder)
+
+	go func() {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/queued", http.NoBody))
+		queued <- w
+	}()
+
+	// the queued request waits for MaxWait, as the slow request keeps the only slot
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(priorityQueued.WithLabelValues("default")) == 1
+	}, time.Second, time.Millisecond)
+
+	tests := []struct {
+		desc   string
+		target string
+		status int
+	}{
+		{"queue is full", "/orders", http.StatusServiceUnavailable},
+		{"health check is not limited", "/.well-known/health-check", http.StatusOK},
+	}
+
+	for i, tc := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, http.NoBody))
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	w := <-queued
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "request waiting longer than MaxWait is not rejected")
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	close(block)
+}
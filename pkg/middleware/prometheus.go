@@ -1,15 +1,16 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"gofr.dev/pkg"
 	"gofr.dev/pkg/gofr/config"
@@ -18,11 +19,16 @@ import (
 
 //nolint:gochecknoglobals // metrics need to be initialized only once
 var (
-	httpResponse = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	httpResponseOpts = prometheus.HistogramOpts{
 		Name:    "zs_http_response",
 		Help:    "Histogram of HTTP response times in seconds",
 		Buckets: []float64{.001, .003, .005, .01, .025, .05, .1, .2, .3, .4, .5, .75, 1, 2, 3, 5, 10, 30},
-	}, []string{"path", "method", "status"})
+	}
+	httpResponseLabels = []string{"path", "method", "status"}
+
+	// httpResponse is registered when the first request is served, so that the request labels can be added till then
+	httpResponse     = prometheus.NewHistogramVec(httpResponseOpts, httpResponseLabels)
+	httpResponseOnce sync.Once
 
 	goRoutines = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "zs_go_routines",
@@ -59,7 +65,6 @@ var (
 		Help: "Counter for deprecated features",
 	}, []string{"appName", "appVersion", "featureName"})
 
-	_ = prometheus.Register(httpResponse)
 	_ = prometheus.Register(goRoutines)
 	_ = prometheus.Register(alloc)
 	_ = prometheus.Register(totalAlloc)
@@ -78,6 +83,8 @@ func PrometheusMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		httpResponseOnce.Do(func() { _ = metricsRegisterer.Register(httpResponse) })
+
 		start := time.Now()
 
 		route := mux.CurrentRoute(r)
@@ -85,11 +92,17 @@ func PrometheusMiddleware(next http.Handler) http.Handler {
 		// remove the trailing slash
 		path = strings.TrimSuffix(path, "/")
 		srw := &StatusResponseWriter{ResponseWriter: w}
+		labels := requestLabels.new()
+
+		if labels != nil {
+			r = r.WithContext(context.WithValue(r.Context(), metricLabelsKey, labels))
+		}
 
 		// this has to be called in the end so that status code is populated
 		defer func(res *StatusResponseWriter, req *http.Request) {
 			duration := time.Since(start)
-			httpResponse.WithLabelValues(path, req.Method, fmt.Sprintf("%d", res.status)).Observe(duration.Seconds())
+			values := append([]string{path, req.Method, fmt.Sprintf("%d", res.status)}, labels.values()...)
+			httpResponse.WithLabelValues(values...).Observe(duration.Seconds())
 		}(srw, r)
 
 		// set system stats
@@ -0,0 +1,286 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultReloadInterval = 30 * time.Second
+	defaultQuotaPeriod    = 24 * time.Hour
+)
+
+//nolint:gochecknoglobals // metrics need to be initialized only once
+var (
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_http_rate_limited_total",
+		Help: "Counter of the requests rejected as the tenant exceeded its rate limit or its quota",
+	}, []string{"reason"})
+
+	_ = prometheus.Register(rateLimited)
+)
+
+// TenantLimit is the rate limit and the quota of a tenant, the zero values are not limited.
+type TenantLimit struct {
+	// Rate is the number of requests per second, which are allowed on average.
+	Rate float64 `json:"rate"`
+	// Burst is the number of requests which are allowed at once, above the rate. Default is the rate, rounded up.
+	Burst int `json:"burst,omitempty"`
+	// Quota is the number of requests which are allowed in a quota period.
+	Quota int64 `json:"quota,omitempty"`
+}
+
+// LimitStore stores the limits of the tenants, ex: in a SQL table or in Redis, so that they are changed at runtime and
+// shared by the instances of the application.
+type LimitStore interface {
+	// Limits returns the limits of the tenants, keyed by the tenant.
+	Limits(ctx context.Context) (map[string]TenantLimit, error)
+	SetLimit(ctx context.Context, tenant string, limit TenantLimit) error
+}
+
+/*
+RateLimitConfig configures the limits of the tenants, and where they are loaded from.
+
+The tenant of a request must be authenticated, as a client sending the tenant of another tenant gets its limit. It is
+returned by Identity, ex: from a claim of the verified JWT, or else read from Header, which must be a credential of
+the tenant, ex: X-API-Key.
+*/
+type RateLimitConfig struct {
+	Store LimitStore
+	// Default is the limit shared by the requests of the tenants which are not in the store, and of the requests
+	// without a tenant, so that the clients sending unknown tenants do not get a limit each.
+	Default TenantLimit
+	// Identity returns the authenticated tenant of the request, it is empty when the request is not authenticated.
+	Identity func(r *http.Request) string
+	// Header is the header of the tenant, which is read when Identity is not set.
+	Header string
+	// ReloadInterval is the interval at which the limits are reloaded from the store. Default is 30 seconds.
+	ReloadInterval time.Duration
+	// QuotaPeriod is the period of the quotas, the quota of a tenant is reset when the period of its first request is
+	// over. Default is 24 hours.
+	QuotaPeriod time.Duration
+	Logger      logger
+}
+
+/*
+RateLimiter limits the requests of every tenant, using a token bucket for the rate and a counter for the quota. The
+limits are loaded from the store, and reloaded at the reload interval, so that the limits changed by the other
+instances are applied. The requests are counted by the instance, hence the limits of a tenant are per instance. The
+buckets which are idle till they are full again, and their quota period is over, are evicted at the reload interval.
+*/
+type RateLimiter struct {
+	cfg RateLimitConfig
+
+	mu      sync.Mutex
+	limits  map[string]TenantLimit
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+// bucket is the usage of a tenant, the tokens are refilled at the rate of the tenant, up to its burst.
+type bucket struct {
+	tokens      float64
+	last        time.Time
+	used        int64
+	periodStart time.Time
+	// lastUsed is the time of the last request of the tenant
+	lastUsed time.Time
+	burst    float64
+	rate     float64
+}
+
+// NewRateLimiter returns the rate limiter, the limits are loaded using Reload.
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	if cfg.ReloadInterval <= 0 {
+		cfg.ReloadInterval = defaultReloadInterval
+	}
+
+	if cfg.QuotaPeriod <= 0 {
+		cfg.QuotaPeriod = defaultQuotaPeriod
+	}
+
+	return &RateLimiter{cfg: cfg, limits: make(map[string]TenantLimit), buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Reload loads the limits from the store, the limits are not changed when they can not be loaded.
+func (l *RateLimiter) Reload(ctx context.Context) error {
+	if l.cfg.Store == nil {
+		return nil
+	}
+
+	limits, err := l.cfg.Store.Limits(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.limits = limits
+	l.mu.Unlock()
+
+	return nil
+}
+
+// Run reloads the limits at the reload interval, till the context is done.
+func (l *RateLimiter) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.cfg.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.Reload(ctx); err != nil && l.cfg.Logger != nil {
+				l.cfg.Logger.Errorf("could not reload the rate limits: %v", err)
+			}
+
+			l.evict()
+		}
+	}
+}
+
+// evict removes the buckets which are as good as new, as their tokens are refilled and their quota period is over.
+func (l *RateLimiter) evict() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	for tenant, b := range l.buckets {
+		idle := now.Sub(b.lastUsed)
+
+		if idle >= l.cfg.QuotaPeriod && (b.rate <= 0 || idle.Seconds()*b.rate >= b.burst) {
+			delete(l.buckets, tenant)
+		}
+	}
+}
+
+// Limits returns the limits of the tenants loaded from the store.
+func (l *RateLimiter) Limits() map[string]TenantLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limits := make(map[string]TenantLimit, len(l.limits))
+
+	for tenant, limit := range l.limits {
+		limits[tenant] = limit
+	}
+
+	return limits
+}
+
+// SetLimit changes the limit of the tenant in the store, and applies it to the instance at once.
+func (l *RateLimiter) SetLimit(ctx context.Context, tenant string, limit TenantLimit) error {
+	if l.cfg.Store != nil {
+		if err := l.cfg.Store.SetLimit(ctx, tenant, limit); err != nil {
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	l.limits[tenant] = limit
+	delete(l.buckets, tenant)
+	l.mu.Unlock()
+
+	return nil
+}
+
+// Handler rejects the requests of the tenants which exceeded their rate limit or their quota with 429 Too Many
+// Requests, along with the Retry-After header. The well-known endpoints are not limited.
+func (l *RateLimiter) Handler(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ExemptPath(r) || strings.HasPrefix(r.URL.Path, "/.well-known/") {
+			inner.ServeHTTP(w, r)
+			return
+		}
+
+		if retryAfter, err := l.take(l.tenant(r)); err != nil {
+			rateLimited.WithLabelValues(err.Error()).Inc()
+
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			description, code := GetDescription(err)
+			ErrorResponse(w, r, l.cfg.Logger, *FetchErrResponseWithCode(code, description, err.Error()))
+
+			return
+		}
+
+		inner.ServeHTTP(w, r)
+	})
+}
+
+// tenant returns the authenticated tenant of the request.
+func (l *RateLimiter) tenant(r *http.Request) string {
+	if l.cfg.Identity != nil {
+		return l.cfg.Identity(r)
+	}
+
+	if l.cfg.Header != "" {
+		return r.Header.Get(l.cfg.Header)
+	}
+
+	return ""
+}
+
+// take takes a token of the tenant, it returns the time after which the request can be retried, along with the error,
+// when the tenant exceeded its limits. The tenants which are not in the store share the bucket of the default limit.
+func (l *RateLimiter) take(tenant string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit, ok := l.limits[tenant]
+	if !ok {
+		tenant, limit = "", l.cfg.Default
+	}
+
+	if limit.Rate <= 0 && limit.Quota <= 0 {
+		return 0, nil
+	}
+
+	now := l.now()
+
+	b, ok := l.buckets[tenant]
+	if !ok {
+		b = &bucket{tokens: burst(limit), last: now, periodStart: now}
+		l.buckets[tenant] = b
+	}
+
+	b.lastUsed, b.burst, b.rate = now, burst(limit), limit.Rate
+
+	if now.Sub(b.periodStart) >= l.cfg.QuotaPeriod {
+		b.used, b.periodStart = 0, now
+	}
+
+	if limit.Quota > 0 && b.used >= limit.Quota {
+		return b.periodStart.Add(l.cfg.QuotaPeriod).Sub(now), ErrQuotaExceeded
+	}
+
+	if limit.Rate > 0 {
+		b.tokens = math.Min(burst(limit), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+		b.last = now
+
+		if b.tokens < 1 {
+			return time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second)), ErrRateLimited
+		}
+
+		b.tokens--
+	}
+
+	b.used++
+
+	return 0, nil
+}
+
+func burst(limit TenantLimit) float64 {
+	if limit.Burst > 0 {
+		return float64(limit.Burst)
+	}
+
+	return math.Max(1, math.Ceil(limit.Rate))
+}
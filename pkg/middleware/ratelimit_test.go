@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockLimitStore stores the limits in memory.
+type mockLimitStore struct {
+	limits map[string]TenantLimit
+	err    error
+}
+
+func (m *mockLimitStore) Limits(context.Context) (map[string]TenantLimit, error) {
+	return m.limits, m.err
+}
+
+func (m *mockLimitStore) SetLimit(_ context.Context, tenant string, limit TenantLimit) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.limits[tenant] = limit
+
+	return nil
+}
+
+func TestRateLimiter_take(t *testing.T) {
+	store := &mockLimitStore{limits: map[string]TenantLimit{"acme": {Rate: 1, Burst: 2}, "trial": {Quota: 1}}}
+	l := NewRateLimiter(RateLimitConfig{Store: store, Default: TenantLimit{Rate: 0.5}, QuotaPeriod: time.Hour})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	assert.NoError(t, l.Reload(context.Background()))
+
+	tests := []struct {
+		desc       string
+		tenant     string
+		elapsed    time.Duration
+		err        error
+		retryAfter time.Duration
+	}{
+		{"first token of the burst", "acme", 0, nil, 0},
+		{"second token of the burst", "acme", 0, nil, 0},
+		{"burst is spent", "acme", 0, ErrRateLimited, time.Second},
+		{"token is refilled", "acme", time.Second, nil, 0},
+		{"default limit", "unknown", 0, nil, 0},
+		{"default limit is spent", "unknown", 0, ErrRateLimited, 2 * time.Second},
+		{"default limit is shared by the unknown tenants", "random", 0, ErrRateLimited, 2 * time.Second},
+		{"quota", "trial", 0, nil, 0},
+		{"quota is spent", "trial", time.Minute, ErrQuotaExceeded, time.Hour - time.Minute},
+		{"quota is reset", "trial", time.Hour, nil, 0},
+	}
+
+	for i, tc := range tests {
+		now = now.Add(tc.elapsed)
+
+		retryAfter, err := l.take(tc.tenant)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.retryAfter, retryAfter, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestRateLimiter_Handler(t *testing.T) {
+	store := &mockLimitStore{limits: map[string]TenantLimit{"acme": {Rate: 1}}}
+	l := NewRateLimiter(RateLimitConfig{Store: store, Header: "X-API-Key"})
+
+	_ = l.Reload(context.Background())
+
+	handler := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+	tests := []struct {
+		desc   string
+		target string
+		status int
+	}{
+		{"request within the limit", "/orders", http.StatusOK},
+		{"request above the limit", "/orders", http.StatusTooManyRequests},
+		{"well-known endpoint", "/.well-known/health-check", http.StatusOK},
+		{"path with well-known segment", "/orders/.well-known/health", http.StatusTooManyRequests},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, tc.target, http.NoBody)
+		r.Header.Set("X-API-Key", "acme")
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	// the requests of the tenants without a limit are not limited, as the default limit is not set
+	r := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimiter_Identity(t *testing.T) {
+	store := &mockLimitStore{limits: map[string]TenantLimit{"acme": {Rate: 100}}}
+	l := NewRateLimiter(RateLimitConfig{Store: store, Default: TenantLimit{Rate: 1},
+		Identity: func(r *http.Request) string { return r.Header.Get("X-Verified-Tenant") }})
+
+	_ = l.Reload(context.Background())
+
+	handler := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+	tests := []struct {
+		desc   string
+		tenant string
+		status int
+	}{
+		{"tenant of the identity", "acme", http.StatusOK},
+		{"tenant of the identity within its limit", "acme", http.StatusOK},
+		{"unauthenticated request", "", http.StatusOK},
+		{"default limit is spent", "", http.StatusTooManyRequests},
+	}
+
+	for i, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+		r.Header.Set("X-Verified-Tenant", tc.tenant)
+		// the tenant header of the client is not trusted
+		r.Header.Set("X-Tenant-ID", "acme")
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestRateLimiter_evict(t *testing.T) {
+	store := &mockLimitStore{limits: map[string]TenantLimit{"acme": {Rate: 1, Burst: 10}, "trial": {Quota: 5}}}
+	l := NewRateLimiter(RateLimitConfig{Store: store, QuotaPeriod: time.Second})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	_ = l.Reload(context.Background())
+	_, _ = l.take("acme")
+	_, _ = l.take("trial")
+
+	now = now.Add(2 * time.Second)
+	l.evict()
+
+	assert.Len(t, l.buckets, 1, "bucket of acme is held till its tokens are refilled")
+
+	now = now.Add(10 * time.Second)
+	l.evict()
+
+	assert.Empty(t, l.buckets)
+}
+
+func TestRateLimiter_SetLimit(t *testing.T) {
+	store := &mockLimitStore{limits: map[string]TenantLimit{}}
+	l := NewRateLimiter(RateLimitConfig{Store: store})
+
+	_, _ = l.take("acme")
+
+	assert.NoError(t, l.SetLimit(context.Background(), "acme", TenantLimit{Rate: 5}))
+	assert.Equal(t, map[string]TenantLimit{"acme": {Rate: 5}}, store.limits)
+	assert.Equal(t, map[string]TenantLimit{"acme": {Rate: 5}}, l.Limits())
+
+	store.err = ErrServiceDown
+
+	assert.Equal(t, ErrServiceDown, l.SetLimit(context.Background(), "acme", TenantLimit{}))
+	assert.Equal(t, ErrServiceDown, l.Reload(context.Background()))
+	assert.Equal(t, map[string]TenantLimit{"acme": {Rate: 5}}, l.Limits(), "the limits are kept when the store fails")
+}
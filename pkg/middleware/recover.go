@@ -27,20 +27,46 @@ func getAppData(ctx context.Context) map[string]interface{} {
 	return appData
 }
 
-// Recover handles error by allowing the inner HTTP handler to recover from panics.
-func Recover(logger logger) func(inner http.Handler) http.Handler {
+// Recover handles error by allowing the inner HTTP handler to recover from panics. The panics are reported to the
+// reporters, along with the breadcrumbs recorded in the request using AddBreadcrumb.
+func Recover(logger logger, reporters ...PanicReporter) func(inner http.Handler) http.Handler {
 	return func(inner http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			defer panicRecovery(logger, w, r)
+			if len(reporters) > 0 {
+				r = r.WithContext(WithBreadcrumbs(r.Context()))
+			}
+
+			defer panicRecovery(logger, w, r, reporters)
 			inner.ServeHTTP(w, r)
 		})
 	}
 }
 
-func panicRecovery(logger logger, w http.ResponseWriter, r *http.Request) {
+func panicRecovery(logger logger, w http.ResponseWriter, r *http.Request, reporters []PanicReporter) {
 	re := recover()
 
+	// the handler aborts the response which is partially written, ex: a stream which failed, the server closes the
+	// connection for the panic, so that the client does not take the response as complete
+	if err, ok := re.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+		panic(re)
+	}
+
 	if re != nil {
+		d, _ := r.Context().Value(devErrorKey).(*devError)
+
+		if len(reporters) > 0 || d != nil {
+			report := NewPanicReport(r.Context(), re, r)
+
+			if len(reporters) > 0 {
+				ReportPanic(logger, report, reporters...)
+			}
+
+			// the panic is shown in the error page of DevErrorPage
+			if d != nil {
+				d.setPanic(report)
+			}
+		}
+
 		var e string
 		switch t := re.(type) {
 		case string:
@@ -33,6 +33,9 @@ func (r *MockHandlerForPanic) ServeHTTP(_ http.ResponseWriter, req *http.Request
 	case "/panic":
 		// generating a panic case to test recovery for unknown panic type
 		panic(1)
+	case "/abortPanic":
+		// generating a panic case to test the abort of a response
+		panic(http.ErrAbortHandler)
 	}
 }
 
@@ -115,6 +118,21 @@ func TestPanicRecovery(t *testing.T) {
 	}
 }
 
+// TestPanicRecoveryAbort tests that the aborted responses are not recovered, so that the server closes the connection
+func TestPanicRecoveryAbort(t *testing.T) {
+	var b bytes.Buffer
+
+	handler := Recover(log.NewMockLogger(&b))(&MockHandlerForPanic{})
+	w := httptest.NewRecorder()
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/abortPanic", http.NoBody))
+	})
+
+	assert.Empty(t, b.String(), "aborted response must not be logged as a panic")
+	assert.Empty(t, w.Body.String(), "error must not be written to the aborted response")
+}
+
 func TestPanicNewRelicErrorReport(t *testing.T) {
 	testcases := []struct {
 		endpoint           string
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"runtime/metrics"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	cpuMetric   = "/cpu/classes/user:cpu-seconds"
+	allocMetric = "/gc/heap/allocs:bytes"
+
+	defaultAttributionInterval = 10 * time.Second
+)
+
+//nolint:gochecknoglobals // metrics need to be initialized only once
+var (
+	routeCPU = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_http_route_cpu_seconds_total",
+		Help: "Counter of the CPU time attributed to the route, estimated from the time spent in its requests",
+	}, []string{"path", "method"})
+
+	routeAlloc = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_http_route_alloc_bytes_total",
+		Help: "Counter of the heap allocations attributed to the route, estimated from the time spent in its requests",
+	}, []string{"path", "method"})
+
+	_ = prometheus.Register(routeCPU)
+	_ = prometheus.Register(routeAlloc)
+)
+
+type routeKey struct {
+	path   string
+	method string
+}
+
+// attributor distributes the CPU time and the allocations of the process in an interval to the routes, in proportion
+// to the time spent by the requests of each route which completed in the interval.
+type attributor struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+	samples  []metrics.Sample
+	busy     map[routeKey]time.Duration
+}
+
+func newAttributor(interval time.Duration) *attributor {
+	if interval <= 0 {
+		interval = defaultAttributionInterval
+	}
+
+	a := &attributor{
+		interval: interval,
+		last:     time.Now(),
+		samples:  []metrics.Sample{{Name: cpuMetric}, {Name: allocMetric}},
+		busy:     make(map[routeKey]time.Duration),
+	}
+
+	metrics.Read(a.samples)
+
+	return a
+}
+
+// record adds the duration of a request of the route, and attributes the usage once the interval has elapsed.
+func (a *attributor) record(key routeKey, d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.busy[key] += d
+
+	if time.Since(a.last) < a.interval {
+		return
+	}
+
+	a.attribute()
+}
+
+func (a *attributor) attribute() {
+	prev := []metrics.Sample{a.samples[0], a.samples[1]}
+
+	metrics.Read(a.samples)
+	a.last = time.Now()
+
+	cpu := sampleValue(a.samples[0]) - sampleValue(prev[0])
+	allocs := sampleValue(a.samples[1]) - sampleValue(prev[1])
+
+	var total time.Duration
+
+	for _, d := range a.busy {
+		total += d
+	}
+
+	if total > 0 {
+		for key, d := range a.busy {
+			share := float64(d) / float64(total)
+
+			if cpu > 0 {
+				routeCPU.WithLabelValues(key.path, key.method).Add(cpu * share)
+			}
+
+			if allocs > 0 {
+				routeAlloc.WithLabelValues(key.path, key.method).Add(allocs * share)
+			}
+		}
+	}
+
+	a.busy = make(map[routeKey]time.Duration, len(a.busy))
+}
+
+func sampleValue(s metrics.Sample) float64 {
+	switch s.Value.Kind() {
+	case metrics.KindFloat64:
+		return s.Value.Float64()
+	case metrics.KindUint64:
+		return float64(s.Value.Uint64())
+	default:
+		// the metric is not supported by the Go runtime
+		return 0
+	}
+}
+
+// ResourceAttribution attributes the CPU time and the heap allocations of the process to the routes, which are
+// exported as zs_http_route_cpu_seconds_total and zs_http_route_alloc_bytes_total. The usage is measured for the
+// whole process every interval, and is distributed to the routes in proportion to the time spent in their requests,
+// so it is an estimate suitable to find the routes which consume the most resources. The interval defaults to 10s
+// when it is zero.
+//
+// The requests are also run with the route and method pprof labels, so that the CPU profiles of the metrics server,
+// ex: of /debug/pprof/profile, can be filtered by the route using `go tool pprof -tagfocus`.
+func ResourceAttribution(interval time.Duration) func(inner http.Handler) http.Handler {
+	a := newAttributor(interval)
+
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ExemptPath(r) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			key := routeKey{method: r.Method}
+
+			if route := mux.CurrentRoute(r); route != nil {
+				key.path, _ = route.GetPathTemplate()
+				key.path = strings.TrimSuffix(key.path, "/")
+			}
+
+			start := time.Now()
+
+			pprof.Do(r.Context(), pprof.Labels("route", key.path, "method", key.method), func(ctx context.Context) {
+				inner.ServeHTTP(w, r.WithContext(ctx))
+			})
+
+			a.record(key, time.Since(start))
+		})
+	}
+}
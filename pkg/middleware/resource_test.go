@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+//nolint:gochecknoglobals // the allocations must escape to the heap to be measured
+var resourceSink [][]byte
+
+func TestResourceAttribution(t *testing.T) {
+	var label string
+
+	muxRouter := mux.NewRouter()
+	muxRouter.Use(ResourceAttribution(time.Nanosecond))
+	muxRouter.HandleFunc("/reports/{id}", func(w http.ResponseWriter, r *http.Request) {
+		label, _ = pprof.Label(r.Context(), "route")
+
+		for i := 0; i < 100; i++ {
+			resourceSink = append(resourceSink, make([]byte, 1024))
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	})
+	muxRouter.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {})
+
+	before := testutil.ToFloat64(routeAlloc.WithLabelValues("/reports/{id}", http.MethodGet))
+
+	for _, target := range []string{"/reports/1", "/ping", "/reports/2"} {
+		muxRouter.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, http.NoBody))
+	}
+
+	assert.Equal(t, "/reports/{id}", label)
+	assert.Greater(t, testutil.ToFloat64(routeAlloc.WithLabelValues("/reports/{id}", http.MethodGet)), before)
+
+	resourceSink = nil
+}
+
+func TestAttributor_Share(t *testing.T) {
+	a := newAttributor(time.Hour)
+
+	a.busy[routeKey{"/orders", http.MethodGet}] = 3 * time.Second
+	a.busy[routeKey{"/users", http.MethodGet}] = time.Second
+
+	orders := testutil.ToFloat64(routeAlloc.WithLabelValues("/orders", http.MethodGet))
+	users := testutil.ToFloat64(routeAlloc.WithLabelValues("/users", http.MethodGet))
+
+	resourceSink = append(resourceSink, make([]byte, 1<<20))
+	a.attribute()
+
+	orders = testutil.ToFloat64(routeAlloc.WithLabelValues("/orders", http.MethodGet)) - orders
+	users = testutil.ToFloat64(routeAlloc.WithLabelValues("/users", http.MethodGet)) - users
+
+	assert.InDelta(t, 3, orders/users, 0.001, "allocations are not attributed in proportion to the time of the routes")
+	assert.Empty(t, a.busy)
+
+	resourceSink = nil
+}
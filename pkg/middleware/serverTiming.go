@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The phases of a request which are timed, in the Server-Timing header of the responses.
+const (
+	TimingDatastore     = "datastore"
+	TimingHandler       = "handler"
+	TimingSerialization = "serialization"
+)
+
+const serverTimingKey contextKey = "serverTiming"
+
+// ServerTiming holds the time spent by a request in each of its phases, ex: the time of its datastore queries. It is
+// added to the request context by the middlewares which report the timing, so that the datastores called with the
+// context can record their time.
+type ServerTiming struct {
+	mu        sync.Mutex
+	names     []string
+	durations map[string]time.Duration
+	started   map[string]time.Time
+}
+
+// Add adds the duration to the time spent in the phase.
+func (t *ServerTiming) Add(name string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.durations == nil {
+		t.durations = make(map[string]time.Duration)
+	}
+
+	if _, ok := t.durations[name]; !ok {
+		t.names = append(t.names, name)
+	}
+
+	t.durations[name] += d
+}
+
+// Begin starts the timing of the phase, which is added to the time spent in the phase by End.
+func (t *ServerTiming) Begin(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.started == nil {
+		t.started = make(map[string]time.Time)
+	}
+
+	t.started[name] = time.Now()
+}
+
+// End adds the time since Begin to the time spent in the phase, it does nothing when the phase is not begun.
+func (t *ServerTiming) End(name string) {
+	t.mu.Lock()
+	start, ok := t.started[name]
+	delete(t.started, name)
+	t.mu.Unlock()
+
+	if ok {
+		t.Add(name, time.Since(start))
+	}
+}
+
+// Duration returns the time spent in the phase.
+func (t *ServerTiming) Duration(name string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.durations[name]
+}
+
+// Header returns the value of the Server-Timing header, ex: datastore;dur=12.5, handler;dur=40.1, with the durations in
+// milliseconds, in the order in which the phases were first recorded.
+func (t *ServerTiming) Header() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	metrics := make([]string, 0, len(t.names))
+
+	for _, name := range t.names {
+		ms := float64(t.durations[name].Microseconds()) / float64(time.Millisecond/time.Microsecond)
+		metrics = append(metrics, name+";dur="+strconv.FormatFloat(ms, 'f', -1, 64))
+	}
+
+	return strings.Join(metrics, ", ")
+}
+
+// WithServerTiming returns a copy of ctx carrying the timing, the zero value of which is ready to use.
+func WithServerTiming(ctx context.Context, t *ServerTiming) context.Context {
+	return context.WithValue(ctx, serverTimingKey, t)
+}
+
+// ServerTimingFrom returns the ServerTiming of the context, it returns nil when the timing of the request is not
+// reported.
+func ServerTimingFrom(ctx context.Context) *ServerTiming {
+	if ctx == nil {
+		return nil
+	}
+
+	t, _ := ctx.Value(serverTimingKey).(*ServerTiming)
+
+	return t
+}
+
+// AddServerTiming adds the duration to the time spent in the phase by the request of the context. It returns false when
+// the context does not have a ServerTiming, ie: the timing of the request is not reported.
+func AddServerTiming(ctx context.Context, name string, d time.Duration) bool {
+	t := ServerTimingFrom(ctx)
+	if t == nil {
+		return false
+	}
+
+	t.Add(name, d)
+
+	return true
+}
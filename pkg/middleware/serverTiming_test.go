@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerTiming(t *testing.T) {
+	timing := &ServerTiming{}
+	ctx := WithServerTiming(context.Background(), timing)
+
+	assert.True(t, AddServerTiming(ctx, TimingDatastore, 1500*time.Microsecond))
+	assert.True(t, AddServerTiming(ctx, TimingHandler, 2*time.Millisecond))
+	assert.True(t, AddServerTiming(ctx, TimingDatastore, time.Millisecond))
+
+	timing.End(TimingSerialization)
+
+	assert.Equal(t, 2500*time.Microsecond, timing.Duration(TimingDatastore))
+	assert.Equal(t, "datastore;dur=2.5, handler;dur=2", timing.Header(), "the phases which are not begun are not timed")
+
+	timing.Begin(TimingSerialization)
+	timing.End(TimingSerialization)
+
+	assert.Contains(t, timing.Header(), ", serialization;dur=")
+	assert.Equal(t, timing, ServerTimingFrom(ctx))
+
+	assert.False(t, AddServerTiming(context.Background(), TimingDatastore, time.Millisecond),
+		"the time is not recorded when the timing of the request is not reported")
+	assert.Nil(t, ServerTimingFrom(nil)) //nolint:staticcheck // the nil context is handled
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"gofr.dev/pkg/log"
+)
+
+const (
+	// ServiceSignatureHeader carries the JWS, with a detached payload, which signs a service-to-service request.
+	ServiceSignatureHeader = "X-Service-Signature"
+	// ServiceNameKey is the context key of the name of the service which signed the request.
+	ServiceNameKey contextKey = "serviceName"
+
+	defaultSignatureSkew = 5 * time.Minute
+)
+
+type signatureHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Issuer    string `json:"iss"`
+	IssuedAt  int64  `json:"iat"`
+}
+
+// ServiceKey is the private key with which a service signs its requests to the other services.
+type ServiceKey struct {
+	// Service is the name of the signing service, the peers register its public keys against this name.
+	Service string
+	// KeyID identifies the key among the keys of the service, a new id is used whenever the key is rotated.
+	KeyID string
+	// Algorithm is the JWS algorithm of the key, ex: EdDSA, ES256 or RS256.
+	Algorithm  string
+	PrivateKey crypto.PrivateKey
+}
+
+// Sign signs the method, the request URI and the body of the request, and sets the signature in the
+// X-Service-Signature header as a JWS with a detached payload, ie: <header>..<signature>
+func (k *ServiceKey) Sign(r *http.Request, body []byte, now time.Time) error {
+	method, err := signingMethod(k.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	header, err := json.Marshal(signatureHeader{Algorithm: k.Algorithm, KeyID: k.KeyID, Issuer: k.Service, IssuedAt: now.Unix()})
+	if err != nil {
+		return err
+	}
+
+	protected := base64.RawURLEncoding.EncodeToString(header)
+
+	signature, err := method.Sign(protected+"."+signaturePayload(r.Method, r.URL.RequestURI(), body), k.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set(ServiceSignatureHeader, protected+".."+signature)
+
+	return nil
+}
+
+// signaturePayload returns the encoded payload of the JWS, which is not sent along with the signature, as the
+// receiver derives it from the request.
+func signaturePayload(method, requestURI string, body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(method + "\n" + requestURI + "\n" + base64.RawURLEncoding.EncodeToString(sum[:])))
+}
+
+// signingMethod returns the JWS signing method of the algorithm. Only the asymmetric algorithms are supported, as
+// a symmetric algorithm would allow a public key to be used as the secret of a forged signature.
+func signingMethod(alg string) (jwt.SigningMethod, error) {
+	method := jwt.GetSigningMethod(alg)
+
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA, *jwt.SigningMethodEd25519:
+		return method, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+}
+
+// PeerKey is a public key of a peer service, with which the requests signed by the service are verified.
+type PeerKey struct {
+	Service   string
+	KeyID     string
+	PublicKey crypto.PublicKey
+	// Expiry is the time after which the key is not accepted, so that a rotated key is retired without a deployment.
+	// The key does not expire when it is zero.
+	Expiry time.Time
+}
+
+// PeerKeys is the registry of the public keys of the peer services. A service rotates its key by registering the
+// new key with its peers before signing with it, and the old key is removed, or expires, after the rotation.
+type PeerKeys struct {
+	mu   sync.RWMutex
+	keys map[string]PeerKey
+}
+
+// NewPeerKeys returns a registry of the keys.
+func NewPeerKeys(keys ...PeerKey) *PeerKeys {
+	p := &PeerKeys{keys: make(map[string]PeerKey, len(keys))}
+
+	for _, k := range keys {
+		p.Add(k)
+	}
+
+	return p
+}
+
+// Add registers the key, replacing the key with the same service and key id.
+func (p *PeerKeys) Add(k PeerKey) {
+	p.mu.Lock()
+	p.keys[k.Service+"/"+k.KeyID] = k
+	p.mu.Unlock()
+}
+
+// Remove removes the key of the service with the key id.
+func (p *PeerKeys) Remove(service, keyID string) {
+	p.mu.Lock()
+	delete(p.keys, service+"/"+keyID)
+	p.mu.Unlock()
+}
+
+func (p *PeerKeys) get(service, keyID string, now time.Time) (PeerKey, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	k, ok := p.keys[service+"/"+keyID]
+	if !ok || (!k.Expiry.IsZero() && now.After(k.Expiry)) {
+		return PeerKey{}, false
+	}
+
+	return k, true
+}
+
+// ParsePublicKeyPEM parses a PEM encoded PKIX public key, ex: the output of openssl pkey -pubout
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded key found")
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// ServiceAuth middleware verifies the signatures of the requests of the peer services against their registered keys,
+// and rejects the requests whose signature is missing, invalid or was created more than maxSkew ago. The name of the
+// calling service is set in the request context with the key ServiceNameKey. The well-known endpoints are exempted.
+func ServiceAuth(logger log.Logger, keys *PeerKeys, maxSkew time.Duration) func(inner http.Handler) http.Handler {
+	if maxSkew <= 0 {
+		maxSkew = defaultSignatureSkew
+	}
+
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ExemptPath(r) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			service, err := verifyServiceSignature(r, keys, maxSkew, time.Now())
+			if err != nil {
+				logger.Debugf("service signature of %v %v could not be verified: %v", r.Method, r.URL.Path, err)
+
+				description, code := GetDescription(err)
+				e := FetchErrResponseWithCode(code, description, err.Error())
+				ErrorResponse(w, r, logger, *e)
+
+				return
+			}
+
+			inner.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ServiceNameKey, service)))
+		})
+	}
+}
+
+//nolint:gocyclo // every field of the signature is validated
+func verifyServiceSignature(r *http.Request, keys *PeerKeys, maxSkew time.Duration, now time.Time) (string, error) {
+	value := r.Header.Get(ServiceSignatureHeader)
+	if value == "" {
+		return "", ErrMissingSignature
+	}
+
+	parts := strings.Split(value, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return "", ErrInvalidSignature
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	var header signatureHeader
+
+	if err = json.Unmarshal(decoded, &header); err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	issuedAt := time.Unix(header.IssuedAt, 0)
+	if issuedAt.Before(now.Add(-maxSkew)) || issuedAt.After(now.Add(maxSkew)) {
+		return "", ErrInvalidSignature
+	}
+
+	key, ok := keys.get(header.Issuer, header.KeyID, now)
+	if !ok {
+		return "", ErrInvalidSignature
+	}
+
+	method, err := signingMethod(header.Algorithm)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	var body []byte
+
+	if r.Body != nil {
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return "", ErrInvalidSignature
+		}
+
+		// the body is restored, so that the handlers can read it
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if method.Verify(parts[0]+"."+signaturePayload(r.Method, r.URL.RequestURI(), body), parts[2], key.PublicKey) != nil {
+		return "", ErrInvalidSignature
+	}
+
+	return header.Issuer, nil
+}
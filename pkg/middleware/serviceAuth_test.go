@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+func signedRequest(t *testing.T, key *ServiceKey, method, target, body string, now time.Time) *http.Request {
+	t.Helper()
+
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+
+	if err := key.Sign(r, []byte(body), now); err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
+func TestServiceAuth(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	now := time.Now()
+
+	orders := &ServiceKey{Service: "orders", KeyID: "2023-05", Algorithm: "EdDSA", PrivateKey: priv}
+	billing := &ServiceKey{Service: "billing", KeyID: "1", Algorithm: "ES256", PrivateKey: ecKey}
+	retired := &ServiceKey{Service: "orders", KeyID: "2023-01", Algorithm: "EdDSA", PrivateKey: priv}
+
+	keys := NewPeerKeys(
+		PeerKey{Service: "orders", KeyID: "2023-05", PublicKey: pub},
+		PeerKey{Service: "billing", KeyID: "1", PublicKey: &ecKey.PublicKey},
+		PeerKey{Service: "orders", KeyID: "2023-01", PublicKey: pub, Expiry: now.Add(-time.Hour)},
+	)
+
+	tampered := signedRequest(t, orders, http.MethodPost, "/payments", `{"amount":10}`, now)
+	tampered.Body = io.NopCloser(strings.NewReader(`{"amount":1000}`))
+
+	otherQuery := signedRequest(t, orders, http.MethodGet, "/payments?id=1", "", now)
+	otherQuery.URL.RawQuery = "id=2"
+
+	tests := []struct {
+		desc       string
+		req        *http.Request
+		statusCode int
+		service    string
+	}{
+		{"EdDSA", signedRequest(t, orders, http.MethodPost, "/payments?id=1", `{"amount":10}`, now), http.StatusOK, "orders"},
+		{"ES256", signedRequest(t, billing, http.MethodGet, "/payments", "", now), http.StatusOK, "billing"},
+		{"missing signature", httptest.NewRequest(http.MethodGet, "/payments", http.NoBody), http.StatusUnauthorized, ""},
+		{"tampered body", tampered, http.StatusUnauthorized, ""},
+		{"tampered query", otherQuery, http.StatusUnauthorized, ""},
+		{"expired key", signedRequest(t, retired, http.MethodGet, "/payments", "", now), http.StatusUnauthorized, ""},
+		{"old signature", signedRequest(t, orders, http.MethodGet, "/payments", "", now.Add(-time.Hour)), http.StatusUnauthorized, ""},
+		{"unknown service", signedRequest(t, &ServiceKey{Service: "billing", KeyID: "2023-05", Algorithm: "EdDSA", PrivateKey: priv},
+			http.MethodGet, "/payments", "", now), http.StatusUnauthorized, ""},
+		{"well-known endpoint", httptest.NewRequest(http.MethodGet, "/.well-known/health-check", http.NoBody), http.StatusOK, ""},
+	}
+
+	for i, tc := range tests {
+		var (
+			service string
+			body    []byte
+		)
+
+		handler := ServiceAuth(log.NewMockLogger(io.Discard), keys, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			service, _ = r.Context().Value(ServiceNameKey).(string)
+			body, _ = io.ReadAll(r.Body)
+		}))
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, tc.req)
+
+		assert.Equal(t, tc.statusCode, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.service, service, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if i == 0 {
+			assert.Equal(t, `{"amount":10}`, string(body), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestServiceKey_UnsupportedAlgorithm(t *testing.T) {
+	for i, alg := range []string{"HS256", "none", ""} {
+		key := &ServiceKey{Service: "orders", KeyID: "1", Algorithm: alg, PrivateKey: []byte("secret")}
+
+		err := key.Sign(httptest.NewRequest(http.MethodGet, "/", http.NoBody), nil, time.Now())
+
+		assert.Error(t, err, "TEST[%d], failed.\n%s", i, alg)
+	}
+}
+
+func TestParsePublicKeyPEM(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+
+	var buf bytes.Buffer
+
+	_ = pem.Encode(&buf, &pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	key, err := ParsePublicKeyPEM(buf.Bytes())
+
+	assert.NoError(t, err)
+	assert.Equal(t, pub, key)
+
+	_, err = ParsePublicKeyPEM([]byte("not a key"))
+
+	assert.Error(t, err)
+}
+
+func TestPeerKeys_Rotation(t *testing.T) {
+	keys := NewPeerKeys(PeerKey{Service: "orders", KeyID: "1"})
+	keys.Add(PeerKey{Service: "orders", KeyID: "2"})
+
+	_, ok := keys.get("orders", "1", time.Now())
+	assert.True(t, ok, "old key is not accepted during the rotation")
+
+	keys.Remove("orders", "1")
+
+	_, ok = keys.get("orders", "1", time.Now())
+	assert.False(t, ok, "removed key is accepted")
+
+	_, ok = keys.get("orders", "2", time.Now())
+	assert.True(t, ok, "new key is not accepted")
+}
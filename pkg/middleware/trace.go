@@ -1,11 +1,11 @@
 package middleware
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
@@ -35,18 +35,32 @@ func Trace(appName, appVersion, tracerExporter string) func(inner http.Handler)
 				trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(semconv.ServiceNameKey.String(appName),
 					semconv.TelemetrySDKNameKey.String(tracerExporter)))
 
-			defer func() {
-				//nolint // cannot create custom type as it will result in import cycle
-				*r = *r.Clone(context.WithValue(r.Context(), "path", ""))
+			srw := &StatusResponseWriter{ResponseWriter: w}
 
+			defer func() {
+				setSpanStatus(span, srw.status)
 				span.End()
 			}()
 
-			inner.ServeHTTP(w, r.WithContext(ctx))
+			inner.ServeHTTP(srw, r.WithContext(ctx))
 		})
 	}
 }
 
+// setSpanStatus records the status code of the response in the span, and marks the span as failed for the server
+// errors, so that the traces of the failed requests are sampled by the adaptive sampling.
+func setSpanStatus(span trace.Span, status int) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(status))
+
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
+
 // getTraceID is used to fetch the correlationID from request header.
 func getTraceID(r *http.Request) string {
 	if id := r.Header.Get("X-B3-TraceId"); id != "" {
@@ -0,0 +1,282 @@
+// Package pii masks the personal data in the values which are responded and logged. The fields of the personal
+// data are tagged with their kind, ex:
+//
+//	type User struct {
+//		Name  string `json:"name"`
+//		Email string `json:"email" pii:"email"`
+//		Phone string `json:"phone" pii:"phone,tokenize"`
+//	}
+//
+// A masked field keeps the parts of the value which are needed to recognize it, ex: j***@example.com, while a
+// tokenized field is replaced by a token, which is the same for the same value, so that the values can still be
+// correlated, ex: in the logs, without being revealed.
+package pii
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// maxDepth stops masking cyclic values, ex: a linked list with a loop.
+	maxDepth      = 32
+	visibleDigits = 4
+	tokenLength   = 16
+	tokenizeOpt   = "tokenize"
+)
+
+//nolint:gochecknoglobals // the type information and the key are shared by all the values masked by the application
+var (
+	types    sync.Map // reflect.Type -> bool
+	tokenKey atomic.Value
+)
+
+// SetTokenKey sets the secret with which the tokens are generated. The tokenized fields are masked instead, when the
+// key is not set, as the tokens of a known key can be reversed by tokenizing the likely values.
+func SetTokenKey(key []byte) {
+	tokenKey.Store(key)
+}
+
+// Mask returns a copy of the value in which the fields tagged with pii are masked, or tokenized, unless the caller is
+// entitled to the kind of the field. All the fields are masked when entitled is nil. The value is returned as is when
+// it does not have personal data.
+func Mask(v interface{}, entitled func(kind string) bool) interface{} {
+	if !Contains(v) {
+		return v
+	}
+
+	if entitled == nil {
+		entitled = func(string) bool { return false }
+	}
+
+	return mask(reflect.ValueOf(v), entitled, 0).Interface()
+}
+
+// Contains reports whether the value has a field tagged with pii.
+func Contains(v interface{}) bool {
+	return v != nil && containsPII(reflect.ValueOf(v), 0)
+}
+
+// mayHavePII reports whether the values of the type can have tagged fields, the values of the interfaces are known
+// only at runtime, hence they may have them.
+func mayHavePII(t reflect.Type) bool {
+	if has, ok := types.Load(t); ok {
+		return has.(bool)
+	}
+
+	has := inspect(t, make(map[reflect.Type]bool))
+	types.Store(t, has)
+
+	return has
+}
+
+// inspect walks the type, the types which are being inspected are skipped, so that the recursive types terminate,
+// the tagged fields of a recursive type are found from where it is first reached.
+func inspect(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	//nolint:exhaustive // the other kinds can not have fields
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return inspect(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.IsExported() && (f.Tag.Get("pii") != "" || inspect(f.Type, visiting)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// containsPII reports whether the value has a tagged field, so that the values without personal data are not copied.
+//
+//nolint:gocognit // the kinds are walked alike, splitting them hurts the readability
+func containsPII(v reflect.Value, depth int) bool {
+	if depth > maxDepth || !v.IsValid() || !mayHavePII(v.Type()) {
+		return false
+	}
+
+	//nolint:exhaustive // mayHavePII is false for the other kinds
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return !v.IsNil() && containsPII(v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if containsPII(v.Index(i), depth+1) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if containsPII(iter.Value(), depth+1) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.IsExported() && (f.Tag.Get("pii") != "" || containsPII(v.Field(i), depth+1)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+//nolint:gocognit,gocyclo // the kinds are copied alike, splitting them hurts the readability
+func mask(v reflect.Value, entitled func(string) bool, depth int) reflect.Value {
+	if depth > maxDepth || !v.IsValid() || !mayHavePII(v.Type()) {
+		return v
+	}
+
+	//nolint:exhaustive // mayHavePII is false for the other kinds
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.New(v.Type()).Elem()
+		out.Set(mask(v.Elem(), entitled, depth+1))
+
+		return out
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(mask(v.Elem(), entitled, depth+1))
+
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(mask(v.Index(i), entitled, depth+1))
+		}
+
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(mask(v.Index(i), entitled, depth+1))
+		}
+
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), mask(iter.Value(), entitled, depth+1))
+		}
+
+		return out
+	case reflect.Struct:
+		return maskStruct(v, entitled, depth)
+	}
+
+	return v
+}
+
+func maskStruct(v reflect.Value, entitled func(string) bool, depth int) reflect.Value {
+	out := reflect.New(v.Type()).Elem()
+	out.Set(v)
+
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("pii")
+		if tag == "" {
+			out.Field(i).Set(mask(v.Field(i), entitled, depth+1))
+			continue
+		}
+
+		kind, opts, _ := strings.Cut(tag, ",")
+		if entitled(kind) {
+			continue
+		}
+
+		maskField(out.Field(i), kind, opts == tokenizeOpt)
+	}
+
+	return out
+}
+
+// maskField masks the string fields, and the pointers to them, the other fields are set to their zero value, as they
+// can not hold a masked value.
+func maskField(f reflect.Value, kind string, tokenize bool) {
+	switch {
+	case f.Kind() == reflect.String:
+		f.SetString(maskValue(f.String(), kind, tokenize))
+	case f.Kind() == reflect.Ptr && f.Type().Elem().Kind() == reflect.String:
+		if f.IsNil() {
+			return
+		}
+
+		s := reflect.New(f.Type().Elem())
+		s.Elem().SetString(maskValue(f.Elem().String(), kind, tokenize))
+		f.Set(s)
+	default:
+		f.Set(reflect.Zero(f.Type()))
+	}
+}
+
+func maskValue(s, kind string, tokenize bool) string {
+	if s == "" {
+		return s
+	}
+
+	if key, _ := tokenKey.Load().([]byte); tokenize && len(key) > 0 {
+		return Token(key, kind, s)
+	}
+
+	switch kind {
+	case "email":
+		if local, domain, ok := strings.Cut(s, "@"); ok && local != "" {
+			return local[:1] + "***@" + domain
+		}
+	case "phone", "card", "account":
+		if len(s) > visibleDigits {
+			return "***" + s[len(s)-visibleDigits:]
+		}
+	}
+
+	return "***"
+}
+
+// Token returns the token of the value of the kind, which is an HMAC of the value with the key, ex: tok_1f2e3d4c5b6a7980.
+func Token(key []byte, kind, value string) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(kind + ":" + value))
+
+	return "tok_" + hex.EncodeToString(h.Sum(nil))[:tokenLength]
+}
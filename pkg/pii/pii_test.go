@@ -0,0 +1,81 @@
+package pii
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type contact struct {
+	Name  string  `json:"name"`
+	Email string  `json:"email" pii:"email"`
+	Phone *string `json:"phone" pii:"phone"`
+	SSN   string  `json:"ssn" pii:"ssn,tokenize"`
+	Age   int     `json:"age" pii:"age"`
+}
+
+type account struct {
+	ID       int
+	Contacts []contact
+	Primary  *contact
+	Extra    interface{}
+	Labels   map[string]contact
+	Parent   *account
+}
+
+func TestMask(t *testing.T) {
+	phone := "+919876543210"
+	c := contact{Name: "Jane", Email: "jane@example.com", Phone: &phone, SSN: "123-45-6789", Age: 30}
+	masked := contact{Name: "Jane", Email: "j***@example.com", Phone: strPtr("***3210"), SSN: "***"}
+
+	tests := []struct {
+		desc     string
+		value    interface{}
+		entitled func(string) bool
+		expected interface{}
+	}{
+		{"nil", nil, nil, nil},
+		{"value without personal data", map[string]int{"a": 1}, nil, map[string]int{"a": 1}},
+		{"struct", c, nil, masked},
+		{"pointer to struct", &c, nil, &masked},
+		{"entitled kinds", c, func(kind string) bool { return kind == "email" || kind == "age" },
+			contact{Name: "Jane", Email: "jane@example.com", Phone: strPtr("***3210"), SSN: "***", Age: 30}},
+		{"nested values", account{ID: 1, Contacts: []contact{c}, Primary: &c, Extra: c, Labels: map[string]contact{"home": c},
+			Parent: &account{ID: 2, Extra: []interface{}{c}}}, nil,
+			account{ID: 1, Contacts: []contact{masked}, Primary: &masked, Extra: masked, Labels: map[string]contact{"home": masked},
+				Parent: &account{ID: 2, Extra: []interface{}{masked}}}},
+		{"map of interfaces", map[string]interface{}{"contact": c, "count": 1}, nil,
+			map[string]interface{}{"contact": masked, "count": 1}},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.expected, Mask(tc.value, tc.entitled), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.Equal(t, "jane@example.com", c.Email, "the value of the caller must not be changed")
+}
+
+func TestMask_Tokenize(t *testing.T) {
+	SetTokenKey([]byte("secret"))
+	defer SetTokenKey(nil)
+
+	first := Mask(contact{SSN: "123-45-6789"}, nil).(contact)
+	second := Mask(contact{SSN: "123-45-6789"}, nil).(contact)
+	other := Mask(contact{SSN: "987-65-4321"}, nil).(contact)
+
+	assert.Equal(t, Token([]byte("secret"), "ssn", "123-45-6789"), first.SSN)
+	assert.Regexp(t, `^tok_[0-9a-f]{16}$`, first.SSN)
+	assert.Equal(t, first.SSN, second.SSN, "the token of a value must be the same")
+	assert.NotEqual(t, first.SSN, other.SSN)
+}
+
+func TestContains(t *testing.T) {
+	assert.True(t, Contains(account{Extra: contact{}}))
+	assert.False(t, Contains(account{Extra: 1}))
+	assert.False(t, Contains("jane@example.com"))
+	assert.False(t, Contains(nil))
+}
+
+func strPtr(s string) *string {
+	return &s
+}
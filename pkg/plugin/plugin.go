@@ -0,0 +1,109 @@
+/*
+Package plugin defines the interfaces through which external modules add datastores, pubsub backends and middlewares
+to the gofr applications, without changes to the framework.
+
+A plugin registers itself in the init function of its package, so that it is added to an application by a blank
+import, like the drivers of database/sql:
+
+	import _ "example.com/gofr-couchbase"
+
+The registered plugins are initialized on creating the application, using the configuration of the application.
+*/
+package plugin
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+)
+
+//nolint:gochecknoglobals // the plugins are registered from the init functions of their packages
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+// Config is the configuration of the application, the plugins read their configuration from it, ex: COUCHBASE_HOST.
+type Config interface {
+	Get(string) string
+	GetOrDefault(string, string) string
+}
+
+// Plugin is initialized on creating the application. It is returned by the Plugin method of the application, so that
+// the handlers can use it, ex: app.Plugin("couchbase").(*couchbase.Client).
+type Plugin interface {
+	// Init connects the plugin to its backend. A plugin which returns an error is logged, and not added to the application.
+	Init(c Config, logger log.Logger) error
+}
+
+// HealthChecker is implemented by the plugins whose health is reported in the health check of the application.
+type HealthChecker interface {
+	HealthCheck() types.Health
+}
+
+// MetricsCollector is implemented by the plugins which expose metrics, the collectors are registered with Prometheus.
+type MetricsCollector interface {
+	Collectors() []prometheus.Collector
+}
+
+// Middleware is implemented by the plugins which add a middleware to the HTTP server.
+type Middleware interface {
+	Middleware() func(inner http.Handler) http.Handler
+}
+
+// Factory creates the plugin for an application.
+//
+// A plugin implementing pubsub.PublisherSubscriber is the pubsub of the application when PUBSUB_BACKEND is the name
+// of the plugin.
+type Factory func() Plugin
+
+// Register registers the plugin by its name. It panics when the factory is nil or a plugin with the same name is
+// already registered, as it is called from the init functions.
+func Register(name string, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if f == nil {
+		panic("plugin: factory of " + name + " is nil")
+	}
+
+	if _, ok := factories[name]; ok {
+		panic("plugin: " + name + " is registered twice")
+	}
+
+	factories[name] = f
+}
+
+// Registered returns the names of the registered plugins, sorted.
+func Registered() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// New creates the registered plugin, it returns nil when no plugin is registered by the name.
+func New(name string) Plugin {
+	mu.RLock()
+	f, ok := factories[name]
+	mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	return f()
+}
@@ -0,0 +1,36 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+type mockPlugin struct{}
+
+func (mockPlugin) Init(Config, log.Logger) error { return nil }
+
+func TestRegister(t *testing.T) {
+	Register("plugin-b", func() Plugin { return mockPlugin{} })
+	Register("plugin-a", func() Plugin { return mockPlugin{} })
+
+	assert.Equal(t, []string{"plugin-a", "plugin-b"}, Registered())
+	assert.Equal(t, mockPlugin{}, New("plugin-a"))
+	assert.Nil(t, New("plugin-c"))
+
+	tests := []struct {
+		desc    string
+		name    string
+		factory Factory
+		panic   string
+	}{
+		{"registered twice", "plugin-a", func() Plugin { return mockPlugin{} }, "plugin: plugin-a is registered twice"},
+		{"nil factory", "plugin-c", nil, "plugin: factory of plugin-c is nil"},
+	}
+
+	for i, tc := range tests {
+		assert.PanicsWithValue(t, tc.panic, func() { Register(tc.name, tc.factory) }, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"strconv"
+	"time"
+)
+
+// the outcomes of a call recorded by the zs_http_service_calls metric
+const (
+	outcomeSuccess        = "success"
+	outcomeError          = "error"
+	outcomeRetryExhausted = "retry_exhausted"
+	outcomeCircuitOpen    = "circuit_open"
+)
+
+// the attempts of a call recorded by the zs_http_service_attempt metric, the first try is split from the retries so
+// that the slowness of the service can be told apart from the load added by the retries.
+const (
+	attemptFirst = "first"
+	attemptRetry = "retry"
+)
+
+// observeAttempt records the duration of an attempt of the call, i is the index of the attempt starting from 0.
+func (h *httpService) observeAttempt(method string, i, statusCode int, start time.Time) {
+	attempt := attemptFirst
+	if i > 0 {
+		attempt = attemptRetry
+	}
+
+	httpServiceAttempt.WithLabelValues(h.url, method, attempt, strconv.Itoa(statusCode)).Observe(time.Since(start).Seconds())
+}
+
+// countCall records the outcome of the call.
+func (h *httpService) countCall(method, outcome string) {
+	httpServiceCalls.WithLabelValues(h.url, method, outcome).Inc()
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+// attemptCount returns the number of attempts recorded for the host, of either of the statuses returned by the test server
+func attemptCount(t *testing.T, host, attempt string) uint64 {
+	t.Helper()
+
+	var count uint64
+
+	for _, status := range []string{"200", "500"} {
+		observer, err := httpServiceAttempt.GetMetricWithLabelValues(host, http.MethodGet, attempt, status)
+		assert.NoError(t, err)
+
+		var m dto.Metric
+
+		_ = observer.(prometheus.Metric).Write(&m)
+
+		count += m.GetHistogram().GetSampleCount()
+	}
+
+	return count
+}
+
+func TestService_CallMetrics(t *testing.T) {
+	tests := []struct {
+		desc     string
+		statuses []int // the statuses returned by the server on the successive attempts
+		healthy  bool
+		outcome  string
+		first    uint64
+		retries  uint64
+	}{
+		{"success on first try", []int{200}, true, outcomeSuccess, 1, 0},
+		{"success on retry", []int{500, 500, 200}, true, outcomeSuccess, 1, 2},
+		{"retries exhausted", []int{500, 500, 500}, true, outcomeRetryExhausted, 1, 2},
+		{"circuit open", nil, false, outcomeCircuitOpen, 0, 0},
+	}
+
+	for i, tc := range tests {
+		var calls int
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the heartbeats of the surge protector are not the attempts of the call
+			if r.URL.Path != "/orders" {
+				return
+			}
+
+			w.WriteHeader(tc.statuses[calls])
+			calls++
+		}))
+
+		h := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{NumOfRetries: 2})
+		h.isHealthy = tc.healthy
+		h.CustomRetry = func(_ log.Logger, _ error, statusCode, _ int) bool {
+			return statusCode == http.StatusInternalServerError
+		}
+
+		_, _ = h.Get(context.Background(), "orders", nil)
+
+		assert.Equal(t, 1.0, testutil.ToFloat64(httpServiceCalls.WithLabelValues(ts.URL, http.MethodGet, tc.outcome)),
+			"TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.first, attemptCount(t, ts.URL, attemptFirst), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.retries, attemptCount(t, ts.URL, attemptRetry), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		ts.Close()
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"gofr.dev/pkg/middleware"
+)
+
+// get makes the GET request, the concurrent identical requests are coalesced into a single request to the service
+// when coalescing is enabled, and each of them gets a copy of its response.
+func (h *httpService) get(ctx context.Context, api string, params map[string]interface{}, headers map[string]string) (*Response, error) {
+	if h.coalescer == nil {
+		return h.call(ctx, http.MethodGet, api, params, nil, headers)
+	}
+
+	key := generateKey(h.url+"/"+api, params, h.coalesceHeaders(ctx, headers))
+
+	// the request is made without the cancellation of the context which started it, as it is shared by the other
+	// requests, each of which stops waiting for it when its own context is done
+	ch := h.coalescer.DoChan(key, func() (interface{}, error) {
+		return h.call(context.WithoutCancel(ctx), http.MethodGet, api, params, nil, headers)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, RequestCanceled{}
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
+		resp, _ := res.Val.(*Response)
+		if !res.Shared {
+			return resp, nil
+		}
+
+		return &Response{Body: append([]byte(nil), resp.Body...), StatusCode: resp.StatusCode, headers: resp.headers.Clone()}, nil
+	}
+}
+
+// coalesceHeaders returns the headers which identify the caller, so that the requests of the different users are not
+// coalesced. The correlation and the trace ids are excluded, as they are different for every request.
+func (h *httpService) coalesceHeaders(ctx context.Context, headers map[string]string) map[string]string {
+	identity := make(map[string]string, len(headers)+len(h.headerKeys)+1)
+
+	for k, v := range headers {
+		identity[k] = v
+	}
+
+	if val, ok := ctx.Value(middleware.AuthenticatedUserIDKey).(string); ok {
+		identity["X-Authenticated-UserId"] = val
+	}
+
+	for _, k := range h.headerKeys {
+		if val, ok := ctx.Value(k).(string); ok {
+			identity[k] = val
+		}
+	}
+
+	return identity
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
+)
+
+func TestHTTPService_Coalesce(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		// the response is delayed, so that the concurrent requests are in flight together
+		time.Sleep(100 * time.Millisecond)
+
+		_, _ = w.Write([]byte(`{"user":"` + r.Header.Get("X-Authenticated-UserId") + `"}`))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		desc     string
+		coalesce bool
+		users    []string
+		calls    int32
+	}{
+		{"coalesced", true, []string{"1", "1", "1", "1"}, 1},
+		{"different users", true, []string{"1", "2", "1", "2"}, 2},
+		{"not coalesced", false, []string{"1", "1", "1", "1"}, 4},
+	}
+
+	for i, tc := range tests {
+		atomic.StoreInt32(&calls, 0)
+
+		svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+			SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+			Coalesce:             tc.coalesce,
+		})
+
+		var wg sync.WaitGroup
+
+		for _, user := range tc.users {
+			wg.Add(1)
+
+			go func(user string) {
+				defer wg.Done()
+
+				ctx := context.WithValue(context.Background(), middleware.AuthenticatedUserIDKey, user)
+				ctx = context.WithValue(ctx, middleware.CorrelationIDKey, time.Now().String())
+
+				resp, err := svc.Get(ctx, "profile", map[string]interface{}{"fields": "name"})
+
+				assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+				assert.Equal(t, `{"user":"`+user+`"}`, string(resp.Body), "TEST[%d], failed.\n%s", i, tc.desc)
+			}(user)
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, tc.calls, atomic.LoadInt32(&calls), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestHTTPService_CoalesceCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer ts.Close()
+
+	svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+		SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+		Coalesce:             true,
+	})
+
+	done := make(chan error)
+
+	// the request which started the call is canceled, the other request still gets the response
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		_, err := svc.Get(ctx, "orders", nil)
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	go func() {
+		resp, err := svc.Get(context.Background(), "orders", nil)
+		if err == nil && resp.StatusCode != http.StatusOK {
+			err = io.ErrUnexpectedEOF
+		}
+
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	assert.Equal(t, RequestCanceled{}, <-done)
+	assert.NoError(t, <-done)
+}
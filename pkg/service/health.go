@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/gofr/types"
+)
+
+const defaultProbeTimeout = 2 * time.Second
+
+// HealthOption configures how the health of the service is checked, when the service is included in the health
+// checks of the application using AddServices.
+type HealthOption struct {
+	// Name of the service in the health checks. Default is the address of the service.
+	Name string
+	// Optional services make the application DEGRADED, instead of not ready, when they are down.
+	Optional bool
+	// Probe is the path of the endpoint which is called for every health check, the service is up when it responds
+	// with a 2xx. Default is the heartbeat URL of the surge protector, which is also considered up on a 404, for the
+	// services which do not have a heartbeat endpoint.
+	Probe string
+	// Timeout of the probe. Default is 2 seconds.
+	Timeout time.Duration
+}
+
+// IsCritical reports whether the application is not ready when the service is down.
+func (h *httpService) IsCritical() bool {
+	return h.health == nil || !h.health.Optional
+}
+
+// probe calls the probe endpoint of the service, with the same headers and authentication as the other requests.
+func (h *httpService) probe() types.Health {
+	health := types.Health{Name: h.url, Host: h.url, Status: pkg.StatusUp}
+	if h.health.Name != "" {
+		health.Name = h.health.Name
+	}
+
+	timeout := h.health.Timeout
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	probe, isHeartbeat := h.health.Probe, false
+	if probe == "" {
+		probe, isHeartbeat = h.sp.customHeartbeatURL, true
+	}
+
+	req, err := h.createReq(ctx, http.MethodGet, strings.TrimLeft(probe, "/"), nil, nil, nil)
+	if err != nil {
+		health.Status, health.Details = pkg.StatusDown, err.Error()
+		return health
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		health.Status, health.Details = pkg.StatusDown, err.Error()
+		return health
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		if !isHeartbeat || resp.StatusCode != http.StatusNotFound {
+			health.Status, health.Details = pkg.StatusDown, fmt.Sprintf("status code %v", resp.StatusCode)
+		}
+	}
+
+	return health
+}
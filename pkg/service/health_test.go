@@ -0,0 +1,69 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg"
+	"gofr.dev/pkg/gofr/types"
+	"gofr.dev/pkg/log"
+)
+
+func TestHTTPService_HealthProbe(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		case "/degraded":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		desc     string
+		option   *HealthOption
+		critical bool
+		expected types.Health
+	}{
+		{"probe", &HealthOption{Name: "orders", Probe: "/health"}, true,
+			types.Health{Name: "orders", Host: ts.URL, Status: pkg.StatusUp}},
+		{"probe fails", &HealthOption{Probe: "/degraded", Optional: true}, false,
+			types.Health{Name: ts.URL, Host: ts.URL, Status: pkg.StatusDown, Details: "status code 503"}},
+		{"probe not found", &HealthOption{Probe: "/missing"}, true,
+			types.Health{Name: ts.URL, Host: ts.URL, Status: pkg.StatusDown, Details: "status code 404"}},
+		// the services without a heartbeat endpoint are considered up, as by the surge protector
+		{"heartbeat not found", &HealthOption{}, true, types.Health{Name: ts.URL, Host: ts.URL, Status: pkg.StatusUp}},
+	}
+
+	for i, tc := range tests {
+		svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+			Auth:                 &Auth{UserName: "user", Password: "pass"},
+			SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+			Health:               tc.option,
+		})
+
+		assert.Equal(t, tc.expected, svc.HealthCheck(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.critical, svc.IsCritical(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestHTTPService_HealthProbeUnreachable(t *testing.T) {
+	svc := NewHTTPServiceWithOptions("http://localhost:1", log.NewMockLogger(io.Discard), &Options{
+		SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+		Health:               &HealthOption{Name: "orders"},
+	})
+
+	h := svc.HealthCheck()
+
+	assert.Equal(t, pkg.StatusDown, h.Status)
+	assert.NotEmpty(t, h.Details)
+}
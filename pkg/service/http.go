@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
@@ -45,6 +46,15 @@ type httpService struct {
 
 	authOptions
 
+	serviceKey *middleware.ServiceKey
+	sigV4      *sigV4Signer
+	stream     StreamOption
+	health     *HealthOption
+	coalescer  *singleflight.Group
+
+	requestTransformers  []RequestTransformer
+	responseTransformers []ResponseTransformer
+
 	// CustomRetry enables the custom retry logic to make service calls
 	// arguments: logger, error, response status-code, attempt count
 	// returns whether framework should retry service call or not
@@ -94,11 +104,19 @@ func (h *httpService) call(ctx context.Context, method, target string, params ma
 			h.logError(&errorLog{CorrelationID: correlationID, Method: method, URI: h.url + "/" + target, Params: params,
 				Message: err.Error(), AppData: appData}, headers, start, authorizationHeader)
 
+			if _, ok := err.(ErrServiceDown); ok {
+				h.countCall(method, outcomeCircuitOpen)
+			} else {
+				h.countCall(method, outcomeError)
+			}
+
 			return nil, err
 		}
 
 		req, err := h.createReq(ctx, method, target, params, body, headers)
 		if err != nil {
+			h.countCall(method, outcomeError)
+
 			return nil, err
 		}
 
@@ -116,14 +134,21 @@ func (h *httpService) call(ctx context.Context, method, target string, params ma
 
 		var resp *http.Response
 
+		// exhausted remains true when every attempt of the call asks for a retry
+		exhausted := true
+
 		for i := 0; i <= h.numOfRetries; i++ {
 			req.Body = io.NopCloser(bytes.NewReader(body)) // reset Request.Body
 
+			attemptStart := time.Now()
+
 			resp, err = h.Do(req) //nolint:bodyclose // body is being closed after call response is logged
 			if resp != nil {
 				statusCode = resp.StatusCode
 			}
 
+			h.observeAttempt(method, i, statusCode, attemptStart)
+
 			if h.CustomRetry != nil {
 				if retry := h.CustomRetry(h.logger, err, statusCode, i+1); retry {
 					continue
@@ -140,11 +165,22 @@ func (h *httpService) call(ctx context.Context, method, target string, params ma
 				}
 			}
 
+			exhausted = false
+
 			break
 		}
 		// add url, method, statusCode and duration in prometheus metric
 		httpServiceResponse.WithLabelValues(h.url, method, fmt.Sprintf("%d", statusCode)).Observe(time.Since(start).Seconds())
 
+		switch {
+		case exhausted && h.numOfRetries > 0:
+			h.countCall(method, outcomeRetryExhausted)
+		case err != nil:
+			h.countCall(method, outcomeError)
+		default:
+			h.countCall(method, outcomeSuccess)
+		}
+
 		if err != nil {
 			return nil, err
 		}
@@ -174,7 +210,11 @@ func (h *httpService) call(ctx context.Context, method, target string, params ma
 			headers:    resp.Header,
 		}
 
-		return &response, err
+		if err := h.transformResponse(&response); err != nil {
+			return nil, err
+		}
+
+		return &response, nil
 	}
 }
 
@@ -274,6 +314,24 @@ func (h *httpService) createReq(ctx context.Context, method, target string, para
 		encodeQueryParameters(req, params)
 	}
 
+	if err := h.transformRequest(req); err != nil {
+		return nil, FailedRequest{URL: h.url, Err: err}
+	}
+
+	// the request is signed at the end, as the signature covers the query parameters
+	if h.serviceKey != nil {
+		if err := h.serviceKey.Sign(req, body, time.Now()); err != nil {
+			return nil, FailedRequest{URL: h.url, Err: err}
+		}
+	}
+
+	// SigV4 signs the headers as well, hence it is the last one
+	if h.sigV4 != nil {
+		if err := h.sigV4.sign(req, body, time.Now()); err != nil {
+			return nil, FailedRequest{URL: h.url, Err: err}
+		}
+	}
+
 	return req, nil
 }
 
@@ -299,6 +357,11 @@ func (h *httpService) setHeadersFromContext(ctx context.Context, req *http.Reque
 		req.Header.Add("X-Authenticated-UserId", authUserID)
 	}
 
+	// the exported values of the context, ex: the tenant, are forwarded in the X-Context headers
+	for k, v := range middleware.ExportHeaders(ctx) {
+		req.Header.Set(k, v)
+	}
+
 	if h.auth != "" {
 		req.Header.Add("Authorization", h.auth)
 	}
@@ -57,7 +57,7 @@ func (c *cachedHTTPService) GetWithHeaders(ctx context.Context, api string, para
 		}, nil
 	}
 
-	resp, err := c.httpService.call(ctx, "GET", api, params, nil, headers)
+	resp, err := c.httpService.get(ctx, api, params, headers)
 	if err != nil {
 		return nil, err
 	}
@@ -153,6 +153,13 @@ func (c cachedHTTPService) getHeaders(ctx context.Context, headers map[string]st
 		headers["X-Authenticated-UserId"] = authUserID
 	}
 
+	// the exported values are a part of the cache key, as the response can differ by them, ex: by the tenant
+	for k, v := range middleware.ExportHeaders(ctx) {
+		if _, ok := headers[k]; !ok {
+			headers[k] = v
+		}
+	}
+
 	if c.auth != "" {
 		headers["Authorization"] = c.auth
 	}
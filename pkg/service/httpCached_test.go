@@ -212,6 +212,18 @@ func Test_GetHeaders_All(t *testing.T) {
 	}
 }
 
+func TestGetHeaders_ExportedContext(t *testing.T) {
+	ctx := middleware.WithExportedValues(context.TODO(), map[string]string{"tenant": "acme", "locale": "de"})
+
+	headers := cachedHTTPService{httpService: &httpService{}}.getHeaders(ctx, map[string]string{"X-Context-Locale": "fr"})
+
+	// the exported values are a part of the cache key, the headers passed take precedence
+	expected := map[string]string{"X-Context-Tenant": "acme", "X-Context-Locale": "fr"}
+	if !reflect.DeepEqual(headers, expected) {
+		t.Errorf("exported headers are not set, got %v, expected %v", headers, expected)
+	}
+}
+
 // check the condition when user passed the keyGeneratorFunc
 func TestCacheGetWithHeadersPassedKey(t *testing.T) {
 	type resp struct {
@@ -3,6 +3,8 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
@@ -469,6 +471,17 @@ func TestHttpService_PropagateHeaders(t *testing.T) {
 	}
 }
 
+func TestHttpService_ExportedContext(t *testing.T) {
+	httpSvc := httpService{Client: &http.Client{}, url: dummyURL}
+
+	ctx := middleware.WithExportedValues(context.TODO(), map[string]string{"tenant": "acme", "locale": "de"})
+
+	req, _ := httpSvc.createReq(ctx, http.MethodGet, "", nil, nil, map[string]string{"X-Context-Locale": "fr"})
+
+	assert.Equal(t, "acme", req.Header.Get("X-Context-Tenant"))
+	assert.Equal(t, "fr", req.Header.Get("X-Context-Locale"), "the headers of the call take precedence")
+}
+
 // TestCallLog tests if Authorization is not logged and AppData is logged
 func TestCallLog(t *testing.T) {
 	ts := testServer()
@@ -897,3 +910,27 @@ func initializeLogTest() (*httpService, *bytes.Buffer, string) {
 
 	return httpSvc, b, logMsg
 }
+
+func TestHTTPService_ServiceKey(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	keys := middleware.NewPeerKeys(middleware.PeerKey{Service: "orders", KeyID: "1", PublicKey: pub})
+
+	var caller string
+
+	ts := httptest.NewServer(middleware.ServiceAuth(log.NewMockLogger(io.Discard), keys, 0)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			caller, _ = r.Context().Value(middleware.ServiceNameKey).(string)
+		})))
+	defer ts.Close()
+
+	svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+		SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+		ServiceKey:           &middleware.ServiceKey{Service: "orders", KeyID: "1", Algorithm: "EdDSA", PrivateKey: priv},
+	})
+
+	resp, err := svc.Post(context.Background(), "payments", map[string]interface{}{"mode": "card"}, []byte(`{"amount":10}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "orders", caller)
+}
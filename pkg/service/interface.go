@@ -40,6 +40,14 @@ type HTTP interface {
 	SetSurgeProtectorOptions(isEnabled bool, customHeartbeatURL string, retryFrequencySeconds int)
 }
 
+// Streamer is an interface for consuming the push-style endpoints, which send the events as they occur.
+type Streamer interface {
+	// Events consumes a Server-Sent Events stream, reconnecting to it until the context is done.
+	Events(ctx context.Context, api string, params map[string]interface{}, handler EventHandler) error
+	// LongPoll polls a long-poll endpoint, until the context is done.
+	LongPoll(ctx context.Context, api string, params map[string]interface{}, handler ResponseHandler) error
+}
+
 // SOAP is an interface for making SOAP requests and handling responses.
 type SOAP interface {
 	Call(ctx context.Context, action string, body []byte) (*Response, error)
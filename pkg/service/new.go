@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 	"gofr.dev/pkg"
 	"gofr.dev/pkg/gofr/types"
 	"gofr.dev/pkg/log"
+	"gofr.dev/pkg/middleware"
 )
 
 const basic = "Basic"
@@ -25,6 +27,21 @@ type Options struct {
 	*Auth
 	*Cache
 	*SurgeProtectorOption
+	// ServiceKey signs the requests, so that the peer services can authenticate them using middleware.ServiceAuth
+	ServiceKey *middleware.ServiceKey
+	// SigV4 signs the requests for the AWS endpoints
+	SigV4 *SigV4Option
+	// Stream configures the reconnection of the Server-Sent Events streams and the long-polls
+	Stream *StreamOption
+	// RequestTransformers mutate the requests in the order of registration, before they are sent
+	RequestTransformers []RequestTransformer
+	// ResponseTransformers post-process the responses in the order of registration, before they are returned
+	ResponseTransformers []ResponseTransformer
+	// Health configures the health check of the service, which probes the service on every check when it is set
+	Health *HealthOption
+	// Coalesce collapses the concurrent identical GET requests into a single request to the service, which protects a
+	// slow service from a stampede of requests, ex: when a cached response expires
+	Coalesce bool
 }
 
 // KeyGenerator provides ability to  the user that can use custom or own logic to Generate the key for HTTPCached
@@ -62,6 +79,17 @@ var (
 		Name: "zs_external_service_circuit_open_count",
 		Help: "Counter to track the number of times circuit opens",
 	}, []string{"host"})
+
+	httpServiceAttempt = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "zs_http_service_attempt",
+		Help:    "Histogram of HTTP response times in seconds of the attempts of a call, by first try or retry, and status",
+		Buckets: []float64{.001, .003, .005, .01, .025, .05, .1, .2, .3, .4, .5, .75, 1, 2, 3, 5, 10, 30},
+	}, []string{"host", "method", "attempt", "status"})
+
+	httpServiceCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_http_service_calls",
+		Help: "Counter of the HTTP service calls by their outcome, after the retries",
+	}, []string{"host", "method", "outcome"})
 )
 
 // NewHTTPServiceWithOptions creates a http client based on the options configured
@@ -76,6 +104,8 @@ func NewHTTPServiceWithOptions(resourceAddr string, logger log.Logger, options *
 	}
 
 	_ = prometheus.Register(httpServiceResponse)
+	_ = prometheus.Register(httpServiceAttempt)
+	_ = prometheus.Register(httpServiceCalls)
 
 	// Transport for http Client
 	transport := otelhttp.NewTransport(http.DefaultTransport)
@@ -111,6 +141,27 @@ func NewHTTPServiceWithOptions(resourceAddr string, logger log.Logger, options *
 
 	httpSvc.initializeClientWithAuth(*options)
 
+	httpSvc.serviceKey = options.ServiceKey
+
+	if options.SigV4 != nil {
+		httpSvc.sigV4 = newSigV4Signer(options.SigV4)
+		if httpSvc.sigV4.err != nil {
+			logger.Errorf("SigV4 credentials could not be loaded for %v: %v", resourceAddr, httpSvc.sigV4.err)
+		}
+	}
+
+	httpSvc.health = options.Health
+
+	if options.Coalesce {
+		httpSvc.coalescer = &singleflight.Group{}
+	}
+	httpSvc.requestTransformers = options.RequestTransformers
+	httpSvc.responseTransformers = options.ResponseTransformers
+
+	if options.Stream != nil {
+		httpSvc.stream = *options.Stream
+	}
+
 	enableSP := true
 
 	// enable surge protection
@@ -179,6 +230,10 @@ func (h *httpService) enableOAuth(options Options) {
 
 // HealthCheck performs a health check and returns the health status based on whether the service is considered healthy or not.
 func (h *httpService) HealthCheck() types.Health {
+	if h.health != nil {
+		return h.probe()
+	}
+
 	h.mu.Lock()
 	isHealthy := h.isHealthy
 	h.mu.Unlock()
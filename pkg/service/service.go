@@ -16,7 +16,7 @@ func (h *httpService) Get(ctx context.Context, api string, params map[string]int
 		return h.cache.Get(ctx, api, params)
 	}
 
-	return h.call(ctx, http.MethodGet, api, params, nil, nil)
+	return h.get(ctx, api, params, nil)
 }
 
 // Post used for making HTTP POST requests to a specified API endpoint with optional query parameters.
@@ -48,7 +48,7 @@ func (h *httpService) GetWithHeaders(ctx context.Context, api string, params map
 		return h.cache.GetWithHeaders(ctx, api, params, headers)
 	}
 
-	return h.call(ctx, "GET", api, params, nil, headers)
+	return h.get(ctx, api, params, headers)
 }
 
 // PostWithHeaders used for making HTTP POST requests to a specified API endpoint with optional query parameters and headers
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+func TestSigV4Signer_Sign(t *testing.T) {
+	signer := newSigV4Signer(&SigV4Option{Region: "us-east-1", Service: "execute-api",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")})
+	now := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	sign := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "https://api.example.com/prod/orders?id=1", strings.NewReader(body))
+
+		assert.NoError(t, signer.sign(req, []byte(body), now))
+
+		return req
+	}
+
+	req := sign(`{"id":1}`)
+
+	assert.True(t, strings.HasPrefix(req.Header.Get("Authorization"),
+		"AWS4-HMAC-SHA256 Credential=AKID/20230501/us-east-1/execute-api/aws4_request, SignedHeaders="))
+	assert.Equal(t, "20230501T100000Z", req.Header.Get("X-Amz-Date"))
+	sum := sha256.Sum256([]byte(`{"id":1}`))
+
+	assert.Equal(t, hex.EncodeToString(sum[:]), req.Header.Get("X-Amz-Content-Sha256"))
+	assert.Equal(t, req.Header.Get("Authorization"), sign(`{"id":1}`).Header.Get("Authorization"), "signature is not deterministic")
+	assert.NotEqual(t, req.Header.Get("Authorization"), sign(`{"id":2}`).Header.Get("Authorization"), "body is not signed")
+}
+
+func TestHTTPService_SigV4(t *testing.T) {
+	var authorization string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer ts.Close()
+
+	svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+		SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+		Auth:                 &Auth{UserName: "user", Password: "pass"},
+		SigV4: &SigV4Option{Region: "eu-west-1", Service: "es",
+			Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")},
+	})
+
+	_, err := svc.Get(context.Background(), "_search", nil)
+
+	assert.NoError(t, err)
+	assert.Contains(t, authorization, "/eu-west-1/es/aws4_request", "SigV4 does not replace the basic auth")
+
+	svc.sigV4 = newSigV4Signer(&SigV4Option{Credentials: aws.CredentialsProviderFunc(
+		func(context.Context) (aws.Credentials, error) { return aws.Credentials{}, errors.New("no credentials") })})
+
+	_, err = svc.Get(context.Background(), "_search", nil)
+
+	assert.Error(t, err, "unsigned request is sent")
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultReconnectDelay    = 3 * time.Second
+	defaultMaxReconnectDelay = time.Minute
+	defaultPollTimeout       = time.Minute
+)
+
+// errNoContent is returned when the server responds to an event stream with 204 No Content, which tells the
+// client not to reconnect.
+var errNoContent = errors.New("no content")
+
+// StreamOption configures the consumption of the Server-Sent Events and long-poll endpoints.
+type StreamOption struct {
+	// ReconnectDelay is the delay before reconnecting, it is doubled on every failed attempt up to MaxReconnectDelay.
+	// A retry sent by the server replaces it. Default is 3 seconds.
+	ReconnectDelay time.Duration
+	// MaxReconnectDelay is the maximum delay between the attempts. Default is 1 minute.
+	MaxReconnectDelay time.Duration
+	// PollTimeout is the time for which a long-poll request waits for a response. Default is 1 minute.
+	PollTimeout time.Duration
+}
+
+// Event is a server-sent event.
+type Event struct {
+	ID string
+	// Type is the event field of the event, it is message when the event does not have one.
+	Type string
+	Data []byte
+}
+
+// EventHandler handles an event, the stream is closed when it returns an error.
+type EventHandler func(ctx context.Context, event Event) error
+
+// ResponseHandler handles a response of a long-poll endpoint, the polling stops when it returns an error.
+type ResponseHandler func(ctx context.Context, resp *Response) error
+
+// handlerError distinguishes the errors of the handlers, which stop the stream, from the errors of the connection.
+type handlerError struct {
+	err error
+}
+
+func (e handlerError) Error() string {
+	return e.err.Error()
+}
+
+// streamClient returns a client without a timeout, as the streams are open until the context is done.
+func (h *httpService) streamClient() *http.Client {
+	return &http.Client{Transport: h.Client.Transport, CheckRedirect: h.Client.CheckRedirect, Jar: h.Client.Jar}
+}
+
+func (h *httpService) streamOption() StreamOption {
+	o := h.stream
+
+	if o.ReconnectDelay <= 0 {
+		o.ReconnectDelay = defaultReconnectDelay
+	}
+
+	if o.MaxReconnectDelay <= 0 {
+		o.MaxReconnectDelay = defaultMaxReconnectDelay
+	}
+
+	if o.PollTimeout <= 0 {
+		o.PollTimeout = defaultPollTimeout
+	}
+
+	return o
+}
+
+// Events consumes the Server-Sent Events of the api, and calls the handler for every event. The stream is reconnected
+// when it is closed or fails, resuming from the last event using the Last-Event-ID header. It returns nil when the
+// context is done or the server responds with 204 No Content, and the error of the handler when the handler fails.
+func (h *httpService) Events(ctx context.Context, api string, params map[string]interface{}, handler EventHandler) error {
+	opt := h.streamOption()
+	client := h.streamClient()
+	s := &eventStream{lastID: "", delay: opt.ReconnectDelay}
+	backoff := s.delay
+
+	for {
+		received, err := s.consume(ctx, h, client, api, params, handler)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err == errNoContent:
+			return nil
+		}
+
+		if e, ok := err.(handlerError); ok {
+			return e.err
+		}
+
+		// the backoff is reset once the stream delivers events, so that a stream closed after a while is reconnected quickly
+		if received {
+			backoff = s.delay
+		}
+
+		if err != nil {
+			h.logger.Warnf("event stream of %v/%v failed, reconnecting in %v: %v", h.url, api, backoff, err)
+		}
+
+		if !sleep(ctx, backoff) {
+			return nil
+		}
+
+		backoff = min(backoff*2, opt.MaxReconnectDelay)
+	}
+}
+
+type eventStream struct {
+	lastID string
+	delay  time.Duration
+}
+
+// consume reads the stream until it ends, and reports whether any event was received.
+func (s *eventStream) consume(ctx context.Context, h *httpService, client *http.Client, api string,
+	params map[string]interface{}, handler EventHandler) (bool, error) {
+	headers := map[string]string{"Accept": "text/event-stream", "Cache-Control": "no-cache"}
+	if s.lastID != "" {
+		headers["Last-Event-ID"] = s.lastID
+	}
+
+	req, err := h.createReq(ctx, http.MethodGet, strings.TrimLeft(api, "/"), params, nil, headers)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		return false, errNoContent
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("unexpected status code %v", resp.StatusCode)
+	case !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"):
+		return false, fmt.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	return s.read(ctx, bufio.NewReader(resp.Body), handler)
+}
+
+// read parses the events as per the Server-Sent Events specification, and dispatches them to the handler.
+//
+//nolint:gocognit,gocyclo // the fields of the event stream are parsed in a single loop
+func (s *eventStream) read(ctx context.Context, r *bufio.Reader, handler EventHandler) (bool, error) {
+	var (
+		event    = Event{}
+		data     bytes.Buffer
+		received bool
+	)
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			// an incomplete event at the end of the stream is discarded
+			if err == io.EOF {
+				err = nil
+			}
+
+			return received, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if data.Len() > 0 {
+				event.ID = s.lastID
+				event.Data = bytes.TrimSuffix(data.Bytes(), []byte("\n"))
+
+				if event.Type == "" {
+					event.Type = "message"
+				}
+
+				if err := handler(ctx, event); err != nil {
+					return received, handlerError{err: err}
+				}
+
+				received = true
+			}
+
+			event, data = Event{}, bytes.Buffer{}
+
+			continue
+		}
+
+		// the lines starting with a colon are comments, which are sent to keep the connection alive
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event.Type = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				s.lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				s.delay = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// LongPoll calls GET on the api repeatedly, and calls the handler for every response with content. The api is
+// polled again right away after a response, or when the request times out after PollTimeout without a response,
+// and after a delay when the request fails. It returns nil when the context is done, and the error of the handler,
+// or of a response transformer, when it fails.
+//
+//nolint:gocyclo // every outcome of a poll decides whether, and when, to poll again
+func (h *httpService) LongPoll(ctx context.Context, api string, params map[string]interface{}, handler ResponseHandler) error {
+	opt := h.streamOption()
+	client := h.streamClient()
+	backoff := opt.ReconnectDelay
+
+	for ctx.Err() == nil {
+		resp, err := h.poll(ctx, client, api, params, opt.PollTimeout)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err == nil:
+			backoff = opt.ReconnectDelay
+
+			if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified {
+				continue
+			}
+
+			if err := handler(ctx, resp); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if e, ok := err.(handlerError); ok {
+			return e.err
+		}
+
+		if e, ok := err.(net.Error); ok && e.Timeout() {
+			continue
+		}
+
+		h.logger.Warnf("long-poll of %v/%v failed, retrying in %v: %v", h.url, api, backoff, err)
+
+		if !sleep(ctx, backoff) {
+			return nil
+		}
+
+		backoff = min(backoff*2, opt.MaxReconnectDelay)
+	}
+
+	return nil
+}
+
+func (h *httpService) poll(ctx context.Context, client *http.Client, api string, params map[string]interface{},
+	timeout time.Duration) (*Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := h.createReq(ctx, http.MethodGet, strings.TrimLeft(api, "/"), params, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("unexpected status code %v", resp.StatusCode)
+	}
+
+	response := &Response{Body: body, StatusCode: resp.StatusCode, headers: resp.Header}
+
+	if err := h.transformResponse(response); err != nil {
+		return nil, handlerError{err: err}
+	}
+
+	return response, nil
+}
+
+// sleep waits for the duration, it returns false when the context is done before it.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
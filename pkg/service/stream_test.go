@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+var errStop = errors.New("stop")
+
+func TestEventStream_Read(t *testing.T) {
+	tests := []struct {
+		desc   string
+		stream string
+		events []Event
+		lastID string
+		delay  time.Duration
+	}{
+		{"single event", "data: hello\n\n", []Event{{Type: "message", Data: []byte("hello")}}, "", time.Second},
+		{"multi-line data", "data: a\ndata: b\n\n", []Event{{Type: "message", Data: []byte("a\nb")}}, "", time.Second},
+		{"event type and id", "event: order\nid: 7\ndata: {}\r\n\r\n",
+			[]Event{{ID: "7", Type: "order", Data: []byte("{}")}}, "7", time.Second},
+		{"id is retained", "id: 1\ndata: a\n\ndata: b\n\n",
+			[]Event{{ID: "1", Type: "message", Data: []byte("a")}, {ID: "1", Type: "message", Data: []byte("b")}}, "1", time.Second},
+		{"comments and unknown fields", ": ping\nfoo: bar\ndata:x\n\n", []Event{{Type: "message", Data: []byte("x")}}, "", time.Second},
+		{"event without data", "event: ping\n\n", nil, "", time.Second},
+		{"retry", "retry: 250\n\n", nil, "", 250 * time.Millisecond},
+		{"invalid retry", "retry: soon\n\n", nil, "", time.Second},
+		{"incomplete event", "data: a\n\ndata: b\n", []Event{{Type: "message", Data: []byte("a")}}, "", time.Second},
+	}
+
+	for i, tc := range tests {
+		s := &eventStream{delay: time.Second}
+
+		var events []Event
+
+		received, err := s.read(context.Background(), bufio.NewReader(strings.NewReader(tc.stream)), func(_ context.Context, e Event) error {
+			events = append(events, e)
+			return nil
+		})
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.events, events, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, len(tc.events) > 0, received, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.lastID, s.lastID, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.delay, s.delay, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestHTTPService_Events(t *testing.T) {
+	var lastIDs []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastIDs = append(lastIDs, r.Header.Get("Last-Event-ID"))
+
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+		assert.Equal(t, "/orders/events", r.URL.Path)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		// the stream is closed after every event, so that the client reconnects
+		fmt.Fprintf(w, "retry: 10\nid: %d\ndata: event %d\n\n", len(lastIDs), len(lastIDs))
+	}))
+	defer ts.Close()
+
+	svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+		SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+	})
+
+	var data []string
+
+	err := svc.Events(context.Background(), "orders/events", nil, func(_ context.Context, e Event) error {
+		data = append(data, string(e.Data))
+
+		if len(data) == 3 {
+			return errStop
+		}
+
+		return nil
+	})
+
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, []string{"event 1", "event 2", "event 3"}, data)
+	assert.Equal(t, []string{"", "1", "2"}, lastIDs)
+}
+
+func TestHTTPService_EventsStop(t *testing.T) {
+	tests := []struct {
+		desc    string
+		status  int
+		timeout time.Duration
+	}{
+		{"no content", http.StatusNoContent, time.Minute},
+		{"context done while reconnecting", http.StatusServiceUnavailable, 50 * time.Millisecond},
+	}
+
+	for i, tc := range tests {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(tc.status)
+		}))
+
+		svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+			SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+			Stream:               &StreamOption{ReconnectDelay: time.Millisecond, MaxReconnectDelay: 5 * time.Millisecond},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+
+		err := svc.Events(ctx, "events", nil, func(context.Context, Event) error {
+			return errStop
+		})
+
+		cancel()
+		ts.Close()
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.status == http.StatusNoContent {
+			assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "TEST[%d], failed.\n%s", i, tc.desc)
+		} else {
+			assert.Greater(t, atomic.LoadInt32(&calls), int32(1), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestHTTPService_LongPoll(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusNoContent)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		case 3:
+			// the request outlives the poll timeout, and is polled again
+			time.Sleep(100 * time.Millisecond)
+		default:
+			_, _ = w.Write([]byte(`{"cursor":` + r.URL.Query().Get("cursor") + `}`))
+		}
+	}))
+	defer ts.Close()
+
+	svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+		SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+		Stream:               &StreamOption{ReconnectDelay: time.Millisecond, PollTimeout: 50 * time.Millisecond},
+	})
+
+	var bodies []string
+
+	err := svc.LongPoll(context.Background(), "updates", map[string]interface{}{"cursor": 5}, func(_ context.Context, resp *Response) error {
+		bodies = append(bodies, string(resp.Body))
+		return errStop
+	})
+
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, []string{`{"cursor":5}`}, bodies)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPService_LongPollContextDone(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer ts.Close()
+
+	svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+		SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := svc.LongPoll(ctx, "updates", nil, func(context.Context, *Response) error {
+		return errStop
+	})
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"gofr.dev/pkg/errors"
+)
+
+// RequestTransformer mutates a request before it is sent, ex: to add the headers required by a vendor.
+// The request is signed after the transformers, if signing is configured, hence the transformers must not
+// change the body of the request.
+type RequestTransformer func(req *http.Request) error
+
+// ResponseTransformer post-processes a response before it is returned to the caller, ex: to unwrap the envelope of
+// a vendor. The call fails with the error returned by it, which allows the vendor error formats to be mapped to the
+// gofr errors.
+type ResponseTransformer func(resp *Response) error
+
+func (h *httpService) transformRequest(req *http.Request) error {
+	for _, t := range h.requestTransformers {
+		if err := t(req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *httpService) transformResponse(resp *Response) error {
+	for _, t := range h.responseTransformers {
+		if err := t(resp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnwrapEnvelope replaces the body of the successful JSON responses with the value of the field, for the vendors
+// which wrap their responses in an envelope, ex: {"data": {...}, "meta": {...}}. The nested fields are separated by
+// dots, ex: "result.data". The responses without the field are not changed.
+func UnwrapEnvelope(field string) ResponseTransformer {
+	path := strings.Split(field, ".")
+
+	return func(resp *Response) error {
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			return nil
+		}
+
+		if value, ok := lookupJSON(resp.Body, path); ok {
+			resp.Body = value
+		}
+
+		return nil
+	}
+}
+
+// MapErrors converts the error responses, ie: the responses with status code 4xx or 5xx, to an *errors.Response,
+// with the code and the reason read from the fields of the JSON body, ex: MapErrors("error.type", "error.message").
+// The status code of the response is retained, and the body is set as the detail when the fields are not present.
+func MapErrors(codeField, reasonField string) ResponseTransformer {
+	codePath, reasonPath := strings.Split(codeField, "."), strings.Split(reasonField, ".")
+
+	return func(resp *Response) error {
+		if resp.StatusCode < http.StatusBadRequest {
+			return nil
+		}
+
+		e := &errors.Response{StatusCode: resp.StatusCode, Code: http.StatusText(resp.StatusCode), Reason: string(resp.Body)}
+
+		if code, ok := lookupJSON(resp.Body, codePath); ok {
+			e.Code = jsonString(code)
+		}
+
+		if reason, ok := lookupJSON(resp.Body, reasonPath); ok {
+			e.Reason = jsonString(reason)
+		}
+
+		return e
+	}
+}
+
+// lookupJSON returns the raw value of the field at the path in the JSON object.
+func lookupJSON(data []byte, path []string) ([]byte, bool) {
+	for _, key := range path {
+		var object map[string]json.RawMessage
+
+		if err := json.Unmarshal(data, &object); err != nil {
+			return nil, false
+		}
+
+		value, ok := object[key]
+		if !ok {
+			return nil, false
+		}
+
+		data = value
+	}
+
+	return data, true
+}
+
+// jsonString returns the value of a JSON string, and the raw value of the other JSON types, ex: the numeric codes.
+func jsonString(raw []byte) string {
+	var s string
+
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	return string(raw)
+}
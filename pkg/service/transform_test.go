@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/errors"
+	"gofr.dev/pkg/log"
+)
+
+func TestUnwrapEnvelope(t *testing.T) {
+	tests := []struct {
+		desc   string
+		field  string
+		status int
+		body   string
+		expRes string
+	}{
+		{"field", "data", http.StatusOK, `{"data":{"id":1},"meta":{}}`, `{"id":1}`},
+		{"nested field", "result.items", http.StatusOK, `{"result":{"items":[1,2]}}`, `[1,2]`},
+		{"missing field", "data", http.StatusOK, `{"items":[]}`, `{"items":[]}`},
+		{"not an object", "data", http.StatusOK, `[1]`, `[1]`},
+		{"error response", "data", http.StatusBadRequest, `{"data":"x"}`, `{"data":"x"}`},
+	}
+
+	for i, tc := range tests {
+		resp := &Response{StatusCode: tc.status, Body: []byte(tc.body)}
+
+		assert.NoError(t, UnwrapEnvelope(tc.field)(resp), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.expRes, string(resp.Body), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	tests := []struct {
+		desc   string
+		status int
+		body   string
+		expErr error
+	}{
+		{"success", http.StatusOK, `{"error":{"type":"x"}}`, nil},
+		{"vendor error", http.StatusNotFound, `{"error":{"type":"not_found","message":"order not found"}}`,
+			&errors.Response{StatusCode: http.StatusNotFound, Code: "not_found", Reason: "order not found"}},
+		{"numeric code", http.StatusConflict, `{"error":{"type":409,"message":"exists"}}`,
+			&errors.Response{StatusCode: http.StatusConflict, Code: "409", Reason: "exists"}},
+		{"unknown format", http.StatusBadGateway, `upstream failed`,
+			&errors.Response{StatusCode: http.StatusBadGateway, Code: "Bad Gateway", Reason: "upstream failed"}},
+	}
+
+	for i, tc := range tests {
+		err := MapErrors("error.type", "error.message")(&Response{StatusCode: tc.status, Body: []byte(tc.body)})
+
+		assert.Equal(t, tc.expErr, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestHTTPService_Transformers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vendor-Version") != "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"fault":{"code":"VERSION","text":"unsupported version"}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"payload":{"id":1}}`))
+	}))
+	defer ts.Close()
+
+	newService := func(version string) *httpService {
+		return NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+			SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+			RequestTransformers: []RequestTransformer{func(req *http.Request) error {
+				req.Header.Set("X-Vendor-Version", version)
+				return nil
+			}},
+			ResponseTransformers: []ResponseTransformer{MapErrors("fault.code", "fault.text"), UnwrapEnvelope("payload")},
+		})
+	}
+
+	resp, err := newService("2").Get(context.Background(), "orders/1", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1}`, string(resp.Body))
+
+	resp, err = newService("1").Get(context.Background(), "orders/1", nil)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, &errors.Response{StatusCode: http.StatusBadRequest, Code: "VERSION", Reason: "unsupported version"}, err)
+}
+
+func TestHTTPService_RequestTransformerError(t *testing.T) {
+	svc := NewHTTPServiceWithOptions("http://localhost", log.NewMockLogger(io.Discard), &Options{
+		SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+		RequestTransformers: []RequestTransformer{func(*http.Request) error {
+			return errors.Error("no tenant")
+		}},
+	})
+
+	_, err := svc.Get(context.Background(), "orders", nil)
+
+	assert.Equal(t, FailedRequest{URL: "http://localhost", Err: errors.Error("no tenant")}, err)
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gofr.dev/pkg/errors"
+)
+
+const (
+	defaultPingInterval     = 30 * time.Second
+	defaultPongTimeout      = 10 * time.Second
+	defaultHandshakeTimeout = 10 * time.Second
+
+	// ErrNotConnected is returned when a message is sent while the WebSocket is not connected.
+	ErrNotConnected = errors.Error("websocket is not connected")
+)
+
+//nolint:gochecknoglobals // the metrics are shared by all the WebSocket clients, and are labelled by the host
+var (
+	webSocketMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_websocket_service_messages_total",
+		Help: "Counter of the messages sent and received by the WebSocket clients",
+	}, []string{"host", "direction"})
+
+	webSocketReconnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zs_websocket_service_reconnects_total",
+		Help: "Counter of the reconnections of the WebSocket clients",
+	}, []string{"host"})
+
+	webSocketConnected = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "zs_websocket_service_connected",
+		Help: "Number of the connected WebSocket clients",
+	}, []string{"host"})
+)
+
+// WebSocketOption configures a WebSocket client.
+type WebSocketOption struct {
+	// Params are the query parameters of the connection URL.
+	Params map[string]interface{}
+	// PingInterval is the interval at which the pings are sent. Default is 30 seconds.
+	PingInterval time.Duration
+	// PongTimeout is the time within which a pong, or any other message, must be received after a ping,
+	// else the connection is considered dead and is reconnected. Default is 10 seconds.
+	PongTimeout time.Duration
+	// ReconnectDelay is the delay before reconnecting, it is doubled on every failed attempt up to MaxReconnectDelay.
+	// Default is 3 seconds.
+	ReconnectDelay time.Duration
+	// MaxReconnectDelay is the maximum delay between the attempts. Default is 1 minute.
+	MaxReconnectDelay time.Duration
+}
+
+// MessageHandler handles a message received on a WebSocket, the client is stopped when it returns an error.
+// The messageType is websocket.TextMessage or websocket.BinaryMessage.
+type MessageHandler func(ctx context.Context, messageType int, data []byte) error
+
+// WebSocket is a client of an upstream WebSocket, which reconnects whenever the connection is lost. It is created
+// from an HTTP service, and connects to its host with the same headers and authentication as its requests.
+type WebSocket struct {
+	svc     *httpService
+	api     string
+	opt     WebSocketOption
+	handler MessageHandler
+	// onConnect is called after every connection, ex: to subscribe to the channels of the feed.
+	onConnect func(ctx context.Context, ws *WebSocket) error
+
+	// mu serialises the writes, as a connection supports only one concurrent writer.
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+// WebSocket returns a client of the WebSocket at the api of the service. The ws or wss scheme is derived from the
+// scheme of the service. The client connects when Run is called.
+func (h *httpService) WebSocket(api string, option *WebSocketOption) *WebSocket {
+	_ = prometheus.Register(webSocketMessages)
+	_ = prometheus.Register(webSocketReconnects)
+	_ = prometheus.Register(webSocketConnected)
+
+	ws := &WebSocket{svc: h, api: strings.TrimLeft(api, "/")}
+
+	if option != nil {
+		ws.opt = *option
+	}
+
+	if ws.opt.PingInterval <= 0 {
+		ws.opt.PingInterval = defaultPingInterval
+	}
+
+	if ws.opt.PongTimeout <= 0 {
+		ws.opt.PongTimeout = defaultPongTimeout
+	}
+
+	if ws.opt.ReconnectDelay <= 0 {
+		ws.opt.ReconnectDelay = defaultReconnectDelay
+	}
+
+	if ws.opt.MaxReconnectDelay <= 0 {
+		ws.opt.MaxReconnectDelay = defaultMaxReconnectDelay
+	}
+
+	return ws
+}
+
+// Handle registers the handler of the received messages.
+func (ws *WebSocket) Handle(handler MessageHandler) {
+	ws.handler = handler
+}
+
+// OnConnect registers a function which is called after every connection, before the messages are handled.
+// The connection is retried when it returns an error.
+func (ws *WebSocket) OnConnect(f func(ctx context.Context, ws *WebSocket) error) {
+	ws.onConnect = f
+}
+
+// Send sends a text message.
+func (ws *WebSocket) Send(data []byte) error {
+	return ws.write(websocket.TextMessage, data)
+}
+
+// SendJSON sends v encoded as JSON in a text message.
+func (ws *WebSocket) SendJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return ws.Send(data)
+}
+
+func (ws *WebSocket) write(messageType int, data []byte) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.conn == nil {
+		return ErrNotConnected
+	}
+
+	if err := ws.conn.WriteMessage(messageType, data); err != nil {
+		return err
+	}
+
+	if messageType == websocket.TextMessage || messageType == websocket.BinaryMessage {
+		webSocketMessages.WithLabelValues(ws.svc.url, "sent").Inc()
+	}
+
+	return nil
+}
+
+// Run connects to the WebSocket, and handles the messages until the context is done. The connection is
+// re-established whenever it is lost. It returns nil when the context is done, and the error of the handler
+// when the handler fails.
+func (ws *WebSocket) Run(ctx context.Context) error {
+	backoff := ws.opt.ReconnectDelay
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			webSocketReconnects.WithLabelValues(ws.svc.url).Inc()
+		}
+
+		connected, err := ws.run(ctx)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if e, ok := err.(handlerError); ok {
+			return e.err
+		}
+
+		if connected {
+			backoff = ws.opt.ReconnectDelay
+		}
+
+		ws.svc.logger.Warnf("websocket %v/%v disconnected, reconnecting in %v: %v", ws.svc.url, ws.api, backoff, err)
+
+		if !sleep(ctx, backoff) {
+			return nil
+		}
+
+		backoff = min(backoff*2, ws.opt.MaxReconnectDelay)
+	}
+}
+
+// run handles a single connection, it reports whether the connection was established.
+func (ws *WebSocket) run(ctx context.Context) (bool, error) {
+	conn, err := ws.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	ws.mu.Lock()
+	ws.conn = conn
+	ws.mu.Unlock()
+
+	webSocketConnected.WithLabelValues(ws.svc.url).Inc()
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	// the connection is closed when the context is done, which also interrupts the blocked read
+	go func() {
+		<-ctx.Done()
+
+		ws.mu.Lock()
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(time.Second))
+		_ = conn.Close()
+		ws.mu.Unlock()
+	}()
+
+	defer func() {
+		ws.mu.Lock()
+		ws.conn = nil
+		ws.mu.Unlock()
+
+		cancel()
+		webSocketConnected.WithLabelValues(ws.svc.url).Dec()
+	}()
+
+	if ws.onConnect != nil {
+		if err := ws.onConnect(ctx, ws); err != nil {
+			return true, err
+		}
+	}
+
+	go ws.keepAlive(ctx, conn)
+
+	return true, ws.read(ctx, conn)
+}
+
+func (ws *WebSocket) dial(ctx context.Context) (*websocket.Conn, error) {
+	// the request is created as for the HTTP calls, so that it has the same headers, authentication and signature
+	req, err := ws.svc.createReq(ctx, http.MethodGet, ws.api, ws.opt.Params, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	u := *req.URL
+
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+
+	// the handshake headers are set by the dialer, which rejects the requests that already have them
+	header := req.Header.Clone()
+	for _, k := range []string{"Content-Type", "Accept", "Upgrade", "Connection", "Sec-Websocket-Key",
+		"Sec-Websocket-Version", "Sec-Websocket-Extensions", "Sec-Websocket-Protocol"} {
+		header.Del(k)
+	}
+
+	dialer := websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: defaultHandshakeTimeout}
+
+	conn, resp, err := dialer.DialContext(ctx, u.String(), header)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+
+	return conn, err
+}
+
+func (ws *WebSocket) read(ctx context.Context, conn *websocket.Conn) error {
+	deadline := ws.opt.PingInterval + ws.opt.PongTimeout
+
+	_ = conn.SetReadDeadline(time.Now().Add(deadline))
+
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(deadline))
+	})
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		_ = conn.SetReadDeadline(time.Now().Add(deadline))
+
+		webSocketMessages.WithLabelValues(ws.svc.url, "received").Inc()
+
+		if ws.handler == nil {
+			continue
+		}
+
+		if err := ws.handler(ctx, messageType, data); err != nil {
+			return handlerError{err: err}
+		}
+	}
+}
+
+// keepAlive sends the pings until the context is done.
+func (ws *WebSocket) keepAlive(ctx context.Context, conn *websocket.Conn) {
+	ticker := time.NewTicker(ws.opt.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ws.mu.Lock()
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(ws.opt.PongTimeout))
+			ws.mu.Unlock()
+
+			if err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"gofr.dev/pkg/log"
+)
+
+// echoFeed upgrades the connections, echoes the first message of every connection with the connection number and
+// the header X-Api-Key, and closes the connection.
+func echoFeed(t *testing.T, connections *int32) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+
+		defer conn.Close()
+
+		n := atomic.AddInt32(connections, 1)
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		reply := string(data) + " " + r.Header.Get("X-Api-Key") + " " + r.URL.Query().Get("channel") + " " + string('0'+byte(n))
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(reply))
+	}))
+}
+
+func TestWebSocket_Run(t *testing.T) {
+	var connections int32
+
+	ts := echoFeed(t, &connections)
+	defer ts.Close()
+
+	svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+		Headers:              map[string]string{"X-Api-Key": "key"},
+		SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+	})
+
+	ws := svc.WebSocket("/feed", &WebSocketOption{Params: map[string]interface{}{"channel": "prices"},
+		ReconnectDelay: time.Millisecond})
+
+	// the subscription is sent again after every reconnection
+	ws.OnConnect(func(_ context.Context, ws *WebSocket) error {
+		return ws.Send([]byte("subscribe"))
+	})
+
+	var messages []string
+
+	ws.Handle(func(_ context.Context, messageType int, data []byte) error {
+		assert.Equal(t, websocket.TextMessage, messageType)
+
+		messages = append(messages, string(data))
+		if len(messages) == 2 {
+			return errStop
+		}
+
+		return nil
+	})
+
+	assert.Equal(t, errStop, ws.Run(context.Background()))
+	assert.Equal(t, []string{"subscribe key prices 1", "subscribe key prices 2"}, messages)
+	assert.Equal(t, ErrNotConnected, ws.Send([]byte("after")))
+}
+
+func TestWebSocket_RunContextDone(t *testing.T) {
+	tests := []struct {
+		desc string
+		url  func(ts *httptest.Server) string
+	}{
+		{"connected", func(ts *httptest.Server) string { return ts.URL }},
+		{"reconnecting", func(*httptest.Server) string { return "http://localhost:1" }},
+	}
+
+	for i, tc := range tests {
+		var connections int32
+
+		ts := echoFeed(t, &connections)
+
+		svc := NewHTTPServiceWithOptions(tc.url(ts), log.NewMockLogger(io.Discard), &Options{
+			SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+		})
+
+		ws := svc.WebSocket("feed", &WebSocketOption{ReconnectDelay: time.Millisecond})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+
+		assert.NoError(t, ws.Run(ctx), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		cancel()
+		ts.Close()
+	}
+}
+
+func TestWebSocket_PongTimeout(t *testing.T) {
+	var connections int32
+
+	// the server does not read, hence it never responds to the pings
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		atomic.AddInt32(&connections, 1)
+
+		time.Sleep(200 * time.Millisecond)
+		conn.Close()
+	}))
+	defer ts.Close()
+
+	svc := NewHTTPServiceWithOptions(ts.URL, log.NewMockLogger(io.Discard), &Options{
+		SurgeProtectorOption: &SurgeProtectorOption{Disable: true},
+	})
+
+	ws := svc.WebSocket("feed", &WebSocketOption{PingInterval: 10 * time.Millisecond, PongTimeout: 10 * time.Millisecond,
+		ReconnectDelay: time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	assert.NoError(t, ws.Run(ctx))
+	assert.Greater(t, atomic.LoadInt32(&connections), int32(1), "dead connection is not reconnected")
+}